@@ -4,6 +4,7 @@ package api
 
 import (
 	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
 	"github.com/netapp/trident/utils"
 )
 
@@ -56,6 +57,22 @@ type MultipleSnapshotResponse struct {
 	Items []storage.SnapshotExternal `json:"items"`
 }
 
+type MultipleLUKSPassphraseRotationResponse struct {
+	Items []storage.LUKSPassphraseRotation `json:"items"`
+}
+
+type MultipleVolumeTransactionResponse struct {
+	Items []storage.VolumeTransactionExternal `json:"items"`
+}
+
+type MultipleJobResponse struct {
+	Items []storage.JobExternal `json:"items"`
+}
+
+type MultiplePoolPlacementResponse struct {
+	Items []storageclass.PoolPlacement `json:"items"`
+}
+
 type Version struct {
 	Version       string `json:"version"`
 	MajorVersion  uint   `json:"majorVersion"`
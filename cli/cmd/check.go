@@ -0,0 +1,18 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+func init() {
+	RootCmd.AddCommand(checkCmd)
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the consistency of a resource in Trident",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := discoverOperatingMode(cmd)
+		return err
+	},
+}
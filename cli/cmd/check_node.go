@@ -0,0 +1,135 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/utils"
+)
+
+var (
+	repairNode bool
+	dryRunNode bool
+)
+
+func init() {
+	checkCmd.AddCommand(checkNodeCmd)
+	checkNodeCmd.Flags().BoolVar(&repairNode, "repair", false,
+		"Repair the findings: unmount orphaned mounts, fix or delete tracking files, flush orphaned multipath maps, "+
+			"detach orphaned loop devices and log out of orphaned iSCSI sessions")
+	checkNodeCmd.Flags().BoolVar(&dryRunNode, "dry-run", false,
+		"With --repair, report the repairs that would be made without making them")
+}
+
+var checkNodeCmd = &cobra.Command{
+	Use:   "node <name>",
+	Short: "Check a node's volume tracking files against its mounts and devices",
+	Long: `Check a node's volume tracking files against its mounts, iSCSI sessions, multipath maps and loop devices.
+
+Reports Trident mounts without tracking files, tracking files and published paths that refer to volumes
+no longer on the node, and iSCSI sessions, multipath maps and loop devices that no tracked volume uses.
+
+Repairs are not coordinated with volume operations in progress on the node, so cordon and drain the node
+before running with --repair.`,
+	Aliases: []string{"n"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if dryRunNode && !repairNode {
+			return fmt.Errorf("--dry-run may only be specified with --repair")
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"check", "node", args[0]}
+			if repairNode {
+				command = append(command, "--repair")
+			}
+			if dryRunNode {
+				command = append(command, "--dry-run")
+			}
+			TunnelCommand(command)
+			return nil
+		} else {
+			return nodeCheck(args[0], repairNode, dryRunNode)
+		}
+	},
+}
+
+func nodeCheck(nodeName string, repair, dryRun bool) error {
+	url := BaseURL() + "/node/" + nodeName + "/check"
+
+	requestBytes, err := json.Marshal(nodeAPI.NodeCheckRequest{Repair: repair, DryRun: dryRun})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not check node %s: %v", nodeName, GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var checkResponse rest.CheckNodeResponse
+	if err = json.Unmarshal(responseBody, &checkResponse); err != nil {
+		return err
+	}
+	if checkResponse.Report == nil {
+		return fmt.Errorf("could not check node %s: no report returned", nodeName)
+	}
+
+	WriteNodeCheckReport(checkResponse.Report)
+	return nil
+}
+
+func WriteNodeCheckReport(report *utils.NodeCheckReport) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(report)
+	case FormatYAML:
+		WriteYAML(report)
+	default:
+		writeNodeCheckTable(report)
+	}
+}
+
+func writeNodeCheckTable(report *utils.NodeCheckReport) {
+	if len(report.Findings) == 0 {
+		fmt.Printf("No problems found on node %s.\n", report.Node)
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	header := []string{"Type", "Volume", "Resource", "Message", "Repair"}
+	if report.Repair && !report.DryRun {
+		header = append(header, "Repaired", "Error")
+	}
+	table.SetHeader(header)
+
+	for _, finding := range report.Findings {
+		row := []string{
+			string(finding.Type),
+			finding.VolumeID,
+			finding.Resource,
+			finding.Message,
+			finding.Repair,
+		}
+		if report.Repair && !report.DryRun {
+			row = append(row, strconv.FormatBool(finding.Repaired), finding.Error)
+		}
+		table.Append(row)
+	}
+
+	table.Render()
+}
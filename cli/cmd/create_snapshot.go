@@ -0,0 +1,78 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+func init() {
+	createCmd.AddCommand(createSnapshotCmd)
+}
+
+var createSnapshotCmd = &cobra.Command{
+	Use:     "snapshot <volume name>/<snapshot name>",
+	Short:   "Add a volume snapshot to Trident",
+	Aliases: []string{"s", "snap"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			TunnelCommand(append([]string{"create", "snapshot"}, args...))
+			return nil
+		} else {
+			return snapshotCreate(args[0])
+		}
+	},
+}
+
+func snapshotCreate(snapshotID string) error {
+	volumeName, snapshotName, ok := strings.Cut(snapshotID, "/")
+	if !ok || volumeName == "" || snapshotName == "" {
+		return utils.InvalidInputError(fmt.Sprintf("invalid snapshot ID: %s; Please use the format "+
+			"<volume name>/<snapshot name>", snapshotID))
+	}
+
+	postData, err := json.Marshal(&storage.SnapshotConfig{
+		Version:    config.OrchestratorAPIVersion,
+		Name:       snapshotName,
+		VolumeName: volumeName,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := BaseURL() + "/snapshot"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, postData, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusCreated {
+		return fmt.Errorf("could not create snapshot %s: %v", snapshotID,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var addSnapshotResponse rest.AddSnapshotResponse
+	if err = json.Unmarshal(responseBody, &addSnapshotResponse); err != nil {
+		return err
+	}
+
+	// Retrieve the newly created snapshot and write to stdout
+	snapshot, err := GetSnapshot(addSnapshotResponse.SnapshotID)
+	if err != nil {
+		return err
+	}
+
+	WriteSnapshots([]storage.SnapshotExternal{snapshot})
+	return nil
+}
@@ -0,0 +1,18 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import "github.com/spf13/cobra"
+
+func init() {
+	RootCmd.AddCommand(explainCmd)
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain how Trident would handle a resource",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := discoverOperatingMode(cmd)
+		return err
+	},
+}
@@ -0,0 +1,197 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+)
+
+const defaultExplainVolumeName = "explain"
+
+var (
+	explainStorageClass        string
+	explainSize                string
+	explainProtocol            string
+	explainAccessMode          string
+	explainVolumeMode          string
+	explainRequisiteTopologies []string
+	explainPreferredTopologies []string
+)
+
+func init() {
+	explainCmd.AddCommand(explainVolumeCmd)
+	explainVolumeCmd.Flags().StringVarP(&explainStorageClass, "storage-class", "s", "", "Storage class of the volume")
+	explainVolumeCmd.Flags().StringVarP(&explainSize, "size", "", "1Gi", "Size of the volume")
+	explainVolumeCmd.Flags().StringVarP(&explainProtocol, "protocol", "", "",
+		"Protocol of the volume (file or block)")
+	explainVolumeCmd.Flags().StringVarP(&explainAccessMode, "access-mode", "", "",
+		"Access mode of the volume (ReadWriteOnce, ReadWriteOncePod, ReadOnlyMany or ReadWriteMany)")
+	explainVolumeCmd.Flags().StringVarP(&explainVolumeMode, "volume-mode", "", "",
+		"Volume mode of the volume (Filesystem or Block)")
+	explainVolumeCmd.Flags().StringArrayVarP(&explainRequisiteTopologies, "requisite-topology", "", nil,
+		"Topology the volume must be accessible from, as key=value pairs separated by commas (repeatable)")
+	explainVolumeCmd.Flags().StringArrayVarP(&explainPreferredTopologies, "preferred-topology", "", nil,
+		"Topology the volume should preferably be placed in, as key=value pairs separated by commas (repeatable)")
+}
+
+var explainVolumeCmd = &cobra.Command{
+	Use:   "volume [<name>]",
+	Short: "Explain where Trident would place a new volume",
+	Long: `Explain where Trident would place a new volume
+
+Every storage pool is checked against a hypothetical volume as if it were being
+provisioned, and is listed with the reason it was accepted or rejected. Accepted
+pools are listed first, in the order Trident would try them. Nothing is provisioned.`,
+	Aliases: []string{"v"},
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{
+				"explain", "volume", "--storage-class", explainStorageClass, "--size", explainSize,
+				"--protocol", explainProtocol, "--access-mode", explainAccessMode, "--volume-mode", explainVolumeMode,
+			}
+			for _, topology := range explainRequisiteTopologies {
+				command = append(command, "--requisite-topology", topology)
+			}
+			for _, topology := range explainPreferredTopologies {
+				command = append(command, "--preferred-topology", topology)
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			volumeConfig, err := getExplainVolumeConfig(args)
+			if err != nil {
+				return err
+			}
+			return volumeExplain(volumeConfig)
+		}
+	},
+}
+
+// getExplainVolumeConfig builds the config of the hypothetical volume from the command line.
+func getExplainVolumeConfig(args []string) (*storage.VolumeConfig, error) {
+	if explainStorageClass == "" {
+		return nil, errors.New("no storage class was specified")
+	}
+
+	name := defaultExplainVolumeName
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	requisiteTopologies, err := parseTopologies(explainRequisiteTopologies)
+	if err != nil {
+		return nil, err
+	}
+	preferredTopologies, err := parseTopologies(explainPreferredTopologies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage.VolumeConfig{
+		Name:                name,
+		Size:                explainSize,
+		StorageClass:        explainStorageClass,
+		Protocol:            config.Protocol(explainProtocol),
+		AccessMode:          config.AccessMode(explainAccessMode),
+		VolumeMode:          config.VolumeMode(explainVolumeMode),
+		RequisiteTopologies: requisiteTopologies,
+		PreferredTopologies: preferredTopologies,
+	}, nil
+}
+
+// parseTopologies parses topologies written as "key1=value1,key2=value2".
+func parseTopologies(values []string) ([]map[string]string, error) {
+	topologies := make([]map[string]string, 0, len(values))
+	for _, value := range values {
+		topology := make(map[string]string)
+		for _, segment := range strings.Split(value, ",") {
+			kv := strings.SplitN(segment, "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+				return nil, fmt.Errorf("invalid topology %s; expected key=value pairs separated by commas", value)
+			}
+			topology[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+		topologies = append(topologies, topology)
+	}
+	return topologies, nil
+}
+
+func volumeExplain(volumeConfig *storage.VolumeConfig) error {
+	requestBytes, err := json.Marshal(volumeConfig)
+	if err != nil {
+		return err
+	}
+
+	url := BaseURL() + "/volume/explain"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	} else if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not explain volume placement: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var explainResponse rest.ExplainVolumePlacementResponse
+	err = json.Unmarshal(responseBody, &explainResponse)
+	if err != nil {
+		return err
+	}
+
+	WritePoolPlacements(explainResponse.Pools)
+
+	return nil
+}
+
+func WritePoolPlacements(placements []storageclass.PoolPlacement) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultiplePoolPlacementResponse{Items: placements})
+	case FormatYAML:
+		WriteYAML(api.MultiplePoolPlacementResponse{Items: placements})
+	case FormatName:
+		writePoolPlacementNames(placements)
+	default:
+		writePoolPlacementTable(placements)
+	}
+}
+
+func writePoolPlacementTable(placements []storageclass.PoolPlacement) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Backend", "Pool", "Accepted", "Reason"})
+
+	for _, placement := range placements {
+		table.Append([]string{
+			placement.Backend,
+			placement.Pool,
+			strconv.FormatBool(placement.Accepted),
+			placement.Reason,
+		})
+	}
+
+	table.Render()
+}
+
+// writePoolPlacementNames lists the pools that were accepted, in the order they would be tried
+func writePoolPlacementNames(placements []storageclass.PoolPlacement) {
+	for _, placement := range placements {
+		if placement.Accepted {
+			fmt.Printf("%s/%s\n", placement.Backend, placement.Pool)
+		}
+	}
+}
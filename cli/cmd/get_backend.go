@@ -206,11 +206,15 @@ func writeBackendTable(backends []storage.BackendExternal) {
 
 		if configAsMap, ok := b.Config.(map[string]interface{}); ok {
 			storageDriverName := configAsMap["storageDriverName"].(string)
+			state := b.State.String()
+			if b.Degraded {
+				state += " (degraded)"
+			}
 			table.Append([]string{
 				b.Name,
 				storageDriverName,
 				b.BackendUUID,
-				b.State.String(),
+				state,
 				strconv.Itoa(len(b.Volumes)),
 			})
 		}
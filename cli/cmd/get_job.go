@@ -0,0 +1,181 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+func init() {
+	getCmd.AddCommand(getJobCmd)
+}
+
+var getJobCmd = &cobra.Command{
+	Use:     "job [<id>...]",
+	Short:   "Get one or more asynchronous jobs from Trident",
+	Aliases: []string{"jobs"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "job"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return jobList(args)
+		}
+	},
+}
+
+func jobList(jobIDs []string) error {
+	var jobs []storage.JobExternal
+
+	if len(jobIDs) == 0 {
+		var err error
+		if jobs, err = GetJobs(); err != nil {
+			return err
+		}
+	} else {
+		jobs = make([]storage.JobExternal, 0, len(jobIDs))
+		for _, jobID := range jobIDs {
+			job, err := GetJob(jobID)
+			if err != nil {
+				return err
+			}
+			jobs = append(jobs, job)
+		}
+	}
+
+	WriteJobs(jobs)
+
+	return nil
+}
+
+func GetJobs() ([]storage.JobExternal, error) {
+	url := BaseURL() + "/job"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get jobs: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listResponse rest.ListJobsResponse
+	err = json.Unmarshal(responseBody, &listResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]storage.JobExternal, 0, len(listResponse.Jobs))
+	for _, job := range listResponse.Jobs {
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, nil
+}
+
+func GetJob(jobID string) (storage.JobExternal, error) {
+	url := BaseURL() + "/job/" + jobID
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return storage.JobExternal{}, err
+	} else if response.StatusCode != http.StatusOK {
+		errorMessage := fmt.Sprintf("could not get job %s: %v", jobID,
+			GetErrorFromHTTPResponse(response, responseBody))
+		switch response.StatusCode {
+		case http.StatusNotFound:
+			return storage.JobExternal{}, utils.NotFoundError(errorMessage)
+		default:
+			return storage.JobExternal{}, errors.New(errorMessage)
+		}
+	}
+
+	var getJobResponse rest.GetJobResponse
+	err = json.Unmarshal(responseBody, &getJobResponse)
+	if err != nil {
+		return storage.JobExternal{}, err
+	}
+	if getJobResponse.Job == nil {
+		return storage.JobExternal{}, fmt.Errorf("could not get job %s: no job returned", jobID)
+	}
+
+	return *getJobResponse.Job, nil
+}
+
+func WriteJobs(jobs []storage.JobExternal) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleJobResponse{Items: jobs})
+	case FormatYAML:
+		WriteYAML(api.MultipleJobResponse{Items: jobs})
+	case FormatName:
+		writeJobIDs(jobs)
+	case FormatWide:
+		writeWideJobTable(jobs)
+	default:
+		writeJobTable(jobs)
+	}
+}
+
+func writeJobTable(jobs []storage.JobExternal) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Operation", "Volume", "State", "Progress", "Age"})
+
+	for _, job := range jobs {
+		table.Append([]string{
+			job.ID,
+			string(job.Op),
+			job.Volume,
+			string(job.State),
+			strconv.Itoa(job.Progress) + "%",
+			(time.Duration(job.AgeSeconds) * time.Second).String(),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideJobTable(jobs []storage.JobExternal) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{
+		"ID", "Operation", "Volume", "Backend UUID", "State", "Progress", "Attempts", "Created", "Updated",
+		"Message",
+	})
+
+	for _, job := range jobs {
+		table.Append([]string{
+			job.ID,
+			string(job.Op),
+			job.Volume,
+			job.BackendUUID,
+			string(job.State),
+			strconv.Itoa(job.Progress) + "%",
+			strconv.Itoa(job.Attempts),
+			job.CreatedTime,
+			job.UpdatedTime,
+			job.Message,
+		})
+	}
+
+	table.Render()
+}
+
+func writeJobIDs(jobs []storage.JobExternal) {
+	for _, job := range jobs {
+		fmt.Println(job.ID)
+	}
+}
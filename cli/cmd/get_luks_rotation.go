@@ -0,0 +1,171 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var getLUKSRotationIncomplete bool
+
+func init() {
+	getCmd.AddCommand(getLUKSRotationCmd)
+	getLUKSRotationCmd.Flags().BoolVar(&getLUKSRotationIncomplete, "incomplete", false,
+		"List only volumes whose data or snapshots may still require a previous passphrase.")
+}
+
+var getLUKSRotationCmd = &cobra.Command{
+	Use:     "luksrotation [<volume>...]",
+	Short:   "Get the LUKS passphrase rotation status of one or more volumes from Trident",
+	Aliases: []string{"lr", "luksrotations"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "luksrotation"}
+			if getLUKSRotationIncomplete {
+				command = append(command, "--incomplete")
+			}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return luksRotationList(args)
+		}
+	},
+}
+
+func luksRotationList(volumeNames []string) error {
+	rotations, err := GetLUKSPassphraseRotations(getLUKSRotationIncomplete)
+	if err != nil {
+		return err
+	}
+
+	if len(volumeNames) > 0 {
+		rotationsByVolume := make(map[string]storage.LUKSPassphraseRotation, len(rotations))
+		for _, rotation := range rotations {
+			rotationsByVolume[rotation.VolumeName] = rotation
+		}
+
+		rotations = make([]storage.LUKSPassphraseRotation, 0, len(volumeNames))
+		for _, volumeName := range volumeNames {
+			rotation, ok := rotationsByVolume[volumeName]
+			if !ok {
+				if getLUKSRotationIncomplete {
+					continue
+				}
+				return fmt.Errorf("no LUKS passphrase rotation status found for volume %s", volumeName)
+			}
+			rotations = append(rotations, rotation)
+		}
+	}
+
+	WriteLUKSPassphraseRotations(rotations)
+
+	return nil
+}
+
+func GetLUKSPassphraseRotations(incompleteOnly bool) ([]storage.LUKSPassphraseRotation, error) {
+	url := BaseURL() + "/luksrotation"
+	if incompleteOnly {
+		url += "?incomplete=true"
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get LUKS passphrase rotations: %v",
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listResponse rest.ListLUKSPassphraseRotationsResponse
+	err = json.Unmarshal(responseBody, &listResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	rotations := make([]storage.LUKSPassphraseRotation, 0, len(listResponse.Rotations))
+	for _, rotation := range listResponse.Rotations {
+		rotations = append(rotations, *rotation)
+	}
+
+	return rotations, nil
+}
+
+func WriteLUKSPassphraseRotations(rotations []storage.LUKSPassphraseRotation) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleLUKSPassphraseRotationResponse{Items: rotations})
+	case FormatYAML:
+		WriteYAML(api.MultipleLUKSPassphraseRotationResponse{Items: rotations})
+	case FormatName:
+		writeLUKSPassphraseRotationNames(rotations)
+	case FormatWide:
+		writeWideLUKSPassphraseRotationTable(rotations)
+	default:
+		writeLUKSPassphraseRotationTable(rotations)
+	}
+}
+
+func writeLUKSPassphraseRotationTable(rotations []storage.LUKSPassphraseRotation) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Volume", "State", "Passphrase", "Previous in use"})
+
+	for _, r := range rotations {
+		table.Append([]string{
+			r.VolumeName,
+			string(r.State),
+			r.PassphraseName,
+			strconv.FormatBool(r.UsesPreviousPassphrase()),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideLUKSPassphraseRotationTable(rotations []storage.LUKSPassphraseRotation) {
+	table := tablewriter.NewWriter(os.Stdout)
+
+	header := []string{
+		"Volume",
+		"State",
+		"Passphrase",
+		"Volume passphrases",
+		"Snapshots",
+		"Node",
+		"Attempts",
+		"Message",
+	}
+	table.SetHeader(header)
+
+	for _, r := range rotations {
+		table.Append([]string{
+			r.VolumeName,
+			string(r.State),
+			r.PassphraseName,
+			strings.Join(r.VolumePassphraseNames, "\n"),
+			strings.Join(r.Snapshots, "\n"),
+			r.Node,
+			strconv.Itoa(r.Attempts),
+			r.Message,
+		})
+	}
+
+	table.Render()
+}
+
+func writeLUKSPassphraseRotationNames(rotations []storage.LUKSPassphraseRotation) {
+	for _, r := range rotations {
+		fmt.Println(r.VolumeName)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/olekukonko/tablewriter"
@@ -153,19 +154,31 @@ func writeWideNodeTable(nodes []utils.Node) {
 		"IQN",
 		"IPs",
 		"Services",
+		"Fenced",
+		"Multipath",
 	}
 	table.SetHeader(header)
 
 	for _, node := range nodes {
-		var services []string
+		var services, multipath []string
 		if node.HostInfo != nil {
 			services = node.HostInfo.Services
+			if status := node.HostInfo.Multipath; status != nil {
+				if status.Valid {
+					multipath = append(multipath, "OK")
+				}
+				for _, issue := range status.Issues {
+					multipath = append(multipath, issue.String())
+				}
+			}
 		}
 		table.Append([]string{
 			node.Name,
 			node.IQN,
 			strings.Join(node.IPs, "\n"),
 			strings.Join(services, "\n"),
+			strconv.FormatBool(node.Fenced),
+			strings.Join(multipath, "\n"),
 		})
 	}
 
@@ -0,0 +1,151 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+func init() {
+	getCmd.AddCommand(getTransactionCmd)
+}
+
+var getTransactionCmd = &cobra.Command{
+	Use:     "transaction [<name>...]",
+	Short:   "Get one or more in-flight transactions from Trident",
+	Aliases: []string{"txn", "txns", "transactions"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if OperatingMode == ModeTunnel {
+			command := []string{"get", "transaction"}
+			TunnelCommand(append(command, args...))
+			return nil
+		} else {
+			return transactionList(args)
+		}
+	},
+}
+
+func transactionList(txnNames []string) error {
+	txns, err := GetVolumeTransactions()
+	if err != nil {
+		return err
+	}
+
+	if len(txnNames) > 0 {
+		txnsByName := make(map[string]storage.VolumeTransactionExternal, len(txns))
+		for _, txn := range txns {
+			txnsByName[txn.Name] = txn
+		}
+
+		txns = make([]storage.VolumeTransactionExternal, 0, len(txnNames))
+		for _, txnName := range txnNames {
+			txn, ok := txnsByName[txnName]
+			if !ok {
+				return fmt.Errorf("transaction %s was not found", txnName)
+			}
+			txns = append(txns, txn)
+		}
+	}
+
+	WriteVolumeTransactions(txns)
+
+	return nil
+}
+
+func GetVolumeTransactions() ([]storage.VolumeTransactionExternal, error) {
+	url := BaseURL() + "/txn"
+
+	response, responseBody, err := api.InvokeRESTAPI("GET", url, nil, Debug)
+	if err != nil {
+		return nil, err
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get transactions: %v", GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var listResponse rest.ListVolumeTransactionsResponse
+	err = json.Unmarshal(responseBody, &listResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	txns := make([]storage.VolumeTransactionExternal, 0, len(listResponse.Transactions))
+	for _, txn := range listResponse.Transactions {
+		txns = append(txns, *txn)
+	}
+
+	return txns, nil
+}
+
+func WriteVolumeTransactions(txns []storage.VolumeTransactionExternal) {
+	switch OutputFormat {
+	case FormatJSON:
+		WriteJSON(api.MultipleVolumeTransactionResponse{Items: txns})
+	case FormatYAML:
+		WriteYAML(api.MultipleVolumeTransactionResponse{Items: txns})
+	case FormatName:
+		writeVolumeTransactionNames(txns)
+	case FormatWide:
+		writeWideVolumeTransactionTable(txns)
+	default:
+		writeVolumeTransactionTable(txns)
+	}
+}
+
+func writeVolumeTransactionTable(txns []storage.VolumeTransactionExternal) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Operation", "Age", "Failed"})
+
+	for _, txn := range txns {
+		table.Append([]string{
+			txn.Name,
+			string(txn.Op),
+			formatVolumeTransactionAge(txn),
+			strconv.FormatBool(txn.Failed),
+		})
+	}
+
+	table.Render()
+}
+
+func writeWideVolumeTransactionTable(txns []storage.VolumeTransactionExternal) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Operation", "Started", "Age", "Failed"})
+
+	for _, txn := range txns {
+		table.Append([]string{
+			txn.Name,
+			string(txn.Op),
+			txn.StartTime,
+			formatVolumeTransactionAge(txn),
+			strconv.FormatBool(txn.Failed),
+		})
+	}
+
+	table.Render()
+}
+
+func writeVolumeTransactionNames(txns []storage.VolumeTransactionExternal) {
+	for _, txn := range txns {
+		fmt.Println(txn.Name)
+	}
+}
+
+// formatVolumeTransactionAge returns a transaction's age, or "unknown" if it has no recorded start time
+func formatVolumeTransactionAge(txn storage.VolumeTransactionExternal) string {
+	if txn.StartTime == "" {
+		return "unknown"
+	}
+	return (time.Duration(txn.AgeSeconds) * time.Second).String()
+}
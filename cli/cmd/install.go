@@ -40,6 +40,7 @@ const (
 	// CRD names
 	BackendConfigCRDName      = "tridentbackendconfigs.trident.netapp.io"
 	BackendCRDName            = "tridentbackends.trident.netapp.io"
+	JobCRDName                = "tridentjobs.trident.netapp.io"
 	MirrorRelationshipCRDName = "tridentmirrorrelationships.trident.netapp.io"
 	NodeCRDName               = "tridentnodes.trident.netapp.io"
 	SnapshotCRDName           = "tridentsnapshots.trident.netapp.io"
@@ -103,6 +104,7 @@ var (
 	skipK8sVersionCheck     bool
 	windows                 bool
 	enableForceDetach       bool
+	manageMultipath         bool
 	disableAuditLog         bool
 	pvName                  string
 	pvcName                 string
@@ -162,6 +164,7 @@ var (
 	CRDnames = []string{
 		BackendConfigCRDName,
 		BackendCRDName,
+		JobCRDName,
 		MirrorRelationshipCRDName,
 		NodeCRDName,
 		VolumeReferenceCRDName,
@@ -196,6 +199,8 @@ func init() {
 		"(Deprecated) Attempt to automatically install required packages on nodes.")
 	installCmd.Flags().BoolVar(&enableForceDetach, "enable-force-detach", false,
 		"Enable the force detach feature.")
+	installCmd.Flags().BoolVar(&manageMultipath, "manage-multipath-config", false,
+		"Write a Trident-managed multipath drop-in configuration on nodes.")
 	installCmd.Flags().BoolVar(&disableAuditLog, "disable-audit-log", true, "Disable the audit logger.")
 
 	installCmd.Flags().StringVar(&pvcName, "pvc", DefaultPVCName,
@@ -627,6 +632,7 @@ func prepareYAMLFiles() error {
 		Labels:               daemonSetlabels,
 		ControllingCRDetails: nil,
 		EnableForceDetach:    enableForceDetach,
+		ManageMultipath:      manageMultipath,
 		Debug:                Debug,
 		Version:              client.ServerVersion(),
 		HTTPRequestTimeout:   httpRequestTimeout.String(),
@@ -1049,7 +1055,8 @@ func installTrident() (returnError error) {
 
 	// Create the certificates for the CSI controller's HTTPS REST interface
 	certInfo, err := utils.MakeHTTPCertInfo(
-		tridentconfig.CACertName, tridentconfig.ServerCertName, tridentconfig.ClientCertName)
+		tridentconfig.CACertName, tridentconfig.ServerCertName, tridentconfig.ClientCertName,
+		tridentconfig.ControllerCertName)
 	if err != nil {
 		returnError = fmt.Errorf("could not create Trident X509 certificates; %v", err)
 		return
@@ -1057,12 +1064,14 @@ func installTrident() (returnError error) {
 
 	// Create the secret for the HTTP certs & keys
 	secretMap := map[string]string{
-		tridentconfig.CAKeyFile:      certInfo.CAKey,
-		tridentconfig.CACertFile:     certInfo.CACert,
-		tridentconfig.ServerKeyFile:  certInfo.ServerKey,
-		tridentconfig.ServerCertFile: certInfo.ServerCert,
-		tridentconfig.ClientKeyFile:  certInfo.ClientKey,
-		tridentconfig.ClientCertFile: certInfo.ClientCert,
+		tridentconfig.CAKeyFile:          certInfo.CAKey,
+		tridentconfig.CACertFile:         certInfo.CACert,
+		tridentconfig.ServerKeyFile:      certInfo.ServerKey,
+		tridentconfig.ServerCertFile:     certInfo.ServerCert,
+		tridentconfig.ClientKeyFile:      certInfo.ClientKey,
+		tridentconfig.ClientCertFile:     certInfo.ClientCert,
+		tridentconfig.ControllerKeyFile:  certInfo.ControllerKey,
+		tridentconfig.ControllerCertFile: certInfo.ControllerCert,
 	}
 	err = client.CreateObjectByYAML(
 		k8sclient.GetSecretYAML(getProtocolSecretName(), TridentPodNamespace, labels, nil, secretMap, nil))
@@ -1210,6 +1219,7 @@ func installTrident() (returnError error) {
 			Labels:               daemonSetlabels,
 			ControllingCRDetails: nil,
 			EnableForceDetach:    enableForceDetach,
+			ManageMultipath:      manageMultipath,
 			Debug:                Debug,
 			Version:              client.ServerVersion(),
 			HTTPRequestTimeout:   httpRequestTimeout.String(),
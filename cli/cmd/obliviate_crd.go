@@ -134,6 +134,10 @@ func deleteCRs() error {
 		return err
 	}
 
+	if err := deleteJobs(); err != nil {
+		return err
+	}
+
 	if err := deleteSnapshots(); err != nil {
 		return err
 	}
@@ -717,6 +721,62 @@ func deleteTransactions() error {
 	return nil
 }
 
+func deleteJobs() error {
+	crd := "tridentjobs.trident.netapp.io"
+	logFields := log.Fields{"CRD": crd}
+
+	// See if CRD exists
+	exists, err := k8sClient.CheckCRDExists(crd)
+	if err != nil {
+		return err
+	} else if !exists {
+		log.WithField("CRD", crd).Debug("CRD not present.")
+		return nil
+	}
+
+	jobs, err := crdClientset.TridentV1().TridentJobs(allNamespaces).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	} else if len(jobs.Items) == 0 {
+		log.WithFields(logFields).Info("Resources not present.")
+		return nil
+	}
+
+	for _, job := range jobs.Items {
+		if job.DeletionTimestamp.IsZero() {
+			_ = crdClientset.TridentV1().TridentJobs(job.Namespace).Delete(ctx(), job.Name, deleteOpts)
+		}
+	}
+
+	jobs, err = crdClientset.TridentV1().TridentJobs(allNamespaces).List(ctx(), listOpts)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs.Items {
+		if job.HasTridentFinalizers() {
+			crCopy := job.DeepCopy()
+			crCopy.RemoveTridentFinalizers()
+			_, err := crdClientset.TridentV1().TridentJobs(job.Namespace).Update(ctx(), crCopy, updateOpts)
+			if isNotFoundError(err) {
+				continue
+			} else if err != nil {
+				log.Errorf("Problem removing finalizers: %v", err)
+				return err
+			}
+		}
+
+		deleteFunc := crdClientset.TridentV1().TridentJobs(job.Namespace).Delete
+		if err := deleteWithRetry(deleteFunc, ctx(), job.Name, nil); err != nil {
+			log.Errorf("Problem deleting resource: %v", err)
+			return err
+		}
+	}
+
+	log.WithFields(logFields).Info("Resources deleted.")
+	return nil
+}
+
 func deleteSnapshots() error {
 	crd := "tridentsnapshots.trident.netapp.io"
 	logFields := log.Fields{"CRD": crd}
@@ -840,6 +900,7 @@ func deleteCRDs() error {
 		"tridentvolumes.trident.netapp.io",
 		"tridentnodes.trident.netapp.io",
 		"tridenttransactions.trident.netapp.io",
+		"tridentjobs.trident.netapp.io",
 		"tridentsnapshots.trident.netapp.io",
 		"tridentvolumepublications.trident.netapp.io",
 		"tridentvolumereferences.trident.netapp.io",
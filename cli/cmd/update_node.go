@@ -0,0 +1,81 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/utils"
+)
+
+var (
+	fenceNode   bool
+	unfenceNode bool
+)
+
+func init() {
+	updateCmd.AddCommand(updateNodeCmd)
+	updateNodeCmd.Flags().BoolVar(&fenceNode, "fence", false,
+		"Revoke the node's access to storage and detach its volumes, so they may be attached elsewhere")
+	updateNodeCmd.Flags().BoolVar(&unfenceNode, "unfence", false,
+		"Restore a fenced node's access to storage")
+	updateNodeCmd.MarkFlagsMutuallyExclusive("fence", "unfence")
+}
+
+var updateNodeCmd = &cobra.Command{
+	Use:     "node <name>",
+	Short:   "Update a node in Trident",
+	Aliases: []string{"n"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !fenceNode && !unfenceNode {
+			return fmt.Errorf("one of --fence or --unfence must be specified")
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"update", "node", args[0]}
+			if fenceNode {
+				command = append(command, "--fence")
+			} else {
+				command = append(command, "--unfence")
+			}
+			TunnelCommand(command)
+			return nil
+		} else {
+			return nodeFenceUpdate(args[0], fenceNode)
+		}
+	},
+}
+
+func nodeFenceUpdate(nodeName string, fenced bool) error {
+	url := BaseURL() + "/node/" + nodeName + "/fence"
+
+	requestBytes, err := json.Marshal(rest.NodeFence{Fenced: fenced})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("PUT", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not update fencing of node %s: %v", nodeName,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var fenceResponse rest.UpdateNodeResponse
+	if err = json.Unmarshal(responseBody, &fenceResponse); err != nil {
+		return err
+	}
+
+	nodes := []utils.Node{*fenceResponse.Node}
+	WriteNodes(nodes)
+	return nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/netapp/trident/cli/api"
+	"github.com/netapp/trident/frontend/rest"
+	"github.com/netapp/trident/storage"
+)
+
+var (
+	revokeLUKSKey   bool
+	restoreLUKSKey  bool
+	promoteReplica  bool
+	resizeVolume    string
+	restoreSnapshot string
+	takeOverVolume  bool
+	takeOverHost    string
+)
+
+func init() {
+	updateCmd.AddCommand(updateVolumeCmd)
+	updateVolumeCmd.Flags().BoolVar(&revokeLUKSKey, "revoke-luks-key", false,
+		"Revoke the volume's KMS-wrapped LUKS key, so the volume cannot be staged again")
+	updateVolumeCmd.Flags().BoolVar(&restoreLUKSKey, "restore-luks-key", false,
+		"Restore a previously revoked KMS-wrapped LUKS key")
+	updateVolumeCmd.Flags().BoolVar(&promoteReplica, "promote-replica", false,
+		"Fail the volume over to its cross-zone replica")
+	updateVolumeCmd.Flags().StringVar(&resizeVolume, "size", "",
+		"Grow the volume to the specified size, e.g. 10Gi")
+	updateVolumeCmd.Flags().StringVar(&restoreSnapshot, "restore-snapshot", "",
+		"Revert the volume, in place, to the named snapshot; the volume should not be in use")
+	updateVolumeCmd.Flags().BoolVar(&takeOverVolume, "takeover", false,
+		"Fence every other Docker host from the volume so it may be mounted on this host")
+	updateVolumeCmd.Flags().StringVar(&takeOverHost, "host", "",
+		"The host taking over the volume; defaults to this host's name")
+	updateVolumeCmd.MarkFlagsMutuallyExclusive("revoke-luks-key", "restore-luks-key", "promote-replica", "size",
+		"restore-snapshot", "takeover")
+}
+
+var updateVolumeCmd = &cobra.Command{
+	Use:     "volume <name>",
+	Short:   "Update a volume in Trident",
+	Aliases: []string{"v"},
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !revokeLUKSKey && !restoreLUKSKey && !promoteReplica && resizeVolume == "" && restoreSnapshot == "" &&
+			!takeOverVolume {
+			return fmt.Errorf("one of --revoke-luks-key, --restore-luks-key, --promote-replica, --size, " +
+				"--restore-snapshot or --takeover must be specified")
+		}
+		if takeOverHost != "" && !takeOverVolume {
+			return fmt.Errorf("--host may only be specified with --takeover")
+		}
+		if takeOverVolume && takeOverHost == "" {
+			hostName, err := os.Hostname()
+			if err != nil {
+				return fmt.Errorf("could not determine this host's name; %v", err)
+			}
+			takeOverHost = hostName
+		}
+
+		if OperatingMode == ModeTunnel {
+			command := []string{"update", "volume", args[0]}
+			if revokeLUKSKey {
+				command = append(command, "--revoke-luks-key")
+			} else if restoreLUKSKey {
+				command = append(command, "--restore-luks-key")
+			} else if promoteReplica {
+				command = append(command, "--promote-replica")
+			} else if resizeVolume != "" {
+				command = append(command, "--size", resizeVolume)
+			} else if takeOverVolume {
+				command = append(command, "--takeover", "--host", takeOverHost)
+			} else {
+				command = append(command, "--restore-snapshot", restoreSnapshot)
+			}
+			TunnelCommand(command)
+			return nil
+		} else if promoteReplica {
+			return volumeReplicaPromote(args[0])
+		} else if resizeVolume != "" {
+			return volumeResize(args[0], resizeVolume)
+		} else if restoreSnapshot != "" {
+			return volumeSnapshotRestore(args[0], restoreSnapshot)
+		} else if takeOverVolume {
+			return volumeTakeOver(args[0], takeOverHost)
+		} else {
+			return volumeLUKSKeyRevocationUpdate(args[0], revokeLUKSKey)
+		}
+	},
+}
+
+func volumeLUKSKeyRevocationUpdate(volumeName string, revoked bool) error {
+	url := BaseURL() + "/volume/" + volumeName + "/luksKeyRevocation"
+
+	requestBytes, err := json.Marshal(rest.VolumeLUKSKeyRevocation{Revoked: revoked})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("PUT", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not update LUKS key for volume %s: %v", volumeName,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	if revoked {
+		fmt.Printf("LUKS key for volume %s revoked.\n", volumeName)
+	} else {
+		fmt.Printf("LUKS key for volume %s restored.\n", volumeName)
+	}
+	return nil
+}
+
+func volumeReplicaPromote(volumeName string) error {
+	url := BaseURL() + "/volume/" + volumeName + "/promoteReplica"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, nil, Debug)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not promote replica of volume %s: %v", volumeName,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var promoteResponse rest.UpdateVolumeResponse
+	if err = json.Unmarshal(responseBody, &promoteResponse); err != nil {
+		return err
+	}
+
+	volumes := []storage.VolumeExternal{*promoteResponse.Volume}
+	WriteVolumes(volumes)
+	return nil
+}
+
+func volumeResize(volumeName, size string) error {
+	url := BaseURL() + "/volume/" + volumeName + "/size"
+
+	requestBytes, err := json.Marshal(rest.VolumeResize{Size: size})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("PUT", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not resize volume %s: %v", volumeName,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var resizeResponse rest.UpdateVolumeResponse
+	if err = json.Unmarshal(responseBody, &resizeResponse); err != nil {
+		return err
+	}
+
+	volumes := []storage.VolumeExternal{*resizeResponse.Volume}
+	WriteVolumes(volumes)
+	return nil
+}
+
+func volumeSnapshotRestore(volumeName, snapshotName string) error {
+	url := BaseURL() + "/snapshot/" + volumeName + "/" + snapshotName + "/restore"
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, nil, Debug)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not restore volume %s from snapshot %s: %v", volumeName, snapshotName,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	fmt.Printf("Volume %s restored from snapshot %s.\n", volumeName, snapshotName)
+	return nil
+}
+
+func volumeTakeOver(volumeName, hostName string) error {
+	url := BaseURL() + "/volume/" + volumeName + "/takeover"
+
+	requestBytes, err := json.Marshal(rest.VolumeTakeover{Host: hostName})
+	if err != nil {
+		return err
+	}
+
+	response, responseBody, err := api.InvokeRESTAPI("POST", url, requestBytes, Debug)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not take over volume %s: %v", volumeName,
+			GetErrorFromHTTPResponse(response, responseBody))
+	}
+
+	var takeoverResponse rest.UpdateVolumeResponse
+	if err = json.Unmarshal(responseBody, &takeoverResponse); err != nil {
+		return err
+	}
+
+	volumes := []storage.VolumeExternal{*takeoverResponse.Volume}
+	WriteVolumes(volumes)
+	return nil
+}
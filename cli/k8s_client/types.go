@@ -199,6 +199,7 @@ type DaemonsetYAMLArguments struct {
 	Labels               map[string]string   `json:"labels"`
 	ControllingCRDetails map[string]string   `json:"controllingCRDetails"`
 	EnableForceDetach    bool                `json:"enableForceDetach"`
+	ManageMultipath      bool                `json:"manageMultipath"`
 	DisableAuditLog      bool                `json:"disableAuditLog"`
 	Debug                bool                `json:"debug"`
 	Version              *utils.Version      `json:"version"`
@@ -134,7 +134,7 @@ rules:
     verbs: ["get", "list", "watch"]
   - apiGroups: ["trident.netapp.io"]
     resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes",
-"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status",
+"tridenttransactions", "tridentjobs", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status",
 "tridentmirrorrelationships", "tridentmirrorrelationships/status", "tridentsnapshotinfos",
 "tridentsnapshotinfos/status", "tridentvolumepublications", "tridentvolumereferences"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
@@ -190,6 +190,12 @@ rules:
   - apiGroups: ["storage.k8s.io"]
     resources: ["volumeattachments/status"]
     verbs: ["update", "patch"]
+  - apiGroups: ["coordination.k8s.io"]
+    resources: ["leases"]
+    verbs: ["get"]
+  - apiGroups: ["csiaddons.openshift.io"]
+    resources: ["networkfences"]
+    verbs: ["get", "list", "watch"]
   - apiGroups: ["snapshot.storage.k8s.io"]
     resources: ["volumesnapshots", "volumesnapshotclasses"]
     verbs: ["get", "list", "watch"]
@@ -204,7 +210,7 @@ rules:
     verbs: ["get", "list", "watch"]
   - apiGroups: ["trident.netapp.io"]
     resources: ["tridentversions", "tridentbackends", "tridentstorageclasses", "tridentvolumes","tridentnodes",
-"tridenttransactions", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status",
+"tridenttransactions", "tridentjobs", "tridentsnapshots", "tridentbackendconfigs", "tridentbackendconfigs/status",
 "tridentmirrorrelationships", "tridentmirrorrelationships/status", "tridentsnapshotinfos",
 "tridentsnapshotinfos/status", "tridentvolumepublications", "tridentvolumereferences"]
     verbs: ["get", "list", "watch", "create", "delete", "update", "patch"]
@@ -758,6 +764,7 @@ func GetCSIDaemonSetYAMLLinux(args *DaemonsetYAMLArguments) string {
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{KUBELET_DIR}", kubeletDir)
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{LABEL_APP}", args.Labels[TridentAppLabelKey])
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{FORCE_DETACH_BOOL}", strconv.FormatBool(args.EnableForceDetach))
+	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{MANAGE_MULTIPATH_BOOL}", strconv.FormatBool(args.ManageMultipath))
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{DEBUG}", debugLine)
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{LOG_LEVEL}", logLevel)
 	daemonSetYAML = strings.ReplaceAll(daemonSetYAML, "{LOG_FORMAT}", args.LogFormat)
@@ -820,6 +827,7 @@ spec:
         - "--https_rest"
         - "--https_port={PROBE_PORT}"
         - "--enable_force_detach={FORCE_DETACH_BOOL}"
+        - "--manage_multipath_config={MANAGE_MULTIPATH_BOOL}"
         {DEBUG}
         startupProbe:
           httpGet:
@@ -942,8 +950,20 @@ spec:
       - name: certs
         projected:
           sources:
+          # Nodes are given neither the CA key nor the controller's key
           - secret:
               name: trident-csi
+              items:
+              - key: caCert
+                path: caCert
+              - key: serverKey
+                path: serverKey
+              - key: serverCert
+                path: serverCert
+              - key: clientKey
+                path: clientKey
+              - key: clientCert
+                path: clientCert
           - secret:
               name: trident-encryption-keys
 `
@@ -1126,8 +1146,20 @@ spec:
         - name: certs
           projected:
             sources:
+            # Nodes are given neither the CA key nor the controller's key
             - secret:
                 name: trident-csi
+                items:
+                - key: caCert
+                  path: caCert
+                - key: serverKey
+                  path: serverKey
+                - key: serverCert
+                  path: serverCert
+                - key: clientKey
+                  path: clientKey
+                - key: clientCert
+                  path: clientCert
             - secret:
                 name: trident-encryption-keys
         - name: csi-proxy-volume-pipe
@@ -1385,6 +1417,10 @@ func GetTransactionCRDYAML() string {
 	return tridentTransactionCRDYAMLv1
 }
 
+func GetJobCRDYAML() string {
+	return tridentJobCRDYAMLv1
+}
+
 func GetSnapshotCRDYAML() string {
 	return tridentSnapshotCRDYAMLv1
 }
@@ -1407,6 +1443,7 @@ kubectl delete crd tridentvolumes.trident.netapp.io --wait=false
 kubectl delete crd tridentvolumepublications.trident.netapp.io --wait=false
 kubectl delete crd tridentnodes.trident.netapp.io --wait=false
 kubectl delete crd tridenttransactions.trident.netapp.io --wait=false
+kubectl delete crd tridentjobs.trident.netapp.io --wait=false
 kubectl delete crd tridentsnapshots.trident.netapp.io --wait=false
 kubectl delete crd tridentvolumereferences.trident.netapp.io --wait=false
 
@@ -1419,6 +1456,7 @@ kubectl patch crd tridentvolumes.trident.netapp.io -p '{"metadata":{"finalizers"
 kubectl patch crd tridentvolumepublications.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentnodes.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridenttransactions.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
+kubectl patch crd tridentjobs.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentsnapshots.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 kubectl patch crd tridentvolumereferences.trident.netapp.io -p '{"metadata":{"finalizers": []}}' --type=merge
 
@@ -1431,6 +1469,7 @@ kubectl delete crd tridentvolumes.trident.netapp.io
 kubectl delete crd tridentvolumepublications.trident.netapp.io
 kubectl delete crd tridentnodes.trident.netapp.io
 kubectl delete crd tridenttransactions.trident.netapp.io
+kubectl delete crd tridentjobs.trident.netapp.io
 kubectl delete crd tridentsnapshots.trident.netapp.io
 kubectl delete crd tridentvolumereferences.trident.netapp.io
 */
@@ -1535,6 +1574,8 @@ spec:
                   type: string
                 replicationSchedule:
                   type: string
+                recoveryPointObjective:
+                  type: string
                 volumeMappings:
                   items:
                     type: object
@@ -1578,6 +1619,12 @@ spec:
                         type: string
                       replicationSchedule:
                         type: string
+                      health:
+                        type: string
+                      lastTransferTime:
+                        type: string
+                      replicationLag:
+                        type: string
       subresources:
         status: {}
       additionalPrinterColumns:
@@ -1597,6 +1644,10 @@ spec:
         jsonPath: .status.conditions[*].message
         name: Message
         type: string
+      - description: Mirror health
+        jsonPath: .status.conditions[*].health
+        name: Health
+        type: string
   scope: Namespaced
   names:
     plural: tridentmirrorrelationships
@@ -1928,6 +1979,47 @@ spec:
     categories:
     - trident-internal`
 
+const tridentJobCRDYAMLv1 = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tridentjobs.trident.netapp.io
+spec:
+  group: trident.netapp.io
+  versions:
+    - name: v1
+      served: true
+      storage: true
+      schema:
+          openAPIV3Schema:
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+      additionalPrinterColumns:
+      - name: Operation
+        type: string
+        description: The job's operation
+        priority: 0
+        jsonPath: .job.op
+      - name: State
+        type: string
+        description: The job's state
+        priority: 0
+        jsonPath: .job.state
+      - name: Progress
+        type: integer
+        description: The job's progress, in percent
+        priority: 1
+        jsonPath: .job.progress
+  scope: Namespaced
+  names:
+    plural: tridentjobs
+    singular: tridentjob
+    kind: TridentJob
+    shortNames:
+    - tjob
+    categories:
+    - trident-internal`
+
 const tridentSnapshotCRDYAMLv1 = `
 apiVersion: apiextensions.k8s.io/v1
 kind: CustomResourceDefinition
@@ -2038,6 +2130,7 @@ const customResourceDefinitionYAMLv1 = tridentVersionCRDYAMLv1 +
 	"\n---" + tridentVolumePublicationCRDYAMLv1 +
 	"\n---" + tridentNodeCRDYAMLv1 +
 	"\n---" + tridentTransactionCRDYAMLv1 +
+	"\n---" + tridentJobCRDYAMLv1 +
 	"\n---" + tridentSnapshotCRDYAMLv1 +
 	"\n---" + tridentVolumeReferenceCRDYAMLv1 + "\n"
 
@@ -2057,6 +2150,9 @@ metadata:
   {OWNER_REF}
 spec:
   attachRequired: true
+  volumeLifecycleModes:
+  - Persistent
+  - Ephemeral
 `
 
 func GetPrivilegedPodSecurityPolicyYAML(pspName string, labels, controllingCRDetails map[string]string) string {
@@ -487,6 +487,17 @@ func TestGetCSIDaemonSetYAMLLinux_ForceDetach(t *testing.T) {
 	}
 }
 
+func TestGetCSIDaemonSetYAMLLinux_ManageMultipath(t *testing.T) {
+	version := utils.MustParseSemantic("1.26.0")
+
+	for _, enabled := range []bool{false, true} {
+		yamlData := GetCSIDaemonSetYAMLLinux(&DaemonsetYAMLArguments{Version: version, ManageMultipath: enabled})
+		_, err := yaml.YAMLToJSON([]byte(yamlData))
+		assert.NoError(t, err, "expected valid YAML")
+		assert.Contains(t, yamlData, fmt.Sprintf(`- "--manage_multipath_config=%t"`, enabled))
+	}
+}
+
 func TestGetCSIDaemonSetYAMLLinuxImagePullPolicy(t *testing.T) {
 	versions := []string{"1.26.0"}
 	expectedStr := `imagePullPolicy: %s`
@@ -1075,6 +1086,9 @@ func TestGetCRDsYAML(t *testing.T) {
 						"replicationSchedule": {
 							Type: "string",
 						},
+						"recoveryPointObjective": {
+							Type: "string",
+						},
 						"volumeMappings": {
 							Items: &apiextensionsv1.JSONSchemaPropsOrArray{
 								Schema: &apiextensionsv1.JSONSchemaProps{
@@ -1136,6 +1150,15 @@ func TestGetCRDsYAML(t *testing.T) {
 										"replicationSchedule": {
 											Type: "string",
 										},
+										"health": {
+											Type: "string",
+										},
+										"lastTransferTime": {
+											Type: "string",
+										},
+										"replicationLag": {
+											Type: "string",
+										},
 									},
 								},
 							},
@@ -1432,6 +1455,12 @@ func TestGetCRDsYAML(t *testing.T) {
 							Description: "Status message",
 							JSONPath:    ".status.conditions[*].message",
 						},
+						{
+							Name:        "Health",
+							Type:        "string",
+							Description: "Mirror health",
+							JSONPath:    ".status.conditions[*].health",
+						},
 					},
 				},
 			},
@@ -1693,6 +1722,57 @@ func TestGetCRDsYAML(t *testing.T) {
 			},
 		},
 	}
+	expectedJob := apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CustomResourceDefinition",
+			APIVersion: "apiextensions.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tridentjobs.trident.netapp.io",
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "trident.netapp.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:     "tridentjobs",
+				Singular:   "tridentjob",
+				Kind:       "TridentJob",
+				ShortNames: []string{"tjob"},
+				Categories: []string{"trident-internal"},
+			},
+			Scope: "Namespaced",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema:  &schema1,
+					AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+						{
+							Name:        "Operation",
+							Type:        "string",
+							Description: "The job's operation",
+							Priority:    int32(0),
+							JSONPath:    ".job.op",
+						},
+						{
+							Name:        "State",
+							Type:        "string",
+							Description: "The job's state",
+							Priority:    int32(0),
+							JSONPath:    ".job.state",
+						},
+						{
+							Name:        "Progress",
+							Type:        "integer",
+							Description: "The job's progress, in percent",
+							Priority:    int32(1),
+							JSONPath:    ".job.progress",
+						},
+					},
+				},
+			},
+		},
+	}
 	expected11 := apiextensionsv1.CustomResourceDefinition{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "CustomResourceDefinition",
@@ -1820,14 +1900,20 @@ func TestGetCRDsYAML(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(expected10.ObjectMeta, actual10.ObjectMeta))
 	assert.True(t, reflect.DeepEqual(expected10.Spec, actual10.Spec))
 
+	var actualJob apiextensionsv1.CustomResourceDefinition
+	assert.Nil(t, yaml.Unmarshal([]byte(result[10]), &actualJob), "invalid YAML")
+	assert.True(t, reflect.DeepEqual(expectedJob.TypeMeta, actualJob.TypeMeta))
+	assert.True(t, reflect.DeepEqual(expectedJob.ObjectMeta, actualJob.ObjectMeta))
+	assert.True(t, reflect.DeepEqual(expectedJob.Spec, actualJob.Spec))
+
 	var actual11 apiextensionsv1.CustomResourceDefinition
-	assert.Nil(t, yaml.Unmarshal([]byte(result[10]), &actual11), "invalid YAML")
+	assert.Nil(t, yaml.Unmarshal([]byte(result[11]), &actual11), "invalid YAML")
 	assert.True(t, reflect.DeepEqual(expected11.TypeMeta, actual11.TypeMeta))
 	assert.True(t, reflect.DeepEqual(expected11.ObjectMeta, actual11.ObjectMeta))
 	assert.True(t, reflect.DeepEqual(expected11.Spec, actual11.Spec))
 
 	var actual12 apiextensionsv1.CustomResourceDefinition
-	assert.Nil(t, yaml.Unmarshal([]byte(result[11]), &actual12), "invalid YAML")
+	assert.Nil(t, yaml.Unmarshal([]byte(result[12]), &actual12), "invalid YAML")
 	assert.True(t, reflect.DeepEqual(expected12.TypeMeta, actual12.TypeMeta))
 	assert.True(t, reflect.DeepEqual(expected12.ObjectMeta, actual12.ObjectMeta))
 	assert.True(t, reflect.DeepEqual(expected12.Spec, actual12.Spec))
@@ -2134,6 +2220,9 @@ func TestGetMirrorRelationshipCRDYAML(t *testing.T) {
 						"replicationSchedule": {
 							Type: "string",
 						},
+						"recoveryPointObjective": {
+							Type: "string",
+						},
 						"volumeMappings": {
 							Items: &apiextensionsv1.JSONSchemaPropsOrArray{
 								Schema: &apiextensionsv1.JSONSchemaProps{
@@ -2195,6 +2284,15 @@ func TestGetMirrorRelationshipCRDYAML(t *testing.T) {
 										"replicationSchedule": {
 											Type: "string",
 										},
+										"health": {
+											Type: "string",
+										},
+										"lastTransferTime": {
+											Type: "string",
+										},
+										"replicationLag": {
+											Type: "string",
+										},
 									},
 								},
 							},
@@ -2257,6 +2355,12 @@ func TestGetMirrorRelationshipCRDYAML(t *testing.T) {
 							Description: "Status message",
 							JSONPath:    ".status.conditions[*].message",
 						},
+						{
+							Name:        "Health",
+							Type:        "string",
+							Description: "Mirror health",
+							JSONPath:    ".status.conditions[*].health",
+						},
 					},
 				},
 			},
@@ -2679,6 +2783,75 @@ func TestGetTransactionCRDYAML(t *testing.T) {
 	assert.True(t, reflect.DeepEqual(expected.Spec, actual.Spec))
 }
 
+func TestGetJobCRDYAML(t *testing.T) {
+	preserveValue := true
+	schema := apiextensionsv1.CustomResourceValidation{
+		OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+			Type:                   "object",
+			XPreserveUnknownFields: &preserveValue,
+		},
+	}
+	expected := apiextensionsv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CustomResourceDefinition",
+			APIVersion: "apiextensions.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "tridentjobs.trident.netapp.io",
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "trident.netapp.io",
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Plural:     "tridentjobs",
+				Singular:   "tridentjob",
+				Kind:       "TridentJob",
+				ShortNames: []string{"tjob"},
+				Categories: []string{"trident-internal"},
+			},
+			Scope: "Namespaced",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1",
+					Served:  true,
+					Storage: true,
+					Schema:  &schema,
+					AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+						{
+							Name:        "Operation",
+							Type:        "string",
+							Description: "The job's operation",
+							Priority:    int32(0),
+							JSONPath:    ".job.op",
+						},
+						{
+							Name:        "State",
+							Type:        "string",
+							Description: "The job's state",
+							Priority:    int32(0),
+							JSONPath:    ".job.state",
+						},
+						{
+							Name:        "Progress",
+							Type:        "integer",
+							Description: "The job's progress, in percent",
+							Priority:    int32(1),
+							JSONPath:    ".job.progress",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	actualYAML := GetJobCRDYAML()
+
+	var actual apiextensionsv1.CustomResourceDefinition
+	assert.Nil(t, yaml.Unmarshal([]byte(actualYAML), &actual), "invalid YAML")
+	assert.True(t, reflect.DeepEqual(expected.TypeMeta, actual.TypeMeta))
+	assert.True(t, reflect.DeepEqual(expected.ObjectMeta, actual.ObjectMeta))
+	assert.True(t, reflect.DeepEqual(expected.Spec, actual.Spec))
+}
+
 func TestGetSnapshotCRDYAML(t *testing.T) {
 	preserveValue := true
 	schema := apiextensionsv1.CustomResourceValidation{
@@ -2797,6 +2970,10 @@ func TestGetCSIDriverYAML(t *testing.T) {
 		},
 		Spec: csiv1.CSIDriverSpec{
 			AttachRequired: &required,
+			VolumeLifecycleModes: []csiv1.VolumeLifecycleMode{
+				csiv1.VolumeLifecyclePersistent,
+				csiv1.VolumeLifecycleEphemeral,
+			},
 		},
 	}
 
@@ -2806,6 +2983,7 @@ func TestGetCSIDriverYAML(t *testing.T) {
 	assert.Nil(t, yaml.Unmarshal([]byte(actualYAML), &actual), "invalid YAML")
 	assert.True(t, reflect.DeepEqual(expected.TypeMeta, actual.TypeMeta))
 	assert.True(t, reflect.DeepEqual(expected.ObjectMeta, actual.ObjectMeta))
+	assert.True(t, reflect.DeepEqual(expected.Spec, actual.Spec))
 	assert.Equal(t, "csi.trident.netapp.io", actual.Name)
 }
 
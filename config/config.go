@@ -53,27 +53,32 @@ const (
 	HTTPTimeout       = 90 * time.Second
 	HTTPTimeoutString = "90s"
 
-	CACertName     = "trident-ca"
-	ServerCertName = "trident-csi" // Must match CSI service name
-	ClientCertName = "trident-node"
-
-	CAKeyFile      = "caKey"
-	CACertFile     = "caCert"
-	ServerKeyFile  = "serverKey"
-	ServerCertFile = "serverCert"
-	ClientKeyFile  = "clientKey"
-	ClientCertFile = "clientCert"
-	AESKeyFile     = "aesKey"
+	CACertName         = "trident-ca"
+	ServerCertName     = "trident-csi" // Must match CSI service name
+	ClientCertName     = "trident-node"
+	ControllerCertName = "trident-controller" // Identifies the controller to nodes
+
+	CAKeyFile          = "caKey"
+	CACertFile         = "caCert"
+	ServerKeyFile      = "serverKey"
+	ServerCertFile     = "serverCert"
+	ClientKeyFile      = "clientKey"
+	ClientCertFile     = "clientCert"
+	ControllerKeyFile  = "controllerKey"
+	ControllerCertFile = "controllerCert"
+	AESKeyFile         = "aesKey"
 
 	certsPath = "/certs/"
 
-	CAKeyPath      = certsPath + CAKeyFile
-	CACertPath     = certsPath + CACertFile
-	ServerKeyPath  = certsPath + ServerKeyFile
-	ServerCertPath = certsPath + ServerCertFile
-	ClientKeyPath  = certsPath + ClientKeyFile
-	ClientCertPath = certsPath + ClientCertFile
-	AESKeyPath     = certsPath + AESKeyFile
+	CAKeyPath          = certsPath + CAKeyFile
+	CACertPath         = certsPath + CACertFile
+	ServerKeyPath      = certsPath + ServerKeyFile
+	ServerCertPath     = certsPath + ServerCertFile
+	ClientKeyPath      = certsPath + ClientKeyFile
+	ClientCertPath     = certsPath + ClientCertFile
+	ControllerKeyPath  = certsPath + ControllerKeyFile
+	ControllerCertPath = certsPath + ControllerCertFile
+	AESKeyPath         = certsPath + AESKeyFile
 
 	/* Protocol constants. This value denotes a volume's backing storage protocol. For example,
 	a Trident volume with  'file' protocol is most likely NFS, while a 'block' protocol volume is probably iSCSI. */
@@ -83,10 +88,11 @@ const (
 	ProtocolAny Protocol = ""
 
 	/* Access mode constants */
-	ReadWriteOnce AccessMode = "ReadWriteOnce"
-	ReadOnlyMany  AccessMode = "ReadOnlyMany"
-	ReadWriteMany AccessMode = "ReadWriteMany"
-	ModeAny       AccessMode = ""
+	ReadWriteOnce    AccessMode = "ReadWriteOnce"
+	ReadWriteOncePod AccessMode = "ReadWriteOncePod"
+	ReadOnlyMany     AccessMode = "ReadOnlyMany"
+	ReadWriteMany    AccessMode = "ReadWriteMany"
+	ModeAny          AccessMode = ""
 
 	/* Volume mode constants. This value describes how a volume will be consumed by application containers.
 	Most Trident volumes (regardless of protocol) probably use the 'Filesystem' mode, where the volume contains
@@ -164,6 +170,15 @@ const (
 
 	// ISCSISelfHealingWaitTime is an interval after which iSCSI self-healing attempts to fix stale sessions.
 	ISCSISelfHealingWaitTime = 420 * time.Second
+
+	// VolumeJobWorkers is the default number of volume jobs that may run at once
+	VolumeJobWorkers = 8
+
+	// VolumeJobBackendConcurrency is the default number of volume jobs that may be in flight on any one backend
+	VolumeJobBackendConcurrency = 4
+
+	// VolumeJobWaitTime is the default time CreateVolume waits for a volume job before asking the CO to retry
+	VolumeJobWaitTime = 10 * time.Second
 )
 
 var (
@@ -200,11 +215,13 @@ var (
 	BackendUUIDURL  = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/backendUUID"
 	VolumeURL       = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/volume"
 	TransactionURL  = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/txn"
+	JobURL          = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/job"
 	StorageClassURL = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/storageclass"
 	NodeURL         = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/node"
 	SnapshotURL     = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/snapshot"
 	ChapURL         = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/chap"
 	PublicationURL  = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/publication"
+	LUKSRotationURL = "/" + OrchestratorName + "/v" + OrchestratorAPIVersion + "/luksrotation"
 	StoreURL        = "/" + OrchestratorName + "/store"
 
 	UsingPassthroughStore bool
@@ -0,0 +1,336 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	backendHealthCheckPeriod  = 1 * time.Minute
+	backendHealthCheckTimeout = 30 * time.Second
+)
+
+// Whether a backend is responding is decided by its circuit breaker, to which every storage API call made to the
+// backend is reported, including the health monitor's probes.  The monitor probes each online backend so that the
+// breaker of a backend that is not otherwise called learns when it stops responding, takes a backend offline while
+// its breaker is open, and brings it back online once the breaker closes.  A backend that failed to initialize has
+// no breaker to consult, so the monitor tries to initialize it again instead.
+
+// backendHealth is what the health monitor knows about a backend.
+type backendHealth struct {
+	// recoverable is set when the backend was taken offline by the health monitor or failed to initialize,
+	// so the monitor should bring it back online once it responds again
+	recoverable bool
+	lastError   error
+}
+
+// backendHealthMonitor periodically probes each online backend's storage system, takes backends offline
+// while their circuit breakers are open, and brings them back online once the breakers close.
+type backendHealthMonitor struct {
+	mutex    *sync.Mutex
+	backends map[string]*backendHealth // key is backend UUID
+	period   time.Duration
+	timeout  time.Duration
+	stop     chan struct{}
+	started  bool
+	stopped  bool
+}
+
+func newBackendHealthMonitor() *backendHealthMonitor {
+	return &backendHealthMonitor{
+		mutex:    &sync.Mutex{},
+		backends: make(map[string]*backendHealth),
+		period:   backendHealthCheckPeriod,
+		timeout:  backendHealthCheckTimeout,
+		stop:     make(chan struct{}),
+	}
+}
+
+// get returns a copy of a backend's health.
+func (m *backendHealthMonitor) get(backendUUID string) backendHealth {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if health, ok := m.backends[backendUUID]; ok {
+		return *health
+	}
+	return backendHealth{}
+}
+
+// markRecoverable records that a backend is offline or failed because it could not be reached, so that
+// the monitor will bring it back online once it responds.
+func (m *backendHealthMonitor) markRecoverable(backendUUID string, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	health, ok := m.backends[backendUUID]
+	if !ok {
+		health = &backendHealth{}
+		m.backends[backendUUID] = health
+	}
+	health.recoverable = true
+	health.lastError = err
+}
+
+// reset forgets a backend's health, as when it is brought back online or is updated or deleted.
+func (m *backendHealthMonitor) reset(backendUUID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	delete(m.backends, backendUUID)
+}
+
+// startBackendHealthMonitor starts the thread that checks the health of the backends.
+func (o *TridentOrchestrator) startBackendHealthMonitor(ctx context.Context) {
+	m := o.health
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.started || m.stopped {
+		return
+	}
+	m.started = true
+
+	go func() {
+		ticker := time.NewTicker(m.period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+				ctx := GenerateRequestContext(context.Background(), "", ContextSourcePeriodic)
+				o.checkBackendHealth(ctx)
+			}
+		}
+	}()
+
+	Logc(ctx).WithField("period", m.period).Debug("Backend health monitor started.")
+}
+
+// stopBackendHealthMonitor stops the thread that checks the health of the backends.
+func (o *TridentOrchestrator) stopBackendHealthMonitor() {
+	m := o.health
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if !m.stopped {
+		close(m.stop)
+		m.stopped = true
+	}
+}
+
+// checkBackendHealth probes every online backend, taking offline any whose circuit breakers have opened, brings
+// back online any it took offline whose breakers have closed, and tries to initialize again any that failed to
+// initialize because they could not be reached.  Backends that are being deleted, or that an administrator failed,
+// are left alone.
+func (o *TridentOrchestrator) checkBackendHealth(ctx context.Context) {
+	if o.bootstrapError != nil {
+		return
+	}
+
+	o.mutex.RLock()
+	online := make([]storage.Backend, 0, len(o.backends))
+	offline := make([]storage.Backend, 0)
+	failed := make([]storage.Backend, 0)
+	for _, backend := range o.backends {
+		switch state := backend.State(); {
+		case state.IsOnline():
+			online = append(online, backend)
+		case state.IsOffline() && o.health.get(backend.BackendUUID()).recoverable:
+			offline = append(offline, backend)
+		case state.IsFailed() && o.health.get(backend.BackendUUID()).recoverable:
+			failed = append(failed, backend)
+		}
+	}
+	o.mutex.RUnlock()
+
+	for _, backend := range online {
+		o.probeBackend(ctx, backend)
+	}
+	for _, backend := range offline {
+		if degraded, _ := backend.Degraded(); !degraded {
+			o.onlineBackend(ctx, backend)
+		}
+	}
+	for _, backend := range failed {
+		o.reinitializeBackend(ctx, backend)
+	}
+}
+
+// probeBackend checks that an online backend's storage system is responding, and takes the backend offline if
+// its circuit breaker has opened.
+func (o *TridentOrchestrator) probeBackend(ctx context.Context, backend storage.Backend) {
+	logFields := log.Fields{"backend": backend.Name(), "backendUUID": backend.BackendUUID()}
+
+	probeCtx, cancel := context.WithTimeout(ctx, o.health.timeout)
+	err := backend.ProbeHealth(probeCtx)
+	cancel()
+
+	if err != nil && !utils.IsBackendUnavailableError(err) {
+		backendHealthCheckFailuresCounter.WithLabelValues(backend.GetDriverName(), backend.Name(),
+			backend.BackendUUID()).Inc()
+		Logc(ctx).WithFields(logFields).WithError(err).Warning("Backend health check failed.")
+	}
+
+	if degraded, reason := backend.Degraded(); degraded {
+		o.offlineBackend(ctx, backend, errors.New(reason))
+		return
+	}
+
+	if err == nil {
+		Logc(ctx).WithFields(logFields).Trace("Backend is healthy.")
+		o.updatePoolUtilization(ctx, backend)
+	}
+}
+
+// updatePoolUtilization refreshes the performance utilization reported by a backend's storage pools, which
+// storage classes may use to avoid busy pools.
+func (o *TridentOrchestrator) updatePoolUtilization(ctx context.Context, backend storage.Backend) {
+	updateCtx, cancel := context.WithTimeout(ctx, o.health.timeout)
+	defer cancel()
+
+	if err := backend.UpdatePoolUtilization(updateCtx); err != nil && !utils.IsUnsupportedError(err) {
+		Logc(ctx).WithField("backend", backend.Name()).WithError(err).Warning(
+			"Could not update storage pool utilization.")
+	}
+}
+
+// offlineBackend takes a backend that is not responding offline, so that no new volumes are placed on it.
+func (o *TridentOrchestrator) offlineBackend(ctx context.Context, backend storage.Backend, reason error) {
+	backendUUID := backend.BackendUUID()
+
+	// Wait for any volumes being provisioned on the backend
+	lockBackend(ctx, "offlineBackend", backendUUID)
+	defer unlockBackend(ctx, "offlineBackend", backendUUID)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	// The backend may have been updated, deleted, or failed while it was being probed
+	if o.backends[backendUUID] != backend || !backend.State().IsOnline() {
+		return
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":     backend.Name(),
+		"backendUUID": backendUUID,
+	}).WithError(reason).Error("Backend is not responding, taking it offline.")
+
+	backend.SetOnline(false)
+	backend.SetState(storage.Offline)
+	for _, storagePool := range backend.Storage() {
+		storagePool.SetStorageClasses([]string{})
+	}
+	for _, sc := range o.storageClasses {
+		sc.RemovePoolsForBackend(backend)
+	}
+	o.health.markRecoverable(backendUUID, reason)
+
+	if err := o.storeClient.UpdateBackend(ctx, backend); err != nil {
+		Logc(ctx).WithField("backend", backend.Name()).WithError(err).Error("Could not persist offline backend.")
+	}
+}
+
+// onlineBackend brings a backend that offlineBackend took offline back online, once its circuit breaker has
+// closed because the backend is responding again.
+func (o *TridentOrchestrator) onlineBackend(ctx context.Context, backend storage.Backend) {
+	backendUUID := backend.BackendUUID()
+
+	lockBackend(ctx, "onlineBackend", backendUUID)
+	defer unlockBackend(ctx, "onlineBackend", backendUUID)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	// The backend may have been updated, deleted, or failed while it was offline
+	if o.backends[backendUUID] != backend || !backend.State().IsOffline() {
+		return
+	}
+
+	backend.SetOnline(true)
+	backend.SetState(storage.Online)
+	for _, sc := range o.storageClasses {
+		sc.CheckAndAddBackend(ctx, backend)
+	}
+	o.health.reset(backendUUID)
+
+	if err := o.storeClient.UpdateBackend(ctx, backend); err != nil {
+		Logc(ctx).WithField("backend", backend.Name()).WithError(err).Error("Could not persist online backend.")
+	}
+
+	// Node access may have changed while the backend was offline, so it is reconciled before the next publish
+	backend.InvalidateNodeAccess()
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":     backend.Name(),
+		"backendUUID": backendUUID,
+	}).Info("Backend is responding again, brought it back online.")
+}
+
+// reinitializeBackend recreates a backend that failed to initialize from its config, and brings it online if it
+// initializes.
+func (o *TridentOrchestrator) reinitializeBackend(ctx context.Context, backend storage.Backend) {
+	backendUUID := backend.BackendUUID()
+	logFields := log.Fields{"backend": backend.Name(), "backendUUID": backendUUID}
+
+	o.mutex.RLock()
+	configJSON, err := backend.ConstructPersistent(ctx).MarshalConfig()
+	o.mutex.RUnlock()
+	if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Could not read backend config.")
+		return
+	}
+
+	newBackend, err := o.validateAndCreateBackendFromConfig(ctx, configJSON, backend.ConfigRef(), backendUUID)
+	if err != nil {
+		if newBackend != nil {
+			newBackend.Terminate(ctx)
+		}
+		o.health.markRecoverable(backendUUID, err)
+		Logc(ctx).WithFields(logFields).WithError(err).Debug("Backend still cannot be initialized.")
+		return
+	}
+
+	lockBackend(ctx, "reinitializeBackend", backendUUID)
+	defer unlockBackend(ctx, "reinitializeBackend", backendUUID)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	// The backend may have been updated or deleted while it was being reinitialized
+	if o.backends[backendUUID] != backend || !backend.State().IsFailed() {
+		newBackend.Terminate(ctx)
+		return
+	}
+
+	newBackend.SetName(backend.Name())
+	if err = o.updateBackendOnPersistentStore(ctx, newBackend, false); err != nil {
+		newBackend.Terminate(ctx)
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Could not persist reinitialized backend.")
+		return
+	}
+	if err = o.replaceBackend(ctx, backend, newBackend); err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Warning("Could not update volumes on reinitialized backend.")
+	}
+
+	// Node access may have changed while the backend was failed, so it is reconciled before the next publish
+	newBackend.InvalidateNodeAccess()
+
+	Logc(ctx).WithFields(logFields).Info("Backend initialized, brought it online.")
+}
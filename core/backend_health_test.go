@@ -0,0 +1,124 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/storage"
+)
+
+// unhealthyBackend fails its health checks while probeErr is set, and reports that its circuit breaker is open
+// while degraded is set.
+type unhealthyBackend struct {
+	*slowBackend
+	probeErr error
+	degraded bool
+}
+
+func (b *unhealthyBackend) ProbeHealth(ctx context.Context) error {
+	return b.probeErr
+}
+
+func (b *unhealthyBackend) Degraded() (bool, string) {
+	if b.degraded {
+		return true, "backend stopped responding"
+	}
+	return false, ""
+}
+
+func addUnhealthyBackend(t *testing.T, o *TridentOrchestrator, name string) *unhealthyBackend {
+	slow := addSlowBackend(t, o, name, 0)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	backend := &unhealthyBackend{slowBackend: slow}
+	for _, pool := range backend.Storage() {
+		pool.SetBackend(backend)
+	}
+	o.backends[backend.BackendUUID()] = backend
+	return backend
+}
+
+func TestCheckBackendHealth(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	backend := addUnhealthyBackend(t, o, "backend")
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+	_, err := o.AddVolume(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Failed health checks are left to the backend's circuit breaker to judge
+	backend.probeErr = errors.New("connection refused")
+	o.checkBackendHealth(ctx())
+	assert.True(t, backend.State().IsOnline())
+
+	// Once the breaker opens, the backend is taken offline and removed from its storage classes
+	backend.degraded = true
+	o.checkBackendHealth(ctx())
+	assert.True(t, backend.State().IsOffline())
+	assert.False(t, backend.Online())
+	assert.Empty(t, o.storageClasses["sc"].GetStoragePoolsForProtocol(ctx(), "", ""))
+	persistentBackend, err := o.storeClient.GetBackend(ctx(), "backend")
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.Offline, persistentBackend.State)
+	}
+	_, err = o.AddVolume(ctx(), getLockTestVolumeConfig("vol2", "sc"))
+	assert.Error(t, err)
+
+	// The backend stays offline while the breaker is open
+	o.checkBackendHealth(ctx())
+	assert.True(t, backend.State().IsOffline())
+
+	// Once the breaker closes, the backend is brought back online with its volumes
+	backend.probeErr = nil
+	backend.degraded = false
+	o.checkBackendHealth(ctx())
+	current, err := o.getBackendByBackendUUID(backend.BackendUUID())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Same(t, backend, current)
+	assert.True(t, current.State().IsOnline())
+	assert.True(t, current.Online())
+	assert.Contains(t, current.Volumes(), "vol1")
+	assert.Len(t, o.storageClasses["sc"].GetStoragePoolsForProtocol(ctx(), "", ""), 1)
+	assert.Equal(t, backendHealth{}, o.health.get(backend.BackendUUID()))
+	persistentBackend, err = o.storeClient.GetBackend(ctx(), "backend")
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.Online, persistentBackend.State)
+	}
+
+	_, err = o.AddVolume(ctx(), getLockTestVolumeConfig("vol2", "sc"))
+	assert.NoError(t, err)
+}
+
+func TestCheckBackendHealth_FailedBackendIsLeftAlone(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	backend := addUnhealthyBackend(t, o, "backend")
+	backend.probeErr = errors.New("connection refused")
+	backend.degraded = true
+
+	_, err := o.UpdateBackendState(ctx(), "backend", string(storage.Failed))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	o.checkBackendHealth(ctx())
+	o.checkBackendHealth(ctx())
+
+	current, err := o.getBackendByBackendUUID(backend.BackendUUID())
+	assert.NoError(t, err)
+	assert.Same(t, backend, current)
+	assert.True(t, current.State().IsFailed())
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// Docker hosts don't share a persistent store, so a host can't learn from its own state whether another host
+// is using a volume.  Backends that can record publications with the volume on the storage system are used
+// instead, which lets each host refuse to attach a volume that is already attached elsewhere.
+
+// isShareableVolume returns true if a volume may safely be attached to more than one host at a time.
+func isShareableVolume(ctx context.Context, volume *storage.Volume, backend storage.Backend) bool {
+	switch volume.Config.AccessMode {
+	case config.ReadWriteMany, config.ReadOnlyMany:
+		return true
+	}
+	return backend.GetProtocol(ctx) == config.File
+}
+
+// refreshVolumePublications replaces the cached publications of a volume with those recorded on its backend.
+func (o *TridentOrchestrator) refreshVolumePublications(
+	ctx context.Context, volume *storage.Volume, backend storage.Backend,
+) ([]*utils.VolumePublication, error) {
+	publications, err := backend.GetVolumePublications(ctx, volume.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := make(map[string]bool, len(publications))
+	for _, publication := range publications {
+		recorded[publication.NodeName] = true
+		if err = o.volumePublications.Set(volume.Config.Name, publication.NodeName, publication); err != nil {
+			return nil, fmt.Errorf("unable to cache volume publication %s; %v", publication.Name, err)
+		}
+	}
+	for _, publication := range o.volumePublications.ListPublicationsForVolume(volume.Config.Name) {
+		if recorded[publication.NodeName] {
+			continue
+		}
+		if err = o.volumePublications.Delete(volume.Config.Name, publication.NodeName); err != nil {
+			return nil, fmt.Errorf("unable to remove volume publication %s from cache; %v", publication.Name, err)
+		}
+	}
+
+	return publications, nil
+}
+
+// checkDockerVolumePublications records a volume's publication to this Docker host on its backend before it is
+// published, refusing it if the volume isn't shareable and is attached to another host.  The backend checks
+// and records the publication atomically, since other hosts may be publishing the volume at the same time.
+// Volumes on backends that don't record publications are not checked.
+func (o *TridentOrchestrator) checkDockerVolumePublications(
+	ctx context.Context, volume *storage.Volume, publishInfo *utils.VolumePublishInfo,
+) error {
+	backend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		// Not a not found error because this is not user input
+		return fmt.Errorf("backend %s not found", volume.BackendUUID)
+	}
+
+	publications, err := o.refreshVolumePublications(ctx, volume, backend)
+	if err != nil {
+		if utils.IsUnsupportedError(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to read publications of volume %s; %v", volume.Config.Name, err)
+	}
+	alreadyPublished := false
+	for _, publication := range publications {
+		if publication.NodeName == publishInfo.HostName {
+			alreadyPublished = true
+		}
+	}
+
+	exclusive := !isShareableVolume(ctx, volume, backend)
+	publication := generateVolumePublication(volume.Config.Name, publishInfo)
+	if err = backend.AddVolumePublication(ctx, volume.Config, publication, exclusive); err != nil {
+		if utils.IsFoundError(err) {
+			return utils.FoundError(fmt.Sprintf("%v; run 'tridentctl update volume %s --takeover' on this "+
+				"host to take it over", err, volume.Config.Name))
+		}
+		return fmt.Errorf("unable to record publication of volume %s; %v", volume.Config.Name, err)
+	}
+	if err = o.volumePublications.Set(volume.Config.Name, publication.NodeName, publication); err != nil {
+		return fmt.Errorf("unable to cache volume publication %s; %v", publication.Name, err)
+	}
+
+	// Nobody else is using the volume, so remove any mappings left by hosts that used it before
+	if exclusive && !alreadyPublished {
+		if err = backend.EnablePublishEnforcement(ctx, volume); err != nil {
+			Logc(ctx).WithError(err).Warnf("Error enabling volume publish enforcement for volume %s",
+				volume.Config.Name)
+		}
+	}
+
+	return nil
+}
+
+// TakeOverVolume fences every other Docker host from a volume so that it may be published to the named host.
+// This is meant for recovering volumes from hosts that failed without unmounting them.
+func (o *TridentOrchestrator) TakeOverVolume(ctx context.Context, volumeName, hostName string) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("volume_takeover", &err)()
+
+	if config.CurrentDriverContext != config.ContextDocker {
+		return utils.UnsupportedError("volume takeover is only supported with Docker")
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if volume.State.IsDeleting() {
+		return utils.VolumeStateError(fmt.Sprintf("volume %s is deleting", volumeName))
+	}
+
+	backend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("backend %s not found", volume.BackendUUID))
+	}
+
+	publications, err := o.refreshVolumePublications(ctx, volume, backend)
+	if err != nil {
+		return err
+	}
+
+	// Removing every mapping also fences hosts that mapped the volume through the backend's igroup, which
+	// Docker hosts did before they each used their own
+	if err = backend.EnablePublishEnforcement(ctx, volume); err != nil {
+		return fmt.Errorf("unable to fence other hosts from volume %s; %v", volumeName, err)
+	}
+
+	for _, publication := range publications {
+		if publication.NodeName == hostName {
+			continue
+		}
+
+		Logc(ctx).WithFields(log.Fields{
+			"volume":  volumeName,
+			"host":    publication.NodeName,
+			"newHost": hostName,
+		}).Warning("Fencing host from volume.")
+
+		publishInfo := &utils.VolumePublishInfo{
+			HostName:    publication.NodeName,
+			TridentUUID: o.uuid,
+		}
+		if err = backend.UnpublishVolume(ctx, volume.Config, publishInfo); err != nil {
+			return fmt.Errorf("unable to fence host %s from volume %s; %v", publication.NodeName, volumeName, err)
+		}
+		if err = o.deleteVolumePublication(ctx, volumeName, publication.NodeName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// publicationRecordingBackend records volume publications, as ontap-san does.  Once its volumes are
+// provisioned it may claim to be a block backend, so that they are not shareable.
+type publicationRecordingBackend struct {
+	*slowBackend
+	publications map[string][]*utils.VolumePublication
+	fenced       []string
+	enforced     []string
+	revoked      []string
+	block        bool
+	// onRevoke, if set, is called when a node's access is revoked
+	onRevoke func()
+}
+
+func (b *publicationRecordingBackend) GetProtocol(ctx context.Context) config.Protocol {
+	if b.block {
+		return config.Block
+	}
+	return b.slowBackend.GetProtocol(ctx)
+}
+
+func (b *publicationRecordingBackend) GetVolumePublications(
+	_ context.Context, volConfig *storage.VolumeConfig,
+) ([]*utils.VolumePublication, error) {
+	return b.publications[volConfig.Name], nil
+}
+
+func (b *publicationRecordingBackend) AddVolumePublication(
+	_ context.Context, volConfig *storage.VolumeConfig, publication *utils.VolumePublication, exclusive bool,
+) error {
+	publications := make([]*utils.VolumePublication, 0)
+	for _, p := range b.publications[volConfig.Name] {
+		if p.NodeName == publication.NodeName {
+			continue
+		}
+		if exclusive {
+			return utils.FoundError("volume is in use by host " + p.NodeName)
+		}
+		publications = append(publications, p)
+	}
+	b.publications[volConfig.Name] = append(publications, publication)
+	return nil
+}
+
+func (b *publicationRecordingBackend) DeleteVolumePublication(
+	_ context.Context, volConfig *storage.VolumeConfig, nodeName string,
+) error {
+	publications := make([]*utils.VolumePublication, 0)
+	for _, p := range b.publications[volConfig.Name] {
+		if p.NodeName != nodeName {
+			publications = append(publications, p)
+		}
+	}
+	b.publications[volConfig.Name] = publications
+	return nil
+}
+
+func (b *publicationRecordingBackend) RevokeNodeAccess(_ context.Context, node *utils.Node, _ string) error {
+	b.revoked = append(b.revoked, node.Name)
+	if b.onRevoke != nil {
+		b.onRevoke()
+	}
+	return nil
+}
+
+func (b *publicationRecordingBackend) EnablePublishEnforcement(_ context.Context, volume *storage.Volume) error {
+	b.enforced = append(b.enforced, volume.Config.Name)
+	return nil
+}
+
+func (b *publicationRecordingBackend) PublishVolume(
+	context.Context, *storage.VolumeConfig, *utils.VolumePublishInfo,
+) error {
+	return nil
+}
+
+func (b *publicationRecordingBackend) UnpublishVolume(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+	b.fenced = append(b.fenced, publishInfo.HostName)
+	return b.slowBackend.UnpublishVolume(ctx, volConfig, publishInfo)
+}
+
+func addPublicationRecordingBackend(
+	t *testing.T, o *TridentOrchestrator, name string,
+) *publicationRecordingBackend {
+	slow := addSlowBackend(t, o, name, 0)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	backend := &publicationRecordingBackend{
+		slowBackend:  slow,
+		publications: make(map[string][]*utils.VolumePublication),
+	}
+	for _, pool := range backend.Storage() {
+		pool.SetBackend(backend)
+	}
+	o.backends[backend.BackendUUID()] = backend
+	return backend
+}
+
+func TestPublishVolume_Docker(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	backend := addPublicationRecordingBackend(t, o, "backend")
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+	for _, name := range []string{"vol1", "vol2"} {
+		if _, err := o.AddVolume(ctx(), getLockTestVolumeConfig(name, "sc")); !assert.NoError(t, err) {
+			return
+		}
+	}
+	sharedConfig := getLockTestVolumeConfig("shared", "sc")
+	sharedConfig.AccessMode = config.ReadWriteMany
+	if _, err := o.AddVolume(ctx(), sharedConfig); !assert.NoError(t, err) {
+		return
+	}
+
+	backend.block = true
+	originalContext := config.CurrentDriverContext
+	config.CurrentDriverContext = config.ContextDocker
+	defer func() { config.CurrentDriverContext = originalContext }()
+
+	host2 := &utils.VolumePublication{Name: "vol1.host2", VolumeName: "vol1", NodeName: "host2"}
+	backend.publications["vol1"] = []*utils.VolumePublication{host2}
+	backend.publications["shared"] = []*utils.VolumePublication{
+		{Name: "shared.host2", VolumeName: "shared", NodeName: "host2"},
+	}
+
+	// A volume in use by another host is refused, and the other host's publication is now known
+	err := o.PublishVolume(ctx(), "vol1", &utils.VolumePublishInfo{HostName: "host1"})
+	assert.True(t, utils.IsFoundError(err))
+	_, found := o.volumePublications.TryGet("vol1", "host2")
+	assert.True(t, found)
+	assert.Len(t, backend.publications["vol1"], 1)
+
+	// The host it is published to may publish it again, without other hosts being fenced
+	err = o.PublishVolume(ctx(), "vol1", &utils.VolumePublishInfo{HostName: "host2"})
+	assert.NoError(t, err)
+	assert.Empty(t, backend.enforced)
+
+	// A volume nobody uses is recorded as published here, and any hosts that used it before are fenced
+	err = o.PublishVolume(ctx(), "vol2", &utils.VolumePublishInfo{HostName: "host1"})
+	assert.NoError(t, err)
+	if assert.Len(t, backend.publications["vol2"], 1) {
+		assert.Equal(t, "host1", backend.publications["vol2"][0].NodeName)
+	}
+	assert.Equal(t, []string{"vol2"}, backend.enforced)
+
+	// Volumes that may be shared are not checked
+	err = o.PublishVolume(ctx(), "shared", &utils.VolumePublishInfo{HostName: "host1"})
+	assert.NoError(t, err)
+	assert.Len(t, backend.publications["shared"], 2)
+}
+
+func TestTakeOverVolume(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	backend := addPublicationRecordingBackend(t, o, "backend")
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+	if _, err := o.AddVolume(ctx(), getLockTestVolumeConfig("vol1", "sc")); !assert.NoError(t, err) {
+		return
+	}
+
+	backend.block = true
+
+	// Takeover is only meaningful for Docker
+	err := o.TakeOverVolume(ctx(), "vol1", "host1")
+	assert.True(t, utils.IsUnsupportedError(err))
+
+	originalContext := config.CurrentDriverContext
+	config.CurrentDriverContext = config.ContextDocker
+	defer func() { config.CurrentDriverContext = originalContext }()
+
+	err = o.TakeOverVolume(ctx(), "missing", "host1")
+	assert.True(t, utils.IsNotFoundError(err))
+
+	host2 := &utils.VolumePublication{Name: "vol1.host2", VolumeName: "vol1", NodeName: "host2"}
+	backend.publications["vol1"] = []*utils.VolumePublication{
+		{Name: "vol1.host1", VolumeName: "vol1", NodeName: "host1"},
+		host2,
+	}
+	if err = o.storeClient.AddVolumePublication(ctx(), host2); !assert.NoError(t, err) {
+		return
+	}
+
+	err = o.TakeOverVolume(ctx(), "vol1", "host1")
+
+	// Every mapping is removed, only the other host is unpublished, and its publication is forgotten
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vol1"}, backend.enforced)
+	assert.Equal(t, []string{"host2"}, backend.fenced)
+	_, found := o.volumePublications.TryGet("vol1", "host2")
+	assert.False(t, found)
+	_, found = o.volumePublications.TryGet("vol1", "host1")
+	assert.True(t, found)
+}
@@ -0,0 +1,703 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// jobRetryInterval is how long a job waits before it is tried again, either because its backend is busy
+	// or because the backend is still creating its volume
+	jobRetryInterval = 10 * time.Second
+	// jobRetentionPeriod is how long a finished job is kept so that its outcome may be reported
+	jobRetentionPeriod = 60 * time.Minute
+	jobReapPeriod      = 10 * time.Minute
+
+	// Rough progress estimates, in percent, for each phase of a volume job
+	jobProgressStarted  = 10
+	jobProgressWaiting  = 50
+	jobProgressFinished = 100
+)
+
+// jobQueue holds the orchestrator's asynchronous jobs and the queue of jobs waiting for a worker.  Its lock
+// is taken after any other orchestrator lock, and no other orchestrator lock may be taken while it is held.
+// Nor is the persistent store called while it is held; jobs are saved from copies once it is released.
+type jobQueue struct {
+	mutex *sync.Mutex
+	jobs  map[string]*storage.Job // key is job ID
+	// done is closed when the job with the same ID finishes
+	done map[string]chan struct{}
+	// errors holds the errors of jobs that failed while this process was running them
+	errors map[string]error
+	// backendJobs holds the IDs of the jobs in flight on each backend, keyed by backend UUID
+	backendJobs map[string]map[string]bool
+	// admissionKeys holds the key under which each job in flight is counted in backendJobs
+	admissionKeys map[string]string
+	pending       chan string
+	stop          chan struct{}
+	started       bool
+	stopped       bool
+
+	workers int
+	// backendConcurrency is the number of jobs that may be in flight on any one backend, or 0 for no limit
+	backendConcurrency int
+	retryInterval      time.Duration
+	retention          time.Duration
+}
+
+func newJobQueue() *jobQueue {
+	return &jobQueue{
+		mutex:              &sync.Mutex{},
+		jobs:               make(map[string]*storage.Job),
+		done:               make(map[string]chan struct{}),
+		errors:             make(map[string]error),
+		backendJobs:        make(map[string]map[string]bool),
+		admissionKeys:      make(map[string]string),
+		pending:            make(chan string, 1024),
+		stop:               make(chan struct{}),
+		workers:            config.VolumeJobWorkers,
+		backendConcurrency: config.VolumeJobBackendConcurrency,
+		retryInterval:      jobRetryInterval,
+		retention:          jobRetentionPeriod,
+	}
+}
+
+// add records a job that was submitted or read from the persistent store.  The caller must hold the lock.
+func (q *jobQueue) add(job *storage.Job) {
+	q.jobs[job.ID] = job
+	done := make(chan struct{})
+	if job.Done() {
+		close(done)
+	}
+	q.done[job.ID] = done
+}
+
+// remove forgets a finished job.  The caller must hold the lock.
+func (q *jobQueue) remove(job *storage.Job) {
+	delete(q.jobs, job.ID)
+	delete(q.done, job.ID)
+	delete(q.errors, job.ID)
+}
+
+// enqueue adds a job to the queue of jobs waiting for a worker.  It does not block.
+func (q *jobQueue) enqueue(jobID string) {
+	select {
+	case q.pending <- jobID:
+	default:
+		go func() {
+			select {
+			case q.pending <- jobID:
+			case <-q.stop:
+			}
+		}()
+	}
+}
+
+// requeue adds a job to the queue of jobs waiting for a worker once the retry interval has passed.
+func (q *jobQueue) requeue(jobID string) {
+	time.AfterFunc(q.retryInterval, func() {
+		select {
+		case q.pending <- jobID:
+		case <-q.stop:
+		}
+	})
+}
+
+// volumeJob returns the most recently created job for a volume, if any.  The caller must hold the lock.
+func (q *jobQueue) volumeJob(volumeName string) *storage.Job {
+	var latest *storage.Job
+	for _, job := range q.jobs {
+		if job.VolumeName() == volumeName && (latest == nil || job.CreatedTime.After(latest.CreatedTime)) {
+			latest = job
+		}
+	}
+	return latest
+}
+
+// jobAdmissionKey returns the key under which a job counts toward the limit of jobs in flight, which is the
+// UUID of its backend.  A new volume's backend is not chosen until the volume is created, so until then the job
+// has no key, and is admitted to a backend as the orchestrator tries each one (see admitJobOnBackend).
+func jobAdmissionKey(job *storage.Job) string {
+	return job.BackendUUID
+}
+
+// acquireBackend reserves a place for a job on its backend, if the backend is known.  It returns false if there
+// are already as many jobs in flight there as are allowed.  The caller must hold the lock.
+func (q *jobQueue) acquireBackend(job *storage.Job) bool {
+	key := jobAdmissionKey(job)
+	if key == "" {
+		return true
+	}
+	return q.admitOnBackend(job, key)
+}
+
+// admitOnBackend reserves a place for a job on a backend, giving up any place it held on another backend.  It
+// returns false if there are already as many jobs in flight on the backend as are allowed.  The caller must hold
+// the lock.
+func (q *jobQueue) admitOnBackend(job *storage.Job, backendUUID string) bool {
+	if q.admissionKeys[job.ID] == backendUUID {
+		return true
+	}
+	if q.backendConcurrency > 0 && len(q.backendJobs[backendUUID]) >= q.backendConcurrency {
+		return false
+	}
+	job.BackendUUID = backendUUID
+	q.holdBackend(job)
+	return true
+}
+
+// holdBackend reserves a place for a job on its backend regardless of the limit, as is needed once a backend
+// has been asked to create the job's volume.  A place held on another backend is given up.  The caller must
+// hold the lock.
+func (q *jobQueue) holdBackend(job *storage.Job) {
+	key := jobAdmissionKey(job)
+	if heldKey, ok := q.admissionKeys[job.ID]; ok && heldKey != key {
+		q.releaseBackend(job)
+	}
+	if key == "" {
+		return
+	}
+	if q.backendJobs[key] == nil {
+		q.backendJobs[key] = make(map[string]bool)
+	}
+	q.backendJobs[key][job.ID] = true
+	q.admissionKeys[job.ID] = key
+}
+
+// releaseBackend gives up a job's place on its backend.  The caller must hold the lock.
+func (q *jobQueue) releaseBackend(job *storage.Job) {
+	key, ok := q.admissionKeys[job.ID]
+	if !ok {
+		return
+	}
+	delete(q.admissionKeys, job.ID)
+	if inFlight, ok := q.backendJobs[key]; ok {
+		delete(inFlight, job.ID)
+		if len(inFlight) == 0 {
+			delete(q.backendJobs, key)
+		}
+	}
+}
+
+// jobAdmissionContextKey is the context key under which a running job passes the function that admits it to a
+// backend (see admitJobOnBackend).
+type jobAdmissionContextKey struct{}
+
+// withJobAdmission returns a context that carries the function that admits a running job to a backend.
+func withJobAdmission(ctx context.Context, admit func(backendUUID string) bool) context.Context {
+	return context.WithValue(ctx, jobAdmissionContextKey{}, admit)
+}
+
+// admitJobOnBackend reports whether a volume may be created on a backend.  A volume created by a job may only
+// be created on a backend that does not already have as many jobs in flight as are allowed, and the job then
+// holds a place there.  Any other volume may be created on any backend.
+func admitJobOnBackend(ctx context.Context, backendUUID string) bool {
+	if admit, ok := ctx.Value(jobAdmissionContextKey{}).(func(string) bool); ok {
+		return admit(backendUUID)
+	}
+	return true
+}
+
+// jobBackendsBusyError is returned when a job's volume could not be created because every backend that might
+// have taken it already has as many jobs in flight as are allowed.  The job is tried again later.
+type jobBackendsBusyError struct {
+	message string
+}
+
+func (e *jobBackendsBusyError) Error() string { return e.message }
+
+func isJobBackendsBusyError(err error) bool {
+	var busyErr *jobBackendsBusyError
+	return errors.As(err, &busyErr)
+}
+
+// update records that a job's state has changed, and returns a copy of the job to be saved with persistJob
+// once the lock is released.  The caller must hold the lock.
+func (q *jobQueue) update(job *storage.Job) *storage.Job {
+	job.UpdatedTime = time.Now()
+	return job.ConstructClone()
+}
+
+// SetJobLimits sets the number of jobs that may run at once, and the number that may be in flight on any one
+// backend, where 0 removes the limit.  It must be called before the orchestrator is bootstrapped.
+func (o *TridentOrchestrator) SetJobLimits(workers, backendConcurrency int) error {
+	if workers < 1 {
+		return fmt.Errorf("invalid number of volume job workers %d", workers)
+	}
+	if backendConcurrency < 0 {
+		return fmt.Errorf("invalid volume job backend concurrency %d", backendConcurrency)
+	}
+
+	o.jobs.mutex.Lock()
+	defer o.jobs.mutex.Unlock()
+
+	if o.jobs.started {
+		return errors.New("volume job workers are already running")
+	}
+	o.jobs.workers = workers
+	o.jobs.backendConcurrency = backendConcurrency
+	return nil
+}
+
+// bootstrapJobs reads jobs from the persistent store.  Jobs that had not finished are queued again once the
+// workers are started, and will resume any volume creation that was in progress on a backend.
+func (o *TridentOrchestrator) bootstrapJobs(ctx context.Context) error {
+	jobs, err := o.storeClient.GetJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	o.jobs.mutex.Lock()
+	defer o.jobs.mutex.Unlock()
+
+	for _, job := range jobs {
+		if job.State == storage.JobRunning {
+			job.State = storage.JobQueued
+		}
+		o.jobs.add(job)
+
+		Logc(ctx).WithFields(log.Fields{
+			"job":    job.ID,
+			"op":     job.Op,
+			"volume": job.VolumeName(),
+			"state":  job.State,
+		}).Debug("Added an existing job.")
+	}
+	return nil
+}
+
+// startJobWorkers starts the workers that run jobs, and queues any unfinished jobs read during bootstrap.
+func (o *TridentOrchestrator) startJobWorkers(ctx context.Context) {
+	q := o.jobs
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.started || q.stopped {
+		return
+	}
+	q.started = true
+
+	for i := 0; i < q.workers; i++ {
+		go o.jobWorker()
+	}
+	go o.jobReaper()
+
+	for _, job := range q.jobs {
+		if !job.Done() {
+			q.enqueue(job.ID)
+		}
+	}
+
+	Logc(ctx).WithField("workers", q.workers).Debug("Started job workers.")
+}
+
+// stopJobWorkers stops the job workers.  Jobs that are running are not interrupted, but no more are started.
+func (o *TridentOrchestrator) stopJobWorkers() {
+	q := o.jobs
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !q.stopped {
+		close(q.stop)
+		q.stopped = true
+	}
+}
+
+func (o *TridentOrchestrator) jobWorker() {
+	for {
+		select {
+		case <-o.jobs.stop:
+			return
+		case jobID := <-o.jobs.pending:
+			ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
+			o.runJob(ctx, jobID)
+		}
+	}
+}
+
+func (o *TridentOrchestrator) jobReaper() {
+	ticker := time.NewTicker(jobReapPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-o.jobs.stop:
+			return
+		case <-ticker.C:
+			ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
+			o.reapFinishedJobs(ctx)
+		}
+	}
+}
+
+// reapFinishedJobs deletes jobs that finished longer ago than the retention period.
+func (o *TridentOrchestrator) reapFinishedJobs(ctx context.Context) {
+	q := o.jobs
+
+	q.mutex.Lock()
+	expired := make([]*storage.Job, 0)
+	for _, job := range q.jobs {
+		if job.Done() && time.Since(job.UpdatedTime) >= q.retention {
+			expired = append(expired, job.ConstructClone())
+		}
+	}
+	q.mutex.Unlock()
+
+	for _, job := range expired {
+		if err := o.storeClient.DeleteJob(ctx, job); err != nil {
+			Logc(ctx).WithField("job", job.ID).WithError(err).Warning("Could not delete finished job.")
+			continue
+		}
+		q.mutex.Lock()
+		// A finished job is never run again, so it is the same job that was deleted
+		if current, ok := q.jobs[job.ID]; ok && current.Done() {
+			q.remove(current)
+		}
+		q.mutex.Unlock()
+	}
+}
+
+// persistJob saves a job's state, from a copy returned by jobQueue.update.  A job whose state cannot be saved
+// carries on, as its outcome is still reported by this process and the operation it runs is itself recorded
+// in a volume transaction.
+func (o *TridentOrchestrator) persistJob(ctx context.Context, job *storage.Job) {
+	if err := o.storeClient.UpdateJob(ctx, job); err != nil {
+		Logc(ctx).WithField("job", job.ID).WithError(err).Warning("Could not save job state.")
+	}
+}
+
+// runJob makes one attempt at a job.  A job whose backend already has too many jobs in flight, or whose
+// backend is still creating its volume, is queued again to be tried later.  So is a new volume's job when all
+// the backends it might be created on are busy.
+func (o *TridentOrchestrator) runJob(ctx context.Context, jobID string) {
+	q := o.jobs
+
+	q.mutex.Lock()
+	job, ok := q.jobs[jobID]
+	if !ok || job.Done() {
+		q.mutex.Unlock()
+		return
+	}
+	if !q.acquireBackend(job) {
+		q.mutex.Unlock()
+		Logc(ctx).WithFields(log.Fields{
+			"job":     jobID,
+			"backend": job.BackendUUID,
+		}).Debug("Backend is busy, job will be tried again.")
+		q.requeue(jobID)
+		return
+	}
+	job.State = storage.JobRunning
+	job.Attempts++
+	if job.Progress < jobProgressStarted {
+		job.Progress = jobProgressStarted
+	}
+	attempt := q.update(job)
+	q.mutex.Unlock()
+	o.persistJob(ctx, attempt)
+
+	logFields := log.Fields{"job": jobID, "op": attempt.Op, "volume": attempt.VolumeName(), "attempt": attempt.Attempts}
+	Logc(ctx).WithFields(logFields).Debug("Running job.")
+
+	var volume *storage.VolumeExternal
+	var err error
+	if attempt.Attempts > 1 {
+		// A previous attempt may have created the volume just before Trident restarted
+		volume, _ = o.GetVolume(ctx, attempt.VolumeName())
+	}
+	if volume == nil {
+		switch attempt.Op {
+		case storage.JobCloneVolume:
+			volume, err = o.CloneVolume(ctx, attempt.Config.ConstructClone())
+		default:
+			addCtx := withJobAdmission(ctx, func(backendUUID string) bool {
+				q.mutex.Lock()
+				defer q.mutex.Unlock()
+				job, ok := q.jobs[jobID]
+				return !ok || q.admitOnBackend(job, backendUUID)
+			})
+			volume, err = o.AddVolume(addCtx, attempt.Config.ConstructClone())
+		}
+	}
+
+	if isJobBackendsBusyError(err) {
+		var updated *storage.Job
+		q.mutex.Lock()
+		if job, ok = q.jobs[jobID]; ok {
+			q.releaseBackend(job)
+			job.BackendUUID = ""
+			job.State = storage.JobQueued
+			updated = q.update(job)
+		}
+		q.mutex.Unlock()
+		if updated != nil {
+			o.persistJob(ctx, updated)
+		}
+
+		Logc(ctx).WithFields(logFields).Debug("All backends are busy, job will be tried again.")
+		q.requeue(jobID)
+		return
+	}
+
+	if utils.IsVolumeCreatingError(err) {
+		backendUUID := o.getVolumeCreatingBackendUUID(ctx, attempt.Config)
+
+		var updated *storage.Job
+		q.mutex.Lock()
+		if job, ok = q.jobs[jobID]; ok {
+			if backendUUID != "" {
+				job.BackendUUID = backendUUID
+			}
+			q.holdBackend(job)
+			job.Progress = jobProgressWaiting
+			job.Message = err.Error()
+			updated = q.update(job)
+		}
+		q.mutex.Unlock()
+		if updated != nil {
+			o.persistJob(ctx, updated)
+		}
+
+		Logc(ctx).WithFields(logFields).WithError(err).Debug("Volume is still being created, job will be tried again.")
+		q.requeue(jobID)
+		return
+	}
+
+	o.finishJob(ctx, jobID, volume, err)
+	if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Job failed.")
+	} else {
+		Logc(ctx).WithFields(logFields).Info("Job succeeded.")
+	}
+}
+
+// getVolumeCreatingBackendUUID returns the backend on which a volume is being created, if it is known.
+func (o *TridentOrchestrator) getVolumeCreatingBackendUUID(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	txn, err := o.GetVolumeCreatingTransaction(ctx, volConfig)
+	if err != nil || txn == nil {
+		return ""
+	}
+	return txn.VolumeCreatingConfig.BackendUUID
+}
+
+// finishJob records the outcome of a job and wakes anyone waiting for it.
+func (o *TridentOrchestrator) finishJob(
+	ctx context.Context, jobID string, volume *storage.VolumeExternal, jobErr error,
+) {
+	q := o.jobs
+
+	q.mutex.Lock()
+	job, ok := q.jobs[jobID]
+	if !ok {
+		q.mutex.Unlock()
+		return
+	}
+
+	q.releaseBackend(job)
+	if jobErr != nil {
+		job.State = storage.JobFailed
+		job.Message = jobErr.Error()
+		q.errors[jobID] = jobErr
+	} else {
+		job.State = storage.JobSucceeded
+		job.Progress = jobProgressFinished
+		job.Message = ""
+		if volume != nil {
+			job.BackendUUID = volume.BackendUUID
+		}
+	}
+	updated := q.update(job)
+	q.mutex.Unlock()
+
+	o.persistJob(ctx, updated)
+
+	// Waiters are woken once the outcome is saved, so that it is not lost to a restart after being reported
+	q.mutex.Lock()
+	if done, ok := q.done[jobID]; ok {
+		close(done)
+	}
+	q.mutex.Unlock()
+}
+
+// SubmitVolumeJob starts a job that creates or clones a volume.  If a job for the same volume is already
+// queued or running, or has created the volume, that job is returned instead, so that callers retrying a
+// request follow the job they started originally.  A new job replaces one that failed.
+func (o *TridentOrchestrator) SubmitVolumeJob(
+	ctx context.Context, volumeConfig *storage.VolumeConfig,
+) (externalJob *storage.JobExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("job_submit", &err)()
+
+	op := storage.JobAddVolume
+	if volumeConfig.CloneSourceVolume != "" {
+		op = storage.JobCloneVolume
+	}
+
+	o.mutex.RLock()
+	_, volumeExists := o.volumes[volumeConfig.Name]
+	backendUUID := ""
+	if sourceVolume, ok := o.volumes[volumeConfig.CloneSourceVolume]; ok && op == storage.JobCloneVolume {
+		backendUUID = sourceVolume.BackendUUID
+	}
+	o.mutex.RUnlock()
+
+	q := o.jobs
+	q.mutex.Lock()
+
+	if job := q.volumeJob(volumeConfig.Name); job != nil &&
+		(!job.Done() || (job.State == storage.JobSucceeded && volumeExists)) {
+		defer q.mutex.Unlock()
+
+		if job.Op != op || job.Config.CloneSourceVolume != volumeConfig.CloneSourceVolume ||
+			job.Config.CloneSourceSnapshot != volumeConfig.CloneSourceSnapshot {
+			return nil, utils.FoundError(fmt.Sprintf("volume %s is already being created by job %s from a "+
+				"different source", volumeConfig.Name, job.ID))
+		}
+		return job.ConstructExternal(), nil
+	}
+
+	if volumeExists {
+		q.mutex.Unlock()
+		return nil, utils.FoundError(fmt.Sprintf("volume %s already exists", volumeConfig.Name))
+	}
+
+	now := time.Now()
+	job := &storage.Job{
+		ID:          uuid.NewString(),
+		Op:          op,
+		Config:      volumeConfig.ConstructClone(),
+		BackendUUID: backendUUID,
+		State:       storage.JobQueued,
+		CreatedTime: now,
+		UpdatedTime: now,
+	}
+	q.add(job)
+	added := job.ConstructClone()
+	q.mutex.Unlock()
+
+	// The job is not queued until it is saved, so that no worker saves it first
+	if err = o.storeClient.AddJob(ctx, added); err != nil {
+		q.mutex.Lock()
+		q.remove(job)
+		q.mutex.Unlock()
+		return nil, err
+	}
+	q.enqueue(job.ID)
+
+	Logc(ctx).WithFields(log.Fields{
+		"job":    job.ID,
+		"op":     job.Op,
+		"volume": job.VolumeName(),
+	}).Debug("Submitted job.")
+
+	return added.ConstructExternal(), nil
+}
+
+// WaitForJob waits up to the specified time for a job to finish, and returns the job's state.  If the job
+// failed, its error is returned as well.
+func (o *TridentOrchestrator) WaitForJob(
+	ctx context.Context, jobID string, timeout time.Duration,
+) (*storage.JobExternal, error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	q := o.jobs
+
+	q.mutex.Lock()
+	done, ok := q.done[jobID]
+	q.mutex.Unlock()
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("job %s not found", jobID))
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	job, ok := q.jobs[jobID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("job %s not found", jobID))
+	}
+	if job.State == storage.JobFailed {
+		jobErr, ok := q.errors[jobID]
+		if !ok {
+			jobErr = errors.New(job.Message)
+		}
+		return job.ConstructExternal(), jobErr
+	}
+	return job.ConstructExternal(), nil
+}
+
+func (o *TridentOrchestrator) GetJob(ctx context.Context, jobID string) (externalJob *storage.JobExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("job_get", &err)()
+
+	o.jobs.mutex.Lock()
+	defer o.jobs.mutex.Unlock()
+
+	job, ok := o.jobs.jobs[jobID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("job %s not found", jobID))
+	}
+	return job.ConstructExternal(), nil
+}
+
+// ListJobs returns all jobs, oldest first.
+func (o *TridentOrchestrator) ListJobs(ctx context.Context) (externalJobs []*storage.JobExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("job_list", &err)()
+
+	o.jobs.mutex.Lock()
+	defer o.jobs.mutex.Unlock()
+
+	jobs := make([]*storage.Job, 0, len(o.jobs.jobs))
+	for _, job := range o.jobs.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedTime.Before(jobs[j].CreatedTime)
+	})
+
+	externalJobs = make([]*storage.JobExternal, 0, len(jobs))
+	for _, job := range jobs {
+		externalJobs = append(externalJobs, job.ConstructExternal())
+	}
+	return externalJobs, nil
+}
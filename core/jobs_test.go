@@ -0,0 +1,285 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/storage"
+	sa "github.com/netapp/trident/storage_attribute"
+	"github.com/netapp/trident/utils"
+)
+
+// creatingBackend reports that a volume is still being created for a number of attempts before creating it,
+// as a backend with asynchronous volume creation would.
+type creatingBackend struct {
+	*slowBackend
+	creating int32
+}
+
+func (b *creatingBackend) AddVolume(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool storage.Pool,
+	volAttributes map[string]sa.Request, retry bool,
+) (*storage.Volume, error) {
+	if atomic.AddInt32(&b.creating, -1) >= 0 {
+		return nil, utils.VolumeCreatingError("volume is still being created")
+	}
+	return b.slowBackend.AddVolume(ctx, volConfig, storagePool, volAttributes, retry)
+}
+
+func addCreatingBackend(t *testing.T, o *TridentOrchestrator, name string, creating int32) *creatingBackend {
+	slow := addSlowBackend(t, o, name, 0)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	backend := &creatingBackend{slowBackend: slow, creating: creating}
+	for _, pool := range backend.Storage() {
+		pool.SetBackend(backend)
+	}
+	o.backends[backend.BackendUUID()] = backend
+	return backend
+}
+
+func TestSubmitVolumeJob(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	addSlowBackend(t, o, "backend", 0)
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+
+	job, err := o.SubmitVolumeJob(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, storage.JobAddVolume, job.Op)
+	assert.Equal(t, "vol1", job.Volume)
+
+	job, err = o.WaitForJob(ctx(), job.ID, 10*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.JobSucceeded, job.State)
+	assert.Equal(t, jobProgressFinished, job.Progress)
+	assert.Equal(t, 1, job.Attempts)
+
+	volume, err := o.GetVolume(ctx(), "vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, volume.BackendUUID, job.BackendUUID)
+
+	// Submitting the same volume again returns the job that created it
+	again, err := o.SubmitVolumeJob(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	assert.NoError(t, err)
+	assert.Equal(t, job.ID, again.ID)
+
+	// A clone of another source conflicts with it
+	cloneConfig := getLockTestVolumeConfig("vol1", "sc")
+	cloneConfig.CloneSourceVolume = "other"
+	_, err = o.SubmitVolumeJob(ctx(), cloneConfig)
+	assert.True(t, utils.IsFoundError(err))
+
+	// The job is listed and persisted
+	jobs, err := o.ListJobs(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+	persistentJobs, err := o.storeClient.GetJobs(ctx())
+	assert.NoError(t, err)
+	if assert.Len(t, persistentJobs, 1) {
+		assert.Equal(t, storage.JobSucceeded, persistentJobs[0].State)
+	}
+
+	_, err = o.GetJob(ctx(), "missing")
+	assert.True(t, utils.IsNotFoundError(err))
+}
+
+func TestSubmitVolumeJob_VolumeCreating(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+	o.jobs.retryInterval = 10 * time.Millisecond
+
+	backend := addCreatingBackend(t, o, "backend", 2)
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+
+	job, err := o.SubmitVolumeJob(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// A caller that does not wait long enough finds the job waiting for its backend
+	assert.Eventually(t, func() bool {
+		job, err = o.GetJob(ctx(), job.ID)
+		return err == nil && job.Progress == jobProgressWaiting
+	}, 10*time.Second, time.Millisecond)
+	assert.Equal(t, backend.BackendUUID(), job.BackendUUID)
+
+	// A retry of the request attaches to the running job
+	again, err := o.SubmitVolumeJob(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	assert.NoError(t, err)
+	assert.Equal(t, job.ID, again.ID)
+
+	job, err = o.WaitForJob(ctx(), job.ID, 10*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.JobSucceeded, job.State)
+	assert.Equal(t, 3, job.Attempts)
+
+	_, err = o.GetVolume(ctx(), "vol1")
+	assert.NoError(t, err)
+	assert.Empty(t, o.jobs.backendJobs, "finished job should not hold its backend")
+}
+
+func TestSubmitVolumeJob_Failed(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	cloneConfig := getLockTestVolumeConfig("clone", "sc")
+	cloneConfig.CloneSourceVolume = "missing"
+	job, err := o.SubmitVolumeJob(ctx(), cloneConfig)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, storage.JobCloneVolume, job.Op)
+
+	failed, err := o.WaitForJob(ctx(), job.ID, 10*time.Second)
+	assert.Error(t, err)
+	assert.True(t, utils.IsNotFoundError(err), "the job's original error should be returned")
+	assert.Equal(t, storage.JobFailed, failed.State)
+	assert.NotEmpty(t, failed.Message)
+
+	// A failed job is replaced when the request is retried
+	retried, err := o.SubmitVolumeJob(ctx(), cloneConfig)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, job.ID, retried.ID)
+
+	_, err = o.WaitForJob(ctx(), retried.ID, 10*time.Second)
+	assert.True(t, utils.IsNotFoundError(err))
+}
+
+func TestSubmitVolumeJob_BackendBusy(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+	o.jobs.retryInterval = 10 * time.Millisecond
+	o.jobs.backendConcurrency = 1
+
+	backend := addSlowBackend(t, o, "backend", 0)
+	backend.started = make(chan string, 10)
+	backend.release = make(chan struct{})
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+
+	job1, err := o.SubmitVolumeJob(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	job2, err := o.SubmitVolumeJob(ctx(), getLockTestVolumeConfig("vol2", "sc"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Only one job is admitted to the backend chosen for its volume; the other waits for it
+	first := <-backend.started
+	waiting := job2.ID
+	if first == "vol2" {
+		waiting = job1.ID
+	}
+	assert.Eventually(t, func() bool {
+		job, err := o.GetJob(ctx(), waiting)
+		return err == nil && job.Attempts > 0 && job.State == storage.JobQueued
+	}, 10*time.Second, time.Millisecond)
+	assert.Empty(t, backend.started, "the backend should not have been asked to create the second volume")
+
+	close(backend.release)
+	for _, jobID := range []string{job1.ID, job2.ID} {
+		job, err := o.WaitForJob(ctx(), jobID, 10*time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, storage.JobSucceeded, job.State)
+		assert.Equal(t, backend.BackendUUID(), job.BackendUUID)
+	}
+}
+
+func TestBootstrapJobs_ResumesUnfinishedJobs(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	addSlowBackend(t, o, "backend", 0)
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+	o.Stop()
+
+	// A job was running when Trident stopped
+	now := time.Now()
+	err := o.storeClient.AddJob(ctx(), &storage.Job{
+		ID:          "job1",
+		Op:          storage.JobAddVolume,
+		Config:      getLockTestVolumeConfig("vol1", "sc"),
+		State:       storage.JobRunning,
+		Progress:    jobProgressStarted,
+		Attempts:    1,
+		CreatedTime: now,
+		UpdatedTime: now,
+	})
+	assert.NoError(t, err)
+
+	restarted := getOrchestrator(t, false)
+	defer restarted.Stop()
+
+	job, err := restarted.WaitForJob(ctx(), "job1", 10*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.JobSucceeded, job.State)
+	assert.Equal(t, 2, job.Attempts)
+	_, err = restarted.GetVolume(ctx(), "vol1")
+	assert.NoError(t, err)
+}
+
+func TestJobQueue_BackendConcurrency(t *testing.T) {
+	q := newJobQueue()
+	q.backendConcurrency = 2
+
+	job1 := &storage.Job{ID: "job1", BackendUUID: "backend1"}
+	job2 := &storage.Job{ID: "job2", BackendUUID: "backend1"}
+	job3 := &storage.Job{ID: "job3", BackendUUID: "backend1"}
+	job4 := &storage.Job{ID: "job4", BackendUUID: "backend2"}
+	unplaced1 := &storage.Job{ID: "job5", Config: &storage.VolumeConfig{StorageClass: "sc"}}
+	unplaced2 := &storage.Job{ID: "job6", Config: &storage.VolumeConfig{StorageClass: "sc"}}
+
+	assert.True(t, q.acquireBackend(job1))
+	assert.True(t, q.acquireBackend(job2))
+	assert.True(t, q.acquireBackend(job1), "a job may run again on a backend it holds")
+	assert.False(t, q.acquireBackend(job3), "the backend is full")
+	assert.True(t, q.acquireBackend(job4), "other backends are unaffected")
+	assert.True(t, q.acquireBackend(unplaced1), "jobs without a backend wait for one to be chosen")
+	assert.Empty(t, q.admissionKeys[unplaced1.ID])
+
+	q.releaseBackend(job1)
+	assert.True(t, q.acquireBackend(job3))
+
+	// A job already running on a full backend is still counted
+	q.holdBackend(&storage.Job{ID: "job8", BackendUUID: "backend1"})
+	assert.Len(t, q.backendJobs["backend1"], 3)
+
+	// A new volume's job is admitted only to a backend with room, and moves as other backends are tried
+	assert.False(t, q.admitOnBackend(unplaced1, "backend1"))
+	assert.Empty(t, unplaced1.BackendUUID)
+	assert.True(t, q.admitOnBackend(unplaced1, "backend2"))
+	assert.Equal(t, "backend2", unplaced1.BackendUUID)
+	assert.False(t, q.admitOnBackend(unplaced2, "backend2"), "the backend is full")
+	assert.True(t, q.admitOnBackend(unplaced1, "backend3"))
+	assert.Len(t, q.backendJobs["backend2"], 1)
+	assert.True(t, q.admitOnBackend(unplaced2, "backend2"))
+
+	// Without a limit, any number of jobs are admitted
+	q.backendConcurrency = 0
+	assert.True(t, q.admitOnBackend(&storage.Job{ID: "job9"}, "backend1"))
+	assert.Len(t, q.backendJobs["backend1"], 4)
+}
+
+func TestSetJobLimits(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	assert.Error(t, o.SetJobLimits(0, 1), "there must be a worker")
+	assert.Error(t, o.SetJobLimits(1, -1))
+	assert.Error(t, o.SetJobLimits(1, 0), "the workers are already running")
+}
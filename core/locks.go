@@ -0,0 +1,142 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// The orchestrator uses three kinds of locks, which must always be acquired in this order:
+//
+//  1. Volume locks, keyed by volume name, serialize all operations that change a volume, its snapshots or its
+//     publications with any other such operation on a volume of the same name.  They are held for the entire
+//     operation, including any driver calls it makes.
+//  2. Backend locks, keyed by backend UUID, serialize operations that update, fail or delete the backend itself.
+//     Driver calls on behalf of a volume take the backend lock only long enough to check that the backend was
+//     not replaced and to register themselves (see callBackend), so driver calls on one backend run in parallel,
+//     while lockBackend waits for the calls registered on a backend to finish before the backend is changed.
+//  3. The global lock (o.mutex) protects the orchestrator's in-memory maps.  Read-only operations take it for
+//     reading; anything that modifies core state takes it for writing.
+//
+// A goroutine holding the global lock must never wait for a backend lock, since the backend lock may be held
+// until the driver calls on the backend finish.  Code that finds it needs a backend lock while holding the
+// global lock uses lockBackendHoldingGlobalLock.  Each backend's volume cache has a lock of its own, so volumes
+// are cached and uncached with CacheVolume and RemoveCachedVolume rather than by writing to the map returned
+// by Volumes.
+
+const (
+	volumeLockPrefix  = "core-volume-"
+	backendLockPrefix = "core-backend-"
+)
+
+// backendCalls tracks the driver calls in progress on each backend, keyed by backend UUID.  Calls are only
+// registered while holding the backend lock, so lockBackend may wait for them without racing new calls.
+var backendCalls sync.Map
+
+// backendCallGroup returns the wait group that tracks the driver calls in progress on the specified backend.
+func backendCallGroup(backendUUID string) *sync.WaitGroup {
+	calls, _ := backendCalls.LoadOrStore(backendUUID, &sync.WaitGroup{})
+	return calls.(*sync.WaitGroup)
+}
+
+// lockVolumes acquires the volume locks for the specified volume names.  The locks are always taken
+// in sorted order, so that operations involving more than one volume, such as a clone, cannot deadlock.
+func lockVolumes(ctx context.Context, lockContext string, volumeNames ...string) {
+	for _, name := range sortedVolumeLockNames(volumeNames) {
+		utils.Lock(ctx, lockContext, volumeLockPrefix+name)
+	}
+}
+
+// unlockVolumes releases the volume locks acquired by lockVolumes.
+func unlockVolumes(ctx context.Context, lockContext string, volumeNames ...string) {
+	names := sortedVolumeLockNames(volumeNames)
+	for i := len(names) - 1; i >= 0; i-- {
+		utils.Unlock(ctx, lockContext, volumeLockPrefix+names[i])
+	}
+}
+
+// sortedVolumeLockNames returns the distinct, non-empty volume names in sorted order
+func sortedVolumeLockNames(volumeNames []string) []string {
+	names := make([]string, 0, len(volumeNames))
+	for _, name := range volumeNames {
+		if name != "" && !utils.SliceContainsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lockBackend acquires the lock for the specified backend, then waits for the driver calls in progress on the
+// backend to finish, so that the caller may change or terminate the backend.
+func lockBackend(ctx context.Context, lockContext, backendUUID string) {
+	utils.Lock(ctx, lockContext, backendLockPrefix+backendUUID)
+	backendCallGroup(backendUUID).Wait()
+}
+
+// unlockBackend releases the lock for the specified backend.
+func unlockBackend(ctx context.Context, lockContext, backendUUID string) {
+	utils.Unlock(ctx, lockContext, backendLockPrefix+backendUUID)
+}
+
+// lockBackendHoldingGlobalLock acquires the lock for the specified backend on behalf of a caller that holds the
+// global lock for writing.  To keep to the lock order above, the global lock is released while waiting for the
+// backend lock and is acquired again once the backend lock is held, so the caller must look up again any core
+// state it read beforehand, including the backend itself, which may have been updated or deleted meanwhile.
+func (o *TridentOrchestrator) lockBackendHoldingGlobalLock(ctx context.Context, lockContext, backendUUID string) {
+	o.mutex.Unlock()
+	lockBackend(ctx, lockContext, backendUUID)
+	o.mutex.Lock()
+}
+
+// callBackend runs a driver call on behalf of a volume, such as a create, clone, resize, publish or snapshot,
+// without holding the global lock, so that the call does not block operations on other volumes or backends.
+// The backend lock is held only while checking that the backend is still the one in core state and registering
+// the call, so that the backend is not updated, failed or deleted until the call finishes.
+//
+// The caller must hold the global lock for writing and the volume lock for each volume the call changes, and must
+// have recorded any operation that changes storage in a volume transaction, so that it can be recovered if Trident
+// restarts mid-call.  The call must not touch the orchestrator's in-memory state, and the caller must not rely on
+// any such state read before the call without looking it up again afterwards.  If the backend was updated or
+// deleted meanwhile, the call is not made and an error is returned; otherwise the call's error is returned.
+func (o *TridentOrchestrator) callBackend(
+	ctx context.Context, lockContext string, backend storage.Backend, call func() error,
+) error {
+	backendUUID := backend.BackendUUID()
+
+	o.mutex.Unlock()
+	defer o.mutex.Lock()
+
+	utils.Lock(ctx, lockContext, backendLockPrefix+backendUUID)
+	o.mutex.RLock()
+	currentBackend, ok := o.backends[backendUUID]
+	o.mutex.RUnlock()
+	if !ok || currentBackend != backend {
+		utils.Unlock(ctx, lockContext, backendLockPrefix+backendUUID)
+		return fmt.Errorf("backend %s was modified or removed during the operation", backend.Name())
+	}
+	calls := backendCallGroup(backendUUID)
+	calls.Add(1)
+	utils.Unlock(ctx, lockContext, backendLockPrefix+backendUUID)
+	defer calls.Done()
+
+	return call()
+}
+
+// volumeLockNames returns the names of the volume locks to take for an operation on the specified volume, which
+// for a subordinate volume include the lock for its source volume, whose config and access rules it shares.
+func (o *TridentOrchestrator) volumeLockNames(volumeName string) []string {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	if volume, ok := o.subordinateVolumes[volumeName]; ok {
+		return []string{volumeName, volume.Config.ShareSourceVolume}
+	}
+	return []string{volumeName}
+}
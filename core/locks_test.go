@@ -0,0 +1,408 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/fake"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	"github.com/netapp/trident/utils"
+)
+
+// slowBackend delays volume creation on a fake backend, as a storage system's API would.  If started and
+// release are set, each create or resize announces itself on started and then waits for release to be closed.
+type slowBackend struct {
+	*storage.StorageBackend
+	latency time.Duration
+	started chan string
+	release chan struct{}
+}
+
+func (b *slowBackend) AddVolume(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool storage.Pool,
+	volAttributes map[string]sa.Request, retry bool,
+) (*storage.Volume, error) {
+	if b.started != nil {
+		b.started <- volConfig.Name
+		<-b.release
+	}
+	time.Sleep(b.latency)
+	return b.StorageBackend.AddVolume(ctx, volConfig, storagePool, volAttributes, retry)
+}
+
+func (b *slowBackend) ResizeVolume(ctx context.Context, volConfig *storage.VolumeConfig, newSize string) error {
+	if b.started != nil {
+		b.started <- volConfig.Name
+		<-b.release
+	}
+	return b.StorageBackend.ResizeVolume(ctx, volConfig, newSize)
+}
+
+func addSlowBackend(t testing.TB, o *TridentOrchestrator, name string, latency time.Duration) *slowBackend {
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+		name,
+		config.File,
+		map[string]*fake.StoragePool{
+			"primary": {
+				Attrs: map[string]sa.Offer{
+					sa.TestingAttribute: sa.NewBoolOffer(true),
+				},
+				Bytes: 1024 * 1024 * 1024 * 1024 * 1024,
+			},
+		},
+		[]fake.Volume{},
+	)
+	if err != nil {
+		t.Fatal("Unable to create mock driver config JSON: ", err)
+	}
+
+	backendExternal, err := o.AddBackend(ctx(), configJSON, "")
+	if err != nil {
+		t.Fatalf("Unable to add backend: %v", err)
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	backend := &slowBackend{
+		StorageBackend: o.backends[backendExternal.BackendUUID].(*storage.StorageBackend),
+		latency:        latency,
+	}
+	for _, pool := range backend.Storage() {
+		pool.SetBackend(backend)
+	}
+	o.backends[backendExternal.BackendUUID] = backend
+	return backend
+}
+
+func addBackendOnlyStorageClass(t testing.TB, o *TridentOrchestrator, name, backendName string) {
+	_, err := o.AddStorageClass(ctx(), &storageclass.Config{
+		Name:  name,
+		Pools: map[string][]string{backendName: {"primary"}},
+	})
+	if err != nil {
+		t.Fatal("Unable to add storage class: ", err)
+	}
+}
+
+func getLockTestVolumeConfig(name, storageClass string) *storage.VolumeConfig {
+	return &storage.VolumeConfig{
+		Name:         name,
+		Size:         "1GiB",
+		Protocol:     config.File,
+		VolumeMode:   config.Filesystem,
+		AccessMode:   config.ReadWriteOnce,
+		StorageClass: storageClass,
+	}
+}
+
+func TestAddVolume_ProvisioningDoesNotBlockOtherOperations(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	blocked := addSlowBackend(t, o, "blocked", 0)
+	blocked.started = make(chan string, 1)
+	blocked.release = make(chan struct{})
+	addSlowBackend(t, o, "unblocked", 0)
+	addBackendOnlyStorageClass(t, o, "blocked-sc", "blocked")
+	addBackendOnlyStorageClass(t, o, "unblocked-sc", "unblocked")
+
+	_, err := o.AddVolume(ctx(), getLockTestVolumeConfig("existing", "unblocked-sc"))
+	assert.NoError(t, err)
+
+	// Start a create that stalls in the driver
+	errs := make(chan error, 2)
+	go func() {
+		_, err := o.AddVolume(ctx(), getLockTestVolumeConfig("vol1", "blocked-sc"))
+		errs <- err
+	}()
+	assert.Equal(t, "vol1", <-blocked.started)
+
+	// Existing volumes may be read, and volumes may be created on other backends
+	volume, err := o.GetVolume(ctx(), "existing")
+	assert.NoError(t, err)
+	assert.Equal(t, "existing", volume.Config.Name)
+	volumes, err := o.ListVolumes(ctx())
+	assert.NoError(t, err)
+	assert.Len(t, volumes, 1)
+	_, err = o.AddVolume(ctx(), getLockTestVolumeConfig("vol2", "unblocked-sc"))
+	assert.NoError(t, err)
+
+	// The in-flight create is recorded in a transaction, but the volume is not yet known
+	txn, err := o.GetVolumeTransaction(ctx(), &storage.VolumeTransaction{
+		Config: &storage.VolumeConfig{Name: "vol1"},
+		Op:     storage.AddVolume,
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, txn)
+	_, err = o.GetVolume(ctx(), "vol1")
+	assert.True(t, utils.IsNotFoundError(err))
+
+	// A second create of the same volume waits for the first, then fails
+	go func() {
+		_, err := o.AddVolume(ctx(), getLockTestVolumeConfig("vol1", "blocked-sc"))
+		errs <- err
+	}()
+
+	close(blocked.release)
+	assert.NoError(t, <-errs)
+	err = <-errs
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "already exists")
+	}
+
+	volume, err = o.GetVolume(ctx(), "vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, blocked.BackendUUID(), volume.BackendUUID)
+	txn, err = o.GetVolumeTransaction(ctx(), &storage.VolumeTransaction{
+		Config: &storage.VolumeConfig{Name: "vol1"},
+		Op:     storage.AddVolume,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, txn)
+}
+
+func TestAddVolume_BackendUpdatedDuringProvisioning(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	blocked := addSlowBackend(t, o, "blocked", 0)
+	blocked.started = make(chan string, 1)
+	blocked.release = make(chan struct{})
+	addBackendOnlyStorageClass(t, o, "blocked-sc", "blocked")
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := o.AddVolume(ctx(), getLockTestVolumeConfig("vol1", "blocked-sc"))
+		errs <- err
+	}()
+	<-blocked.started
+
+	// The update waits for the in-flight create to finish
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSON(
+		"blocked",
+		config.File,
+		map[string]*fake.StoragePool{
+			"primary": {
+				Attrs: map[string]sa.Offer{sa.TestingAttribute: sa.NewBoolOffer(true)},
+				Bytes: 1024 * 1024 * 1024 * 1024 * 1024,
+			},
+		},
+		[]fake.Volume{},
+	)
+	assert.NoError(t, err)
+	updated := make(chan error, 1)
+	go func() {
+		_, err := o.UpdateBackend(ctx(), "blocked", configJSON, "")
+		updated <- err
+	}()
+
+	select {
+	case err = <-updated:
+		t.Fatalf("Backend update did not wait for provisioning; %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The waiting update does not hold the global lock
+	_, err = o.ListVolumes(ctx())
+	assert.NoError(t, err)
+	_, err = o.GetBackend(ctx(), "blocked")
+	assert.NoError(t, err)
+
+	close(blocked.release)
+	assert.NoError(t, <-errs)
+	assert.NoError(t, <-updated)
+
+	// The new volume is known to the updated backend
+	volume, err := o.GetVolume(ctx(), "vol1")
+	assert.NoError(t, err)
+	assert.False(t, volume.Orphaned)
+	backend, err := o.GetBackendByBackendUUID(ctx(), volume.BackendUUID)
+	assert.NoError(t, err)
+	assert.Contains(t, backend.Volumes, "vol1")
+}
+
+func TestAddVolume_CreatesOnOneBackendRunInParallel(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	blocked := addSlowBackend(t, o, "blocked", 0)
+	blocked.started = make(chan string, 2)
+	blocked.release = make(chan struct{})
+	addBackendOnlyStorageClass(t, o, "blocked-sc", "blocked")
+
+	errs := make(chan error, 2)
+	for _, name := range []string{"vol1", "vol2"} {
+		go func(name string) {
+			_, err := o.AddVolume(ctx(), getLockTestVolumeConfig(name, "blocked-sc"))
+			errs <- err
+		}(name)
+	}
+
+	// Both creates reach the driver before either finishes
+	started := []string{<-blocked.started, <-blocked.started}
+	assert.ElementsMatch(t, []string{"vol1", "vol2"}, started)
+
+	close(blocked.release)
+	assert.NoError(t, <-errs)
+	assert.NoError(t, <-errs)
+}
+
+func TestResizeVolume_DoesNotBlockOtherOperations(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	backend := addSlowBackend(t, o, "backend", 0)
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+
+	_, err := o.AddVolume(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	assert.NoError(t, err)
+
+	backend.started = make(chan string, 1)
+	backend.release = make(chan struct{})
+	resized := make(chan error, 1)
+	go func() {
+		resized <- o.ResizeVolume(ctx(), "vol1", "2GiB")
+	}()
+	assert.Equal(t, "vol1", <-backend.started)
+
+	// Other volumes may be read, created and deleted on the same backend meanwhile
+	_, err = o.GetVolume(ctx(), "vol1")
+	assert.NoError(t, err)
+	backend.started = nil
+	_, err = o.AddVolume(ctx(), getLockTestVolumeConfig("vol2", "sc"))
+	assert.NoError(t, err)
+	assert.NoError(t, o.DeleteVolume(ctx(), "vol2"))
+
+	close(backend.release)
+	assert.NoError(t, <-resized)
+
+	volume, err := o.GetVolume(ctx(), "vol1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2147483648", volume.Config.Size)
+}
+
+func TestCloneVolume_SourceCannotBeDeletedDuringProvisioning(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	addSlowBackend(t, o, "backend", 0)
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+
+	_, err := o.AddVolume(ctx(), getLockTestVolumeConfig("source", "sc"))
+	assert.NoError(t, err)
+
+	// Hold the source volume's lock as a clone would
+	lockVolumes(ctx(), "cloneVolume", "clone", "source")
+
+	deleted := make(chan error, 1)
+	go func() {
+		deleted <- o.DeleteVolume(ctx(), "source")
+	}()
+
+	select {
+	case err = <-deleted:
+		t.Fatalf("Volume delete did not wait for clone; %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// The source volume may still be read
+	_, err = o.GetVolume(ctx(), "source")
+	assert.NoError(t, err)
+
+	unlockVolumes(ctx(), "cloneVolume", "clone", "source")
+	assert.NoError(t, <-deleted)
+}
+
+func TestSortedVolumeLockNames(t *testing.T) {
+	assert.Equal(t, []string{"a", "b", "c"}, sortedVolumeLockNames([]string{"c", "", "a", "b", "a"}))
+	assert.Empty(t, sortedVolumeLockNames(nil))
+}
+
+// benchmarkBackendLatency approximates the time taken by a storage system to create a volume
+const benchmarkBackendLatency = 5 * time.Millisecond
+
+func benchmarkOrchestrator(b *testing.B, backendCount int) *TridentOrchestrator {
+	o := getOrchestrator(b, false)
+	for i := 0; i < backendCount; i++ {
+		addSlowBackend(b, o, fmt.Sprintf("backend%d", i), benchmarkBackendLatency)
+	}
+	_, err := o.AddStorageClass(ctx(), &storageclass.Config{
+		Name:       "sc",
+		Attributes: map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+	})
+	if err != nil {
+		b.Fatal("Unable to add storage class: ", err)
+	}
+	return o
+}
+
+func BenchmarkAddVolume_Parallel(b *testing.B) {
+	for _, backendCount := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("backends=%d", backendCount), func(b *testing.B) {
+			o := benchmarkOrchestrator(b, backendCount)
+			defer cleanup(b, o)
+
+			var counter int64
+			b.SetParallelism(backendCount)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					name := fmt.Sprintf("vol%d", atomic.AddInt64(&counter, 1))
+					if _, err := o.AddVolume(ctx(), getLockTestVolumeConfig(name, "sc")); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkGetVolume_ParallelWithAddVolume(b *testing.B) {
+	o := benchmarkOrchestrator(b, 1)
+	defer cleanup(b, o)
+
+	_, err := o.AddVolume(ctx(), getLockTestVolumeConfig("existing", "sc"))
+	if err != nil {
+		b.Fatal("Unable to add volume: ", err)
+	}
+
+	// Keep volumes provisioning in the background for the duration of the benchmark
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	defer func() {
+		close(done)
+		<-stopped
+	}()
+	go func() {
+		defer close(stopped)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+				_, _ = o.AddVolume(ctx(), getLockTestVolumeConfig(fmt.Sprintf("background%d", i), "sc"))
+			}
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := o.GetVolume(ctx(), "existing"); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
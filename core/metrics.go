@@ -24,6 +24,22 @@ var (
 		},
 		[]string{"backend_type", "backend_name", "backend_uuid"},
 	)
+	backendHealthyGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Name:      "backend_healthy",
+			Help:      "Whether a backend is online and responding to health checks",
+		},
+		[]string{"backend_type", "backend_name", "backend_uuid"},
+	)
+	backendHealthCheckFailuresCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.OrchestratorName,
+			Name:      "backend_health_check_failures_total",
+			Help:      "The total number of failed backend health checks",
+		},
+		[]string{"backend_type", "backend_name", "backend_uuid"},
+	)
 	backendsGauge = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: config.OrchestratorName,
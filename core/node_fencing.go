@@ -0,0 +1,222 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/netapp/trident/config"
+	controllerhelpers "github.com/netapp/trident/frontend/csi/controller_helpers"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// A failed node may still be writing to its volumes, so before its volumes are attached elsewhere the node is
+// fenced: it is left out of every backend's node access rules (igroups, export rules, VAGs), each of its volume
+// publications is force-detached, and no volume is published to it until it is unfenced.
+
+// accessibleNodes returns the nodes that may be granted access to volumes, which excludes fenced nodes.
+func (o *TridentOrchestrator) accessibleNodes() []*utils.Node {
+	nodes := make([]*utils.Node, 0, len(o.nodes))
+	for _, node := range o.nodes {
+		if !node.Fenced {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// FenceNode revokes a node's access to its volumes on every backend, then marks each of the node's volume
+// publications as not safe to attach, so the volumes may be attached to other nodes.  Fencing a node that is
+// already fenced retries any steps that failed before.
+func (o *TridentOrchestrator) FenceNode(
+	ctx context.Context, nodeName string, nodeEventCallback NodeEventCallback,
+) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("node_fence", &err)()
+
+	if config.CurrentDriverContext != config.ContextCSI {
+		return utils.UnsupportedError("node fencing is only supported with CSI")
+	}
+
+	// Once the node is fenced, no volume is published to it, so its publications can only shrink while the
+	// volume locks for them are taken
+	publishedVolumes, err := o.markNodeFenced(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	var lockNames []string
+	for _, volumeName := range publishedVolumes {
+		lockNames = append(lockNames, o.volumeLockNames(volumeName)...)
+	}
+	lockVolumes(ctx, "fenceNode", lockNames...)
+	defer unlockVolumes(ctx, "fenceNode", lockNames...)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	node, ok := o.nodes[nodeName]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("node %s not found", nodeName))
+	}
+
+	publications := o.volumePublications.ListPublicationsForNode(nodeName)
+	Logc(ctx).WithFields(log.Fields{
+		"node":         nodeName,
+		"publications": len(publications),
+	}).Warning("Fencing node.")
+
+	// Revoke the node's access on the storage before any of its volumes may be attached elsewhere.  This covers
+	// both the access rules shared by all nodes and those kept for the node alone, such as its own igroups,
+	// VAGs and volume export rules, including those of publications that were force-detached before.
+	if err = o.updateNodeAccessOnBackends(ctx, "fenceNode", node); err != nil {
+		nodeEventCallback(controllerhelpers.EventTypeWarning, "NodeFencingFailed",
+			fmt.Sprintf("Could not revoke node access to storage; %v", err))
+		return fmt.Errorf("unable to revoke access of node %s; %v", nodeName, err)
+	}
+
+	notSafeToAttach := true
+	for _, publication := range publications {
+		if publication.NotSafeToAttach {
+			continue
+		}
+		if err = o.updateVolumePublication(ctx, publication.VolumeName, nodeName, &notSafeToAttach); err != nil {
+			nodeEventCallback(controllerhelpers.EventTypeWarning, "NodeFencingFailed",
+				fmt.Sprintf("Could not detach volume %s; %v", publication.VolumeName, err))
+			return fmt.Errorf("unable to detach volume %s from node %s; %v", publication.VolumeName, nodeName,
+				err)
+		}
+		Logc(ctx).WithFields(log.Fields{
+			"node":   nodeName,
+			"volume": publication.VolumeName,
+		}).Warning("Volume detached from fenced node.")
+	}
+
+	nodeEventCallback(controllerhelpers.EventTypeWarning, "NodeFenced",
+		fmt.Sprintf("Node access to storage revoked and %d volume(s) detached.", len(publications)))
+	return nil
+}
+
+// markNodeFenced records that a node is fenced and returns the names of the volumes published to it.
+func (o *TridentOrchestrator) markNodeFenced(ctx context.Context, nodeName string) ([]string, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	node, ok := o.nodes[nodeName]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("node %s not found", nodeName))
+	}
+
+	if !node.Fenced {
+		node.Fenced = true
+		if err := o.storeClient.AddOrUpdateNode(ctx, node); err != nil {
+			node.Fenced = false
+			return nil, fmt.Errorf("unable to record that node %s is fenced; %v", nodeName, err)
+		}
+	}
+
+	publications := o.volumePublications.ListPublicationsForNode(nodeName)
+	volumeNames := make([]string, 0, len(publications))
+	for _, publication := range publications {
+		volumeNames = append(volumeNames, publication.VolumeName)
+	}
+	return volumeNames, nil
+}
+
+// updateNodeAccessOnBackends reconciles every backend's node access rules after a node was fenced or unfenced,
+// and if a node is supplied, removes it from the access rules the backend keeps for individual nodes.  The caller
+// must hold the global lock for writing, which is released while each backend's driver is called (see
+// callBackend), so the backends are called one at a time with the backends and nodes found beforehand.
+func (o *TridentOrchestrator) updateNodeAccessOnBackends(
+	ctx context.Context, lockContext string, revokedNode *utils.Node,
+) error {
+	if revokedNode != nil {
+		nodeCopy := *revokedNode
+		revokedNode = &nodeCopy
+	}
+	nodes := make([]*utils.Node, 0, len(o.nodes))
+	for _, node := range o.accessibleNodes() {
+		nodeCopy := *node
+		nodes = append(nodes, &nodeCopy)
+	}
+	backends := make([]storage.Backend, 0, len(o.backends))
+	for _, b := range o.backends {
+		b.InvalidateNodeAccess()
+		backends = append(backends, b)
+	}
+
+	errored := false
+	for _, b := range backends {
+		b := b
+		err := o.callBackend(ctx, lockContext, b, func() error {
+			if err := b.ReconcileNodeAccess(ctx, nodes); err != nil {
+				return err
+			}
+			if revokedNode == nil {
+				return nil
+			}
+			return b.RevokeNodeAccess(ctx, revokedNode, o.uuid)
+		})
+		if err != nil {
+			Logc(ctx).WithError(err).WithField("backend", b.Name()).Warn("Error updating node access.")
+			errored = true
+		}
+	}
+	if errored {
+		return fmt.Errorf("one or more errors updating node access")
+	}
+	return nil
+}
+
+// UnfenceNode restores a fenced node's access to storage.  Volume publications marked as not safe to attach
+// while the node was fenced are left alone, so they are still cleaned up from the node before they are removed.
+func (o *TridentOrchestrator) UnfenceNode(
+	ctx context.Context, nodeName string, nodeEventCallback NodeEventCallback,
+) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("node_unfence", &err)()
+
+	if config.CurrentDriverContext != config.ContextCSI {
+		return utils.UnsupportedError("node fencing is only supported with CSI")
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	node, ok := o.nodes[nodeName]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("node %s not found", nodeName))
+	}
+	if !node.Fenced {
+		return nil
+	}
+
+	Logc(ctx).WithField("node", nodeName).Warning("Unfencing node.")
+
+	node.Fenced = false
+	if err = o.storeClient.AddOrUpdateNode(ctx, node); err != nil {
+		node.Fenced = true
+		return fmt.Errorf("unable to record that node %s is unfenced; %v", nodeName, err)
+	}
+
+	if err = o.updateNodeAccessOnBackends(ctx, "unfenceNode", nil); err != nil {
+		// Node access is reconciled again before each publish, so this is not fatal
+		Logc(ctx).WithField("node", nodeName).WithError(err).Warning("Could not restore node access to storage.")
+	}
+
+	nodeEventCallback(controllerhelpers.EventTypeNormal, "NodeUnfenced", "Node access to storage restored.")
+	return nil
+}
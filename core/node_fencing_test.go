@@ -0,0 +1,96 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils"
+)
+
+func TestFenceNode(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	backend := addPublicationRecordingBackend(t, o, "backend")
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+	if _, err := o.AddVolume(ctx(), getLockTestVolumeConfig("vol1", "sc")); !assert.NoError(t, err) {
+		return
+	}
+
+	var events []string
+	nodeEventCallback := func(eventType, reason, message string) {
+		events = append(events, reason)
+	}
+
+	// Fencing is only supported with CSI
+	err := o.FenceNode(ctx(), "node1", nodeEventCallback)
+	assert.True(t, utils.IsUnsupportedError(err))
+
+	originalContext := config.CurrentDriverContext
+	config.CurrentDriverContext = config.ContextCSI
+	defer func() { config.CurrentDriverContext = originalContext }()
+
+	err = o.FenceNode(ctx(), "node1", nodeEventCallback)
+	assert.True(t, utils.IsNotFoundError(err))
+
+	node := &utils.Node{Name: "node1"}
+	if err = o.storeClient.AddOrUpdateNode(ctx(), node); !assert.NoError(t, err) {
+		return
+	}
+	o.nodes[node.Name] = node
+	publication := &utils.VolumePublication{Name: "vol1.node1", VolumeName: "vol1", NodeName: "node1"}
+	if err = o.storeClient.AddVolumePublication(ctx(), publication); !assert.NoError(t, err) {
+		return
+	}
+	if err = o.volumePublications.Set("vol1", "node1", publication); !assert.NoError(t, err) {
+		return
+	}
+
+	// The node's own access rules are removed without holding the global lock, and its publications are force
+	// detached and no longer safe to attach
+	unblocked := false
+	backend.onRevoke = func() {
+		done := make(chan struct{})
+		go func() {
+			_, _ = o.GetNode(ctx(), "node1")
+			close(done)
+		}()
+		select {
+		case <-done:
+			unblocked = true
+		case <-time.After(5 * time.Second):
+		}
+	}
+	err = o.FenceNode(ctx(), "node1", nodeEventCallback)
+	assert.NoError(t, err)
+	assert.True(t, unblocked, "other operations should not wait for node access to be revoked")
+	assert.True(t, o.nodes["node1"].Fenced)
+	assert.Equal(t, []string{"node1"}, backend.revoked)
+	assert.Equal(t, []string{"node1"}, backend.fenced)
+	fenced, found := o.volumePublications.TryGet("vol1", "node1")
+	assert.True(t, found)
+	assert.True(t, fenced.NotSafeToAttach)
+	assert.Equal(t, []string{"NodeFenced"}, events)
+
+	// Fencing is recorded in the persistent store
+	storedNode, err := o.storeClient.GetNode(ctx(), "node1")
+	assert.NoError(t, err)
+	assert.True(t, storedNode.Fenced)
+
+	// Nothing may be published to a fenced node
+	err = o.PublishVolume(ctx(), "vol1", &utils.VolumePublishInfo{HostName: "node1"})
+	assert.Error(t, err)
+
+	// Unfencing restores the node but leaves its publications to be cleaned up
+	err = o.UnfenceNode(ctx(), "node1", nodeEventCallback)
+	assert.NoError(t, err)
+	assert.False(t, o.nodes["node1"].Fenced)
+	_, found = o.volumePublications.TryGet("vol1", "node1")
+	assert.True(t, found)
+	assert.Equal(t, []string{"NodeFenced", "NodeUnfenced"}, events)
+}
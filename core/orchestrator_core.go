@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"go.uber.org/multierr"
@@ -40,6 +41,13 @@ const (
 	AttachISCSIVolumeTimeoutLong   = time.Second * 90
 )
 
+var (
+	// reservationPreemptInitialInterval and reservationPreemptMaxElapsedTime bound the retries of a node's attempts
+	// to preempt the reservation key of a node force detached from a volume
+	reservationPreemptInitialInterval = 5 * time.Second
+	reservationPreemptMaxElapsedTime  = 5 * time.Minute
+)
+
 // recordTiming is used to record in Prometheus the total time taken for an operation as follows:
 //
 //	defer recordTiming("backend_add")()
@@ -81,7 +89,7 @@ type TridentOrchestrator struct {
 	volumes                  map[string]*storage.Volume
 	subordinateVolumes       map[string]*storage.Volume
 	frontends                map[string]frontend.Plugin
-	mutex                    *sync.Mutex
+	mutex                    *sync.RWMutex
 	storageClasses           map[string]*storageclass.StorageClass
 	nodes                    map[string]*utils.Node
 	volumePublications       *cache.VolumePublicationCache
@@ -96,6 +104,8 @@ type TridentOrchestrator struct {
 	lastVolumePublication    time.Time
 	volumePublicationsSynced bool
 	stopNodeAccessLoop       chan bool
+	jobs                     *jobQueue
+	health                   *backendHealthMonitor
 	uuid                     string
 }
 
@@ -110,7 +120,9 @@ func NewTridentOrchestrator(client persistentstore.Client) *TridentOrchestrator
 		nodes:              make(map[string]*utils.Node),
 		volumePublications: cache.NewVolumePublicationCache(),
 		snapshots:          make(map[string]*storage.Snapshot), // key is ID, not name
-		mutex:              &sync.Mutex{},
+		mutex:              &sync.RWMutex{},
+		jobs:               newJobQueue(),
+		health:             newBackendHealthMonitor(),
 		storeClient:        client,
 		bootstrapped:       false,
 		bootstrapError:     utils.NotReadyError(),
@@ -183,6 +195,12 @@ func (o *TridentOrchestrator) Bootstrap(monitorTransactions bool) error {
 		o.StartTransactionMonitor(ctx, txnMonitorPeriod, txnMonitorMaxAge)
 	}
 
+	// Start running jobs, including any that were interrupted by a restart
+	o.startJobWorkers(ctx)
+
+	// Start checking that the backends are responding
+	o.startBackendHealthMonitor(ctx)
+
 	o.bootstrapped = true
 	o.bootstrapError = nil
 	log.Infof("%s bootstrapped successfully.", utils.Title(config.OrchestratorName))
@@ -211,7 +229,9 @@ func (o *TridentOrchestrator) bootstrapBackends(ctx context.Context) error {
 			return err
 		}
 
+		o.mutex.Lock()
 		newBackendExternal, backendErr := o.addBackend(ctx, serializedConfig, b.BackendUUID, b.ConfigRef)
+		o.mutex.Unlock()
 		if backendErr == nil {
 			newBackendExternal.BackendUUID = b.BackendUUID
 		} else {
@@ -256,6 +276,8 @@ func (o *TridentOrchestrator) bootstrapBackends(ctx context.Context) error {
 			newBackend.SetOnline(b.Online)
 			if backendErr != nil {
 				newBackend.SetState(storage.Failed)
+				// Keep trying to initialize the backend, in case its storage system was unreachable
+				o.health.markRecoverable(b.BackendUUID, backendErr)
 			} else {
 				if b.State == storage.Deleting {
 					newBackend.SetState(storage.Deleting)
@@ -336,7 +358,7 @@ func (o *TridentOrchestrator) bootstrapVolumes(ctx context.Context) error {
 				}).Warning("Couldn't find backend. Setting state to MissingBackend.")
 				vol.State = storage.VolumeStateMissingBackend
 			} else {
-				backend.Volumes()[vol.Config.Name] = vol
+				backend.CacheVolume(vol)
 				if fakeDriver, ok := backend.Driver().(*fake.StorageDriver); ok {
 					fakeDriver.BootstrapVolume(ctx, vol)
 				}
@@ -434,8 +456,8 @@ func (o *TridentOrchestrator) bootstrapNodes(ctx context.Context) error {
 }
 
 func (o *TridentOrchestrator) bootstrapVolumePublications(ctx context.Context) error {
-	// Don't bootstrap volume publications if we're not CSI
-	if config.CurrentDriverContext != config.ContextCSI {
+	// Only CSI and Docker track volume publications
+	if config.CurrentDriverContext != config.ContextCSI && config.CurrentDriverContext != config.ContextDocker {
 		return nil
 	}
 
@@ -496,7 +518,8 @@ func (o *TridentOrchestrator) bootstrap(ctx context.Context) error {
 	type bootstrapFunc func(context.Context) error
 	for _, f := range []bootstrapFunc{
 		o.bootstrapBackends, o.bootstrapStorageClasses, o.bootstrapVolumes, o.bootstrapSnapshots,
-		o.bootstrapVolTxns, o.bootstrapNodes, o.bootstrapVolumePublications, o.bootstrapSubordinateVolumes,
+		o.bootstrapVolTxns, o.bootstrapJobs, o.bootstrapNodes, o.bootstrapVolumePublications,
+		o.bootstrapSubordinateVolumes,
 	} {
 		err := f(ctx)
 		if err != nil {
@@ -545,6 +568,12 @@ func (o *TridentOrchestrator) Stop() {
 
 	// Stop transaction monitor
 	o.StopTransactionMonitor()
+
+	// Stop running jobs
+	o.stopJobWorkers()
+
+	// Stop checking backend health
+	o.stopBackendHealthMonitor()
 }
 
 // updateMetrics updates the metrics that track the core objects.
@@ -556,6 +585,7 @@ func (o *TridentOrchestrator) updateMetrics() {
 
 	backendsGauge.Reset()
 	tridentBackendInfo.Reset()
+	backendHealthyGauge.Reset()
 	for _, backend := range o.backends {
 		if backend == nil {
 			continue
@@ -563,6 +593,12 @@ func (o *TridentOrchestrator) updateMetrics() {
 		backendsGauge.WithLabelValues(backend.GetDriverName(), backend.State().String()).Inc()
 		tridentBackendInfo.WithLabelValues(backend.GetDriverName(), backend.Name(),
 			backend.BackendUUID()).Set(float64(1))
+		healthy := float64(0)
+		if backend.State().IsOnline() {
+			healthy = 1
+		}
+		backendHealthyGauge.WithLabelValues(backend.GetDriverName(), backend.Name(),
+			backend.BackendUUID()).Set(healthy)
 	}
 
 	volumesGauge.Reset()
@@ -659,16 +695,23 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 			// unique across backends, thanks to the StoragePrefix field,
 			// so this should be idempotent.
 			// Handles case 2)
-			for _, backend := range o.backends {
+			backendUUIDs := make([]string, 0, len(o.backends))
+			for backendUUID := range o.backends {
+				backendUUIDs = append(backendUUIDs, backendUUID)
+			}
+			for _, backendUUID := range backendUUIDs {
+				backend, ok := o.backends[backendUUID]
 				// Backend offlining is serialized with volume creation,
 				// so we can safely skip offline backends.
-				if !backend.State().IsOnline() && !backend.State().IsDeleting() {
+				if !ok || (!backend.State().IsOnline() && !backend.State().IsDeleting()) {
 					continue
 				}
 				// Volume deletion is an idempotent operation, so it's safe to
 				// delete an already deleted volume.
-
-				if err := backend.RemoveVolume(ctx, v.Config); err != nil {
+				err := o.callBackend(ctx, "handleFailedTransaction", backend, func() error {
+					return backend.RemoveVolume(ctx, v.Config)
+				})
+				if err != nil {
 					return fmt.Errorf("error attempting to clean up volume %s from backend %s: %v", v.Config.Name,
 						backend.Name(), err)
 				}
@@ -723,14 +766,21 @@ func (o *TridentOrchestrator) handleFailedTransaction(ctx context.Context, v *st
 			// We're guaranteed that the volume name will be unique across backends,
 			// thanks to the StoragePrefix field, so this should be idempotent.
 			// Handles case 2)
-			for _, backend := range o.backends {
+			backendUUIDs := make([]string, 0, len(o.backends))
+			for backendUUID := range o.backends {
+				backendUUIDs = append(backendUUIDs, backendUUID)
+			}
+			for _, backendUUID := range backendUUIDs {
 				// Skip backends that aren't ready to accept a snapshot delete operation
-				if !backend.State().IsOnline() && !backend.State().IsDeleting() {
+				backend, ok := o.backends[backendUUID]
+				if !ok || (!backend.State().IsOnline() && !backend.State().IsDeleting()) {
 					continue
 				}
 				// Snapshot deletion is an idempotent operation, so it's safe to
 				// delete an already deleted snapshot.
-				err := backend.DeleteSnapshot(ctx, v.SnapshotConfig, v.Config)
+				err := o.callBackend(ctx, "handleFailedTransaction", backend, func() error {
+					return backend.DeleteSnapshot(ctx, v.SnapshotConfig, v.Config)
+				})
 				if err != nil && !utils.IsUnsupportedError(err) {
 					return fmt.Errorf("error attempting to clean up snapshot %s from backend %s: %v",
 						v.SnapshotConfig.Name, backend.Name(), err)
@@ -1152,6 +1202,10 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 ) (backendExternal *storage.BackendExternal, err error) {
 	var backend storage.Backend
 
+	// Wait for any volumes being provisioned on the backend
+	o.lockBackendHoldingGlobalLock(ctx, "updateBackend", backendUUID)
+	defer unlockBackend(ctx, "updateBackend", backendUUID)
+
 	// Check whether the backend exists.
 	originalBackend, found := o.backends[backendUUID]
 	if !found {
@@ -1282,6 +1336,21 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 		}
 	}
 
+	if err = o.replaceBackend(ctx, originalBackend, backend); err != nil {
+		return nil, err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend": backend,
+	}).Debug("Returning external version")
+	return backend.ConstructExternal(ctx), nil
+}
+
+// replaceBackend replaces a backend in memory with an updated or reinitialized instance of it, moving its
+// volumes and storage class memberships to the new instance.  It assumes the mutex lock is already held.
+func (o *TridentOrchestrator) replaceBackend(
+	ctx context.Context, originalBackend, backend storage.Backend,
+) error {
 	// Update the backend state in memory
 	delete(o.backends, originalBackend.BackendUUID())
 	// the fake driver needs these copied forward
@@ -1294,6 +1363,7 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 	}
 	originalBackend.Terminate(ctx)
 	o.backends[backend.BackendUUID()] = backend
+	o.health.reset(backend.BackendUUID())
 
 	// Update the volume state in memory
 	// Identify orphaned volumes (i.e., volumes that are not present on the
@@ -1330,10 +1400,10 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 			}
 			if updatePersistentStore {
 				if err := o.updateVolumeOnPersistentStore(ctx, vol); err != nil {
-					return nil, err
+					return err
 				}
 			}
-			o.backends[backend.BackendUUID()].Volumes()[volName] = vol
+			backend.CacheVolume(vol)
 		}
 	}
 
@@ -1356,10 +1426,7 @@ func (o *TridentOrchestrator) updateBackendByBackendUUID(
 			strings.Join(classes, ", "))
 	}
 
-	Logc(ctx).WithFields(log.Fields{
-		"backend": backend,
-	}).Debug("Returning external version")
-	return backend.ConstructExternal(ctx), nil
+	return nil
 }
 
 // UpdateBackendState updates an existing backend's state.
@@ -1395,6 +1462,11 @@ func (o *TridentOrchestrator) updateBackendState(
 	if err != nil {
 		return nil, err
 	}
+
+	// Wait for any volumes being provisioned on the backend
+	o.lockBackendHoldingGlobalLock(ctx, "updateBackendState", backendUUID)
+	defer unlockBackend(ctx, "updateBackendState", backendUUID)
+
 	backend, found := o.backends[backendUUID]
 	if !found {
 		return nil, utils.NotFoundError(fmt.Sprintf("backend %v was not found", backendName))
@@ -1412,6 +1484,9 @@ func (o *TridentOrchestrator) updateBackendState(
 	}
 	backend.SetState(newBackendState)
 
+	// A backend failed by an administrator stays failed
+	o.health.reset(backendUUID)
+
 	return backend.ConstructExternal(ctx), o.storeClient.UpdateBackend(ctx, backend)
 }
 
@@ -1461,8 +1536,8 @@ func (o *TridentOrchestrator) GetBackend(
 
 	defer recordTiming("backend_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	backendUUID, err := o.getBackendUUIDByBackendName(backendName)
 	if err != nil {
@@ -1492,8 +1567,8 @@ func (o *TridentOrchestrator) GetBackendByBackendUUID(
 
 	defer recordTiming("backend_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	backend, err := o.getBackendByBackendUUID(backendUUID)
 	if err != nil {
@@ -1523,8 +1598,8 @@ func (o *TridentOrchestrator) ListBackends(
 
 	defer recordTiming("backend_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	Logc(ctx).Debugf("About to list backends: %v", o.backends)
 	backends := make([]*storage.BackendExternal, 0)
@@ -1580,6 +1655,10 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(ctx context.Context, ba
 		"backendUUID": backendUUID,
 	}).Debug("deleteBackendByBackendUUID")
 
+	// Wait for any volumes being provisioned on the backend
+	o.lockBackendHoldingGlobalLock(ctx, "deleteBackend", backendUUID)
+	defer unlockBackend(ctx, "deleteBackend", backendUUID)
+
 	backend, found := o.backends[backendUUID]
 	if !found {
 		return utils.NotFoundError(fmt.Sprintf("backend %s not found", backendName))
@@ -1612,6 +1691,7 @@ func (o *TridentOrchestrator) deleteBackendByBackendUUID(ctx context.Context, ba
 	for _, sc := range storageClasses {
 		sc.RemovePoolsForBackend(backend)
 	}
+	o.health.reset(backendUUID)
 	if !backend.HasVolumes() {
 		backend.Terminate(ctx)
 		delete(o.backends, backendUUID)
@@ -1661,6 +1741,13 @@ func (o *TridentOrchestrator) AddVolume(
 
 	defer recordTiming("volume_add", &err)()
 
+	// The global lock is released while the volume is provisioned, so the volume lock guards the name
+	// of the new volume and of any cross-zone replica for the whole operation.  A subordinate volume
+	// is registered with its source volume, so the source volume is locked too.
+	lockNames := []string{volumeConfig.Name, volumeConfig.Name + replicaVolumeSuffix, volumeConfig.ShareSourceVolume}
+	lockVolumes(ctx, "addVolume", lockNames...)
+	defer unlockVolumes(ctx, "addVolume", lockNames...)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -1674,6 +1761,66 @@ func (o *TridentOrchestrator) AddVolume(
 	}
 }
 
+// ExplainVolumePlacement reports, for every storage pool, whether a volume with the supplied config would be
+// placed there and if not, why.  It is a dry run that provisions nothing, so the volume need not exist.
+func (o *TridentOrchestrator) ExplainVolumePlacement(
+	ctx context.Context, volumeConfig *storage.VolumeConfig,
+) (placements []storageclass.PoolPlacement, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("volume_explain", &err)()
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	protocol, err := o.getProtocol(ctx, volumeConfig.VolumeMode, volumeConfig.AccessMode, volumeConfig.Protocol)
+	if err != nil {
+		return nil, err
+	}
+
+	sc, ok := o.storageClasses[volumeConfig.StorageClass]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("storage class %s was not found", volumeConfig.StorageClass))
+	}
+
+	// Pools on backends that can't provision are rejected without checking them against the storage class
+	pools := make([]storage.Pool, 0)
+	unavailable := make([]storageclass.PoolPlacement, 0)
+	for _, backend := range o.backends {
+		reason := ""
+		if !backend.State().IsOnline() {
+			reason = fmt.Sprintf("backend is %s", backend.State())
+		} else if backendExternal := backend.ConstructExternal(ctx); backendExternal.Degraded {
+			reason = fmt.Sprintf("backend is degraded; %s", backendExternal.DegradedReason)
+		}
+
+		for _, pool := range backend.Storage() {
+			if reason == "" {
+				pools = append(pools, pool)
+			} else {
+				unavailable = append(unavailable, storageclass.PoolPlacement{
+					Backend: backend.Name(),
+					Pool:    pool.Name(),
+					Reason:  reason,
+				})
+			}
+		}
+	}
+
+	placements = append(sc.ExplainPlacement(ctx, pools, protocol, volumeConfig), unavailable...)
+
+	// Accepted pools stay in the order they would be tried, while rejected pools are listed by name
+	firstRejected := 0
+	for firstRejected < len(placements) && placements[firstRejected].Accepted {
+		firstRejected++
+	}
+	storageclass.SortPoolPlacements(placements[firstRejected:])
+
+	return placements, nil
+}
+
 func (o *TridentOrchestrator) addVolume(
 	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
@@ -1687,13 +1834,24 @@ func (o *TridentOrchestrator) addVolume(
 	// If a volume is already being created, retry the operation with the same backend
 	// instead of continuing here and potentially starting over on a different backend.
 	// Otherwise, treat this as a new volume creation workflow.
-	if retryTxn, err := o.GetVolumeCreatingTransaction(ctx, volumeConfig); err != nil {
+	retryTxn, err := o.GetVolumeCreatingTransaction(ctx, volumeConfig)
+	if err != nil {
 		return nil, err
 	} else if retryTxn != nil {
-		return o.addVolumeRetry(ctx, retryTxn)
+		externalVol, err = o.addVolumeRetry(ctx, retryTxn)
+	} else {
+		externalVol, err = o.addVolumeInitial(ctx, volumeConfig)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return o.addVolumeInitial(ctx, volumeConfig)
+	// Mirror the new volume into another zone if its storage class asks for it
+	if sc, ok := o.storageClasses[externalVol.Config.StorageClass]; ok && sc.HasCrossZoneReplica() {
+		return o.addVolumeReplica(ctx, externalVol.Config.Name, sc)
+	}
+
+	return externalVol, nil
 }
 
 // addVolumeInitial continues the volume creation operation.
@@ -1702,12 +1860,9 @@ func (o *TridentOrchestrator) addVolume(
 func (o *TridentOrchestrator) addVolumeInitial(
 	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
-	var (
-		backend storage.Backend
-		vol     *storage.Volume
-		pool    storage.Pool
-		txn     *storage.VolumeTransaction
-	)
+	if volumeConfig.SCSIPersistentReservation && volumeConfig.VolumeMode != config.RawBlock {
+		return nil, utils.InvalidInputError("SCSI persistent reservations are only supported for raw block volumes")
+	}
 
 	// Get the protocol based on the specified access mode & protocol
 	protocol, err := o.getProtocol(ctx, volumeConfig.VolumeMode, volumeConfig.AccessMode, volumeConfig.Protocol)
@@ -1725,6 +1880,25 @@ func (o *TridentOrchestrator) addVolumeInitial(
 		return nil, fmt.Errorf("no available backends for storage class %s", volumeConfig.StorageClass)
 	}
 
+	return o.addVolumeToPools(ctx, volumeConfig, sc, protocol, pools)
+}
+
+// addVolumeToPools creates a volume on the first of the supplied pools that can accommodate it.
+// This method should only be called from addVolumeInitial or addVolumeReplica, as it does not take locks
+// or otherwise do much validation of the volume config.
+func (o *TridentOrchestrator) addVolumeToPools(
+	ctx context.Context, volumeConfig *storage.VolumeConfig, sc *storageclass.StorageClass,
+	protocol config.Protocol, pools []storage.Pool,
+) (externalVol *storage.VolumeExternal, err error) {
+	var (
+		backend storage.Backend
+		vol     *storage.Volume
+		pool    storage.Pool
+		txn     *storage.VolumeTransaction
+	)
+
+	volAttributes := sc.GetAttributes()
+
 	// Add a transaction to clean out any existing transactions
 	txn = &storage.VolumeTransaction{
 		Config: volumeConfig,
@@ -1746,6 +1920,7 @@ func (o *TridentOrchestrator) addVolumeInitial(
 
 	volumeCreationErrors := make([]error, 0)
 	ineligibleBackends := make(map[string]struct{})
+	backendsBusy := false
 
 	// The pool lists are already shuffled, so just try them in order.
 	// The loop terminates when creation on all matching pools has failed.
@@ -1764,19 +1939,33 @@ func (o *TridentOrchestrator) addVolumeInitial(
 			continue
 		}
 
+		// A job may only create its volume on a backend that has room for another job in flight
+		if !admitJobOnBackend(ctx, backend.BackendUUID()) {
+			Logc(ctx).WithFields(log.Fields{
+				"backend": backend.Name(),
+				"volume":  volumeConfig.Name,
+			}).Debug("Backend has too many volume jobs in flight, skipping it.")
+			backendsBusy = true
+			continue
+		}
+
 		// CreatePrepare has a side effect that updates the volumeConfig with the backend-specific internal name
 		backend.Driver().CreatePrepare(ctx, volumeConfig)
 
 		// Update transaction with updated volumeConfig
 		txn = &storage.VolumeTransaction{
-			Config: volumeConfig,
-			Op:     storage.AddVolume,
+			Config:    volumeConfig,
+			Op:        storage.AddVolume,
+			StartTime: txn.StartTime,
 		}
 		if err = o.storeClient.UpdateVolumeTransaction(ctx, txn); err != nil {
 			return nil, err
 		}
 
-		vol, err = backend.AddVolume(ctx, volumeConfig, pool, sc.GetAttributes(), false)
+		err = o.callBackend(ctx, "addVolume", backend, func() (provisionErr error) {
+			vol, provisionErr = backend.AddVolume(ctx, volumeConfig, pool, volAttributes, false)
+			return provisionErr
+		})
 		if err != nil {
 
 			logFields := log.Fields{
@@ -1801,8 +1990,9 @@ func (o *TridentOrchestrator) addVolumeInitial(
 				fmt.Errorf("[Failed to create volume %s on storage pool %s from backend %s: %w]",
 					volumeConfig.Name, pool.Name(), backend.Name(), err))
 
-			// If this backend cannot handle the new volume on any pool, remove it from further consideration.
-			if drivers.IsBackendIneligibleError(err) {
+			// If this backend cannot handle the new volume on any pool, or is not responding, remove it
+			// from further consideration.
+			if drivers.IsBackendIneligibleError(err) || utils.IsBackendUnavailableError(err) {
 				ineligibleBackends[backend.BackendUUID()] = struct{}{}
 			}
 		} else {
@@ -1812,7 +2002,12 @@ func (o *TridentOrchestrator) addVolumeInitial(
 	}
 
 	externalVol = nil
-	if len(volumeCreationErrors) == 0 {
+	if backendsBusy {
+		err = &jobBackendsBusyError{
+			message: fmt.Sprintf("all backends that might create volume %s have too many volume jobs in flight",
+				volumeConfig.Name),
+		}
+	} else if len(volumeCreationErrors) == 0 {
 		err = fmt.Errorf("no suitable %s backend with \"%s\" storage class and %s of free space was found",
 			protocol, volumeConfig.StorageClass, volumeConfig.Size)
 	} else {
@@ -1862,7 +2057,10 @@ func (o *TridentOrchestrator) addVolumeRetry(
 		err = o.addVolumeRetryCleanup(ctx, err, backend, pool, txn, volumeConfig)
 	}()
 
-	vol, err = backend.AddVolume(ctx, volumeConfig, pool, make(map[string]sa.Request), true)
+	err = o.callBackend(ctx, "addVolume", backend, func() (provisionErr error) {
+		vol, provisionErr = backend.AddVolume(ctx, volumeConfig, pool, make(map[string]sa.Request), true)
+		return provisionErr
+	})
 	if err != nil {
 
 		logFields := log.Fields{
@@ -1912,6 +2110,12 @@ func (o *TridentOrchestrator) addVolumeFinish(
 		return nil, err
 	}
 
+	// If the backend was updated while the volume was being provisioned, the volume was cached on the
+	// backend's previous incarnation, so cache it on the current one too.
+	if currentBackend, ok := o.backends[vol.BackendUUID]; ok && currentBackend != backend {
+		currentBackend.CacheVolume(vol)
+	}
+
 	// Update internal cache and return external form of the new volume
 	o.volumes[vol.Config.Name] = vol
 	externalVol = vol.ConstructExternal()
@@ -1946,6 +2150,113 @@ func (o *TridentOrchestrator) UpdateVolume(ctx context.Context, volume string, p
 	return nil
 }
 
+// UpdateVolumeLUKSWrappedKey records the KMS-wrapped LUKS data-encryption key for a volume in the cache and
+// persistent store.  The key is only replaced if the volume's current key is previousWrappedKey, or if the volume
+// has no key and previousWrappedKey is empty, so that nodes staging a new volume at once cannot overwrite the key
+// another node is already using.  Once a volume's key has been revoked, it may not be replaced.
+func (o *TridentOrchestrator) UpdateVolumeLUKSWrappedKey(
+	ctx context.Context, volume string, wrappedKey *utils.LUKSWrappedKey, previousWrappedKey string,
+) error {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	if wrappedKey == nil || wrappedKey.WrappedKey == "" {
+		return utils.InvalidInputError("wrapped key must be specified")
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	vol, ok := o.volumes[volume]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %v was not found", volume))
+	}
+	if vol.Config.LUKSWrappedKey != nil && vol.Config.LUKSWrappedKey.Revoked {
+		return fmt.Errorf("LUKS key for volume %s has been revoked", volume)
+	}
+	currentWrappedKey := ""
+	if vol.Config.LUKSWrappedKey != nil {
+		currentWrappedKey = vol.Config.LUKSWrappedKey.WrappedKey
+	}
+	if currentWrappedKey != previousWrappedKey {
+		return utils.FoundError(fmt.Sprintf("LUKS key for volume %s has already been replaced", volume))
+	}
+
+	newVolume := storage.NewVolume(vol.Config.ConstructClone(), vol.BackendUUID, vol.Pool, vol.Orphaned, vol.State)
+	newVolume.Config.LUKSWrappedKey = &utils.LUKSWrappedKey{
+		Provider:   wrappedKey.Provider,
+		KeyName:    wrappedKey.KeyName,
+		WrappedKey: wrappedKey.WrappedKey,
+	}
+	if err := o.storeClient.UpdateVolume(ctx, newVolume); err != nil {
+		return err
+	}
+	o.volumes[volume] = newVolume
+	return nil
+}
+
+// SetVolumeLUKSKeyRevoked revokes or restores a volume's KMS-wrapped LUKS key.  Nodes refuse to stage a volume
+// whose key is revoked.
+func (o *TridentOrchestrator) SetVolumeLUKSKeyRevoked(ctx context.Context, volume string, revoked bool) error {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	vol, ok := o.volumes[volume]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %v was not found", volume))
+	}
+	if vol.Config.LUKSWrappedKey == nil {
+		return utils.NotFoundError(fmt.Sprintf("volume %v has no KMS-wrapped LUKS key", volume))
+	}
+
+	newVolume := storage.NewVolume(vol.Config.ConstructClone(), vol.BackendUUID, vol.Pool, vol.Orphaned, vol.State)
+	newVolume.Config.LUKSWrappedKey.Revoked = revoked
+	if err := o.storeClient.UpdateVolume(ctx, newVolume); err != nil {
+		return err
+	}
+	o.volumes[volume] = newVolume
+
+	Logc(ctx).WithFields(log.Fields{"volume": volume, "revoked": revoked}).Info("Updated LUKS key revocation.")
+	return nil
+}
+
+// UpdateSnapshot updates the LUKS passphrase names stored on a snapshot in the cache and persistent store
+func (o *TridentOrchestrator) UpdateSnapshot(
+	ctx context.Context, volumeName, snapshotName string, passphraseNames *[]string,
+) error {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	snapshotID := storage.MakeSnapshotID(volumeName, snapshotName)
+	snapshot, ok := o.snapshots[snapshotID]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("snapshot %v was not found", snapshotID))
+	}
+
+	// Update the persistence layer before the core copy, as is done for volumes
+	newSnapshot := snapshot.ConstructClone()
+	if passphraseNames != nil {
+		newSnapshot.Config.LUKSPassphraseNames = *passphraseNames
+	}
+	if err := o.storeClient.UpdateSnapshot(ctx, newSnapshot); err != nil {
+		return err
+	}
+	o.snapshots[snapshotID] = newSnapshot
+	return nil
+}
+
 func (o *TridentOrchestrator) CloneVolume(
 	ctx context.Context, volumeConfig *storage.VolumeConfig,
 ) (externalVol *storage.VolumeExternal, err error) {
@@ -1955,6 +2266,11 @@ func (o *TridentOrchestrator) CloneVolume(
 
 	defer recordTiming("volume_clone", &err)()
 
+	// The global lock is released while the clone is provisioned, so the volume lock guards the clone's
+	// name and keeps the source volume from being deleted for the whole operation.
+	lockVolumes(ctx, "cloneVolume", volumeConfig.Name, volumeConfig.CloneSourceVolume)
+	defer unlockVolumes(ctx, "cloneVolume", volumeConfig.Name, volumeConfig.CloneSourceVolume)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -2114,7 +2430,12 @@ func (o *TridentOrchestrator) cloneVolumeInitial(
 	}()
 
 	// Create the clone
-	if vol, err = backend.CloneVolume(ctx, sourceVolume.Config, cloneConfig, pool, false); err != nil {
+	sourceConfig := sourceVolume.Config.ConstructClone()
+	err = o.callBackend(ctx, "cloneVolume", backend, func() (provisionErr error) {
+		vol, provisionErr = backend.CloneVolume(ctx, sourceConfig, cloneConfig, pool, false)
+		return provisionErr
+	})
+	if err != nil {
 
 		logFields := log.Fields{
 			"backend":      backend.Name(),
@@ -2190,7 +2511,12 @@ func (o *TridentOrchestrator) cloneVolumeRetry(
 	}()
 
 	// Create the clone
-	if vol, err = backend.CloneVolume(ctx, sourceVolume.Config, cloneConfig, pool, true); err != nil {
+	sourceConfig := sourceVolume.Config.ConstructClone()
+	err = o.callBackend(ctx, "cloneVolume", backend, func() (provisionErr error) {
+		vol, provisionErr = backend.CloneVolume(ctx, sourceConfig, cloneConfig, pool, true)
+		return provisionErr
+	})
+	if err != nil {
 
 		logFields := log.Fields{
 			"backend":      backend.Name(),
@@ -2227,8 +2553,8 @@ func (o *TridentOrchestrator) GetVolumeExternal(
 
 	defer recordTiming("volume_get_external", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	Logc(ctx).WithFields(log.Fields{
 		"originalName": volumeName,
@@ -2258,8 +2584,8 @@ func (o *TridentOrchestrator) GetVolumeByInternalName(
 ) (volume string, err error) {
 	defer recordTiming("volume_internal_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	for _, vol := range o.volumes {
 		if vol.Config.InternalName == volumeInternal {
@@ -2549,6 +2875,9 @@ func (o *TridentOrchestrator) AddVolumeTransaction(ctx context.Context, volTxn *
 		}
 	}
 
+	if volTxn.StartTime.IsZero() {
+		volTxn.StartTime = time.Now()
+	}
 	return o.storeClient.AddVolumeTransaction(ctx, volTxn)
 }
 
@@ -2577,6 +2906,35 @@ func (o *TridentOrchestrator) GetVolumeTransaction(
 	return o.storeClient.GetExistingVolumeTransaction(ctx, volTxn)
 }
 
+// ListVolumeTransactions returns the transactions of all in-flight operations, oldest first.
+func (o *TridentOrchestrator) ListVolumeTransactions(
+	ctx context.Context,
+) (txns []*storage.VolumeTransactionExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+
+	defer recordTiming("transaction_list", &err)()
+
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	volTxns, err := o.storeClient.GetVolumeTransactions(ctx)
+	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
+		return nil, err
+	}
+
+	sort.Slice(volTxns, func(i, j int) bool {
+		return volTxns[i].StartTime.Before(volTxns[j].StartTime)
+	})
+
+	txns = make([]*storage.VolumeTransactionExternal, 0, len(volTxns))
+	for _, volTxn := range volTxns {
+		txns = append(txns, volTxn.ConstructExternal())
+	}
+	return txns, nil
+}
+
 // DeleteVolumeTransaction deletes a volume transaction created by
 // addVolumeTransaction.
 func (o *TridentOrchestrator) DeleteVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error {
@@ -2606,7 +2964,12 @@ func (o *TridentOrchestrator) addVolumeCleanup(
 		if backend != nil && vol != nil {
 			// We succeeded in adding the volume to the backend; now
 			// delete it.
-			cleanupErr = backend.RemoveVolume(ctx, vol.Config)
+			if currentBackend, ok := o.backends[backend.BackendUUID()]; ok {
+				backend = currentBackend
+			}
+			cleanupErr = o.callBackend(ctx, "addVolumeCleanup", backend, func() error {
+				return backend.RemoveVolume(ctx, vol.Config)
+			})
 			if cleanupErr != nil {
 				cleanupErr = fmt.Errorf("unable to delete volume from backend during cleanup:  %v", cleanupErr)
 			}
@@ -2673,14 +3036,16 @@ func (o *TridentOrchestrator) addVolumeRetryCleanup(
 
 	if existingTxn.Op == storage.AddVolume {
 
+		startTime := time.Now()
 		creatingTxn := &storage.VolumeTransaction{
 			VolumeCreatingConfig: &storage.VolumeCreatingConfig{
-				StartTime:    time.Now(),
+				StartTime:    startTime,
 				BackendUUID:  backend.BackendUUID(),
 				Pool:         pool.Name(),
 				VolumeConfig: *volumeConfig,
 			},
-			Op: storage.VolumeCreating,
+			Op:        storage.VolumeCreating,
+			StartTime: startTime,
 		}
 
 		if creatingErr := o.storeClient.UpdateVolumeTransaction(ctx, creatingTxn); creatingErr != nil {
@@ -2758,8 +3123,8 @@ func (o *TridentOrchestrator) GetVolume(
 
 	defer recordTiming("volume_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	return o.getVolume(ctx, volumeName)
 }
@@ -2794,8 +3159,8 @@ func (o *TridentOrchestrator) ListVolumes(context.Context) (volumes []*storage.V
 
 	defer recordTiming("volume_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 	volumes = make([]*storage.VolumeExternal, 0, len(o.volumes)+len(o.subordinateVolumes))
 	for _, v := range o.volumes {
 		volumes = append(volumes, v.ConstructExternal())
@@ -2871,7 +3236,10 @@ func (o *TridentOrchestrator) deleteVolume(ctx context.Context, volumeName strin
 	// Note that this call will only return an error if the backend actually
 	// fails to delete the volume.  If the volume does not exist on the backend,
 	// the driver will not return an error.  Thus, we're fine.
-	if err := volumeBackend.RemoveVolume(ctx, volume.Config); err != nil {
+	err = o.callBackend(ctx, "deleteVolume", volumeBackend, func() error {
+		return volumeBackend.RemoveVolume(ctx, volume.Config)
+	})
+	if err != nil {
 		if _, ok := err.(*storage.NotManagedError); !ok {
 			Logc(ctx).WithFields(log.Fields{
 				"volume":      volumeName,
@@ -2887,6 +3255,12 @@ func (o *TridentOrchestrator) deleteVolume(ctx context.Context, volumeName strin
 			}).Debug("Skipping backend deletion of volume.")
 		}
 	}
+
+	// A volume in deleting state may have been deleted by another operation that released its last pin
+	// while the backend was deleting it
+	if _, ok := o.volumes[volumeName]; !ok {
+		return nil
+	}
 	if err := o.deleteVolumeFromPersistentStoreIgnoreError(ctx, volume); err != nil {
 		return err
 	}
@@ -2902,7 +3276,12 @@ func (o *TridentOrchestrator) deleteVolume(ctx context.Context, volumeName strin
 	}
 
 	// Check if we need to remove a soft-deleted backend
-	if volumeBackend.State().IsDeleting() && !volumeBackend.HasVolumes() {
+	if volumeBackend, ok := o.backends[volume.BackendUUID]; ok && volumeBackend.State().IsDeleting() {
+		o.lockBackendHoldingGlobalLock(ctx, "deleteVolume", volume.BackendUUID)
+		defer unlockBackend(ctx, "deleteVolume", volume.BackendUUID)
+	}
+	volumeBackend, ok := o.backends[volume.BackendUUID]
+	if ok && volumeBackend.State().IsDeleting() && !volumeBackend.HasVolumes() {
 		if err := o.storeClient.DeleteBackend(ctx, volumeBackend); err != nil {
 			Logc(ctx).WithFields(log.Fields{
 				"backendUUID": volume.BackendUUID,
@@ -2944,6 +3323,10 @@ func (o *TridentOrchestrator) DeleteVolume(ctx context.Context, volumeName strin
 
 	defer recordTiming("volume_delete", &err)()
 
+	lockNames := o.volumeLockNames(volumeName)
+	lockVolumes(ctx, "deleteVolume", lockNames...)
+	defer unlockVolumes(ctx, "deleteVolume", lockNames...)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -2992,6 +3375,10 @@ func (o *TridentOrchestrator) DeleteVolume(ctx context.Context, volumeName strin
 		}
 	}()
 
+	if err = o.deleteVolumeReplica(ctx, volume); err != nil {
+		return err
+	}
+
 	return o.deleteVolume(ctx, volumeName)
 }
 
@@ -3010,6 +3397,10 @@ func (o *TridentOrchestrator) PublishVolume(
 	}
 	Logc(ctx).WithFields(fields).Info("Publishing volume to node.")
 
+	lockNames := o.volumeLockNames(volumeName)
+	lockVolumes(ctx, "publishVolume", lockNames...)
+	defer unlockVolumes(ctx, "publishVolume", lockNames...)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
@@ -3042,6 +3433,21 @@ func (o *TridentOrchestrator) publishVolume(
 	if volume.State.IsDeleting() {
 		return utils.VolumeStateError(fmt.Sprintf("volume %s is deleting", volumeName))
 	}
+	if volume.State.IsRestoring() {
+		return utils.VolumeStateError(fmt.Sprintf("volume %s is being restored", volumeName))
+	}
+
+	// Docker hosts learn about each other's publications from the backend
+	if config.CurrentDriverContext == config.ContextDocker {
+		if err := o.checkDockerVolumePublications(ctx, volume, publishInfo); err != nil {
+			return err
+		}
+	}
+
+	if node, ok := o.nodes[publishInfo.HostName]; ok && node.Fenced {
+		return fmt.Errorf("node %s is fenced; volume %s may not be published to it", publishInfo.HostName,
+			volumeName)
+	}
 
 	// Check if the publication already exists.
 	publication, found := o.volumePublications.TryGet(volumeName, publishInfo.HostName)
@@ -3114,31 +3520,38 @@ func (o *TridentOrchestrator) publishVolume(
 					safeToEnable = false
 				}
 				if safeToEnable {
-					err := backend.EnablePublishEnforcement(ctx, volume)
-					if err != nil {
-						Logc(ctx).WithError(err).Warnf("Error enabling volume publish enforcement for volume %s",
-							volumeName)
-					}
+					o.enablePublishEnforcement(ctx, backend, volume)
 				}
 			}
 		}
 	}
 
-	// Fill in what we already know
-	publishInfo.VolumeAccessInfo = volume.Config.AccessInfo
+	// The driver works on a copy of the volume config, since the global lock is not held while it does
+	volConfig := volume.Config.ConstructClone()
 
-	nodes := make([]*utils.Node, 0)
-	for _, node := range o.nodes {
-		nodes = append(nodes, node)
+	// Nodes sharing the volume register keys derived from their names; a node that is published the volume again
+	// is no longer preempted
+	if volume.Config.SCSIPersistentReservation {
+		publishInfo.SCSIPersistentReservation = true
+		publishInfo.ReservationKey = utils.PersistentReservationKey(publishInfo.HostName)
+		volConfig.AccessInfo.PreemptReservationKeys = utils.RemoveStringFromSlice(
+			volConfig.AccessInfo.PreemptReservationKeys, publishInfo.ReservationKey)
 	}
-	publishInfo.Nodes = nodes
+
+	publishInfo.Nodes = o.accessibleNodes()
 	if err := o.reconcileNodeAccessOnBackend(ctx, backend); err != nil {
 		err = fmt.Errorf("unable to update node access rules on backend %s; %v", backend.Name(), err)
 		Logc(ctx).Error(err)
 		return err
 	}
 
-	err := backend.PublishVolume(ctx, volume.Config, publishInfo)
+	// Fill in what we already know
+	publishInfo.VolumeAccessInfo = volConfig.AccessInfo
+
+	err := o.callBackend(ctx, "publishVolume", backend, func() error {
+		return backend.PublishVolume(ctx, volConfig, publishInfo)
+	})
+	volume.Config.AccessInfo = volConfig.AccessInfo
 	if err != nil {
 		return err
 	}
@@ -3154,6 +3567,29 @@ func (o *TridentOrchestrator) publishVolume(
 	return nil
 }
 
+// enablePublishEnforcement enables publish enforcement for a volume.  The driver works on a copy of the volume,
+// since the global lock is not held while it does, and any error is only logged, so that the volume may still be
+// published without enforcement.
+func (o *TridentOrchestrator) enablePublishEnforcement(
+	ctx context.Context, backend storage.Backend, volume *storage.Volume,
+) {
+	enforcedVolume := &storage.Volume{
+		Config:      volume.Config.ConstructClone(),
+		BackendUUID: volume.BackendUUID,
+		Pool:        volume.Pool,
+		Orphaned:    volume.Orphaned,
+		State:       volume.State,
+	}
+	err := o.callBackend(ctx, "publishVolume", backend, func() error {
+		return backend.EnablePublishEnforcement(ctx, enforcedVolume)
+	})
+	volume.Config.AccessInfo = enforcedVolume.Config.AccessInfo
+	if err != nil {
+		Logc(ctx).WithError(err).Warnf("Error enabling volume publish enforcement for volume %s",
+			volume.Config.Name)
+	}
+}
+
 func generateVolumePublication(volName string, publishInfo *utils.VolumePublishInfo) *utils.VolumePublication {
 	vp := &utils.VolumePublication{
 		Name:       utils.GenerateVolumePublishName(volName, publishInfo.HostName),
@@ -3205,6 +3641,10 @@ func (o *TridentOrchestrator) UnpublishVolume(ctx context.Context, volumeName, n
 	}
 	Logc(ctx).WithFields(fields).Info("Unpublishing volume from node.") // audit trail
 
+	lockNames := o.volumeLockNames(volumeName)
+	lockVolumes(ctx, "unpublishVolume", lockNames...)
+	defer unlockVolumes(ctx, "unpublishVolume", lockNames...)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
@@ -3286,6 +3726,7 @@ func (o *TridentOrchestrator) unpublishVolume(ctx context.Context, volumeName, n
 
 	// Build list of nodes to which the volume remains published
 	nodeMap := make(map[string]*utils.Node)
+	nodeVolumes := make(map[string]string) // Volumes still staged on nodes that were not force detached
 	for _, pub := range o.listVolumePublicationsForVolumeAndSubordinates(ctx, volume.Config.Name) {
 
 		// Exclude the publication we are unpublishing
@@ -3296,8 +3737,13 @@ func (o *TridentOrchestrator) unpublishVolume(ctx context.Context, volumeName, n
 		if n, found := o.nodes[pub.NodeName]; !found {
 			Logc(ctx).WithField("node", pub.NodeName).Warning("Node not found during volume unpublish.")
 			continue
+		} else if n.Fenced {
+			continue
 		} else {
 			nodeMap[pub.NodeName] = n
+			if !pub.NotSafeToAttach {
+				nodeVolumes[pub.NodeName] = pub.VolumeName
+			}
 		}
 	}
 
@@ -3311,11 +3757,32 @@ func (o *TridentOrchestrator) unpublishVolume(ctx context.Context, volumeName, n
 		return fmt.Errorf("backend %s not found", volume.BackendUUID)
 	}
 
-	// Unpublish the volume
-	if err := backend.UnpublishVolume(ctx, volume.Config, publishInfo); err != nil {
+	// Unpublish the volume; the driver works on a copy of the volume config, since the global lock is not held
+	// while it does
+	volConfig := volume.Config.ConstructClone()
+	err := o.callBackend(ctx, "unpublishVolume", backend, func() error {
+		return backend.UnpublishVolume(ctx, volConfig, publishInfo)
+	})
+	volume.Config.AccessInfo = volConfig.AccessInfo
+	if err != nil {
 		return err
 	}
 
+	// A force-detached node may still be running, so its reservation key is saved with the volume to be preempted
+	// by any node that stages the volume later, and is preempted as soon as possible by a node still sharing the
+	// volume.  That node is called once the lock is released, and the unpublish does not wait for it.
+	if dirty && volume.Config.SCSIPersistentReservation {
+		reservationKey := utils.PersistentReservationKey(nodeName)
+		if !utils.SliceContainsString(volume.Config.AccessInfo.PreemptReservationKeys, reservationKey) {
+			volume.Config.AccessInfo.PreemptReservationKeys = append(
+				volume.Config.AccessInfo.PreemptReservationKeys, reservationKey)
+			if err := o.updateVolumeOnPersistentStore(ctx, volume); err != nil {
+				return err
+			}
+		}
+		o.startReservationKeyPreempt(ctx, nodeMap, nodeVolumes, reservationKey)
+	}
+
 	// Delete the publication if it's not a dirty unpublish.
 	if !dirty {
 		if err := o.deleteVolumePublication(ctx, volumeName, nodeName); err != nil {
@@ -3328,6 +3795,87 @@ func (o *TridentOrchestrator) unpublishVolume(ctx context.Context, volumeName, n
 	return nil
 }
 
+// startReservationKeyPreempt has one of the nodes still sharing a volume preempt the reservation key of a node that
+// was force detached from it, retrying in the background until one succeeds or the retries run out.  If no node
+// shares the volume, or none can be reached, the key is left for the next node to stage the volume.  The caller
+// must hold the lock, which is used only to find the frontend that reaches the nodes.
+func (o *TridentOrchestrator) startReservationKeyPreempt(
+	ctx context.Context, nodes map[string]*utils.Node, nodeVolumes map[string]string, reservationKey string,
+) {
+	if len(nodeVolumes) == 0 {
+		return
+	}
+
+	var preempter ReservationPreempter
+	for _, f := range o.frontends {
+		if p, ok := f.(ReservationPreempter); ok {
+			preempter = p
+			break
+		}
+	}
+	if preempter == nil {
+		Logc(ctx).WithField("reservationKey", reservationKey).Warning(
+			"No frontend can reach the nodes sharing the volume; reservation key will be preempted when the " +
+				"volume is next staged.")
+		return
+	}
+
+	// The nodes are copied, as they may change once the lock is released
+	sharingNodes := make(map[string]*utils.Node, len(nodeVolumes))
+	for nodeName := range nodeVolumes {
+		node := *nodes[nodeName]
+		sharingNodes[nodeName] = &node
+	}
+	preemptVolumes := make(map[string]string, len(nodeVolumes))
+	for nodeName, volumeName := range nodeVolumes {
+		preemptVolumes[nodeName] = volumeName
+	}
+
+	preemptCtx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
+	go preemptReservationKey(preemptCtx, preempter, sharingNodes, preemptVolumes, reservationKey)
+}
+
+// preemptReservationKey asks each node sharing a volume in turn to preempt the reservation key of a node that was
+// force detached from it, until one succeeds, retrying with backoff.
+func preemptReservationKey(
+	ctx context.Context, preempter ReservationPreempter, nodes map[string]*utils.Node,
+	nodeVolumes map[string]string, reservationKey string,
+) {
+	preempt := func() error {
+		var err error
+		for nodeName, volumeName := range nodeVolumes {
+			if err = preempter.PreemptReservationKey(ctx, volumeName, nodes[nodeName], reservationKey); err == nil {
+				Logc(ctx).WithFields(log.Fields{
+					"node":           nodeName,
+					"volume":         volumeName,
+					"reservationKey": reservationKey,
+				}).Info("Preempted reservation key of force-detached node.")
+				return nil
+			}
+			Logc(ctx).WithError(err).WithFields(log.Fields{
+				"node":           nodeName,
+				"volume":         volumeName,
+				"reservationKey": reservationKey,
+			}).Warning("Could not preempt reservation key from node.")
+		}
+		return err
+	}
+	preemptNotify := func(err error, duration time.Duration) {
+		Logc(ctx).WithFields(log.Fields{
+			"reservationKey": reservationKey,
+			"increment":      duration,
+		}).Debug("Reservation key not yet preempted, waiting.")
+	}
+	preemptBackoff := backoff.NewExponentialBackOff()
+	preemptBackoff.InitialInterval = reservationPreemptInitialInterval
+	preemptBackoff.MaxElapsedTime = reservationPreemptMaxElapsedTime
+
+	if err := backoff.RetryNotify(preempt, preemptBackoff, preemptNotify); err != nil {
+		Logc(ctx).WithError(err).WithField("reservationKey", reservationKey).Warning(
+			"Unable to preempt reservation key; it will be preempted when the volume is next staged.")
+	}
+}
+
 // isDockerPluginMode returns true if the ENV variable config.DockerPluginModeEnvVariable is set
 func isDockerPluginMode() bool {
 	return os.Getenv(config.DockerPluginModeEnvVariable) != ""
@@ -3357,6 +3905,12 @@ func (o *TridentOrchestrator) AttachVolume(
 		return utils.VolumeStateError(fmt.Sprintf("volume %s is deleting", volumeName))
 	}
 
+	// Docker has no way to supply the LUKS passphrases, so encrypted volumes cannot be opened here
+	if isLUKS, _ := strconv.ParseBool(publishInfo.LUKSEncryption); isLUKS {
+		return utils.UnsupportedError(fmt.Sprintf("volume %s is LUKS encrypted, which is not supported by Docker",
+			volumeName))
+	}
+
 	hostMountpoint := mountpoint
 	isDockerPluginModeSet := isDockerPluginMode()
 	if isDockerPluginModeSet {
@@ -3409,7 +3963,8 @@ func (o *TridentOrchestrator) AttachVolume(
 			publishInfo.SubvolumeMountOptions = utils.AppendToStringList(publishInfo.SubvolumeMountOptions, "bind", ",")
 		}
 
-		if loopDeviceName, _, err := utils.AttachBlockOnFileVolume(ctx, "", publishInfo); err != nil {
+		if loopDeviceName, _, err := utils.AttachBlockOnFileVolume(ctx, volumeName, "", publishInfo,
+			map[string]string{}); err != nil {
 			return err
 		} else {
 			return utils.MountDevice(ctx, loopDeviceName, mountpoint, publishInfo.SubvolumeMountOptions, isRawBlock)
@@ -3653,8 +4208,8 @@ func (o *TridentOrchestrator) ListSubordinateVolumes(
 
 	defer recordTiming("subordinate_volume_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	var sourceVolumes map[string]*storage.Volume
 
@@ -3696,8 +4251,8 @@ func (o *TridentOrchestrator) GetSubordinateSourceVolume(
 
 	defer recordTiming("subordinate_source_volume_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	if subordinateVolume, ok := o.subordinateVolumes[subordinateVolumeName]; !ok {
 		return nil, utils.NotFoundError(fmt.Sprintf("subordinate volume %s not found", subordinateVolumeName))
@@ -3782,6 +4337,9 @@ func (o *TridentOrchestrator) CreateSnapshot(
 
 	defer recordTiming("snapshot_create", &err)()
 
+	lockVolumes(ctx, "createSnapshot", snapshotConfig.VolumeName)
+	defer unlockVolumes(ctx, "createSnapshot", snapshotConfig.VolumeName)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -3838,7 +4396,11 @@ func (o *TridentOrchestrator) CreateSnapshot(
 	}()
 
 	// Create the snapshot
-	snapshot, err = backend.CreateSnapshot(ctx, snapshotConfig, volume.Config)
+	volConfig := volume.Config.ConstructClone()
+	err = o.callBackend(ctx, "createSnapshot", backend, func() (snapshotErr error) {
+		snapshot, snapshotErr = backend.CreateSnapshot(ctx, snapshotConfig, volConfig)
+		return snapshotErr
+	})
 	if err != nil {
 		if utils.IsMaxLimitReachedError(err) {
 			return nil, utils.MaxLimitReachedError(fmt.Sprintf("failed to create snapshot %s for volume %s on backend %s: %v",
@@ -3849,7 +4411,6 @@ func (o *TridentOrchestrator) CreateSnapshot(
 	}
 
 	// Ensure we store all potential LUKS passphrases, there may have been a passphrase rotation during the snapshot process
-	volume = o.volumes[snapshotConfig.VolumeName]
 	for _, v := range volume.Config.LUKSPassphraseNames {
 		if !utils.SliceContainsString(snapshotConfig.LUKSPassphraseNames, v) {
 			snapshotConfig.LUKSPassphraseNames = append(snapshotConfig.LUKSPassphraseNames, v)
@@ -3881,7 +4442,9 @@ func (o *TridentOrchestrator) addSnapshotCleanup(
 		//     In this case, we need to remove the snapshot from the backend.
 		if backend != nil && snapshot != nil {
 			// We succeeded in adding the snapshot to the backend; now delete it.
-			cleanupErr = backend.DeleteSnapshot(ctx, snapshot.Config, volTxn.Config)
+			cleanupErr = o.callBackend(ctx, "addSnapshotCleanup", backend, func() error {
+				return backend.DeleteSnapshot(ctx, snapshot.Config, volTxn.Config)
+			})
 			if cleanupErr != nil {
 				cleanupErr = fmt.Errorf("unable to delete snapshot from backend during cleanup:  %v", cleanupErr)
 			}
@@ -3924,8 +4487,8 @@ func (o *TridentOrchestrator) GetSnapshot(
 
 	defer recordTiming("snapshot_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	return o.getSnapshot(ctx, volumeName, snapshotName)
 }
@@ -4013,7 +4576,10 @@ func (o *TridentOrchestrator) deleteSnapshot(ctx context.Context, snapshotConfig
 	// Note that this call will only return an error if the backend actually
 	// fails to delete the snapshot.  If the snapshot does not exist on the backend,
 	// the driver will not return an error.  Thus, we're fine.
-	if err := backend.DeleteSnapshot(ctx, snapshot.Config, volume.Config); err != nil {
+	err := o.callBackend(ctx, "deleteSnapshot", backend, func() error {
+		return backend.DeleteSnapshot(ctx, snapshot.Config, volume.Config)
+	})
+	if err != nil {
 		Logc(ctx).WithFields(log.Fields{
 			"volume":   snapshot.Config.VolumeName,
 			"snapshot": snapshot.Config.Name,
@@ -4067,6 +4633,9 @@ func (o *TridentOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, sn
 
 	defer recordTiming("snapshot_delete", &err)()
 
+	lockVolumes(ctx, "deleteSnapshot", volumeName)
+	defer unlockVolumes(ctx, "deleteSnapshot", volumeName)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -4158,6 +4727,102 @@ func (o *TridentOrchestrator) DeleteSnapshot(ctx context.Context, volumeName, sn
 	return o.deleteSnapshot(ctx, snapshot.Config)
 }
 
+// RestoreSnapshot reverts a volume, in place, to the contents of one of its snapshots.  The volume should not
+// be in use while it is restored.  Some storage systems discard any snapshots newer than the one restored, so
+// those are removed from Trident as well.
+func (o *TridentOrchestrator) RestoreSnapshot(ctx context.Context, volumeName, snapshotName string) (err error) {
+	if o.bootstrapError != nil {
+		return o.bootstrapError
+	}
+
+	defer recordTiming("snapshot_restore", &err)()
+
+	lockVolumes(ctx, "restoreSnapshot", volumeName)
+	defer unlockVolumes(ctx, "restoreSnapshot", volumeName)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	snapshot, ok := o.snapshots[storage.MakeSnapshotID(volumeName, snapshotName)]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("snapshot %s not found on volume %s", snapshotName, volumeName))
+	}
+
+	volume, ok := o.volumes[volumeName]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	if volume.State.IsDeleting() {
+		return utils.VolumeStateError(fmt.Sprintf("volume %s is deleting", volumeName))
+	}
+	if !volume.State.IsOnline() {
+		return utils.VolumeStateError(fmt.Sprintf("volume %s is %s", volumeName, volume.State))
+	}
+	// Restoring a mounted volume would change its data underneath the workload
+	if len(o.volumePublications.ListPublicationsForVolume(volumeName)) > 0 {
+		return utils.VolumeStateError(fmt.Sprintf("volume %s is published; unpublish it before restoring "+
+			"a snapshot", volumeName))
+	}
+
+	backend, ok := o.backends[volume.BackendUUID]
+	if !ok {
+		return utils.NotFoundError(fmt.Sprintf("backend %s not found", volume.BackendUUID))
+	}
+
+	// The volume is marked as restoring while only the volume lock is held for the restore
+	snapConfig := snapshot.Config
+	volConfig := volume.Config.ConstructClone()
+	volume.State = storage.VolumeStateRestoring
+
+	var backendSnapshots []*storage.Snapshot
+	var listErr error
+	err = o.callBackend(ctx, "restoreSnapshot", backend, func() error {
+		if restoreErr := backend.RestoreSnapshot(ctx, snapConfig, volConfig); restoreErr != nil {
+			return restoreErr
+		}
+		backendSnapshots, listErr = backend.GetSnapshots(ctx, volConfig)
+		return nil
+	})
+	volume.State = storage.VolumeStateOnline
+
+	if err != nil {
+		return fmt.Errorf("failed to restore volume %s from snapshot %s on backend %s: %v",
+			volumeName, snapshotName, backend.Name(), err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":   volumeName,
+		"snapshot": snapshotName,
+	}).Info("Orchestrator restored the volume from a snapshot.")
+
+	// Forget any snapshots that the restore removed from the storage system
+	if listErr != nil {
+		Logc(ctx).WithField("volume", volumeName).WithError(listErr).Warning(
+			"Could not list the volume's snapshots after the restore.")
+		return nil
+	}
+	remaining := make(map[string]bool, len(backendSnapshots))
+	for _, backendSnapshot := range backendSnapshots {
+		remaining[backendSnapshot.Config.Name] = true
+	}
+	for id, knownSnapshot := range o.snapshots {
+		if knownSnapshot.Config.VolumeName != volumeName || remaining[knownSnapshot.Config.Name] {
+			continue
+		}
+		if err = o.deleteSnapshotFromPersistentStoreIgnoreError(ctx, knownSnapshot); err != nil {
+			return err
+		}
+		delete(o.snapshots, id)
+		Logc(ctx).WithFields(log.Fields{
+			"volume":   volumeName,
+			"snapshot": knownSnapshot.Config.Name,
+		}).Info("Removed snapshot discarded by the restore.")
+	}
+
+	return nil
+}
+
 func (o *TridentOrchestrator) ListSnapshots(context.Context) (snapshots []*storage.SnapshotExternal, err error) {
 	if o.bootstrapError != nil {
 		return nil, o.bootstrapError
@@ -4165,8 +4830,8 @@ func (o *TridentOrchestrator) ListSnapshots(context.Context) (snapshots []*stora
 
 	defer recordTiming("snapshot_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	snapshots = make([]*storage.SnapshotExternal, 0, len(o.snapshots))
 	for _, s := range o.snapshots {
@@ -4185,8 +4850,8 @@ func (o *TridentOrchestrator) ListSnapshotsByName(
 
 	defer recordTiming("snapshot_list_by_snapshot_name", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	snapshots = make([]*storage.SnapshotExternal, 0)
 	for _, s := range o.snapshots {
@@ -4207,8 +4872,8 @@ func (o *TridentOrchestrator) ListSnapshotsForVolume(
 
 	defer recordTiming("snapshot_list_by_volume_name", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	if _, ok := o.volumes[volumeName]; !ok {
 		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
@@ -4300,6 +4965,9 @@ func (o *TridentOrchestrator) ResizeVolume(ctx context.Context, volumeName, newS
 
 	defer recordTiming("volume_resize", &err)()
 
+	lockVolumes(ctx, "resizeVolume", volumeName)
+	defer unlockVolumes(ctx, "resizeVolume", volumeName)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -4321,6 +4989,9 @@ func (o *TridentOrchestrator) ResizeVolume(ctx context.Context, volumeName, newS
 	if volume.State.IsDeleting() {
 		return utils.VolumeStateError(fmt.Sprintf("volume %s is deleting", volumeName))
 	}
+	if volume.State.IsRestoring() {
+		return utils.VolumeStateError(fmt.Sprintf("volume %s is being restored", volumeName))
+	}
 
 	// Create a new config for the volume transaction
 	cloneConfig := volume.Config.ConstructClone()
@@ -4364,9 +5035,14 @@ func (o *TridentOrchestrator) resizeVolume(ctx context.Context, volume *storage.
 	}
 
 	if volume.Config.Size != newSize {
-		// If the resize is successful the driver updates the volume.Config.Size, as a side effect, with the actual
-		// byte size of the expanded volume.
-		if err := volumeBackend.ResizeVolume(ctx, volume.Config, newSize); err != nil {
+		// If the resize is successful the driver updates the size in the volume config, as a side effect, with the
+		// actual byte size of the expanded volume.  The driver works on a copy of the volume config, since the
+		// global lock is not held while it does.
+		volConfig := volume.Config.ConstructClone()
+		err := o.callBackend(ctx, "resizeVolume", volumeBackend, func() error {
+			return volumeBackend.ResizeVolume(ctx, volConfig, newSize)
+		})
+		if err != nil {
 			Logc(ctx).WithFields(log.Fields{
 				"volume":          volume.Config.Name,
 				"volume_internal": volume.Config.InternalName,
@@ -4377,6 +5053,7 @@ func (o *TridentOrchestrator) resizeVolume(ctx context.Context, volume *storage.
 			}).Error("Unable to resize the volume.")
 			return fmt.Errorf("unable to resize the volume: %v", err)
 		}
+		volume.Config.Size = volConfig.Size
 	}
 
 	if err := o.updateVolumeOnPersistentStore(ctx, volume); err != nil {
@@ -4474,6 +5151,11 @@ func (o *TridentOrchestrator) getProtocol(
 		{config.Filesystem, config.ReadWriteOnce, config.Block}:       {config.Block, nil},
 		{config.Filesystem, config.ReadWriteOnce, config.BlockOnFile}: {config.BlockOnFile, nil},
 
+		{config.Filesystem, config.ReadWriteOncePod, config.ProtocolAny}: {config.ProtocolAny, nil},
+		{config.Filesystem, config.ReadWriteOncePod, config.File}:        {config.File, nil},
+		{config.Filesystem, config.ReadWriteOncePod, config.Block}:       {config.Block, nil},
+		{config.Filesystem, config.ReadWriteOncePod, config.BlockOnFile}: {config.BlockOnFile, nil},
+
 		{config.Filesystem, config.ReadOnlyMany, config.ProtocolAny}: {config.ProtocolAny, nil},
 		{config.Filesystem, config.ReadOnlyMany, config.File}:        {config.File, nil},
 		{config.Filesystem, config.ReadOnlyMany, config.Block}:       {config.Block, nil},
@@ -4494,6 +5176,11 @@ func (o *TridentOrchestrator) getProtocol(
 		{config.RawBlock, config.ReadWriteOnce, config.Block}:       {config.Block, nil},
 		{config.RawBlock, config.ReadWriteOnce, config.BlockOnFile}: {config.ProtocolAny, err},
 
+		{config.RawBlock, config.ReadWriteOncePod, config.ProtocolAny}: {config.Block, nil},
+		{config.RawBlock, config.ReadWriteOncePod, config.File}:        {config.ProtocolAny, err},
+		{config.RawBlock, config.ReadWriteOncePod, config.Block}:       {config.Block, nil},
+		{config.RawBlock, config.ReadWriteOncePod, config.BlockOnFile}: {config.ProtocolAny, err},
+
 		{config.RawBlock, config.ReadOnlyMany, config.ProtocolAny}: {config.Block, nil},
 		{config.RawBlock, config.ReadOnlyMany, config.File}:        {config.ProtocolAny, err},
 		{config.RawBlock, config.ReadOnlyMany, config.Block}:       {config.Block, nil},
@@ -4562,8 +5249,8 @@ func (o *TridentOrchestrator) GetStorageClass(
 
 	defer recordTiming("storageclass_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	sc, found := o.storageClasses[scName]
 	if !found {
@@ -4583,8 +5270,8 @@ func (o *TridentOrchestrator) ListStorageClasses(ctx context.Context) (
 
 	defer recordTiming("storageclass_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	storageClasses := make([]*storageclass.External, 0, len(o.storageClasses))
 	for _, sc := range o.storageClasses {
@@ -4649,13 +5336,7 @@ func (o *TridentOrchestrator) reconcileNodeAccessOnBackend(ctx context.Context,
 		return nil
 	}
 
-	nodes := make([]*utils.Node, 0)
-
-	for _, n := range o.nodes {
-		nodes = append(nodes, n)
-	}
-
-	return b.ReconcileNodeAccess(ctx, nodes)
+	return b.ReconcileNodeAccess(ctx, o.accessibleNodes())
 }
 
 // safeReconcileNodeAccessOnBackend wraps reconcileNodeAccessOnBackend in a mutex lock for use in functions that aren't
@@ -4718,6 +5399,19 @@ func (o *TridentOrchestrator) AddNode(
 		nodeEventCallback(controllerhelpers.EventTypeNormal, "TridentServiceDiscovery", fmt.Sprintf("%s detected on host.",
 			node.HostInfo.Services))
 	}
+	if node.HostInfo != nil && node.HostInfo.Multipath != nil && !node.HostInfo.Multipath.Valid {
+		issues := make([]string, 0, len(node.HostInfo.Multipath.Issues))
+		for _, issue := range node.HostInfo.Multipath.Issues {
+			issues = append(issues, issue.String())
+		}
+		nodeEventCallback(controllerhelpers.EventTypeWarning, "MultipathConfigInvalid",
+			fmt.Sprintf("Multipath configuration is not as Trident expects: %s.", strings.Join(issues, "; ")))
+	}
+
+	// A fenced node stays fenced when it registers again
+	if existingNode, ok := o.nodes[node.Name]; ok && existingNode.Fenced {
+		node.Fenced = true
+	}
 
 	if err := o.storeClient.AddOrUpdateNode(ctx, node); err != nil {
 		return err
@@ -4743,8 +5437,8 @@ func (o *TridentOrchestrator) GetNode(ctx context.Context, nName string) (node *
 
 	defer recordTiming("node_get", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	node, found := o.nodes[nName]
 	if !found {
@@ -4763,8 +5457,8 @@ func (o *TridentOrchestrator) ListNodes(context.Context) (nodes []*utils.Node, e
 
 	defer recordTiming("node_list", &err)()
 
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	nodes = make([]*utils.Node, 0, len(o.nodes))
 	for _, node := range o.nodes {
@@ -4877,6 +5571,10 @@ func (o *TridentOrchestrator) UpdateVolumePublication(
 
 	defer recordTiming("vol_pub_update", &err)()
 
+	lockNames := o.volumeLockNames(volumeName)
+	lockVolumes(ctx, "updateVolumePublication", lockNames...)
+	defer unlockVolumes(ctx, "updateVolumePublication", lockNames...)
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 	defer o.updateMetrics()
@@ -5117,6 +5815,11 @@ func (o *TridentOrchestrator) deleteVolumePublication(ctx context.Context, volum
 		return fmt.Errorf("unable to remove publication from cache; %v", err)
 	}
 
+	// Docker hosts are not tracked as nodes
+	if config.CurrentDriverContext == config.ContextDocker {
+		return nil
+	}
+
 	node, ok := o.nodes[nodeName]
 	if !ok {
 		return utils.NotFoundError(fmt.Sprintf("node %s not found", nodeName))
@@ -5263,9 +5966,8 @@ func (o *TridentOrchestrator) GetMirrorStatus(
 		return "", o.bootstrapError
 	}
 	defer recordTiming("mirror_status", &err)()
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-	defer o.updateMetrics()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	backend, err := o.getBackendByBackendUUID(backendUUID)
 	if err != nil {
@@ -5283,9 +5985,8 @@ func (o *TridentOrchestrator) CanBackendMirror(_ context.Context, backendUUID st
 		return false, o.bootstrapError
 	}
 	defer recordTiming("mirror_capable", &err)()
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-	defer o.updateMetrics()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	backend, err := o.getBackendByBackendUUID(backendUUID)
 	if err != nil {
@@ -5325,9 +6026,8 @@ func (o *TridentOrchestrator) GetReplicationDetails(
 		return "", "", o.bootstrapError
 	}
 	defer recordTiming("replication_details", &err)()
-	o.mutex.Lock()
-	defer o.mutex.Unlock()
-	defer o.updateMetrics()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
 
 	backend, err := o.getBackendByBackendUUID(backendUUID)
 	if err != nil {
@@ -5340,6 +6040,28 @@ func (o *TridentOrchestrator) GetReplicationDetails(
 	return mirrorBackend.GetReplicationDetails(ctx, localVolumeHandle, remoteVolumeHandle)
 }
 
+// GetMirrorHealth returns the health and time of the last completed transfer of a mirror relationship
+func (o *TridentOrchestrator) GetMirrorHealth(
+	ctx context.Context, backendUUID, localVolumeHandle, remoteVolumeHandle string,
+) (health *storage.MirrorHealth, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+	defer recordTiming("mirror_health", &err)()
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	backend, err := o.getBackendByBackendUUID(backendUUID)
+	if err != nil {
+		return nil, err
+	}
+	mirrorBackend, ok := backend.(storage.Mirrorer)
+	if !ok {
+		return nil, fmt.Errorf("backend does not support mirroring")
+	}
+	return mirrorBackend.GetMirrorHealth(ctx, localVolumeHandle, remoteVolumeHandle)
+}
+
 func (o *TridentOrchestrator) GetCHAP(
 	ctx context.Context, volumeName, nodeName string,
 ) (chapInfo *utils.IscsiChapInfo, err error) {
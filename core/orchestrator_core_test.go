@@ -12,6 +12,7 @@ import (
 	"io"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -64,7 +65,7 @@ type recoveryTest struct {
 	expectDestroy  bool
 }
 
-func cleanup(t *testing.T, o *TridentOrchestrator) {
+func cleanup(t testing.TB, o *TridentOrchestrator) {
 	err := o.storeClient.DeleteBackends(ctx())
 	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
 		t.Fatal("Unable to clean up backends: ", err)
@@ -89,6 +90,16 @@ func cleanup(t *testing.T, o *TridentOrchestrator) {
 	if err != nil && !persistentstore.MatchKeyNotFoundErr(err) {
 		t.Fatal("Unable to clean up snapshots: ", err)
 	}
+	o.stopJobWorkers()
+	jobs, err := o.storeClient.GetJobs(ctx())
+	if err != nil {
+		t.Fatal("Unable to retrieve jobs: ", err)
+	}
+	for _, job := range jobs {
+		if err = o.storeClient.DeleteJob(ctx(), job); err != nil {
+			t.Fatalf("Unable to clean up job %s: %v", job.ID, err)
+		}
+	}
 
 	// Clear the InMemoryClient state so that it looks like we're
 	// bootstrapping afresh next time.
@@ -270,7 +281,7 @@ type storageClassTest struct {
 	expected []*tu.PoolMatch
 }
 
-func getOrchestrator(t *testing.T, monitorTransactions bool) *TridentOrchestrator {
+func getOrchestrator(t testing.TB, monitorTransactions bool) *TridentOrchestrator {
 	var (
 		storeClient persistentstore.Client
 		err         error
@@ -900,6 +911,154 @@ func TestUpdateVolume_LUKSPassphraseNames(t *testing.T) {
 	assert.ErrorIs(t, err, bootstrapError)
 }
 
+func TestAttachVolume_LUKSUnsupported(t *testing.T) {
+	orchestrator := getOrchestrator(t, false)
+	vol := &storage.Volume{
+		Config:      &storage.VolumeConfig{Name: "test-luks-vol", LUKSEncryption: "true"},
+		BackendUUID: "12345",
+	}
+	orchestrator.volumes[vol.Config.Name] = vol
+
+	// Docker cannot supply LUKS passphrases, so the volume is refused before anything is mounted
+	publishInfo := &utils.VolumePublishInfo{FilesystemType: "nfs/ext4", LUKSEncryption: "true"}
+	err := orchestrator.AttachVolume(ctx(), "test-luks-vol", "/mnt/test-luks-vol", publishInfo)
+	assert.True(t, utils.IsUnsupportedError(err))
+}
+
+func TestUpdateVolumeLUKSWrappedKey(t *testing.T) {
+	ctx := context.TODO()
+	orchestrator := getOrchestrator(t, false)
+	vol := &storage.Volume{
+		Config:      &storage.VolumeConfig{Name: "test-kms-vol"},
+		BackendUUID: "12345",
+	}
+	orchestrator.volumes[vol.Config.Name] = vol
+	err := orchestrator.storeClient.AddVolume(ctx, vol)
+	assert.NoError(t, err)
+	defer func() { _ = orchestrator.storeClient.DeleteVolume(ctx, vol) }()
+
+	// Missing key is rejected
+	err = orchestrator.UpdateVolumeLUKSWrappedKey(ctx, "test-kms-vol", &utils.LUKSWrappedKey{}, "")
+	assert.True(t, utils.IsInvalidInputError(err))
+
+	// Unknown volume
+	wrappedKey := &utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "wrapped-1"}
+	err = orchestrator.UpdateVolumeLUKSWrappedKey(ctx, "missing", wrappedKey, "")
+	assert.True(t, utils.IsNotFoundError(err))
+
+	// Revoking a volume without a key fails
+	err = orchestrator.SetVolumeLUKSKeyRevoked(ctx, "test-kms-vol", true)
+	assert.True(t, utils.IsNotFoundError(err))
+
+	// Key recorded in cache and store
+	err = orchestrator.UpdateVolumeLUKSWrappedKey(ctx, "test-kms-vol", wrappedKey, "")
+	assert.NoError(t, err)
+	assert.Equal(t, wrappedKey, orchestrator.volumes["test-kms-vol"].Config.LUKSWrappedKey)
+	storedVol, err := orchestrator.storeClient.GetVolume(ctx, "test-kms-vol")
+	assert.NoError(t, err)
+	assert.Equal(t, wrappedKey, storedVol.Config.LUKSWrappedKey)
+
+	// A key recorded by another node is not replaced by one generated at the same time, nor by a stale rewrap
+	err = orchestrator.UpdateVolumeLUKSWrappedKey(ctx, "test-kms-vol",
+		&utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "wrapped-other"}, "")
+	assert.True(t, utils.IsFoundError(err))
+	err = orchestrator.UpdateVolumeLUKSWrappedKey(ctx, "test-kms-vol",
+		&utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "wrapped-other"}, "wrapped-0")
+	assert.True(t, utils.IsFoundError(err))
+	assert.Equal(t, "wrapped-1", orchestrator.volumes["test-kms-vol"].Config.LUKSWrappedKey.WrappedKey)
+
+	// Revoke the key
+	err = orchestrator.SetVolumeLUKSKeyRevoked(ctx, "test-kms-vol", true)
+	assert.NoError(t, err)
+	assert.True(t, orchestrator.volumes["test-kms-vol"].Config.LUKSWrappedKey.Revoked)
+	assert.False(t, wrappedKey.Revoked, "cached key should not alias the caller's key")
+	storedVol, err = orchestrator.storeClient.GetVolume(ctx, "test-kms-vol")
+	assert.NoError(t, err)
+	assert.True(t, storedVol.Config.LUKSWrappedKey.Revoked)
+
+	// A revoked key cannot be replaced
+	err = orchestrator.UpdateVolumeLUKSWrappedKey(ctx, "test-kms-vol",
+		&utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "wrapped-2"}, "wrapped-1")
+	assert.Error(t, err)
+	assert.Equal(t, "wrapped-1", orchestrator.volumes["test-kms-vol"].Config.LUKSWrappedKey.WrappedKey)
+
+	// Restore the key, after which it may be rewrapped
+	err = orchestrator.SetVolumeLUKSKeyRevoked(ctx, "test-kms-vol", false)
+	assert.NoError(t, err)
+	err = orchestrator.UpdateVolumeLUKSWrappedKey(ctx, "test-kms-vol",
+		&utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "wrapped-2"}, "wrapped-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "wrapped-2", orchestrator.volumes["test-kms-vol"].Config.LUKSWrappedKey.WrappedKey)
+
+	// Bootstrap error
+	orchestrator.bootstrapError = fmt.Errorf("my bootstrap error")
+	err = orchestrator.UpdateVolumeLUKSWrappedKey(ctx, "test-kms-vol", wrappedKey, "wrapped-2")
+	assert.Error(t, err)
+	err = orchestrator.SetVolumeLUKSKeyRevoked(ctx, "test-kms-vol", true)
+	assert.Error(t, err)
+}
+
+func TestUpdateSnapshot_LUKSPassphraseNames(t *testing.T) {
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Positive case: luksPassphraseNames field updated
+	orchestrator := getOrchestrator(t, false)
+	snap := &storage.Snapshot{
+		Config: &storage.SnapshotConfig{
+			Name:                "test-snap",
+			VolumeName:          "test-vol",
+			LUKSPassphraseNames: []string{"B", "A"},
+		},
+	}
+	orchestrator.snapshots[snap.ID()] = snap
+	err := orchestrator.storeClient.AddSnapshot(context.TODO(), snap)
+	assert.NoError(t, err)
+
+	err = orchestrator.UpdateSnapshot(context.TODO(), "test-vol", "test-snap", &[]string{"B"})
+	desiredPassphraseNames := []string{"B"}
+	assert.NoError(t, err)
+	assert.Equal(t, desiredPassphraseNames, orchestrator.snapshots[snap.ID()].Config.LUKSPassphraseNames)
+	// The original object is not modified in place
+	assert.Equal(t, []string{"B", "A"}, snap.Config.LUKSPassphraseNames)
+
+	storedSnap, err := orchestrator.storeClient.GetSnapshot(context.TODO(), "test-vol", "test-snap")
+	assert.NoError(t, err)
+	assert.Equal(t, desiredPassphraseNames, storedSnap.Config.LUKSPassphraseNames)
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Negative case: snapshot not found
+	err = orchestrator.UpdateSnapshot(context.TODO(), "test-vol", "missing-snap", &[]string{"B"})
+	assert.True(t, utils.IsNotFoundError(err))
+
+	err = orchestrator.storeClient.DeleteSnapshot(context.TODO(), snap)
+	assert.NoError(t, err)
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Negative case: failed to update persistence
+	orchestrator = getOrchestrator(t, false)
+	snap = &storage.Snapshot{
+		Config: &storage.SnapshotConfig{
+			Name:                "test-snap",
+			VolumeName:          "test-vol",
+			LUKSPassphraseNames: []string{"B", "A"},
+		},
+	}
+	orchestrator.snapshots[snap.ID()] = snap
+
+	err = orchestrator.UpdateSnapshot(context.TODO(), "test-vol", "test-snap", &[]string{"B"})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"B", "A"}, orchestrator.snapshots[snap.ID()].Config.LUKSPassphraseNames)
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Negative case: bootstrap error
+	orchestrator = getOrchestrator(t, false)
+	bootstrapError := fmt.Errorf("my bootstrap error")
+	orchestrator.bootstrapError = bootstrapError
+
+	err = orchestrator.UpdateSnapshot(context.TODO(), "test-vol", "test-snap", &[]string{"B"})
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, bootstrapError)
+}
+
 func TestCloneVolume_SnapshotDataSource_LUKS(t *testing.T) {
 	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
 	// Positive case: luksPassphraseNames field updated
@@ -3048,7 +3207,9 @@ func TestUpdateVolumePublication_UnpublishFails(t *testing.T) {
 	}
 
 	mockBackend.EXPECT().UnpublishVolume(gomock.Any(), vol.Config, gomock.Any()).Return(fmt.Errorf("error"))
+	orchestrator.mutex.Lock()
 	err := orchestrator.updateVolumePublication(context.Background(), volumeName, nodeName, notSafeToAttach)
+	orchestrator.mutex.Unlock()
 
 	// Try to get the cached publication from the publications map. It should still exist because the delete call failed.
 	cachedPub, found := orchestrator.volumePublications.TryGet(volumeName, nodeName)
@@ -3103,7 +3264,9 @@ func TestUpdateVolumePublication_CleanToDirty(t *testing.T) {
 	// Mock out any clients and make the update publication call.
 	mockStoreClient.EXPECT().UpdateVolumePublication(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 	mockBackend.EXPECT().UnpublishVolume(gomock.Any(), vol.Config, gomock.Any()).Return(nil).Times(1)
+	orchestrator.mutex.Lock()
 	err := orchestrator.updateVolumePublication(context.Background(), volumeName, nodeName, notSafeToAttach)
+	orchestrator.mutex.Unlock()
 	cachedPub := orchestrator.volumePublications.Get(volumeName, nodeName)
 
 	assert.NoError(t, err, "expected no error")
@@ -3152,7 +3315,9 @@ func TestUpdateVolumePublication_CleanToDirtyFails(t *testing.T) {
 
 	mockBackend.EXPECT().UnpublishVolume(gomock.Any(), vol.Config, gomock.Any()).Return(nil)
 	mockStoreClient.EXPECT().UpdateVolumePublication(gomock.Any(), gomock.Any()).Return(errors.New("update publication failed"))
+	orchestrator.mutex.Lock()
 	err := orchestrator.updateVolumePublication(context.Background(), volumeName, nodeName, notSafeToAttach)
+	orchestrator.mutex.Unlock()
 
 	// Try to get the cached publication from the publications map. It should still exist because the update call failed.
 	cachedPub, found := orchestrator.volumePublications.TryGet(volumeName, nodeName)
@@ -3202,7 +3367,9 @@ func TestUpdateVolumePublication_DirtyToClean(t *testing.T) {
 
 	// Test if DeleteVolumePublication fails, that the entire operation fails.
 	mockStoreClient.EXPECT().DeleteVolumePublication(gomock.Any(), gomock.Any()).Return(errors.New("pub not found")).Times(1)
+	orchestrator.mutex.Lock()
 	err := orchestrator.updateVolumePublication(context.Background(), volumeName, nodeName, notSafeToAttach)
+	orchestrator.mutex.Unlock()
 
 	// Try to get the cached publication from the publications map. It should still exist because the delete call failed.
 	cachedPub, found := orchestrator.volumePublications.TryGet(volumeName, nodeName)
@@ -3214,7 +3381,9 @@ func TestUpdateVolumePublication_DirtyToClean(t *testing.T) {
 
 	// Test the happy path for cleaning a publication works and the publication is deleted.
 	mockStoreClient.EXPECT().DeleteVolumePublication(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	orchestrator.mutex.Lock()
 	err = orchestrator.updateVolumePublication(context.Background(), volumeName, nodeName, notSafeToAttach)
+	orchestrator.mutex.Unlock()
 
 	// Try to get the cached publication from the publications map.
 	cachedPub, found = orchestrator.volumePublications.TryGet(volumeName, nodeName)
@@ -4926,6 +5095,7 @@ func TestPublishVolume(t *testing.T) {
 			// Create an instance of the orchestrator
 			o := getOrchestrator(t, false)
 			o.storeClient = mockStoreClient
+			mockBackend.EXPECT().BackendUUID().Return(backendUUID).AnyTimes()
 			o.backends[backendUUID] = mockBackend
 			o.volumes = tr.volumes
 			o.subordinateVolumes = tr.subordinateVolumes
@@ -4938,7 +5108,9 @@ func TestPublishVolume(t *testing.T) {
 			tr.mocks(mockBackend, mockStoreClient, volume)
 
 			// Run the test
+			o.mutex.Lock()
 			err := o.publishVolume(ctx(), tr.volumeName, &utils.VolumePublishInfo{HostName: nodeName})
+			o.mutex.Unlock()
 			if !tr.wantErr(t, err, "Unexpected Result") {
 				return
 			}
@@ -4985,7 +5157,9 @@ func TestPublishVolume_DirtyPublication(t *testing.T) {
 	o.volumes = map[string]*storage.Volume{volumeName: vol}
 	_ = o.volumePublications.Set(pub.VolumeName, pub.NodeName, pub)
 
+	o.mutex.Lock()
 	err := o.publishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: nodeName})
+	o.mutex.Unlock()
 	assert.Error(t, err, "Unexpected success publishing dirty publication")
 }
 
@@ -5278,7 +5452,9 @@ func TestUnpublishVolume(t *testing.T) {
 
 			tr.mocks(mockBackend, mockStoreClient)
 
+			o.mutex.Lock()
 			err := o.unpublishVolume(ctx(), tr.volumeName, tr.nodeName, tr.dirty)
+			o.mutex.Unlock()
 			if !tr.wantErr(t, err, "Unexpected Result") {
 				return
 			}
@@ -6347,7 +6523,9 @@ func TestHandleFailedTranxResizeVolume(t *testing.T) {
 			if tt.name == "ResizeVolumeSuccess" || tt.name == "ResizeVolumeFail" {
 				o.volumes = map[string]*storage.Volume{"fakeVol": vol}
 			}
+			o.mutex.Lock()
 			err := o.handleFailedTransaction(ctx(), svt)
+			o.mutex.Unlock()
 			tt.wantErr(t, err, "Unexpected Result")
 		})
 	}
@@ -6396,6 +6574,7 @@ func TestDeleteSubordinateVolume(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockBackend := mockstorage.NewMockBackend(mockCtrl)
+			mockBackend.EXPECT().BackendUUID().Return(backendUUID).AnyTimes()
 			mockBackend.EXPECT().RemoveVolume(ctx(), gomock.Any()).Return(errors.New("error")).AnyTimes()
 
 			mockStoreClient := mockpersistentstore.NewMockStoreClient(mockCtrl)
@@ -6413,7 +6592,9 @@ func TestDeleteSubordinateVolume(t *testing.T) {
 				o.subordinateVolumes[subVolName] = subordVolume
 			}
 
+			o.mutex.Lock()
 			err := o.deleteSubordinateVolume(ctx(), subVolName)
+			o.mutex.Unlock()
 			tt.wantErr(t, err, "Unexpected result")
 		})
 	}
@@ -6487,6 +6668,7 @@ func TestDeleteVolume(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockBackend := mockstorage.NewMockBackend(mockCtrl)
+			mockBackend.EXPECT().BackendUUID().Return(backendUUID).AnyTimes()
 			mockBackend.EXPECT().RemoveVolume(ctx(), gomock.Any()).Return(errors.New("error")).AnyTimes()
 			mockBackend.EXPECT().BackendUUID().Return(backendUUID).AnyTimes()
 			mockBackend.EXPECT().GetDriverName().Return("baz").AnyTimes()
@@ -6570,7 +6752,7 @@ func TestDeleteVolume(t *testing.T) {
 			mockBackend.EXPECT().Name().Return("mockBackend").AnyTimes()
 			if tt.name == "DeleteBackendError" {
 				mockBackend.EXPECT().HasVolumes().Return(false)
-				mockBackend.EXPECT().State().Return(storage.Deleting)
+				mockBackend.EXPECT().State().Return(storage.Deleting).Times(2)
 			} else {
 				mockBackend.EXPECT().State().Return(storage.Online).AnyTimes()
 			}
@@ -6604,7 +6786,9 @@ func TestDeleteVolume(t *testing.T) {
 				o.volumes[cloneVolName] = cloneVolume
 			}
 
+			o.mutex.Lock()
 			err := o.deleteVolume(ctx(), srcVolName)
+			o.mutex.Unlock()
 			tt.wantErr(t, err, "Unexpected result")
 		})
 	}
@@ -6636,7 +6820,9 @@ func TestHandleFailedDeleteVolumeError(t *testing.T) {
 	o.backends[backendUUID] = mockBackend
 	o.volumes[srcVolName] = sourceVolume
 
+	o.mutex.Lock()
 	err := o.handleFailedTransaction(ctx(), tnx)
+	o.mutex.Unlock()
 	assert.Error(t, err, "failed to delete volume transaction")
 }
 
@@ -6885,6 +7071,7 @@ func TestDeleteSnapshotError(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockBackend := mockstorage.NewMockBackend(mockCtrl)
+			mockBackend.EXPECT().BackendUUID().Return(backendUUID).AnyTimes()
 			mockBackend.EXPECT().DeleteSnapshot(ctx(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 			mockBackend.EXPECT().RemoveVolume(ctx(), gomock.Any()).Return(nil).AnyTimes()
 
@@ -6906,7 +7093,9 @@ func TestDeleteSnapshotError(t *testing.T) {
 				o.volumes[volName] = tt.volume
 			}
 
+			o.mutex.Lock()
 			err := o.deleteSnapshot(ctx(), snapConfig)
+			o.mutex.Unlock()
 			assert.Error(t, err, "Unexpected error")
 		})
 	}
@@ -6924,7 +7113,9 @@ func TestHandleFailedSnapshot(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 	mockBackend := mockstorage.NewMockBackend(mockCtrl)
+	mockBackend.EXPECT().BackendUUID().Return(backendUUID).AnyTimes()
 	mockBackend2 := mockstorage.NewMockBackend(mockCtrl)
+	mockBackend2.EXPECT().BackendUUID().Return("xyz").AnyTimes()
 	mockStoreClient := mockpersistentstore.NewMockStoreClient(mockCtrl)
 
 	o := getOrchestrator(t, false)
@@ -6939,7 +7130,9 @@ func TestHandleFailedSnapshot(t *testing.T) {
 
 	mockBackend.EXPECT().DeleteSnapshot(ctx(), gomock.Any(), gomock.Any()).Return(errors.New("failed to delete snapshot"))
 	mockBackend.EXPECT().Name().Return("abc")
+	o.mutex.Lock()
 	err := o.handleFailedTransaction(ctx(), vt)
+	o.mutex.Unlock()
 	assert.Error(t, err, "Delete volume error")
 
 	delete(o.snapshots, snapID)
@@ -6948,14 +7141,18 @@ func TestHandleFailedSnapshot(t *testing.T) {
 	mockBackend.EXPECT().State().Return(storage.Online)
 	mockBackend.EXPECT().Name().Return("abc")
 	mockBackend.EXPECT().DeleteSnapshot(ctx(), gomock.Any(), gomock.Any()).Return(errors.New("failed to delete snapshot"))
+	o.mutex.Lock()
 	err = o.handleFailedTransaction(ctx(), vt)
+	o.mutex.Unlock()
 	assert.Error(t, err, "Delete snapshot error")
 
 	delete(o.backends, "xyz")
 	mockBackend.EXPECT().State().Return(storage.Online)
 	mockBackend.EXPECT().DeleteSnapshot(ctx(), gomock.Any(), gomock.Any()).Return(nil)
 	mockStoreClient.EXPECT().DeleteVolumeTransaction(ctx(), gomock.Any()).Return(errors.New("failed to delete transaction"))
+	o.mutex.Lock()
 	err = o.handleFailedTransaction(ctx(), vt)
+	o.mutex.Unlock()
 	assert.Error(t, err, "Delete volume transaction error")
 
 	// storage.DeleteSnapshot switch case tests
@@ -6965,7 +7162,9 @@ func TestHandleFailedSnapshot(t *testing.T) {
 	mockBackend.EXPECT().DeleteSnapshot(ctx(), gomock.Any(), gomock.Any()).Return(errors.New("failed to delete snapshot"))
 	mockBackend.EXPECT().Name().Return("abc")
 	mockStoreClient.EXPECT().DeleteVolumeTransaction(ctx(), gomock.Any()).Return(errors.New("failed to delete transaction"))
+	o.mutex.Lock()
 	err = o.handleFailedTransaction(ctx(), vt)
+	o.mutex.Unlock()
 	assert.Error(t, err, "Delete volume transaction error")
 }
 
@@ -7175,3 +7374,276 @@ func TestUpdateBackendByBackendUUID(t *testing.T) {
 		})
 	}
 }
+
+func TestExplainVolumePlacement(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	addSlowBackend(t, o, "backend1", 0)
+	addSlowBackend(t, o, "backend2", 0)
+	addSlowBackend(t, o, "backend3", 0)
+	addBackendOnlyStorageClass(t, o, "sc", "backend1|backend3")
+	if _, err := o.UpdateBackendState(ctx(), "backend3", string(storage.Failed)); err != nil {
+		t.Fatal("Unable to fail backend: ", err)
+	}
+
+	placements, err := o.ExplainVolumePlacement(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []storageclass.PoolPlacement{
+		{Backend: "backend1", Pool: "primary", Accepted: true, Reason: "pool matches storage class"},
+		{Backend: "backend2", Pool: "primary", Reason: "pool is not listed in storagePools"},
+		{Backend: "backend3", Pool: "primary", Reason: "backend is failed"},
+	}, placements)
+
+	// Nothing was provisioned
+	assert.Empty(t, o.volumes)
+
+	volumeConfig := getLockTestVolumeConfig("vol1", "sc")
+	volumeConfig.Protocol = config.Block
+	placements, err = o.ExplainVolumePlacement(ctx(), volumeConfig)
+	if assert.NoError(t, err) && assert.Len(t, placements, 3) {
+		assert.False(t, placements[0].Accepted)
+		assert.Equal(t, "backend protocol file does not match requested protocol block", placements[0].Reason)
+	}
+
+	_, err = o.ExplainVolumePlacement(ctx(), getLockTestVolumeConfig("vol1", "unknown"))
+	assert.True(t, utils.IsNotFoundError(err))
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	backend := addSlowBackend(t, o, "backend", 0)
+	addBackendOnlyStorageClass(t, o, "sc", "backend")
+	vol, err := o.AddVolume(ctx(), getLockTestVolumeConfig("vol1", "sc"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, name := range []string{"snap1", "snap2"} {
+		_, err = o.CreateSnapshot(ctx(), &storage.SnapshotConfig{
+			Version:    config.OrchestratorAPIVersion,
+			Name:       name,
+			VolumeName: "vol1",
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	err = o.RestoreSnapshot(ctx(), "vol1", "missing")
+	assert.True(t, utils.IsNotFoundError(err))
+	err = o.RestoreSnapshot(ctx(), "missing", "snap1")
+	assert.True(t, utils.IsNotFoundError(err))
+
+	// Published volumes are not restored
+	err = o.volumePublications.Set("vol1", "node1", &utils.VolumePublication{
+		Name:       "vol1.node1",
+		VolumeName: "vol1",
+		NodeName:   "node1",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	err = o.RestoreSnapshot(ctx(), "vol1", "snap1")
+	assert.True(t, utils.IsVolumeStateError(err))
+	assert.NoError(t, o.volumePublications.Delete("vol1", "node1"))
+
+	// Simulate a storage system that discards snapshots newer than the one restored
+	driver := backend.Driver().(*fakedriver.StorageDriver)
+	delete(driver.Snapshots[vol.Config.InternalName], "snap2")
+
+	err = o.RestoreSnapshot(ctx(), "vol1", "snap1")
+
+	assert.NoError(t, err)
+	restored, err := o.GetVolume(ctx(), "vol1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, storage.VolumeStateOnline, restored.State)
+	}
+	_, err = o.GetSnapshot(ctx(), "vol1", "snap1")
+	assert.NoError(t, err)
+	_, err = o.GetSnapshot(ctx(), "vol1", "snap2")
+	assert.True(t, utils.IsNotFoundError(err))
+	_, err = o.storeClient.GetSnapshot(ctx(), "vol1", "snap2")
+	assert.Error(t, err)
+}
+
+func TestPublishVolume_SCSIPersistentReservation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockBackend := mockstorage.NewMockBackend(mockCtrl)
+	mockStoreClient := mockpersistentstore.NewMockStoreClient(mockCtrl)
+
+	volumeName := "foo"
+	vol := &storage.Volume{
+		Config:      &storage.VolumeConfig{Name: volumeName, SCSIPersistentReservation: true},
+		BackendUUID: "12345",
+	}
+	pub := &utils.VolumePublication{
+		Name:       utils.GenerateVolumePublishName(volumeName, "node1"),
+		VolumeName: volumeName,
+		NodeName:   "node1",
+	}
+
+	mockBackend.EXPECT().BackendUUID().Return(vol.BackendUUID).AnyTimes()
+	mockBackend.EXPECT().Name().Return("backend").AnyTimes()
+	mockBackend.EXPECT().ReconcileNodeAccess(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockStoreClient.EXPECT().UpdateVolume(gomock.Any(), vol).Return(nil).AnyTimes()
+	mockStoreClient.EXPECT().UpdateVolumePublication(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockStoreClient.EXPECT().AddVolumePublication(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockStoreClient.EXPECT().DeleteVolumePublication(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	orchestrator := getOrchestrator(t, false)
+	orchestrator.storeClient = mockStoreClient
+	orchestrator.backends[vol.BackendUUID] = mockBackend
+	orchestrator.volumes[volumeName] = vol
+	orchestrator.nodes["node1"] = &utils.Node{Name: "node1"}
+	orchestrator.nodes["node2"] = &utils.Node{Name: "node2"}
+	if err := orchestrator.volumePublications.Set(volumeName, "node1", pub); err != nil {
+		t.Fatal("unable to set cache value")
+	}
+	node1Key := utils.PersistentReservationKey("node1")
+
+	// Force detaching a node marks its reservation key to be preempted
+	notSafeToAttach := true
+	mockBackend.EXPECT().UnpublishVolume(gomock.Any(), vol.Config, gomock.Any()).Return(nil)
+	orchestrator.mutex.Lock()
+	err := orchestrator.updateVolumePublication(ctx(), volumeName, "node1", &notSafeToAttach)
+	orchestrator.mutex.Unlock()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{node1Key}, vol.Config.AccessInfo.PreemptReservationKeys)
+
+	// Other nodes are told their own key and the keys to preempt
+	mockBackend.EXPECT().PublishVolume(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo) error {
+			assert.True(t, publishInfo.SCSIPersistentReservation)
+			assert.Equal(t, utils.PersistentReservationKey("node2"), publishInfo.ReservationKey)
+			assert.Equal(t, []string{node1Key}, publishInfo.PreemptReservationKeys)
+			return nil
+		})
+	err = orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: "node2"})
+	assert.NoError(t, err)
+
+	// Once the node is cleaned up and the volume is published to it again, its key is no longer preempted
+	notSafeToAttach = false
+	orchestrator.mutex.Lock()
+	err = orchestrator.updateVolumePublication(ctx(), volumeName, "node1", &notSafeToAttach)
+	orchestrator.mutex.Unlock()
+	assert.NoError(t, err)
+	mockBackend.EXPECT().PublishVolume(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	err = orchestrator.PublishVolume(ctx(), volumeName, &utils.VolumePublishInfo{HostName: "node1"})
+	assert.NoError(t, err)
+	assert.Empty(t, vol.Config.AccessInfo.PreemptReservationKeys)
+}
+
+// fakeReservationPreempter is a frontend that records the reservation keys it is asked to preempt, failing the
+// first few requests.
+type fakeReservationPreempter struct {
+	mutex     sync.Mutex
+	preempted map[string][]string
+	attempts  int
+	failures  int
+}
+
+func (f *fakeReservationPreempter) Activate() error   { return nil }
+func (f *fakeReservationPreempter) Deactivate() error { return nil }
+func (f *fakeReservationPreempter) GetName() string   { return "fake" }
+func (f *fakeReservationPreempter) Version() string   { return "1" }
+
+func (f *fakeReservationPreempter) PreemptReservationKey(
+	_ context.Context, volumeName string, node *utils.Node, reservationKey string,
+) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.attempts++
+	if f.attempts <= f.failures {
+		return fmt.Errorf("failed")
+	}
+	f.preempted[node.Name] = append(f.preempted[node.Name], volumeName+"/"+reservationKey)
+	return nil
+}
+
+func (f *fakeReservationPreempter) preemptedKeys() (map[string][]string, int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	preempted := make(map[string][]string, len(f.preempted))
+	for nodeName, keys := range f.preempted {
+		preempted[nodeName] = append([]string(nil), keys...)
+	}
+	return preempted, f.attempts
+}
+
+func TestUpdateVolumePublication_PreemptsReservationKey(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockBackend := mockstorage.NewMockBackend(mockCtrl)
+	mockStoreClient := mockpersistentstore.NewMockStoreClient(mockCtrl)
+
+	volumeName := "foo"
+	vol := &storage.Volume{
+		Config:      &storage.VolumeConfig{Name: volumeName, SCSIPersistentReservation: true},
+		BackendUUID: "12345",
+	}
+
+	mockBackend.EXPECT().BackendUUID().Return(vol.BackendUUID).AnyTimes()
+	mockBackend.EXPECT().Name().Return("backend").AnyTimes()
+	mockBackend.EXPECT().UnpublishVolume(gomock.Any(), vol.Config, gomock.Any()).Return(nil).AnyTimes()
+	mockStoreClient.EXPECT().UpdateVolume(gomock.Any(), vol).Return(nil).AnyTimes()
+	mockStoreClient.EXPECT().UpdateVolumePublication(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	defer func(initial, maxElapsed time.Duration) {
+		reservationPreemptInitialInterval, reservationPreemptMaxElapsedTime = initial, maxElapsed
+	}(reservationPreemptInitialInterval, reservationPreemptMaxElapsedTime)
+	reservationPreemptInitialInterval = time.Millisecond
+	reservationPreemptMaxElapsedTime = 10 * time.Second
+
+	preempter := &fakeReservationPreempter{preempted: make(map[string][]string), failures: 2}
+	orchestrator := getOrchestrator(t, false)
+	orchestrator.storeClient = mockStoreClient
+	orchestrator.backends[vol.BackendUUID] = mockBackend
+	orchestrator.volumes[volumeName] = vol
+	orchestrator.frontends["fake"] = preempter
+	for _, nodeName := range []string{"node1", "node2", "node3"} {
+		orchestrator.nodes[nodeName] = &utils.Node{Name: nodeName}
+		pub := &utils.VolumePublication{
+			Name:       utils.GenerateVolumePublishName(volumeName, nodeName),
+			VolumeName: volumeName,
+			NodeName:   nodeName,
+		}
+		if err := orchestrator.volumePublications.Set(volumeName, nodeName, pub); err != nil {
+			t.Fatal("unable to set cache value")
+		}
+	}
+	orchestrator.nodes["node3"].Fenced = true
+
+	// Force detach saves the key and succeeds without waiting for the preempt
+	notSafeToAttach := true
+	orchestrator.mutex.Lock()
+	err := orchestrator.updateVolumePublication(ctx(), volumeName, "node1", &notSafeToAttach)
+	orchestrator.mutex.Unlock()
+	assert.NoError(t, err)
+	pub, _ := orchestrator.volumePublications.TryGet(volumeName, "node1")
+	assert.True(t, pub.NotSafeToAttach)
+	assert.Equal(t, []string{utils.PersistentReservationKey("node1")}, vol.Config.AccessInfo.PreemptReservationKeys)
+
+	// A node still sharing the volume preempts the force-detached node's key, retrying until it succeeds; fenced
+	// nodes are not asked
+	expected := map[string][]string{"node2": {volumeName + "/" + utils.PersistentReservationKey("node1")}}
+	assert.Eventually(t, func() bool {
+		preempted, attempts := preempter.preemptedKeys()
+		return attempts == 3 && assert.ObjectsAreEqual(expected, preempted)
+	}, 10*time.Second, time.Millisecond)
+
+	// Force-detached nodes are not asked either, so the key is left for the next node to stage the volume
+	orchestrator.mutex.Lock()
+	err = orchestrator.updateVolumePublication(ctx(), volumeName, "node2", &notSafeToAttach)
+	orchestrator.mutex.Unlock()
+	assert.NoError(t, err)
+	preempted, attempts := preempter.preemptedKeys()
+	assert.Equal(t, expected, preempted)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []string{utils.PersistentReservationKey("node1"), utils.PersistentReservationKey("node2")},
+		vol.Config.AccessInfo.PreemptReservationKeys)
+}
@@ -16,8 +16,43 @@ import (
 const (
 	txnMonitorPeriod = 60 * time.Minute
 	txnMonitorMaxAge = 24 * time.Hour
+
+	// Synchronous operations hold the locks of the objects they change until they finish, so their
+	// transactions only outlive them if the operation's own cleanup failed.
+	txnMonitorSyncMaxAge    = 10 * time.Minute
+	txnMonitorUpgradeMaxAge = 60 * time.Minute
+)
+
+// txnRecoveryAction is what the transaction monitor does with an abandoned transaction.
+type txnRecoveryAction string
+
+const (
+	// txnRecoveryRetry repeats the operation until it completes
+	txnRecoveryRetry txnRecoveryAction = "retry"
+	// txnRecoveryRollBack undoes whatever part of the operation was done
+	txnRecoveryRollBack txnRecoveryAction = "rollBack"
+	// txnRecoveryMarkFailed leaves the transaction in place, marked as failed, for an administrator to resolve
+	txnRecoveryMarkFailed txnRecoveryAction = "markFailed"
 )
 
+type txnMonitorPolicy struct {
+	maxAge time.Duration
+	action txnRecoveryAction
+}
+
+// txnMonitorPolicies defines how long each type of transaction may run and how it is recovered once it is
+// abandoned.  Retries and rollbacks are done by handleFailedTransaction, just as they are during bootstrap.
+// Long-running volume creations are governed by the maximum age given to the transaction monitor.
+var txnMonitorPolicies = map[storage.VolumeOperation]txnMonitorPolicy{
+	storage.AddVolume:      {maxAge: txnMonitorSyncMaxAge, action: txnRecoveryRollBack},
+	storage.ImportVolume:   {maxAge: txnMonitorSyncMaxAge, action: txnRecoveryRollBack},
+	storage.AddSnapshot:    {maxAge: txnMonitorSyncMaxAge, action: txnRecoveryRollBack},
+	storage.DeleteVolume:   {maxAge: txnMonitorSyncMaxAge, action: txnRecoveryRetry},
+	storage.DeleteSnapshot: {maxAge: txnMonitorSyncMaxAge, action: txnRecoveryRetry},
+	storage.ResizeVolume:   {maxAge: txnMonitorSyncMaxAge, action: txnRecoveryRetry},
+	storage.UpgradeVolume:  {maxAge: txnMonitorUpgradeMaxAge, action: txnRecoveryMarkFailed},
+}
+
 // StartTransactionMonitor starts the thread that reaps abandoned long-running transactions.
 func (o *TridentOrchestrator) StartTransactionMonitor(
 	ctx context.Context, txnPeriod, txnMaxAge time.Duration,
@@ -62,31 +97,37 @@ func (o *TridentOrchestrator) checkLongRunningTransactions(ctx context.Context,
 		return
 	}
 
+	o.mutex.RLock()
 	txns, err := o.storeClient.GetVolumeTransactions(ctx)
+	o.mutex.RUnlock()
 	if err != nil {
 		if !persistentstore.MatchKeyNotFoundErr(err) {
 			Logc(ctx).WithField("error", err).Errorf("Could not read transactions.")
 		}
 		return
 	}
-	log.Debugf("Transaction monitor found %d long-running transaction(s).", len(txns))
-
-	// Build map of long-running transactions
-	txnMap := make(map[*storage.VolumeTransaction]time.Time)
+	log.Debugf("Transaction monitor found %d transaction(s).", len(txns))
 
 	for _, txn := range txns {
-		switch txn.Op {
-		case storage.VolumeCreating:
-			txnMap[txn] = txn.VolumeCreatingConfig.StartTime
-		default:
+
+		// Failed transactions are left for an administrator
+		if txn.Failed {
 			continue
 		}
-	}
 
-	// Reap each long-running transaction that has expired
-	for txn, startTime := range txnMap {
+		policy := getTxnMonitorPolicy(txn.Op, txnMaxAge)
+
+		// Transactions recorded before start times were, or with an unknown operation, are timed from now
+		startTime := txn.StartTime
+		if startTime.IsZero() && txn.Op == storage.VolumeCreating {
+			startTime = txn.VolumeCreatingConfig.StartTime
+		}
+		if startTime.IsZero() || policy == nil {
+			o.recordTransactionStartTime(ctx, txn, startTime)
+			continue
+		}
 
-		expirationTime := startTime.Add(txnMaxAge)
+		expirationTime := startTime.Add(policy.maxAge)
 
 		Logc(ctx).WithFields(log.Fields{
 			"started": startTime,
@@ -96,60 +137,150 @@ func (o *TridentOrchestrator) checkLongRunningTransactions(ctx context.Context,
 		}).Debug("Transaction monitor checking transaction.")
 
 		if expirationTime.Before(time.Now()) {
-			o.reapLongRunningTransaction(ctx, txn)
+			o.reapLongRunningTransaction(ctx, txn, policy.action)
 		}
 	}
 }
 
-// reapLongRunningTransaction cleans up any transactions that have expired so that any
-// storage resources associated with them are not orphaned indefinitely.
-func (o *TridentOrchestrator) reapLongRunningTransaction(ctx context.Context, txn *storage.VolumeTransaction) {
+// getTxnMonitorPolicy returns the transaction monitor's policy for an operation, or nil if it has none.
+func getTxnMonitorPolicy(op storage.VolumeOperation, txnMaxAge time.Duration) *txnMonitorPolicy {
+	if op == storage.VolumeCreating {
+		return &txnMonitorPolicy{maxAge: txnMaxAge, action: txnRecoveryRollBack}
+	}
+	if policy, ok := txnMonitorPolicies[op]; ok {
+		return &policy
+	}
+	return nil
+}
+
+// recordTransactionStartTime saves a start time for a transaction that lacks one, so that its age may be
+// tracked.  If no start time is known, the transaction is considered to have started now.
+func (o *TridentOrchestrator) recordTransactionStartTime(
+	ctx context.Context, txn *storage.VolumeTransaction, startTime time.Time,
+) {
+	if !txn.StartTime.IsZero() {
+		Logc(ctx).WithFields(log.Fields{
+			"op":   txn.Op,
+			"name": txn.Name(),
+		}).Warning("Transaction monitor found transaction with unknown operation.")
+		return
+	}
+	if startTime.IsZero() {
+		startTime = time.Now()
+	}
+
+	lockVolumes(ctx, "recordTransactionStartTime", getTransactionVolumeName(txn))
+	defer unlockVolumes(ctx, "recordTransactionStartTime", getTransactionVolumeName(txn))
+
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
 
-	Logc(ctx).WithFields(log.Fields{
-		"op":   txn.Op,
-		"name": txn.Name(),
-	}).Debug("Transaction monitor reaping transaction.")
+	existingTxn, err := o.storeClient.GetExistingVolumeTransaction(ctx, txn)
+	if err != nil || existingTxn == nil || existingTxn.Op != txn.Op || !existingTxn.StartTime.IsZero() {
+		return
+	}
+	existingTxn.StartTime = startTime
+	if err = o.storeClient.UpdateVolumeTransaction(ctx, existingTxn); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"op":   txn.Op,
+			"name": txn.Name(),
+		}).WithError(err).Error("Could not record transaction start time.")
+	}
+}
 
-	// Clean up any resources associated with the transaction.
+// getTransactionVolumeName returns the name of the volume whose lock guards a transaction.
+func getTransactionVolumeName(txn *storage.VolumeTransaction) string {
 	switch txn.Op {
+	case storage.AddSnapshot, storage.DeleteSnapshot:
+		return txn.SnapshotConfig.VolumeName
 	case storage.VolumeCreating:
+		return txn.VolumeCreatingConfig.Name
+	default:
+		return txn.Config.Name
+	}
+}
 
-		// If the volume was somehow fully created and the transaction was left around, don't delete the volume!
-		if _, found := o.volumes[txn.VolumeCreatingConfig.Name]; found {
+// reapLongRunningTransaction recovers a transaction that has expired, so that the operation it records
+// does not remain incomplete, and any storage resources associated with it are not orphaned indefinitely.
+func (o *TridentOrchestrator) reapLongRunningTransaction(
+	ctx context.Context, txn *storage.VolumeTransaction, action txnRecoveryAction,
+) {
+	// Wait for any in-flight operation on the volume, which may complete the transaction
+	volumeName := getTransactionVolumeName(txn)
+	lockVolumes(ctx, "reapTransaction", volumeName)
+	defer unlockVolumes(ctx, "reapTransaction", volumeName)
 
-			Logc(ctx).WithFields(log.Fields{
-				"volume": txn.VolumeCreatingConfig.Name,
-			}).Warning("Volume for expired transaction is known to Trident and will not be reaped.")
-			break
-		}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
 
-		// Get the backend where this abandoned volume may still exist
-		backend, found := o.backends[txn.VolumeCreatingConfig.BackendUUID]
-		if !found {
+	logFields := log.Fields{
+		"op":     txn.Op,
+		"name":   txn.Name(),
+		"action": action,
+	}
 
-			Logc(ctx).WithFields(log.Fields{
-				"backendUUID": txn.VolumeCreatingConfig.BackendUUID,
-				"volume":      txn.VolumeCreatingConfig.Name,
-			}).Error("Backend for expired transaction not found. Volume may have to be removed manually.")
-			break
+	// The operation may have completed the transaction while the monitor was waiting
+	existingTxn, err := o.storeClient.GetExistingVolumeTransaction(ctx, txn)
+	if err != nil || existingTxn == nil || existingTxn.Op != txn.Op || existingTxn.Failed {
+		Logc(ctx).WithFields(logFields).Debug("Transaction monitor found transaction already completed.")
+		return
+	}
+
+	Logc(ctx).WithFields(logFields).Info("Transaction monitor recovering abandoned transaction.")
+
+	switch {
+	case existingTxn.Op == storage.VolumeCreating:
+		o.reapVolumeCreatingTransaction(ctx, existingTxn)
+
+	case action == txnRecoveryMarkFailed:
+		existingTxn.Failed = true
+		if err = o.storeClient.UpdateVolumeTransaction(ctx, existingTxn); err != nil {
+			Logc(ctx).WithFields(logFields).WithError(err).Error("Could not mark transaction as failed.")
+			return
 		}
+		Logc(ctx).WithFields(logFields).Errorf("Transaction did not complete and must be resolved manually.")
+
+	default:
+		// The transaction is deleted once recovered; if recovery fails, it is left to be tried again later.
+		if err = o.handleFailedTransaction(ctx, existingTxn); err != nil {
+			Logc(ctx).WithFields(logFields).WithError(err).Error("Could not recover abandoned transaction.")
+			return
+		}
+		Logc(ctx).WithFields(logFields).Info("Transaction monitor recovered abandoned transaction.")
+	}
+}
+
+// reapVolumeCreatingTransaction deletes the volume of an expired long-running volume creation, along with
+// its transaction.
+func (o *TridentOrchestrator) reapVolumeCreatingTransaction(ctx context.Context, txn *storage.VolumeTransaction) {
+	// If the volume was somehow fully created and the transaction was left around, don't delete the volume!
+	if _, found := o.volumes[txn.VolumeCreatingConfig.Name]; found {
+
+		Logc(ctx).WithFields(log.Fields{
+			"volume": txn.VolumeCreatingConfig.Name,
+		}).Warning("Volume for expired transaction is known to Trident and will not be reaped.")
+
+	} else if backend, found := o.backends[txn.VolumeCreatingConfig.BackendUUID]; !found {
+
+		Logc(ctx).WithFields(log.Fields{
+			"backendUUID": txn.VolumeCreatingConfig.BackendUUID,
+			"volume":      txn.VolumeCreatingConfig.Name,
+		}).Error("Backend for expired transaction not found. Volume may have to be removed manually.")
+
+	} else {
 
 		// Delete the volume.  This should be safe since the transaction was left around and Trident doesn't
 		// know anything about the volume.
-		if err := backend.RemoveVolume(ctx, &txn.VolumeCreatingConfig.VolumeConfig); err != nil {
-
+		err := o.callBackend(ctx, "reapTransaction", backend, func() error {
+			return backend.RemoveVolume(ctx, &txn.VolumeCreatingConfig.VolumeConfig)
+		})
+		if err != nil {
 			Logc(ctx).WithFields(log.Fields{
 				"backendUUID": txn.VolumeCreatingConfig.BackendUUID,
 				"volume":      txn.VolumeCreatingConfig.Name,
 				"error":       err,
 			}).Error("Volume for expired transaction not deleted. Volume may have to be removed manually.")
-			break
 		}
-
-	default:
-		break
 	}
 
 	// Delete the transaction record in all cases.
@@ -11,6 +11,7 @@ import (
 
 	"github.com/netapp/trident/config"
 	persistentstore "github.com/netapp/trident/persistent_store"
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/storage/fake"
 	sa "github.com/netapp/trident/storage_attribute"
 	storageclass "github.com/netapp/trident/storage_class"
@@ -263,6 +264,77 @@ func TestVolumeCreatingTwoTransactions(t *testing.T) {
 	assert.Equal(t, volName02, volTxns[0].VolumeCreatingConfig.InternalName, "failed to find matching transaction")
 }
 
+// TestRecoverAbandonedTransactions tests that expired transactions of synchronous operations are recovered
+// according to the monitor's policy for each operation, and that other transactions are left alone.
+func TestRecoverAbandonedTransactions(t *testing.T) {
+	o, storeClient := setupOrchestratorAndBackend(t, false)
+
+	for _, name := range []string{"deleted", "resized", "fresh"} {
+		_, err := o.AddVolume(ctx(), tu.GenerateVolumeConfig(name, 1, "slow", config.File))
+		assert.NoError(t, err)
+	}
+	volume, err := o.GetVolume(ctx(), "resized")
+	assert.NoError(t, err)
+
+	expired := time.Now().Add(-2 * txnMonitorUpgradeMaxAge)
+	resizeConfig := volume.Config.ConstructClone()
+	resizeConfig.Size = "2147483648"
+	for _, txn := range []*storage.VolumeTransaction{
+		{Config: &storage.VolumeConfig{Name: "added"}, Op: storage.AddVolume, StartTime: expired},
+		{Config: &storage.VolumeConfig{Name: "deleted"}, Op: storage.DeleteVolume, StartTime: expired},
+		{Config: resizeConfig, Op: storage.ResizeVolume, StartTime: expired},
+		{Config: &storage.VolumeConfig{Name: "upgraded"}, Op: storage.UpgradeVolume, StartTime: expired},
+		{Config: &storage.VolumeConfig{Name: "fresh"}, Op: storage.DeleteVolume, StartTime: time.Now()},
+	} {
+		assert.NoError(t, storeClient.AddVolumeTransaction(ctx(), txn))
+	}
+
+	o.checkLongRunningTransactions(ctx(), maxAge)
+
+	// The abandoned add was rolled back, and the abandoned delete and resize were retried
+	_, err = o.GetVolume(ctx(), "deleted")
+	assert.True(t, utils.IsNotFoundError(err))
+	volume, err = o.GetVolume(ctx(), "resized")
+	assert.NoError(t, err)
+	assert.Equal(t, "2147483648", volume.Config.Size)
+	_, err = o.GetVolume(ctx(), "fresh")
+	assert.NoError(t, err)
+
+	// The abandoned upgrade is marked failed, and the fresh transaction is untouched
+	txns, err := o.ListVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	if assert.Len(t, txns, 2) {
+		assert.Equal(t, "upgraded", txns[0].Name)
+		assert.True(t, txns[0].Failed)
+		assert.GreaterOrEqual(t, txns[0].AgeSeconds, int64(2*txnMonitorUpgradeMaxAge.Seconds()))
+		assert.Equal(t, "fresh", txns[1].Name)
+		assert.False(t, txns[1].Failed)
+	}
+}
+
+// TestRecordTransactionStartTime tests that transactions without a start time are timed from when the
+// monitor first sees them.
+func TestRecordTransactionStartTime(t *testing.T) {
+	o, storeClient := setupOrchestratorAndBackend(t, false)
+
+	txn := &storage.VolumeTransaction{Config: &storage.VolumeConfig{Name: "vol1"}, Op: storage.DeleteVolume}
+	assert.NoError(t, storeClient.AddVolumeTransaction(ctx(), txn))
+
+	txns, err := o.ListVolumeTransactions(ctx())
+	assert.NoError(t, err)
+	if assert.Len(t, txns, 1) {
+		assert.Empty(t, txns[0].StartTime)
+	}
+
+	o.checkLongRunningTransactions(ctx(), maxAge)
+
+	txn, err = storeClient.GetExistingVolumeTransaction(ctx(), txn)
+	assert.NoError(t, err)
+	if assert.NotNil(t, txn) {
+		assert.WithinDuration(t, time.Now(), txn.StartTime, time.Minute)
+	}
+}
+
 func setupOrchestratorAndBackend(t *testing.T, monitorTransactions bool) (*TridentOrchestrator, *persistentstore.InMemoryClient) {
 	storeClient := persistentstore.NewInMemoryClient()
 	o := NewTridentOrchestrator(storeClient)
@@ -6,6 +6,7 @@ package core
 
 import (
 	"context"
+	"time"
 
 	"github.com/netapp/trident/frontend"
 	"github.com/netapp/trident/storage"
@@ -37,7 +38,15 @@ type Orchestrator interface {
 	RemoveBackendConfigRef(ctx context.Context, backendUUID, configRef string) (err error)
 
 	AddVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
+	ExplainVolumePlacement(ctx context.Context, volumeConfig *storage.VolumeConfig) (
+		[]storageclass.PoolPlacement, error,
+	)
 	UpdateVolume(ctx context.Context, volume string, passphraseNames *[]string) error
+	UpdateVolumeLUKSWrappedKey(
+		ctx context.Context, volume string, wrappedKey *utils.LUKSWrappedKey, previousWrappedKey string,
+	) error
+	SetVolumeLUKSKeyRevoked(ctx context.Context, volume string, revoked bool) error
+	PromoteVolumeReplica(ctx context.Context, volumeName string) (*storage.VolumeExternal, error)
 	AttachVolume(ctx context.Context, volumeName, mountpoint string, publishInfo *utils.VolumePublishInfo) error
 	CloneVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) (*storage.VolumeExternal, error)
 	DetachVolume(ctx context.Context, volumeName, mountpoint string) error
@@ -53,6 +62,7 @@ type Orchestrator interface {
 	ListVolumes(ctx context.Context) ([]*storage.VolumeExternal, error)
 	PublishVolume(ctx context.Context, volumeName string, publishInfo *utils.VolumePublishInfo) error
 	UnpublishVolume(ctx context.Context, volumeName, nodeName string) error
+	TakeOverVolume(ctx context.Context, volumeName, hostName string) error
 	ResizeVolume(ctx context.Context, volumeName, newSize string) error
 	SetVolumeState(ctx context.Context, volumeName string, state storage.VolumeState) error
 	ReloadVolumes(ctx context.Context) error
@@ -67,6 +77,8 @@ type Orchestrator interface {
 	ListSnapshotsForVolume(ctx context.Context, volumeName string) ([]*storage.SnapshotExternal, error)
 	ReadSnapshotsForVolume(ctx context.Context, volumeName string) ([]*storage.SnapshotExternal, error)
 	DeleteSnapshot(ctx context.Context, volumeName, snapshotName string) error
+	RestoreSnapshot(ctx context.Context, volumeName, snapshotName string) error
+	UpdateSnapshot(ctx context.Context, volumeName, snapshotName string, passphraseNames *[]string) error
 
 	AddStorageClass(ctx context.Context, scConfig *storageclass.Config) (*storageclass.External, error)
 	DeleteStorageClass(ctx context.Context, scName string) error
@@ -77,6 +89,8 @@ type Orchestrator interface {
 	GetNode(ctx context.Context, nName string) (*utils.Node, error)
 	ListNodes(ctx context.Context) ([]*utils.Node, error)
 	DeleteNode(ctx context.Context, nName string) error
+	FenceNode(ctx context.Context, nodeName string, nodeEventCallback NodeEventCallback) error
+	UnfenceNode(ctx context.Context, nodeName string, nodeEventCallback NodeEventCallback) error
 	PeriodicallyReconcileNodeAccessOnBackends()
 
 	AddVolumePublication(ctx context.Context, vp *utils.VolumePublication) error
@@ -94,6 +108,12 @@ type Orchestrator interface {
 	AddVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error
 	GetVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) (*storage.VolumeTransaction, error)
 	DeleteVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error
+	ListVolumeTransactions(ctx context.Context) ([]*storage.VolumeTransactionExternal, error)
+
+	SubmitVolumeJob(ctx context.Context, volumeConfig *storage.VolumeConfig) (*storage.JobExternal, error)
+	WaitForJob(ctx context.Context, jobID string, timeout time.Duration) (*storage.JobExternal, error)
+	GetJob(ctx context.Context, jobID string) (*storage.JobExternal, error)
+	ListJobs(ctx context.Context) ([]*storage.JobExternal, error)
 
 	EstablishMirror(
 		ctx context.Context, backendUUID, localVolumeHandle, remoteVolumeHandle, replicationPolicy,
@@ -112,6 +132,9 @@ type Orchestrator interface {
 	GetReplicationDetails(
 		ctx context.Context, backendUUID, localVolumeHandle, remoteVolumeHandle string,
 	) (string, string, error)
+	GetMirrorHealth(
+		ctx context.Context, backendUUID, localVolumeHandle, remoteVolumeHandle string,
+	) (*storage.MirrorHealth, error)
 
 	GetCHAP(ctx context.Context, volumeName, nodeName string) (*utils.IscsiChapInfo, error)
 }
@@ -120,3 +143,9 @@ type (
 	VolumeCallback    func(*storage.VolumeExternal, string) error
 	NodeEventCallback func(eventType, reason, message string)
 )
+
+// ReservationPreempter is implemented by frontends that can reach the nodes sharing a volume.  The orchestrator
+// uses it to have one of them preempt the SCSI persistent reservation key of a node force detached from the volume.
+type ReservationPreempter interface {
+	PreemptReservationKey(ctx context.Context, volumeName string, node *utils.Node, reservationKey string) error
+}
@@ -0,0 +1,312 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/utils"
+)
+
+const replicaVolumeSuffix = "-replica"
+
+// addVolumeReplica creates a mirror destination for a newly created volume in a topology zone other than
+// the one hosting the volume, and establishes the mirror relationship between the two.  If the replica
+// cannot be created, the primary volume is deleted so that the request may be retried.
+// This method should only be called from addVolume, as it does not take locks.
+func (o *TridentOrchestrator) addVolumeReplica(
+	ctx context.Context, volumeName string, sc *storageclass.StorageClass,
+) (*storage.VolumeExternal, error) {
+	primary := o.volumes[volumeName]
+	primaryBackend := o.backends[primary.BackendUUID]
+	replicaName := volumeName + replicaVolumeSuffix
+
+	logFields := log.Fields{
+		"volume":       volumeName,
+		"replica":      replicaName,
+		"storageClass": sc.GetName(),
+	}
+	Logc(ctx).WithFields(logFields).Debug("Creating cross-zone replica.")
+
+	// Undo the primary (and replica, if any) on failure
+	fail := func(err error) (*storage.VolumeExternal, error) {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Could not create cross-zone replica.")
+		if _, ok := o.volumes[replicaName]; ok {
+			if deleteErr := o.deleteVolume(ctx, replicaName); deleteErr != nil {
+				Logc(ctx).WithFields(logFields).WithError(deleteErr).Warning("Could not delete replica volume.")
+			}
+		}
+		if deleteErr := o.deleteVolume(ctx, volumeName); deleteErr != nil {
+			Logc(ctx).WithFields(logFields).WithError(deleteErr).Warning("Could not delete primary volume.")
+		}
+		return nil, fmt.Errorf("could not create cross-zone replica for volume %s; %v", volumeName, err)
+	}
+
+	if !primaryBackend.CanMirror() {
+		return fail(fmt.Errorf("backend %s does not support mirroring", primaryBackend.Name()))
+	}
+	if primary.Config.MirrorHandle == "" {
+		return fail(fmt.Errorf("volume %s has no mirror handle", volumeName))
+	}
+	if _, ok := o.volumes[replicaName]; ok {
+		return fail(fmt.Errorf("volume %s already exists", replicaName))
+	}
+
+	var primaryTopologies []map[string]string
+	if pool, ok := primaryBackend.Storage()[primary.Pool]; ok {
+		primaryTopologies = pool.SupportedTopologies()
+	}
+	if len(primaryTopologies) == 0 {
+		return fail(fmt.Errorf("storage pool %s of backend %s is not limited to a topology zone",
+			primary.Pool, primaryBackend.Name()))
+	}
+
+	// Find mirroring pools that share no topology with the primary's pool
+	protocol := primary.Config.Protocol
+	pools := make([]storage.Pool, 0)
+	for _, pool := range sc.GetStoragePoolsForProtocolByBackend(ctx, protocol, nil, nil, primary.Config.AccessMode) {
+		if pool.Backend().CanMirror() {
+			pools = append(pools, pool)
+		}
+	}
+	pools = storageclass.FilterPoolsOutsideTopology(ctx, pools, primaryTopologies)
+	if len(pools) == 0 {
+		return fail(fmt.Errorf("no mirroring storage pool in storage class %s is outside the topology of "+
+			"storage pool %s", sc.GetName(), primary.Pool))
+	}
+
+	replicaConfig := primary.Config.ConstructClone()
+	replicaConfig.Name = replicaName
+	replicaConfig.InternalName = ""
+	replicaConfig.InternalID = ""
+	replicaConfig.MirrorHandle = ""
+	replicaConfig.AccessInfo = utils.VolumeAccessInfo{}
+	replicaConfig.RequisiteTopologies = nil
+	replicaConfig.PreferredTopologies = nil
+	replicaConfig.AllowedTopologies = nil
+	replicaConfig.CloneSourceVolume = ""
+	replicaConfig.CloneSourceVolumeInternal = ""
+	replicaConfig.CloneSourceSnapshot = ""
+	replicaConfig.SubordinateVolumes = nil
+	replicaConfig.IsMirrorDestination = true
+	replicaConfig.PeerVolumeHandle = primary.Config.MirrorHandle
+	replicaConfig.ReplicaVolume = ""
+	replicaConfig.ReplicaSourceVolume = volumeName
+
+	if _, err := o.addVolumeToPools(ctx, replicaConfig, sc, protocol, pools); err != nil {
+		return fail(err)
+	}
+
+	// The global lock was released while the replica was provisioned
+	primary = o.volumes[volumeName]
+	replica := o.volumes[replicaName]
+	mirrorer, ok := o.backends[replica.BackendUUID].(storage.Mirrorer)
+	if !ok {
+		return fail(fmt.Errorf("backend does not support mirroring"))
+	}
+	err := mirrorer.EstablishMirror(ctx, replica.Config.MirrorHandle, primary.Config.MirrorHandle,
+		sc.GetReplicationPolicy(), sc.GetReplicationSchedule())
+	if err != nil && !api.IsNotReadyError(err) {
+		return fail(err)
+	}
+
+	// Record the replica on the primary, which remains accessible only from its own zone until it fails over
+	newPrimary := storage.NewVolume(primary.Config.ConstructClone(), primary.BackendUUID, primary.Pool,
+		primary.Orphaned, primary.State)
+	newPrimary.Config.ReplicaVolume = replicaName
+	if err = o.updateVolumeOnPersistentStore(ctx, newPrimary); err != nil {
+		return fail(err)
+	}
+	o.volumes[volumeName] = newPrimary
+
+	Logc(ctx).WithFields(logFields).WithFields(log.Fields{
+		"primaryBackend": primaryBackend.Name(),
+		"replicaBackend": o.backends[replica.BackendUUID].Name(),
+	}).Info("Created cross-zone replica.")
+
+	return newPrimary.ConstructExternal(), nil
+}
+
+// deleteVolumeReplica removes the cross-zone replica of a volume, if it has one, and releases the mirror
+// relationship on the primary.  If the volume is itself a replica, it is detached from its primary.
+// This method should only be called from DeleteVolume, as it does not take locks.
+func (o *TridentOrchestrator) deleteVolumeReplica(ctx context.Context, volume *storage.Volume) error {
+	if sourceName := volume.Config.ReplicaSourceVolume; sourceName != "" {
+		source, ok := o.volumes[sourceName]
+		if !ok || source.Config.ReplicaVolume != volume.Config.Name {
+			return nil
+		}
+		newSource := storage.NewVolume(source.Config.ConstructClone(), source.BackendUUID, source.Pool,
+			source.Orphaned, source.State)
+		newSource.Config.ReplicaVolume = ""
+		if err := o.updateVolumeOnPersistentStore(ctx, newSource); err != nil {
+			return err
+		}
+		o.volumes[sourceName] = newSource
+		return nil
+	}
+
+	replicaName := volume.Config.ReplicaVolume
+	if replicaName == "" {
+		return nil
+	}
+	if _, ok := o.volumes[replicaName]; ok {
+		if err := o.deleteVolume(ctx, replicaName); err != nil {
+			return fmt.Errorf("could not delete replica %s of volume %s; %v", replicaName, volume.Config.Name, err)
+		}
+	}
+
+	// Clean up any mirror metadata left on the primary
+	if backend, ok := o.backends[volume.BackendUUID]; ok {
+		if mirrorer, ok := backend.(storage.Mirrorer); ok && backend.CanMirror() {
+			if err := mirrorer.ReleaseMirror(ctx, volume.Config.MirrorHandle); err != nil {
+				Logc(ctx).WithFields(log.Fields{
+					"volume":  volume.Config.Name,
+					"replica": replicaName,
+				}).WithError(err).Warning("Could not release mirror.")
+			}
+		}
+	}
+	return nil
+}
+
+// PromoteVolumeReplica fails a volume over to its cross-zone replica.  The replica is promoted on its
+// backend, and the volume's record is pointed at the replica's storage so that the volume keeps its name,
+// after which the volume may also be accessed from the replica's zone.  The former primary's storage is then
+// tracked as the (no longer mirrored) replica.  The volume may not be published, since its nodes would keep
+// using the former primary's storage.
+func (o *TridentOrchestrator) PromoteVolumeReplica(
+	ctx context.Context, volumeName string,
+) (externalVol *storage.VolumeExternal, err error) {
+	if o.bootstrapError != nil {
+		return nil, o.bootstrapError
+	}
+	defer recordTiming("volume_promote_replica", &err)()
+
+	lockVolumes(ctx, "promoteVolumeReplica", volumeName, volumeName+replicaVolumeSuffix)
+	defer unlockVolumes(ctx, "promoteVolumeReplica", volumeName, volumeName+replicaVolumeSuffix)
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	defer o.updateMetrics()
+
+	primary, ok := o.volumes[volumeName]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	}
+	replicaName := primary.Config.ReplicaVolume
+	if replicaName == "" {
+		return nil, utils.InvalidInputError(fmt.Sprintf("volume %s has no cross-zone replica", volumeName))
+	}
+	if len(o.volumePublications.ListPublicationsForVolume(volumeName)) > 0 {
+		return nil, utils.VolumeStateError(fmt.Sprintf("volume %s is published; unpublish it before promoting "+
+			"its replica", volumeName))
+	}
+	replica, ok := o.volumes[replicaName]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("replica %s of volume %s not found", replicaName, volumeName))
+	}
+	if !replica.Config.IsMirrorDestination {
+		return nil, utils.InvalidInputError(fmt.Sprintf("replica %s of volume %s is not a mirror destination",
+			replicaName, volumeName))
+	}
+	primaryBackend, ok := o.backends[primary.BackendUUID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %s not found", primary.BackendUUID))
+	}
+	replicaBackend, ok := o.backends[replica.BackendUUID]
+	if !ok {
+		return nil, utils.NotFoundError(fmt.Sprintf("backend %s not found", replica.BackendUUID))
+	}
+	mirrorer, ok := replicaBackend.(storage.Mirrorer)
+	if !ok {
+		return nil, fmt.Errorf("backend does not support mirroring")
+	}
+
+	logFields := log.Fields{
+		"volume":         volumeName,
+		"replica":        replicaName,
+		"primaryBackend": primaryBackend.Name(),
+		"replicaBackend": replicaBackend.Name(),
+	}
+
+	waitingForSnapshot, err := mirrorer.PromoteMirror(ctx, replica.Config.MirrorHandle,
+		primary.Config.MirrorHandle, "")
+	if err != nil {
+		return nil, err
+	}
+	if waitingForSnapshot {
+		Logc(ctx).WithFields(logFields).Warning("Replica promoted before its latest snapshot arrived.")
+	}
+
+	// Swap the storage behind the two volume records
+	newPrimaryConfig := primary.Config.ConstructClone()
+	newReplicaConfig := replica.Config.ConstructClone()
+	newPrimaryConfig.InternalName, newReplicaConfig.InternalName = replica.Config.InternalName,
+		primary.Config.InternalName
+	newPrimaryConfig.InternalID, newReplicaConfig.InternalID = replica.Config.InternalID, primary.Config.InternalID
+	newPrimaryConfig.MirrorHandle, newReplicaConfig.MirrorHandle = replica.Config.MirrorHandle,
+		primary.Config.MirrorHandle
+	newPrimaryConfig.AccessInfo, newReplicaConfig.AccessInfo = replica.Config.AccessInfo, primary.Config.AccessInfo
+	newPrimaryConfig.AllowedTopologies = mergeTopologies(primary.Config.AllowedTopologies,
+		replica.Config.AllowedTopologies)
+	if pool, ok := primaryBackend.Storage()[primary.Pool]; ok {
+		newReplicaConfig.AllowedTopologies = pool.SupportedTopologies()
+	}
+	newPrimaryConfig.IsMirrorDestination = false
+	newPrimaryConfig.PeerVolumeHandle = ""
+	newReplicaConfig.IsMirrorDestination = false
+	newReplicaConfig.PeerVolumeHandle = ""
+
+	newPrimary := storage.NewVolume(newPrimaryConfig, replica.BackendUUID, replica.Pool, replica.Orphaned,
+		primary.State)
+	newReplica := storage.NewVolume(newReplicaConfig, primary.BackendUUID, primary.Pool, primary.Orphaned,
+		replica.State)
+
+	if err = o.updateVolumeOnPersistentStore(ctx, newPrimary); err != nil {
+		return nil, err
+	}
+	if err = o.updateVolumeOnPersistentStore(ctx, newReplica); err != nil {
+		if revertErr := o.updateVolumeOnPersistentStore(ctx, primary); revertErr != nil {
+			Logc(ctx).WithFields(logFields).WithError(revertErr).Error("Could not revert volume.")
+		}
+		return nil, err
+	}
+
+	primaryBackend.RemoveCachedVolume(volumeName)
+	primaryBackend.CacheVolume(newReplica)
+	replicaBackend.RemoveCachedVolume(replicaName)
+	replicaBackend.CacheVolume(newPrimary)
+	o.volumes[volumeName] = newPrimary
+	o.volumes[replicaName] = newReplica
+
+	Logc(ctx).WithFields(logFields).Info("Promoted cross-zone replica.")
+
+	return newPrimary.ConstructExternal(), nil
+}
+
+// mergeTopologies returns the union of two topology lists.
+func mergeTopologies(a, b []map[string]string) []map[string]string {
+	merged := make([]map[string]string, 0, len(a)+len(b))
+	for _, topology := range append(append([]map[string]string{}, a...), b...) {
+		found := false
+		for _, existing := range merged {
+			if reflect.DeepEqual(existing, topology) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, topology)
+		}
+	}
+	return merged
+}
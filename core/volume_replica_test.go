@@ -0,0 +1,194 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/storage/fake"
+	sa "github.com/netapp/trident/storage_attribute"
+	storageclass "github.com/netapp/trident/storage_class"
+	drivers "github.com/netapp/trident/storage_drivers"
+	fakedriver "github.com/netapp/trident/storage_drivers/fake"
+	"github.com/netapp/trident/utils"
+)
+
+// mirroringBackend adds a recording Mirrorer to a fake backend
+type mirroringBackend struct {
+	*storage.StorageBackend
+	established map[string]string
+	promoted    []string
+	released    []string
+}
+
+func (b *mirroringBackend) CanMirror() bool {
+	return true
+}
+
+func (b *mirroringBackend) AddVolume(
+	ctx context.Context, volConfig *storage.VolumeConfig, storagePool storage.Pool,
+	volAttributes map[string]sa.Request, retry bool,
+) (*storage.Volume, error) {
+	volConfig.MirrorHandle = b.Name() + ":" + volConfig.InternalName
+	return b.StorageBackend.AddVolume(ctx, volConfig, storagePool, volAttributes, retry)
+}
+
+func (b *mirroringBackend) EstablishMirror(_ context.Context, local, remote, _, _ string) error {
+	b.established[local] = remote
+	return nil
+}
+
+func (b *mirroringBackend) PromoteMirror(_ context.Context, local, _, _ string) (bool, error) {
+	b.promoted = append(b.promoted, local)
+	return false, nil
+}
+
+func (b *mirroringBackend) ReleaseMirror(_ context.Context, local string) error {
+	b.released = append(b.released, local)
+	return nil
+}
+
+func addZonedMirroringBackend(t *testing.T, o *TridentOrchestrator, name, zone string) *mirroringBackend {
+	configJSON, err := fakedriver.NewFakeStorageDriverConfigJSONWithVirtualPools(
+		name,
+		config.File,
+		map[string]*fake.StoragePool{
+			"primary": {
+				Attrs: map[string]sa.Offer{
+					sa.TestingAttribute: sa.NewBoolOffer(true),
+				},
+				Bytes: 100 * 1024 * 1024 * 1024,
+			},
+		},
+		drivers.FakeStorageDriverPool{
+			SupportedTopologies: []map[string]string{{"topology.kubernetes.io/zone": zone}},
+		},
+		nil,
+	)
+	assert.NoError(t, err)
+
+	backendExternal, err := o.AddBackend(ctx(), configJSON, "")
+	assert.NoError(t, err)
+
+	backend := &mirroringBackend{
+		StorageBackend: o.backends[backendExternal.BackendUUID].(*storage.StorageBackend),
+		established:    make(map[string]string),
+	}
+	for _, pool := range backend.Storage() {
+		pool.SetBackend(backend)
+	}
+	o.backends[backendExternal.BackendUUID] = backend
+	return backend
+}
+
+func TestAddVolumeReplica(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	backendA := addZonedMirroringBackend(t, o, "zone-a", "a")
+	backendB := addZonedMirroringBackend(t, o, "zone-b", "b")
+
+	_, err := o.AddStorageClass(ctx(), &storageclass.Config{
+		Name:             "replicated",
+		Attributes:       map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		CrossZoneReplica: true,
+	})
+	assert.NoError(t, err)
+
+	volume, err := o.AddVolume(ctx(), &storage.VolumeConfig{
+		Name:                "vol1",
+		Size:                "1GiB",
+		Protocol:            config.File,
+		VolumeMode:          config.Filesystem,
+		AccessMode:          config.ReadWriteOnce,
+		StorageClass:        "replicated",
+		RequisiteTopologies: []map[string]string{{"topology.kubernetes.io/zone": "a"}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "vol1-replica", volume.Config.ReplicaVolume)
+	assert.Equal(t, backendA.BackendUUID(), volume.BackendUUID)
+	assert.Equal(t, []map[string]string{{"topology.kubernetes.io/zone": "a"}}, volume.Config.AllowedTopologies,
+		"volume should only be accessible from its own zone before failover")
+
+	replica, err := o.GetVolume(ctx(), "vol1-replica")
+	assert.NoError(t, err)
+	assert.Equal(t, backendB.BackendUUID(), replica.BackendUUID)
+	assert.True(t, replica.Config.IsMirrorDestination)
+	assert.Equal(t, "vol1", replica.Config.ReplicaSourceVolume)
+	assert.Equal(t, volume.Config.MirrorHandle, replica.Config.PeerVolumeHandle)
+	assert.Equal(t, volume.Config.MirrorHandle, backendB.established[replica.Config.MirrorHandle])
+
+	// A published volume cannot be failed over, since its nodes use the primary's storage
+	err = o.volumePublications.Set("vol1", "node1", &utils.VolumePublication{
+		Name: "vol1.node1", VolumeName: "vol1", NodeName: "node1",
+	})
+	assert.NoError(t, err)
+	_, err = o.PromoteVolumeReplica(ctx(), "vol1")
+	assert.True(t, utils.IsVolumeStateError(err))
+	assert.Empty(t, backendB.promoted)
+	assert.NoError(t, o.volumePublications.Delete("vol1", "node1"))
+
+	// Promote the replica, and the volume should now be served from zone b
+	promoted, err := o.PromoteVolumeReplica(ctx(), "vol1")
+	assert.NoError(t, err)
+	assert.Len(t, promoted.Config.AllowedTopologies, 2, "volume should be accessible from both zones")
+	assert.Equal(t, []string{replica.Config.MirrorHandle}, backendB.promoted)
+	assert.Equal(t, backendB.BackendUUID(), promoted.BackendUUID)
+	assert.Equal(t, replica.Config.MirrorHandle, promoted.Config.MirrorHandle)
+	assert.False(t, promoted.Config.IsMirrorDestination)
+	_, ok := backendB.Volumes()["vol1"]
+	assert.True(t, ok, "promoted volume should be cached on the replica's backend")
+
+	demoted, err := o.GetVolume(ctx(), "vol1-replica")
+	assert.NoError(t, err)
+	assert.Equal(t, backendA.BackendUUID(), demoted.BackendUUID)
+	assert.Equal(t, volume.Config.MirrorHandle, demoted.Config.MirrorHandle)
+
+	// The replica is no longer a mirror destination, so it cannot be promoted again
+	_, err = o.PromoteVolumeReplica(ctx(), "vol1")
+	assert.True(t, utils.IsInvalidInputError(err))
+
+	// Deleting the volume deletes its replica too
+	assert.NoError(t, o.DeleteVolume(ctx(), "vol1"))
+	_, err = o.GetVolume(ctx(), "vol1-replica")
+	assert.True(t, utils.IsNotFoundError(err))
+	assert.Equal(t, []string{promoted.Config.MirrorHandle}, backendB.released)
+}
+
+func TestAddVolumeReplica_NoOtherZone(t *testing.T) {
+	o := getOrchestrator(t, false)
+	defer cleanup(t, o)
+
+	addZonedMirroringBackend(t, o, "zone-a", "a")
+
+	_, err := o.AddStorageClass(ctx(), &storageclass.Config{
+		Name:             "replicated",
+		Attributes:       map[string]sa.Request{sa.TestingAttribute: sa.NewBoolRequest(true)},
+		CrossZoneReplica: true,
+	})
+	assert.NoError(t, err)
+
+	_, err = o.AddVolume(ctx(), &storage.VolumeConfig{
+		Name:         "vol1",
+		Size:         "1GiB",
+		Protocol:     config.File,
+		VolumeMode:   config.Filesystem,
+		AccessMode:   config.ReadWriteOnce,
+		StorageClass: "replicated",
+	})
+	assert.Error(t, err)
+
+	// The primary is removed so that the request may be retried
+	_, err = o.GetVolume(ctx(), "vol1")
+	assert.True(t, utils.IsNotFoundError(err))
+
+	_, err = o.PromoteVolumeReplica(ctx(), "vol1")
+	assert.True(t, utils.IsNotFoundError(err))
+}
@@ -174,6 +174,17 @@ func CombineAccessModes(accessModes []config.AccessMode) config.AccessMode {
 		// ReadWriteMany            ReadWriteOnce       ReadWriteMany
 		// ReadWriteMany            ReadOnlyMany        ReadWriteMany
 		// ReadWriteMany            ReadWriteMany       ReadWriteMany
+
+		// ReadWriteOncePod may only be requested by itself, so combining it with any other mode
+		// relaxes it to the combination of ReadWriteOnce and that mode.
+		if volConfigAccessMode == config.ReadWriteOncePod && accessMode != config.ModeAny &&
+			accessMode != config.ReadWriteOncePod {
+			volConfigAccessMode = config.ReadWriteOnce
+		}
+		if accessMode == config.ReadWriteOncePod && volConfigAccessMode != config.ModeAny {
+			accessMode = config.ReadWriteOnce
+		}
+
 		if volConfigAccessMode == config.ModeAny {
 			volConfigAccessMode = accessMode
 		} else if volConfigAccessMode == config.ReadWriteOnce {
@@ -47,6 +47,13 @@ func TestCombineAccessModes(t *testing.T) {
 		{[]config.AccessMode{config.ReadWriteMany, config.ReadWriteOnce}, config.ReadWriteMany},
 		{[]config.AccessMode{config.ReadWriteMany, config.ReadOnlyMany}, config.ReadWriteMany},
 		{[]config.AccessMode{config.ReadWriteMany, config.ReadWriteMany}, config.ReadWriteMany},
+		{[]config.AccessMode{config.ModeAny, config.ReadWriteOncePod}, config.ReadWriteOncePod},
+		{[]config.AccessMode{config.ReadWriteOncePod, config.ModeAny}, config.ReadWriteOncePod},
+		{[]config.AccessMode{config.ReadWriteOncePod, config.ReadWriteOncePod}, config.ReadWriteOncePod},
+		{[]config.AccessMode{config.ReadWriteOncePod, config.ReadWriteOnce}, config.ReadWriteOnce},
+		{[]config.AccessMode{config.ReadWriteOnce, config.ReadWriteOncePod}, config.ReadWriteOnce},
+		{[]config.AccessMode{config.ReadWriteOncePod, config.ReadOnlyMany}, config.ReadWriteMany},
+		{[]config.AccessMode{config.ReadWriteMany, config.ReadWriteOncePod}, config.ReadWriteMany},
 	}
 
 	for _, tc := range accessModesTests {
@@ -59,6 +59,10 @@ const (
 	OperationStatusSuccess string = "Success"
 	OperationStatusFailed  string = "Failed"
 
+	BackendOnlineReason  string = "BackendOnline"
+	BackendOfflineReason string = "BackendOffline"
+	BackendFailedReason  string = "BackendFailed"
+
 	controllerName                 = "crd"
 	controllerAgentName            = "trident-crd-controller"
 	tridentBackendConfigsQueueName = "TridentBackendConfigs"
@@ -131,6 +135,10 @@ type TridentCrdController struct {
 	transactionsLister listers.TridentTransactionLister
 	transactionsSynced cache.InformerSynced
 
+	// TridentJob CRD handling
+	jobsLister listers.TridentJobLister
+	jobsSynced cache.InformerSynced
+
 	// TridentVersion CRD handling
 	versionsLister listers.TridentVersionLister
 	versionsSynced cache.InformerSynced
@@ -205,6 +213,7 @@ func newTridentCrdControllerImpl(
 	nodeInformer := crdInformer.TridentNodes()
 	storageClassInformer := crdInformer.TridentStorageClasses()
 	transactionInformer := crdInformer.TridentTransactions()
+	jobInformer := crdInformer.TridentJobs()
 	versionInformer := crdInformer.TridentVersions()
 	volumeInformer := crdInformer.TridentVolumes()
 	volumePublicationInformer := crdInformer.TridentVolumePublications()
@@ -243,6 +252,8 @@ func newTridentCrdControllerImpl(
 		storageClassesSynced:     storageClassInformer.Informer().HasSynced,
 		transactionsLister:       transactionInformer.Lister(),
 		transactionsSynced:       transactionInformer.Informer().HasSynced,
+		jobsLister:               jobInformer.Lister(),
+		jobsSynced:               jobInformer.Informer().HasSynced,
 		versionsLister:           versionInformer.Lister(),
 		versionsSynced:           versionInformer.Informer().HasSynced,
 		volumesLister:            volumeInformer.Lister(),
@@ -279,6 +290,9 @@ func newTridentCrdControllerImpl(
 			if err := controller.removeFinalizers(ctx, newCrd, false); err != nil {
 				Logc(ctx).WithError(err).Error("Error removing finalizers")
 			}
+			// Backend state changes made by Trident itself, such as a backend being taken offline
+			// when it stops responding, are reported on the backend's TridentBackendConfig.
+			controller.updateTridentBackendEvent(oldCrd, newCrd)
 		},
 		DeleteFunc: controller.deleteTridentBackendEvent,
 	})
@@ -308,6 +322,7 @@ func newTridentCrdControllerImpl(
 		nodeInformer.Informer(),
 		storageClassInformer.Informer(),
 		transactionInformer.Informer(),
+		jobInformer.Informer(),
 		versionInformer.Informer(),
 		volumeInformer.Informer(),
 		volumePublicationInformer.Informer(),
@@ -376,6 +391,7 @@ func (c *TridentCrdController) Run(threadiness int, stopCh <-chan struct{}) {
 		c.nodesSynced,
 		c.storageClassesSynced,
 		c.transactionsSynced,
+		c.jobsSynced,
 		c.versionsSynced,
 		c.volumesSynced,
 		c.volumePublicationsSynced,
@@ -394,6 +410,9 @@ func (c *TridentCrdController) Run(threadiness int, stopCh <-chan struct{}) {
 		go wait.Until(c.runWorker, time.Second, stopCh)
 	}
 
+	// Periodically check the health of mirror relationships
+	go wait.Until(c.checkMirrorHealth, MirrorHealthCheckPeriod, stopCh)
+
 	log.Info("Started workers.")
 	<-stopCh
 	log.Info("Shutting down workers.")
@@ -538,6 +557,40 @@ func (c *TridentCrdController) deleteTridentBackendEvent(obj interface{}) {
 	c.workqueue.Add(keyItem)
 }
 
+// updateTridentBackendEvent takes a TridentBackend resource whose state has changed and converts it into a
+// namespace/backendUUID string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than TridentBackend.
+func (c *TridentCrdController) updateTridentBackendEvent(old, new interface{}) {
+	oldBackend, ok := old.(*tridentv1.TridentBackend)
+	if !ok {
+		return
+	}
+	newBackend, ok := new.(*tridentv1.TridentBackend)
+	if !ok {
+		return
+	}
+
+	// Only state changes of backends created using a TridentBackendConfig are of interest
+	if oldBackend.State == newBackend.State || newBackend.ConfigRef == "" ||
+		!newBackend.ObjectMeta.DeletionTimestamp.IsZero() {
+		return
+	}
+
+	ctx := GenerateRequestContext(context.Background(), "", ContextSourceCRD)
+	ctx = context.WithValue(ctx, CRDControllerEvent, string(EventUpdate))
+
+	Logx(ctx).Debug("TridentCrdController#updateTridentBackendEvent")
+
+	keyItem := KeyItem{
+		key:        newBackend.Namespace + "/" + newBackend.BackendUUID,
+		event:      EventUpdate,
+		ctx:        ctx,
+		objectType: ObjectTypeTridentBackend,
+	}
+
+	c.workqueue.Add(keyItem)
+}
+
 // updateSecretEvent takes a Kubernetes secret resource and converts it
 // into a namespace/name string which is then put onto the work queue.
 // This method should *not* be passed resources of any type other than Secrets.
@@ -690,7 +743,7 @@ func (c *TridentCrdController) reconcileBackend(keyItem *KeyItem) {
 		"objectType": objectType,
 	}).Debug("TridentCrdController#reconcileBackend")
 
-	if eventType != EventDelete {
+	if eventType != EventDelete && eventType != EventUpdate {
 		Logx(ctx).Error("Wrong backend event triggered a reconcileBackend.")
 		return
 	}
@@ -702,6 +755,11 @@ func (c *TridentCrdController) reconcileBackend(keyItem *KeyItem) {
 		return
 	}
 
+	if eventType == EventUpdate {
+		c.reconcileBackendState(ctx, namespace, name)
+		return
+	}
+
 	// Get the backend config that matches the backendUUID, i.e. the key
 	backendConfig, err := c.getBackendConfigWithBackendUUID(ctx, namespace, name)
 	if err != nil {
@@ -747,6 +805,60 @@ func (c *TridentCrdController) reconcileBackend(keyItem *KeyItem) {
 	c.workqueue.Add(newKeyItem)
 }
 
+// reconcileBackendState reports a change in a backend's state on the status of the backend config it is
+// bound to, and records an event for it.
+func (c *TridentCrdController) reconcileBackendState(ctx context.Context, namespace, backendUUID string) {
+	backend, err := c.getTridentBackend(ctx, namespace, backendUUID)
+	if err != nil || backend == nil {
+		Logx(ctx).WithField("backendUUID", backendUUID).Debug("Backend not found, nothing to do.")
+		return
+	}
+
+	backendConfig, err := c.getBackendConfigWithBackendUUID(ctx, namespace, backendUUID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			Logx(ctx).Infof("No backend config is associated with the backendUUID '%v'.", backendUUID)
+		} else {
+			Logx(ctx).Errorf("unable to identify a backend config associated with the backendUUID '%v'.",
+				backendUUID)
+		}
+		return
+	}
+
+	if backendConfig.Status.Phase != string(tridentv1.PhaseBound) {
+		Logx(ctx).Debugf("Backend Config '%v' has %v phase, nothing to do", backendConfig.Name,
+			backendConfig.Status.Phase)
+		return
+	}
+
+	var eventType, reason, message string
+	switch storage.BackendState(backend.State) {
+	case storage.Online:
+		eventType = corev1.EventTypeNormal
+		reason = BackendOnlineReason
+		message = fmt.Sprintf("Backend '%v' is online", backend.BackendName)
+	case storage.Offline:
+		eventType = corev1.EventTypeWarning
+		reason = BackendOfflineReason
+		message = fmt.Sprintf("Backend '%v' is offline; its storage system is not responding", backend.BackendName)
+	case storage.Failed:
+		eventType = corev1.EventTypeWarning
+		reason = BackendFailedReason
+		message = fmt.Sprintf("Backend '%v' has failed", backend.BackendName)
+	default:
+		return
+	}
+
+	newStatus := backendConfig.Status
+	newStatus.Message = message
+
+	debugMessage := fmt.Sprintf("Reporting backend state %v.", backend.State)
+	if _, _, err = c.updateTridentBackendConfigCRStatus(ctx, backendConfig, newStatus, debugMessage); err != nil {
+		Logx(ctx).WithError(err).Error("Could not update the backend config status.")
+	}
+	c.recorder.Event(backendConfig, eventType, reason, message)
+}
+
 func (c *TridentCrdController) reconcileSecret(keyItem *KeyItem) {
 	key := keyItem.key
 	ctx := keyItem.ctx
@@ -1583,6 +1695,10 @@ func (c *TridentCrdController) removeFinalizers(ctx context.Context, obj interfa
 		if force || !crd.ObjectMeta.DeletionTimestamp.IsZero() {
 			return c.removeTransactionFinalizers(ctx, crd)
 		}
+	case *tridentv1.TridentJob:
+		if force || !crd.ObjectMeta.DeletionTimestamp.IsZero() {
+			return c.removeJobFinalizers(ctx, crd)
+		}
 	case *tridentv1.TridentVersion:
 		if force || !crd.ObjectMeta.DeletionTimestamp.IsZero() {
 			return c.removeVersionFinalizers(ctx, crd)
@@ -1739,6 +1855,29 @@ func (c *TridentCrdController) removeTransactionFinalizers(
 	return
 }
 
+// removeJobFinalizers removes Trident's finalizers from TridentJob CRs
+func (c *TridentCrdController) removeJobFinalizers(ctx context.Context, job *tridentv1.TridentJob) (err error) {
+	Logx(ctx).WithFields(log.Fields{
+		"job.ResourceVersion":              job.ResourceVersion,
+		"job.ObjectMeta.DeletionTimestamp": job.ObjectMeta.DeletionTimestamp,
+	}).Debug("removeJobFinalizers")
+
+	if job.HasTridentFinalizers() {
+		Logx(ctx).Debug("Has finalizers, removing them.")
+		jobCopy := job.DeepCopy()
+		jobCopy.RemoveTridentFinalizers()
+		_, err = c.crdClientset.TridentV1().TridentJobs(job.Namespace).Update(ctx, jobCopy, updateOpts)
+		if err != nil {
+			Logx(ctx).Errorf("Problem removing finalizers: %v", err)
+			return
+		}
+	} else {
+		Logx(ctx).Debug("No finalizers to remove.")
+	}
+
+	return
+}
+
 // removeVersionFinalizers removes Trident's finalizers from TridentVersion CRs
 func (c *TridentCrdController) removeVersionFinalizers(ctx context.Context, v *tridentv1.TridentVersion) (err error) {
 	Logx(ctx).WithFields(log.Fields{
@@ -13,10 +13,12 @@ import (
 	"github.com/google/uuid"
 	fakesnapshots "github.com/kubernetes-csi/external-snapshotter/client/v6/clientset/versioned/fake"
 	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8s_fake "k8s.io/client-go/kubernetes/fake"
 	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/netapp/trident/config"
 	mockcore "github.com/netapp/trident/mocks/mock_core"
@@ -544,3 +546,67 @@ func TestCrdController2(t *testing.T) {
 		t.Fatalf("error while deactivating: %v", err.Error())
 	}
 }
+
+func TestReconcileBackendState(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	orchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+
+	tridentNamespace := "trident"
+	crdClient := GetTestCrdClientset()
+	crdController, err := newTridentCrdControllerImpl(orchestrator, tridentNamespace, GetTestKubernetesClientset(),
+		GetTestSnapshotClientset(), crdClient)
+	if err != nil {
+		t.Fatalf("cannot create Trident CRD controller frontend, error: %v", err.Error())
+	}
+	recorder := record.NewFakeRecorder(10)
+	crdController.recorder = recorder
+
+	backendUUID := uuid.New().String()
+	tbc := &tridentv1.TridentBackendConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "tbc1", Namespace: tridentNamespace, UID: "configRef1"},
+		Status: tridentv1.TridentBackendConfigStatus{
+			Message:             "Backend 'fake1' created",
+			BackendInfo:         tridentv1.TridentBackendConfigBackendInfo{BackendName: "fake1", BackendUUID: backendUUID},
+			Phase:               string(tridentv1.PhaseBound),
+			LastOperationStatus: OperationStatusSuccess,
+		},
+	}
+	if _, err = crdClient.TridentV1().TridentBackendConfigs(tridentNamespace).Create(ctx(), tbc,
+		createOpts); err != nil {
+		t.Fatalf("error creating backend config: %v", err)
+	}
+	backend := &tridentv1.TridentBackend{
+		ObjectMeta:  metav1.ObjectMeta{Name: "tbe-1", Namespace: tridentNamespace},
+		BackendName: "fake1",
+		BackendUUID: backendUUID,
+		State:       string(storage.Offline),
+		ConfigRef:   "configRef1",
+	}
+	if _, err = crdClient.TridentV1().TridentBackends(tridentNamespace).Create(ctx(), backend,
+		createOpts); err != nil {
+		t.Fatalf("error creating backend: %v", err)
+	}
+
+	crdController.reconcileBackendState(ctx(), tridentNamespace, backendUUID)
+
+	tbc, err = crdClient.TridentV1().TridentBackendConfigs(tridentNamespace).Get(ctx(), "tbc1", getOpts)
+	if assert.NoError(t, err) {
+		assert.Contains(t, tbc.Status.Message, "offline")
+		assert.Equal(t, string(tridentv1.PhaseBound), tbc.Status.Phase)
+	}
+	if assert.Len(t, recorder.Events, 1) {
+		assert.Contains(t, <-recorder.Events, BackendOfflineReason)
+	}
+
+	// Only state changes of backends bound to a backend config are queued
+	online := backend.DeepCopy()
+	online.State = string(storage.Online)
+	crdController.updateTridentBackendEvent(backend, online)
+	assert.Equal(t, 1, crdController.workqueue.Len())
+
+	unbound := online.DeepCopy()
+	unbound.ConfigRef = ""
+	crdController.updateTridentBackendEvent(backend, unbound)
+	crdController.updateTridentBackendEvent(online, online)
+	assert.Equal(t, 1, crdController.workqueue.Len())
+}
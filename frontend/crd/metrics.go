@@ -0,0 +1,31 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/netapp/trident/config"
+)
+
+var (
+	mirrorReplicationLagGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "mirror",
+			Name:      "replication_lag_seconds",
+			Help:      "The number of seconds since the last completed transfer of a mirror relationship",
+		},
+		[]string{"namespace", "mirror_relationship", "local_pvc"},
+	)
+	mirrorHealthyGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "mirror",
+			Name:      "healthy",
+			Help:      "Whether a mirror relationship is healthy and within its recovery point objective",
+		},
+		[]string{"namespace", "mirror_relationship", "local_pvc"},
+	)
+)
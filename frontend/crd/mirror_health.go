@@ -0,0 +1,207 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	. "github.com/netapp/trident/logger"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	// MirrorHealthCheckPeriod is how often the health of established mirror relationships is polled
+	MirrorHealthCheckPeriod = 1 * time.Minute
+
+	mirrorHealthyReason   = "MirrorHealthy"
+	mirrorLaggingReason   = "MirrorLagging"
+	mirrorUnhealthyReason = "MirrorUnhealthy"
+)
+
+// checkMirrorHealth polls the storage for the health of every settled mirror relationship. Unlike the
+// reconcile loop, which only acts on TMR events, this catches mirrors that break or fall behind after
+// they were established, so that the problem is visible before a promotion is attempted.
+func (c *TridentCrdController) checkMirrorHealth() {
+	ctx := GenerateRequestContext(context.Background(), "", ContextSourcePeriodic)
+
+	relationships, err := c.mirrorLister.List(labels.Everything())
+	if err != nil {
+		Logx(ctx).WithError(err).Error("Could not list TridentMirrorRelationships.")
+		return
+	}
+
+	mirrorReplicationLagGauge.Reset()
+	mirrorHealthyGauge.Reset()
+
+	for _, relationship := range relationships {
+		c.checkTMRHealth(ctx, relationship)
+	}
+}
+
+// checkTMRHealth refreshes the health, last transfer time and replication lag of a single TMR's status
+// condition, and raises an event if the mirror has become unhealthy or has exceeded its recovery point
+// objective. The mirror state is left alone so that a health check never triggers a reconcile.
+func (c *TridentCrdController) checkTMRHealth(ctx context.Context, relationship *netappv1.TridentMirrorRelationship) {
+	if !relationship.ObjectMeta.DeletionTimestamp.IsZero() || len(relationship.Status.Conditions) == 0 {
+		return
+	}
+	if valid, _ := relationship.IsValid(); !valid {
+		return
+	}
+
+	// Only check relationships that have reached their desired state
+	condition := relationship.Status.Conditions[0]
+	if condition.MirrorState != relationship.Spec.MirrorState {
+		return
+	}
+
+	logFields := log.Fields{
+		"TridentMirrorRelationship": relationship.Name,
+		"namespace":                 relationship.Namespace,
+	}
+
+	health, err := c.getTMRMirrorHealth(ctx, relationship)
+	if err != nil {
+		if !utils.IsNotFoundError(err) && !utils.IsUnsupportedError(err) {
+			Logx(ctx).WithFields(logFields).WithError(err).Debug("Could not get mirror health.")
+		}
+		return
+	} else if health == nil {
+		return
+	}
+
+	rpo, _ := relationship.GetRecoveryPointObjective()
+	localPVCName := relationship.Spec.VolumeMappings[0].LocalPVCName
+	metricLabels := []string{relationship.Namespace, relationship.Name, localPVCName}
+
+	conditionCopy := condition.DeepCopy()
+	conditionCopy.LastTransferTime = ""
+	conditionCopy.ReplicationLag = ""
+
+	var lag time.Duration
+	if health.LastTransferTime != nil {
+		lag = time.Since(*health.LastTransferTime).Round(time.Second)
+		conditionCopy.LastTransferTime = health.LastTransferTime.Format(time.RFC3339)
+		conditionCopy.ReplicationLag = lag.String()
+		mirrorReplicationLagGauge.WithLabelValues(metricLabels...).Set(lag.Seconds())
+	}
+
+	var eventType, reason, message string
+	if !health.Healthy {
+		conditionCopy.Health = netappv1.MirrorHealthUnhealthy
+		eventType, reason = corev1.EventTypeWarning, mirrorUnhealthyReason
+		message = "Mirror relationship is unhealthy"
+		if health.UnhealthyReason != "" {
+			message = fmt.Sprintf("%s: %s", message, health.UnhealthyReason)
+		}
+	} else if rpo > 0 && health.LastTransferTime != nil && lag > rpo {
+		conditionCopy.Health = netappv1.MirrorHealthLagging
+		eventType, reason = corev1.EventTypeWarning, mirrorLaggingReason
+		message = fmt.Sprintf("Replication lag of %v exceeds the recovery point objective of %v", lag, rpo)
+	} else {
+		conditionCopy.Health = netappv1.MirrorHealthHealthy
+		eventType, reason = corev1.EventTypeNormal, mirrorHealthyReason
+		message = "Mirror relationship is healthy"
+	}
+
+	if conditionCopy.Health == netappv1.MirrorHealthHealthy {
+		mirrorHealthyGauge.WithLabelValues(metricLabels...).Set(1)
+	} else {
+		mirrorHealthyGauge.WithLabelValues(metricLabels...).Set(0)
+	}
+
+	// Warnings are raised on every check so that they remain visible; recovery is only reported once
+	if eventType == corev1.EventTypeWarning || (condition.Health != "" && condition.Health != conditionCopy.Health) {
+		Logx(ctx).WithFields(logFields).WithField("health", conditionCopy.Health).Debug(message)
+		c.recorder.Event(relationship, eventType, reason, message)
+	}
+
+	if *conditionCopy == *condition {
+		return
+	}
+	if _, err = c.updateTMRHealth(ctx, relationship, conditionCopy); err != nil {
+		Logx(ctx).WithFields(logFields).WithError(err).Error("Could not update TridentMirrorRelationship health.")
+	}
+}
+
+// getTMRMirrorHealth returns the health of the mirror described by a TMR. For an established mirror, the
+// local volume is the destination. For a promoted volume with a cross-zone replica, the replica is the
+// destination of the mirror, so its health is reported instead.
+func (c *TridentCrdController) getTMRMirrorHealth(
+	ctx context.Context, relationship *netappv1.TridentMirrorRelationship,
+) (*storage.MirrorHealth, error) {
+	volume, err := c.getTMRLocalVolume(ctx, relationship)
+	if err != nil {
+		return nil, err
+	}
+
+	switch relationship.Spec.MirrorState {
+	case netappv1.MirrorStateEstablished, netappv1.MirrorStateReestablished:
+		return c.orchestrator.GetMirrorHealth(ctx, volume.BackendUUID, volume.Config.MirrorHandle,
+			relationship.Spec.VolumeMappings[0].RemoteVolumeHandle)
+
+	case netappv1.MirrorStatePromoted:
+		if volume.Config.ReplicaVolume == "" {
+			return nil, nil
+		}
+		replica, err := c.orchestrator.GetVolume(ctx, volume.Config.ReplicaVolume)
+		if err != nil {
+			return nil, err
+		}
+		return c.orchestrator.GetMirrorHealth(ctx, replica.BackendUUID, replica.Config.MirrorHandle,
+			volume.Config.MirrorHandle)
+	}
+
+	return nil, nil
+}
+
+// getTMRLocalVolume returns the Trident volume bound to a TMR's local PVC
+func (c *TridentCrdController) getTMRLocalVolume(
+	ctx context.Context, relationship *netappv1.TridentMirrorRelationship,
+) (*storage.VolumeExternal, error) {
+	localPVCName := relationship.Spec.VolumeMappings[0].LocalPVCName
+	localPVC, err := c.kubeClientset.CoreV1().PersistentVolumeClaims(relationship.Namespace).Get(
+		ctx, localPVCName, metav1.GetOptions{},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if localPVC.Spec.VolumeName == "" {
+		return nil, utils.NotFoundError(fmt.Sprintf("PVC %s is not bound", localPVCName))
+	}
+
+	localPV, err := c.kubeClientset.CoreV1().PersistentVolumes().Get(ctx, localPVC.Spec.VolumeName,
+		metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if localPV.Spec.CSI == nil {
+		return nil, utils.NotFoundError(fmt.Sprintf("PV %s is not a CSI volume", localPV.Name))
+	}
+
+	return c.orchestrator.GetVolume(ctx, localPV.Spec.CSI.VolumeHandle)
+}
+
+// updateTMRHealth updates the health fields of a TMR's status condition. Unlike updateTMRStatus, the
+// last transition time is preserved, since the mirror state has not changed.
+func (c *TridentCrdController) updateTMRHealth(
+	ctx context.Context,
+	relationship *netappv1.TridentMirrorRelationship,
+	statusCondition *netappv1.TridentMirrorRelationshipCondition,
+) (*netappv1.TridentMirrorRelationship, error) {
+	mirrorRCopy := relationship.DeepCopy()
+	mirrorRCopy.Status.Conditions[0] = statusCondition
+
+	return c.crdClientset.TridentV1().TridentMirrorRelationships(mirrorRCopy.Namespace).UpdateStatus(
+		ctx, mirrorRCopy, updateOpts,
+	)
+}
@@ -0,0 +1,211 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package crd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	mockcore "github.com/netapp/trident/mocks/mock_core"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+func newMirrorHealthTestController(
+	t *testing.T, orchestrator *mockcore.MockOrchestrator, relationship *netappv1.TridentMirrorRelationship,
+) *TridentCrdController {
+	ctx := context.Background()
+	kubeClient := GetTestKubernetesClientset()
+	crdClient := GetTestCrdClientset()
+
+	_, err := kubeClient.CoreV1().PersistentVolumeClaims("default").Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc1", Namespace: "default"},
+		Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: "pvc-1"},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	_, err = kubeClient.CoreV1().PersistentVolumes().Create(ctx, &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc-1"},
+		Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+			CSI: &corev1.CSIPersistentVolumeSource{VolumeHandle: "pvc-1"},
+		}},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	_, err = crdClient.TridentV1().TridentMirrorRelationships("default").Create(ctx, relationship,
+		metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	controller, err := newTridentCrdControllerImpl(orchestrator, "trident", kubeClient,
+		GetTestSnapshotClientset(), crdClient)
+	assert.NoError(t, err)
+	return controller
+}
+
+func newEstablishedTMR(rpo string) *netappv1.TridentMirrorRelationship {
+	return &netappv1.TridentMirrorRelationship{
+		ObjectMeta: metav1.ObjectMeta{Name: "tmr1", Namespace: "default"},
+		Spec: netappv1.TridentMirrorRelationshipSpec{
+			MirrorState:            netappv1.MirrorStateEstablished,
+			RecoveryPointObjective: rpo,
+			VolumeMappings: []*netappv1.TridentMirrorRelationshipVolumeMapping{{
+				LocalPVCName:       "pvc1",
+				RemoteVolumeHandle: "svm1:vol1",
+			}},
+		},
+		Status: netappv1.TridentMirrorRelationshipStatus{
+			Conditions: []*netappv1.TridentMirrorRelationshipCondition{{
+				MirrorState:        netappv1.MirrorStateEstablished,
+				LastTransitionTime: "2022-01-01T00:00:00Z",
+			}},
+		},
+	}
+}
+
+func TestCheckTMRHealth(t *testing.T) {
+	ctx := context.Background()
+	volume := &storage.VolumeExternal{
+		Config:      &storage.VolumeConfig{Name: "pvc-1", MirrorHandle: "svm2:vol1"},
+		BackendUUID: "backend2",
+	}
+	lastTransferTime := time.Now().Add(-30 * time.Minute)
+
+	tests := []struct {
+		name           string
+		rpo            string
+		health         *storage.MirrorHealth
+		expectedHealth string
+		expectedMetric float64
+	}{
+		{
+			name:           "Healthy",
+			rpo:            "1h",
+			health:         &storage.MirrorHealth{Healthy: true, LastTransferTime: &lastTransferTime},
+			expectedHealth: netappv1.MirrorHealthHealthy,
+			expectedMetric: 1,
+		},
+		{
+			name:           "NoRPO",
+			health:         &storage.MirrorHealth{Healthy: true, LastTransferTime: &lastTransferTime},
+			expectedHealth: netappv1.MirrorHealthHealthy,
+			expectedMetric: 1,
+		},
+		{
+			name:           "Lagging",
+			rpo:            "15m",
+			health:         &storage.MirrorHealth{Healthy: true, LastTransferTime: &lastTransferTime},
+			expectedHealth: netappv1.MirrorHealthLagging,
+			expectedMetric: 0,
+		},
+		{
+			name: "Unhealthy",
+			rpo:  "1h",
+			health: &storage.MirrorHealth{
+				Healthy: false, UnhealthyReason: "transfer failed", LastTransferTime: &lastTransferTime,
+			},
+			expectedHealth: netappv1.MirrorHealthUnhealthy,
+			expectedMetric: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			orchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+			relationship := newEstablishedTMR(test.rpo)
+			controller := newMirrorHealthTestController(t, orchestrator, relationship)
+
+			orchestrator.EXPECT().GetVolume(gomock.Any(), "pvc-1").Return(volume, nil)
+			orchestrator.EXPECT().GetMirrorHealth(gomock.Any(), "backend2", "svm2:vol1", "svm1:vol1").
+				Return(test.health, nil)
+
+			controller.checkTMRHealth(ctx, relationship)
+
+			updated, err := controller.crdClientset.TridentV1().TridentMirrorRelationships("default").Get(
+				ctx, "tmr1", metav1.GetOptions{})
+			assert.NoError(t, err)
+			condition := updated.Status.Conditions[0]
+			assert.Equal(t, test.expectedHealth, condition.Health)
+			assert.Equal(t, netappv1.MirrorStateEstablished, condition.MirrorState, "state should not change")
+			assert.Equal(t, "2022-01-01T00:00:00Z", condition.LastTransitionTime, "transition time should not change")
+			assert.Equal(t, lastTransferTime.Format(time.RFC3339), condition.LastTransferTime)
+			assert.Equal(t, "30m0s", condition.ReplicationLag)
+
+			assert.Equal(t, test.expectedMetric,
+				testutil.ToFloat64(mirrorHealthyGauge.WithLabelValues("default", "tmr1", "pvc1")))
+			assert.InDelta(t, 1800,
+				testutil.ToFloat64(mirrorReplicationLagGauge.WithLabelValues("default", "tmr1", "pvc1")), 1)
+		})
+	}
+}
+
+func TestCheckTMRHealth_PromotedReplica(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	orchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+
+	relationship := newEstablishedTMR("")
+	relationship.Spec.MirrorState = netappv1.MirrorStatePromoted
+	relationship.Status.Conditions[0].MirrorState = netappv1.MirrorStatePromoted
+	controller := newMirrorHealthTestController(t, orchestrator, relationship)
+
+	volume := &storage.VolumeExternal{
+		Config:      &storage.VolumeConfig{Name: "pvc-1", MirrorHandle: "svm1:vol1", ReplicaVolume: "pvc-1-replica"},
+		BackendUUID: "backend1",
+	}
+	replica := &storage.VolumeExternal{
+		Config:      &storage.VolumeConfig{Name: "pvc-1-replica", MirrorHandle: "svm2:vol1"},
+		BackendUUID: "backend2",
+	}
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "pvc-1").Return(volume, nil)
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "pvc-1-replica").Return(replica, nil)
+	orchestrator.EXPECT().GetMirrorHealth(gomock.Any(), "backend2", "svm2:vol1", "svm1:vol1").
+		Return(&storage.MirrorHealth{Healthy: true}, nil)
+
+	controller.checkTMRHealth(ctx, relationship)
+
+	updated, err := controller.crdClientset.TridentV1().TridentMirrorRelationships("default").Get(
+		ctx, "tmr1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, netappv1.MirrorHealthHealthy, updated.Status.Conditions[0].Health)
+	assert.Empty(t, updated.Status.Conditions[0].ReplicationLag)
+}
+
+func TestCheckTMRHealth_Skipped(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	orchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+
+	// A relationship that has not reached its desired state is not checked
+	relationship := newEstablishedTMR("")
+	relationship.Status.Conditions[0].MirrorState = netappv1.MirrorStateEstablishing
+	controller := newMirrorHealthTestController(t, orchestrator, relationship)
+	controller.checkTMRHealth(ctx, relationship)
+
+	// A promoted volume without a replica has no mirror to check
+	relationship = newEstablishedTMR("")
+	relationship.Spec.MirrorState = netappv1.MirrorStatePromoted
+	relationship.Status.Conditions[0].MirrorState = netappv1.MirrorStatePromoted
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "pvc-1").Return(
+		&storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "pvc-1"}}, nil)
+	controller.checkTMRHealth(ctx, relationship)
+
+	// Backends that cannot report mirror health are ignored
+	relationship = newEstablishedTMR("")
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "pvc-1").Return(
+		&storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "pvc-1"}}, nil)
+	orchestrator.EXPECT().GetMirrorHealth(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		nil, utils.UnsupportedError("unsupported"))
+	controller.checkTMRHealth(ctx, relationship)
+
+	updated, err := controller.crdClientset.TridentV1().TridentMirrorRelationships("default").Get(
+		ctx, "tmr1", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, updated.Status.Conditions[0].Health)
+}
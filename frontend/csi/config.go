@@ -12,4 +12,14 @@ const (
 	// CSI supported features
 	CSIBlockVolumes  controllerhelpers.Feature = "CSI_BLOCK_VOLUMES"
 	ExpandCSIVolumes controllerhelpers.Feature = "EXPAND_CSI_VOLUMES"
+
+	// Kubernetes sets this volume context key for CSI ephemeral (inline) volumes
+	EphemeralVolumeContextKey = "csi.storage.k8s.io/ephemeral"
+
+	// Volume attributes of CSI ephemeral volumes
+	EphemeralStorageClassAttribute = "storageClass"
+	EphemeralSizeAttribute         = "size"
+	EphemeralFsTypeAttribute       = "fsType"
+
+	DefaultEphemeralVolumeSize = "1Gi"
 )
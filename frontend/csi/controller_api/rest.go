@@ -16,6 +16,7 @@ import (
 
 	"github.com/netapp/trident/config"
 	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
 
@@ -249,3 +250,159 @@ func (c *ControllerRestClient) UpdateVolumeLUKSPassphraseNames(
 	}
 	return nil
 }
+
+type VolumeLUKSWrappedKeyResponse struct {
+	WrappedKey *utils.LUKSWrappedKey `json:"wrappedKey"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// GetVolumeLUKSWrappedKey requests the KMS-wrapped LUKS key for a volume from the Trident controller.  A nil key
+// means none has been recorded yet.
+func (c *ControllerRestClient) GetVolumeLUKSWrappedKey(
+	ctx context.Context, volumeName string,
+) (*utils.LUKSWrappedKey, error) {
+	url := config.VolumeURL + "/" + volumeName + "/luksWrappedKey"
+	resp, respBody, err := c.InvokeAPI(ctx, nil, "GET", url, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not communicate with the Trident CSI Controller: %v", err)
+	}
+	keyResponse := VolumeLUKSWrappedKeyResponse{}
+	if err := json.Unmarshal(respBody, &keyResponse); err != nil {
+		return nil, fmt.Errorf("could not parse LUKS key response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not get volume LUKS key: %s", keyResponse.Error)
+	}
+	return keyResponse.WrappedKey, nil
+}
+
+// UpdateVolumeLUKSWrappedKey records the KMS-wrapped LUKS key for a volume in the Trident controller, replacing
+// previousWrappedKey.  If the volume's key is no longer previousWrappedKey, a FoundError is returned.
+func (c *ControllerRestClient) UpdateVolumeLUKSWrappedKey(
+	ctx context.Context, volumeName string, wrappedKey *utils.LUKSWrappedKey, previousWrappedKey string,
+) error {
+	body, err := json.Marshal(&utils.LUKSWrappedKeyUpdate{
+		LUKSWrappedKey:     *wrappedKey,
+		PreviousWrappedKey: previousWrappedKey,
+	})
+	if err != nil {
+		return fmt.Errorf("could not marshal JSON; %v", err)
+	}
+	url := config.VolumeURL + "/" + volumeName + "/luksWrappedKey"
+	resp, _, err := c.InvokeAPI(ctx, body, "PUT", url, false, false)
+	if err != nil {
+		return fmt.Errorf("could not log into the Trident CSI Controller: %v", err)
+	}
+	if resp.StatusCode == http.StatusConflict {
+		return utils.FoundError(fmt.Sprintf("LUKS key for volume %s has already been replaced", volumeName))
+	}
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("could not update volume LUKS key")
+	}
+	return nil
+}
+
+type AddVolumeResponse struct {
+	BackendID string `json:"backend"`
+	Error     string `json:"error,omitempty"`
+}
+
+// CreateVolume asks the Trident controller to provision a volume, which the node uses for CSI ephemeral volumes.
+func (c *ControllerRestClient) CreateVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) error {
+	body, err := json.Marshal(volumeConfig)
+	if err != nil {
+		return fmt.Errorf("could not marshal JSON; %v", err)
+	}
+	resp, respBody, err := c.InvokeAPI(ctx, body, "POST", config.VolumeURL, false, false)
+	if err != nil {
+		return fmt.Errorf("could not communicate with the Trident CSI Controller: %v", err)
+	}
+	addResponse := AddVolumeResponse{}
+	if err := json.Unmarshal(respBody, &addResponse); err != nil {
+		return fmt.Errorf("could not parse add volume response: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("could not create volume %s: %s", volumeConfig.Name, addResponse.Error)
+	}
+	return nil
+}
+
+type GetVolumeResponse struct {
+	Volume *storage.VolumeExternal `json:"volume"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+// GetVolume requests a volume from the Trident controller.  A NotFoundError is returned if the volume does not exist.
+func (c *ControllerRestClient) GetVolume(ctx context.Context, volumeName string) (*storage.VolumeExternal, error) {
+	resp, respBody, err := c.InvokeAPI(ctx, nil, "GET", config.VolumeURL+"/"+volumeName, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not communicate with the Trident CSI Controller: %v", err)
+	}
+	getResponse := GetVolumeResponse{}
+	if err := json.Unmarshal(respBody, &getResponse); err != nil {
+		return nil, fmt.Errorf("could not parse volume: %v", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return getResponse.Volume, nil
+	case http.StatusNotFound:
+		return nil, utils.NotFoundError(fmt.Sprintf("volume %s not found", volumeName))
+	default:
+		return nil, fmt.Errorf("could not get volume %s: %s", volumeName, getResponse.Error)
+	}
+}
+
+// DeleteVolume asks the Trident controller to delete a volume.  Deleting a volume that does not exist succeeds.
+func (c *ControllerRestClient) DeleteVolume(ctx context.Context, volumeName string) error {
+	resp, _, err := c.InvokeAPI(ctx, nil, "DELETE", config.VolumeURL+"/"+volumeName, false, false)
+	if err != nil {
+		return fmt.Errorf("could not communicate with the Trident CSI Controller: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("could not delete volume %s", volumeName)
+	}
+	return nil
+}
+
+type PublishVolumeResponse struct {
+	PublishInfo *utils.VolumePublishInfo `json:"publishInfo"`
+	Error       string                   `json:"error,omitempty"`
+}
+
+// PublishVolume asks the Trident controller to publish a volume to a node and returns the details needed to attach
+// it.  Only the read-only flag and access mode of the supplied publish info are used.
+func (c *ControllerRestClient) PublishVolume(
+	ctx context.Context, volumeName, nodeName string, publishInfo *utils.VolumePublishInfo,
+) (*utils.VolumePublishInfo, error) {
+	body, err := json.Marshal(publishInfo)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal JSON; %v", err)
+	}
+	url := config.PublicationURL + "/" + volumeName + "/" + nodeName
+	resp, respBody, err := c.InvokeAPI(ctx, body, "POST", url, false, true)
+	if err != nil {
+		return nil, fmt.Errorf("could not communicate with the Trident CSI Controller: %v", err)
+	}
+	publishResponse := PublishVolumeResponse{}
+	if err := json.Unmarshal(respBody, &publishResponse); err != nil {
+		return nil, fmt.Errorf("could not parse publish volume response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not publish volume %s: %s", volumeName, publishResponse.Error)
+	}
+	return publishResponse.PublishInfo, nil
+}
+
+// UnpublishVolume asks the Trident controller to unpublish a volume from a node.  Unpublishing a volume that does
+// not exist succeeds.
+func (c *ControllerRestClient) UnpublishVolume(ctx context.Context, volumeName, nodeName string) error {
+	url := config.PublicationURL + "/" + volumeName + "/" + nodeName
+	resp, _, err := c.InvokeAPI(ctx, nil, "DELETE", url, false, false)
+	if err != nil {
+		return fmt.Errorf("could not communicate with the Trident CSI Controller: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("could not unpublish volume %s", volumeName)
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
 
@@ -457,3 +458,253 @@ func TestUpdateVolumeLUKSPassphraseNames(t *testing.T) {
 	err = controllerRestClient.UpdateVolumeLUKSPassphraseNames(ctx, "test-vol", []string{"A"})
 	assert.Error(t, err)
 }
+
+func TestGetVolumeLUKSWrappedKey(t *testing.T) {
+	// Positive
+	controllerRestClient := ControllerRestClient{}
+	ctx = context.Background()
+	wrappedKey := &utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "wrapped"}
+	mockGetVolumeLUKSWrappedKey := func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, VolumeLUKSWrappedKeyResponse{WrappedKey: wrappedKey}, http.StatusOK)
+	}
+
+	server := getHttpServer(config.VolumeURL+"/"+"test-vol/luksWrappedKey", mockGetVolumeLUKSWrappedKey)
+	controllerRestClient.url = server.URL
+	result, err := controllerRestClient.GetVolumeLUKSWrappedKey(ctx, "test-vol")
+	assert.NoError(t, err)
+	assert.Equal(t, wrappedKey, result)
+	server.Close()
+
+	// Positive: No key recorded yet
+	mockGetVolumeLUKSWrappedKey = func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, VolumeLUKSWrappedKeyResponse{}, http.StatusOK)
+	}
+
+	server = getHttpServer(config.VolumeURL+"/"+"test-vol/luksWrappedKey", mockGetVolumeLUKSWrappedKey)
+	controllerRestClient.url = server.URL
+	result, err = controllerRestClient.GetVolumeLUKSWrappedKey(ctx, "test-vol")
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	server.Close()
+
+	// Negative: Volume not found
+	mockGetVolumeLUKSWrappedKey = func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, VolumeLUKSWrappedKeyResponse{Error: "not found"}, http.StatusNotFound)
+	}
+
+	server = getHttpServer(config.VolumeURL+"/"+"test-vol/luksWrappedKey", mockGetVolumeLUKSWrappedKey)
+	controllerRestClient.url = server.URL
+	_, err = controllerRestClient.GetVolumeLUKSWrappedKey(ctx, "test-vol")
+	assert.Error(t, err)
+	server.Close()
+
+	// Negative: Cannot connect to trident api
+	controllerRestClient = ControllerRestClient{}
+	_, err = controllerRestClient.GetVolumeLUKSWrappedKey(ctx, "test-vol")
+	assert.Error(t, err)
+}
+
+func TestUpdateVolumeLUKSWrappedKey(t *testing.T) {
+	// Positive
+	controllerRestClient := ControllerRestClient{}
+	ctx = context.Background()
+	wrappedKey := &utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "wrapped"}
+	mockUpdateVolumeLUKSWrappedKey := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+		assert.NoError(t, err)
+
+		received := &utils.LUKSWrappedKeyUpdate{}
+		err = json.Unmarshal(body, received)
+		assert.NoError(t, err, "Got: ", body)
+		assert.Equal(t, *wrappedKey, received.LUKSWrappedKey)
+		assert.Equal(t, "old", received.PreviousWrappedKey)
+
+		createResponse(w, "", http.StatusOK)
+	}
+
+	server := getHttpServer(config.VolumeURL+"/"+"test-vol/luksWrappedKey", mockUpdateVolumeLUKSWrappedKey)
+	controllerRestClient.url = server.URL
+	err := controllerRestClient.UpdateVolumeLUKSWrappedKey(ctx, "test-vol", wrappedKey, "old")
+	assert.NoError(t, err)
+	server.Close()
+
+	// Negative: Key replaced since it was read
+	mockUpdateVolumeLUKSWrappedKey = func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, "", http.StatusConflict)
+	}
+
+	server = getHttpServer(config.VolumeURL+"/"+"test-vol/luksWrappedKey", mockUpdateVolumeLUKSWrappedKey)
+	controllerRestClient.url = server.URL
+	err = controllerRestClient.UpdateVolumeLUKSWrappedKey(ctx, "test-vol", wrappedKey, "old")
+	assert.True(t, utils.IsFoundError(err))
+	server.Close()
+
+	// Negative: Key revoked
+	mockUpdateVolumeLUKSWrappedKey = func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, "", http.StatusBadRequest)
+	}
+
+	server = getHttpServer(config.VolumeURL+"/"+"test-vol/luksWrappedKey", mockUpdateVolumeLUKSWrappedKey)
+	controllerRestClient.url = server.URL
+	err = controllerRestClient.UpdateVolumeLUKSWrappedKey(ctx, "test-vol", wrappedKey, "old")
+	assert.Error(t, err)
+	server.Close()
+
+	// Negative: Cannot connect to trident api
+	controllerRestClient = ControllerRestClient{}
+	err = controllerRestClient.UpdateVolumeLUKSWrappedKey(ctx, "test-vol", wrappedKey, "old")
+	assert.Error(t, err)
+}
+
+func TestGetVolume(t *testing.T) {
+	// Positive
+	controllerRestClient := ControllerRestClient{}
+	ctx = context.Background()
+	volume := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "test-vol"}}
+	mockGetVolume := func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, GetVolumeResponse{Volume: volume}, http.StatusOK)
+	}
+
+	server := getHttpServer(config.VolumeURL+"/"+"test-vol", mockGetVolume)
+	controllerRestClient.url = server.URL
+	result, err := controllerRestClient.GetVolume(ctx, "test-vol")
+	assert.NoError(t, err)
+	assert.Equal(t, volume, result)
+	server.Close()
+
+	// Negative: Volume not found
+	mockGetVolume = func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, GetVolumeResponse{Error: "not found"}, http.StatusNotFound)
+	}
+
+	server = getHttpServer(config.VolumeURL+"/"+"test-vol", mockGetVolume)
+	controllerRestClient.url = server.URL
+	_, err = controllerRestClient.GetVolume(ctx, "test-vol")
+	assert.True(t, utils.IsNotFoundError(err))
+	server.Close()
+
+	// Negative: Cannot connect to trident api
+	controllerRestClient = ControllerRestClient{}
+	_, err = controllerRestClient.GetVolume(ctx, "test-vol")
+	assert.Error(t, err)
+}
+
+func TestCreateVolume(t *testing.T) {
+	// Positive
+	controllerRestClient := ControllerRestClient{}
+	ctx = context.Background()
+	volConfig := &storage.VolumeConfig{Name: "test-vol", Size: "1073741824", StorageClass: "gold"}
+	mockCreateVolume := func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+		assert.NoError(t, err)
+
+		received := &storage.VolumeConfig{}
+		err = json.Unmarshal(body, received)
+		assert.NoError(t, err, "Got: ", body)
+		assert.Equal(t, volConfig, received)
+
+		createResponse(w, AddVolumeResponse{BackendID: "backend"}, http.StatusCreated)
+	}
+
+	server := getHttpServer(config.VolumeURL, mockCreateVolume)
+	controllerRestClient.url = server.URL
+	err := controllerRestClient.CreateVolume(ctx, volConfig)
+	assert.NoError(t, err)
+	server.Close()
+
+	// Negative: No matching storage
+	mockCreateVolume = func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, AddVolumeResponse{Error: "no suitable pools"}, http.StatusBadRequest)
+	}
+
+	server = getHttpServer(config.VolumeURL, mockCreateVolume)
+	controllerRestClient.url = server.URL
+	err = controllerRestClient.CreateVolume(ctx, volConfig)
+	assert.Error(t, err)
+	server.Close()
+}
+
+func TestDeleteVolume(t *testing.T) {
+	controllerRestClient := ControllerRestClient{}
+	ctx = context.Background()
+
+	for _, statusCode := range []int{http.StatusOK, http.StatusNotFound, http.StatusBadRequest} {
+		mockDeleteVolume := func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			createResponse(w, "", statusCode)
+		}
+
+		server := getHttpServer(config.VolumeURL+"/"+"test-vol", mockDeleteVolume)
+		controllerRestClient.url = server.URL
+		err := controllerRestClient.DeleteVolume(ctx, "test-vol")
+		if statusCode == http.StatusBadRequest {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+		server.Close()
+	}
+}
+
+func TestPublishVolume(t *testing.T) {
+	// Positive
+	controllerRestClient := ControllerRestClient{}
+	ctx = context.Background()
+	request := &utils.VolumePublishInfo{}
+	request.AccessMode = 1
+	publishInfo := &utils.VolumePublishInfo{HostName: "node1"}
+	publishInfo.NfsServerIP = "1.1.1.1"
+	publishInfo.NfsPath = "/vol1"
+	mockPublishVolume := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+		assert.NoError(t, err)
+
+		received := &utils.VolumePublishInfo{}
+		err = json.Unmarshal(body, received)
+		assert.NoError(t, err, "Got: ", body)
+		assert.Equal(t, request, received)
+
+		createResponse(w, PublishVolumeResponse{PublishInfo: publishInfo}, http.StatusOK)
+	}
+
+	server := getHttpServer(config.PublicationURL+"/test-vol/node1", mockPublishVolume)
+	controllerRestClient.url = server.URL
+	result, err := controllerRestClient.PublishVolume(ctx, "test-vol", "node1", request)
+	assert.NoError(t, err)
+	assert.Equal(t, publishInfo, result)
+	server.Close()
+
+	// Negative: Node is fenced
+	mockPublishVolume = func(w http.ResponseWriter, r *http.Request) {
+		createResponse(w, PublishVolumeResponse{Error: "node node1 is fenced"}, http.StatusBadRequest)
+	}
+
+	server = getHttpServer(config.PublicationURL+"/test-vol/node1", mockPublishVolume)
+	controllerRestClient.url = server.URL
+	_, err = controllerRestClient.PublishVolume(ctx, "test-vol", "node1", request)
+	assert.Error(t, err)
+	server.Close()
+}
+
+func TestUnpublishVolume(t *testing.T) {
+	controllerRestClient := ControllerRestClient{}
+	ctx = context.Background()
+
+	for _, statusCode := range []int{http.StatusOK, http.StatusNotFound, http.StatusBadRequest} {
+		mockUnpublishVolume := func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			createResponse(w, "", statusCode)
+		}
+
+		server := getHttpServer(config.PublicationURL+"/test-vol/node1", mockUnpublishVolume)
+		controllerRestClient.url = server.URL
+		err := controllerRestClient.UnpublishVolume(ctx, "test-vol", "node1")
+		if statusCode == http.StatusBadRequest {
+			assert.Error(t, err)
+		} else {
+			assert.NoError(t, err)
+		}
+		server.Close()
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
 
@@ -22,4 +23,15 @@ type TridentController interface {
 	GetChap(ctx context.Context, volume, node string) (*utils.IscsiChapInfo, error)
 	UpdateVolumePublication(ctx context.Context, publication *utils.VolumePublicationExternal) error
 	UpdateVolumeLUKSPassphraseNames(ctx context.Context, volume string, passphraseNames []string) error
+	GetVolumeLUKSWrappedKey(ctx context.Context, volume string) (*utils.LUKSWrappedKey, error)
+	UpdateVolumeLUKSWrappedKey(
+		ctx context.Context, volume string, wrappedKey *utils.LUKSWrappedKey, previousWrappedKey string,
+	) error
+	CreateVolume(ctx context.Context, volumeConfig *storage.VolumeConfig) error
+	GetVolume(ctx context.Context, volume string) (*storage.VolumeExternal, error)
+	DeleteVolume(ctx context.Context, volume string) error
+	PublishVolume(
+		ctx context.Context, volume, node string, publishInfo *utils.VolumePublishInfo,
+	) (*utils.VolumePublishInfo, error)
+	UnpublishVolume(ctx context.Context, volume, node string) error
 }
@@ -18,6 +18,7 @@ const (
 	PreSyncCacheWaitPeriod  = 10 * time.Second
 	PostSyncCacheWaitPeriod = 30 * time.Second
 	ResizeSyncPeriod        = 3 * time.Minute
+	LUKSRotationSyncPeriod  = 2 * time.Minute
 	PVDeleteWaitPeriod      = 30 * time.Second
 	PodDeleteWaitPeriod     = 60 * time.Second
 	ImportPVCacheWaitPeriod = 180 * time.Second
@@ -59,6 +60,7 @@ const (
 	AnnMirrorRelationship = annPrefix + "/mirrorRelationship"
 	AnnVolumeShareFromPVC = annPrefix + "/shareFromPVC"
 	AnnVolumeShareToNS    = annPrefix + "/shareToNamespace"
+	AnnSCSIReservation    = annPrefix + "/scsiPersistentReservation"
 )
 
 var features = map[controllerhelpers.Feature]*utils.Version{
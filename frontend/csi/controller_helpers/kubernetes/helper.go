@@ -472,6 +472,14 @@ func getVolumeConfig(
 		Logc(ctx).Warnf("unable to parse notManaged annotation into bool; %v", err)
 	}
 
+	var scsiReservation bool
+	if getAnnotation(annotations, AnnSCSIReservation) != "" {
+		scsiReservation, err = strconv.ParseBool(getAnnotation(annotations, AnnSCSIReservation))
+		if err != nil {
+			Logc(ctx).Warnf("unable to parse scsiPersistentReservation annotation into bool; %v", err)
+		}
+	}
+
 	return &storage.VolumeConfig{
 		Name:                name,
 		Size:                fmt.Sprintf("%d", size.Value()),
@@ -493,6 +501,8 @@ func getVolumeConfig(
 		MountOptions:        strings.Join(storageClass.MountOptions, ","),
 		RequisiteTopologies: requisiteTopology,
 		PreferredTopologies: preferredTopology,
+
+		SCSIPersistentReservation: scsiReservation,
 	}
 }
 
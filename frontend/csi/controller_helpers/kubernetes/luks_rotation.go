@@ -0,0 +1,392 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/netapp/trident/frontend/csi"
+	controllerhelpers "github.com/netapp/trident/frontend/csi/controller_helpers"
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+/////////////////////////////////////////////////////////////////////////////
+//
+// This file contains the code that rotates the passphrases of LUKS volumes
+// when their node-stage secrets change.
+//
+/////////////////////////////////////////////////////////////////////////////
+
+// reconcileLUKSPassphrasesPeriodically runs LUKS passphrase reconciliation until the stop channel is closed.
+func (h *helper) reconcileLUKSPassphrasesPeriodically(stopChan chan struct{}) {
+	ctx := GenerateRequestContext(context.Background(), "", ContextSourcePeriodic)
+
+	Logc(ctx).Info("Starting periodic LUKS passphrase rotation service.")
+	defer Logc(ctx).Info("Stopping periodic LUKS passphrase rotation service.")
+
+	ticker := time.NewTicker(LUKSRotationSyncPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			h.reconcileLUKSPassphrases(ctx)
+		}
+	}
+}
+
+// reconcileLUKSPassphrases compares the passphrase names Trident has recorded for every LUKS volume with the
+// current passphrase in the volume's node-stage secret, and rotates the passphrase of any staged volume that
+// is not yet using it.  Volumes whose secret selects a key-management service are skipped, since their passphrase
+// is derived from their data-encryption key when they are staged.
+func (h *helper) reconcileLUKSPassphrases(ctx context.Context) {
+	Logc(ctx).Trace("Periodic LUKS passphrase reconciliation beginning.")
+
+	secretsByRef := make(map[string]map[string]string)
+	luksVolumes := make(map[string]bool)
+
+	for _, obj := range h.pvIndexer.List() {
+		pv, ok := obj.(*v1.PersistentVolume)
+		if !ok || pv.Spec.CSI == nil || pv.Spec.CSI.Driver != csi.Provisioner {
+			continue
+		}
+		secretRef := pv.Spec.CSI.NodeStageSecretRef
+		if secretRef == nil {
+			continue
+		}
+
+		volume, err := h.orchestrator.GetVolume(ctx, pv.Name)
+		if err != nil {
+			if !utils.IsNotFoundError(err) {
+				Logc(ctx).WithField("volume", pv.Name).WithError(err).Warning("Could not get volume.")
+			}
+			continue
+		}
+		if isLUKS, _ := strconv.ParseBool(volume.Config.LUKSEncryption); !isLUKS {
+			continue
+		}
+		luksVolumes[volume.Config.Name] = true
+
+		secretKey := secretRef.Namespace + "/" + secretRef.Name
+		secrets, ok := secretsByRef[secretKey]
+		if !ok {
+			if secrets, err = h.getSecretData(ctx, secretRef.Namespace, secretRef.Name); err != nil {
+				Logc(ctx).WithFields(log.Fields{
+					"volume": volume.Config.Name,
+					"secret": secretKey,
+				}).WithError(err).Warning("Could not read node-stage secret for LUKS volume.")
+				continue
+			}
+			secretsByRef[secretKey] = secrets
+		}
+		if utils.IsLUKSKMSConfigured(secrets) {
+			delete(luksVolumes, volume.Config.Name)
+			continue
+		}
+
+		h.reconcileLUKSPassphrase(ctx, volume, secrets)
+	}
+
+	// Forget volumes that no longer exist or are no longer encrypted
+	h.luksRotationsLock.Lock()
+	for volumeName := range h.luksRotations {
+		if !luksVolumes[volumeName] {
+			delete(h.luksRotations, volumeName)
+		}
+	}
+	h.luksRotationsLock.Unlock()
+
+	Logc(ctx).Trace("Periodic LUKS passphrase reconciliation complete.")
+}
+
+// reconcileLUKSPassphrase brings a single LUKS volume, and the records of its snapshots, up to date with the
+// current passphrase in its node-stage secret.
+func (h *helper) reconcileLUKSPassphrase(
+	ctx context.Context, volume *storage.VolumeExternal, secrets map[string]string,
+) {
+	passphraseName, _, _, _ := utils.GetLUKSPassphrasesFromSecretMap(secrets)
+
+	rotation := h.getLUKSPassphraseRotation(volume.Config.Name)
+	if rotation == nil || rotation.PassphraseName != passphraseName {
+		// Either this volume was not seen before or its secret changed again, so start over
+		rotation = &storage.LUKSPassphraseRotation{
+			VolumeName:     volume.Config.Name,
+			PassphraseName: passphraseName,
+			State:          storage.LUKSPassphraseRotationPending,
+		}
+	}
+	defer h.setLUKSPassphraseRotation(rotation)
+
+	if passphraseName == "" {
+		rotation.State = storage.LUKSPassphraseRotationFailed
+		rotation.Message = "node-stage secret does not contain a LUKS passphrase name"
+		rotation.VolumePassphraseNames = volume.Config.LUKSPassphraseNames
+		return
+	}
+
+	if !usesOnlyLUKSPassphrase(volume.Config.LUKSPassphraseNames, passphraseName) {
+		volume = h.rotateLUKSPassphrase(ctx, volume, secrets, rotation)
+	} else if rotation.State != storage.LUKSPassphraseRotationComplete {
+		rotation.State = storage.LUKSPassphraseRotationComplete
+		rotation.Message = ""
+		if !rotation.StartTime.IsZero() {
+			rotation.CompletionTime = time.Now()
+		}
+	}
+
+	rotation.VolumePassphraseNames = volume.Config.LUKSPassphraseNames
+	rotation.Snapshots = h.reconcileSnapshotLUKSPassphraseNames(ctx, volume.Config.Name, rotation)
+}
+
+// rotateLUKSPassphrase asks a node on which the volume is published to rotate the volume's passphrase in place.
+// It returns the volume as Trident knows it after the attempt, since the node updates the passphrase names.
+func (h *helper) rotateLUKSPassphrase(
+	ctx context.Context, volume *storage.VolumeExternal, secrets map[string]string,
+	rotation *storage.LUKSPassphraseRotation,
+) *storage.VolumeExternal {
+	volumeName := volume.Config.Name
+
+	publications, err := h.orchestrator.ListVolumePublicationsForVolume(ctx, volumeName, nil)
+	if err != nil {
+		rotation.State = storage.LUKSPassphraseRotationFailed
+		rotation.Message = fmt.Sprintf("could not list publications; %v", err)
+		return volume
+	}
+	if len(publications) == 0 {
+		rotation.State = storage.LUKSPassphraseRotationPending
+		rotation.Message = "volume is not published; its passphrase will be rotated when it is next staged"
+		return volume
+	}
+
+	if rotation.StartTime.IsZero() {
+		rotation.StartTime = time.Now()
+	}
+	rotation.State = storage.LUKSPassphraseRotationRotating
+	rotation.Attempts++
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":         volumeName,
+		"passphraseName": rotation.PassphraseName,
+		"attempt":        rotation.Attempts,
+	}).Info("Rotating LUKS passphrase.")
+
+	// The LUKS header lives on the volume, so rotating it from any one node is sufficient
+	var failures []string
+	rotated := false
+	for _, publication := range publications {
+		if err = h.rotateLUKSPassphraseOnNode(ctx, volume, publication.NodeName, secrets); err != nil {
+			Logc(ctx).WithFields(log.Fields{
+				"volume": volumeName,
+				"node":   publication.NodeName,
+			}).WithError(err).Warning("Could not rotate LUKS passphrase on node.")
+			failures = append(failures, fmt.Sprintf("%s: %v", publication.NodeName, err))
+			continue
+		}
+		rotation.Node = publication.NodeName
+		rotated = true
+		break
+	}
+
+	if !rotated {
+		rotation.State = storage.LUKSPassphraseRotationFailed
+		rotation.Message = strings.Join(failures, "; ")
+		h.RecordVolumeEvent(ctx, volumeName, controllerhelpers.EventTypeWarning, "LUKSPassphraseRotationFailed",
+			fmt.Sprintf("Could not rotate LUKS passphrase to %s; %s", rotation.PassphraseName, rotation.Message))
+		return volume
+	}
+
+	updatedVolume, err := h.orchestrator.GetVolume(ctx, volumeName)
+	if err != nil {
+		rotation.Message = fmt.Sprintf("could not confirm passphrase rotation; %v", err)
+		return volume
+	}
+	if !usesOnlyLUKSPassphrase(updatedVolume.Config.LUKSPassphraseNames, rotation.PassphraseName) {
+		rotation.Message = "waiting for the node to confirm the current passphrase"
+		return updatedVolume
+	}
+
+	rotation.State = storage.LUKSPassphraseRotationComplete
+	rotation.Message = ""
+	rotation.CompletionTime = time.Now()
+	h.RecordVolumeEvent(ctx, volumeName, controllerhelpers.EventTypeNormal, "LUKSPassphraseRotated",
+		fmt.Sprintf("Rotated LUKS passphrase to %s.", rotation.PassphraseName))
+
+	return updatedVolume
+}
+
+// rotateLUKSPassphraseOnNode calls the REST interface of the named node to rotate the volume's passphrase.
+func (h *helper) rotateLUKSPassphraseOnNode(
+	ctx context.Context, volume *storage.VolumeExternal, nodeName string, secrets map[string]string,
+) error {
+	nodeClient, err := h.getNodeClient(ctx, nodeName)
+	if err != nil {
+		return err
+	}
+
+	return nodeClient.RotateLUKSPassphrase(ctx, volume.Config.Name, volume.Config.InternalName, secrets)
+}
+
+// getNodeClient returns a client for the REST interface of the named node.
+func (h *helper) getNodeClient(ctx context.Context, nodeName string) (nodeAPI.TridentNode, error) {
+	node, err := h.orchestrator.GetNode(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+	return h.newClientForNode(node)
+}
+
+// newClientForNode returns a client for the REST interface of a node.
+func (h *helper) newClientForNode(node *utils.Node) (nodeAPI.TridentNode, error) {
+	if node.RESTPort == "" {
+		return nil, utils.UnsupportedError(fmt.Sprintf("node %s does not have a REST interface", node.Name))
+	}
+
+	address := h.getNodeAddress(node)
+	if address == "" {
+		return nil, fmt.Errorf("could not determine an address for node %s", node.Name)
+	}
+
+	nodeClient, err := h.newNodeClient("https://" + net.JoinHostPort(address, node.RESTPort))
+	if err != nil {
+		return nil, fmt.Errorf("could not create REST client for node %s; %v", node.Name, err)
+	}
+	return nodeClient, nil
+}
+
+// getNodeAddress returns the internal IP that Kubernetes reports for a node, falling back to the first
+// address the Trident node registered.
+func (h *helper) getNodeAddress(node *utils.Node) string {
+	if h.nodeIndexer != nil {
+		if obj, exists, err := h.nodeIndexer.GetByKey(node.Name); err == nil && exists {
+			if k8sNode, ok := obj.(*v1.Node); ok {
+				for _, address := range k8sNode.Status.Addresses {
+					if address.Type == v1.NodeInternalIP && address.Address != "" {
+						return address.Address
+					}
+				}
+			}
+		}
+	}
+	if len(node.IPs) > 0 {
+		return node.IPs[0]
+	}
+	return ""
+}
+
+// reconcileSnapshotLUKSPassphraseNames removes stale passphrase names from snapshots that were created after the
+// volume's passphrase rotation completed, and returns the names of snapshots that may still require a previous
+// passphrase.  Snapshots taken before or during a rotation keep their names, since their LUKS headers are frozen
+// with whichever passphrase the volume had at the time.
+func (h *helper) reconcileSnapshotLUKSPassphraseNames(
+	ctx context.Context, volumeName string, rotation *storage.LUKSPassphraseRotation,
+) []string {
+	snapshots, err := h.orchestrator.ListSnapshotsForVolume(ctx, volumeName)
+	if err != nil {
+		Logc(ctx).WithField("volume", volumeName).WithError(err).Warning("Could not list snapshots for volume.")
+		return rotation.Snapshots
+	}
+
+	previousSnapshots := make([]string, 0)
+	for _, snapshot := range snapshots {
+		passphraseNames := snapshot.Config.LUKSPassphraseNames
+		if usesOnlyLUKSPassphrase(passphraseNames, rotation.PassphraseName) {
+			continue
+		}
+
+		if rotation.State == storage.LUKSPassphraseRotationComplete && !rotation.CompletionTime.IsZero() &&
+			utils.SliceContainsString(passphraseNames, rotation.PassphraseName) {
+
+			created, err := time.Parse(time.RFC3339, snapshot.Created)
+			if err == nil && created.After(rotation.CompletionTime) {
+				currentNames := []string{rotation.PassphraseName}
+				err = h.orchestrator.UpdateSnapshot(ctx, volumeName, snapshot.Config.Name, &currentNames)
+				if err == nil {
+					continue
+				}
+				Logc(ctx).WithFields(log.Fields{
+					"volume":   volumeName,
+					"snapshot": snapshot.Config.Name,
+				}).WithError(err).Warning("Could not update LUKS passphrase names for snapshot.")
+			}
+		}
+
+		previousSnapshots = append(previousSnapshots, snapshot.Config.Name)
+	}
+
+	sort.Strings(previousSnapshots)
+	return previousSnapshots
+}
+
+// getSecretData reads a secret and returns its data as strings.
+func (h *helper) getSecretData(ctx context.Context, namespace, name string) (map[string]string, error) {
+	secret, err := h.kubeClient.CoreV1().Secrets(namespace).Get(ctx, name, getOpts)
+	if err != nil {
+		return nil, err
+	}
+	secrets := make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		secrets[key] = string(value)
+	}
+	return secrets, nil
+}
+
+// getLUKSPassphraseRotation returns a copy of the rotation status for a volume, or nil if there is none.
+func (h *helper) getLUKSPassphraseRotation(volumeName string) *storage.LUKSPassphraseRotation {
+	h.luksRotationsLock.RLock()
+	defer h.luksRotationsLock.RUnlock()
+
+	rotation, ok := h.luksRotations[volumeName]
+	if !ok {
+		return nil
+	}
+	rotationCopy := *rotation
+	return &rotationCopy
+}
+
+func (h *helper) setLUKSPassphraseRotation(rotation *storage.LUKSPassphraseRotation) {
+	h.luksRotationsLock.Lock()
+	defer h.luksRotationsLock.Unlock()
+
+	h.luksRotations[rotation.VolumeName] = rotation
+}
+
+// ListLUKSPassphraseRotations returns the passphrase rotation status of every LUKS volume with a node-stage
+// secret.  If incompleteOnly is set, only volumes whose data or snapshots may still require a previous
+// passphrase are returned.
+func (h *helper) ListLUKSPassphraseRotations(
+	_ context.Context, incompleteOnly bool,
+) []*storage.LUKSPassphraseRotation {
+	h.luksRotationsLock.RLock()
+	defer h.luksRotationsLock.RUnlock()
+
+	rotations := make([]*storage.LUKSPassphraseRotation, 0, len(h.luksRotations))
+	for _, rotation := range h.luksRotations {
+		if incompleteOnly && !rotation.UsesPreviousPassphrase() {
+			continue
+		}
+		rotationCopy := *rotation
+		rotations = append(rotations, &rotationCopy)
+	}
+
+	sort.Slice(rotations, func(i, j int) bool { return rotations[i].VolumeName < rotations[j].VolumeName })
+	return rotations
+}
+
+// usesOnlyLUKSPassphrase returns true if the passphrase names consist of exactly the named passphrase.
+func usesOnlyLUKSPassphrase(passphraseNames []string, passphraseName string) bool {
+	return len(passphraseNames) == 1 && passphraseNames[0] == passphraseName
+}
@@ -0,0 +1,316 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/netapp/trident/frontend/csi"
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
+	. "github.com/netapp/trident/logger"
+	mockcore "github.com/netapp/trident/mocks/mock_core"
+	mockNodeAPI "github.com/netapp/trident/mocks/mock_frontend/mock_csi/mock_node_api"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	luksTestPVCUID    = "3f8b7e1a-6c2d-4e5f-9a0b-1c2d3e4f5a6b"
+	luksTestVolume    = "pvc-" + luksTestPVCUID
+	luksTestNamespace = "default"
+	luksTestSecret    = "luks-secret"
+	luksTestNode      = "node1"
+	luksTestNodeIP    = "10.0.0.1"
+	luksTestRESTPort  = "17546"
+)
+
+var luksTestSecrets = map[string]string{
+	"luks-passphrase-name":          "B",
+	"luks-passphrase":               "passphraseB",
+	"previous-luks-passphrase-name": "A",
+	"previous-luks-passphrase":      "passphraseA",
+}
+
+func newLUKSRotationTestPlugin(
+	t *testing.T,
+) (*mockcore.MockOrchestrator, *mockNodeAPI.MockTridentNode, *record.FakeRecorder, *helper) {
+	mockCtrl := gomock.NewController(t)
+	mockCore := mockcore.NewMockOrchestrator(mockCtrl)
+	mockNode := mockNodeAPI.NewMockTridentNode(mockCtrl)
+
+	secretData := make(map[string][]byte)
+	for key, value := range luksTestSecrets {
+		secretData[key] = []byte(value)
+	}
+	kubeClient := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: luksTestSecret, Namespace: luksTestNamespace},
+		Data:       secretData,
+	})
+
+	pvIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = pvIndexer.Add(&v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: luksTestVolume},
+		Spec: v1.PersistentVolumeSpec{
+			PersistentVolumeSource: v1.PersistentVolumeSource{
+				CSI: &v1.CSIPersistentVolumeSource{
+					Driver:             csi.Provisioner,
+					VolumeHandle:       luksTestVolume,
+					NodeStageSecretRef: &v1.SecretReference{Name: luksTestSecret, Namespace: luksTestNamespace},
+				},
+			},
+		},
+	})
+
+	pvcIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{uidIndex: MetaUIDKeyFunc})
+	_ = pvcIndexer.Add(&v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc1", Namespace: luksTestNamespace, UID: types.UID(luksTestPVCUID)},
+	})
+
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = nodeIndexer.Add(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: luksTestNode},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{
+				{Type: v1.NodeHostName, Address: luksTestNode},
+				{Type: v1.NodeInternalIP, Address: luksTestNodeIP},
+			},
+		},
+	})
+
+	recorder := record.NewFakeRecorder(10)
+
+	plugin := &helper{
+		orchestrator:  mockCore,
+		kubeClient:    kubeClient,
+		eventRecorder: recorder,
+		pvIndexer:     pvIndexer,
+		pvcIndexer:    pvcIndexer,
+		nodeIndexer:   nodeIndexer,
+		luksRotations: make(map[string]*storage.LUKSPassphraseRotation),
+		newNodeClient: func(url string) (nodeAPI.TridentNode, error) {
+			assert.Equal(t, "https://"+luksTestNodeIP+":"+luksTestRESTPort, url)
+			return mockNode, nil
+		},
+	}
+
+	return mockCore, mockNode, recorder, plugin
+}
+
+func luksTestVolumeExternal(passphraseNames ...string) *storage.VolumeExternal {
+	return &storage.VolumeExternal{
+		Config: &storage.VolumeConfig{
+			Name:                luksTestVolume,
+			InternalName:        "trident_" + luksTestVolume,
+			LUKSEncryption:      "true",
+			LUKSPassphraseNames: passphraseNames,
+		},
+	}
+}
+
+func luksTestSnapshot(name string, created time.Time, passphraseNames ...string) *storage.SnapshotExternal {
+	return &storage.SnapshotExternal{
+		Snapshot: storage.Snapshot{
+			Config: &storage.SnapshotConfig{
+				Name:                name,
+				VolumeName:          luksTestVolume,
+				LUKSPassphraseNames: passphraseNames,
+			},
+			Created: created.UTC().Format(time.RFC3339),
+		},
+	}
+}
+
+func TestReconcileLUKSPassphrases_Rotated(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourcePeriodic)
+	mockCore, mockNode, recorder, plugin := newLUKSRotationTestPlugin(t)
+
+	publication := &utils.VolumePublicationExternal{Name: luksTestVolume + "." + luksTestNode, NodeName: luksTestNode,
+		VolumeName: luksTestVolume}
+	node := &utils.Node{Name: luksTestNode, IPs: []string{"192.168.0.1"}, RESTPort: luksTestRESTPort}
+	oldSnapshot := luksTestSnapshot("snap1", time.Now().Add(-time.Hour), "A")
+
+	gomock.InOrder(
+		mockCore.EXPECT().GetVolume(gomock.Any(), luksTestVolume).Return(luksTestVolumeExternal("A"), nil),
+		mockCore.EXPECT().GetVolume(gomock.Any(), luksTestVolume).Return(luksTestVolumeExternal("B"), nil),
+	)
+	mockCore.EXPECT().ListVolumePublicationsForVolume(gomock.Any(), luksTestVolume, nil).
+		Return([]*utils.VolumePublicationExternal{publication}, nil)
+	mockCore.EXPECT().GetNode(gomock.Any(), luksTestNode).Return(node, nil)
+	mockNode.EXPECT().RotateLUKSPassphrase(gomock.Any(), luksTestVolume, "trident_"+luksTestVolume,
+		luksTestSecrets).Return(nil)
+	mockCore.EXPECT().ListSnapshotsForVolume(gomock.Any(), luksTestVolume).
+		Return([]*storage.SnapshotExternal{oldSnapshot}, nil)
+
+	plugin.reconcileLUKSPassphrases(ctx)
+
+	rotations := plugin.ListLUKSPassphraseRotations(ctx, false)
+	assert.Len(t, rotations, 1)
+	rotation := rotations[0]
+	assert.Equal(t, storage.LUKSPassphraseRotationComplete, rotation.State)
+	assert.Equal(t, "B", rotation.PassphraseName)
+	assert.Equal(t, []string{"B"}, rotation.VolumePassphraseNames)
+	assert.Equal(t, []string{"snap1"}, rotation.Snapshots)
+	assert.Equal(t, luksTestNode, rotation.Node)
+	assert.Equal(t, 1, rotation.Attempts)
+	assert.False(t, rotation.CompletionTime.IsZero())
+
+	// The old snapshot still needs passphrase A, so the rotation is reported as incomplete
+	assert.Len(t, plugin.ListLUKSPassphraseRotations(ctx, true), 1)
+
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "LUKSPassphraseRotated")
+}
+
+func TestReconcileLUKSPassphrases_NotPublished(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourcePeriodic)
+	mockCore, _, recorder, plugin := newLUKSRotationTestPlugin(t)
+
+	mockCore.EXPECT().GetVolume(gomock.Any(), luksTestVolume).Return(luksTestVolumeExternal("A"), nil)
+	mockCore.EXPECT().ListVolumePublicationsForVolume(gomock.Any(), luksTestVolume, nil).
+		Return([]*utils.VolumePublicationExternal{}, nil)
+	mockCore.EXPECT().ListSnapshotsForVolume(gomock.Any(), luksTestVolume).Return(nil, nil)
+
+	plugin.reconcileLUKSPassphrases(ctx)
+
+	rotations := plugin.ListLUKSPassphraseRotations(ctx, true)
+	assert.Len(t, rotations, 1)
+	assert.Equal(t, storage.LUKSPassphraseRotationPending, rotations[0].State)
+	assert.Equal(t, 0, rotations[0].Attempts)
+	assert.Equal(t, []string{"A"}, rotations[0].VolumePassphraseNames)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestReconcileLUKSPassphrases_NodeFailure(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourcePeriodic)
+	mockCore, mockNode, recorder, plugin := newLUKSRotationTestPlugin(t)
+
+	publication := &utils.VolumePublicationExternal{NodeName: luksTestNode, VolumeName: luksTestVolume}
+	node := &utils.Node{Name: luksTestNode, RESTPort: luksTestRESTPort}
+
+	mockCore.EXPECT().GetVolume(gomock.Any(), luksTestVolume).Return(luksTestVolumeExternal("A"), nil).Times(2)
+	mockCore.EXPECT().ListVolumePublicationsForVolume(gomock.Any(), luksTestVolume, nil).
+		Return([]*utils.VolumePublicationExternal{publication}, nil).Times(2)
+	mockCore.EXPECT().GetNode(gomock.Any(), luksTestNode).Return(node, nil).Times(2)
+	mockNode.EXPECT().RotateLUKSPassphrase(gomock.Any(), luksTestVolume, gomock.Any(), gomock.Any()).
+		Return(fmt.Errorf("no working passphrase provided")).Times(2)
+	mockCore.EXPECT().ListSnapshotsForVolume(gomock.Any(), luksTestVolume).Return(nil, nil).Times(2)
+
+	plugin.reconcileLUKSPassphrases(ctx)
+	plugin.reconcileLUKSPassphrases(ctx)
+
+	rotations := plugin.ListLUKSPassphraseRotations(ctx, false)
+	assert.Len(t, rotations, 1)
+	assert.Equal(t, storage.LUKSPassphraseRotationFailed, rotations[0].State)
+	assert.Equal(t, 2, rotations[0].Attempts)
+	assert.Contains(t, rotations[0].Message, "no working passphrase provided")
+
+	assert.Len(t, recorder.Events, 2)
+	assert.Contains(t, <-recorder.Events, "LUKSPassphraseRotationFailed")
+}
+
+func TestReconcileLUKSPassphrases_NodeWithoutREST(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourcePeriodic)
+	mockCore, _, _, plugin := newLUKSRotationTestPlugin(t)
+
+	publication := &utils.VolumePublicationExternal{NodeName: luksTestNode, VolumeName: luksTestVolume}
+
+	mockCore.EXPECT().GetVolume(gomock.Any(), luksTestVolume).Return(luksTestVolumeExternal("A"), nil)
+	mockCore.EXPECT().ListVolumePublicationsForVolume(gomock.Any(), luksTestVolume, nil).
+		Return([]*utils.VolumePublicationExternal{publication}, nil)
+	mockCore.EXPECT().GetNode(gomock.Any(), luksTestNode).Return(&utils.Node{Name: luksTestNode}, nil)
+	mockCore.EXPECT().ListSnapshotsForVolume(gomock.Any(), luksTestVolume).Return(nil, nil)
+
+	plugin.reconcileLUKSPassphrases(ctx)
+
+	rotations := plugin.ListLUKSPassphraseRotations(ctx, false)
+	assert.Len(t, rotations, 1)
+	assert.Equal(t, storage.LUKSPassphraseRotationFailed, rotations[0].State)
+	assert.Contains(t, rotations[0].Message, "REST interface")
+}
+
+func TestReconcileLUKSPassphrases_PrunesNewSnapshots(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourcePeriodic)
+	mockCore, _, _, plugin := newLUKSRotationTestPlugin(t)
+
+	completionTime := time.Now().Add(-time.Hour)
+	plugin.luksRotations[luksTestVolume] = &storage.LUKSPassphraseRotation{
+		VolumeName:     luksTestVolume,
+		PassphraseName: "B",
+		State:          storage.LUKSPassphraseRotationComplete,
+		StartTime:      completionTime.Add(-time.Minute),
+		CompletionTime: completionTime,
+	}
+
+	snapshots := []*storage.SnapshotExternal{
+		luksTestSnapshot("before", completionTime.Add(-time.Hour), "B", "A"),
+		luksTestSnapshot("after", time.Now(), "B", "A"),
+		luksTestSnapshot("current", time.Now(), "B"),
+	}
+
+	mockCore.EXPECT().GetVolume(gomock.Any(), luksTestVolume).Return(luksTestVolumeExternal("B"), nil)
+	mockCore.EXPECT().ListSnapshotsForVolume(gomock.Any(), luksTestVolume).Return(snapshots, nil)
+	mockCore.EXPECT().UpdateSnapshot(gomock.Any(), luksTestVolume, "after", &[]string{"B"}).Return(nil)
+
+	plugin.reconcileLUKSPassphrases(ctx)
+
+	rotations := plugin.ListLUKSPassphraseRotations(ctx, true)
+	assert.Len(t, rotations, 1)
+	assert.Equal(t, []string{"before"}, rotations[0].Snapshots)
+	assert.Equal(t, completionTime, rotations[0].CompletionTime)
+}
+
+func TestReconcileLUKSPassphrases_IgnoresUnencryptedAndForgetsDeleted(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourcePeriodic)
+	mockCore, _, _, plugin := newLUKSRotationTestPlugin(t)
+
+	plugin.luksRotations["deleted"] = &storage.LUKSPassphraseRotation{VolumeName: "deleted"}
+
+	volume := luksTestVolumeExternal()
+	volume.Config.LUKSEncryption = "false"
+	mockCore.EXPECT().GetVolume(gomock.Any(), luksTestVolume).Return(volume, nil)
+
+	plugin.reconcileLUKSPassphrases(ctx)
+
+	assert.Empty(t, plugin.ListLUKSPassphraseRotations(ctx, false))
+}
+
+func TestReconcileLUKSPassphrases_SkipsKMSVolumes(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourcePeriodic)
+	mockCore, _, recorder, plugin := newLUKSRotationTestPlugin(t)
+
+	plugin.luksRotations[luksTestVolume] = &storage.LUKSPassphraseRotation{VolumeName: luksTestVolume}
+	plugin.kubeClient = fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: luksTestSecret, Namespace: luksTestNamespace},
+		Data: map[string][]byte{
+			utils.LUKSKMSProviderKey: []byte(utils.LUKSKMSProviderLocal),
+			utils.LUKSKMSKeyNameKey:  []byte("trident"),
+			utils.LUKSKMSLocalKeyKey: []byte("key"),
+		},
+	})
+
+	// The volume is not published, rotated or listed, since its node derives its passphrase from the KMS
+	mockCore.EXPECT().GetVolume(gomock.Any(), luksTestVolume).Return(luksTestVolumeExternal("A"), nil)
+
+	plugin.reconcileLUKSPassphrases(ctx)
+
+	assert.Empty(t, plugin.ListLUKSPassphraseRotations(ctx, false))
+	assert.Empty(t, recorder.Events)
+}
+
+func TestUsesOnlyLUKSPassphrase(t *testing.T) {
+	assert.True(t, usesOnlyLUKSPassphrase([]string{"B"}, "B"))
+	assert.False(t, usesOnlyLUKSPassphrase([]string{"B", "A"}, "B"))
+	assert.False(t, usesOnlyLUKSPassphrase([]string{"A"}, "B"))
+	assert.False(t, usesOnlyLUKSPassphrase(nil, "B"))
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+// CheckNode asks the named node to check its volume tracking files against its mounts, iSCSI sessions, multipath
+// maps and loop devices, and to repair the findings if requested.  The node is told the iSCSI targets of Trident's
+// volumes, so that it leaves sessions to other targets alone.
+func (h *helper) CheckNode(
+	ctx context.Context, nodeName string, request *nodeAPI.NodeCheckRequest,
+) (*utils.NodeCheckReport, error) {
+	fields := log.Fields{"node": nodeName, "repair": request.Repair, "dryRun": request.DryRun}
+	Logc(ctx).WithFields(fields).Debug(">>>> CheckNode")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< CheckNode")
+
+	nodeClient, err := h.getNodeClient(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := h.orchestrator.ListVolumes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list volumes; %v", err)
+	}
+	targets := make(map[string]struct{})
+	for _, volume := range volumes {
+		if volume.Config != nil && volume.Config.AccessInfo.IscsiTargetIQN != "" {
+			targets[volume.Config.AccessInfo.IscsiTargetIQN] = struct{}{}
+		}
+	}
+	nodeRequest := *request
+	nodeRequest.ISCSITargets = make([]string, 0, len(targets))
+	for target := range targets {
+		nodeRequest.ISCSITargets = append(nodeRequest.ISCSITargets, target)
+	}
+	sort.Strings(nodeRequest.ISCSITargets)
+
+	report, err := nodeClient.CheckNode(ctx, nodeName, &nodeRequest)
+	if err != nil {
+		return nil, err
+	}
+	if report.Node != nodeName {
+		return nil, fmt.Errorf("node %s answered the check request for node %s", report.Node, nodeName)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+func TestCheckNode(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceREST)
+	mockCore, mockNode, _, plugin := newLUKSRotationTestPlugin(t)
+
+	node := &utils.Node{Name: luksTestNode, IPs: []string{"192.168.0.1"}, RESTPort: luksTestRESTPort}
+	request := &nodeAPI.NodeCheckRequest{Repair: true, DryRun: true}
+	report := &utils.NodeCheckReport{Node: luksTestNode, Repair: true, DryRun: true}
+
+	// The node is told the unique iSCSI targets of Trident's volumes
+	volumes := make([]*storage.VolumeExternal, 0)
+	for _, iqn := range []string{"iqn.2", "", "iqn.1", "iqn.2"} {
+		volume := &storage.VolumeExternal{Config: &storage.VolumeConfig{}}
+		volume.Config.AccessInfo.IscsiTargetIQN = iqn
+		volumes = append(volumes, volume)
+	}
+	nodeRequest := &nodeAPI.NodeCheckRequest{Repair: true, DryRun: true, ISCSITargets: []string{"iqn.1", "iqn.2"}}
+
+	mockCore.EXPECT().GetNode(gomock.Any(), luksTestNode).Return(node, nil)
+	mockCore.EXPECT().ListVolumes(gomock.Any()).Return(volumes, nil)
+	mockNode.EXPECT().CheckNode(gomock.Any(), luksTestNode, nodeRequest).Return(report, nil)
+
+	result, err := plugin.CheckNode(ctx, luksTestNode, request)
+	assert.NoError(t, err)
+	assert.Equal(t, report, result)
+}
+
+func TestCheckNode_Failed(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceREST)
+	mockCore, mockNode, _, plugin := newLUKSRotationTestPlugin(t)
+
+	node := &utils.Node{Name: luksTestNode, IPs: []string{"192.168.0.1"}, RESTPort: luksTestRESTPort}
+	request := &nodeAPI.NodeCheckRequest{}
+
+	// Nodes without a REST interface cannot be checked
+	mockCore.EXPECT().GetNode(gomock.Any(), "node2").Return(&utils.Node{Name: "node2"}, nil)
+	_, err := plugin.CheckNode(ctx, "node2", request)
+	assert.True(t, utils.IsUnsupportedError(err))
+
+	// Volumes must be listed to tell the node which iSCSI sessions are Trident's
+	mockCore.EXPECT().GetNode(gomock.Any(), luksTestNode).Return(node, nil)
+	mockCore.EXPECT().ListVolumes(gomock.Any()).Return(nil, fmt.Errorf("failed"))
+	_, err = plugin.CheckNode(ctx, luksTestNode, request)
+	assert.Error(t, err)
+
+	// Node errors are returned
+	mockCore.EXPECT().GetNode(gomock.Any(), luksTestNode).Return(node, nil)
+	mockCore.EXPECT().ListVolumes(gomock.Any()).Return(nil, nil).Times(2)
+	mockNode.EXPECT().CheckNode(gomock.Any(), luksTestNode, gomock.Any()).Return(nil, fmt.Errorf("failed"))
+	_, err = plugin.CheckNode(ctx, luksTestNode, request)
+	assert.Error(t, err)
+
+	// Reports from the wrong node are rejected
+	mockCore.EXPECT().GetNode(gomock.Any(), luksTestNode).Return(node, nil)
+	mockNode.EXPECT().CheckNode(gomock.Any(), luksTestNode, gomock.Any()).Return(
+		&utils.NodeCheckReport{Node: "node2"}, nil)
+	_, err = plugin.CheckNode(ctx, luksTestNode, request)
+	assert.Error(t, err)
+}
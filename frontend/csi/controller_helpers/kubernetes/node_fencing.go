@@ -0,0 +1,194 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/netapp/trident/frontend/csi"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	outOfServiceTaintKey = "node.kubernetes.io/out-of-service"
+	nodeLeaseNamespace   = "kube-node-lease"
+
+	networkFenceGroupVersion = "csiaddons.openshift.io/v1alpha1"
+	networkFenceFenced       = "Fenced"
+	networkFenceUnfenced     = "Unfenced"
+)
+
+// networkFenceResource identifies the cluster-scoped NetworkFence CRs with which an external fencing
+// agent declares the nodes in a set of CIDRs failed (Fenced) or recovered (Unfenced).
+var networkFenceResource = schema.GroupVersionResource{
+	Group:    "csiaddons.openshift.io",
+	Version:  "v1alpha1",
+	Resource: "networkfences",
+}
+
+// hasOutOfServiceTaint returns true if an administrator has declared that a node is out of service.
+func hasOutOfServiceTaint(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == outOfServiceTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+// isNodeLeaseExpired returns true if a node's kubelet has stopped renewing its lease.  A node without a
+// lease is not considered failed, since its kubelet may simply predate node leases.
+func (h *helper) isNodeLeaseExpired(ctx context.Context, nodeName string) (bool, error) {
+	lease, err := h.kubeClient.CoordinationV1().Leases(nodeLeaseNamespace).Get(ctx, nodeName, getOpts)
+	if err != nil {
+		return false, err
+	}
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false, nil
+	}
+
+	leaseDuration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	return time.Now().After(lease.Spec.RenewTime.Add(leaseDuration)), nil
+}
+
+// fenceFailedNode fences a node that has been tainted out of service and whose lease has expired, so that
+// its volumes may be attached elsewhere.  Fenced nodes are only unfenced by an administrator.
+func (h *helper) fenceFailedNode(ctx context.Context, node *v1.Node) {
+	if !hasOutOfServiceTaint(node) {
+		return
+	}
+
+	logFields := log.Fields{"node": node.Name}
+
+	tridentNode, err := h.orchestrator.GetNode(ctx, node.Name)
+	if err != nil {
+		if !utils.IsNotFoundError(err) {
+			Logc(ctx).WithFields(logFields).WithError(err).Error("Could not get node from Trident.")
+		}
+		return
+	}
+	if tridentNode.Fenced {
+		return
+	}
+
+	expired, err := h.isNodeLeaseExpired(ctx, node.Name)
+	if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Warning("Could not read node lease.")
+		return
+	}
+	if !expired {
+		Logc(ctx).WithFields(logFields).Debug("Out-of-service node still holds its lease; not fencing it.")
+		return
+	}
+
+	nodeEventCallback := func(eventType, reason, message string) {
+		h.eventRecorder.Event(node, mapEventType(eventType), reason, message)
+	}
+	if err = h.orchestrator.FenceNode(ctx, node.Name, nodeEventCallback); err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Could not fence failed node.")
+	}
+}
+
+// addNetworkFence is the add handler for the NetworkFence watcher.
+func (h *helper) addNetworkFence(obj interface{}) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceK8S)
+
+	switch networkFence := obj.(type) {
+	case *unstructured.Unstructured:
+		h.processNetworkFence(ctx, networkFence)
+	default:
+		Logc(ctx).Errorf("K8S helper expected NetworkFence; got %v", obj)
+	}
+}
+
+// updateNetworkFence is the update handler for the NetworkFence watcher.
+func (h *helper) updateNetworkFence(_, newObj interface{}) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceK8S)
+
+	switch networkFence := newObj.(type) {
+	case *unstructured.Unstructured:
+		h.processNetworkFence(ctx, networkFence)
+	default:
+		Logc(ctx).Errorf("K8S helper expected NetworkFence; got %v", newObj)
+	}
+}
+
+// processNetworkFence fences or unfences each Trident node with an IP address inside the CIDRs of a
+// NetworkFence addressed to Trident.  Deleting a NetworkFence leaves its nodes as they are.
+func (h *helper) processNetworkFence(ctx context.Context, networkFence *unstructured.Unstructured) {
+	logFields := log.Fields{"networkFence": networkFence.GetName()}
+
+	driver, _, _ := unstructured.NestedString(networkFence.Object, "spec", "driver")
+	if driver != csi.Provisioner {
+		return
+	}
+
+	fenceState, _, _ := unstructured.NestedString(networkFence.Object, "spec", "fenceState")
+	if fenceState != networkFenceFenced && fenceState != networkFenceUnfenced {
+		Logc(ctx).WithFields(logFields).WithField("fenceState", fenceState).Warning(
+			"Unknown NetworkFence state; ignoring it.")
+		return
+	}
+
+	cidrs, _, err := unstructured.NestedStringSlice(networkFence.Object, "spec", "cidrs")
+	if err != nil || len(cidrs) == 0 {
+		Logc(ctx).WithFields(logFields).Warning("NetworkFence has no CIDRs; ignoring it.")
+		return
+	}
+
+	nodes, err := h.orchestrator.ListNodes(ctx)
+	if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Could not list nodes from Trident.")
+		return
+	}
+
+	for _, node := range nodes {
+		nodeIPs, err := utils.FilterIPs(ctx, node.IPs, cidrs)
+		if err != nil {
+			Logc(ctx).WithFields(logFields).WithError(err).Error("Could not match NetworkFence CIDRs.")
+			return
+		}
+		if len(nodeIPs) == 0 {
+			continue
+		}
+
+		nodeName := node.Name
+		nodeEventCallback := func(eventType, reason, message string) {
+			h.eventRecorder.Event(networkFence, mapEventType(eventType), reason,
+				fmt.Sprintf("Node %s: %s", nodeName, message))
+		}
+
+		if fenceState == networkFenceFenced && !node.Fenced {
+			if err = h.orchestrator.FenceNode(ctx, nodeName, nodeEventCallback); err != nil {
+				Logc(ctx).WithFields(logFields).WithField("node", nodeName).WithError(err).Error(
+					"Could not fence node.")
+			}
+		} else if fenceState == networkFenceUnfenced && node.Fenced {
+			if err = h.orchestrator.UnfenceNode(ctx, nodeName, nodeEventCallback); err != nil {
+				Logc(ctx).WithFields(logFields).WithField("node", nodeName).WithError(err).Error(
+					"Could not unfence node.")
+			}
+		}
+	}
+}
+
+// PreemptReservationKey asks a node sharing a multi-attach block volume to preempt the persistent reservation key
+// of a node that was force detached from it.  The orchestrator calls this in the background with a copy of the node
+// it found when the volume was unpublished, so the node is supplied rather than looked up.
+func (h *helper) PreemptReservationKey(
+	ctx context.Context, volumeName string, node *utils.Node, reservationKey string,
+) error {
+	nodeClient, err := h.newClientForNode(node)
+	if err != nil {
+		return err
+	}
+	return nodeClient.PreemptReservationKeys(ctx, volumeName, []string{reservationKey})
+}
@@ -0,0 +1,118 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/netapp/trident/frontend/csi"
+	. "github.com/netapp/trident/logger"
+	mockcore "github.com/netapp/trident/mocks/mock_core"
+	"github.com/netapp/trident/utils"
+)
+
+func TestFenceFailedNode(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceInternal)
+	mockCtrl := gomock.NewController(t)
+	mockCore := mockcore.NewMockOrchestrator(mockCtrl)
+
+	leaseDuration := int32(40)
+	renewTime := metav1.NewMicroTime(time.Now().Add(-time.Hour))
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Namespace: nodeLeaseNamespace},
+		Spec: coordinationv1.LeaseSpec{
+			LeaseDurationSeconds: &leaseDuration,
+			RenewTime:            &renewTime,
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(lease)
+	h := &helper{orchestrator: mockCore, kubeClient: kubeClient, eventRecorder: record.NewFakeRecorder(10)}
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+
+	// Nodes that aren't out of service are ignored
+	h.fenceFailedNode(ctx, node)
+
+	node.Spec.Taints = []v1.Taint{{Key: outOfServiceTaintKey, Effect: v1.TaintEffectNoExecute}}
+
+	// Nodes that are already fenced are ignored
+	mockCore.EXPECT().GetNode(gomock.Any(), "node1").Return(&utils.Node{Name: "node1", Fenced: true}, nil)
+	h.fenceFailedNode(ctx, node)
+
+	// Out-of-service nodes whose lease has expired are fenced
+	mockCore.EXPECT().GetNode(gomock.Any(), "node1").Return(&utils.Node{Name: "node1"}, nil)
+	mockCore.EXPECT().FenceNode(gomock.Any(), "node1", gomock.Any()).Return(nil)
+	h.fenceFailedNode(ctx, node)
+
+	// Out-of-service nodes that still renew their lease are not fenced
+	renewTime = metav1.NewMicroTime(time.Now())
+	lease.Spec.RenewTime = &renewTime
+	_, err := kubeClient.CoordinationV1().Leases(nodeLeaseNamespace).Update(ctx, lease, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	mockCore.EXPECT().GetNode(gomock.Any(), "node1").Return(&utils.Node{Name: "node1"}, nil)
+	h.fenceFailedNode(ctx, node)
+}
+
+func TestProcessNetworkFence(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceInternal)
+	mockCtrl := gomock.NewController(t)
+	mockCore := mockcore.NewMockOrchestrator(mockCtrl)
+	h := &helper{orchestrator: mockCore, eventRecorder: record.NewFakeRecorder(10)}
+
+	networkFence := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": networkFenceGroupVersion,
+		"kind":       "NetworkFence",
+		"metadata":   map[string]interface{}{"name": "fence1"},
+		"spec": map[string]interface{}{
+			"driver":     "other.csi.driver",
+			"fenceState": networkFenceFenced,
+			"cidrs":      []interface{}{"10.0.0.1/32"},
+		},
+	}}
+	nodes := []*utils.Node{
+		{Name: "node1", IPs: []string{"10.0.0.1"}},
+		{Name: "node2", IPs: []string{"10.0.0.2"}},
+		{Name: "node3", IPs: []string{"10.0.0.3"}, Fenced: true},
+	}
+
+	// NetworkFences for other drivers are ignored
+	h.processNetworkFence(ctx, networkFence)
+
+	// Only unfenced nodes inside the CIDRs are fenced
+	networkFence.Object["spec"].(map[string]interface{})["driver"] = csi.Provisioner
+	networkFence.Object["spec"].(map[string]interface{})["cidrs"] = []interface{}{"10.0.0.1/32", "10.0.0.3/32"}
+	mockCore.EXPECT().ListNodes(gomock.Any()).Return(nodes, nil)
+	mockCore.EXPECT().FenceNode(gomock.Any(), "node1", gomock.Any()).Return(nil)
+	h.processNetworkFence(ctx, networkFence)
+
+	// Only fenced nodes inside the CIDRs are unfenced
+	networkFence.Object["spec"].(map[string]interface{})["fenceState"] = networkFenceUnfenced
+	mockCore.EXPECT().ListNodes(gomock.Any()).Return(nodes, nil)
+	mockCore.EXPECT().UnfenceNode(gomock.Any(), "node3", gomock.Any()).Return(nil)
+	h.processNetworkFence(ctx, networkFence)
+}
+
+func TestPreemptReservationKey(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceInternal)
+	_, mockNode, _, plugin := newLUKSRotationTestPlugin(t)
+
+	node := &utils.Node{Name: luksTestNode, IPs: []string{"192.168.0.1"}, RESTPort: luksTestRESTPort}
+
+	// The node is called directly, without looking it up through the orchestrator
+	mockNode.EXPECT().PreemptReservationKeys(gomock.Any(), luksTestVolume, []string{"0x1a2b"}).Return(nil)
+	assert.NoError(t, plugin.PreemptReservationKey(ctx, luksTestVolume, node, "0x1a2b"))
+
+	// Nodes without a REST interface cannot preempt keys
+	err := plugin.PreemptReservationKey(ctx, luksTestVolume, &utils.Node{Name: "node2"}, "0x1a2b")
+	assert.True(t, utils.IsUnsupportedError(err))
+}
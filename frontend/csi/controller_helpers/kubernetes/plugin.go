@@ -6,7 +6,9 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -16,9 +18,11 @@ import (
 	k8sstoragev1beta "k8s.io/api/storage/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8sversion "k8s.io/apimachinery/pkg/version"
 	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -32,6 +36,7 @@ import (
 	"github.com/netapp/trident/frontend"
 	"github.com/netapp/trident/frontend/csi"
 	controllerhelpers "github.com/netapp/trident/frontend/csi/controller_helpers"
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
 	. "github.com/netapp/trident/logger"
 	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
 	clientset "github.com/netapp/trident/persistent_store/crd/client/clientset/versioned"
@@ -61,6 +66,10 @@ type K8SControllerHelperPlugin interface {
 	frontend.Plugin
 	ImportVolume(ctx context.Context, request *storage.ImportVolumeRequest) (*storage.VolumeExternal, error)
 	UpgradeVolume(ctx context.Context, request *storage.UpgradeVolumeRequest) (*storage.VolumeExternal, error)
+	ListLUKSPassphraseRotations(ctx context.Context, incompleteOnly bool) []*storage.LUKSPassphraseRotation
+	CheckNode(
+		ctx context.Context, nodeName string, request *nodeAPI.NodeCheckRequest,
+	) (*utils.NodeCheckReport, error)
 }
 
 type helper struct {
@@ -101,10 +110,21 @@ type helper struct {
 	vrefController         cache.SharedIndexInformer
 	vrefControllerStopChan chan struct{}
 	vrefSource             cache.ListerWatcher
+
+	networkFenceController         cache.SharedIndexInformer
+	networkFenceControllerStopChan chan struct{}
+	networkFenceSource             cache.ListerWatcher
+
+	luksRotations        map[string]*storage.LUKSPassphraseRotation
+	luksRotationsLock    sync.RWMutex
+	luksRotationStopChan chan struct{}
+	newNodeClient        func(url string) (nodeAPI.TridentNode, error)
 }
 
 // NewHelper instantiates this plugin when running outside a pod.
-func NewHelper(orchestrator core.Orchestrator, masterURL, kubeConfigPath string) (frontend.Plugin, error) {
+func NewHelper(
+	orchestrator core.Orchestrator, masterURL, kubeConfigPath, caCert, controllerCert, controllerKey string,
+) (frontend.Plugin, error) {
 	ctx := GenerateRequestContext(nil, "", ContextSourceInternal)
 
 	Logc(ctx).Info("Initializing K8S helper frontend.")
@@ -134,7 +154,13 @@ func NewHelper(orchestrator core.Orchestrator, masterURL, kubeConfigPath string)
 		nodeControllerStopChan: make(chan struct{}),
 		mrControllerStopChan:   make(chan struct{}),
 		vrefControllerStopChan: make(chan struct{}),
+		luksRotationStopChan:   make(chan struct{}),
+		luksRotations:          make(map[string]*storage.LUKSPassphraseRotation),
 		namespace:              clients.Namespace,
+		newNodeClient: func(url string) (nodeAPI.TridentNode, error) {
+			return nodeAPI.CreateTLSRestClient(url, caCert, controllerCert, controllerKey)
+		},
+		networkFenceControllerStopChan: make(chan struct{}),
 	}
 
 	Logc(ctx).WithFields(log.Fields{
@@ -315,6 +341,38 @@ func NewHelper(orchestrator core.Orchestrator, masterURL, kubeConfigPath string)
 	)
 	p.vrefIndexer = p.vrefController.GetIndexer()
 
+	// Set up a watch for NetworkFences, if the CRD is installed
+	if _, err = kubeClient.Discovery().ServerResourcesForGroupVersion(networkFenceGroupVersion); err != nil {
+		Logc(ctx).WithError(err).Debug("NetworkFence CRD not found; not watching NetworkFences.")
+	} else {
+		dynamicClient, err := dynamic.NewForConfig(clients.RestConfig)
+		if err != nil {
+			return nil, fmt.Errorf("could not create dynamic client; %v", err)
+		}
+		p.networkFenceSource = &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return dynamicClient.Resource(networkFenceResource).List(ctx, options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return dynamicClient.Resource(networkFenceResource).Watch(ctx, options)
+			},
+		}
+		p.networkFenceController = cache.NewSharedIndexInformer(
+			p.networkFenceSource,
+			&unstructured.Unstructured{},
+			CacheSyncPeriod,
+			cache.Indexers{},
+		)
+
+		// Add handler for fencing nodes named by NetworkFences
+		_, _ = p.networkFenceController.AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    p.addNetworkFence,
+				UpdateFunc: p.updateNetworkFence,
+			},
+		)
+	}
+
 	return p, nil
 }
 
@@ -389,7 +447,11 @@ func (h *helper) Activate() error {
 	go h.nodeController.Run(h.nodeControllerStopChan)
 	go h.mrController.Run(h.mrControllerStopChan)
 	go h.vrefController.Run(h.vrefControllerStopChan)
+	if h.networkFenceController != nil {
+		go h.networkFenceController.Run(h.networkFenceControllerStopChan)
+	}
 	go h.reconcileNodes(ctx)
+	go h.reconcileLUKSPassphrasesPeriodically(h.luksRotationStopChan)
 
 	// Configure telemetry
 	config.OrchestratorTelemetry.Platform = string(config.PlatformKubernetes)
@@ -411,6 +473,8 @@ func (h *helper) Deactivate() error {
 	close(h.nodeControllerStopChan)
 	close(h.mrControllerStopChan)
 	close(h.vrefControllerStopChan)
+	close(h.networkFenceControllerStopChan)
+	close(h.luksRotationStopChan)
 	return nil
 }
 
@@ -506,7 +570,7 @@ func (h *helper) deletePVC(obj interface{}) {
 	}
 }
 
-// processPVC logs the add/update/delete PVC events.
+// processPVC logs the add/update/delete PVC events, and tracks the mirrors of cross-zone replicas.
 func (h *helper) processPVC(ctx context.Context, pvc *v1.PersistentVolumeClaim, eventType string) {
 	// Validate the PVC
 	size, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]
@@ -528,8 +592,10 @@ func (h *helper) processPVC(ctx context.Context, pvc *v1.PersistentVolumeClaim,
 	switch eventType {
 	case eventAdd:
 		Logc(ctx).WithFields(logFields).Debug("PVC added to cache.")
+		h.ensureReplicaMirrorRelationship(ctx, pvc)
 	case eventUpdate:
 		Logc(ctx).WithFields(logFields).Debug("PVC updated in cache.")
+		h.ensureReplicaMirrorRelationship(ctx, pvc)
 	case eventDelete:
 		Logc(ctx).WithFields(logFields).Debug("PVC deleted from cache.")
 	}
@@ -858,6 +924,41 @@ func (h *helper) processAddedStorageClass(ctx context.Context, sc *k8sstoragev1.
 			}
 			scConfig.Pools = pools
 
+		case storageattribute.CrossZoneReplica:
+			crossZoneReplica, err := strconv.ParseBool(v)
+			if err != nil {
+				Logc(ctx).WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", newKey)
+				return
+			}
+			scConfig.CrossZoneReplica = crossZoneReplica
+
+		case storageattribute.ReplicationPolicy:
+			scConfig.ReplicationPolicy = v
+
+		case storageattribute.ReplicationSchedule:
+			scConfig.ReplicationSchedule = v
+
+		case storageattribute.PoolUtilizationThreshold:
+			threshold, err := strconv.Atoi(v)
+			if err == nil && threshold <= 0 {
+				err = fmt.Errorf("%s must be a positive percentage", newKey)
+			}
+			if err != nil {
+				Logc(ctx).WithFields(log.Fields{
+					"name":        sc.Name,
+					"provisioner": sc.Provisioner,
+					"parameters":  sc.Parameters,
+					"error":       err,
+				}).Errorf("K8S helper could not process the storage class parameter %s", newKey)
+				return
+			}
+			scConfig.PoolUtilizationThreshold = threshold
+
 		default:
 			// format:  attribute: "value"
 			req, err := storageattribute.CreateAttributeRequestFromAttributeValue(newKey, v)
@@ -1003,8 +1104,10 @@ func (h *helper) processNode(ctx context.Context, node *v1.Node, eventType strin
 	switch eventType {
 	case eventAdd:
 		Logc(ctx).WithFields(logFields).Debug("Node added to cache.")
+		h.fenceFailedNode(ctx, node)
 	case eventUpdate:
 		Logc(ctx).WithFields(logFields).Debug("Node updated in cache.")
+		h.fenceFailedNode(ctx, node)
 	case eventDelete:
 		err := h.orchestrator.DeleteNode(ctx, node.Name)
 		if err != nil {
@@ -0,0 +1,82 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"context"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/netapp/trident/logger"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+)
+
+// ensureReplicaMirrorRelationship creates a TridentMirrorRelationship for a bound PVC whose volume has a
+// cross-zone replica, so that the mirror created by the orchestrator is tracked like a hand-authored one.
+// The relationship is named for the replica volume and kept pointed at the replica's current mirror handle.
+func (h *helper) ensureReplicaMirrorRelationship(ctx context.Context, pvc *v1.PersistentVolumeClaim) {
+	if pvc.Status.Phase != v1.ClaimBound || pvc.Spec.VolumeName == "" {
+		return
+	}
+
+	volume, err := h.orchestrator.GetVolume(ctx, pvc.Spec.VolumeName)
+	if err != nil || volume.Config.ReplicaVolume == "" {
+		return
+	}
+	replica, err := h.orchestrator.GetVolume(ctx, volume.Config.ReplicaVolume)
+	if err != nil {
+		Logc(ctx).WithField("replica", volume.Config.ReplicaVolume).WithError(err).Warning(
+			"Could not find cross-zone replica.")
+		return
+	}
+
+	logFields := log.Fields{
+		"pvc":       pvc.Name,
+		"namespace": pvc.Namespace,
+		"tmr":       replica.Config.Name,
+	}
+
+	tmrClient := h.tridentClient.TridentV1().TridentMirrorRelationships(pvc.Namespace)
+	tmr, err := tmrClient.Get(ctx, replica.Config.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		tmr = &netappv1.TridentMirrorRelationship{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      replica.Config.Name,
+				Namespace: pvc.Namespace,
+			},
+			Spec: netappv1.TridentMirrorRelationshipSpec{
+				MirrorState: netappv1.MirrorStatePromoted,
+				VolumeMappings: []*netappv1.TridentMirrorRelationshipVolumeMapping{{
+					LocalPVCName:       pvc.Name,
+					RemoteVolumeHandle: replica.Config.MirrorHandle,
+				}},
+			},
+		}
+		if _, err = tmrClient.Create(ctx, tmr, metav1.CreateOptions{}); err != nil {
+			Logc(ctx).WithFields(logFields).WithError(err).Error(
+				"Could not create TridentMirrorRelationship for cross-zone replica.")
+			return
+		}
+		Logc(ctx).WithFields(logFields).Info("Created TridentMirrorRelationship for cross-zone replica.")
+		return
+	} else if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Could not get TridentMirrorRelationship.")
+		return
+	}
+
+	// After a promotion, the replica's mirror handle refers to what was the primary's storage
+	if len(tmr.Spec.VolumeMappings) != 1 ||
+		tmr.Spec.VolumeMappings[0].RemoteVolumeHandle == replica.Config.MirrorHandle {
+		return
+	}
+	tmrCopy := tmr.DeepCopy()
+	tmrCopy.Spec.VolumeMappings[0].RemoteVolumeHandle = replica.Config.MirrorHandle
+	if _, err = tmrClient.Update(ctx, tmrCopy, metav1.UpdateOptions{}); err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Could not update TridentMirrorRelationship.")
+		return
+	}
+	Logc(ctx).WithFields(logFields).Info("Updated TridentMirrorRelationship for cross-zone replica.")
+}
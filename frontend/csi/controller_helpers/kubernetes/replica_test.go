@@ -0,0 +1,77 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/netapp/trident/logger"
+	mockcore "github.com/netapp/trident/mocks/mock_core"
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	tridentfake "github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/fake"
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+func TestEnsureReplicaMirrorRelationship(t *testing.T) {
+	ctx := GenerateRequestContext(nil, "", ContextSourceInternal)
+	mockCtrl := gomock.NewController(t)
+	mockCore := mockcore.NewMockOrchestrator(mockCtrl)
+	tridentClient := tridentfake.NewSimpleClientset()
+	h := &helper{orchestrator: mockCore, tridentClient: tridentClient}
+
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "pvc1", Namespace: "default"},
+		Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pvc-1"},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimBound},
+	}
+	volume := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "pvc-1", ReplicaVolume: "pvc-1-replica"}}
+	replica := &storage.VolumeExternal{Config: &storage.VolumeConfig{
+		Name: "pvc-1-replica", MirrorHandle: "svm2:trident_pvc_1_replica", ReplicaSourceVolume: "pvc-1",
+	}}
+
+	// Pending PVCs are ignored
+	pendingPVC := pvc.DeepCopy()
+	pendingPVC.Status.Phase = v1.ClaimPending
+	h.ensureReplicaMirrorRelationship(ctx, pendingPVC)
+
+	// Volumes without a replica are ignored
+	mockCore.EXPECT().GetVolume(gomock.Any(), "pvc-1").Return(
+		&storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "pvc-1"}}, nil)
+	h.ensureReplicaMirrorRelationship(ctx, pvc)
+	tmrs, err := tridentClient.TridentV1().TridentMirrorRelationships("default").List(ctx, metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, tmrs.Items)
+
+	// A TMR is created for a volume with a replica
+	mockCore.EXPECT().GetVolume(gomock.Any(), "pvc-1").Return(volume, nil)
+	mockCore.EXPECT().GetVolume(gomock.Any(), "pvc-1-replica").Return(replica, nil)
+	h.ensureReplicaMirrorRelationship(ctx, pvc)
+	tmr, err := tridentClient.TridentV1().TridentMirrorRelationships("default").Get(ctx, "pvc-1-replica",
+		metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, netappv1.MirrorStatePromoted, tmr.Spec.MirrorState)
+	assert.Equal(t, "pvc1", tmr.Spec.VolumeMappings[0].LocalPVCName)
+	assert.Equal(t, "svm2:trident_pvc_1_replica", tmr.Spec.VolumeMappings[0].RemoteVolumeHandle)
+
+	// After promotion, the TMR follows the replica's new mirror handle
+	promotedReplica := &storage.VolumeExternal{Config: replica.Config.ConstructClone()}
+	promotedReplica.Config.MirrorHandle = "svm1:trident_pvc_1"
+	mockCore.EXPECT().GetVolume(gomock.Any(), "pvc-1").Return(volume, nil)
+	mockCore.EXPECT().GetVolume(gomock.Any(), "pvc-1-replica").Return(promotedReplica, nil)
+	h.ensureReplicaMirrorRelationship(ctx, pvc)
+	tmr, err = tridentClient.TridentV1().TridentMirrorRelationships("default").Get(ctx, "pvc-1-replica",
+		metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "svm1:trident_pvc_1", tmr.Spec.VolumeMappings[0].RemoteVolumeHandle)
+
+	// A missing replica is tolerated
+	mockCore.EXPECT().GetVolume(gomock.Any(), "pvc-1").Return(volume, nil)
+	mockCore.EXPECT().GetVolume(gomock.Any(), "pvc-1-replica").Return(nil, utils.NotFoundError("not found"))
+	h.ensureReplicaMirrorRelationship(ctx, pvc)
+}
@@ -250,15 +250,15 @@ func (p *Plugin) CreateVolume(
 
 	// Invoke the orchestrator to create or clone the new volume
 	var newVolume *storage.VolumeExternal
-	if volConfig.CloneSourceVolume != "" {
-		newVolume, err = p.orchestrator.CloneVolume(ctx, volConfig)
-	} else if volConfig.ImportOriginalName != "" {
+	if volConfig.ImportOriginalName != "" {
 		newVolume, err = p.orchestrator.ImportVolume(ctx, volConfig)
 	} else {
-		newVolume, err = p.orchestrator.AddVolume(ctx, volConfig)
+		newVolume, err = p.createVolumeWithJob(ctx, volConfig)
 	}
 
-	if err != nil {
+	if utils.IsVolumeCreatingError(err) {
+		return nil, p.getCSIErrorForOrchestratorError(err)
+	} else if err != nil {
 		p.controllerHelper.RecordVolumeEvent(ctx, req.Name, controllerhelpers.EventTypeNormal, "ProvisioningFailed", err.Error())
 		return nil, p.getCSIErrorForOrchestratorError(err)
 	} else {
@@ -273,6 +273,37 @@ func (p *Plugin) CreateVolume(
 	return &csi.CreateVolumeResponse{Volume: csiVolume}, nil
 }
 
+// createVolumeWithJob creates or clones a volume using an orchestrator job, and waits a while for the job to
+// finish.  If it does not, a VolumeCreatingError is returned so that the CO retries the request, and the retry
+// follows the same job rather than starting the operation again.
+func (p *Plugin) createVolumeWithJob(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) (*storage.VolumeExternal, error) {
+	job, err := p.orchestrator.SubmitVolumeJob(ctx, volConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err = p.orchestrator.WaitForJob(ctx, job.ID, p.volumeJobWaitTime)
+	if err != nil {
+		return nil, err
+	}
+
+	switch job.State {
+	case storage.JobSucceeded:
+		return p.orchestrator.GetVolume(ctx, volConfig.Name)
+	default:
+		Logc(ctx).WithFields(log.Fields{
+			"volume":   volConfig.Name,
+			"job":      job.ID,
+			"state":    job.State,
+			"progress": job.Progress,
+		}).Debug("Volume job has not finished.")
+		return nil, utils.VolumeCreatingError(fmt.Sprintf("volume %s is being created by job %s (%d%% complete)",
+			volConfig.Name, job.ID, job.Progress))
+	}
+}
+
 func (p *Plugin) DeleteVolume(
 	ctx context.Context, req *csi.DeleteVolumeRequest,
 ) (*csi.DeleteVolumeResponse, error) {
@@ -362,27 +393,47 @@ func (p *Plugin) ControllerPublishVolume(
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	applyPublishMountFlags(volumePublishInfo, volume.Config.Protocol, req.VolumeCapability.GetMount())
+
+	publishInfo, err := p.getPublishContext(ctx, volume.Config.Protocol, volumePublishInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &csi.ControllerPublishVolumeResponse{PublishContext: publishInfo}, nil
+}
+
+// applyPublishMountFlags records the mount flags of a CSI volume capability in the volume publish info.
+func applyPublishMountFlags(
+	volumePublishInfo *utils.VolumePublishInfo, protocol tridentconfig.Protocol,
+	mount *csi.VolumeCapability_MountVolume,
+) {
 	// If any mount options are passed in via CSI (e.g. from a StorageClass), then any mount options
 	// that were specified in the storage driver's backend configuration and passed here in the
 	// VolumePublishInfo struct are completely discarded and replaced by the CSI-supplied values.
-	mount := req.VolumeCapability.GetMount()
 	if mount != nil && len(mount.MountFlags) > 0 {
-		if volume.Config.Protocol == tridentconfig.BlockOnFile {
+		if protocol == tridentconfig.BlockOnFile {
 			mount.MountFlags = utils.RemoveStringFromSlice(mount.MountFlags, "ro")
 			volumePublishInfo.SubvolumeMountOptions = strings.Join(mount.MountFlags, ",")
 		} else {
 			volumePublishInfo.MountOptions = strings.Join(mount.MountFlags, ",")
 		}
 	}
+}
 
+// getPublishContext builds the CSI publish context, which is passed to the node when it stages a volume, from the
+// volume publish info.
+func (p *Plugin) getPublishContext(
+	ctx context.Context, protocol tridentconfig.Protocol, volumePublishInfo *utils.VolumePublishInfo,
+) (map[string]string, error) {
 	// Build CSI controller publish info from volume publish info
 	publishInfo := map[string]string{
-		"protocol": string(volume.Config.Protocol),
+		"protocol": string(protocol),
 	}
 
 	publishInfo["mountOptions"] = volumePublishInfo.MountOptions
 	publishInfo["filesystemType"] = volumePublishInfo.FilesystemType
-	switch volume.Config.Protocol {
+	switch protocol {
 	case tridentconfig.File:
 		if volumePublishInfo.FilesystemType == "smb" {
 			publishInfo["smbServer"] = volumePublishInfo.SMBServer
@@ -413,6 +464,11 @@ func (p *Plugin) ControllerPublishVolume(
 		publishInfo["useCHAP"] = strconv.FormatBool(volumePublishInfo.UseCHAP)
 		publishInfo["LUKSEncryption"] = volumePublishInfo.LUKSEncryption
 		publishInfo["sharedTarget"] = strconv.FormatBool(volumePublishInfo.SharedTarget)
+		if volumePublishInfo.SCSIPersistentReservation {
+			publishInfo["scsiPersistentReservation"] = "true"
+			publishInfo["reservationKey"] = volumePublishInfo.ReservationKey
+			publishInfo["preemptReservationKeys"] = strings.Join(volumePublishInfo.PreemptReservationKeys, ",")
+		}
 	case tridentconfig.BlockOnFile:
 		publishInfo["subvolumeMountOptions"] = volumePublishInfo.SubvolumeMountOptions
 		publishInfo["nfsServerIp"] = volumePublishInfo.NfsServerIP
@@ -420,9 +476,10 @@ func (p *Plugin) ControllerPublishVolume(
 		publishInfo["nfsUniqueID"] = volumePublishInfo.NfsUniqueID
 		publishInfo["subvolumeName"] = volumePublishInfo.SubvolumeName
 		publishInfo["backendUUID"] = volumePublishInfo.BackendUUID
+		publishInfo["LUKSEncryption"] = volumePublishInfo.LUKSEncryption
 	}
 
-	return &csi.ControllerPublishVolumeResponse{PublishContext: publishInfo}, nil
+	return publishInfo, nil
 }
 
 func (p *Plugin) verifyVolumePublicationIsNew(ctx context.Context, vp *utils.VolumePublication) error {
@@ -500,7 +557,8 @@ func (p *Plugin) ValidateVolumeCapabilities(
 	resp := &csi.ValidateVolumeCapabilitiesResponse{}
 
 	for _, v := range req.GetVolumeCapabilities() {
-		if volume.Config.AccessMode != p.getAccessForCSIAccessMode(v.GetAccessMode().Mode) {
+		if !p.supportsAccessMode(v.GetAccessMode().Mode) ||
+			volume.Config.AccessMode != p.getAccessForCSIAccessMode(v.GetAccessMode().Mode) {
 			resp.Message = "Could not satisfy one or more access modes."
 			return resp, nil
 		}
@@ -1013,6 +1071,10 @@ func (p *Plugin) getAccessForCSIAccessMode(accessMode csi.VolumeCapability_Acces
 		return tridentconfig.ReadWriteOnce
 	case csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY:
 		return tridentconfig.ReadWriteOnce
+	case csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER:
+		return tridentconfig.ReadWriteOncePod
+	case csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER:
+		return tridentconfig.ReadWriteOnce
 	case csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY:
 		return tridentconfig.ReadOnlyMany
 	case csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER:
@@ -1024,6 +1086,16 @@ func (p *Plugin) getAccessForCSIAccessMode(accessMode csi.VolumeCapability_Acces
 	}
 }
 
+// supportsAccessMode returns true if the plugin advertises a CSI access mode.
+func (p *Plugin) supportsAccessMode(accessMode csi.VolumeCapability_AccessMode_Mode) bool {
+	for _, vCap := range p.vCap {
+		if vCap.GetMode() == accessMode {
+			return true
+		}
+	}
+	return false
+}
+
 func (p *Plugin) getProtocolForCSIAccessMode(
 	accessMode csi.VolumeCapability_AccessMode_Mode, volumeMode tridentconfig.VolumeMode,
 ) tridentconfig.Protocol {
@@ -1034,6 +1106,8 @@ func (p *Plugin) getProtocolForCSIAccessMode(
 	// AccessMode                       AccessType              Result: Protocol
 	// SINGLE_NODE_WRITER               Any                     Any
 	// SINGLE_NODE_READER_ONLY          Any                     Any
+	// SINGLE_NODE_SINGLE_WRITER        Any                     Any
+	// SINGLE_NODE_MULTI_WRITER         Any                     Any
 	// MULTI_NODE_READER_ONLY           Any                     Any
 	// MULTI_NODE_SINGLE_WRITER         Block                   block
 	// MULTI_NODE_SINGLE_WRITER         Any/Filesystem          file
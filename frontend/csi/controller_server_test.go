@@ -160,6 +160,39 @@ func TestControllerPublishVolume_BlockProtocol(t *testing.T) {
 	assert.Equal(t, expectedPublishContext, publishContext)
 }
 
+func TestControllerPublishVolume_SCSIPersistentReservation(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	// Create a mocked orchestrator
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	// Create a mocked helper
+	mockHelper := mockhelpers.NewMockControllerHelper(mockCtrl)
+	// Create an instance of ControllerServer for this test
+	controllerServer := generateController(mockOrchestrator, mockHelper)
+
+	// Create fake objects for this test
+	req := generateFakePublishVolumeRequest()
+	fakeNode := generateFakeNode(req.NodeId)
+	fakeVolumeExternal := generateFakeVolumeExternal(req.VolumeId)
+	fakeVolumeExternal.Config.Protocol = tridentconfig.Block
+
+	mockOrchestrator.EXPECT().GetVolume(ctx, req.VolumeId).Return(fakeVolumeExternal, nil)
+	mockOrchestrator.EXPECT().GetNode(ctx, req.NodeId).Return(fakeNode, nil)
+	mockOrchestrator.EXPECT().PublishVolume(ctx, req.VolumeId, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, publishInfo *utils.VolumePublishInfo) error {
+			publishInfo.SCSIPersistentReservation = true
+			publishInfo.ReservationKey = "0x1a2b"
+			publishInfo.PreemptReservationKeys = []string{"0x3c4d", "0x5e6f"}
+			return nil
+		})
+
+	publishResponse, err := controllerServer.ControllerPublishVolume(ctx, req)
+	assert.Nilf(t, err, "unexpected error publishing volume; %v", err)
+	publishContext := publishResponse.PublishContext
+	assert.Equal(t, "true", publishContext["scsiPersistentReservation"])
+	assert.Equal(t, "0x1a2b", publishContext["reservationKey"])
+	assert.Equal(t, "0x3c4d,0x5e6f", publishContext["preemptReservationKeys"])
+}
+
 func TestControllerUnpublishVolume(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	// Create a mocked orchestrator
@@ -196,3 +229,77 @@ func TestControllerUnpublishVolume_NotFoundErrors(t *testing.T) {
 	_, err := controllerServer.ControllerUnpublishVolume(ctx, req)
 	assert.Nil(t, err, "unexpected error unpublishing volume")
 }
+
+func TestCreateVolumeWithJob(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	mockHelper := mockhelpers.NewMockControllerHelper(mockCtrl)
+	controllerServer := generateController(mockOrchestrator, mockHelper)
+
+	volConfig := &storage.VolumeConfig{Name: "vol1"}
+	running := &storage.JobExternal{ID: "job1", Volume: "vol1", State: storage.JobRunning, Progress: 50}
+	succeeded := &storage.JobExternal{ID: "job1", Volume: "vol1", State: storage.JobSucceeded, Progress: 100}
+	volume := &storage.VolumeExternal{Config: volConfig}
+
+	// A job that does not finish in time asks the CO to retry
+	mockOrchestrator.EXPECT().SubmitVolumeJob(gomock.Any(), volConfig).Return(running, nil)
+	mockOrchestrator.EXPECT().WaitForJob(gomock.Any(), "job1", controllerServer.volumeJobWaitTime).Return(running, nil)
+	_, err := controllerServer.createVolumeWithJob(ctx, volConfig)
+	assert.True(t, utils.IsVolumeCreatingError(err))
+
+	// The retry attaches to the same job and returns its volume
+	mockOrchestrator.EXPECT().SubmitVolumeJob(gomock.Any(), volConfig).Return(running, nil)
+	mockOrchestrator.EXPECT().WaitForJob(gomock.Any(), "job1", controllerServer.volumeJobWaitTime).Return(succeeded, nil)
+	mockOrchestrator.EXPECT().GetVolume(gomock.Any(), "vol1").Return(volume, nil)
+	result, err := controllerServer.createVolumeWithJob(ctx, volConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, volume, result)
+
+	// A failed job returns its error
+	failed := &storage.JobExternal{ID: "job2", Volume: "vol1", State: storage.JobFailed}
+	mockOrchestrator.EXPECT().SubmitVolumeJob(gomock.Any(), volConfig).Return(failed, nil)
+	mockOrchestrator.EXPECT().WaitForJob(gomock.Any(), "job2", controllerServer.volumeJobWaitTime).Return(failed,
+		utils.NotFoundError("source volume not found"))
+	_, err = controllerServer.createVolumeWithJob(ctx, volConfig)
+	assert.True(t, utils.IsNotFoundError(err))
+}
+
+func TestValidateVolumeCapabilities_SingleNodeAccessModes(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	// Create a mocked orchestrator
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	// Create a mocked helper
+	mockHelper := mockhelpers.NewMockControllerHelper(mockCtrl)
+	// Create an instance of ControllerServer for this test
+	controllerServer := generateController(mockOrchestrator, mockHelper)
+	controllerServer.addVolumeCapabilityAccessModes([]csi.VolumeCapability_AccessMode_Mode{
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+	})
+
+	fakeVolumeExternal := generateFakeVolumeExternal("vol1")
+	fakeVolumeExternal.Config.Protocol = tridentconfig.File
+	mockOrchestrator.EXPECT().GetVolume(ctx, "vol1").Return(fakeVolumeExternal, nil).AnyTimes()
+
+	validate := func(volumeAccessMode tridentconfig.AccessMode, mode csi.VolumeCapability_AccessMode_Mode) bool {
+		fakeVolumeExternal.Config.AccessMode = volumeAccessMode
+		resp, err := controllerServer.ValidateVolumeCapabilities(ctx, &csi.ValidateVolumeCapabilitiesRequest{
+			VolumeId: "vol1",
+			VolumeCapabilities: []*csi.VolumeCapability{{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: mode},
+			}},
+		})
+		assert.NoError(t, err)
+		return resp.Confirmed != nil
+	}
+
+	assert.True(t, validate(tridentconfig.ReadWriteOncePod, csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER))
+	assert.False(t, validate(tridentconfig.ReadWriteOncePod, csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER))
+	assert.True(t, validate(tridentconfig.ReadWriteOnce, csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER))
+	assert.False(t, validate(tridentconfig.ReadWriteOnce, csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER))
+
+	// Modes the plugin doesn't advertise are not confirmed
+	assert.False(t, validate(tridentconfig.ReadWriteMany, csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER))
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	iSCSIPathRescan    = "rescan"
+	iSCSIPathReinstate = "reinstate"
+	iSCSIPathRemove    = "remove"
+)
+
+// ISCSIPathHealingPolicy controls what iSCSI self-healing does about failed paths of published volumes.
+type ISCSIPathHealingPolicy struct {
+	// Rescan failed paths whose sessions are logged in, bringing offline devices back online
+	Rescan bool
+	// Reinstate paths that the path checker finds usable again but device mapper still has failed; paths the
+	// checker finds faulty are left to the checker
+	Reinstate bool
+	// Remove paths that have failed for this long, as long as the volume has another healthy path; zero disables
+	LostPathRemovalTime time.Duration
+}
+
+// failedISCSIPaths records when self-healing first found each failed path, keyed by device.  It is only accessed
+// while holding the self-healing lock.
+var failedISCSIPaths = make(map[string]time.Time)
+
+type iSCSILUN struct {
+	targetIQN string
+	lun       int32
+}
+
+type iSCSIVolumePaths struct {
+	active int
+	failed int
+}
+
+type iSCSIPathAction struct {
+	action   string
+	volumeID string
+	path     utils.ISCSIPath
+}
+
+// planISCSIPathHealing counts the healthy and failed paths of each published volume, and decides what to do about
+// the failed ones according to the policy.  Paths of sessions that are not logged in are left to session healing,
+// unless they have failed long enough to be removed.
+func planISCSIPathHealing(
+	paths []utils.ISCSIPath, volumes map[iSCSILUN]string, failedSince map[string]time.Time,
+	policy ISCSIPathHealingPolicy, now time.Time,
+) (map[string]*iSCSIVolumePaths, []iSCSIPathAction) {
+	volumePaths := make(map[string]*iSCSIVolumePaths, len(volumes))
+	for _, volumeID := range volumes {
+		volumePaths[volumeID] = &iSCSIVolumePaths{}
+	}
+
+	// Count the healthy paths of each volume first, since the last healthy path is never removed
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		volumeID, ok := volumes[iSCSILUN{path.TargetIQN, path.LUN}]
+		if !ok {
+			continue
+		}
+		seen[path.Device] = true
+		if path.IsHealthy() {
+			volumePaths[volumeID].active++
+			delete(failedSince, path.Device)
+		} else {
+			volumePaths[volumeID].failed++
+			if _, ok := failedSince[path.Device]; !ok {
+				failedSince[path.Device] = now
+			}
+		}
+	}
+
+	// Forget devices that are gone
+	for device := range failedSince {
+		if !seen[device] {
+			delete(failedSince, device)
+		}
+	}
+
+	actions := make([]iSCSIPathAction, 0)
+	for _, path := range paths {
+		volumeID, ok := volumes[iSCSILUN{path.TargetIQN, path.LUN}]
+		if !ok || path.IsHealthy() {
+			continue
+		}
+
+		if policy.LostPathRemovalTime > 0 && now.Sub(failedSince[path.Device]) >= policy.LostPathRemovalTime &&
+			volumePaths[volumeID].active > 0 {
+			actions = append(actions, iSCSIPathAction{iSCSIPathRemove, volumeID, path})
+			continue
+		}
+
+		if !path.SessionLoggedIn() {
+			continue
+		}
+		if policy.Rescan {
+			actions = append(actions, iSCSIPathAction{iSCSIPathRescan, volumeID, path})
+		}
+		if policy.Reinstate && path.NeedsReinstatement() {
+			actions = append(actions, iSCSIPathAction{iSCSIPathReinstate, volumeID, path})
+		}
+	}
+
+	return volumePaths, actions
+}
+
+// publishedISCSIVolumes returns the volume published through each iSCSI LUN.
+func publishedISCSIVolumes() map[iSCSILUN]string {
+	volumes := make(map[iSCSILUN]string)
+	for _, sessionData := range publishedISCSISessions.Info {
+		if sessionData == nil {
+			continue
+		}
+		for lun, volumeID := range sessionData.LUNs.Info {
+			volumes[iSCSILUN{sessionData.PortalInfo.ISCSITargetIQN, lun}] = volumeID
+		}
+	}
+	return volumes
+}
+
+// healISCSIPaths restores failed paths of published iSCSI volumes that session healing leaves alone, since their
+// sessions are logged in, and records the number of paths of each volume.
+func (p *Plugin) healISCSIPaths(ctx context.Context, stopAt time.Time) {
+	paths, err := utils.GetISCSIPaths(ctx)
+	if err != nil {
+		Logc(ctx).WithError(err).Error("Failed to get iSCSI paths; skipping iSCSI path healing.")
+		return
+	}
+
+	volumePaths, actions := planISCSIPathHealing(paths, publishedISCSIVolumes(), failedISCSIPaths,
+		p.iSCSIPathHealingPolicy, time.Now())
+
+	iSCSIVolumePathsGauge.Reset()
+	for volumeID, counts := range volumePaths {
+		iSCSIVolumePathsGauge.WithLabelValues(volumeID, "active").Set(float64(counts.active))
+		iSCSIVolumePathsGauge.WithLabelValues(volumeID, "failed").Set(float64(counts.failed))
+	}
+
+	if len(actions) == 0 {
+		Logc(ctx).Debug("No failed iSCSI paths require remediation.")
+		return
+	}
+
+	// Remove lost paths last, in case the other actions are preempted
+	sort.SliceStable(actions, func(i, j int) bool {
+		return actions[i].action != iSCSIPathRemove && actions[j].action == iSCSIPathRemove
+	})
+
+	for idx, action := range actions {
+		if idx > 0 && (utils.WaitQueueSize(lockID) > 0 || time.Now().After(stopAt)) {
+			Logc(ctx).Debug("Preempting iSCSI path healing.")
+			break
+		}
+
+		fields := log.Fields{
+			"action":    action.action,
+			"volumeID":  action.volumeID,
+			"device":    action.path.Device,
+			"session":   action.path.SessionNumber,
+			"dmState":   action.path.DMState,
+			"checker":   action.path.CheckerState,
+			"devState":  action.path.DeviceState,
+			"targetIQN": action.path.TargetIQN,
+		}
+
+		switch action.action {
+		case iSCSIPathRescan:
+			err = utils.RescanISCSIPath(ctx, action.path.Device)
+		case iSCSIPathReinstate:
+			err = utils.ReinstateMultipathPath(ctx, action.path.Device)
+		case iSCSIPathRemove:
+			if err = utils.RemoveISCSIPath(ctx, action.path.Device); err == nil {
+				delete(failedISCSIPaths, action.path.Device)
+			}
+		}
+
+		if err != nil {
+			iSCSIPathHealingActionsCounter.WithLabelValues(action.action, "failure").Inc()
+			Logc(ctx).WithFields(fields).WithError(err).Error("Failed to heal iSCSI path.")
+		} else {
+			iSCSIPathHealingActionsCounter.WithLabelValues(action.action, "success").Inc()
+			Logc(ctx).WithFields(fields).Info("Healed iSCSI path.")
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/utils"
+)
+
+func healthyISCSIPath(device string, session int, lun int32) utils.ISCSIPath {
+	return utils.ISCSIPath{
+		Device:          device,
+		TargetIQN:       testIQN,
+		LUN:             lun,
+		SessionNumber:   session,
+		SessionState:    "LOGGED_IN",
+		DeviceState:     "running",
+		MultipathDevice: "dm-0",
+		DMState:         "active",
+		CheckerState:    "ready",
+	}
+}
+
+func iSCSIPathActionNames(actions []iSCSIPathAction) []string {
+	names := make([]string, 0, len(actions))
+	for _, action := range actions {
+		names = append(names, action.action+" "+action.path.Device)
+	}
+	return names
+}
+
+func TestPlanISCSIPathHealing(t *testing.T) {
+	now := time.Now()
+	volumes := map[iSCSILUN]string{{testIQN, 0}: "pvc-1"}
+	policy := ISCSIPathHealingPolicy{Rescan: true, Reinstate: true, LostPathRemovalTime: 10 * time.Minute}
+
+	failedDM := healthyISCSIPath("sdc", 2, 0)
+	failedDM.DMState = "failed"
+	failedDM.CheckerState = "faulty"
+
+	recovered := healthyISCSIPath("sdg", 6, 0)
+	recovered.DMState = "failed"
+
+	notInMap := healthyISCSIPath("sdd", 3, 0)
+	notInMap.DMState = ""
+	notInMap.CheckerState = ""
+	notInMap.MultipathDevice = ""
+
+	sessionDown := healthyISCSIPath("sde", 4, 0)
+	sessionDown.SessionState = "FAILED"
+	sessionDown.DeviceState = "transport-offline"
+	sessionDown.DMState = "failed"
+
+	otherTarget := healthyISCSIPath("sdf", 5, 0)
+	otherTarget.TargetIQN = testOtherIQN
+	otherTarget.DMState = "failed"
+
+	paths := []utils.ISCSIPath{
+		healthyISCSIPath("sdb", 1, 0), failedDM, notInMap, sessionDown, otherTarget, recovered,
+	}
+	failedSince := map[string]time.Time{"sdb": now.Add(-time.Hour), "sdz": now.Add(-time.Hour)}
+
+	volumePaths, actions := planISCSIPathHealing(paths, volumes, failedSince, policy, now)
+
+	assert.Equal(t, map[string]*iSCSIVolumePaths{"pvc-1": {active: 1, failed: 4}}, volumePaths)
+	// Only paths the checker finds usable are reinstated
+	assert.Equal(t, []string{"rescan sdc", "rescan sdd", "rescan sdg", "reinstate sdg"},
+		iSCSIPathActionNames(actions))
+	assert.Equal(t, map[string]time.Time{"sdc": now, "sdd": now, "sde": now, "sdg": now}, failedSince,
+		"healthy and missing devices should be forgotten")
+
+	// Paths that stay failed past the removal time are removed, regardless of their session
+	later := now.Add(policy.LostPathRemovalTime)
+	_, actions = planISCSIPathHealing(paths, volumes, failedSince, policy, later)
+	assert.Equal(t, []string{"remove sdc", "remove sdd", "remove sde", "remove sdg"}, iSCSIPathActionNames(actions))
+
+	// The last healthy path is never removed
+	_, actions = planISCSIPathHealing(paths[1:], volumes, failedSince, policy, later)
+	assert.Equal(t, []string{"rescan sdc", "rescan sdd", "rescan sdg", "reinstate sdg"},
+		iSCSIPathActionNames(actions))
+
+	// Nothing is done beyond counting paths if the policy allows nothing
+	volumePaths, actions = planISCSIPathHealing(paths, volumes, failedSince, ISCSIPathHealingPolicy{}, later)
+	assert.Equal(t, map[string]*iSCSIVolumePaths{"pvc-1": {active: 1, failed: 4}}, volumePaths)
+	assert.Empty(t, actions)
+}
+
+func TestPlanISCSIPathHealing_NoPaths(t *testing.T) {
+	volumes := map[iSCSILUN]string{{testIQN, 0}: "pvc-1", {testIQN, 1}: "pvc-2"}
+
+	volumePaths, actions := planISCSIPathHealing(nil, volumes, make(map[string]time.Time),
+		ISCSIPathHealingPolicy{Rescan: true, Reinstate: true}, time.Now())
+
+	assert.Equal(t, map[string]*iSCSIVolumePaths{"pvc-1": {}, "pvc-2": {}}, volumePaths)
+	assert.Empty(t, actions)
+}
+
+func TestPublishedISCSIVolumes(t *testing.T) {
+	original := publishedISCSISessions
+	defer func() { publishedISCSISessions = original }()
+
+	publishedISCSISessions = utils.ISCSISessions{Info: map[string]*utils.ISCSISessionData{
+		"10.0.0.1": {
+			PortalInfo: utils.PortalInfo{ISCSITargetIQN: testIQN},
+			LUNs:       utils.LUNs{Info: map[int32]string{0: "pvc-1", 1: "pvc-2"}},
+		},
+		"10.0.0.2": {
+			PortalInfo: utils.PortalInfo{ISCSITargetIQN: testOtherIQN},
+			LUNs:       utils.LUNs{Info: map[int32]string{0: "pvc-3"}},
+		},
+		"10.0.0.3": nil,
+	}}
+
+	assert.Equal(t, map[iSCSILUN]string{
+		{testIQN, 0}: "pvc-1", {testIQN, 1}: "pvc-2", {testOtherIQN, 0}: "pvc-3",
+	}, publishedISCSIVolumes())
+}
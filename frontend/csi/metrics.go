@@ -0,0 +1,31 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/netapp/trident/config"
+)
+
+var (
+	iSCSIVolumePathsGauge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "iscsi",
+			Name:      "volume_paths",
+			Help:      "The number of iSCSI paths of a volume published on the node, grouped by path state",
+		},
+		[]string{"volume", "state"},
+	)
+	iSCSIPathHealingActionsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.OrchestratorName,
+			Subsystem: "iscsi",
+			Name:      "path_healing_actions_total",
+			Help:      "The total number of actions iSCSI self-healing has taken on failed paths",
+		},
+		[]string{"action", "result"},
+	)
+)
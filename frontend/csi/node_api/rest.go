@@ -0,0 +1,212 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package nodeAPI
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+// HTTPClientTimeout is generous because node operations such as LUKS key changes run external commands.
+const HTTPClientTimeout = time.Second * 60
+
+type NodeRestClient struct {
+	url        string
+	httpClient http.Client
+}
+
+// CreateTLSRestClient creates a client for the HTTPS REST interface of a Trident node.  Nodes present the same
+// server certificate as the controller, which is always verified against the CA, and the client certificate
+// authenticates the caller to the node.
+func CreateTLSRestClient(url, caFile, certFile, keyFile string) (TridentNode, error) {
+	if caFile == "" {
+		return nil, fmt.Errorf("a CA certificate is required to verify Trident nodes")
+	}
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no CA certificates found in %s", caFile)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: config.MinClientTLSVersion,
+		RootCAs:    caCertPool,
+		ServerName: config.ServerCertName,
+	}
+	if "" != certFile && "" != keyFile {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &NodeRestClient{
+		url: url,
+		httpClient: http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig,
+			},
+			Timeout: HTTPClientTimeout,
+		},
+	}, nil
+}
+
+// InvokeAPI makes a REST call to a Trident node REST endpoint. The body must be a marshaled JSON byte array (
+// or nil). The method is the HTTP verb (i.e. GET, POST, ...).  The resource path is appended to the base URL to
+// identify the desired server resource; it should start with '/'.
+func (c *NodeRestClient) InvokeAPI(
+	ctx context.Context, requestBody []byte, method, resourcePath string, redactRequestBody,
+	redactResponseBody bool,
+) (*http.Response, []byte, error) {
+	// Build URL
+	url := c.url + resourcePath
+
+	var request *http.Request
+	var err error
+	var prettyRequestBuffer bytes.Buffer
+	var prettyResponseBuffer bytes.Buffer
+
+	// Create the request
+	if requestBody == nil {
+		request, err = http.NewRequestWithContext(ctx, method, url, nil)
+	} else {
+		request, err = http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(requestBody))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request.Header.Set("X-Request-ID", fmt.Sprint(ctx.Value(ContextKeyRequestID)))
+	request.Header.Set("Content-Type", "application/json")
+
+	// Log the request
+	if requestBody != nil {
+		if err = json.Indent(&prettyRequestBuffer, requestBody, "", "  "); err != nil {
+			return nil, nil, fmt.Errorf("error formating request body; %v", err)
+		}
+	}
+
+	utils.LogHTTPRequest(request, prettyRequestBuffer.Bytes(), redactRequestBody)
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		err = fmt.Errorf("error communicating with Trident node; %v", err)
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response body; %v", err)
+	}
+
+	if len(responseBody) > 0 {
+		if err = json.Indent(&prettyResponseBuffer, responseBody, "", "  "); err != nil {
+			return nil, nil, fmt.Errorf("error formating response body; %v", err)
+		}
+	}
+	utils.LogHTTPResponse(ctx, response, prettyResponseBuffer.Bytes(), redactResponseBody)
+
+	return response, responseBody, err
+}
+
+// RotateLUKSPassphrase asks the node to rotate the passphrase of a staged LUKS volume to the current
+// passphrase in the supplied secrets.
+func (c *NodeRestClient) RotateLUKSPassphrase(
+	ctx context.Context, volume, internalName string, secrets map[string]string,
+) error {
+	body, err := json.Marshal(&RotateLUKSPassphraseRequest{InternalName: internalName, Secrets: secrets})
+	if err != nil {
+		return fmt.Errorf("could not marshal JSON; %v", err)
+	}
+	url := config.VolumeURL + "/" + volume + "/luksPassphrase"
+	resp, respBody, err := c.InvokeAPI(ctx, body, "POST", url, true, false)
+	if err != nil {
+		return fmt.Errorf("could not communicate with the Trident node: %v", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return utils.NotFoundError(fmt.Sprintf("volume %s is not staged on the node", volume))
+	}
+
+	rotateResponse := RotateLUKSPassphraseResponse{}
+	if err = json.Unmarshal(respBody, &rotateResponse); err == nil && rotateResponse.Error != "" {
+		return fmt.Errorf("could not rotate LUKS passphrase; %s", rotateResponse.Error)
+	}
+	return fmt.Errorf("could not rotate LUKS passphrase; node returned status %d", resp.StatusCode)
+}
+
+// PreemptReservationKeys asks the node to preempt the SCSI persistent reservation keys of nodes that were force
+// detached from a volume it has staged.
+func (c *NodeRestClient) PreemptReservationKeys(ctx context.Context, volume string, reservationKeys []string) error {
+	body, err := json.Marshal(&PreemptReservationKeysRequest{ReservationKeys: reservationKeys})
+	if err != nil {
+		return fmt.Errorf("could not marshal JSON; %v", err)
+	}
+	url := config.VolumeURL + "/" + volume + "/reservation/preempt"
+	resp, respBody, err := c.InvokeAPI(ctx, body, "POST", url, false, false)
+	if err != nil {
+		return fmt.Errorf("could not communicate with the Trident node: %v", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return utils.NotFoundError(fmt.Sprintf("volume %s is not staged on the node", volume))
+	}
+
+	preemptResponse := PreemptReservationKeysResponse{}
+	if err = json.Unmarshal(respBody, &preemptResponse); err == nil && preemptResponse.Error != "" {
+		return fmt.Errorf("could not preempt reservation keys; %s", preemptResponse.Error)
+	}
+	return fmt.Errorf("could not preempt reservation keys; node returned status %d", resp.StatusCode)
+}
+
+// CheckNode asks the node to check its volume tracking files against its mounts, iSCSI sessions, multipath maps
+// and loop devices, and to repair the findings if requested.
+func (c *NodeRestClient) CheckNode(
+	ctx context.Context, nodeName string, request *NodeCheckRequest,
+) (*utils.NodeCheckReport, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal JSON; %v", err)
+	}
+	url := config.NodeURL + "/" + nodeName + "/check"
+	resp, respBody, err := c.InvokeAPI(ctx, body, "POST", url, false, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not communicate with the Trident node: %v", err)
+	}
+
+	checkResponse := CheckNodeResponse{}
+	if err = json.Unmarshal(respBody, &checkResponse); err != nil {
+		return nil, fmt.Errorf("could not parse node check response; node returned status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if checkResponse.Error != "" {
+			return nil, fmt.Errorf("could not check node; %s", checkResponse.Error)
+		}
+		return nil, fmt.Errorf("could not check node; node returned status %d", resp.StatusCode)
+	}
+	if checkResponse.Report == nil {
+		return nil, fmt.Errorf("node check response did not include a report")
+	}
+	return checkResponse.Report, nil
+}
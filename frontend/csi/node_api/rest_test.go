@@ -0,0 +1,220 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package nodeAPI
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils"
+)
+
+var ctx = context.Background()
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	os.Exit(m.Run())
+}
+
+func getHttpServer(url string, mockFunction func(w http.ResponseWriter, r *http.Request)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == url {
+			mockFunction(w, r)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+func TestRotateLUKSPassphrase(t *testing.T) {
+	secrets := map[string]string{
+		"luks-passphrase-name": "B",
+		"luks-passphrase":      "passphraseB",
+	}
+	url := config.VolumeURL + "/pvc-1/luksPassphrase"
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		response    interface{}
+		isNotFound  bool
+		expectError bool
+	}{
+		{"Success", http.StatusOK, RotateLUKSPassphraseResponse{}, false, false},
+		{"NotStaged", http.StatusNotFound, RotateLUKSPassphraseResponse{Error: "not found"}, true, true},
+		{"Failed", http.StatusInternalServerError, RotateLUKSPassphraseResponse{Error: "failed"}, false, true},
+		{"EmptyErrorBody", http.StatusBadRequest, nil, false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := getHttpServer(url, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+
+				request := RotateLUKSPassphraseRequest{}
+				body, _ := io.ReadAll(r.Body)
+				assert.NoError(t, json.Unmarshal(body, &request))
+				assert.Equal(t, "trident_pvc_1", request.InternalName)
+				assert.Equal(t, secrets, request.Secrets)
+
+				w.WriteHeader(test.statusCode)
+				if test.response != nil {
+					_ = json.NewEncoder(w).Encode(test.response)
+				}
+			})
+			defer server.Close()
+
+			client := &NodeRestClient{url: server.URL, httpClient: *server.Client()}
+			err := client.RotateLUKSPassphrase(ctx, "pvc-1", "trident_pvc_1", secrets)
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.isNotFound, utils.IsNotFoundError(err))
+		})
+	}
+}
+
+func TestRotateLUKSPassphrase_NoServer(t *testing.T) {
+	server := getHttpServer("/", func(w http.ResponseWriter, r *http.Request) {})
+	server.Close()
+
+	client := &NodeRestClient{url: server.URL, httpClient: *server.Client()}
+	err := client.RotateLUKSPassphrase(ctx, "pvc-1", "trident_pvc_1", nil)
+	assert.Error(t, err)
+}
+
+func TestPreemptReservationKeys(t *testing.T) {
+	url := config.VolumeURL + "/pvc-1/reservation/preempt"
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		response    interface{}
+		isNotFound  bool
+		expectError bool
+	}{
+		{"Success", http.StatusOK, PreemptReservationKeysResponse{}, false, false},
+		{"NotStaged", http.StatusNotFound, PreemptReservationKeysResponse{Error: "not found"}, true, true},
+		{"Failed", http.StatusInternalServerError, PreemptReservationKeysResponse{Error: "failed"}, false, true},
+		{"EmptyErrorBody", http.StatusBadRequest, nil, false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := getHttpServer(url, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+
+				request := PreemptReservationKeysRequest{}
+				body, _ := io.ReadAll(r.Body)
+				assert.NoError(t, json.Unmarshal(body, &request))
+				assert.Equal(t, []string{"0x1a2b"}, request.ReservationKeys)
+
+				w.WriteHeader(test.statusCode)
+				if test.response != nil {
+					_ = json.NewEncoder(w).Encode(test.response)
+				}
+			})
+			defer server.Close()
+
+			client := &NodeRestClient{url: server.URL, httpClient: *server.Client()}
+			err := client.PreemptReservationKeys(ctx, "pvc-1", []string{"0x1a2b"})
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.isNotFound, utils.IsNotFoundError(err))
+		})
+	}
+}
+
+func TestCreateTLSRestClient(t *testing.T) {
+	certInfo, err := utils.MakeHTTPCertInfo(config.CACertName, config.ServerCertName, config.ClientCertName,
+		config.ControllerCertName)
+	assert.NoError(t, err)
+	caCert, err := base64.StdEncoding.DecodeString(certInfo.CACert)
+	assert.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), config.CACertFile)
+	assert.NoError(t, os.WriteFile(caFile, caCert, 0o600))
+	client, err := CreateTLSRestClient("https://1.1.1.1:8443", caFile, "", "")
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+
+	// Nodes are never trusted without a CA
+	_, err = CreateTLSRestClient("https://1.1.1.1:8443", "", "", "")
+	assert.Error(t, err)
+
+	_, err = CreateTLSRestClient("https://1.1.1.1:8443", "/no/such/ca.crt", "", "")
+	assert.Error(t, err)
+
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	assert.NoError(t, os.WriteFile(emptyFile, []byte{}, 0o600))
+	_, err = CreateTLSRestClient("https://1.1.1.1:8443", emptyFile, "", "")
+	assert.Error(t, err)
+}
+
+func TestCheckNode(t *testing.T) {
+	url := config.NodeURL + "/node1/check"
+	report := &utils.NodeCheckReport{
+		Node:   "node1",
+		Repair: true,
+		Findings: []utils.NodeCheckFinding{{
+			Type:     utils.NodeCheckDanglingTrackingFile,
+			VolumeID: "pvc-1",
+			Resource: "pvc-1.json",
+			Repaired: true,
+		}},
+	}
+
+	tests := []struct {
+		name        string
+		statusCode  int
+		response    interface{}
+		expectError bool
+	}{
+		{"Success", http.StatusOK, CheckNodeResponse{Report: report}, false},
+		{"MissingReport", http.StatusOK, CheckNodeResponse{}, true},
+		{"Failed", http.StatusInternalServerError, CheckNodeResponse{Error: "failed"}, true},
+		{"EmptyErrorBody", http.StatusBadRequest, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := getHttpServer(url, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+
+				request := NodeCheckRequest{}
+				body, _ := io.ReadAll(r.Body)
+				assert.NoError(t, json.Unmarshal(body, &request))
+				assert.True(t, request.Repair)
+				assert.False(t, request.DryRun)
+
+				w.WriteHeader(test.statusCode)
+				if test.response != nil {
+					_ = json.NewEncoder(w).Encode(test.response)
+				}
+			})
+			defer server.Close()
+
+			client := &NodeRestClient{url: server.URL, httpClient: *server.Client()}
+			result, err := client.CheckNode(ctx, "node1", &NodeCheckRequest{Repair: true})
+			if test.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, report, result)
+			}
+		})
+	}
+}
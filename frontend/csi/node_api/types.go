@@ -0,0 +1,56 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package nodeAPI
+
+//go:generate mockgen -destination=../../../mocks/mock_frontend/mock_csi/mock_node_api/mock_node_api.go github.com/netapp/trident/frontend/csi/node_api TridentNode
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/netapp/trident/utils"
+)
+
+type TridentNode interface {
+	InvokeAPI(
+		ctx context.Context, requestBody []byte, method, resourcePath string, redactRequestBody,
+		redactResponseBody bool,
+	) (*http.Response, []byte, error)
+	RotateLUKSPassphrase(ctx context.Context, volume, internalName string, secrets map[string]string) error
+	CheckNode(ctx context.Context, nodeName string, request *NodeCheckRequest) (*utils.NodeCheckReport, error)
+	PreemptReservationKeys(ctx context.Context, volume string, reservationKeys []string) error
+}
+
+// RotateLUKSPassphraseRequest is sent by the controller to a node that has a LUKS volume staged.
+type RotateLUKSPassphraseRequest struct {
+	InternalName string            `json:"internalName"`
+	Secrets      map[string]string `json:"secrets"`
+}
+
+type RotateLUKSPassphraseResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+// NodeCheckRequest asks a node to compare its volume tracking files with its kernel state and, optionally, to
+// repair what it finds.
+type NodeCheckRequest struct {
+	Repair bool `json:"repair"`
+	DryRun bool `json:"dryRun"`
+	// ISCSITargets are the target IQNs of Trident volumes; sessions to other targets are never logged out
+	ISCSITargets []string `json:"iscsiTargets,omitempty"`
+}
+
+type CheckNodeResponse struct {
+	Report *utils.NodeCheckReport `json:"report,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// PreemptReservationKeysRequest is sent by the controller to a node sharing a multi-attach block volume when other
+// nodes are force detached from it.
+type PreemptReservationKeysRequest struct {
+	ReservationKeys []string `json:"reservationKeys"`
+}
+
+type PreemptReservationKeysResponse struct {
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,536 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/netapp/trident/config"
+	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	nodeCheckLockContext = "NodeCheck"
+
+	kubeletCSIVolumesDir       = "kubernetes.io~csi"
+	kubeletCSIVolumeDevicesDir = "volumeDevices"
+	kubeletVolumeDataFilename  = "vol_data.json"
+)
+
+// kubeletVolumeData is the subset of the volume data kubelet saves next to each CSI volume it publishes.
+type kubeletVolumeData struct {
+	DriverName   string `json:"driverName"`
+	VolumeHandle string `json:"volumeHandle"`
+}
+
+// nodeCheckItem is a node check finding along with the action that repairs it, if there is one.
+type nodeCheckItem struct {
+	finding utils.NodeCheckFinding
+	repair  func(ctx context.Context) error
+}
+
+// CheckNode compares this node's volume tracking files with its mounts, iSCSI sessions, multipath maps and loop
+// devices, and reports what was left behind or is missing.  If repair is set and dryRun is not, each finding that
+// can be repaired automatically is repaired.  Only iSCSI sessions and multipath maps of the given targets, which are
+// the targets of Trident volumes, or of targets this node published volumes from, are considered orphaned.  The check
+// holds the node lock, so CSI requests wait for it, but kubelet may still retry requests for volumes whose resources
+// are repaired, so repairs should only be made while the node is cordoned and drained.
+func (p *Plugin) CheckNode(
+	ctx context.Context, repair, dryRun bool, iSCSITargets []string,
+) (*utils.NodeCheckReport, error) {
+	fields := log.Fields{"repair": repair, "dryRun": dryRun}
+	Logc(ctx).WithFields(fields).Debug(">>>> CheckNode")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< CheckNode")
+
+	utils.Lock(ctx, nodeCheckLockContext, lockID)
+	defer utils.Unlock(ctx, nodeCheckLockContext, lockID)
+
+	trackingInfos, invalidVolumeIDs, err := p.readAllTrackingInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := utils.GetNodeState(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	knownISCSITargets := make(map[string]struct{}, len(iSCSITargets))
+	for _, target := range iSCSITargets {
+		knownISCSITargets[target] = struct{}{}
+	}
+	for _, sessionData := range publishedISCSISessions.Info {
+		if sessionData != nil && sessionData.PortalInfo.ISCSITargetIQN != "" {
+			knownISCSITargets[sessionData.PortalInfo.ISCSITargetIQN] = struct{}{}
+		}
+	}
+
+	items := p.findNodeInconsistencies(ctx, trackingInfos, invalidVolumeIDs, state, findTridentPublications(ctx,
+		state.Mounts), knownISCSITargets)
+
+	report := &utils.NodeCheckReport{
+		Node:     p.nodeName,
+		Repair:   repair,
+		DryRun:   dryRun,
+		Findings: make([]utils.NodeCheckFinding, 0, len(items)),
+	}
+
+	for _, item := range items {
+		finding := item.finding
+		if repair && !dryRun && item.repair != nil {
+			if err := item.repair(ctx); err != nil {
+				Logc(ctx).WithFields(log.Fields{
+					"type":     finding.Type,
+					"resource": finding.Resource,
+				}).WithError(err).Error("Could not repair node check finding.")
+				finding.Error = err.Error()
+			} else {
+				finding.Repaired = true
+			}
+		}
+		report.Findings = append(report.Findings, finding)
+	}
+
+	Logc(ctx).WithField("findings", len(report.Findings)).Info("Node check complete.")
+	return report, nil
+}
+
+// readAllTrackingInfo reads every volume tracking file on this node.  The IDs of volumes whose tracking files cannot
+// be parsed are returned separately.
+func (p *Plugin) readAllTrackingInfo(
+	ctx context.Context,
+) (map[string]*utils.VolumeTrackingInfo, []string, error) {
+	files, err := p.nodeHelper.GetVolumeTrackingFiles()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list volume tracking files; %v", err)
+	}
+
+	trackingInfos := make(map[string]*utils.VolumeTrackingInfo)
+	invalidVolumeIDs := make([]string, 0)
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") || strings.HasPrefix(file.Name(), "tmp-") {
+			continue
+		}
+		volumeID := strings.TrimSuffix(file.Name(), ".json")
+
+		trackingInfo, err := p.nodeHelper.ReadTrackingInfo(ctx, volumeID)
+		if err != nil {
+			if utils.IsInvalidJSONError(err) {
+				invalidVolumeIDs = append(invalidVolumeIDs, volumeID)
+				continue
+			}
+			return nil, nil, fmt.Errorf("could not read tracking file for volume %s; %v", volumeID, err)
+		}
+		if trackingInfo.PublishedPaths == nil {
+			trackingInfo.PublishedPaths = make(map[string]struct{})
+		}
+		trackingInfos[volumeID] = trackingInfo
+	}
+
+	return trackingInfos, invalidVolumeIDs, nil
+}
+
+// findTridentPublications returns the mounts kubelet asked Trident to publish, mapped to their volume IDs.  Kubelet
+// records the driver and volume handle of each CSI publication in a vol_data.json file next to the target path of a
+// filesystem volume (.../volumes/kubernetes.io~csi/<pv>/mount), or in the data directory of a raw block volume
+// (.../kubernetes.io/csi/volumeDevices/<pv>/data) whose target path is .../volumeDevices/publish/<pv>/<pod>.
+func findTridentPublications(ctx context.Context, mounts []utils.MountInfo) map[string]string {
+	publications := make(map[string]string)
+
+	for _, mount := range mounts {
+		var volumeDataPath string
+		mountPoint := mount.MountPoint
+		parent := filepath.Dir(mountPoint)
+
+		if filepath.Base(mountPoint) == "mount" && filepath.Base(filepath.Dir(parent)) == kubeletCSIVolumesDir {
+			volumeDataPath = filepath.Join(parent, kubeletVolumeDataFilename)
+		} else if filepath.Base(filepath.Dir(parent)) == "publish" &&
+			filepath.Base(filepath.Dir(filepath.Dir(parent))) == kubeletCSIVolumeDevicesDir {
+			volumeDataPath = filepath.Join(filepath.Dir(filepath.Dir(parent)), filepath.Base(parent), "data",
+				kubeletVolumeDataFilename)
+		} else {
+			continue
+		}
+
+		bytes, err := afero.ReadFile(osFs, volumeDataPath)
+		if err != nil {
+			Logc(ctx).WithField("path", volumeDataPath).WithError(err).Debug("Could not read kubelet volume data.")
+			continue
+		}
+		var volumeData kubeletVolumeData
+		if err = json.Unmarshal(bytes, &volumeData); err != nil {
+			Logc(ctx).WithField("path", volumeDataPath).WithError(err).Debug("Could not parse kubelet volume data.")
+			continue
+		}
+		if volumeData.DriverName == Provisioner && volumeData.VolumeHandle != "" {
+			publications[mountPoint] = volumeData.VolumeHandle
+		}
+	}
+
+	return publications
+}
+
+// findNodeInconsistencies compares the tracking files on this node with its kernel state.  The findings are ordered
+// so that their repairs can be made in sequence, e.g. an orphaned multipath map is flushed before the iSCSI session
+// it runs over is logged out.  iSCSI sessions and multipath maps are only reported if they belong to known targets,
+// since other workloads on the node may use iSCSI too.
+func (p *Plugin) findNodeInconsistencies(
+	ctx context.Context, trackingInfos map[string]*utils.VolumeTrackingInfo, invalidVolumeIDs []string,
+	state *utils.NodeState, publications map[string]string, knownISCSITargets map[string]struct{},
+) []nodeCheckItem {
+	items := make([]nodeCheckItem, 0)
+
+	mountPoints := make(map[string]struct{})
+	mountSources := make(map[string]struct{})
+	for _, mount := range state.Mounts {
+		mountPoints[mount.MountPoint] = struct{}{}
+		mountSources[mount.MountSource] = struct{}{}
+	}
+
+	iSCSITargets := make(map[string]struct{})
+	knownSessions := make(map[string]struct{})
+	for _, session := range state.ISCSISessions {
+		iSCSITargets[session.TargetName] = struct{}{}
+		if _, ok := knownISCSITargets[session.TargetName]; ok {
+			knownSessions[session.SID] = struct{}{}
+		}
+	}
+
+	loopBackFiles := make(map[string]struct{})
+	for _, loopDevice := range state.LoopDevices {
+		loopBackFiles[loopDevice.BackFile] = struct{}{}
+	}
+
+	volumeIDs := make([]string, 0, len(trackingInfos))
+	for volumeID := range trackingInfos {
+		volumeIDs = append(volumeIDs, volumeID)
+	}
+	sort.Strings(volumeIDs)
+
+	for _, volumeID := range invalidVolumeIDs {
+		volumeID := volumeID
+		items = append(items, nodeCheckItem{
+			finding: utils.NodeCheckFinding{
+				Type:     utils.NodeCheckInvalidTrackingFile,
+				VolumeID: volumeID,
+				Resource: volumeID + ".json",
+				Message:  "the volume tracking file cannot be parsed",
+				Repair:   "delete the tracking file",
+			},
+			repair: func(ctx context.Context) error {
+				return p.nodeHelper.DeleteTrackingInfo(ctx, volumeID)
+			},
+		})
+	}
+
+	// Publications in pods
+	publishedMountPoints := make([]string, 0, len(publications))
+	publishedVolumes := make(map[string]struct{})
+	for mountPoint, volumeID := range publications {
+		publishedMountPoints = append(publishedMountPoints, mountPoint)
+		publishedVolumes[volumeID] = struct{}{}
+	}
+	sort.Strings(publishedMountPoints)
+
+	for _, mountPoint := range publishedMountPoints {
+		mountPoint, volumeID := mountPoint, publications[mountPoint]
+		trackingInfo, ok := trackingInfos[volumeID]
+		if !ok {
+			if utils.SliceContainsString(invalidVolumeIDs, volumeID) {
+				continue
+			}
+			items = append(items, nodeCheckItem{
+				finding: utils.NodeCheckFinding{
+					Type:     utils.NodeCheckOrphanedMount,
+					VolumeID: volumeID,
+					Resource: mountPoint,
+					Message:  "the volume is mounted in a pod but has no tracking file",
+					Repair:   "unmount the path",
+				},
+				repair: func(ctx context.Context) error {
+					return utils.Umount(ctx, mountPoint)
+				},
+			})
+		} else if _, ok = trackingInfo.PublishedPaths[mountPoint]; !ok {
+			items = append(items, nodeCheckItem{
+				finding: utils.NodeCheckFinding{
+					Type:     utils.NodeCheckUntrackedPublishedPath,
+					VolumeID: volumeID,
+					Resource: mountPoint,
+					Message:  "the volume is mounted in a pod but the path is missing from its tracking file",
+					Repair:   "add the path to the tracking file",
+				},
+				repair: func(ctx context.Context) error {
+					return p.nodeHelper.AddPublishedPath(ctx, volumeID, mountPoint)
+				},
+			})
+		}
+	}
+
+	// Tracking files
+	trackedIQNs := make(map[string]struct{})
+	trackedDevices := make(map[string]struct{})
+	trackedLoopFiles := make(map[string]struct{})
+
+	for _, volumeID := range volumeIDs {
+		volumeID, trackingInfo := volumeID, trackingInfos[volumeID]
+		publishInfo := &trackingInfo.VolumePublishInfo
+
+		protocol, err := getVolumeProtocolFromPublishInfo(publishInfo)
+		if err != nil {
+			items = append(items, nodeCheckItem{
+				finding: utils.NodeCheckFinding{
+					Type:     utils.NodeCheckInvalidTrackingFile,
+					VolumeID: volumeID,
+					Resource: volumeID + ".json",
+					Message:  fmt.Sprintf("the volume tracking file is invalid; %v", err),
+				},
+			})
+			continue
+		}
+
+		switch protocol {
+		case config.Block:
+			trackedIQNs[publishInfo.IscsiTargetIQN] = struct{}{}
+			if publishInfo.DevicePath != "" {
+				trackedDevices[filepath.Base(publishInfo.DevicePath)] = struct{}{}
+			}
+		case config.BlockOnFile:
+			trackedLoopFiles[path.Join(publishInfo.NFSMountpoint, publishInfo.SubvolumeName)] = struct{}{}
+		}
+
+		_, mounted := publishedVolumes[volumeID]
+		publishedPaths := make([]string, 0, len(trackingInfo.PublishedPaths))
+		for publishedPath := range trackingInfo.PublishedPaths {
+			publishedPaths = append(publishedPaths, publishedPath)
+			if _, ok := mountPoints[publishedPath]; ok {
+				mounted = true
+			}
+		}
+		sort.Strings(publishedPaths)
+
+		// A volume is gone from the node once kubelet has removed its staging path, none of its publications are
+		// mounted and its protocol-specific resources are gone.  Ephemeral volumes are left to the node plugin, which
+		// must also delete them from their backend.
+		if !trackingInfo.Ephemeral && !mounted && !nodeCheckPathExists(trackingInfo.StagingTargetPath) {
+			volumePresent := false
+			switch protocol {
+			case config.Block:
+				_, sessionExists := iSCSITargets[publishInfo.IscsiTargetIQN]
+				volumePresent = sessionExists || (publishInfo.DevicePath != "" &&
+					nodeCheckPathExists(publishInfo.DevicePath))
+			case config.BlockOnFile:
+				_, volumePresent = loopBackFiles[path.Join(publishInfo.NFSMountpoint, publishInfo.SubvolumeName)]
+			}
+
+			if !volumePresent {
+				items = append(items, nodeCheckItem{
+					finding: utils.NodeCheckFinding{
+						Type:     utils.NodeCheckDanglingTrackingFile,
+						VolumeID: volumeID,
+						Resource: volumeID + ".json",
+						Message:  "the volume is no longer staged, published or attached on this node",
+						Repair:   "delete the tracking file",
+					},
+					repair: func(ctx context.Context) error {
+						return p.nodeHelper.DeleteTrackingInfo(ctx, volumeID)
+					},
+				})
+				continue
+			}
+		}
+
+		for _, publishedPath := range publishedPaths {
+			publishedPath := publishedPath
+			if _, ok := mountPoints[publishedPath]; ok {
+				continue
+			}
+			items = append(items, nodeCheckItem{
+				finding: utils.NodeCheckFinding{
+					Type:     utils.NodeCheckDanglingPublishedPath,
+					VolumeID: volumeID,
+					Resource: publishedPath,
+					Message:  "the path is in the volume's tracking file but is not mounted",
+					Repair:   "remove the path from the tracking file",
+				},
+				repair: func(ctx context.Context) error {
+					return p.nodeHelper.RemovePublishedPath(ctx, volumeID, publishedPath)
+				},
+			})
+		}
+
+		if protocol == config.Block && state.ISCSIAvailable && mounted {
+			if _, ok := iSCSITargets[publishInfo.IscsiTargetIQN]; !ok {
+				items = append(items, nodeCheckItem{
+					finding: utils.NodeCheckFinding{
+						Type:     utils.NodeCheckMissingISCSISession,
+						VolumeID: volumeID,
+						Resource: publishInfo.IscsiTargetIQN,
+						Message: "the volume is mounted but there is no iSCSI session to its target; " +
+							"iSCSI self-healing logs in to the target again",
+					},
+				})
+			}
+		}
+	}
+
+	// Resources that belong to no tracked volume can only be identified if every tracking file could be read.
+	if len(invalidVolumeIDs) > 0 {
+		Logc(ctx).WithField("invalidTrackingFiles", invalidVolumeIDs).Warning(
+			"Skipped checking for orphaned iSCSI sessions, multipath maps and loop devices.")
+		return items
+	}
+
+	// Loop devices
+	for _, loopDevice := range state.LoopDevices {
+		loopDevice := loopDevice
+		if _, ok := trackedLoopFiles[loopDevice.BackFile]; ok {
+			continue
+		}
+		if _, ok := mountSources[loopDevice.Name]; ok {
+			continue
+		}
+		if !isOnNFSMount(loopDevice.BackFile, state.Mounts) {
+			continue
+		}
+		items = append(items, nodeCheckItem{
+			finding: utils.NodeCheckFinding{
+				Type:     utils.NodeCheckOrphanedLoopDevice,
+				Resource: loopDevice.Name,
+				Message:  fmt.Sprintf("the loop device for %s is not used by any tracked volume", loopDevice.BackFile),
+				Repair:   "detach the loop device",
+			},
+			repair: func(ctx context.Context) error {
+				return utils.DetachLoopDevice(ctx, loopDevice.Name)
+			},
+		})
+	}
+
+	// Multipath maps
+	orphanedMaps := make(map[string]struct{})
+	for _, multipathMap := range state.MultipathMaps {
+		multipathMap := multipathMap
+		if _, ok := trackedDevices[multipathMap.Device]; ok {
+			continue
+		}
+		if _, ok := trackedDevices[multipathMap.Name]; ok {
+			continue
+		}
+		if len(multipathMap.Holders) > 0 {
+			continue
+		}
+		if _, ok := mountSources["/dev/"+multipathMap.Device]; ok {
+			continue
+		}
+		if _, ok := mountSources["/dev/mapper/"+multipathMap.Name]; ok {
+			continue
+		}
+		knownTargetsOnly := true
+		for _, device := range multipathMap.Devices {
+			sid, ok := state.ISCSIDeviceSessions[device]
+			if !ok {
+				knownTargetsOnly = false
+				break
+			}
+			if _, ok = knownSessions[sid]; !ok {
+				knownTargetsOnly = false
+				break
+			}
+		}
+		if !knownTargetsOnly {
+			continue
+		}
+
+		orphanedMaps[multipathMap.Device] = struct{}{}
+		items = append(items, nodeCheckItem{
+			finding: utils.NodeCheckFinding{
+				Type:     utils.NodeCheckOrphanedMultipathMap,
+				Resource: multipathMap.Device,
+				Message:  fmt.Sprintf("the multipath map %s is not used by any tracked volume", multipathMap.Name),
+				Repair:   "flush the multipath map",
+			},
+			repair: func(ctx context.Context) error {
+				return utils.FlushMultipathMap(ctx, "/dev/"+multipathMap.Device)
+			},
+		})
+	}
+
+	// iSCSI sessions
+	if !state.ISCSIAvailable {
+		return items
+	}
+
+	sessionsInUse := make(map[string]struct{})
+	for _, multipathMap := range state.MultipathMaps {
+		if _, ok := orphanedMaps[multipathMap.Device]; ok {
+			continue
+		}
+		for _, device := range multipathMap.Devices {
+			if sid, ok := state.ISCSIDeviceSessions[device]; ok {
+				sessionsInUse[sid] = struct{}{}
+			}
+		}
+	}
+	for device, sid := range state.ISCSIDeviceSessions {
+		if _, ok := mountSources["/dev/"+device]; ok {
+			sessionsInUse[sid] = struct{}{}
+		}
+	}
+
+	for _, session := range state.ISCSISessions {
+		session := session
+		if _, ok := knownSessions[session.SID]; !ok {
+			continue
+		}
+		if _, ok := trackedIQNs[session.TargetName]; ok {
+			continue
+		}
+		if _, ok := sessionsInUse[session.SID]; ok {
+			continue
+		}
+		portal := strings.Split(session.Portal, ",")[0]
+		items = append(items, nodeCheckItem{
+			finding: utils.NodeCheckFinding{
+				Type:     utils.NodeCheckOrphanedISCSISession,
+				Resource: fmt.Sprintf("%s (%s)", session.TargetName, portal),
+				Message:  "the iSCSI session is not used by any tracked volume",
+				Repair:   "log out of the iSCSI session",
+			},
+			repair: func(ctx context.Context) error {
+				return utils.ISCSILogout(ctx, session.TargetName, portal)
+			},
+		})
+	}
+
+	return items
+}
+
+// nodeCheckPathExists returns whether a path exists, treating an empty path as missing.
+func nodeCheckPathExists(pathToCheck string) bool {
+	if pathToCheck == "" {
+		return false
+	}
+	_, err := osFs.Stat(pathToCheck)
+	return err == nil
+}
+
+// isOnNFSMount returns whether a file is on an NFS mount.
+func isOnNFSMount(file string, mounts []utils.MountInfo) bool {
+	for _, mount := range mounts {
+		if strings.HasPrefix(mount.FsType, "nfs") && strings.HasPrefix(file, mount.MountPoint+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package csi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+
+	mockNodeHelpers "github.com/netapp/trident/mocks/mock_frontend/mock_csi/mock_node_helpers"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	testPodVolumesPath = "/var/lib/kubelet/pods/pod-1/volumes/kubernetes.io~csi"
+	testIQN            = "iqn.1992-08.com.netapp:sn.afbb1784f77411e582f8080027e22798:vs.3"
+	testOtherIQN       = "iqn.1992-08.com.netapp:sn.afbb1784f77411e582f8080027e22798:vs.4"
+)
+
+func nodeCheckFindingTypes(items []nodeCheckItem) []utils.NodeCheckFindingType {
+	types := make([]utils.NodeCheckFindingType, 0, len(items))
+	for _, item := range items {
+		types = append(types, item.finding.Type)
+	}
+	return types
+}
+
+func TestFindTridentPublications(t *testing.T) {
+	defer func() { osFs = afero.NewOsFs() }()
+	osFs = afero.NewMemMapFs()
+
+	blockDevicesPath := "/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices"
+	assert.NoError(t, afero.WriteFile(osFs, testPodVolumesPath+"/pvc-1/vol_data.json",
+		[]byte(`{"driverName":"csi.trident.netapp.io","volumeHandle":"pvc-1"}`), 0o600))
+	assert.NoError(t, afero.WriteFile(osFs, testPodVolumesPath+"/pvc-2/vol_data.json",
+		[]byte(`{"driverName":"ebs.csi.aws.com","volumeHandle":"vol-2"}`), 0o600))
+	assert.NoError(t, afero.WriteFile(osFs, blockDevicesPath+"/pvc-3/data/vol_data.json",
+		[]byte(`{"driverName":"csi.trident.netapp.io","volumeHandle":"pvc-3"}`), 0o600))
+
+	mounts := []utils.MountInfo{
+		{MountPoint: "/"},
+		{MountPoint: testPodVolumesPath + "/pvc-1/mount"},
+		{MountPoint: testPodVolumesPath + "/pvc-2/mount"},
+		{MountPoint: blockDevicesPath + "/publish/pvc-3/pod-1"},
+		{MountPoint: testPodVolumesPath + "/pvc-4/mount"},
+	}
+
+	assert.Equal(t, map[string]string{
+		testPodVolumesPath + "/pvc-1/mount":       "pvc-1",
+		blockDevicesPath + "/publish/pvc-3/pod-1": "pvc-3",
+	}, findTridentPublications(context.Background(), mounts))
+}
+
+func TestFindNodeInconsistencies_Publications(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockHelper := mockNodeHelpers.NewMockNodeHelper(mockCtrl)
+	plugin := &Plugin{nodeName: "node1", role: CSINode, nodeHelper: mockHelper}
+
+	mountPath1 := testPodVolumesPath + "/pvc-1/mount"
+	mountPath2 := testPodVolumesPath + "/pvc-2/mount"
+	danglingPath := "/var/lib/kubelet/pods/pod-2/volumes/kubernetes.io~csi/pvc-2/mount"
+
+	trackingInfo := &utils.VolumeTrackingInfo{PublishedPaths: map[string]struct{}{danglingPath: {}}}
+	trackingInfo.NfsServerIP = "10.0.0.1"
+	state := &utils.NodeState{
+		Mounts: []utils.MountInfo{
+			{MountPoint: mountPath1, MountSource: "10.0.0.1:/pvc_1", FsType: "nfs4"},
+			{MountPoint: mountPath2, MountSource: "10.0.0.1:/pvc_2", FsType: "nfs4"},
+		},
+	}
+	publications := map[string]string{mountPath1: "pvc-1", mountPath2: "pvc-2"}
+
+	items := plugin.findNodeInconsistencies(ctx, map[string]*utils.VolumeTrackingInfo{"pvc-2": trackingInfo},
+		[]string{}, state, publications, map[string]struct{}{})
+
+	assert.Equal(t, []utils.NodeCheckFindingType{
+		utils.NodeCheckOrphanedMount,
+		utils.NodeCheckUntrackedPublishedPath,
+		utils.NodeCheckDanglingPublishedPath,
+	}, nodeCheckFindingTypes(items))
+	assert.Equal(t, mountPath1, items[0].finding.Resource)
+	assert.Equal(t, danglingPath, items[2].finding.Resource)
+
+	// Repairs update the tracking files
+	mockHelper.EXPECT().AddPublishedPath(ctx, "pvc-2", mountPath2).Return(nil)
+	mockHelper.EXPECT().RemovePublishedPath(ctx, "pvc-2", danglingPath).Return(nil)
+	assert.NoError(t, items[1].repair(ctx))
+	assert.NoError(t, items[2].repair(ctx))
+}
+
+func TestFindNodeInconsistencies_DanglingTrackingFiles(t *testing.T) {
+	defer func() { osFs = afero.NewOsFs() }()
+	osFs = afero.NewMemMapFs()
+
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockHelper := mockNodeHelpers.NewMockNodeHelper(mockCtrl)
+	plugin := &Plugin{nodeName: "node1", role: CSINode, nodeHelper: mockHelper}
+
+	gonePath := testPodVolumesPath + "/pvc-1/mount"
+
+	// An NFS volume whose pods are gone
+	nfsInfo := &utils.VolumeTrackingInfo{PublishedPaths: map[string]struct{}{gonePath: {}}}
+	nfsInfo.NfsServerIP = "10.0.0.1"
+
+	// An iSCSI volume that is still logged in
+	attachedInfo := &utils.VolumeTrackingInfo{
+		StagingTargetPath: "/var/lib/kubelet/plugins/kubernetes.io/csi/pv/pvc-2/globalmount",
+		PublishedPaths:    map[string]struct{}{},
+	}
+	attachedInfo.IscsiTargetIQN = testIQN
+
+	// An iSCSI volume whose target is logged out and whose device is gone
+	detachedInfo := &utils.VolumeTrackingInfo{PublishedPaths: map[string]struct{}{}}
+	detachedInfo.IscsiTargetIQN = testOtherIQN
+	detachedInfo.DevicePath = "/dev/dm-9"
+
+	// An ephemeral volume, which the node plugin deletes itself
+	ephemeralInfo := &utils.VolumeTrackingInfo{PublishedPaths: map[string]struct{}{}, Ephemeral: true}
+	ephemeralInfo.NfsServerIP = "10.0.0.1"
+
+	trackingInfos := map[string]*utils.VolumeTrackingInfo{
+		"pvc-1": nfsInfo,
+		"pvc-2": attachedInfo,
+		"pvc-3": detachedInfo,
+		"csi-4": ephemeralInfo,
+	}
+	state := &utils.NodeState{
+		ISCSIAvailable: true,
+		ISCSISessions:  []utils.ISCSISessionInfo{{SID: "1", Portal: "10.0.0.2:3260,1028", TargetName: testIQN}},
+	}
+
+	items := plugin.findNodeInconsistencies(ctx, trackingInfos, []string{}, state, map[string]string{},
+		map[string]struct{}{testIQN: {}})
+
+	assert.Equal(t, []utils.NodeCheckFindingType{
+		utils.NodeCheckDanglingTrackingFile,
+		utils.NodeCheckDanglingTrackingFile,
+	}, nodeCheckFindingTypes(items))
+	assert.Equal(t, "pvc-1", items[0].finding.VolumeID)
+	assert.Equal(t, "pvc-3", items[1].finding.VolumeID)
+
+	mockHelper.EXPECT().DeleteTrackingInfo(ctx, "pvc-1").Return(nil)
+	assert.NoError(t, items[0].repair(ctx))
+}
+
+func TestFindNodeInconsistencies_InvalidTrackingFiles(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockHelper := mockNodeHelpers.NewMockNodeHelper(mockCtrl)
+	plugin := &Plugin{nodeName: "node1", role: CSINode, nodeHelper: mockHelper}
+
+	state := &utils.NodeState{
+		ISCSIAvailable: true,
+		ISCSISessions:  []utils.ISCSISessionInfo{{SID: "1", Portal: "10.0.0.2:3260,1028", TargetName: testIQN}},
+	}
+
+	// The iSCSI session may belong to the volume whose tracking file is unreadable, so it is not reported
+	items := plugin.findNodeInconsistencies(ctx, map[string]*utils.VolumeTrackingInfo{}, []string{"pvc-1"}, state,
+		map[string]string{testPodVolumesPath + "/pvc-1/mount": "pvc-1"}, map[string]struct{}{testIQN: {}})
+
+	assert.Equal(t, []utils.NodeCheckFindingType{utils.NodeCheckInvalidTrackingFile}, nodeCheckFindingTypes(items))
+
+	mockHelper.EXPECT().DeleteTrackingInfo(ctx, "pvc-1").Return(nil)
+	assert.NoError(t, items[0].repair(ctx))
+}
+
+func TestFindNodeInconsistencies_OrphanedResources(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockHelper := mockNodeHelpers.NewMockNodeHelper(mockCtrl)
+	plugin := &Plugin{nodeName: "node1", role: CSINode, nodeHelper: mockHelper}
+
+	mountPath := testPodVolumesPath + "/pvc-1/mount"
+
+	iSCSIInfo := &utils.VolumeTrackingInfo{PublishedPaths: map[string]struct{}{mountPath: {}}}
+	iSCSIInfo.IscsiTargetIQN = testIQN
+	iSCSIInfo.DevicePath = "/dev/dm-0"
+
+	bofInfo := &utils.VolumeTrackingInfo{PublishedPaths: map[string]struct{}{}}
+	bofInfo.NfsServerIP = "10.0.0.1"
+	bofInfo.NFSMountpoint = "/tmp/bof"
+	bofInfo.SubvolumeName = "subvol-1"
+
+	state := &utils.NodeState{
+		Mounts: []utils.MountInfo{
+			{MountPoint: mountPath, MountSource: "/dev/dm-0", FsType: "ext4"},
+			{MountPoint: "/tmp/bof", MountSource: "10.0.0.1:/bof", FsType: "nfs4"},
+			{MountPoint: "/mnt/local", MountSource: "/dev/sda1", FsType: "xfs"},
+		},
+		ISCSIAvailable: true,
+		ISCSISessions: []utils.ISCSISessionInfo{
+			{SID: "1", Portal: "10.0.0.2:3260,1028", TargetName: testIQN},
+			{SID: "2", Portal: "10.0.0.3:3260,1029", TargetName: testOtherIQN},
+		},
+		ISCSIDeviceSessions: map[string]string{"sdb": "1", "sdc": "2", "sdd": "2"},
+		MultipathMaps: []utils.MultipathMap{
+			{Device: "dm-0", Name: "3600a0980", Devices: []string{"sdb"}, Holders: []string{}},
+			{Device: "dm-1", Name: "3600a0981", Devices: []string{"sdc", "sdd"}, Holders: []string{}},
+			{Device: "dm-2", Name: "3600a0982", Devices: []string{"sda"}, Holders: []string{}},
+		},
+		LoopDevices: []utils.LoopDevice{
+			{Name: "/dev/loop0", BackFile: "/tmp/bof/subvol-1"},
+			{Name: "/dev/loop1", BackFile: "/tmp/bof/subvol-2"},
+			{Name: "/dev/loop2", BackFile: "/mnt/local/image"},
+		},
+	}
+
+	trackingInfos := map[string]*utils.VolumeTrackingInfo{"pvc-1": iSCSIInfo, "pvc-2": bofInfo}
+	knownTargets := map[string]struct{}{testIQN: {}, testOtherIQN: {}}
+	items := plugin.findNodeInconsistencies(ctx, trackingInfos, []string{}, state,
+		map[string]string{mountPath: "pvc-1"}, knownTargets)
+
+	assert.Equal(t, []utils.NodeCheckFindingType{
+		utils.NodeCheckOrphanedLoopDevice,
+		utils.NodeCheckOrphanedMultipathMap,
+		utils.NodeCheckOrphanedISCSISession,
+	}, nodeCheckFindingTypes(items))
+	assert.Equal(t, "/dev/loop1", items[0].finding.Resource)
+	assert.Equal(t, "dm-1", items[1].finding.Resource)
+	assert.Equal(t, testOtherIQN+" (10.0.0.3:3260)", items[2].finding.Resource)
+
+	// Sessions to targets Trident does not know, and maps over them, belong to someone else
+	items = plugin.findNodeInconsistencies(ctx, trackingInfos, []string{}, state,
+		map[string]string{mountPath: "pvc-1"}, map[string]struct{}{testIQN: {}})
+
+	assert.Equal(t, []utils.NodeCheckFindingType{utils.NodeCheckOrphanedLoopDevice}, nodeCheckFindingTypes(items))
+
+	// A mounted iSCSI volume without a session is reported, but cannot be repaired here
+	state.ISCSISessions = state.ISCSISessions[1:]
+	items = plugin.findNodeInconsistencies(ctx, trackingInfos, []string{}, state,
+		map[string]string{mountPath: "pvc-1"}, knownTargets)
+
+	assert.Equal(t, utils.NodeCheckMissingISCSISession, items[0].finding.Type)
+	assert.Nil(t, items[0].repair)
+}
@@ -20,6 +20,7 @@ import (
 
 	tridentconfig "github.com/netapp/trident/config"
 	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
 
@@ -29,6 +30,9 @@ const (
 	AttachISCSIVolumeTimeoutShort = 20 * time.Second
 	iSCSINodeUnstageMaxDuration   = 15 * time.Second
 	iSCSISelfHealingLockContext   = "ISCSISelfHealingThread"
+
+	// ephemeralStagingDir is created beside the target path of an ephemeral volume and used as its staging path
+	ephemeralStagingDir = "trident-staging"
 )
 
 var (
@@ -163,6 +167,17 @@ func (p *Plugin) NodePublishVolume(
 	Logc(ctx).WithFields(fields).Debug(">>>> NodePublishVolume")
 	defer Logc(ctx).WithFields(fields).Debug("<<<< NodePublishVolume")
 
+	if req.GetVolumeContext()[EphemeralVolumeContextKey] == "true" {
+		var err error
+		if req, err = p.nodeStageEphemeralVolume(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.ensureSinglePodPublication(ctx, req); err != nil {
+		return nil, err
+	}
+
 	switch req.PublishContext["protocol"] {
 	case string(tridentconfig.File):
 		trackingInfo, err := p.nodeHelper.ReadTrackingInfo(ctx, req.VolumeId)
@@ -187,6 +202,272 @@ func (p *Plugin) NodePublishVolume(
 	}
 }
 
+// ensureSinglePodPublication refuses to publish a volume with the SINGLE_NODE_SINGLE_WRITER access mode to a
+// target path if it is already published to a different one, as that would let a second pod use it.
+func (p *Plugin) ensureSinglePodPublication(ctx context.Context, req *csi.NodePublishVolumeRequest) error {
+	if req.GetVolumeCapability().GetAccessMode().GetMode() != csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER {
+		return nil
+	}
+
+	trackingInfo, err := p.nodeHelper.ReadTrackingInfo(ctx, req.VolumeId)
+	if err != nil {
+		// The protocol-specific publish reports a missing or unreadable tracking file
+		return nil
+	}
+
+	for publishedPath := range trackingInfo.PublishedPaths {
+		if publishedPath != req.GetTargetPath() {
+			Logc(ctx).WithFields(log.Fields{
+				"volumeID":      req.VolumeId,
+				"targetPath":    req.GetTargetPath(),
+				"publishedPath": publishedPath,
+			}).Error("Single writer volume is already published to another target path.")
+			return status.Errorf(codes.FailedPrecondition,
+				"volume %s may only be published to one pod and is already published to %s", req.VolumeId,
+				publishedPath)
+		}
+	}
+
+	return nil
+}
+
+// nodeStageEphemeralVolume provisions a CSI ephemeral volume through the Trident controller, publishes it to this
+// node and stages it, as no CreateVolume, ControllerPublishVolume or NodeStageVolume calls are made for ephemeral
+// volumes.  It returns a copy of the publish request that carries the publish context, volume context and staging
+// path of the volume, so it may be published like any other.
+func (p *Plugin) nodeStageEphemeralVolume(
+	ctx context.Context, req *csi.NodePublishVolumeRequest,
+) (*csi.NodePublishVolumeRequest, error) {
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "no volume ID provided")
+	}
+	if req.GetTargetPath() == "" {
+		return nil, status.Error(codes.InvalidArgument, "no target path provided")
+	}
+	if req.GetVolumeCapability() == nil {
+		return nil, status.Error(codes.InvalidArgument, "no volume capability provided")
+	}
+
+	fields := log.Fields{"volumeID": volumeID, "targetPath": req.GetTargetPath()}
+	Logc(ctx).WithFields(fields).Debug(">>>> nodeStageEphemeralVolume")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< nodeStageEphemeralVolume")
+
+	volume, err := p.restClient.GetVolume(ctx, volumeID)
+	if utils.IsNotFoundError(err) {
+		volConfig, configErr := p.getEphemeralVolumeConfig(req)
+		if configErr != nil {
+			return nil, configErr
+		}
+		if err = p.restClient.CreateVolume(ctx, volConfig); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		Logc(ctx).WithFields(fields).Info("Created ephemeral volume.")
+		volume, err = p.restClient.GetVolume(ctx, volumeID)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	volumePublishInfo := &utils.VolumePublishInfo{}
+	volumePublishInfo.ReadOnly = req.GetReadonly()
+	volumePublishInfo.AccessMode = int32(req.GetVolumeCapability().GetAccessMode().GetMode())
+	if volumePublishInfo, err = p.restClient.PublishVolume(ctx, volumeID, p.nodeName, volumePublishInfo); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	applyPublishMountFlags(volumePublishInfo, volume.Config.Protocol, req.GetVolumeCapability().GetMount())
+
+	publishContext, err := p.getPublishContext(ctx, volume.Config.Protocol, volumePublishInfo)
+	if err != nil {
+		return nil, err
+	}
+	csiVolume, err := p.getCSIVolumeFromTridentVolume(ctx, volume)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	stagingTargetPath := path.Join(path.Dir(req.GetTargetPath()), ephemeralStagingDir)
+
+	// A retried publish finds the volume already staged, and staging it again would forget its published paths
+	trackingInfo, err := p.nodeHelper.ReadTrackingInfo(ctx, volumeID)
+	if err != nil || !trackingInfo.Ephemeral {
+		if err = os.MkdirAll(stagingTargetPath, 0o750); err != nil {
+			return nil, status.Errorf(codes.Internal, "could not create staging path %s; %v", stagingTargetPath, err)
+		}
+
+		stageReq := &csi.NodeStageVolumeRequest{
+			VolumeId:          volumeID,
+			PublishContext:    publishContext,
+			StagingTargetPath: stagingTargetPath,
+			VolumeCapability:  req.GetVolumeCapability(),
+			Secrets:           req.GetSecrets(),
+			VolumeContext:     csiVolume.VolumeContext,
+		}
+		if _, err = p.NodeStageVolume(ctx, stageReq); err != nil {
+			return nil, err
+		}
+
+		if trackingInfo, err = p.nodeHelper.ReadTrackingInfo(ctx, volumeID); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		trackingInfo.Ephemeral = true
+		if err = p.nodeHelper.WriteTrackingInfo(ctx, volumeID, trackingInfo); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	return &csi.NodePublishVolumeRequest{
+		VolumeId:          volumeID,
+		PublishContext:    publishContext,
+		StagingTargetPath: trackingInfo.StagingTargetPath,
+		TargetPath:        req.GetTargetPath(),
+		VolumeCapability:  req.GetVolumeCapability(),
+		Readonly:          req.GetReadonly(),
+		Secrets:           req.GetSecrets(),
+		VolumeContext:     csiVolume.VolumeContext,
+	}, nil
+}
+
+// getEphemeralVolumeConfig builds the config of a CSI ephemeral volume from its volume attributes.  The storage
+// class attribute is required, and it selects the backends the volume may be placed on.
+func (p *Plugin) getEphemeralVolumeConfig(req *csi.NodePublishVolumeRequest) (*storage.VolumeConfig, error) {
+	attributes := req.GetVolumeContext()
+
+	storageClass := attributes[EphemeralStorageClassAttribute]
+	if storageClass == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "ephemeral volumes require the %s volume attribute",
+			EphemeralStorageClassAttribute)
+	}
+
+	size := attributes[EphemeralSizeAttribute]
+	if size == "" {
+		size = DefaultEphemeralVolumeSize
+	}
+	sizeBytes, err := utils.ConvertSizeToBytes(size)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid ephemeral volume size %s; %v", size, err)
+	}
+
+	capability := req.GetVolumeCapability()
+	accessMode := capability.GetAccessMode().GetMode()
+	volumeMode := tridentconfig.Filesystem
+	fsType := attributes[EphemeralFsTypeAttribute]
+	if capability.GetBlock() != nil {
+		volumeMode = tridentconfig.RawBlock
+		fsType = tridentconfig.FsRaw
+	} else if fsType == "" {
+		fsType = capability.GetMount().GetFsType()
+	}
+
+	return &storage.VolumeConfig{
+		Version:      tridentconfig.OrchestratorAPIVersion,
+		Name:         req.GetVolumeId(),
+		Size:         sizeBytes,
+		Protocol:     p.getProtocolForCSIAccessMode(accessMode, volumeMode),
+		StorageClass: storageClass,
+		AccessMode:   p.getAccessForCSIAccessMode(accessMode),
+		VolumeMode:   volumeMode,
+		FileSystem:   fsType,
+	}, nil
+}
+
+// deleteEphemeralVolume tears down a CSI ephemeral volume once it is no longer published on this node.
+func (p *Plugin) deleteEphemeralVolume(ctx context.Context, volumeID string) error {
+	trackingInfo, err := p.nodeHelper.ReadTrackingInfo(ctx, volumeID)
+	if err != nil {
+		if utils.IsNotFoundError(err) {
+			return nil
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	if !trackingInfo.Ephemeral || len(trackingInfo.PublishedPaths) > 0 {
+		return nil
+	}
+	return p.teardownEphemeralVolume(ctx, volumeID, trackingInfo)
+}
+
+// teardownEphemeralVolume unstages a CSI ephemeral volume, then unpublishes and deletes it through the Trident
+// controller.  If the controller cannot be reached, the tracking file is kept without a staging path, so the volume
+// is deleted by cleanupEphemeralVolumes later without being unstaged again.
+func (p *Plugin) teardownEphemeralVolume(
+	ctx context.Context, volumeID string, trackingInfo *utils.VolumeTrackingInfo,
+) error {
+	fields := log.Fields{"volumeID": volumeID}
+	Logc(ctx).WithFields(fields).Debug(">>>> teardownEphemeralVolume")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< teardownEphemeralVolume")
+
+	if stagingTargetPath := trackingInfo.StagingTargetPath; stagingTargetPath != "" {
+		unstageReq := &csi.NodeUnstageVolumeRequest{VolumeId: volumeID, StagingTargetPath: stagingTargetPath}
+		if _, err := p.nodeUnstageVolume(ctx, unstageReq, false); err != nil {
+			return err
+		}
+		if err := utils.DeleteResourceAtPath(ctx, stagingTargetPath); err != nil {
+			Logc(ctx).WithFields(fields).WithError(err).Warning("Could not remove ephemeral volume staging path.")
+		}
+	}
+
+	err := p.restClient.UnpublishVolume(ctx, volumeID, p.nodeName)
+	if err == nil {
+		err = p.restClient.DeleteVolume(ctx, volumeID)
+	}
+	if err != nil {
+		trackingInfo.StagingTargetPath = ""
+		trackingInfo.PublishedPaths = make(map[string]struct{})
+		if writeErr := p.nodeHelper.WriteTrackingInfo(ctx, volumeID, trackingInfo); writeErr != nil {
+			Logc(ctx).WithFields(fields).WithError(writeErr).Error("Could not record unstaged ephemeral volume.")
+		}
+		return status.Errorf(codes.Internal, "could not delete ephemeral volume %s; %v", volumeID, err)
+	}
+
+	if err = p.nodeHelper.DeleteTrackingInfo(ctx, volumeID); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	Logc(ctx).WithFields(fields).Info("Deleted ephemeral volume.")
+	return nil
+}
+
+// cleanupEphemeralVolumes deletes the CSI ephemeral volumes recorded in this node's tracking files whose target
+// paths are gone, such as those of pods that were deleted while the node plugin was down.
+func (p *Plugin) cleanupEphemeralVolumes(ctx context.Context) {
+	files, err := p.nodeHelper.GetVolumeTrackingFiles()
+	if err != nil {
+		Logc(ctx).WithError(err).Error("Could not list volume tracking files.")
+		return
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") || strings.HasPrefix(file.Name(), "tmp-") {
+			continue
+		}
+		volumeID := strings.TrimSuffix(file.Name(), ".json")
+		p.cleanupEphemeralVolume(ctx, volumeID)
+	}
+}
+
+func (p *Plugin) cleanupEphemeralVolume(ctx context.Context, volumeID string) {
+	lockContext := "NodePublishVolume-" + volumeID
+	utils.Lock(ctx, lockContext, lockID)
+	defer utils.Unlock(ctx, lockContext, lockID)
+
+	trackingInfo, err := p.nodeHelper.ReadTrackingInfo(ctx, volumeID)
+	if err != nil || !trackingInfo.Ephemeral {
+		return
+	}
+
+	for publishedPath := range trackingInfo.PublishedPaths {
+		if _, err = os.Stat(publishedPath); err == nil {
+			// Kubelet unpublishes volumes of pods it still knows about
+			return
+		}
+	}
+
+	Logc(ctx).WithField("volumeID", volumeID).Info("Deleting orphaned ephemeral volume.")
+	if err = p.teardownEphemeralVolume(ctx, volumeID, trackingInfo); err != nil {
+		Logc(ctx).WithField("volumeID", volumeID).WithError(err).Error("Could not delete orphaned ephemeral volume.")
+	}
+}
+
 func (p *Plugin) NodeUnpublishVolume(
 	ctx context.Context, req *csi.NodeUnpublishVolumeRequest,
 ) (*csi.NodeUnpublishVolumeResponse, error) {
@@ -267,6 +548,10 @@ func (p *Plugin) NodeUnpublishVolume(
 		return nil, status.Errorf(codes.Internal, fmtStr, targetPath, req.VolumeId, err)
 	}
 
+	if err = p.deleteEphemeralVolume(ctx, req.VolumeId); err != nil {
+		return nil, err
+	}
+
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
 
@@ -440,13 +725,17 @@ func (p *Plugin) nodeExpandVolume(
 		Logc(ctx).WithField("volumeId", volumeId).Info("Resizing the LUKS mapping.")
 		// Refresh the luks device
 		// cryptsetup resize <luks-device-path> << <passphrase>
+		secrets, err := resolveLUKSSecrets(ctx, p.restClient, volumeId, secrets)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
 		passphrase, ok := secrets["luks-passphrase"]
 		if !ok {
 			return status.Error(codes.InvalidArgument, "cannot expand LUKS encrypted volume; no passphrase provided")
 		} else if passphrase == "" {
 			return status.Error(codes.InvalidArgument, "cannot expand LUKS encrypted volume; empty passphrase provided")
 		}
-		err := utils.ResizeLUKSDevice(ctx, publishInfo.DevicePath, passphrase)
+		err = utils.ResizeLUKSDevice(ctx, publishInfo.DevicePath, passphrase)
 		if err != nil {
 			if utils.IsIncorrectLUKSPassphraseError(err) {
 				return status.Error(codes.InvalidArgument, err.Error())
@@ -526,25 +815,29 @@ func nodePrepareBlockOnFileVolumeForExpansion(
 	nfsMountpoint := publishInfo.NFSMountpoint
 	loopFile := path.Join(nfsMountpoint, publishInfo.SubvolumeName)
 
-	var err error = nil
+	// For LUKS volumes the device path is the LUKS mapping, so find the loop device beneath it
+	loopDevicePath, err := utils.GetBlockOnFileLoopDevicePath(ctx, publishInfo)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
 
-	loopDeviceAttached, err := utils.IsLoopDeviceAttachedToFile(ctx, publishInfo.DevicePath, loopFile)
+	loopDeviceAttached, err := utils.IsLoopDeviceAttachedToFile(ctx, loopDevicePath, loopFile)
 	if err != nil {
 		return fmt.Errorf("unable to identify if loop device '%s' is attached to file '%s': %v",
-			publishInfo.DevicePath, loopFile, err)
+			loopDevicePath, loopFile, err)
 	}
 
 	// Make sure device is ready.
 	if loopDeviceAttached {
-		if err = utils.ResizeLoopDevice(ctx, publishInfo.DevicePath, loopFile, requiredBytes); err != nil {
-			Logc(ctx).WithField("device", publishInfo.DevicePath).WithError(err).Error(
+		if err = utils.ResizeLoopDevice(ctx, loopDevicePath, loopFile, requiredBytes); err != nil {
+			Logc(ctx).WithField("device", loopDevicePath).WithError(err).Error(
 				"Unable to resize loop device.")
 			err = status.Error(codes.Internal, err.Error())
 		}
 	} else {
-		Logc(ctx).WithField("devicePath", publishInfo.DevicePath).Error(
+		Logc(ctx).WithField("devicePath", loopDevicePath).Error(
 			"Unable to expand volume as device is not attached.")
-		err = fmt.Errorf("device %s to expand is not attached", publishInfo.DevicePath)
+		err = fmt.Errorf("device %s to expand is not attached", loopDevicePath)
 		return status.Error(codes.Internal, err.Error())
 	}
 	return err
@@ -635,6 +928,16 @@ func (p *Plugin) nodeGetInfo(ctx context.Context) *utils.Node {
 	}
 	if iscsiActive {
 		services = append(services, "iSCSI")
+
+		// Misconfigured multipathing otherwise only surfaces as iSCSI staging timeouts.
+		if p.manageMultipathConfig {
+			if dropInPath, err := utils.EnsureMultipathDropIn(ctx); err != nil {
+				Logc(ctx).WithError(err).Error("Could not write multipath drop-in configuration.")
+			} else {
+				Logc(ctx).WithField("path", dropInPath).Debug("Multipath drop-in configuration is current.")
+			}
+		}
+		p.hostInfo.Multipath = utils.ValidateMultipathConfig(ctx)
 	}
 	p.hostInfo.Services = services
 
@@ -645,6 +948,7 @@ func (p *Plugin) nodeGetInfo(ctx context.Context) *utils.Node {
 		IPs:      ips,
 		NodePrep: nil,
 		HostInfo: p.hostInfo,
+		RESTPort: p.nodeRESTPort,
 		Deleted:  false,
 	}
 	return node
@@ -689,6 +993,67 @@ func (p *Plugin) nodeRegisterWithController(ctx context.Context, timeout time.Du
 	}
 
 	p.nodeIsRegistered = true
+
+	// Ephemeral volumes are deleted through the controller, so orphans may only be cleaned up once it is reachable
+	p.cleanupEphemeralVolumes(ctx)
+}
+
+// RotateLUKSPassphrase rotates the passphrase of a staged LUKS volume in place, using the current and previous
+// passphrases found in the supplied secrets.  The Trident controller calls this when the node-stage secret of a
+// volume changes, so the rotation does not have to wait until the volume is staged again.
+func (p *Plugin) RotateLUKSPassphrase(
+	ctx context.Context, volumeID, internalName string, secrets map[string]string,
+) error {
+	Logc(ctx).WithField("volumeID", volumeID).Debug(">>>> RotateLUKSPassphrase")
+	defer Logc(ctx).WithField("volumeID", volumeID).Debug("<<<< RotateLUKSPassphrase")
+
+	if p.role != CSINode {
+		return utils.UnsupportedError("LUKS passphrase rotation is only supported by the node plugin")
+	}
+
+	trackingInfo, err := p.nodeHelper.ReadTrackingInfo(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+	publishInfo := &trackingInfo.VolumePublishInfo
+
+	isLUKS, err := strconv.ParseBool(publishInfo.LUKSEncryption)
+	if err != nil || !isLUKS {
+		return utils.InvalidInputError(fmt.Sprintf("volume %s is not LUKS encrypted", volumeID))
+	}
+
+	luksDevice, err := utils.NewLUKSDeviceFromMappingPath(ctx, publishInfo.DevicePath, internalName)
+	if err != nil {
+		return err
+	}
+
+	// The controller only requests rotation when the recorded passphrase names are stale, so always report them
+	// even if the device is already on the current passphrase
+	return ensureLUKSVolumePassphrase(ctx, p.restClient, luksDevice, volumeID, secrets, true)
+}
+
+// PreemptReservationKeys preempts the SCSI persistent reservation keys of nodes that were force detached from a
+// staged multi-attach block volume.  The Trident controller calls this on a node still sharing the volume when
+// another node is force detached, so the detached node cannot keep writing to it while the volume stays staged here.
+func (p *Plugin) PreemptReservationKeys(ctx context.Context, volumeID string, reservationKeys []string) error {
+	Logc(ctx).WithField("volumeID", volumeID).Debug(">>>> PreemptReservationKeys")
+	defer Logc(ctx).WithField("volumeID", volumeID).Debug("<<<< PreemptReservationKeys")
+
+	if p.role != CSINode {
+		return utils.UnsupportedError("reservation preemption is only supported by the node plugin")
+	}
+
+	trackingInfo, err := p.nodeHelper.ReadTrackingInfo(ctx, volumeID)
+	if err != nil {
+		return err
+	}
+	publishInfo := &trackingInfo.VolumePublishInfo
+
+	if !publishInfo.SCSIPersistentReservation {
+		return utils.InvalidInputError(fmt.Sprintf("volume %s does not use persistent reservations", volumeID))
+	}
+
+	return utils.PreemptPersistentReservationKeys(ctx, publishInfo, reservationKeys)
 }
 
 func (p *Plugin) nodeStageNFSVolume(
@@ -1033,6 +1398,17 @@ func (p *Plugin) nodeStageISCSIVolume(
 	publishInfo.IscsiInterface = req.PublishContext["iscsiInterface"]
 	publishInfo.IscsiIgroup = req.PublishContext["iscsiIgroup"]
 
+	if req.PublishContext["scsiPersistentReservation"] != "" {
+		if publishInfo.SCSIPersistentReservation, err = strconv.ParseBool(
+			req.PublishContext["scsiPersistentReservation"]); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		publishInfo.ReservationKey = req.PublishContext["reservationKey"]
+		if preemptKeys := req.PublishContext["preemptReservationKeys"]; preemptKeys != "" {
+			publishInfo.PreemptReservationKeys = strings.Split(preemptKeys, ",")
+		}
+	}
+
 	if useCHAP {
 		publishInfo.IscsiUsername = req.PublishContext["iscsiUsername"]
 		publishInfo.IscsiInitiatorSecret = req.PublishContext["iscsiInitiatorSecret"]
@@ -1059,10 +1435,22 @@ func (p *Plugin) nodeStageISCSIVolume(
 		}
 	}
 
+	if isLUKS {
+		if req.Secrets, err = resolveLUKSSecrets(ctx, p.restClient, req.GetVolumeId(), req.GetSecrets()); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
 	if err = p.EnsureAttachISCSIVolume(ctx, req, "", publishInfo, AttachISCSIVolumeTimeoutShort); err != nil {
 		return nil, err
 	}
 
+	if publishInfo.SCSIPersistentReservation {
+		if err = utils.EnsurePersistentReservation(ctx, publishInfo); err != nil {
+			return nil, status.Error(codes.Internal, fmt.Sprintf("failed to reserve volume; %v", err))
+		}
+	}
+
 	volumeId, stagingTargetPath, err := p.getVolumeIdAndStagingPath(req)
 	if err != nil {
 		return nil, err
@@ -1148,6 +1536,17 @@ func (p *Plugin) nodeUnstageISCSIVolume(
 	// Remove Portal/LUN entries in self-healing map.
 	utils.RemoveLUNFromSessions(ctx, publishInfo, &publishedISCSISessions)
 
+	// Stop writing to a shared volume before detaching it.  If this node was force detached, its key has
+	// already been or will be preempted by another node.
+	if publishInfo.SCSIPersistentReservation {
+		if err := utils.ReleasePersistentReservation(ctx, publishInfo); err != nil {
+			if !force && !p.unsafeDetach {
+				return status.Error(codes.Internal, err.Error())
+			}
+			Logc(ctx).WithError(err).Warning("Could not release persistent reservation.")
+		}
+	}
+
 	if publishInfo.LUKSEncryption != "" {
 		isLUKS, err := strconv.ParseBool(publishInfo.LUKSEncryption)
 		if err != nil {
@@ -1291,7 +1690,10 @@ func (p *Plugin) nodePublishISCSIVolume(
 			if err != nil {
 				return nil, status.Error(codes.Internal, err.Error())
 			}
-			err = ensureLUKSVolumePassphrase(ctx, p.restClient, luksDevice, req.GetVolumeId(), req.GetSecrets(), false)
+			secrets, err := resolveLUKSSecrets(ctx, p.restClient, req.GetVolumeId(), req.GetSecrets())
+			if err == nil {
+				err = ensureLUKSVolumePassphrase(ctx, p.restClient, luksDevice, req.GetVolumeId(), secrets, false)
+			}
 			if err != nil {
 				Logc(ctx).WithError(err).Error("Failed to ensure current LUKS passphrase.")
 			}
@@ -1349,6 +1751,21 @@ func (p *Plugin) nodeStageNFSBlockVolume(
 	publishInfo.SubvolumeMountOptions = utils.SanitizeMountOptions(req.PublishContext["subvolumeMountOptions"],
 		[]string{"ro"})
 
+	var isLUKS bool
+	if req.PublishContext["LUKSEncryption"] != "" {
+		isLUKS, err = strconv.ParseBool(req.PublishContext["LUKSEncryption"])
+		if err != nil {
+			return nil, fmt.Errorf("could not parse LUKSEncryption into a bool, got %v",
+				req.PublishContext["LUKSEncryption"])
+		}
+	}
+	publishInfo.LUKSEncryption = strconv.FormatBool(isLUKS)
+	if isLUKS {
+		if req.Secrets, err = resolveLUKSSecrets(ctx, p.restClient, req.GetVolumeId(), req.GetSecrets()); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
 	// The NFS mount path should be same for all the Subvolumes belonging to the same NFS volumes
 	// thus use NFS volume's Unique ID. This also means the subvolumes from different Virtual Pools,
 	// different backends but from the same NFS volume would be available under the same NFS mount
@@ -1359,13 +1776,27 @@ func (p *Plugin) nodeStageNFSBlockVolume(
 		publishInfo.NfsPath)
 
 	loopFile := path.Join(publishInfo.NFSMountpoint, publishInfo.SubvolumeName)
-	loopDeviceName, stagingMountpoint, err := utils.AttachBlockOnFileVolume(ctx, stagingTargetPath, publishInfo)
+	devicePath, stagingMountpoint, err := utils.AttachBlockOnFileVolume(ctx, req.VolumeContext["internalName"],
+		stagingTargetPath, publishInfo, req.GetSecrets())
 	if err != nil {
 		return nil, err
 	}
-	publishInfo.DevicePath = loopDeviceName
+	publishInfo.DevicePath = devicePath
 	publishInfo.StagingMountpoint = stagingMountpoint
 
+	if isLUKS {
+		luksDevice, err := utils.NewLUKSDeviceFromMappingPath(ctx, publishInfo.DevicePath,
+			req.VolumeContext["internalName"])
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		// Ensure we update the passphrase incase it has never been set before
+		err = ensureLUKSVolumePassphrase(ctx, p.restClient, luksDevice, volumeId, req.GetSecrets(), true)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "could not set LUKS volume passphrase")
+		}
+	}
+
 	loopFileInfo, err := os.Stat(loopFile)
 	if err != nil {
 		Logc(ctx).WithField("loopFile", loopFile).WithError(err).Error("Failed to get loop file size")
@@ -1374,7 +1805,7 @@ func (p *Plugin) nodeStageNFSBlockVolume(
 
 	loopFileSize := loopFileInfo.Size()
 
-	err = p.nodeExpandVolume(ctx, publishInfo, loopFileSize, stagingTargetPath, volumeId, map[string]string{})
+	err = p.nodeExpandVolume(ctx, publishInfo, loopFileSize, stagingTargetPath, volumeId, req.GetSecrets())
 	if err != nil {
 		return nil, err
 	}
@@ -1408,6 +1839,11 @@ func (p *Plugin) nodeUnstageNFSBlockVolume(
 			publishInfo.StagingMountpoint, err))
 	}
 
+	// The LUKS mapping holds the loop device open, so it must be closed first
+	if err := utils.CloseBlockOnFileLUKSDevice(ctx, publishInfo); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
 	nfsMountpoint := publishInfo.NFSMountpoint
 	loopFile := path.Join(nfsMountpoint, publishInfo.SubvolumeName)
 
@@ -1484,6 +1920,28 @@ func (p *Plugin) nodePublishNFSBlockVolume(
 	}
 	publishInfo := &trackingInfo.VolumePublishInfo
 
+	if publishInfo.LUKSEncryption != "" {
+		isLUKS, err := strconv.ParseBool(publishInfo.LUKSEncryption)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse LUKSEncryption into a bool, got %v", publishInfo.LUKSEncryption)
+		}
+		if isLUKS {
+			// Rotate the LUKS passphrase if needed, on failure, log and continue to publish
+			luksDevice, err := utils.NewLUKSDeviceFromMappingPath(ctx, publishInfo.DevicePath,
+				req.VolumeContext["internalName"])
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			secrets, err := resolveLUKSSecrets(ctx, p.restClient, req.GetVolumeId(), req.GetSecrets())
+			if err == nil {
+				err = ensureLUKSVolumePassphrase(ctx, p.restClient, luksDevice, req.GetVolumeId(), secrets, false)
+			}
+			if err != nil {
+				Logc(ctx).WithError(err).Error("Failed to ensure current LUKS passphrase.")
+			}
+		}
+	}
+
 	publishInfo.SubvolumeMountOptions = getSubvolumeMountOptions(false, publishInfo.SubvolumeMountOptions)
 
 	err = utils.MountDevice(ctx, publishInfo.StagingMountpoint, req.TargetPath, publishInfo.SubvolumeMountOptions,
@@ -1653,6 +2111,7 @@ func (p *Plugin) performISCSISelfHealing(ctx context.Context) {
 	// If there are not iSCSI volumes expected on the host skip self-healing
 	if publishedISCSISessions.IsEmpty() {
 		Logc(ctx).Debug("Skipping iSCSI self-heal cycle; no iSCSI volumes published on the host.")
+		iSCSIVolumePathsGauge.Reset()
 		return
 	}
 
@@ -1697,6 +2156,9 @@ func (p *Plugin) performISCSISelfHealing(ctx context.Context) {
 	// SELF-HEAL STEP 3: Attempt to fix at-least one of the non-stale portals.
 	p.fixISCSISessions(ctx, nonStaleISCSIPortals, "non-stale", stopSelfHealingAt)
 
+	// SELF-HEAL STEP 4: Attempt to fix failed paths within the multipath maps of published volumes.
+	p.healISCSIPaths(ctx, stopSelfHealingAt)
+
 	return
 }
 
@@ -12,8 +12,13 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/mitchellh/copystructure"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"github.com/netapp/trident/config"
 	mockControllerAPI "github.com/netapp/trident/mocks/mock_frontend/mock_csi/mock_controller_api"
+	mockNodeHelpers "github.com/netapp/trident/mocks/mock_frontend/mock_csi/mock_node_helpers"
+	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
 )
 
@@ -541,3 +546,234 @@ func structCopyHelper(input utils.ISCSISessionData) *utils.ISCSISessionData {
 func snooze(val uint32) {
 	time.Sleep(time.Duration(val) * time.Millisecond)
 }
+
+func TestEnsureSinglePodPublication(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockHelper := mockNodeHelpers.NewMockNodeHelper(mockCtrl)
+	plugin := &Plugin{nodeHelper: mockHelper}
+
+	singleWriter := &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER},
+	}
+	req := &csi.NodePublishVolumeRequest{VolumeId: "vol1", TargetPath: "/pods/pod1", VolumeCapability: singleWriter}
+	trackingInfo := &utils.VolumeTrackingInfo{PublishedPaths: map[string]struct{}{}}
+	mockHelper.EXPECT().ReadTrackingInfo(gomock.Any(), "vol1").Return(trackingInfo, nil).AnyTimes()
+
+	// An unpublished volume may be published
+	assert.NoError(t, plugin.ensureSinglePodPublication(context.Background(), req))
+
+	// Publishing to the same target path again is idempotent
+	trackingInfo.PublishedPaths["/pods/pod1"] = struct{}{}
+	assert.NoError(t, plugin.ensureSinglePodPublication(context.Background(), req))
+
+	// A second pod may not use the volume
+	req.TargetPath = "/pods/pod2"
+	err := plugin.ensureSinglePodPublication(context.Background(), req)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// Other access modes are not checked
+	req.VolumeCapability = &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER},
+	}
+	assert.NoError(t, plugin.ensureSinglePodPublication(context.Background(), req))
+}
+
+func TestGetEphemeralVolumeConfig(t *testing.T) {
+	plugin := &Plugin{}
+	mountCapability := &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+		AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+	}
+	req := &csi.NodePublishVolumeRequest{
+		VolumeId:         "csi-1234",
+		VolumeCapability: mountCapability,
+		VolumeContext:    map[string]string{EphemeralVolumeContextKey: "true"},
+	}
+
+	// The storage class attribute is required
+	_, err := plugin.getEphemeralVolumeConfig(req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// The size defaults and the filesystem comes from the capability
+	req.VolumeContext[EphemeralStorageClassAttribute] = "scratch"
+	volConfig, err := plugin.getEphemeralVolumeConfig(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "csi-1234", volConfig.Name)
+	assert.Equal(t, "scratch", volConfig.StorageClass)
+	assert.Equal(t, "1073741824", volConfig.Size)
+	assert.Equal(t, "xfs", volConfig.FileSystem)
+	assert.Equal(t, config.Filesystem, volConfig.VolumeMode)
+	assert.Equal(t, config.ReadWriteOnce, volConfig.AccessMode)
+
+	// Volume attributes override the defaults
+	req.VolumeContext[EphemeralSizeAttribute] = "2Gi"
+	req.VolumeContext[EphemeralFsTypeAttribute] = "ext4"
+	volConfig, err = plugin.getEphemeralVolumeConfig(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "2147483648", volConfig.Size)
+	assert.Equal(t, "ext4", volConfig.FileSystem)
+
+	// An invalid size is rejected
+	req.VolumeContext[EphemeralSizeAttribute] = "lots"
+	_, err = plugin.getEphemeralVolumeConfig(req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// Raw block volumes have no filesystem
+	req.VolumeContext[EphemeralSizeAttribute] = "1Gi"
+	req.VolumeCapability = &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER},
+		AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+	}
+	volConfig, err = plugin.getEphemeralVolumeConfig(req)
+	assert.NoError(t, err)
+	assert.Equal(t, config.RawBlock, volConfig.VolumeMode)
+	assert.Equal(t, config.FsRaw, volConfig.FileSystem)
+	assert.Equal(t, config.Block, volConfig.Protocol)
+}
+
+func TestNodeStageEphemeralVolume(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockClient := mockControllerAPI.NewMockTridentController(mockCtrl)
+	mockHelper := mockNodeHelpers.NewMockNodeHelper(mockCtrl)
+	plugin := &Plugin{nodeName: "node1", role: CSINode, restClient: mockClient, nodeHelper: mockHelper}
+
+	req := &csi.NodePublishVolumeRequest{
+		VolumeId:   "csi-1234",
+		TargetPath: "/pods/pod1/volumes/csi-1234/mount",
+		VolumeCapability: &csi.VolumeCapability{
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		},
+		VolumeContext: map[string]string{
+			EphemeralVolumeContextKey:      "true",
+			EphemeralStorageClassAttribute: "scratch",
+		},
+	}
+	volume := &storage.VolumeExternal{
+		Config: &storage.VolumeConfig{
+			Name:         "csi-1234",
+			InternalName: "trident_csi_1234",
+			Size:         "1073741824",
+			Protocol:     config.File,
+		},
+		BackendUUID: "backend1",
+	}
+	publishInfo := &utils.VolumePublishInfo{FilesystemType: "nfs"}
+	publishInfo.NfsServerIP = "1.1.1.1"
+	publishInfo.NfsPath = "/trident_csi_1234"
+	trackingInfo := &utils.VolumeTrackingInfo{
+		StagingTargetPath: "/pods/pod1/volumes/csi-1234/" + ephemeralStagingDir,
+		PublishedPaths:    map[string]struct{}{},
+		Ephemeral:         true,
+	}
+
+	// A new volume is created and published, and the publish request carries what the node needs
+	gomock.InOrder(
+		mockClient.EXPECT().GetVolume(ctx, "csi-1234").Return(nil, utils.NotFoundError("not found")),
+		mockClient.EXPECT().CreateVolume(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, volConfig *storage.VolumeConfig) error {
+				assert.Equal(t, "scratch", volConfig.StorageClass)
+				return nil
+			}),
+		mockClient.EXPECT().GetVolume(ctx, "csi-1234").Return(volume, nil),
+	)
+	mockClient.EXPECT().PublishVolume(ctx, "csi-1234", "node1", gomock.Any()).Return(publishInfo, nil)
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "csi-1234").Return(trackingInfo, nil)
+
+	publishReq, err := plugin.nodeStageEphemeralVolume(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, trackingInfo.StagingTargetPath, publishReq.StagingTargetPath)
+	assert.Equal(t, req.TargetPath, publishReq.TargetPath)
+	assert.Equal(t, string(config.File), publishReq.PublishContext["protocol"])
+	assert.Equal(t, "/trident_csi_1234", publishReq.PublishContext["nfsPath"])
+	assert.Equal(t, "trident_csi_1234", publishReq.VolumeContext["internalName"])
+
+	// Publishing fails if the controller cannot publish the volume
+	mockClient.EXPECT().GetVolume(ctx, "csi-1234").Return(volume, nil)
+	mockClient.EXPECT().PublishVolume(ctx, "csi-1234", "node1", gomock.Any()).Return(nil, fmt.Errorf("fenced"))
+
+	_, err = plugin.nodeStageEphemeralVolume(ctx, req)
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	// Volumes without a storage class are rejected before anything is created
+	delete(req.VolumeContext, EphemeralStorageClassAttribute)
+	mockClient.EXPECT().GetVolume(ctx, "csi-1234").Return(nil, utils.NotFoundError("not found"))
+
+	_, err = plugin.nodeStageEphemeralVolume(ctx, req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDeleteEphemeralVolume(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockClient := mockControllerAPI.NewMockTridentController(mockCtrl)
+	mockHelper := mockNodeHelpers.NewMockNodeHelper(mockCtrl)
+	plugin := &Plugin{nodeName: "node1", role: CSINode, restClient: mockClient, nodeHelper: mockHelper}
+
+	// Persistent volumes are left alone
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "pvc-1").Return(&utils.VolumeTrackingInfo{}, nil)
+	assert.NoError(t, plugin.deleteEphemeralVolume(ctx, "pvc-1"))
+
+	// Ephemeral volumes still published elsewhere are left alone
+	trackingInfo := &utils.VolumeTrackingInfo{
+		PublishedPaths: map[string]struct{}{"/pods/pod1": {}},
+		Ephemeral:      true,
+	}
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "csi-1").Return(trackingInfo, nil)
+	assert.NoError(t, plugin.deleteEphemeralVolume(ctx, "csi-1"))
+
+	// An unstaged ephemeral volume is unpublished and deleted
+	trackingInfo = &utils.VolumeTrackingInfo{PublishedPaths: map[string]struct{}{}, Ephemeral: true}
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "csi-1").Return(trackingInfo, nil)
+	mockClient.EXPECT().UnpublishVolume(ctx, "csi-1", "node1").Return(nil)
+	mockClient.EXPECT().DeleteVolume(ctx, "csi-1").Return(nil)
+	mockHelper.EXPECT().DeleteTrackingInfo(ctx, "csi-1").Return(nil)
+	assert.NoError(t, plugin.deleteEphemeralVolume(ctx, "csi-1"))
+
+	// If the controller cannot delete the volume, it is kept in the tracking file for cleanup
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "csi-1").Return(trackingInfo, nil)
+	mockClient.EXPECT().UnpublishVolume(ctx, "csi-1", "node1").Return(nil)
+	mockClient.EXPECT().DeleteVolume(ctx, "csi-1").Return(fmt.Errorf("controller unavailable"))
+	mockHelper.EXPECT().WriteTrackingInfo(ctx, "csi-1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, info *utils.VolumeTrackingInfo) error {
+			assert.True(t, info.Ephemeral)
+			assert.Equal(t, "", info.StagingTargetPath)
+			return nil
+		})
+	err := plugin.deleteEphemeralVolume(ctx, "csi-1")
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	// Volumes without tracking files are already gone
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "csi-2").Return(nil, utils.NotFoundError("not found"))
+	assert.NoError(t, plugin.deleteEphemeralVolume(ctx, "csi-2"))
+}
+
+func TestCleanupEphemeralVolume(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockClient := mockControllerAPI.NewMockTridentController(mockCtrl)
+	mockHelper := mockNodeHelpers.NewMockNodeHelper(mockCtrl)
+	plugin := &Plugin{nodeName: "node1", role: CSINode, restClient: mockClient, nodeHelper: mockHelper}
+
+	// Volumes whose target paths still exist are unpublished by kubelet
+	targetPath := t.TempDir()
+	trackingInfo := &utils.VolumeTrackingInfo{
+		PublishedPaths: map[string]struct{}{targetPath: {}},
+		Ephemeral:      true,
+	}
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "csi-1").Return(trackingInfo, nil)
+	plugin.cleanupEphemeralVolume(ctx, "csi-1")
+
+	// Volumes whose pods are gone are deleted
+	trackingInfo.PublishedPaths = map[string]struct{}{targetPath + "/gone": {}}
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "csi-1").Return(trackingInfo, nil)
+	mockClient.EXPECT().UnpublishVolume(ctx, "csi-1", "node1").Return(nil)
+	mockClient.EXPECT().DeleteVolume(ctx, "csi-1").Return(nil)
+	mockHelper.EXPECT().DeleteTrackingInfo(ctx, "csi-1").Return(nil)
+	plugin.cleanupEphemeralVolume(ctx, "csi-1")
+
+	// Persistent volumes are left alone
+	mockHelper.EXPECT().ReadTrackingInfo(ctx, "pvc-1").Return(&utils.VolumeTrackingInfo{}, nil)
+	plugin.cleanupEphemeralVolume(ctx, "pvc-1")
+}
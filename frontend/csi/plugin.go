@@ -41,8 +41,12 @@ type Plugin struct {
 	endpoint string
 	role     string
 
-	unsafeDetach      bool
-	enableForceDetach bool
+	unsafeDetach          bool
+	enableForceDetach     bool
+	manageMultipathConfig bool
+
+	// nodeRESTPort is the port of the node's HTTPS REST interface, reported to the controller at registration
+	nodeRESTPort string
 
 	hostInfo *utils.HostSystem
 
@@ -66,22 +70,28 @@ type Plugin struct {
 	iSCSISelfHealingChannel  chan struct{}
 	iSCSISelfHealingInterval time.Duration
 	iSCSISelfHealingWaitTime time.Duration
+	iSCSIPathHealingPolicy   ISCSIPathHealingPolicy
+
+	// volumeJobWaitTime is how long CreateVolume waits for a volume job to finish before asking the CO to retry
+	volumeJobWaitTime time.Duration
 }
 
 func NewControllerPlugin(
 	nodeName, endpoint, aesKeyFile string, orchestrator core.Orchestrator, helper *controllerhelpers.ControllerHelper,
+	volumeJobWaitTime time.Duration,
 ) (*Plugin, error) {
 	ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
 
 	p := &Plugin{
-		orchestrator:     orchestrator,
-		name:             Provisioner,
-		nodeName:         nodeName,
-		version:          tridentconfig.OrchestratorVersion.ShortString(),
-		endpoint:         endpoint,
-		role:             CSIController,
-		controllerHelper: *helper,
-		opCache:          sync.Map{},
+		orchestrator:      orchestrator,
+		name:              Provisioner,
+		nodeName:          nodeName,
+		version:           tridentconfig.OrchestratorVersion.ShortString(),
+		endpoint:          endpoint,
+		role:              CSIController,
+		controllerHelper:  *helper,
+		opCache:           sync.Map{},
+		volumeJobWaitTime: volumeJobWaitTime,
 	}
 
 	var err error
@@ -100,6 +110,7 @@ func NewControllerPlugin(
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
 		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 	})
 
 	// Define volume capabilities
@@ -109,6 +120,8 @@ func NewControllerPlugin(
 		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
 		csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER,
 		csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
 	})
 
 	return p, nil
@@ -117,7 +130,8 @@ func NewControllerPlugin(
 func NewNodePlugin(
 	nodeName, endpoint, caCert, clientCert, clientKey, aesKeyFile string, orchestrator core.Orchestrator,
 	unsafeDetach bool, helper *nodehelpers.NodeHelper, enableForceDetach bool,
-	iSCSISelfHealingInterval, iSCSIStaleSessionWaitTime time.Duration,
+	iSCSISelfHealingInterval, iSCSIStaleSessionWaitTime time.Duration, iSCSIPathHealingPolicy ISCSIPathHealingPolicy,
+	nodeRESTPort string, manageMultipathConfig bool,
 ) (*Plugin, error) {
 	ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
 
@@ -142,6 +156,9 @@ func NewNodePlugin(
 		opCache:                  sync.Map{},
 		iSCSISelfHealingInterval: iSCSISelfHealingInterval,
 		iSCSISelfHealingWaitTime: iSCSIStaleSessionWaitTime,
+		iSCSIPathHealingPolicy:   iSCSIPathHealingPolicy,
+		nodeRESTPort:             nodeRESTPort,
+		manageMultipathConfig:    manageMultipathConfig,
 	}
 
 	if runtime.GOOS == "windows" {
@@ -149,6 +166,7 @@ func NewNodePlugin(
 			[]csi.NodeServiceCapability_RPC_Type{
 				csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
 				csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
+				csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 			},
 		)
 	} else {
@@ -157,6 +175,7 @@ func NewNodePlugin(
 				csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
 				csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
 				csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+				csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 			},
 		)
 	}
@@ -190,6 +209,8 @@ func NewNodePlugin(
 			csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
 			csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER,
 			csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
 		},
 	)
 
@@ -202,7 +223,8 @@ func NewNodePlugin(
 func NewAllInOnePlugin(
 	nodeName, endpoint, caCert, clientCert, clientKey, aesKeyFile string, orchestrator core.Orchestrator,
 	controllerHelper *controllerhelpers.ControllerHelper, nodeHelper *nodehelpers.NodeHelper, unsafeDetach bool,
-	iSCSISelfHealingInterval, iSCSIStaleSessionWaitTime time.Duration,
+	iSCSISelfHealingInterval, iSCSIStaleSessionWaitTime time.Duration, iSCSIPathHealingPolicy ISCSIPathHealingPolicy,
+	volumeJobWaitTime time.Duration,
 ) (*Plugin, error) {
 	ctx := GenerateRequestContext(context.Background(), "", ContextSourceInternal)
 
@@ -213,12 +235,14 @@ func NewAllInOnePlugin(
 		version:                  tridentconfig.OrchestratorVersion.ShortString(),
 		endpoint:                 endpoint,
 		role:                     CSIAllInOne,
+		volumeJobWaitTime:        volumeJobWaitTime,
 		unsafeDetach:             unsafeDetach,
 		controllerHelper:         *controllerHelper,
 		nodeHelper:               *nodeHelper,
 		opCache:                  sync.Map{},
 		iSCSISelfHealingInterval: iSCSISelfHealingInterval,
 		iSCSISelfHealingWaitTime: iSCSIStaleSessionWaitTime,
+		iSCSIPathHealingPolicy:   iSCSIPathHealingPolicy,
 	}
 
 	// Define controller capabilities
@@ -231,12 +255,14 @@ func NewAllInOnePlugin(
 		csi.ControllerServiceCapability_RPC_EXPAND_VOLUME,
 		csi.ControllerServiceCapability_RPC_CLONE_VOLUME,
 		csi.ControllerServiceCapability_RPC_LIST_VOLUMES_PUBLISHED_NODES,
+		csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 	})
 
 	p.addNodeServiceCapabilities([]csi.NodeServiceCapability_RPC_Type{
 		csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME,
 		csi.NodeServiceCapability_RPC_EXPAND_VOLUME,
 		csi.NodeServiceCapability_RPC_GET_VOLUME_STATS,
+		csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER,
 	})
 	port := "34571"
 	for _, envVar := range os.Environ() {
@@ -265,6 +291,8 @@ func NewAllInOnePlugin(
 		csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
 		csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER,
 		csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
 	})
 
 	return p, nil
@@ -341,6 +369,8 @@ func (p *Plugin) addVolumeCapabilityAccessModes(vc []csi.VolumeCapability_Access
 func (p *Plugin) getCSIErrorForOrchestratorError(err error) error {
 	if utils.IsNotReadyError(err) {
 		return status.Error(codes.Unavailable, err.Error())
+	} else if utils.IsBackendUnavailableError(err) {
+		return status.Error(codes.Unavailable, err.Error())
 	} else if utils.IsBootstrapError(err) {
 		return status.Error(codes.FailedPrecondition, err.Error())
 	} else if utils.IsNotFoundError(err) {
@@ -394,6 +424,7 @@ func (p *Plugin) startISCSISelfHealingThread(ctx context.Context) {
 	Logc(ctx).WithFields(log.Fields{
 		"iSCSISelfHealingInterval": p.iSCSISelfHealingInterval,
 		"iSCSISelfHealingWaitTime": p.iSCSISelfHealingWaitTime,
+		"iSCSIPathHealingPolicy":   p.iSCSIPathHealingPolicy,
 	}).Debugf(
 		"iSCSI self-healing is enabled.")
 	p.iSCSISelfHealingTicker = time.NewTicker(p.iSCSISelfHealingInterval)
@@ -235,6 +235,97 @@ func performProtocolSpecificReconciliation(ctx context.Context, trackingInfo *ut
 	return false, nil
 }
 
+// resolveLUKSSecrets returns the secrets with which to open a LUKS volume.  If the secrets select a key-management
+// service, the volume's data-encryption key is unwrapped, or generated and wrapped the first time the volume is
+// staged, and returned as the current LUKS passphrase; only the wrapped key is ever sent to the Trident controller.
+// If another node records a key for the volume first, that key is used instead.
+// A previous static passphrase in the secrets is retained so existing volumes can be rotated onto the KMS key.
+// Staging fails once the volume's key has been revoked, either in Trident or in the KMS itself.
+func resolveLUKSSecrets(
+	ctx context.Context, restClient controllerAPI.TridentController, volumeId string, secrets map[string]string,
+) (map[string]string, error) {
+	if !utils.IsLUKSKMSConfigured(secrets) {
+		return secrets, nil
+	}
+
+	kms, err := utils.NewKeyManagementService(secrets)
+	if err != nil {
+		return nil, fmt.Errorf("could not configure LUKS key-management service; %v", err)
+	}
+	keyName := secrets[utils.LUKSKMSKeyNameKey]
+
+	wrappedKey, err := restClient.GetVolumeLUKSWrappedKey(ctx, volumeId)
+	if err != nil {
+		return nil, err
+	}
+
+	var dataKey []byte
+	if wrappedKey == nil {
+		// The wrapped key must be recorded before the key is used, or the volume could become unreadable
+		Logc(ctx).WithFields(log.Fields{
+			"volume":   volumeId,
+			"provider": kms.Provider(),
+			"keyName":  keyName,
+		}).Info("Generating LUKS data-encryption key.")
+		var wrapped string
+		if dataKey, wrapped, err = kms.GenerateDataKey(ctx, keyName); err != nil {
+			return nil, err
+		}
+		newWrappedKey := &utils.LUKSWrappedKey{Provider: kms.Provider(), KeyName: keyName, WrappedKey: wrapped}
+		err = restClient.UpdateVolumeLUKSWrappedKey(ctx, volumeId, newWrappedKey, "")
+		if err != nil && !utils.IsFoundError(err) {
+			return nil, fmt.Errorf("could not record LUKS data-encryption key; %v", err)
+		} else if err != nil {
+			// Another node recorded its key first and may already have formatted the volume with it
+			Logc(ctx).WithField("volume", volumeId).Info("Using LUKS data-encryption key recorded by another node.")
+			dataKey = nil
+			if wrappedKey, err = restClient.GetVolumeLUKSWrappedKey(ctx, volumeId); err != nil {
+				return nil, err
+			}
+			if wrappedKey == nil {
+				return nil, fmt.Errorf("LUKS data-encryption key for volume %s is missing", volumeId)
+			}
+		}
+	}
+
+	if dataKey == nil {
+		if wrappedKey.Revoked {
+			return nil, fmt.Errorf("LUKS data-encryption key for volume %s has been revoked", volumeId)
+		}
+		if wrappedKey.Provider != kms.Provider() {
+			return nil, fmt.Errorf("LUKS data-encryption key for volume %s was wrapped by %s, not %s",
+				volumeId, wrappedKey.Provider, kms.Provider())
+		}
+		if dataKey, err = kms.UnwrapDataKey(ctx, wrappedKey.KeyName, wrappedKey.WrappedKey); err != nil {
+			return nil, err
+		}
+
+		// Move the key onto the configured, most recent key-encryption key; on failure, log and continue
+		var rewrapped string
+		if wrappedKey.KeyName != keyName {
+			rewrapped, err = kms.WrapDataKey(ctx, keyName, dataKey)
+		} else {
+			rewrapped, err = kms.RewrapDataKey(ctx, keyName, wrappedKey.WrappedKey)
+		}
+		if err == nil && rewrapped != wrappedKey.WrappedKey {
+			newWrappedKey := &utils.LUKSWrappedKey{Provider: kms.Provider(), KeyName: keyName, WrappedKey: rewrapped}
+			err = restClient.UpdateVolumeLUKSWrappedKey(ctx, volumeId, newWrappedKey, wrappedKey.WrappedKey)
+		}
+		if err != nil {
+			Logc(ctx).WithField("volume", volumeId).WithError(err).Error(
+				"Failed to rewrap LUKS data-encryption key.")
+		}
+	}
+
+	luksSecrets := utils.GetLUKSSecretsForDataKey(dataKey)
+	_, _, previousLUKSPassphraseName, previousLUKSPassphrase := utils.GetLUKSPassphrasesFromSecretMap(secrets)
+	if previousLUKSPassphrase != "" {
+		luksSecrets["previous-luks-passphrase"] = previousLUKSPassphrase
+		luksSecrets["previous-luks-passphrase-name"] = previousLUKSPassphraseName
+	}
+	return luksSecrets, nil
+}
+
 // ensureLUKSVolumePassphrase ensures the LUKS device has the most recent passphrase and notifies the Trident controller
 // of any possibly in use passphrases. If forceUpdate is true, the Trident controller will be notified of the current
 // passphrase name, regardless of a rotation.
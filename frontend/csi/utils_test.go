@@ -465,3 +465,145 @@ func TestEnsureLUKSVolumePassphrase_NoCorrectPassphraseProvided(t *testing.T) {
 	assert.Error(t, err)
 	mockCtrl.Finish()
 }
+
+func TestResolveLUKSSecrets(t *testing.T) {
+	ctx := context.TODO()
+	masterKey, err := utils.GenerateAESKey()
+	assert.NoError(t, err)
+	kmsSecrets := map[string]string{
+		utils.LUKSKMSProviderKey: utils.LUKSKMSProviderLocal,
+		utils.LUKSKMSKeyNameKey:  "trident",
+		utils.LUKSKMSLocalKeyKey: masterKey,
+	}
+	kms, err := utils.NewKeyManagementService(kmsSecrets)
+	assert.NoError(t, err)
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Positive case: Static passphrase secrets are returned unchanged
+	mockCtrl := gomock.NewController(t)
+	mockClient := mockControllerAPI.NewMockTridentController(mockCtrl)
+	staticSecrets := map[string]string{"luks-passphrase-name": "A", "luks-passphrase": "passphraseA"}
+	secrets, err := resolveLUKSSecrets(ctx, mockClient, "test-vol", staticSecrets)
+	assert.NoError(t, err)
+	assert.Equal(t, staticSecrets, secrets)
+	mockCtrl.Finish()
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Positive case: First stage generates and records a wrapped key
+	mockCtrl = gomock.NewController(t)
+	mockClient = mockControllerAPI.NewMockTridentController(mockCtrl)
+	var recorded *utils.LUKSWrappedKey
+	mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(nil, nil)
+	mockClient.EXPECT().UpdateVolumeLUKSWrappedKey(gomock.Any(), "test-vol", gomock.Any(), "").DoAndReturn(
+		func(_ context.Context, _ string, wrappedKey *utils.LUKSWrappedKey, _ string) error {
+			recorded = wrappedKey
+			return nil
+		})
+	secrets, err = resolveLUKSSecrets(ctx, mockClient, "test-vol", kmsSecrets)
+	assert.NoError(t, err)
+	assert.NotNil(t, recorded)
+	assert.Equal(t, utils.LUKSKMSProviderLocal, recorded.Provider)
+	assert.Equal(t, "trident", recorded.KeyName)
+	assert.NotContains(t, recorded.WrappedKey, secrets["luks-passphrase"])
+	dataKey, err := kms.UnwrapDataKey(ctx, "trident", recorded.WrappedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.GetLUKSSecretsForDataKey(dataKey), secrets)
+	mockCtrl.Finish()
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Positive case: Later stages unwrap the recorded key, keeping any previous static passphrase
+	mockCtrl = gomock.NewController(t)
+	mockClient = mockControllerAPI.NewMockTridentController(mockCtrl)
+	mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(recorded, nil)
+	migrationSecrets := map[string]string{
+		"previous-luks-passphrase-name": "A",
+		"previous-luks-passphrase":      "passphraseA",
+	}
+	for k, v := range kmsSecrets {
+		migrationSecrets[k] = v
+	}
+	secrets, err = resolveLUKSSecrets(ctx, mockClient, "test-vol", migrationSecrets)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.GetLUKSSecretsForDataKey(dataKey)["luks-passphrase"], secrets["luks-passphrase"])
+	assert.Equal(t, "passphraseA", secrets["previous-luks-passphrase"])
+	assert.Equal(t, "A", secrets["previous-luks-passphrase-name"])
+	mockCtrl.Finish()
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Positive case: Key rewrapped when the configured key-encryption key changes
+	mockCtrl = gomock.NewController(t)
+	mockClient = mockControllerAPI.NewMockTridentController(mockCtrl)
+	mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(recorded, nil)
+	mockClient.EXPECT().UpdateVolumeLUKSWrappedKey(gomock.Any(), "test-vol", gomock.Any(),
+		recorded.WrappedKey).DoAndReturn(
+		func(_ context.Context, _ string, wrappedKey *utils.LUKSWrappedKey, _ string) error {
+			assert.Equal(t, "trident-2", wrappedKey.KeyName)
+			return nil
+		})
+	rotatedSecrets := map[string]string{}
+	for k, v := range kmsSecrets {
+		rotatedSecrets[k] = v
+	}
+	rotatedSecrets[utils.LUKSKMSKeyNameKey] = "trident-2"
+	secrets, err = resolveLUKSSecrets(ctx, mockClient, "test-vol", rotatedSecrets)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.GetLUKSSecretsForDataKey(dataKey), secrets)
+	mockCtrl.Finish()
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Positive case: A node that loses the race to record a key uses the winner's key
+	mockCtrl = gomock.NewController(t)
+	mockClient = mockControllerAPI.NewMockTridentController(mockCtrl)
+	gomock.InOrder(
+		mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(nil, nil),
+		mockClient.EXPECT().UpdateVolumeLUKSWrappedKey(gomock.Any(), "test-vol", gomock.Any(), "").Return(
+			utils.FoundError("replaced")),
+		mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(recorded, nil),
+	)
+	secrets, err = resolveLUKSSecrets(ctx, mockClient, "test-vol", kmsSecrets)
+	assert.NoError(t, err)
+	assert.Equal(t, utils.GetLUKSSecretsForDataKey(dataKey), secrets)
+	mockCtrl.Finish()
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Negative case: Revoked key blocks staging
+	mockCtrl = gomock.NewController(t)
+	mockClient = mockControllerAPI.NewMockTridentController(mockCtrl)
+	revoked := *recorded
+	revoked.Revoked = true
+	mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(&revoked, nil)
+	_, err = resolveLUKSSecrets(ctx, mockClient, "test-vol", kmsSecrets)
+	assert.Error(t, err)
+	mockCtrl.Finish()
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Negative case: Key wrapped by a different provider
+	mockCtrl = gomock.NewController(t)
+	mockClient = mockControllerAPI.NewMockTridentController(mockCtrl)
+	mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(
+		&utils.LUKSWrappedKey{Provider: utils.LUKSKMSProviderVaultTransit, KeyName: "trident", WrappedKey: "vault:v1:x"},
+		nil)
+	_, err = resolveLUKSSecrets(ctx, mockClient, "test-vol", kmsSecrets)
+	assert.Error(t, err)
+	mockCtrl.Finish()
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Negative case: Generated key could not be recorded
+	mockCtrl = gomock.NewController(t)
+	mockClient = mockControllerAPI.NewMockTridentController(mockCtrl)
+	mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(nil, nil)
+	mockClient.EXPECT().UpdateVolumeLUKSWrappedKey(gomock.Any(), "test-vol", gomock.Any(), "").Return(
+		errors.New("failed"))
+	_, err = resolveLUKSSecrets(ctx, mockClient, "test-vol", kmsSecrets)
+	assert.Error(t, err)
+	mockCtrl.Finish()
+
+	// ////////////////////////////////////////////////////////////////////////////////////////////////////////////
+	// Negative case: Controller unreachable
+	mockCtrl = gomock.NewController(t)
+	mockClient = mockControllerAPI.NewMockTridentController(mockCtrl)
+	mockClient.EXPECT().GetVolumeLUKSWrappedKey(gomock.Any(), "test-vol").Return(nil, errors.New("failed"))
+	_, err = resolveLUKSSecrets(ctx, mockClient, "test-vol", kmsSecrets)
+	assert.Error(t, err)
+	mockCtrl.Finish()
+}
@@ -197,6 +197,12 @@ func (v *VolumePublishManager) ValidateTrackingFile(ctx context.Context, volumeI
 		return false, TerminalReconciliationError(err.Error())
 	}
 
+	// Ephemeral volumes must also be deleted from their backend, which the node plugin does once it registers with
+	// the controller, so their tracking files are kept until then.
+	if trackingInfo.Ephemeral {
+		return false, nil
+	}
+
 	stagePath := trackingInfo.StagingTargetPath
 	// The value of the stagingTargetPath in Windows tracking files that were upgraded is incorrect, so there is no
 	// value in checking the directory. Therefore, we return early before doing so. False is returned here because
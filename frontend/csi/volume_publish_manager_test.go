@@ -274,6 +274,15 @@ func TestValidateTrackingFile(t *testing.T) {
 	assert.True(t, needsDelete, "expected to get true if we can't determine the protocol of the volume")
 	assert.NoError(t, err, "expected no error if we can't determine the protocol of the volume")
 
+	// Ephemeral volumes are kept until the node plugin deletes them, even if nothing else remains.
+	trackInfo.Ephemeral = true
+	jsonReaderWriter.EXPECT().ReadJSONFile(gomock.Any(), emptyTrackInfo, fName, gomock.Any()).
+		SetArg(1, *trackInfo).Return(nil)
+	needsDelete, err = v.ValidateTrackingFile(context.Background(), volName)
+	assert.False(t, needsDelete, "expected to keep the tracking file of an ephemeral volume")
+	assert.NoError(t, err, "expected no error for an ephemeral volume")
+	trackInfo.Ephemeral = false
+
 	// If staging path does exist, and the file is JSON, return false, because we know something related to the volum
 	// still exists.
 
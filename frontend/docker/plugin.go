@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -40,6 +41,9 @@ type Plugin struct {
 	mutex              *sync.Mutex
 	isDockerPluginMode bool
 	hostVolumePath     string
+	hostName           string
+	// mounts holds the IDs of the Docker mounts of each volume on this host, guarded by mutex
+	mounts map[string]map[string]bool
 }
 
 func NewPlugin(driverName, driverPort string, orchestrator core.Orchestrator) (*Plugin, error) {
@@ -53,6 +57,13 @@ func NewPlugin(driverName, driverPort string, orchestrator core.Orchestrator) (*
 		isDockerPluginMode = true
 	}
 
+	// Volume publications are recorded against this host's name, which must be unique among the hosts
+	// sharing a backend.  The plugin container shares the host's UTS namespace.
+	hostName, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine host name; %v", err)
+	}
+
 	// Create the plugin object
 	plugin := &Plugin{
 		orchestrator:       orchestrator,
@@ -62,6 +73,8 @@ func NewPlugin(driverName, driverPort string, orchestrator core.Orchestrator) (*
 		mutex:              &sync.Mutex{},
 		isDockerPluginMode: isDockerPluginMode,
 		hostVolumePath:     "",
+		hostName:           hostName,
+		mounts:             make(map[string]map[string]bool),
 	}
 
 	if plugin.isDockerPluginMode {
@@ -271,6 +284,17 @@ func (p *Plugin) Create(request *volume.CreateRequest) error {
 		"options": request.Options,
 	}, "Docker frontend method is invoked.")
 
+	// Docker only asks to create a volume Trident already has if this host has not yet recorded it,
+	// in which case a larger size grows the volume.  An existing volume can't be cloned into, though.
+	if tridentVol, err := p.orchestrator.GetVolume(ctx, request.Name); err == nil {
+		if utils.GetV(request.Options, "from|fromSnap|fromSnapshot", "") != "" {
+			return p.dockerError(ctx, fmt.Errorf("volume %s already exists", request.Name))
+		}
+		return p.dockerError(ctx, p.growVolume(ctx, tridentVol, request.Options))
+	} else if !utils.IsNotFoundError(err) {
+		return p.dockerError(ctx, err)
+	}
+
 	// Find a matching storage class, or register a new one
 	scConfig, err := frontendcommon.GetStorageClass(ctx, request.Options, p.orchestrator)
 	if err != nil {
@@ -291,6 +315,25 @@ func (p *Plugin) Create(request *volume.CreateRequest) error {
 		return p.dockerError(ctx, err)
 	}
 
+	// Accept the source volume as part of the snapshot, i.e. fromSnapshot=<volume>/<snapshot>
+	if volConfig.CloneSourceSnapshot != "" && volConfig.CloneSourceVolume == "" {
+		sourceVolume, sourceSnapshot, ok := strings.Cut(volConfig.CloneSourceSnapshot, "/")
+		if !ok || sourceVolume == "" || sourceSnapshot == "" {
+			return p.dockerError(ctx, fmt.Errorf("invalid fromSnapshot option %s; use "+
+				"fromSnapshot=<volume>/<snapshot> or specify the source volume with from=<volume>",
+				volConfig.CloneSourceSnapshot))
+		}
+		volConfig.CloneSourceVolume = sourceVolume
+		volConfig.CloneSourceSnapshot = sourceSnapshot
+	}
+	if volConfig.CloneSourceSnapshot != "" {
+		if _, err = p.orchestrator.GetSnapshot(ctx, volConfig.CloneSourceVolume,
+			volConfig.CloneSourceSnapshot); err != nil {
+			return p.dockerError(ctx, fmt.Errorf("cannot clone from snapshot %s of volume %s: %v",
+				volConfig.CloneSourceSnapshot, volConfig.CloneSourceVolume, err))
+		}
+	}
+
 	// Invoke the orchestrator to create or clone the new volume
 	if volConfig.CloneSourceVolume != "" {
 		_, err = p.orchestrator.CloneVolume(ctx, volConfig)
@@ -300,6 +343,34 @@ func (p *Plugin) Create(request *volume.CreateRequest) error {
 	return p.dockerError(ctx, err)
 }
 
+// growVolume resizes an existing volume if the size option is larger than the volume.  Volumes cannot shrink,
+// so smaller or missing sizes are ignored.
+func (p *Plugin) growVolume(ctx context.Context, vol *storage.VolumeExternal, opts map[string]string) error {
+	if utils.GetV(opts, "size", "") == "" {
+		return nil
+	}
+
+	sizeBytes, err := utils.GetVolumeSizeBytes(ctx, opts, "0")
+	if err != nil {
+		return fmt.Errorf("error resizing volume: %v", err)
+	}
+	currentSizeBytes, err := strconv.ParseUint(vol.Config.Size, 10, 64)
+	if err != nil {
+		return fmt.Errorf("could not determine the size of volume %s: %v", vol.Config.Name, err)
+	}
+	if sizeBytes <= currentSizeBytes {
+		return nil
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":      vol.Config.Name,
+		"currentSize": currentSizeBytes,
+		"newSize":     sizeBytes,
+	}).Info("Growing volume.")
+
+	return p.orchestrator.ResizeVolume(ctx, vol.Config.Name, strconv.FormatUint(sizeBytes, 10))
+}
+
 func (p *Plugin) List() (*volume.ListResponse, error) {
 	ctx := GenerateRequestContext(nil, "", ContextSourceDocker)
 
@@ -430,7 +501,7 @@ func (p *Plugin) Mount(request *volume.MountRequest) (*volume.MountResponse, err
 	}
 
 	// First call PublishVolume to make the volume available to the node
-	publishInfo := &utils.VolumePublishInfo{Localhost: true, HostName: "localhost"}
+	publishInfo := &utils.VolumePublishInfo{Localhost: true, HostName: p.hostName}
 	if err = p.orchestrator.PublishVolume(ctx, request.Name, publishInfo); err != nil {
 		err = fmt.Errorf("error publishing volume %s: %v", request.Name, err)
 		Logc(ctx).Error(err)
@@ -447,6 +518,8 @@ func (p *Plugin) Mount(request *volume.MountRequest) (*volume.MountResponse, err
 		return &volume.MountResponse{}, p.dockerError(ctx, err)
 	}
 
+	p.addMount(request.Name, request.ID)
+
 	// if this is binary mode, then hostMountpoint and mountpoint will be the same
 	mountpoint := p.mountpoint(tridentVol.Config.InternalName)
 	return &volume.MountResponse{Mountpoint: mountpoint}, nil
@@ -461,6 +534,16 @@ func (p *Plugin) Unmount(request *volume.UnmountRequest) error {
 		"id":     request.ID,
 	}, "Docker frontend method is invoked.")
 
+	// Containers on this host share the volume's mount, so it stays mounted and published until the
+	// last of them is done with it
+	if remaining := p.removeMount(request.Name, request.ID); remaining > 0 {
+		Logc(ctx).WithFields(log.Fields{
+			"volume": request.Name,
+			"mounts": remaining,
+		}).Debug("Volume is still mounted by other containers.")
+		return nil
+	}
+
 	tridentVol, err := p.orchestrator.GetVolume(ctx, request.Name)
 	if err != nil {
 		return p.dockerError(ctx, err)
@@ -475,12 +558,42 @@ func (p *Plugin) Unmount(request *volume.UnmountRequest) error {
 		return p.dockerError(ctx, err)
 	}
 
+	// Let other hosts know the volume is no longer in use here.  The LUN stays mapped, see below.
+	if err = p.orchestrator.DeleteVolumePublication(ctx, request.Name, p.hostName); err != nil {
+		Logc(ctx).WithError(err).Warningf("Could not remove publication of volume %s.", request.Name)
+	}
+
 	// No longer detaching and removing iSCSI session here because it was causing issues with 'docker cp'.
 	// See https://github.com/moby/moby/issues/34665
 
 	return nil
 }
 
+// addMount records a Docker mount of a volume on this host.
+func (p *Plugin) addMount(volumeName, id string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.mounts[volumeName] == nil {
+		p.mounts[volumeName] = make(map[string]bool)
+	}
+	p.mounts[volumeName][id] = true
+}
+
+// removeMount forgets a Docker mount of a volume on this host and returns how many mounts remain.  Mounts
+// made before the plugin started aren't known, so their volumes appear unmounted.
+func (p *Plugin) removeMount(volumeName, id string) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	delete(p.mounts[volumeName], id)
+	remaining := len(p.mounts[volumeName])
+	if remaining == 0 {
+		delete(p.mounts, volumeName)
+	}
+	return remaining
+}
+
 func (p *Plugin) Capabilities() *volume.CapabilitiesResponse {
 	ctx := GenerateRequestContext(nil, "", ContextSourceDocker)
 
@@ -7,17 +7,25 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 
+	"github.com/docker/go-plugins-helpers/volume"
+	"github.com/golang/mock/gomock"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
+	. "github.com/netapp/trident/logger"
+	mockcore "github.com/netapp/trident/mocks/mock_core"
+	"github.com/netapp/trident/storage"
+	storageclass "github.com/netapp/trident/storage_class"
 	"github.com/netapp/trident/utils"
 )
 
 func TestMain(m *testing.M) {
 	// Disable any standard log output
 	log.SetOutput(ioutil.Discard)
+	InitAuditLogger(true)
 	os.Exit(m.Run())
 }
 
@@ -74,3 +82,137 @@ func TestDeriveHostVolumePath_positive(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, filepath.FromSlash("/dev/lib/docker/plugins/9722f031f38b0188233463043f8a76b09d6c8b1d194ef46c0b16191f84ccf8e9/propagated-mount"), hostVolumePath)
 }
+
+func newTestPlugin(t *testing.T) (*Plugin, *mockcore.MockOrchestrator) {
+	mockCtrl := gomock.NewController(t)
+	orchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	return &Plugin{
+		orchestrator: orchestrator,
+		volumePath:   t.TempDir(),
+		mutex:        &sync.Mutex{},
+		hostName:     "host1",
+		mounts:       make(map[string]map[string]bool),
+	}, orchestrator
+}
+
+func TestCreate_FromSnapshot(t *testing.T) {
+	plugin, orchestrator := newTestPlugin(t)
+
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "clone").Return(nil, utils.NotFoundError("not found"))
+	orchestrator.EXPECT().GetStorageClass(gomock.Any(), gomock.Any()).Return(
+		&storageclass.External{Config: &storageclass.Config{Name: "sc"}}, nil)
+	orchestrator.EXPECT().GetSnapshot(gomock.Any(), "vol1", "snap1").Return(&storage.SnapshotExternal{}, nil)
+	orchestrator.EXPECT().CloneVolume(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, volConfig *storage.VolumeConfig) (*storage.VolumeExternal, error) {
+			assert.Equal(t, "vol1", volConfig.CloneSourceVolume)
+			assert.Equal(t, "snap1", volConfig.CloneSourceSnapshot)
+			return &storage.VolumeExternal{Config: volConfig}, nil
+		})
+
+	err := plugin.Create(&volume.CreateRequest{
+		Name:    "clone",
+		Options: map[string]string{"fromSnapshot": "vol1/snap1"},
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestCreate_FromSnapshotInvalid(t *testing.T) {
+	plugin, orchestrator := newTestPlugin(t)
+
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "clone").Return(nil, utils.NotFoundError("not found")).Times(2)
+	orchestrator.EXPECT().GetStorageClass(gomock.Any(), gomock.Any()).Return(
+		&storageclass.External{Config: &storageclass.Config{Name: "sc"}}, nil).Times(2)
+	orchestrator.EXPECT().GetSnapshot(gomock.Any(), "vol1", "snap2").Return(nil, utils.NotFoundError("not found"))
+
+	// The source volume is required
+	err := plugin.Create(&volume.CreateRequest{
+		Name:    "clone",
+		Options: map[string]string{"fromSnapshot": "snap1"},
+	})
+	assert.Error(t, err)
+
+	// The snapshot must exist
+	err = plugin.Create(&volume.CreateRequest{
+		Name:    "clone",
+		Options: map[string]string{"from": "vol1", "fromSnapshot": "snap2"},
+	})
+	assert.Error(t, err)
+}
+
+func TestCreate_ExistingVolume(t *testing.T) {
+	plugin, orchestrator := newTestPlugin(t)
+
+	existing := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "vol1", Size: "1073741824"}}
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "vol1").Return(existing, nil).Times(4)
+	orchestrator.EXPECT().ResizeVolume(gomock.Any(), "vol1", "2147483648").Return(nil)
+
+	// A larger size grows the volume
+	err := plugin.Create(&volume.CreateRequest{Name: "vol1", Options: map[string]string{"size": "2G"}})
+	assert.NoError(t, err)
+
+	// Volumes are never shrunk, and without a size the volume is left alone
+	err = plugin.Create(&volume.CreateRequest{Name: "vol1", Options: map[string]string{"size": "512M"}})
+	assert.NoError(t, err)
+	err = plugin.Create(&volume.CreateRequest{Name: "vol1", Options: map[string]string{}})
+	assert.NoError(t, err)
+
+	// An existing volume can't become a clone
+	err = plugin.Create(&volume.CreateRequest{Name: "vol1", Options: map[string]string{"fromSnapshot": "vol2/snap1"}})
+	assert.ErrorContains(t, err, "already exists")
+}
+
+func TestMount_InUseElsewhere(t *testing.T) {
+	plugin, orchestrator := newTestPlugin(t)
+
+	vol := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "vol1", InternalName: "trident_vol1"}}
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "vol1").Return(vol, nil)
+	orchestrator.EXPECT().PublishVolume(gomock.Any(), "vol1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, publishInfo *utils.VolumePublishInfo) error {
+			// Publications are made in this host's name
+			assert.Equal(t, "host1", publishInfo.HostName)
+			return utils.FoundError("volume vol1 is in use by host host2")
+		})
+
+	_, err := plugin.Mount(&volume.MountRequest{Name: "vol1", ID: "1"})
+
+	assert.Error(t, err)
+}
+
+func TestUnmount(t *testing.T) {
+	plugin, orchestrator := newTestPlugin(t)
+
+	vol := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "vol1", InternalName: "trident_vol1"}}
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "vol1").Return(vol, nil)
+	orchestrator.EXPECT().DetachVolume(gomock.Any(), "vol1", gomock.Any()).Return(nil)
+	orchestrator.EXPECT().DeleteVolumePublication(gomock.Any(), "vol1", "host1").Return(nil)
+
+	err := plugin.Unmount(&volume.UnmountRequest{Name: "vol1", ID: "1"})
+
+	assert.NoError(t, err)
+}
+
+func TestUnmount_OtherMountsRemain(t *testing.T) {
+	plugin, orchestrator := newTestPlugin(t)
+
+	vol := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "vol1", InternalName: "trident_vol1"}}
+	orchestrator.EXPECT().GetVolume(gomock.Any(), "vol1").Return(vol, nil).Times(3)
+	orchestrator.EXPECT().PublishVolume(gomock.Any(), "vol1", gomock.Any()).Return(nil).Times(2)
+	orchestrator.EXPECT().AttachVolume(gomock.Any(), "vol1", gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	for _, id := range []string{"1", "2"} {
+		_, err := plugin.Mount(&volume.MountRequest{Name: "vol1", ID: id})
+		assert.NoError(t, err)
+	}
+
+	// The volume stays mounted and published while another container uses it
+	err := plugin.Unmount(&volume.UnmountRequest{Name: "vol1", ID: "1"})
+	assert.NoError(t, err)
+
+	orchestrator.EXPECT().DetachVolume(gomock.Any(), "vol1", gomock.Any()).Return(nil)
+	orchestrator.EXPECT().DeleteVolumePublication(gomock.Any(), "vol1", "host1").Return(nil)
+
+	err = plugin.Unmount(&volume.UnmountRequest{Name: "vol1", ID: "2"})
+	assert.NoError(t, err)
+	assert.Empty(t, plugin.mounts)
+}
@@ -24,18 +24,21 @@ type APIServerHTTPS struct {
 	serverKeyFile  string
 }
 
+// NewHTTPSServer creates an HTTPS REST server.  If clientAuth requires a client certificate, every request must
+// come from a Trident node; otherwise, client certificates are verified if presented, so that the handler's
+// routes may decide which callers they serve.
 func NewHTTPSServer(
-	p core.Orchestrator, address, port, caCertFile, serverCertFile, serverKeyFile string, enableMutualTLS bool,
-	handler http.Handler, writeTimeout time.Duration,
+	p core.Orchestrator, address, port, caCertFile, serverCertFile, serverKeyFile string,
+	clientAuth tls.ClientAuthType, handler http.Handler, writeTimeout time.Duration,
 ) (*APIServerHTTPS, error) {
 	orchestrator = p
 
 	apiServer := &APIServerHTTPS{
 		server: &http.Server{
 			Addr:    fmt.Sprintf("%s:%s", address, port),
-			Handler: &tlsAuthHandler{handler: handler},
+			Handler: handler,
 			TLSConfig: &tls.Config{
-				ClientAuth: tls.RequireAndVerifyClientCert,
+				ClientAuth: clientAuth,
 				MinVersion: config.MinServerTLSVersion,
 			},
 			ReadTimeout:  config.HTTPTimeout,
@@ -46,9 +49,11 @@ func NewHTTPSServer(
 		serverKeyFile:  serverKeyFile,
 	}
 
-	if !enableMutualTLS {
-		apiServer.server.Handler = handler
-		apiServer.server.TLSConfig.ClientAuth = tls.NoClientCert
+	if clientAuth == tls.RequireAndVerifyClientCert {
+		apiServer.server.Handler = &tlsAuthHandler{handler: handler}
+	} else if clientAuth != tls.NoClientCert && caCertFile == "" {
+		// Without a CA, client certificates would be checked against the system's roots
+		return nil, fmt.Errorf("a CA certificate is required to verify client certificates")
 	}
 
 	if caCertFile != "" {
@@ -0,0 +1,49 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package rest
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/utils"
+)
+
+func TestNewHTTPSServer(t *testing.T) {
+	certInfo, err := utils.MakeHTTPCertInfo(config.CACertName, config.ServerCertName, config.ClientCertName,
+		config.ControllerCertName)
+	assert.NoError(t, err)
+	caCert, err := base64.StdEncoding.DecodeString(certInfo.CACert)
+	assert.NoError(t, err)
+	caFile := filepath.Join(t.TempDir(), config.CACertFile)
+	assert.NoError(t, os.WriteFile(caFile, caCert, 0o600))
+
+	handler := http.NewServeMux()
+
+	// Every request to a mutual TLS server must come from a Trident node
+	server, err := NewHTTPSServer(nil, "", "8443", caFile, "", "", tls.RequireAndVerifyClientCert, handler,
+		time.Second)
+	assert.NoError(t, err)
+	assert.IsType(t, &tlsAuthHandler{}, server.server.Handler)
+	assert.NotNil(t, server.server.TLSConfig.ClientCAs)
+
+	// Optional client certificates are verified, and left to the routes to check
+	server, err = NewHTTPSServer(nil, "", "8443", caFile, "", "", tls.VerifyClientCertIfGiven, handler,
+		time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, handler, server.server.Handler)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, server.server.TLSConfig.ClientAuth)
+	assert.NotNil(t, server.server.TLSConfig.ClientCAs)
+
+	// Optional client certificates cannot be verified without a CA
+	_, err = NewHTTPSServer(nil, "", "8443", "", "", "", tls.VerifyClientCertIfGiven, handler, time.Second)
+	assert.Error(t, err)
+}
@@ -16,9 +16,11 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/core"
 	"github.com/netapp/trident/frontend"
 	controllerhelpers "github.com/netapp/trident/frontend/csi/controller_helpers"
 	k8shelper "github.com/netapp/trident/frontend/csi/controller_helpers/kubernetes"
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
 	. "github.com/netapp/trident/logger"
 	"github.com/netapp/trident/storage"
 	storageclass "github.com/netapp/trident/storage_class"
@@ -480,6 +482,57 @@ func AddVolume(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type ExplainVolumePlacementResponse struct {
+	Pools []storageclass.PoolPlacement `json:"pools"`
+	Error string                       `json:"error,omitempty"`
+}
+
+func (e *ExplainVolumePlacementResponse) setError(err error) {
+	e.Error = err.Error()
+}
+
+func (e *ExplainVolumePlacementResponse) isError() bool {
+	return e.Error != ""
+}
+
+func (e *ExplainVolumePlacementResponse) logSuccess(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "ExplainVolumePlacement",
+		"pools":   len(e.Pools),
+	}).Debug("Explained volume placement.")
+}
+
+func (e *ExplainVolumePlacementResponse) logFailure(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "ExplainVolumePlacement",
+	}).Error(e.Error)
+}
+
+func ExplainVolumePlacement(w http.ResponseWriter, r *http.Request) {
+	response := &ExplainVolumePlacementResponse{}
+	AddGeneric(w, r, response,
+		func(body []byte) int {
+			volumeConfig := new(storage.VolumeConfig)
+			err := json.Unmarshal(body, volumeConfig)
+			if err != nil {
+				response.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			if err = volumeConfig.Validate(); err != nil {
+				response.setError(err)
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			pools, err := orchestrator.ExplainVolumePlacement(r.Context(), volumeConfig)
+			if err != nil {
+				response.setError(err)
+			} else {
+				response.Pools = pools
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
 type ListVolumesResponse struct {
 	Volumes []string `json:"volumes"`
 	Error   string   `json:"error,omitempty"`
@@ -617,6 +670,230 @@ func UpdateVolumeLUKSPassphraseNames(w http.ResponseWriter, r *http.Request) {
 	UpdateGeneric(w, r, response, volumeLUKSPassphraseNamesUpdater)
 }
 
+func volumeReplicaPromoter(
+	_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, _ []byte,
+) int {
+	updateResponse, ok := response.(*UpdateVolumeResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type UpdateVolumeResponse"))
+		return http.StatusInternalServerError
+	}
+
+	volume, err := orchestrator.PromoteVolumeReplica(r.Context(), vars["volume"])
+	if err != nil {
+		updateResponse.setError(fmt.Errorf("failed to promote replica of volume %s: %s", vars["volume"],
+			err.Error()))
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	updateResponse.Volume = volume
+
+	return http.StatusOK
+}
+
+func PromoteVolumeReplica(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateVolumeResponse{}
+	UpdateGeneric(w, r, response, volumeReplicaPromoter)
+}
+
+type VolumeResize struct {
+	Size string `json:"size"`
+}
+
+func volumeResizer(
+	_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, body []byte,
+) int {
+	updateResponse, ok := response.(*UpdateVolumeResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type UpdateVolumeResponse"))
+		return http.StatusInternalServerError
+	}
+
+	resize := new(VolumeResize)
+	if err := json.Unmarshal(body, resize); err != nil {
+		updateResponse.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+		return http.StatusBadRequest
+	}
+	newSize, err := utils.ConvertSizeToBytes(resize.Size)
+	if err != nil {
+		updateResponse.setError(fmt.Errorf("invalid size %s: %v", resize.Size, err))
+		return http.StatusBadRequest
+	}
+
+	if err = orchestrator.ResizeVolume(r.Context(), vars["volume"], newSize); err != nil {
+		updateResponse.setError(fmt.Errorf("failed to resize volume %s: %s", vars["volume"], err.Error()))
+		return httpStatusCodeForGetUpdateList(err)
+	}
+
+	volume, err := orchestrator.GetVolume(r.Context(), vars["volume"])
+	if err != nil {
+		updateResponse.setError(err)
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	updateResponse.Volume = volume
+
+	return http.StatusOK
+}
+
+func ResizeVolume(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateVolumeResponse{}
+	UpdateGeneric(w, r, response, volumeResizer)
+}
+
+type VolumeTakeover struct {
+	Host string `json:"host"`
+}
+
+func volumeTakeoverUpdater(
+	_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, body []byte,
+) int {
+	updateResponse, ok := response.(*UpdateVolumeResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type UpdateVolumeResponse"))
+		return http.StatusInternalServerError
+	}
+
+	takeover := new(VolumeTakeover)
+	if err := json.Unmarshal(body, takeover); err != nil {
+		updateResponse.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+		return http.StatusBadRequest
+	}
+	if takeover.Host == "" {
+		updateResponse.setError(fmt.Errorf("host must be specified"))
+		return http.StatusBadRequest
+	}
+
+	if err := orchestrator.TakeOverVolume(r.Context(), vars["volume"], takeover.Host); err != nil {
+		updateResponse.setError(fmt.Errorf("failed to take over volume %s: %s", vars["volume"], err.Error()))
+		return httpStatusCodeForGetUpdateList(err)
+	}
+
+	volume, err := orchestrator.GetVolume(r.Context(), vars["volume"])
+	if err != nil {
+		updateResponse.setError(err)
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	updateResponse.Volume = volume
+
+	return http.StatusOK
+}
+
+func TakeOverVolume(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateVolumeResponse{}
+	UpdateGeneric(w, r, response, volumeTakeoverUpdater)
+}
+
+type VolumeLUKSWrappedKeyResponse struct {
+	WrappedKey *utils.LUKSWrappedKey `json:"wrappedKey"`
+	Error      string                `json:"error,omitempty"`
+}
+
+func (r *VolumeLUKSWrappedKeyResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *VolumeLUKSWrappedKeyResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *VolumeLUKSWrappedKeyResponse) logSuccess(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "UpdateVolumeLUKSWrappedKey",
+	}).Info("Updated a volume LUKS key.")
+}
+
+func (r *VolumeLUKSWrappedKeyResponse) logFailure(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "UpdateVolumeLUKSWrappedKey",
+	}).Error(r.Error)
+}
+
+func GetVolumeLUKSWrappedKey(w http.ResponseWriter, r *http.Request) {
+	response := &VolumeLUKSWrappedKeyResponse{}
+	GetGeneric(w, r, response,
+		func(vars map[string]string) int {
+			volume, err := orchestrator.GetVolume(r.Context(), vars["volume"])
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.WrappedKey = volume.Config.LUKSWrappedKey
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+func volumeLUKSWrappedKeyUpdater(
+	_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, body []byte,
+) int {
+	keyResponse, ok := response.(*VolumeLUKSWrappedKeyResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type VolumeLUKSWrappedKeyResponse"))
+		return http.StatusInternalServerError
+	}
+
+	update := &utils.LUKSWrappedKeyUpdate{}
+	if err := json.Unmarshal(body, update); err != nil {
+		keyResponse.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+		return http.StatusBadRequest
+	}
+
+	wrappedKey := &update.LUKSWrappedKey
+	if err := orchestrator.UpdateVolumeLUKSWrappedKey(r.Context(), vars["volume"], wrappedKey,
+		update.PreviousWrappedKey); err != nil {
+		keyResponse.setError(fmt.Errorf("failed to update LUKS key for volume %s: %s", vars["volume"], err.Error()))
+		if utils.IsFoundError(err) {
+			return http.StatusConflict
+		}
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	keyResponse.WrappedKey = wrappedKey
+
+	return http.StatusOK
+}
+
+func UpdateVolumeLUKSWrappedKey(w http.ResponseWriter, r *http.Request) {
+	response := &VolumeLUKSWrappedKeyResponse{}
+	UpdateGeneric(w, r, response, volumeLUKSWrappedKeyUpdater)
+}
+
+type VolumeLUKSKeyRevocation struct {
+	Revoked bool `json:"revoked"`
+}
+
+func volumeLUKSKeyRevocationUpdater(
+	_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, body []byte,
+) int {
+	keyResponse, ok := response.(*VolumeLUKSWrappedKeyResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type VolumeLUKSWrappedKeyResponse"))
+		return http.StatusInternalServerError
+	}
+
+	revocation := &VolumeLUKSKeyRevocation{}
+	if err := json.Unmarshal(body, revocation); err != nil {
+		keyResponse.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+		return http.StatusBadRequest
+	}
+
+	ctx := r.Context()
+	if err := orchestrator.SetVolumeLUKSKeyRevoked(ctx, vars["volume"], revocation.Revoked); err != nil {
+		keyResponse.setError(fmt.Errorf("failed to update LUKS key revocation for volume %s: %s",
+			vars["volume"], err.Error()))
+		return httpStatusCodeForGetUpdateList(err)
+	}
+
+	if volume, err := orchestrator.GetVolume(ctx, vars["volume"]); err == nil {
+		keyResponse.WrappedKey = volume.Config.LUKSWrappedKey
+	}
+
+	return http.StatusOK
+}
+
+func UpdateVolumeLUKSKeyRevocation(w http.ResponseWriter, r *http.Request) {
+	response := &VolumeLUKSWrappedKeyResponse{}
+	UpdateGeneric(w, r, response, volumeLUKSKeyRevocationUpdater)
+}
+
 type ImportVolumeResponse struct {
 	Volume *storage.VolumeExternal `json:"volume"`
 	Error  string                  `json:"error,omitempty"`
@@ -756,6 +1033,95 @@ func UpgradeVolume(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type ListLUKSPassphraseRotationsResponse struct {
+	Rotations []*storage.LUKSPassphraseRotation `json:"rotations"`
+	Error     string                            `json:"error,omitempty"`
+}
+
+func ListLUKSPassphraseRotations(w http.ResponseWriter, r *http.Request) {
+	response := &ListLUKSPassphraseRotationsResponse{}
+	GetGeneric(w, r, response,
+		func(_ map[string]string) int {
+			incompleteOnly := strings.ToLower(r.URL.Query().Get("incomplete")) == "true"
+
+			k8sHelperFrontend, err := orchestrator.GetFrontend(r.Context(), controllerhelpers.KubernetesHelper)
+			if err != nil {
+				response.Error = err.Error()
+				return httpStatusCodeForGetUpdateList(err)
+			}
+			k8sHelper, ok := k8sHelperFrontend.(k8shelper.K8SControllerHelperPlugin)
+			if !ok {
+				err = fmt.Errorf("unable to obtain K8S helper frontend")
+				response.Error = err.Error()
+				return http.StatusInternalServerError
+			}
+
+			response.Rotations = k8sHelper.ListLUKSPassphraseRotations(r.Context(), incompleteOnly)
+			return http.StatusOK
+		},
+	)
+}
+
+type ListVolumeTransactionsResponse struct {
+	Transactions []*storage.VolumeTransactionExternal `json:"transactions"`
+	Error        string                               `json:"error,omitempty"`
+}
+
+func ListVolumeTransactions(w http.ResponseWriter, r *http.Request) {
+	response := &ListVolumeTransactionsResponse{}
+	GetGeneric(w, r, response,
+		func(_ map[string]string) int {
+			txns, err := orchestrator.ListVolumeTransactions(r.Context())
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Transactions = txns
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type ListJobsResponse struct {
+	Jobs  []*storage.JobExternal `json:"jobs"`
+	Error string                 `json:"error,omitempty"`
+}
+
+func ListJobs(w http.ResponseWriter, r *http.Request) {
+	response := &ListJobsResponse{}
+	GetGeneric(w, r, response,
+		func(_ map[string]string) int {
+			jobs, err := orchestrator.ListJobs(r.Context())
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Jobs = jobs
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
+type GetJobResponse struct {
+	Job   *storage.JobExternal `json:"job"`
+	Error string               `json:"error,omitempty"`
+}
+
+func GetJob(w http.ResponseWriter, r *http.Request) {
+	response := &GetJobResponse{}
+	GetGeneric(w, r, response,
+		func(vars map[string]string) int {
+			job, err := orchestrator.GetJob(r.Context(), vars["job"])
+			if err != nil {
+				response.Error = err.Error()
+			} else {
+				response.Job = job
+			}
+			return httpStatusCodeForGetUpdateList(err)
+		},
+	)
+}
+
 type AddStorageClassResponse struct {
 	StorageClassID string `json:"storageClass"`
 	Error          string `json:"error,omitempty"`
@@ -1014,6 +1380,167 @@ func DeleteNode(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type NodeFence struct {
+	Fenced bool `json:"fenced"`
+}
+
+type UpdateNodeResponse struct {
+	Node  *utils.Node `json:"node"`
+	Error string      `json:"error,omitempty"`
+}
+
+func (r *UpdateNodeResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *UpdateNodeResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *UpdateNodeResponse) logSuccess(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "UpdateNodeFence",
+	}).Info("Updated a node.")
+}
+
+func (r *UpdateNodeResponse) logFailure(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "UpdateNodeFence",
+	}).Error(r.Error)
+}
+
+// nodeEventRecorder returns a callback that records events for a node with the CSI helper, if there is one.
+func nodeEventRecorder(ctx context.Context, nodeName string) core.NodeEventCallback {
+	csiFrontend, err := orchestrator.GetFrontend(ctx, controllerhelpers.KubernetesHelper)
+	if err != nil {
+		csiFrontend, err = orchestrator.GetFrontend(ctx, controllerhelpers.PlainCSIHelper)
+	}
+	if helper, ok := csiFrontend.(controllerhelpers.ControllerHelper); err == nil && ok {
+		return func(eventType, reason, message string) {
+			helper.RecordNodeEvent(ctx, nodeName, eventType, reason, message)
+		}
+	}
+	return func(eventType, reason, message string) {}
+}
+
+func nodeFenceUpdater(
+	_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, body []byte,
+) int {
+	updateResponse, ok := response.(*UpdateNodeResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type UpdateNodeResponse"))
+		return http.StatusInternalServerError
+	}
+
+	fence := new(NodeFence)
+	if err := json.Unmarshal(body, fence); err != nil {
+		updateResponse.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+		return http.StatusBadRequest
+	}
+
+	nodeName := vars["node"]
+	nodeEventCallback := nodeEventRecorder(r.Context(), nodeName)
+
+	var err error
+	if fence.Fenced {
+		err = orchestrator.FenceNode(r.Context(), nodeName, nodeEventCallback)
+	} else {
+		err = orchestrator.UnfenceNode(r.Context(), nodeName, nodeEventCallback)
+	}
+	if err != nil {
+		updateResponse.setError(fmt.Errorf("failed to update fencing of node %s: %s", nodeName, err.Error()))
+		return httpStatusCodeForGetUpdateList(err)
+	}
+
+	node, err := orchestrator.GetNode(r.Context(), nodeName)
+	if err != nil {
+		updateResponse.setError(err)
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	updateResponse.Node = node
+
+	return http.StatusOK
+}
+
+func UpdateNodeFence(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateNodeResponse{}
+	UpdateGeneric(w, r, response, nodeFenceUpdater)
+}
+
+type CheckNodeResponse struct {
+	Report *utils.NodeCheckReport `json:"report,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+func (r *CheckNodeResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *CheckNodeResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *CheckNodeResponse) logSuccess(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "CheckNode",
+		"node":    r.Report.Node,
+	}).Info("Checked a node.")
+}
+
+func (r *CheckNodeResponse) logFailure(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "CheckNode",
+	}).Error(r.Error)
+}
+
+func nodeChecker(
+	_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, body []byte,
+) int {
+	checkResponse, ok := response.(*CheckNodeResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type CheckNodeResponse"))
+		return http.StatusInternalServerError
+	}
+
+	request := new(nodeAPI.NodeCheckRequest)
+	if err := json.Unmarshal(body, request); err != nil {
+		checkResponse.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+		return http.StatusBadRequest
+	}
+	if request.DryRun && !request.Repair {
+		checkResponse.setError(fmt.Errorf("a dry run applies only to repairs"))
+		return http.StatusBadRequest
+	}
+
+	// Only the K8S helper can reach the REST interfaces of the nodes
+	k8sHelperFrontend, err := orchestrator.GetFrontend(r.Context(), controllerhelpers.KubernetesHelper)
+	if err != nil {
+		checkResponse.setError(fmt.Errorf("node checks require Kubernetes; %v", err))
+		return http.StatusBadRequest
+	}
+	k8sHelper, ok := k8sHelperFrontend.(k8shelper.K8SControllerHelperPlugin)
+	if !ok {
+		checkResponse.setError(fmt.Errorf("unable to obtain K8S helper frontend"))
+		return http.StatusInternalServerError
+	}
+
+	nodeName := vars["node"]
+	report, err := k8sHelper.CheckNode(r.Context(), nodeName, request)
+	if err != nil {
+		checkResponse.setError(fmt.Errorf("failed to check node %s: %s", nodeName, err.Error()))
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	checkResponse.Report = report
+
+	return http.StatusOK
+}
+
+// CheckNode asks a node to check, and optionally repair, its volume tracking files against its kernel state.
+func CheckNode(w http.ResponseWriter, r *http.Request) {
+	response := &CheckNodeResponse{}
+	UpdateGeneric(w, r, response, nodeChecker)
+}
+
 type VolumePublicationResponse struct {
 	VolumePublication *utils.VolumePublicationExternal `json:"volumePublication"`
 	Error             string                           `json:"error,omitempty"`
@@ -1180,6 +1707,85 @@ func UpdateVolumePublication(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+type PublishVolumeResponse struct {
+	PublishInfo *utils.VolumePublishInfo `json:"publishInfo"`
+	Error       string                   `json:"error,omitempty"`
+}
+
+func (r *PublishVolumeResponse) setError(err error) {
+	r.Error = err.Error()
+}
+
+func (r *PublishVolumeResponse) isError() bool {
+	return r.Error != ""
+}
+
+func (r *PublishVolumeResponse) logSuccess(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "PublishVolume",
+	}).Info("Published a volume.")
+}
+
+func (r *PublishVolumeResponse) logFailure(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"handler": "PublishVolume",
+	}).Error(r.Error)
+}
+
+// volumePublisher publishes a volume to a node on behalf of that node, which is how CSI ephemeral volumes are
+// attached, as no ControllerPublishVolume call is made for them.  The request body supplies the read-only flag and
+// access mode, and the node's details are filled in from its registration.
+func volumePublisher(_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, body []byte) int {
+	publishResponse, ok := response.(*PublishVolumeResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type PublishVolumeResponse"))
+		return http.StatusInternalServerError
+	}
+	request := new(utils.VolumePublishInfo)
+	if err := json.Unmarshal(body, request); err != nil {
+		publishResponse.setError(fmt.Errorf("invalid JSON: %s", err.Error()))
+		return httpStatusCodeForGetUpdateList(err)
+	}
+
+	volume, err := orchestrator.GetVolume(r.Context(), vars["volume"])
+	if err != nil {
+		publishResponse.setError(err)
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	node, err := orchestrator.GetNode(r.Context(), vars["node"])
+	if err != nil {
+		publishResponse.setError(err)
+		return httpStatusCodeForGetUpdateList(err)
+	}
+
+	publishInfo := &utils.VolumePublishInfo{
+		Localhost:      false,
+		HostIQN:        []string{node.IQN},
+		HostIP:         node.IPs,
+		HostName:       node.Name,
+		Unmanaged:      volume.Config.ImportNotManaged,
+		LUKSEncryption: volume.Config.LUKSEncryption,
+	}
+	publishInfo.ReadOnly = request.ReadOnly
+	publishInfo.AccessMode = request.AccessMode
+	if err = orchestrator.PublishVolume(r.Context(), volume.Config.Name, publishInfo); err != nil {
+		publishResponse.setError(err)
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	publishResponse.PublishInfo = publishInfo
+	return http.StatusOK
+}
+
+func PublishVolume(w http.ResponseWriter, r *http.Request) {
+	UpdateGeneric(w, r, &PublishVolumeResponse{}, volumePublisher)
+}
+
+func UnpublishVolume(w http.ResponseWriter, r *http.Request) {
+	DeleteGeneric(w, r, func(ctx context.Context, vars map[string]string) error {
+		return orchestrator.UnpublishVolume(ctx, vars["volume"], vars["node"])
+	})
+}
+
 type GetSnapshotResponse struct {
 	Snapshot *storage.SnapshotExternal `json:"snapshot"`
 	Error    string                    `json:"error,omitempty"`
@@ -1307,6 +1913,35 @@ func DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func snapshotRestorer(
+	_ http.ResponseWriter, r *http.Request, response httpResponse, vars map[string]string, _ []byte,
+) int {
+	updateResponse, ok := response.(*UpdateVolumeResponse)
+	if !ok {
+		response.setError(fmt.Errorf("response object must be of type UpdateVolumeResponse"))
+		return http.StatusInternalServerError
+	}
+
+	if err := orchestrator.RestoreSnapshot(r.Context(), vars["volume"], vars["snapshot"]); err != nil {
+		updateResponse.setError(err)
+		return httpStatusCodeForGetUpdateList(err)
+	}
+
+	volume, err := orchestrator.GetVolume(r.Context(), vars["volume"])
+	if err != nil {
+		updateResponse.setError(err)
+		return httpStatusCodeForGetUpdateList(err)
+	}
+	updateResponse.Volume = volume
+
+	return http.StatusOK
+}
+
+func RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+	response := &UpdateVolumeResponse{}
+	UpdateGeneric(w, r, response, snapshotRestorer)
+}
+
 type GetCHAPResponse struct {
 	CHAP  *utils.IscsiChapInfo `json:"chap"`
 	Error string               `json:"error,omitempty"`
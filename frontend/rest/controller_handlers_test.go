@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +12,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	http_test "github.com/stretchr/testify/http"
 
+	controllerhelpers "github.com/netapp/trident/frontend/csi/controller_helpers"
+	k8shelper "github.com/netapp/trident/frontend/csi/controller_helpers/kubernetes"
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
 	mockcore "github.com/netapp/trident/mocks/mock_core"
 	"github.com/netapp/trident/storage"
 	"github.com/netapp/trident/utils"
@@ -174,3 +178,326 @@ func TestVolumeLUKSPassphraseNamesUpdater(t *testing.T) {
 	assert.Equal(t, volume, response.Volume)
 	mockCtrl.Finish()
 }
+
+func TestVolumeLUKSWrappedKeyUpdater(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	orchestrator = mockOrchestrator
+
+	// Positive case: wrapped key recorded
+	wrappedKey := &utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "local:abcd:wrapped"}
+	writer := &http_test.TestResponseWriter{}
+	response := &VolumeLUKSWrappedKeyResponse{}
+	body := `{"provider":"local","keyName":"trident","wrappedKey":"local:abcd:wrapped"}`
+	request := generateHTTPRequest(http.MethodPut, body)
+	mockOrchestrator.EXPECT().UpdateVolumeLUKSWrappedKey(request.Context(), "test", wrappedKey, "").Return(nil)
+
+	rc := volumeLUKSWrappedKeyUpdater(writer, request, response, map[string]string{"volume": "test"}, []byte(body))
+
+	assert.Equal(t, http.StatusOK, rc)
+	assert.Equal(t, wrappedKey, response.WrappedKey)
+	assert.Equal(t, "", response.Error)
+
+	// Negative case: invalid JSON
+	response = &VolumeLUKSWrappedKeyResponse{}
+	body = `["not a key"]`
+	request = generateHTTPRequest(http.MethodPut, body)
+
+	rc = volumeLUKSWrappedKeyUpdater(writer, request, response, map[string]string{"volume": "test"}, []byte(body))
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+	assert.NotEqual(t, "", response.Error)
+
+	// Negative case: volume not found
+	response = &VolumeLUKSWrappedKeyResponse{}
+	body = `{"provider":"local","keyName":"trident","wrappedKey":"local:abcd:wrapped"}`
+	request = generateHTTPRequest(http.MethodPut, body)
+	mockOrchestrator.EXPECT().UpdateVolumeLUKSWrappedKey(request.Context(), "test", wrappedKey, "").
+		Return(utils.NotFoundError("not found"))
+
+	rc = volumeLUKSWrappedKeyUpdater(writer, request, response, map[string]string{"volume": "test"}, []byte(body))
+
+	assert.Equal(t, http.StatusNotFound, rc)
+	assert.NotEqual(t, "", response.Error)
+
+	// Negative case: key replaced by another node since it was read
+	response = &VolumeLUKSWrappedKeyResponse{}
+	body = `{"provider":"local","keyName":"trident","wrappedKey":"local:abcd:wrapped","previousWrappedKey":"old"}`
+	request = generateHTTPRequest(http.MethodPut, body)
+	mockOrchestrator.EXPECT().UpdateVolumeLUKSWrappedKey(request.Context(), "test", wrappedKey, "old").
+		Return(utils.FoundError("replaced"))
+
+	rc = volumeLUKSWrappedKeyUpdater(writer, request, response, map[string]string{"volume": "test"}, []byte(body))
+
+	assert.Equal(t, http.StatusConflict, rc)
+	assert.NotEqual(t, "", response.Error)
+
+	// Negative case: invalid response object provided
+	rc = volumeLUKSWrappedKeyUpdater(writer, request, &UpdateVolumeResponse{}, map[string]string{"volume": "test"},
+		[]byte(body))
+
+	assert.Equal(t, http.StatusInternalServerError, rc)
+}
+
+func TestVolumeLUKSKeyRevocationUpdater(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	orchestrator = mockOrchestrator
+
+	// Positive case: key revoked
+	wrappedKey := &utils.LUKSWrappedKey{Provider: "local", KeyName: "trident", WrappedKey: "wrapped", Revoked: true}
+	volume := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "test", LUKSWrappedKey: wrappedKey}}
+	writer := &http_test.TestResponseWriter{}
+	response := &VolumeLUKSWrappedKeyResponse{}
+	body := `{"revoked":true}`
+	request := generateHTTPRequest(http.MethodPut, body)
+	mockOrchestrator.EXPECT().SetVolumeLUKSKeyRevoked(request.Context(), "test", true).Return(nil)
+	mockOrchestrator.EXPECT().GetVolume(request.Context(), "test").Return(volume, nil)
+
+	rc := volumeLUKSKeyRevocationUpdater(writer, request, response, map[string]string{"volume": "test"}, []byte(body))
+
+	assert.Equal(t, http.StatusOK, rc)
+	assert.Equal(t, wrappedKey, response.WrappedKey)
+
+	// Negative case: volume has no wrapped key
+	response = &VolumeLUKSWrappedKeyResponse{}
+	mockOrchestrator.EXPECT().SetVolumeLUKSKeyRevoked(request.Context(), "test", true).
+		Return(utils.NotFoundError("no key"))
+
+	rc = volumeLUKSKeyRevocationUpdater(writer, request, response, map[string]string{"volume": "test"}, []byte(body))
+
+	assert.Equal(t, http.StatusNotFound, rc)
+	assert.NotEqual(t, "", response.Error)
+
+	// Negative case: invalid JSON
+	response = &VolumeLUKSWrappedKeyResponse{}
+	body = `"revoked"`
+
+	rc = volumeLUKSKeyRevocationUpdater(writer, request, response, map[string]string{"volume": "test"}, []byte(body))
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+}
+
+func TestVolumeReplicaPromoter(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	orchestrator = mockOrchestrator
+
+	// Positive case: replica promoted
+	volume := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "test", ReplicaVolume: "test-replica"}}
+	writer := &http_test.TestResponseWriter{}
+	response := &UpdateVolumeResponse{}
+	request := generateHTTPRequest(http.MethodPost, "")
+	mockOrchestrator.EXPECT().PromoteVolumeReplica(request.Context(), "test").Return(volume, nil)
+
+	rc := volumeReplicaPromoter(writer, request, response, map[string]string{"volume": "test"}, nil)
+
+	assert.Equal(t, http.StatusOK, rc)
+	assert.Equal(t, volume, response.Volume)
+
+	// Negative case: volume has no replica
+	response = &UpdateVolumeResponse{}
+	mockOrchestrator.EXPECT().PromoteVolumeReplica(request.Context(), "test").
+		Return(nil, utils.InvalidInputError("no replica"))
+
+	rc = volumeReplicaPromoter(writer, request, response, map[string]string{"volume": "test"}, nil)
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+	assert.NotEqual(t, "", response.Error)
+
+	// Negative case: volume not found
+	response = &UpdateVolumeResponse{}
+	mockOrchestrator.EXPECT().PromoteVolumeReplica(request.Context(), "test").
+		Return(nil, utils.NotFoundError("not found"))
+
+	rc = volumeReplicaPromoter(writer, request, response, map[string]string{"volume": "test"}, nil)
+
+	assert.Equal(t, http.StatusNotFound, rc)
+
+	// Negative case: invalid response object provided
+	rc = volumeReplicaPromoter(writer, request, &VolumeLUKSWrappedKeyResponse{}, map[string]string{"volume": "test"},
+		nil)
+
+	assert.Equal(t, http.StatusInternalServerError, rc)
+}
+
+func TestNodeFenceUpdater(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	orchestrator = mockOrchestrator
+	mockOrchestrator.EXPECT().GetFrontend(gomock.Any(), gomock.Any()).
+		Return(nil, utils.NotFoundError("no frontend")).AnyTimes()
+
+	// Positive case: node fenced
+	node := &utils.Node{Name: "node1", Fenced: true}
+	writer := &http_test.TestResponseWriter{}
+	response := &UpdateNodeResponse{}
+	body := `{"fenced":true}`
+	request := generateHTTPRequest(http.MethodPut, body)
+	mockOrchestrator.EXPECT().FenceNode(request.Context(), "node1", gomock.Any()).Return(nil)
+	mockOrchestrator.EXPECT().GetNode(request.Context(), "node1").Return(node, nil)
+
+	rc := nodeFenceUpdater(writer, request, response, map[string]string{"node": "node1"}, []byte(body))
+
+	assert.Equal(t, http.StatusOK, rc)
+	assert.Equal(t, node, response.Node)
+
+	// Negative case: node not found
+	response = &UpdateNodeResponse{}
+	body = `{"fenced":false}`
+	mockOrchestrator.EXPECT().UnfenceNode(request.Context(), "node1", gomock.Any()).
+		Return(utils.NotFoundError("not found"))
+
+	rc = nodeFenceUpdater(writer, request, response, map[string]string{"node": "node1"}, []byte(body))
+
+	assert.Equal(t, http.StatusNotFound, rc)
+	assert.NotEqual(t, "", response.Error)
+
+	// Negative case: invalid JSON
+	response = &UpdateNodeResponse{}
+	body = `"fenced"`
+
+	rc = nodeFenceUpdater(writer, request, response, map[string]string{"node": "node1"}, []byte(body))
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+}
+
+func TestVolumePublisher(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	orchestrator = mockOrchestrator
+
+	volume := &storage.VolumeExternal{Config: &storage.VolumeConfig{Name: "vol1", LUKSEncryption: "false"}}
+	node := &utils.Node{Name: "node1", IQN: "iqn.node1", IPs: []string{"1.1.1.1"}}
+	vars := map[string]string{"volume": "vol1", "node": "node1"}
+
+	// Positive case: publish info returned with node details
+	writer := &http_test.TestResponseWriter{}
+	response := &PublishVolumeResponse{}
+	body := `{"readOnly":true,"accessMode":1}`
+	request := generateHTTPRequest(http.MethodPost, body)
+	mockOrchestrator.EXPECT().GetVolume(request.Context(), "vol1").Return(volume, nil)
+	mockOrchestrator.EXPECT().GetNode(request.Context(), "node1").Return(node, nil)
+	mockOrchestrator.EXPECT().PublishVolume(request.Context(), "vol1", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, publishInfo *utils.VolumePublishInfo) error {
+			publishInfo.NfsPath = "/vol1"
+			return nil
+		})
+
+	rc := volumePublisher(writer, request, response, vars, []byte(body))
+
+	assert.Equal(t, http.StatusOK, rc)
+	assert.Equal(t, "node1", response.PublishInfo.HostName)
+	assert.Equal(t, []string{"iqn.node1"}, response.PublishInfo.HostIQN)
+	assert.True(t, response.PublishInfo.ReadOnly)
+	assert.Equal(t, int32(1), response.PublishInfo.AccessMode)
+	assert.Equal(t, "/vol1", response.PublishInfo.NfsPath)
+
+	// Negative case: node not registered
+	response = &PublishVolumeResponse{}
+	mockOrchestrator.EXPECT().GetVolume(request.Context(), "vol1").Return(volume, nil)
+	mockOrchestrator.EXPECT().GetNode(request.Context(), "node1").Return(nil, utils.NotFoundError("not found"))
+
+	rc = volumePublisher(writer, request, response, vars, []byte(body))
+
+	assert.Equal(t, http.StatusNotFound, rc)
+	assert.NotEqual(t, "", response.Error)
+
+	// Negative case: publish failed
+	response = &PublishVolumeResponse{}
+	mockOrchestrator.EXPECT().GetVolume(request.Context(), "vol1").Return(volume, nil)
+	mockOrchestrator.EXPECT().GetNode(request.Context(), "node1").Return(node, nil)
+	mockOrchestrator.EXPECT().PublishVolume(request.Context(), "vol1", gomock.Any()).
+		Return(fmt.Errorf("node node1 is fenced"))
+
+	rc = volumePublisher(writer, request, response, vars, []byte(body))
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+	assert.Nil(t, response.PublishInfo)
+
+	// Negative case: invalid JSON
+	response = &PublishVolumeResponse{}
+
+	rc = volumePublisher(writer, request, response, vars, []byte(`"readOnly"`))
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+}
+
+// fakeK8SHelper is a K8S helper frontend that answers node checks.
+type fakeK8SHelper struct {
+	k8shelper.K8SControllerHelperPlugin
+	report *utils.NodeCheckReport
+	err    error
+}
+
+func (f *fakeK8SHelper) CheckNode(
+	_ context.Context, nodeName string, request *nodeAPI.NodeCheckRequest,
+) (*utils.NodeCheckReport, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.report.Node = nodeName
+	f.report.Repair = request.Repair
+	f.report.DryRun = request.DryRun
+	return f.report, nil
+}
+
+func TestNodeChecker(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockOrchestrator := mockcore.NewMockOrchestrator(mockCtrl)
+	orchestrator = mockOrchestrator
+
+	vars := map[string]string{"node": "node1"}
+	writer := &http_test.TestResponseWriter{}
+	helper := &fakeK8SHelper{report: &utils.NodeCheckReport{}}
+
+	// Positive case: report returned
+	response := &CheckNodeResponse{}
+	body := `{"repair":true,"dryRun":true}`
+	request := generateHTTPRequest(http.MethodPost, body)
+	mockOrchestrator.EXPECT().GetFrontend(request.Context(), controllerhelpers.KubernetesHelper).Return(helper, nil)
+
+	rc := nodeChecker(writer, request, response, vars, []byte(body))
+
+	assert.Equal(t, http.StatusOK, rc)
+	assert.Equal(t, &utils.NodeCheckReport{Node: "node1", Repair: true, DryRun: true}, response.Report)
+
+	// Negative case: node not found
+	response = &CheckNodeResponse{}
+	helper.err = utils.NotFoundError("not found")
+	mockOrchestrator.EXPECT().GetFrontend(request.Context(), controllerhelpers.KubernetesHelper).Return(helper, nil)
+
+	rc = nodeChecker(writer, request, response, vars, []byte(body))
+
+	assert.Equal(t, http.StatusNotFound, rc)
+	assert.NotEqual(t, "", response.Error)
+
+	// Negative case: not running in Kubernetes
+	response = &CheckNodeResponse{}
+	mockOrchestrator.EXPECT().GetFrontend(request.Context(), controllerhelpers.KubernetesHelper).
+		Return(nil, utils.NotFoundError("no frontend"))
+
+	rc = nodeChecker(writer, request, response, vars, []byte(body))
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+
+	// Negative case: dry run without repair
+	response = &CheckNodeResponse{}
+
+	rc = nodeChecker(writer, request, response, vars, []byte(`{"dryRun":true}`))
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+
+	// Negative case: invalid JSON
+	response = &CheckNodeResponse{}
+
+	rc = nodeChecker(writer, request, response, vars, []byte(`"repair"`))
+
+	assert.Equal(t, http.StatusBadRequest, rc)
+}
@@ -119,6 +119,55 @@ var controllerRoutes = Routes{
 		nil,
 		UpdateVolumeLUKSPassphraseNames,
 	},
+	Route{
+		"GetVolumeLUKSWrappedKey",
+		"GET",
+		config.VolumeURL + "/{volume}/luksWrappedKey",
+		nil,
+		GetVolumeLUKSWrappedKey,
+	},
+	Route{
+		"UpdateVolumeLUKSWrappedKey",
+		"PUT",
+		config.VolumeURL + "/{volume}/luksWrappedKey",
+		nil,
+		UpdateVolumeLUKSWrappedKey,
+	},
+	Route{
+		"UpdateVolumeLUKSKeyRevocation",
+		"PUT",
+		config.VolumeURL + "/{volume}/luksKeyRevocation",
+		nil,
+		UpdateVolumeLUKSKeyRevocation,
+	},
+	Route{
+		"PromoteVolumeReplica",
+		"POST",
+		config.VolumeURL + "/{volume}/promoteReplica",
+		nil,
+		PromoteVolumeReplica,
+	},
+	Route{
+		"ResizeVolume",
+		"PUT",
+		config.VolumeURL + "/{volume}/size",
+		nil,
+		ResizeVolume,
+	},
+	Route{
+		"TakeOverVolume",
+		"POST",
+		config.VolumeURL + "/{volume}/takeover",
+		nil,
+		TakeOverVolume,
+	},
+	Route{
+		"ExplainVolumePlacement",
+		"POST",
+		config.VolumeURL + "/explain",
+		nil,
+		ExplainVolumePlacement,
+	},
 	Route{
 		"ImportVolume",
 		"POST",
@@ -133,6 +182,34 @@ var controllerRoutes = Routes{
 		nil,
 		UpgradeVolume,
 	},
+	Route{
+		"ListLUKSPassphraseRotations",
+		"GET",
+		config.LUKSRotationURL,
+		nil,
+		ListLUKSPassphraseRotations,
+	},
+	Route{
+		"ListVolumeTransactions",
+		"GET",
+		config.TransactionURL,
+		nil,
+		ListVolumeTransactions,
+	},
+	Route{
+		"ListJobs",
+		"GET",
+		config.JobURL,
+		nil,
+		ListJobs,
+	},
+	Route{
+		"GetJob",
+		"GET",
+		config.JobURL + "/{job}",
+		nil,
+		GetJob,
+	},
 	Route{
 		"AddStorageClass",
 		"POST",
@@ -189,6 +266,20 @@ var controllerRoutes = Routes{
 		nil,
 		DeleteNode,
 	},
+	Route{
+		"UpdateNodeFence",
+		"PUT",
+		config.NodeURL + "/{node}/fence",
+		nil,
+		UpdateNodeFence,
+	},
+	Route{
+		"CheckNode",
+		"POST",
+		config.NodeURL + "/{node}/check",
+		nil,
+		CheckNode,
+	},
 	Route{
 		"GetVolumePublication",
 		"GET",
@@ -226,6 +317,20 @@ var controllerRoutes = Routes{
 		},
 		UpdateVolumePublication,
 	},
+	Route{
+		"PublishVolume",
+		"POST",
+		config.PublicationURL + "/{volume}/{node}",
+		nil,
+		PublishVolume,
+	},
+	Route{
+		"UnpublishVolume",
+		"DELETE",
+		config.PublicationURL + "/{volume}/{node}",
+		nil,
+		UnpublishVolume,
+	},
 	Route{
 		"ListSnapshots",
 		"GET",
@@ -261,6 +366,13 @@ var controllerRoutes = Routes{
 		nil,
 		DeleteSnapshot,
 	},
+	Route{
+		"RestoreSnapshot",
+		"POST",
+		config.SnapshotURL + "/{volume}/{snapshot}/restore",
+		nil,
+		RestoreSnapshot,
+	},
 	Route{
 		"GetCHAP",
 		"GET",
@@ -3,6 +3,7 @@
 package rest
 
 import (
+	"fmt"
 	"math"
 	"net/http"
 	"strconv"
@@ -10,6 +11,8 @@ import (
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
+
+	"github.com/netapp/trident/config"
 )
 
 const logInterval = 10 * time.Second
@@ -46,3 +49,24 @@ func rateLimiterMiddleware(r rate.Limit, b int) func(http.Handler) http.Handler
 		})
 	}
 }
+
+// controllerAuthMiddleware rejects requests that did not come from the Trident controller, that is, requests
+// without a client certificate that was verified against the Trident CA and that names the controller.  Nodes
+// have neither the controller's key nor the CA key, so they cannot call these routes on other nodes.  It
+// protects individual routes on servers that do not require mutual TLS for every request, such as the node
+// server, whose liveness and readiness probes are unauthenticated.
+func controllerAuthMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 ||
+				r.TLS.VerifiedChains[0][0].Subject.CommonName != config.ControllerCertName {
+				log.WithField("path", r.URL.Path).Warn("Rejected request without the controller's client certificate.")
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=\"%s\"", config.OrchestratorName))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
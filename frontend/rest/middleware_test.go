@@ -1,12 +1,17 @@
 package rest
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"net/http"
 	"net/http/httptest"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
 )
 
 func TestRateLimiterMiddleware(t *testing.T) {
@@ -43,3 +48,47 @@ func TestRateLimiterMiddleware(t *testing.T) {
 	assert.True(t, someRetryAfter, "no retry after in any response")
 	assert.Contains(t, s, http.StatusTooManyRequests)
 }
+
+func TestControllerAuthMiddleware(t *testing.T) {
+	handler := controllerAuthMiddleware()(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	verifiedCert := func(commonName string) *tls.ConnectionState {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		return &tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert, {Subject: pkix.Name{CommonName: config.CACertName}}}},
+		}
+	}
+	unverifiedCert := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: config.ControllerCertName}}},
+	}
+
+	tests := []struct {
+		name       string
+		tlsState   *tls.ConnectionState
+		statusCode int
+	}{
+		{"NoTLS", nil, http.StatusUnauthorized},
+		{"NoClientCert", &tls.ConnectionState{}, http.StatusUnauthorized},
+		{"UnverifiedClientCert", unverifiedCert, http.StatusUnauthorized},
+		{"WrongCommonName", verifiedCert("someone-else"), http.StatusUnauthorized},
+		{"NodeClientCert", verifiedCert(config.ClientCertName), http.StatusUnauthorized},
+		{"ControllerClientCert", verifiedCert(config.ControllerCertName), http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			r.TLS = test.tlsState
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, test.statusCode, w.Code)
+			if test.statusCode == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
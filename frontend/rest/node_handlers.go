@@ -4,10 +4,17 @@ package rest
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 
+	"github.com/gorilla/mux"
+
+	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend/csi"
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
 	. "github.com/netapp/trident/logger"
+	"github.com/netapp/trident/utils"
 )
 
 // helper method to log HTTP write failures if an error is seen
@@ -36,3 +43,112 @@ func NodeReadinessCheck(plugin *csi.Plugin) http.HandlerFunc {
 		}
 	}
 }
+
+// RotateLUKSPassphrase is the node endpoint the controller uses to rotate the passphrase of a staged LUKS volume
+func RotateLUKSPassphrase(plugin *csi.Plugin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		response := &nodeAPI.RotateLUKSPassphraseResponse{}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+		if err != nil {
+			response.Error = err.Error()
+			writeHTTPResponse(r.Context(), w, response, http.StatusBadRequest)
+			return
+		}
+
+		request := &nodeAPI.RotateLUKSPassphraseRequest{}
+		if err = json.Unmarshal(body, request); err != nil {
+			response.Error = utils.InvalidJSONError(err.Error()).Error()
+			writeHTTPResponse(r.Context(), w, response, http.StatusBadRequest)
+			return
+		}
+
+		httpStatusCode := http.StatusOK
+		err = plugin.RotateLUKSPassphrase(r.Context(), mux.Vars(r)["volume"], request.InternalName, request.Secrets)
+		if err != nil {
+			response.Error = err.Error()
+			switch {
+			case utils.IsNotFoundError(err):
+				httpStatusCode = http.StatusNotFound
+			case utils.IsInvalidInputError(err), utils.IsUnsupportedError(err):
+				httpStatusCode = http.StatusBadRequest
+			default:
+				httpStatusCode = http.StatusInternalServerError
+			}
+			Logc(r.Context()).WithError(err).Error("Could not rotate LUKS passphrase.")
+		}
+		writeHTTPResponse(r.Context(), w, response, httpStatusCode)
+	}
+}
+
+// PreemptReservationKeys is the node endpoint the controller uses to preempt the persistent reservation keys of
+// nodes force detached from a staged volume
+func PreemptReservationKeys(plugin *csi.Plugin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		response := &nodeAPI.PreemptReservationKeysResponse{}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+		if err != nil {
+			response.Error = err.Error()
+			writeHTTPResponse(r.Context(), w, response, http.StatusBadRequest)
+			return
+		}
+
+		request := &nodeAPI.PreemptReservationKeysRequest{}
+		if err = json.Unmarshal(body, request); err != nil {
+			response.Error = utils.InvalidJSONError(err.Error()).Error()
+			writeHTTPResponse(r.Context(), w, response, http.StatusBadRequest)
+			return
+		}
+
+		httpStatusCode := http.StatusOK
+		err = plugin.PreemptReservationKeys(r.Context(), mux.Vars(r)["volume"], request.ReservationKeys)
+		if err != nil {
+			response.Error = err.Error()
+			switch {
+			case utils.IsNotFoundError(err):
+				httpStatusCode = http.StatusNotFound
+			case utils.IsInvalidInputError(err), utils.IsUnsupportedError(err):
+				httpStatusCode = http.StatusBadRequest
+			default:
+				httpStatusCode = http.StatusInternalServerError
+			}
+			Logc(r.Context()).WithError(err).Error("Could not preempt reservation keys.")
+		}
+		writeHTTPResponse(r.Context(), w, response, httpStatusCode)
+	}
+}
+
+// NodeCheck is the node endpoint the controller uses to check, and optionally repair, the node's volume tracking
+// files against its kernel state
+func NodeCheck(plugin *csi.Plugin) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		response := &nodeAPI.CheckNodeResponse{}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, config.MaxRESTRequestSize))
+		if err != nil {
+			response.Error = err.Error()
+			writeHTTPResponse(r.Context(), w, response, http.StatusBadRequest)
+			return
+		}
+
+		request := &nodeAPI.NodeCheckRequest{}
+		if err = json.Unmarshal(body, request); err != nil {
+			response.Error = utils.InvalidJSONError(err.Error()).Error()
+			writeHTTPResponse(r.Context(), w, response, http.StatusBadRequest)
+			return
+		}
+
+		httpStatusCode := http.StatusOK
+		response.Report, err = plugin.CheckNode(r.Context(), request.Repair, request.DryRun, request.ISCSITargets)
+		if err != nil {
+			response.Error = err.Error()
+			httpStatusCode = http.StatusInternalServerError
+			Logc(r.Context()).WithError(err).Error("Could not check node.")
+		}
+		writeHTTPResponse(r.Context(), w, response, httpStatusCode)
+	}
+}
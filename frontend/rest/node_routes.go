@@ -3,6 +3,9 @@
 package rest
 
 import (
+	"github.com/gorilla/mux"
+
+	"github.com/netapp/trident/config"
 	"github.com/netapp/trident/frontend/csi"
 )
 
@@ -22,5 +25,32 @@ func nodeRoutes(plugin *csi.Plugin) []Route {
 			nil,
 			NodeReadinessCheck(plugin),
 		},
+		Route{
+			"RotateLUKSPassphrase",
+			"POST",
+			config.VolumeURL + "/{volume}/luksPassphrase",
+			[]mux.MiddlewareFunc{
+				controllerAuthMiddleware(),
+			},
+			RotateLUKSPassphrase(plugin),
+		},
+		Route{
+			"PreemptReservationKeys",
+			"POST",
+			config.VolumeURL + "/{volume}/reservation/preempt",
+			[]mux.MiddlewareFunc{
+				controllerAuthMiddleware(),
+			},
+			PreemptReservationKeys(plugin),
+		},
+		Route{
+			"CheckNode",
+			"POST",
+			config.NodeURL + "/{node}/check",
+			[]mux.MiddlewareFunc{
+				controllerAuthMiddleware(),
+			},
+			NodeCheck(plugin),
+		},
 	}
 }
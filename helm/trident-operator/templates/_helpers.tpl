@@ -97,6 +97,17 @@ Trident force detach
 {{- end }}
 {{- end }}
 
+{{/*
+Trident multipath configuration management
+*/}}
+{{- define "trident.manageMultipathConfig" -}}
+{{- if .Values.manageMultipathConfig | printf "%v" | eq "true" }}
+{{- "true" }}
+{{- else }}
+{{- "false" }}
+{{- end }}
+{{- end }}
+
 {{/*
 Trident IPv6
 */}}
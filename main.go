@@ -3,6 +3,7 @@
 package main
 
 import (
+	"crypto/tls"
 	"errors"
 	"flag"
 	"fmt"
@@ -68,6 +69,7 @@ var (
 
 	csiUnsafeNodeDetach = flag.Bool("csi_unsafe_detach", false, "Prefer to detach successfully rather than safely")
 	enableForceDetach   = new(bool)
+	manageMultipath     = new(bool)
 	nodePrep            = flag.Bool("node_prep", true, "Attempt to install required packages on nodes.")
 
 	// Persistence
@@ -94,6 +96,10 @@ var (
 	httpsClientKey  = flag.String("https_client_key", config.ClientKeyPath, "HTTPS client private key")
 	httpsClientCert = flag.String("https_client_cert", config.ClientCertPath, "HTTPS client certificate")
 
+	// The controller identifies itself to the nodes' HTTPS REST interfaces with its own client certificate
+	httpsControllerKey  = flag.String("https_controller_key", config.ControllerKeyPath, "HTTPS controller private key")
+	httpsControllerCert = flag.String("https_controller_cert", config.ControllerCertPath, "HTTPS controller certificate")
+
 	aesKey = flag.String("aes_key", config.AESKeyPath, "AES encryption key")
 
 	// HTTP metrics interface
@@ -107,6 +113,21 @@ var (
 	iSCSISelfHealingWaitTime = flag.Duration("iscsi_self_healing_wait_time",
 		config.ISCSISelfHealingWaitTime,
 		"Wait time after which iSCSI self-healing attempts to fix stale sessions")
+	iSCSIPathRescan = flag.Bool("iscsi_path_rescan", true,
+		"Rescan failed iSCSI paths whose sessions are logged in during iSCSI self-healing")
+	iSCSIPathReinstate = flag.Bool("iscsi_path_reinstate", true,
+		"Reinstate iSCSI paths that multipathd has failed but its path checker finds usable during iSCSI self-healing")
+	iSCSILostPathRemovalTime = flag.Duration("iscsi_lost_path_removal_time", 0,
+		"Time after which iSCSI self-healing removes a failed iSCSI path if the volume has another healthy path; "+
+			"0 disables removal")
+
+	// Volume jobs
+	volumeJobWorkers = flag.Int("volume_job_workers", config.VolumeJobWorkers,
+		"Number of volume create and clone jobs that may run at once")
+	volumeJobBackendConcurrency = flag.Int("volume_job_backend_concurrency", config.VolumeJobBackendConcurrency,
+		"Number of volume create and clone jobs that may be in flight on any one backend; 0 removes the limit")
+	volumeJobWaitTime = flag.Duration("volume_job_wait_time", config.VolumeJobWaitTime,
+		"Time CreateVolume waits for a volume job to finish before asking the CO to retry")
 
 	storeClient  persistentstore.Client
 	enableDocker bool
@@ -246,6 +267,8 @@ func main() {
 	// These features are only supported on Linux.
 	if runtime.GOOS == "linux" {
 		enableForceDetach = flag.Bool("enable_force_detach", false, "Enable force detach feature.")
+		manageMultipath = flag.Bool("manage_multipath_config", false,
+			"Write a Trident-managed multipath drop-in configuration on nodes.")
 	}
 
 	flag.Parse()
@@ -296,6 +319,9 @@ func main() {
 	processCmdLineArgs()
 
 	orchestrator := core.NewTridentOrchestrator(storeClient)
+	if err = orchestrator.SetJobLimits(*volumeJobWorkers, *volumeJobBackendConcurrency); err != nil {
+		log.Fatalf("Invalid volume job limits. %v", err)
+	}
 
 	// Create HTTP metrics frontend
 	if *enableMetrics {
@@ -308,7 +334,7 @@ func main() {
 		}
 	}
 
-	enableMutualTLS := true
+	clientAuth := tls.RequireAndVerifyClientCert
 	handler := rest.NewRouter(true)
 
 	// Create Docker *or* CSI/K8S frontend
@@ -349,7 +375,8 @@ func main() {
 		var hybridControllerFrontend frontend.Plugin
 		var hybridNodeFrontend frontend.Plugin
 		if *k8sAPIServer != "" || *k8sPod {
-			hybridControllerFrontend, err = k8sctrlhelper.NewHelper(orchestrator, *k8sAPIServer, *k8sConfigPath)
+			hybridControllerFrontend, err = k8sctrlhelper.NewHelper(orchestrator, *k8sAPIServer, *k8sConfigPath,
+				*httpsCACert, *httpsControllerCert, *httpsControllerKey)
 			hybridNodeFrontend, err = k8snodehelper.NewHelper(orchestrator, *k8sConfigPath)
 		} else {
 			hybridControllerFrontend = plainctrlhelper.NewHelper(orchestrator)
@@ -383,21 +410,33 @@ func main() {
 		}).Info("Initializing CSI frontend.")
 
 		var csiFrontend *csi.Plugin
+		iSCSIPathHealingPolicy := csi.ISCSIPathHealingPolicy{
+			Rescan:              *iSCSIPathRescan,
+			Reinstate:           *iSCSIPathReinstate,
+			LostPathRemovalTime: *iSCSILostPathRemovalTime,
+		}
 		switch *csiRole {
 		case csi.CSIController:
 			txnMonitor = true
-			csiFrontend, err = csi.NewControllerPlugin(*csiNodeName, *csiEndpoint, *aesKey, orchestrator, &controllerHelper)
+			csiFrontend, err = csi.NewControllerPlugin(*csiNodeName, *csiEndpoint, *aesKey, orchestrator,
+				&controllerHelper, *volumeJobWaitTime)
 		case csi.CSINode:
+			nodeRESTPort := ""
+			if *enableHTTPSREST {
+				nodeRESTPort = *httpsPort
+			}
 			csiFrontend, err = csi.NewNodePlugin(*csiNodeName, *csiEndpoint, *httpsCACert, *httpsClientCert,
 				*httpsClientKey, *aesKey, orchestrator, *csiUnsafeNodeDetach, &nodeHelper, *enableForceDetach,
-				*iSCSISelfHealingInterval, *iSCSISelfHealingWaitTime)
-			enableMutualTLS = false
+				*iSCSISelfHealingInterval, *iSCSISelfHealingWaitTime, iSCSIPathHealingPolicy, nodeRESTPort,
+				*manageMultipath)
+			// Only some node routes require a client certificate, so the probes remain unauthenticated
+			clientAuth = tls.VerifyClientCertIfGiven
 			handler = rest.NewNodeRouter(csiFrontend)
 		case csi.CSIAllInOne:
 			txnMonitor = true
 			csiFrontend, err = csi.NewAllInOnePlugin(*csiNodeName, *csiEndpoint, *httpsCACert, *httpsClientCert,
 				*httpsClientKey, *aesKey, orchestrator, &controllerHelper, &nodeHelper, *csiUnsafeNodeDetach,
-				*iSCSISelfHealingInterval, *iSCSISelfHealingWaitTime)
+				*iSCSISelfHealingInterval, *iSCSISelfHealingWaitTime, iSCSIPathHealingPolicy, *volumeJobWaitTime)
 		}
 		if err != nil {
 			log.Fatalf("Unable to start the CSI frontend. %v", err)
@@ -446,7 +485,7 @@ func main() {
 
 			httpsServer, err := rest.NewHTTPSServer(
 				orchestrator, *httpsAddress, *httpsPort, *httpsCACert, *httpsServerCert, *httpsServerKey,
-				enableMutualTLS, handler, *httpRequestTimeout)
+				clientAuth, handler, *httpRequestTimeout)
 			if err != nil {
 				log.Fatalf("Unable to start the HTTPS REST frontend. %v", err)
 			}
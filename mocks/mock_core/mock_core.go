@@ -7,6 +7,7 @@ package mock_core
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	core "github.com/netapp/trident/core"
@@ -351,6 +352,35 @@ func (mr *MockOrchestratorMockRecorder) EstablishMirror(arg0, arg1, arg2, arg3,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EstablishMirror", reflect.TypeOf((*MockOrchestrator)(nil).EstablishMirror), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// ExplainVolumePlacement mocks base method.
+func (m *MockOrchestrator) ExplainVolumePlacement(arg0 context.Context, arg1 *storage.VolumeConfig) ([]storageclass.PoolPlacement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExplainVolumePlacement", arg0, arg1)
+	ret0, _ := ret[0].([]storageclass.PoolPlacement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExplainVolumePlacement indicates an expected call of ExplainVolumePlacement.
+func (mr *MockOrchestratorMockRecorder) ExplainVolumePlacement(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExplainVolumePlacement", reflect.TypeOf((*MockOrchestrator)(nil).ExplainVolumePlacement), arg0, arg1)
+}
+
+// FenceNode mocks base method.
+func (m *MockOrchestrator) FenceNode(arg0 context.Context, arg1 string, arg2 core.NodeEventCallback) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FenceNode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FenceNode indicates an expected call of FenceNode.
+func (mr *MockOrchestratorMockRecorder) FenceNode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FenceNode", reflect.TypeOf((*MockOrchestrator)(nil).FenceNode), arg0, arg1, arg2)
+}
+
 // GetBackend mocks base method.
 func (m *MockOrchestrator) GetBackend(arg0 context.Context, arg1 string) (*storage.BackendExternal, error) {
 	m.ctrl.T.Helper()
@@ -411,6 +441,36 @@ func (mr *MockOrchestratorMockRecorder) GetFrontend(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFrontend", reflect.TypeOf((*MockOrchestrator)(nil).GetFrontend), arg0, arg1)
 }
 
+// GetJob mocks base method.
+func (m *MockOrchestrator) GetJob(arg0 context.Context, arg1 string) (*storage.JobExternal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", arg0, arg1)
+	ret0, _ := ret[0].(*storage.JobExternal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockOrchestratorMockRecorder) GetJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockOrchestrator)(nil).GetJob), arg0, arg1)
+}
+
+// GetMirrorHealth mocks base method.
+func (m *MockOrchestrator) GetMirrorHealth(arg0 context.Context, arg1, arg2, arg3 string) (*storage.MirrorHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMirrorHealth", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*storage.MirrorHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMirrorHealth indicates an expected call of GetMirrorHealth.
+func (mr *MockOrchestratorMockRecorder) GetMirrorHealth(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMirrorHealth", reflect.TypeOf((*MockOrchestrator)(nil).GetMirrorHealth), arg0, arg1, arg2, arg3)
+}
+
 // GetMirrorStatus mocks base method.
 func (m *MockOrchestrator) GetMirrorStatus(arg0 context.Context, arg1, arg2, arg3 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -637,6 +697,21 @@ func (mr *MockOrchestratorMockRecorder) ListBackends(arg0 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBackends", reflect.TypeOf((*MockOrchestrator)(nil).ListBackends), arg0)
 }
 
+// ListJobs mocks base method.
+func (m *MockOrchestrator) ListJobs(arg0 context.Context) ([]*storage.JobExternal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", arg0)
+	ret0, _ := ret[0].([]*storage.JobExternal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockOrchestratorMockRecorder) ListJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockOrchestrator)(nil).ListJobs), arg0)
+}
+
 // ListNodes mocks base method.
 func (m *MockOrchestrator) ListNodes(arg0 context.Context) ([]*utils.Node, error) {
 	m.ctrl.T.Helper()
@@ -772,6 +847,21 @@ func (mr *MockOrchestratorMockRecorder) ListVolumePublicationsForVolume(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumePublicationsForVolume", reflect.TypeOf((*MockOrchestrator)(nil).ListVolumePublicationsForVolume), arg0, arg1, arg2)
 }
 
+// ListVolumeTransactions mocks base method.
+func (m *MockOrchestrator) ListVolumeTransactions(arg0 context.Context) ([]*storage.VolumeTransactionExternal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVolumeTransactions", arg0)
+	ret0, _ := ret[0].([]*storage.VolumeTransactionExternal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVolumeTransactions indicates an expected call of ListVolumeTransactions.
+func (mr *MockOrchestratorMockRecorder) ListVolumeTransactions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVolumeTransactions", reflect.TypeOf((*MockOrchestrator)(nil).ListVolumeTransactions), arg0)
+}
+
 // ListVolumes mocks base method.
 func (m *MockOrchestrator) ListVolumes(arg0 context.Context) ([]*storage.VolumeExternal, error) {
 	m.ctrl.T.Helper()
@@ -814,6 +904,21 @@ func (mr *MockOrchestratorMockRecorder) PromoteMirror(arg0, arg1, arg2, arg3, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteMirror", reflect.TypeOf((*MockOrchestrator)(nil).PromoteMirror), arg0, arg1, arg2, arg3, arg4)
 }
 
+// PromoteVolumeReplica mocks base method.
+func (m *MockOrchestrator) PromoteVolumeReplica(arg0 context.Context, arg1 string) (*storage.VolumeExternal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteVolumeReplica", arg0, arg1)
+	ret0, _ := ret[0].(*storage.VolumeExternal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromoteVolumeReplica indicates an expected call of PromoteVolumeReplica.
+func (mr *MockOrchestratorMockRecorder) PromoteVolumeReplica(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteVolumeReplica", reflect.TypeOf((*MockOrchestrator)(nil).PromoteVolumeReplica), arg0, arg1)
+}
+
 // PublishVolume mocks base method.
 func (m *MockOrchestrator) PublishVolume(arg0 context.Context, arg1 string, arg2 *utils.VolumePublishInfo) error {
 	m.ctrl.T.Helper()
@@ -913,6 +1018,34 @@ func (mr *MockOrchestratorMockRecorder) ResizeVolume(arg0, arg1, arg2 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResizeVolume", reflect.TypeOf((*MockOrchestrator)(nil).ResizeVolume), arg0, arg1, arg2)
 }
 
+// RestoreSnapshot mocks base method.
+func (m *MockOrchestrator) RestoreSnapshot(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSnapshot", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSnapshot indicates an expected call of RestoreSnapshot.
+func (mr *MockOrchestratorMockRecorder) RestoreSnapshot(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshot", reflect.TypeOf((*MockOrchestrator)(nil).RestoreSnapshot), arg0, arg1, arg2)
+}
+
+// SetVolumeLUKSKeyRevoked mocks base method.
+func (m *MockOrchestrator) SetVolumeLUKSKeyRevoked(arg0 context.Context, arg1 string, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetVolumeLUKSKeyRevoked", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetVolumeLUKSKeyRevoked indicates an expected call of SetVolumeLUKSKeyRevoked.
+func (mr *MockOrchestratorMockRecorder) SetVolumeLUKSKeyRevoked(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVolumeLUKSKeyRevoked", reflect.TypeOf((*MockOrchestrator)(nil).SetVolumeLUKSKeyRevoked), arg0, arg1, arg2)
+}
+
 // SetVolumeState mocks base method.
 func (m *MockOrchestrator) SetVolumeState(arg0 context.Context, arg1 string, arg2 storage.VolumeState) error {
 	m.ctrl.T.Helper()
@@ -927,6 +1060,49 @@ func (mr *MockOrchestratorMockRecorder) SetVolumeState(arg0, arg1, arg2 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetVolumeState", reflect.TypeOf((*MockOrchestrator)(nil).SetVolumeState), arg0, arg1, arg2)
 }
 
+// SubmitVolumeJob mocks base method.
+func (m *MockOrchestrator) SubmitVolumeJob(arg0 context.Context, arg1 *storage.VolumeConfig) (*storage.JobExternal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitVolumeJob", arg0, arg1)
+	ret0, _ := ret[0].(*storage.JobExternal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitVolumeJob indicates an expected call of SubmitVolumeJob.
+func (mr *MockOrchestratorMockRecorder) SubmitVolumeJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitVolumeJob", reflect.TypeOf((*MockOrchestrator)(nil).SubmitVolumeJob), arg0, arg1)
+}
+
+// TakeOverVolume mocks base method.
+func (m *MockOrchestrator) TakeOverVolume(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeOverVolume", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TakeOverVolume indicates an expected call of TakeOverVolume.
+func (mr *MockOrchestratorMockRecorder) TakeOverVolume(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeOverVolume", reflect.TypeOf((*MockOrchestrator)(nil).TakeOverVolume), arg0, arg1, arg2)
+}
+
+// UnfenceNode mocks base method.
+func (m *MockOrchestrator) UnfenceNode(arg0 context.Context, arg1 string, arg2 core.NodeEventCallback) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfenceNode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfenceNode indicates an expected call of UnfenceNode.
+func (mr *MockOrchestratorMockRecorder) UnfenceNode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfenceNode", reflect.TypeOf((*MockOrchestrator)(nil).UnfenceNode), arg0, arg1, arg2)
+}
+
 // UnpublishVolume mocks base method.
 func (m *MockOrchestrator) UnpublishVolume(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -986,6 +1162,20 @@ func (mr *MockOrchestratorMockRecorder) UpdateBackendState(arg0, arg1, arg2 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBackendState", reflect.TypeOf((*MockOrchestrator)(nil).UpdateBackendState), arg0, arg1, arg2)
 }
 
+// UpdateSnapshot mocks base method.
+func (m *MockOrchestrator) UpdateSnapshot(arg0 context.Context, arg1, arg2 string, arg3 *[]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSnapshot", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSnapshot indicates an expected call of UpdateSnapshot.
+func (mr *MockOrchestratorMockRecorder) UpdateSnapshot(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSnapshot", reflect.TypeOf((*MockOrchestrator)(nil).UpdateSnapshot), arg0, arg1, arg2, arg3)
+}
+
 // UpdateVolume mocks base method.
 func (m *MockOrchestrator) UpdateVolume(arg0 context.Context, arg1 string, arg2 *[]string) error {
 	m.ctrl.T.Helper()
@@ -1000,6 +1190,20 @@ func (mr *MockOrchestratorMockRecorder) UpdateVolume(arg0, arg1, arg2 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolume", reflect.TypeOf((*MockOrchestrator)(nil).UpdateVolume), arg0, arg1, arg2)
 }
 
+// UpdateVolumeLUKSWrappedKey mocks base method.
+func (m *MockOrchestrator) UpdateVolumeLUKSWrappedKey(arg0 context.Context, arg1 string, arg2 *utils.LUKSWrappedKey, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVolumeLUKSWrappedKey", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVolumeLUKSWrappedKey indicates an expected call of UpdateVolumeLUKSWrappedKey.
+func (mr *MockOrchestratorMockRecorder) UpdateVolumeLUKSWrappedKey(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolumeLUKSWrappedKey", reflect.TypeOf((*MockOrchestrator)(nil).UpdateVolumeLUKSWrappedKey), arg0, arg1, arg2, arg3)
+}
+
 // UpdateVolumePublication mocks base method.
 func (m *MockOrchestrator) UpdateVolumePublication(arg0 context.Context, arg1, arg2 string, arg3 *bool) error {
 	m.ctrl.T.Helper()
@@ -1013,3 +1217,18 @@ func (mr *MockOrchestratorMockRecorder) UpdateVolumePublication(arg0, arg1, arg2
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolumePublication", reflect.TypeOf((*MockOrchestrator)(nil).UpdateVolumePublication), arg0, arg1, arg2, arg3)
 }
+
+// WaitForJob mocks base method.
+func (m *MockOrchestrator) WaitForJob(arg0 context.Context, arg1 string, arg2 time.Duration) (*storage.JobExternal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitForJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*storage.JobExternal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitForJob indicates an expected call of WaitForJob.
+func (mr *MockOrchestratorMockRecorder) WaitForJob(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForJob", reflect.TypeOf((*MockOrchestrator)(nil).WaitForJob), arg0, arg1, arg2)
+}
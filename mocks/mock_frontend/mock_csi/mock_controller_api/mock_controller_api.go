@@ -11,6 +11,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	controllerAPI "github.com/netapp/trident/frontend/csi/controller_api"
+	storage "github.com/netapp/trident/storage"
 	utils "github.com/netapp/trident/utils"
 )
 
@@ -52,6 +53,20 @@ func (mr *MockTridentControllerMockRecorder) CreateNode(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNode", reflect.TypeOf((*MockTridentController)(nil).CreateNode), arg0, arg1)
 }
 
+// CreateVolume mocks base method.
+func (m *MockTridentController) CreateVolume(arg0 context.Context, arg1 *storage.VolumeConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVolume", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVolume indicates an expected call of CreateVolume.
+func (mr *MockTridentControllerMockRecorder) CreateVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockTridentController)(nil).CreateVolume), arg0, arg1)
+}
+
 // DeleteNode mocks base method.
 func (m *MockTridentController) DeleteNode(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -66,6 +81,20 @@ func (mr *MockTridentControllerMockRecorder) DeleteNode(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNode", reflect.TypeOf((*MockTridentController)(nil).DeleteNode), arg0, arg1)
 }
 
+// DeleteVolume mocks base method.
+func (m *MockTridentController) DeleteVolume(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVolume", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVolume indicates an expected call of DeleteVolume.
+func (mr *MockTridentControllerMockRecorder) DeleteVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolume", reflect.TypeOf((*MockTridentController)(nil).DeleteVolume), arg0, arg1)
+}
+
 // GetChap mocks base method.
 func (m *MockTridentController) GetChap(arg0 context.Context, arg1, arg2 string) (*utils.IscsiChapInfo, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +125,36 @@ func (mr *MockTridentControllerMockRecorder) GetNodes(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNodes", reflect.TypeOf((*MockTridentController)(nil).GetNodes), arg0)
 }
 
+// GetVolume mocks base method.
+func (m *MockTridentController) GetVolume(arg0 context.Context, arg1 string) (*storage.VolumeExternal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolume", arg0, arg1)
+	ret0, _ := ret[0].(*storage.VolumeExternal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolume indicates an expected call of GetVolume.
+func (mr *MockTridentControllerMockRecorder) GetVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolume", reflect.TypeOf((*MockTridentController)(nil).GetVolume), arg0, arg1)
+}
+
+// GetVolumeLUKSWrappedKey mocks base method.
+func (m *MockTridentController) GetVolumeLUKSWrappedKey(arg0 context.Context, arg1 string) (*utils.LUKSWrappedKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumeLUKSWrappedKey", arg0, arg1)
+	ret0, _ := ret[0].(*utils.LUKSWrappedKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumeLUKSWrappedKey indicates an expected call of GetVolumeLUKSWrappedKey.
+func (mr *MockTridentControllerMockRecorder) GetVolumeLUKSWrappedKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeLUKSWrappedKey", reflect.TypeOf((*MockTridentController)(nil).GetVolumeLUKSWrappedKey), arg0, arg1)
+}
+
 // InvokeAPI mocks base method.
 func (m *MockTridentController) InvokeAPI(arg0 context.Context, arg1 []byte, arg2, arg3 string, arg4, arg5 bool) (*http.Response, []byte, error) {
 	m.ctrl.T.Helper()
@@ -112,6 +171,35 @@ func (mr *MockTridentControllerMockRecorder) InvokeAPI(arg0, arg1, arg2, arg3, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeAPI", reflect.TypeOf((*MockTridentController)(nil).InvokeAPI), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// PublishVolume mocks base method.
+func (m *MockTridentController) PublishVolume(arg0 context.Context, arg1, arg2 string, arg3 *utils.VolumePublishInfo) (*utils.VolumePublishInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishVolume", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*utils.VolumePublishInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishVolume indicates an expected call of PublishVolume.
+func (mr *MockTridentControllerMockRecorder) PublishVolume(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishVolume", reflect.TypeOf((*MockTridentController)(nil).PublishVolume), arg0, arg1, arg2, arg3)
+}
+
+// UnpublishVolume mocks base method.
+func (m *MockTridentController) UnpublishVolume(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpublishVolume", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnpublishVolume indicates an expected call of UnpublishVolume.
+func (mr *MockTridentControllerMockRecorder) UnpublishVolume(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpublishVolume", reflect.TypeOf((*MockTridentController)(nil).UnpublishVolume), arg0, arg1, arg2)
+}
+
 // UpdateVolumeLUKSPassphraseNames mocks base method.
 func (m *MockTridentController) UpdateVolumeLUKSPassphraseNames(arg0 context.Context, arg1 string, arg2 []string) error {
 	m.ctrl.T.Helper()
@@ -126,6 +214,20 @@ func (mr *MockTridentControllerMockRecorder) UpdateVolumeLUKSPassphraseNames(arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolumeLUKSPassphraseNames", reflect.TypeOf((*MockTridentController)(nil).UpdateVolumeLUKSPassphraseNames), arg0, arg1, arg2)
 }
 
+// UpdateVolumeLUKSWrappedKey mocks base method.
+func (m *MockTridentController) UpdateVolumeLUKSWrappedKey(arg0 context.Context, arg1 string, arg2 *utils.LUKSWrappedKey, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVolumeLUKSWrappedKey", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVolumeLUKSWrappedKey indicates an expected call of UpdateVolumeLUKSWrappedKey.
+func (mr *MockTridentControllerMockRecorder) UpdateVolumeLUKSWrappedKey(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVolumeLUKSWrappedKey", reflect.TypeOf((*MockTridentController)(nil).UpdateVolumeLUKSWrappedKey), arg0, arg1, arg2, arg3)
+}
+
 // UpdateVolumePublication mocks base method.
 func (m *MockTridentController) UpdateVolumePublication(arg0 context.Context, arg1 *utils.VolumePublicationExternal) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/netapp/trident/frontend/csi/node_api (interfaces: TridentNode)
+
+// Package mock_node_api is a generated GoMock package.
+package mock_node_api
+
+import (
+	context "context"
+	http "net/http"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	nodeAPI "github.com/netapp/trident/frontend/csi/node_api"
+	utils "github.com/netapp/trident/utils"
+)
+
+// MockTridentNode is a mock of TridentNode interface.
+type MockTridentNode struct {
+	ctrl     *gomock.Controller
+	recorder *MockTridentNodeMockRecorder
+}
+
+// MockTridentNodeMockRecorder is the mock recorder for MockTridentNode.
+type MockTridentNodeMockRecorder struct {
+	mock *MockTridentNode
+}
+
+// NewMockTridentNode creates a new mock instance.
+func NewMockTridentNode(ctrl *gomock.Controller) *MockTridentNode {
+	mock := &MockTridentNode{ctrl: ctrl}
+	mock.recorder = &MockTridentNodeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTridentNode) EXPECT() *MockTridentNodeMockRecorder {
+	return m.recorder
+}
+
+// CheckNode mocks base method.
+func (m *MockTridentNode) CheckNode(arg0 context.Context, arg1 string, arg2 *nodeAPI.NodeCheckRequest) (*utils.NodeCheckReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckNode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*utils.NodeCheckReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckNode indicates an expected call of CheckNode.
+func (mr *MockTridentNodeMockRecorder) CheckNode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckNode", reflect.TypeOf((*MockTridentNode)(nil).CheckNode), arg0, arg1, arg2)
+}
+
+// InvokeAPI mocks base method.
+func (m *MockTridentNode) InvokeAPI(arg0 context.Context, arg1 []byte, arg2, arg3 string, arg4, arg5 bool) (*http.Response, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvokeAPI", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// InvokeAPI indicates an expected call of InvokeAPI.
+func (mr *MockTridentNodeMockRecorder) InvokeAPI(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeAPI", reflect.TypeOf((*MockTridentNode)(nil).InvokeAPI), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// PreemptReservationKeys mocks base method.
+func (m *MockTridentNode) PreemptReservationKeys(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreemptReservationKeys", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PreemptReservationKeys indicates an expected call of PreemptReservationKeys.
+func (mr *MockTridentNodeMockRecorder) PreemptReservationKeys(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreemptReservationKeys", reflect.TypeOf((*MockTridentNode)(nil).PreemptReservationKeys), arg0, arg1, arg2)
+}
+
+// RotateLUKSPassphrase mocks base method.
+func (m *MockTridentNode) RotateLUKSPassphrase(arg0 context.Context, arg1, arg2 string, arg3 map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateLUKSPassphrase", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateLUKSPassphrase indicates an expected call of RotateLUKSPassphrase.
+func (mr *MockTridentNodeMockRecorder) RotateLUKSPassphrase(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateLUKSPassphrase", reflect.TypeOf((*MockTridentNode)(nil).RotateLUKSPassphrase), arg0, arg1, arg2, arg3)
+}
@@ -67,6 +67,20 @@ func (mr *MockStoreClientMockRecorder) AddBackendPersistent(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBackendPersistent", reflect.TypeOf((*MockStoreClient)(nil).AddBackendPersistent), arg0, arg1)
 }
 
+// AddJob mocks base method.
+func (m *MockStoreClient) AddJob(arg0 context.Context, arg1 *storage.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddJob indicates an expected call of AddJob.
+func (mr *MockStoreClientMockRecorder) AddJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJob", reflect.TypeOf((*MockStoreClient)(nil).AddJob), arg0, arg1)
+}
+
 // AddOrUpdateNode mocks base method.
 func (m *MockStoreClient) AddOrUpdateNode(arg0 context.Context, arg1 *utils.Node) error {
 	m.ctrl.T.Helper()
@@ -193,6 +207,20 @@ func (mr *MockStoreClientMockRecorder) DeleteBackends(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBackends", reflect.TypeOf((*MockStoreClient)(nil).DeleteBackends), arg0)
 }
 
+// DeleteJob mocks base method.
+func (m *MockStoreClient) DeleteJob(arg0 context.Context, arg1 *storage.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJob indicates an expected call of DeleteJob.
+func (mr *MockStoreClientMockRecorder) DeleteJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockStoreClient)(nil).DeleteJob), arg0, arg1)
+}
+
 // DeleteNode mocks base method.
 func (m *MockStoreClient) DeleteNode(arg0 context.Context, arg1 *utils.Node) error {
 	m.ctrl.T.Helper()
@@ -407,6 +435,21 @@ func (mr *MockStoreClientMockRecorder) GetExistingVolumeTransaction(arg0, arg1 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExistingVolumeTransaction", reflect.TypeOf((*MockStoreClient)(nil).GetExistingVolumeTransaction), arg0, arg1)
 }
 
+// GetJobs mocks base method.
+func (m *MockStoreClient) GetJobs(arg0 context.Context) ([]*storage.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobs", arg0)
+	ret0, _ := ret[0].([]*storage.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobs indicates an expected call of GetJobs.
+func (mr *MockStoreClientMockRecorder) GetJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobs", reflect.TypeOf((*MockStoreClient)(nil).GetJobs), arg0)
+}
+
 // GetNode mocks base method.
 func (m *MockStoreClient) GetNode(arg0 context.Context, arg1 string) (*utils.Node, error) {
 	m.ctrl.T.Helper()
@@ -700,6 +743,20 @@ func (mr *MockStoreClientMockRecorder) UpdateBackendPersistent(arg0, arg1 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBackendPersistent", reflect.TypeOf((*MockStoreClient)(nil).UpdateBackendPersistent), arg0, arg1)
 }
 
+// UpdateJob mocks base method.
+func (m *MockStoreClient) UpdateJob(arg0 context.Context, arg1 *storage.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockStoreClientMockRecorder) UpdateJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockStoreClient)(nil).UpdateJob), arg0, arg1)
+}
+
 // UpdateSnapshot mocks base method.
 func (m *MockStoreClient) UpdateSnapshot(arg0 context.Context, arg1 *storage.Snapshot) error {
 	m.ctrl.T.Helper()
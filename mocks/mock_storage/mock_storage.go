@@ -66,6 +66,20 @@ func (mr *MockBackendMockRecorder) AddVolume(arg0, arg1, arg2, arg3, arg4 interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddVolume", reflect.TypeOf((*MockBackend)(nil).AddVolume), arg0, arg1, arg2, arg3, arg4)
 }
 
+// AddVolumePublication mocks base method.
+func (m *MockBackend) AddVolumePublication(arg0 context.Context, arg1 *storage.VolumeConfig, arg2 *utils.VolumePublication, arg3 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddVolumePublication", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddVolumePublication indicates an expected call of AddVolumePublication.
+func (mr *MockBackendMockRecorder) AddVolumePublication(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddVolumePublication", reflect.TypeOf((*MockBackend)(nil).AddVolumePublication), arg0, arg1, arg2, arg3)
+}
+
 // BackendUUID mocks base method.
 func (m *MockBackend) BackendUUID() string {
 	m.ctrl.T.Helper()
@@ -80,6 +94,18 @@ func (mr *MockBackendMockRecorder) BackendUUID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BackendUUID", reflect.TypeOf((*MockBackend)(nil).BackendUUID))
 }
 
+// CacheVolume mocks base method.
+func (m *MockBackend) CacheVolume(arg0 *storage.Volume) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "CacheVolume", arg0)
+}
+
+// CacheVolume indicates an expected call of CacheVolume.
+func (mr *MockBackendMockRecorder) CacheVolume(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheVolume", reflect.TypeOf((*MockBackend)(nil).CacheVolume), arg0)
+}
+
 // CanMirror mocks base method.
 func (m *MockBackend) CanMirror() bool {
 	m.ctrl.T.Helper()
@@ -180,6 +206,21 @@ func (mr *MockBackendMockRecorder) CreateSnapshot(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshot", reflect.TypeOf((*MockBackend)(nil).CreateSnapshot), arg0, arg1, arg2)
 }
 
+// Degraded mocks base method.
+func (m *MockBackend) Degraded() (bool, string) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Degraded")
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(string)
+	return ret0, ret1
+}
+
+// Degraded indicates an expected call of Degraded.
+func (mr *MockBackendMockRecorder) Degraded() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Degraded", reflect.TypeOf((*MockBackend)(nil).Degraded))
+}
+
 // DeleteSnapshot mocks base method.
 func (m *MockBackend) DeleteSnapshot(arg0 context.Context, arg1 *storage.SnapshotConfig, arg2 *storage.VolumeConfig) error {
 	m.ctrl.T.Helper()
@@ -194,6 +235,20 @@ func (mr *MockBackendMockRecorder) DeleteSnapshot(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnapshot", reflect.TypeOf((*MockBackend)(nil).DeleteSnapshot), arg0, arg1, arg2)
 }
 
+// DeleteVolumePublication mocks base method.
+func (m *MockBackend) DeleteVolumePublication(arg0 context.Context, arg1 *storage.VolumeConfig, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVolumePublication", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVolumePublication indicates an expected call of DeleteVolumePublication.
+func (mr *MockBackendMockRecorder) DeleteVolumePublication(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolumePublication", reflect.TypeOf((*MockBackend)(nil).DeleteVolumePublication), arg0, arg1, arg2)
+}
+
 // Driver mocks base method.
 func (m *MockBackend) Driver() storage.Driver {
 	m.ctrl.T.Helper()
@@ -352,6 +407,21 @@ func (mr *MockBackendMockRecorder) GetVolumeExternal(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeExternal", reflect.TypeOf((*MockBackend)(nil).GetVolumeExternal), arg0, arg1)
 }
 
+// GetVolumePublications mocks base method.
+func (m *MockBackend) GetVolumePublications(arg0 context.Context, arg1 *storage.VolumeConfig) ([]*utils.VolumePublication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVolumePublications", arg0, arg1)
+	ret0, _ := ret[0].([]*utils.VolumePublication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVolumePublications indicates an expected call of GetVolumePublications.
+func (mr *MockBackendMockRecorder) GetVolumePublications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumePublications", reflect.TypeOf((*MockBackend)(nil).GetVolumePublications), arg0, arg1)
+}
+
 // HasVolumes mocks base method.
 func (m *MockBackend) HasVolumes() bool {
 	m.ctrl.T.Helper()
@@ -435,6 +505,20 @@ func (mr *MockBackendMockRecorder) Online() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Online", reflect.TypeOf((*MockBackend)(nil).Online))
 }
 
+// ProbeHealth mocks base method.
+func (m *MockBackend) ProbeHealth(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProbeHealth", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProbeHealth indicates an expected call of ProbeHealth.
+func (mr *MockBackendMockRecorder) ProbeHealth(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProbeHealth", reflect.TypeOf((*MockBackend)(nil).ProbeHealth), arg0)
+}
+
 // PublishVolume mocks base method.
 func (m *MockBackend) PublishVolume(arg0 context.Context, arg1 *storage.VolumeConfig, arg2 *utils.VolumePublishInfo) error {
 	m.ctrl.T.Helper()
@@ -531,6 +615,20 @@ func (mr *MockBackendMockRecorder) RestoreSnapshot(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshot", reflect.TypeOf((*MockBackend)(nil).RestoreSnapshot), arg0, arg1, arg2)
 }
 
+// RevokeNodeAccess mocks base method.
+func (m *MockBackend) RevokeNodeAccess(arg0 context.Context, arg1 *utils.Node, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeNodeAccess", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeNodeAccess indicates an expected call of RevokeNodeAccess.
+func (mr *MockBackendMockRecorder) RevokeNodeAccess(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeNodeAccess", reflect.TypeOf((*MockBackend)(nil).RevokeNodeAccess), arg0, arg1, arg2)
+}
+
 // SetBackendUUID mocks base method.
 func (m *MockBackend) SetBackendUUID(arg0 string) {
 	m.ctrl.T.Helper()
@@ -681,6 +779,20 @@ func (mr *MockBackendMockRecorder) UnpublishVolume(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpublishVolume", reflect.TypeOf((*MockBackend)(nil).UnpublishVolume), arg0, arg1, arg2)
 }
 
+// UpdatePoolUtilization mocks base method.
+func (m *MockBackend) UpdatePoolUtilization(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePoolUtilization", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePoolUtilization indicates an expected call of UpdatePoolUtilization.
+func (mr *MockBackendMockRecorder) UpdatePoolUtilization(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePoolUtilization", reflect.TypeOf((*MockBackend)(nil).UpdatePoolUtilization), arg0)
+}
+
 // Volumes mocks base method.
 func (m *MockBackend) Volumes() map[string]*storage.Volume {
 	m.ctrl.T.Helper()
@@ -915,6 +1027,18 @@ func (mr *MockPoolMockRecorder) SetSupportedTopologies(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSupportedTopologies", reflect.TypeOf((*MockPool)(nil).SetSupportedTopologies), arg0)
 }
 
+// SetUtilization mocks base method.
+func (m *MockPool) SetUtilization(arg0 float64, arg1 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetUtilization", arg0, arg1)
+}
+
+// SetUtilization indicates an expected call of SetUtilization.
+func (mr *MockPoolMockRecorder) SetUtilization(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUtilization", reflect.TypeOf((*MockPool)(nil).SetUtilization), arg0, arg1)
+}
+
 // StorageClasses mocks base method.
 func (m *MockPool) StorageClasses() []string {
 	m.ctrl.T.Helper()
@@ -942,3 +1066,18 @@ func (mr *MockPoolMockRecorder) SupportedTopologies() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupportedTopologies", reflect.TypeOf((*MockPool)(nil).SupportedTopologies))
 }
+
+// Utilization mocks base method.
+func (m *MockPool) Utilization() (float64, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Utilization")
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Utilization indicates an expected call of Utilization.
+func (mr *MockPoolMockRecorder) Utilization() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Utilization", reflect.TypeOf((*MockPool)(nil).Utilization))
+}
@@ -271,6 +271,20 @@ func (mr *MockAzureMockRecorder) ModifyVolume(arg0, arg1, arg2, arg3 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyVolume", reflect.TypeOf((*MockAzure)(nil).ModifyVolume), arg0, arg1, arg2, arg3)
 }
 
+// ModifyVolumeExportPolicy mocks base method.
+func (m *MockAzure) ModifyVolumeExportPolicy(arg0 context.Context, arg1 *api.FileSystem, arg2 *api.ExportPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyVolumeExportPolicy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyVolumeExportPolicy indicates an expected call of ModifyVolumeExportPolicy.
+func (mr *MockAzureMockRecorder) ModifyVolumeExportPolicy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyVolumeExportPolicy", reflect.TypeOf((*MockAzure)(nil).ModifyVolumeExportPolicy), arg0, arg1, arg2)
+}
+
 // RandomCapacityPoolForStoragePool mocks base method.
 func (m *MockAzure) RandomCapacityPoolForStoragePool(arg0 context.Context, arg1 storage.Pool, arg2 string) *api.CapacityPool {
 	m.ctrl.T.Helper()
@@ -38,6 +38,21 @@ func (m *MockGCPClient) EXPECT() *MockGCPClientMockRecorder {
 	return m.recorder
 }
 
+// ChangeVolumeExportPolicy mocks base method.
+func (m *MockGCPClient) ChangeVolumeExportPolicy(arg0 context.Context, arg1 *api.Volume, arg2 api.ExportPolicy) (*api.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeVolumeExportPolicy", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*api.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeVolumeExportPolicy indicates an expected call of ChangeVolumeExportPolicy.
+func (mr *MockGCPClientMockRecorder) ChangeVolumeExportPolicy(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeVolumeExportPolicy", reflect.TypeOf((*MockGCPClient)(nil).ChangeVolumeExportPolicy), arg0, arg1, arg2)
+}
+
 // ChangeVolumeUnixPermissions mocks base method.
 func (m *MockGCPClient) ChangeVolumeUnixPermissions(arg0 context.Context, arg1 *api.Volume, arg2 string) (*api.Volume, error) {
 	m.ctrl.T.Helper()
@@ -449,6 +449,21 @@ func (mr *MockOntapAPIMockRecorder) FlexgroupUsedSize(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlexgroupUsedSize", reflect.TypeOf((*MockOntapAPI)(nil).FlexgroupUsedSize), arg0, arg1)
 }
 
+// GetAggregatePerformanceUtilization mocks base method.
+func (m *MockOntapAPI) GetAggregatePerformanceUtilization(arg0 context.Context) (map[string]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAggregatePerformanceUtilization", arg0)
+	ret0, _ := ret[0].(map[string]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAggregatePerformanceUtilization indicates an expected call of GetAggregatePerformanceUtilization.
+func (mr *MockOntapAPIMockRecorder) GetAggregatePerformanceUtilization(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAggregatePerformanceUtilization", reflect.TypeOf((*MockOntapAPI)(nil).GetAggregatePerformanceUtilization), arg0)
+}
+
 // GetSLMDataLifs mocks base method.
 func (m *MockOntapAPI) GetSLMDataLifs(arg0 context.Context, arg1, arg2 []string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -740,6 +755,21 @@ func (mr *MockOntapAPIMockRecorder) LunDestroy(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LunDestroy", reflect.TypeOf((*MockOntapAPI)(nil).LunDestroy), arg0, arg1)
 }
 
+// LunGetAttribute mocks base method.
+func (m *MockOntapAPI) LunGetAttribute(arg0 context.Context, arg1, arg2 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LunGetAttribute", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LunGetAttribute indicates an expected call of LunGetAttribute.
+func (mr *MockOntapAPIMockRecorder) LunGetAttribute(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LunGetAttribute", reflect.TypeOf((*MockOntapAPI)(nil).LunGetAttribute), arg0, arg1, arg2)
+}
+
 // LunGetByName mocks base method.
 func (m *MockOntapAPI) LunGetByName(arg0 context.Context, arg1 string) (*api.Lun, error) {
 	m.ctrl.T.Helper()
@@ -846,6 +876,35 @@ func (mr *MockOntapAPIMockRecorder) LunMapInfo(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LunMapInfo", reflect.TypeOf((*MockOntapAPI)(nil).LunMapInfo), arg0, arg1, arg2)
 }
 
+// LunMove mocks base method.
+func (m *MockOntapAPI) LunMove(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LunMove", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LunMove indicates an expected call of LunMove.
+func (mr *MockOntapAPIMockRecorder) LunMove(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LunMove", reflect.TypeOf((*MockOntapAPI)(nil).LunMove), arg0, arg1, arg2)
+}
+
+// LunMoveStatus mocks base method.
+func (m *MockOntapAPI) LunMoveStatus(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LunMoveStatus", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LunMoveStatus indicates an expected call of LunMoveStatus.
+func (mr *MockOntapAPIMockRecorder) LunMoveStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LunMoveStatus", reflect.TypeOf((*MockOntapAPI)(nil).LunMoveStatus), arg0, arg1)
+}
+
 // LunRename mocks base method.
 func (m *MockOntapAPI) LunRename(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -977,6 +1036,20 @@ func (mr *MockOntapAPIMockRecorder) ParseLunComment(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ParseLunComment", reflect.TypeOf((*MockOntapAPI)(nil).ParseLunComment), arg0, arg1)
 }
 
+// Ping mocks base method.
+func (m *MockOntapAPI) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockOntapAPIMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockOntapAPI)(nil).Ping), arg0)
+}
+
 // QtreeCount mocks base method.
 func (m *MockOntapAPI) QtreeCount(arg0 context.Context, arg1 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -42,6 +42,21 @@ func (m *MockRestClientInterface) EXPECT() *MockRestClientInterfaceMockRecorder
 	return m.recorder
 }
 
+// AggregateHeadroomList mocks base method.
+func (m *MockRestClientInterface) AggregateHeadroomList(arg0 context.Context) (*cluster.CounterRowCollectionGetOK, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateHeadroomList", arg0)
+	ret0, _ := ret[0].(*cluster.CounterRowCollectionGetOK)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateHeadroomList indicates an expected call of AggregateHeadroomList.
+func (mr *MockRestClientInterfaceMockRecorder) AggregateHeadroomList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateHeadroomList", reflect.TypeOf((*MockRestClientInterface)(nil).AggregateHeadroomList), arg0)
+}
+
 // AggregateList mocks base method.
 func (m *MockRestClientInterface) AggregateList(arg0 context.Context, arg1 string) (*storage.AggregateCollectionGetOK, error) {
 	m.ctrl.T.Helper()
@@ -994,6 +994,36 @@ func (mr *MockZapiClientInterfaceMockRecorder) LunMapsGetByLun(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LunMapsGetByLun", reflect.TypeOf((*MockZapiClientInterface)(nil).LunMapsGetByLun), arg0)
 }
 
+// LunMoveGet mocks base method.
+func (m *MockZapiClientInterface) LunMoveGet(arg0 string) (*azgo.LunMoveGetIterResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LunMoveGet", arg0)
+	ret0, _ := ret[0].(*azgo.LunMoveGetIterResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LunMoveGet indicates an expected call of LunMoveGet.
+func (mr *MockZapiClientInterfaceMockRecorder) LunMoveGet(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LunMoveGet", reflect.TypeOf((*MockZapiClientInterface)(nil).LunMoveGet), arg0)
+}
+
+// LunMoveStart mocks base method.
+func (m *MockZapiClientInterface) LunMoveStart(arg0, arg1 string) (*azgo.LunMoveStartResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LunMoveStart", arg0, arg1)
+	ret0, _ := ret[0].(*azgo.LunMoveStartResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LunMoveStart indicates an expected call of LunMoveStart.
+func (mr *MockZapiClientInterfaceMockRecorder) LunMoveStart(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LunMoveStart", reflect.TypeOf((*MockZapiClientInterface)(nil).LunMoveStart), arg0, arg1)
+}
+
 // LunOffline mocks base method.
 func (m *MockZapiClientInterface) LunOffline(arg0 string) (*azgo.LunOfflineResponse, error) {
 	m.ctrl.T.Helper()
@@ -1794,6 +1824,20 @@ func (mr *MockZapiClientInterfaceMockRecorder) SystemGetVersion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SystemGetVersion", reflect.TypeOf((*MockZapiClientInterface)(nil).SystemGetVersion))
 }
 
+// SystemPing mocks base method.
+func (m *MockZapiClientInterface) SystemPing(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SystemPing", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SystemPing indicates an expected call of SystemPing.
+func (mr *MockZapiClientInterfaceMockRecorder) SystemPing(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SystemPing", reflect.TypeOf((*MockZapiClientInterface)(nil).SystemPing), arg0)
+}
+
 // TieringPolicyValue mocks base method.
 func (m *MockZapiClientInterface) TieringPolicyValue(arg0 context.Context) string {
 	m.ctrl.T.Helper()
@@ -34,6 +34,7 @@ type TridentOrchestratorList struct {
 // TridentOrchestratorSpec defines the desired state of TridentOrchestrator
 type TridentOrchestratorSpec struct {
 	EnableForceDetach            bool              `json:"enableForceDetach"`
+	ManageMultipathConfig        bool              `json:"manageMultipathConfig,omitempty"`
 	DisableAuditLog              *bool             `json:"disableAuditLog"`
 	Debug                        bool              `json:"debug"`
 	Namespace                    string            `json:"namespace"`
@@ -107,6 +108,7 @@ type TridentOrchestratorStatus struct {
 
 type TridentOrchestratorSpecValues struct {
 	EnableForceDetach       string            `json:"enableForceDetach"`
+	ManageMultipathConfig   string            `json:"manageMultipathConfig"`
 	DisableAuditLog         string            `json:"disableAuditLog"`
 	Debug                   string            `json:"debug"`
 	IPv6                    string            `json:"IPv6"`
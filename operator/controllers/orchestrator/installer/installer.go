@@ -34,6 +34,7 @@ const (
 	NodeCRDName               = "tridentnodes.trident.netapp.io"
 	StorageClassCRDName       = "tridentstorageclasses.trident.netapp.io"
 	TransactionCRDName        = "tridenttransactions.trident.netapp.io"
+	JobCRDName                = "tridentjobs.trident.netapp.io"
 	VersionCRDName            = "tridentversions.trident.netapp.io"
 	VolumeCRDName             = "tridentvolumes.trident.netapp.io"
 	VolumePublicationCRDName  = "tridentvolumepublications.trident.netapp.io"
@@ -51,6 +52,7 @@ var (
 	// CR inputs
 	csi                bool
 	enableForceDetach  bool
+	manageMultipath    bool
 	disableAuditLog    bool
 	debug              bool
 	useIPv6            bool
@@ -91,6 +93,7 @@ var (
 		NodeCRDName,
 		StorageClassCRDName,
 		TransactionCRDName,
+		JobCRDName,
 		VersionCRDName,
 		VolumeCRDName,
 		SnapshotCRDName,
@@ -289,6 +292,7 @@ func (i *Installer) setInstallationParams(
 	// Get values from CR
 	csi = true
 	enableForceDetach = cr.Spec.EnableForceDetach
+	manageMultipath = cr.Spec.ManageMultipathConfig
 	if cr.Spec.DisableAuditLog == nil {
 		disableAuditLog = true
 	} else {
@@ -577,6 +581,7 @@ func (i *Installer) InstallOrPatchTrident(
 
 	identifiedSpecValues := netappv1.TridentOrchestratorSpecValues{
 		EnableForceDetach:       strconv.FormatBool(enableForceDetach),
+		ManageMultipathConfig:   strconv.FormatBool(manageMultipath),
 		DisableAuditLog:         strconv.FormatBool(disableAuditLog),
 		Debug:                   strconv.FormatBool(debug),
 		LogFormat:               logFormat,
@@ -636,6 +641,9 @@ func (i *Installer) createCRDs(performOperationOnce bool) error {
 	if err = i.CreateOrPatchCRD(TransactionCRDName, k8sclient.GetTransactionCRDYAML(), false); err != nil {
 		return err
 	}
+	if err = i.CreateOrPatchCRD(JobCRDName, k8sclient.GetJobCRDYAML(), false); err != nil {
+		return err
+	}
 	if err = i.CreateOrPatchCRD(SnapshotCRDName, k8sclient.GetSnapshotCRDYAML(), false); err != nil {
 		return err
 	}
@@ -1206,19 +1214,21 @@ func (i *Installer) createOrPatchTridentProtocolSecret(
 	if createSecret {
 		// Create the certificates for the CSI controller's HTTPS REST interface
 		certInfo, err := utils.MakeHTTPCertInfo(commonconfig.CACertName, commonconfig.ServerCertName,
-			commonconfig.ClientCertName)
+			commonconfig.ClientCertName, commonconfig.ControllerCertName)
 		if err != nil {
 			return fmt.Errorf("failed to create Trident X509 certificates; %v", err)
 		}
 
 		// Create the secret for the HTTP certs & keys
 		secretMap = map[string]string{
-			commonconfig.CAKeyFile:      certInfo.CAKey,
-			commonconfig.CACertFile:     certInfo.CACert,
-			commonconfig.ServerKeyFile:  certInfo.ServerKey,
-			commonconfig.ServerCertFile: certInfo.ServerCert,
-			commonconfig.ClientKeyFile:  certInfo.ClientKey,
-			commonconfig.ClientCertFile: certInfo.ClientCert,
+			commonconfig.CAKeyFile:          certInfo.CAKey,
+			commonconfig.CACertFile:         certInfo.CACert,
+			commonconfig.ServerKeyFile:      certInfo.ServerKey,
+			commonconfig.ServerCertFile:     certInfo.ServerCert,
+			commonconfig.ClientKeyFile:      certInfo.ClientKey,
+			commonconfig.ClientCertFile:     certInfo.ClientCert,
+			commonconfig.ControllerKeyFile:  certInfo.ControllerKey,
+			commonconfig.ControllerCertFile: certInfo.ControllerCert,
 		}
 	}
 
@@ -1450,6 +1460,7 @@ func (i *Installer) createOrPatchTridentDaemonSet(
 		Labels:               labels,
 		ControllingCRDetails: controllingCRDetails,
 		EnableForceDetach:    enableForceDetach,
+		ManageMultipath:      manageMultipath,
 		Debug:                debug,
 		Version:              i.client.ServerVersion(),
 		HTTPRequestTimeout:   httpTimeout,
@@ -0,0 +1,85 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/netapp/trident/storage"
+	"github.com/netapp/trident/utils"
+)
+
+// NewTridentJob creates a new job CRD object from a Job object
+func NewTridentJob(job *storage.Job) (*TridentJob, error) {
+	tridentJob := &TridentJob{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "trident.netapp.io/v1",
+			Kind:       "TridentJob",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       NameFix(job.ID),
+			Finalizers: GetTridentFinalizers(),
+		},
+	}
+
+	if err := tridentJob.Apply(job); err != nil {
+		return nil, err
+	}
+
+	return tridentJob, nil
+}
+
+// Apply applies changes from an internal storage.Job object to its Kubernetes CRD equivalent
+func (in *TridentJob) Apply(job *storage.Job) error {
+	if NameFix(job.ID) != in.ObjectMeta.Name {
+		return ErrNamesDontMatch
+	}
+
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	in.Job.Raw = raw
+
+	return nil
+}
+
+// Persistent converts a Kubernetes CRD object into its internal storage.Job object
+func (in *TridentJob) Persistent() (*storage.Job, error) {
+	persistent := &storage.Job{}
+
+	if err := json.Unmarshal(in.Job.Raw, persistent); err != nil {
+		return nil, err
+	}
+
+	return persistent, nil
+}
+
+func (in *TridentJob) GetObjectMeta() metav1.ObjectMeta {
+	return in.ObjectMeta
+}
+
+func (in *TridentJob) GetFinalizers() []string {
+	if in.ObjectMeta.Finalizers != nil {
+		return in.ObjectMeta.Finalizers
+	}
+	return []string{}
+}
+
+func (in *TridentJob) HasTridentFinalizers() bool {
+	for _, finalizerName := range GetTridentFinalizers() {
+		if utils.SliceContainsString(in.ObjectMeta.Finalizers, finalizerName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (in *TridentJob) RemoveTridentFinalizers() {
+	for _, finalizerName := range GetTridentFinalizers() {
+		in.ObjectMeta.Finalizers = utils.RemoveStringFromSlice(in.ObjectMeta.Finalizers, finalizerName)
+	}
+}
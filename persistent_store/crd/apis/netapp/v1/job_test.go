@@ -0,0 +1,50 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
+)
+
+func TestNewJob(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	job := &storage.Job{
+		ID: "8a4c0e6c-5b8e-4b34-9b8b-3f0e2a1c9d10",
+		Op: storage.JobCloneVolume,
+		Config: &storage.VolumeConfig{
+			Version:           string(config.OrchestratorAPIVersion),
+			Name:              "clone",
+			Size:              "1GB",
+			CloneSourceVolume: "source",
+		},
+		BackendUUID: "backend",
+		State:       storage.JobRunning,
+		Progress:    50,
+		Message:     "volume is still being created",
+		Attempts:    2,
+		CreatedTime: now,
+		UpdatedTime: now,
+	}
+
+	tridentJob, err := NewTridentJob(job)
+	if err != nil {
+		t.Fatal("Unable to construct TridentJob CRD: ", err)
+	}
+	assert.Equal(t, "TridentJob", tridentJob.Kind)
+	assert.Equal(t, NameFix(job.ID), tridentJob.Name)
+	assert.Equal(t, GetTridentFinalizers(), tridentJob.Finalizers)
+
+	persistent, err := tridentJob.Persistent()
+	assert.NoError(t, err)
+	assert.Equal(t, job, persistent)
+
+	// A job may only be applied to its own CR
+	other := &storage.Job{ID: "other"}
+	assert.Equal(t, ErrNamesDontMatch, tridentJob.Apply(other))
+}
@@ -40,7 +40,9 @@ func (in *TridentNode) Apply(persistent *utils.Node) error {
 	in.Name = persistent.Name
 	in.IQN = persistent.IQN
 	in.IPs = persistent.IPs
+	in.RESTPort = persistent.RESTPort
 	in.Deleted = persistent.Deleted
+	in.Fenced = persistent.Fenced
 
 	nodePrep, err := json.Marshal(persistent.NodePrep)
 	if err != nil {
@@ -65,7 +67,9 @@ func (in *TridentNode) Persistent() (*utils.Node, error) {
 		IPs:      in.IPs,
 		NodePrep: &utils.NodePrep{},
 		HostInfo: &utils.HostSystem{},
+		RESTPort: in.RESTPort,
 		Deleted:  in.Deleted,
+		Fenced:   in.Fenced,
 	}
 
 	if string(in.NodePrep.Raw) != "" {
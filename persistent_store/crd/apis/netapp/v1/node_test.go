@@ -25,6 +25,7 @@ func TestNewNode(t *testing.T) {
 		IPs: []string{
 			"192.168.0.1",
 		},
+		RESTPort: "17546",
 	}
 
 	// Convert to Kubernetes Object using the NewTridentBackend method
@@ -41,6 +42,10 @@ func TestNewNode(t *testing.T) {
 		t.Fatalf("%v differs:  '%v' != '%v'", "IQN", node.IQN, utilsNode.IQN)
 	}
 
+	if node.RESTPort != utilsNode.RESTPort {
+		t.Fatalf("%v differs:  '%v' != '%v'", "RESTPort", node.RESTPort, utilsNode.RESTPort)
+	}
+
 	if len(node.IPs) != len(utilsNode.IPs) {
 		t.Fatalf("%v differs:  '%v' != '%v'", "IPs", node.IPs, utilsNode.IPs)
 	}
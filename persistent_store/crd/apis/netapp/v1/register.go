@@ -50,6 +50,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&TridentStorageClassList{},
 		&TridentTransaction{},
 		&TridentTransactionList{},
+		&TridentJob{},
+		&TridentJobList{},
 		&TridentNode{},
 		&TridentNodeList{},
 		&TridentVersion{},
@@ -5,6 +5,7 @@ package v1
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -24,6 +25,13 @@ const (
 	// Invalid implies the user supplied a non-feasible TMR spec
 	MirrorStateInvalid  = "invalid"
 	MirrorStateReleased = "released"
+
+	// MirrorHealthHealthy means the last check found the mirror healthy and within its recovery point objective
+	MirrorHealthHealthy = "healthy"
+	// MirrorHealthLagging means the mirror is healthy but the last transfer is older than the recovery point objective
+	MirrorHealthLagging = "lagging"
+	// MirrorHealthUnhealthy means the storage reported the mirror as unhealthy
+	MirrorHealthUnhealthy = "unhealthy"
 )
 
 func GetValidMirrorSpecStates() []string {
@@ -103,5 +111,23 @@ func (in *TridentMirrorRelationship) IsValid() (isValid bool, reason string) {
 			MirrorStateEstablished, MirrorStateReestablished)
 	}
 
+	// If a recovery point objective is specified, ensure it is a positive duration
+	if _, err := in.GetRecoveryPointObjective(); err != nil {
+		return false, err.Error()
+	}
+
 	return true, ""
 }
+
+// GetRecoveryPointObjective returns the maximum acceptable replication lag for the relationship, or zero if
+// no recovery point objective has been set
+func (in *TridentMirrorRelationship) GetRecoveryPointObjective() (time.Duration, error) {
+	if in.Spec.RecoveryPointObjective == "" {
+		return 0, nil
+	}
+	rpo, err := time.ParseDuration(in.Spec.RecoveryPointObjective)
+	if err != nil || rpo <= 0 {
+		return 0, fmt.Errorf(".spec.recoveryPointObjective must be a positive duration such as '15m'")
+	}
+	return rpo, nil
+}
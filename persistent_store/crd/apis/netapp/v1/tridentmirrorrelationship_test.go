@@ -4,6 +4,7 @@ package v1
 
 import (
 	"testing"
+	"time"
 )
 
 func TestIsValid(t *testing.T) {
@@ -150,4 +151,32 @@ func TestIsValid(t *testing.T) {
 	if actual {
 		t.Fatalf("TridentMirrorRelationship should be valid, %v", invalidMR)
 	}
+
+	// Test a recovery point objective that is not a positive duration
+	for _, rpo := range []string{"15", "-5m", "0s"} {
+		invalidMR = &TridentMirrorRelationship{
+			Spec: TridentMirrorRelationshipSpec{
+				VolumeMappings:         []*TridentMirrorRelationshipVolumeMapping{{LocalPVCName: "test"}},
+				RecoveryPointObjective: rpo,
+			},
+		}
+		actual, _ = invalidMR.IsValid()
+		if actual {
+			t.Fatalf("TridentMirrorRelationship should be invalid, %v", invalidMR)
+		}
+	}
+}
+
+func TestGetRecoveryPointObjective(t *testing.T) {
+	mr := &TridentMirrorRelationship{}
+	rpo, err := mr.GetRecoveryPointObjective()
+	if err != nil || rpo != 0 {
+		t.Fatalf("Expected no recovery point objective, got %v, %v", rpo, err)
+	}
+
+	mr.Spec.RecoveryPointObjective = "15m"
+	rpo, err = mr.GetRecoveryPointObjective()
+	if err != nil || rpo != 15*time.Minute {
+		t.Fatalf("Expected a 15m recovery point objective, got %v, %v", rpo, err)
+	}
 }
@@ -121,10 +121,11 @@ type TridentMirrorRelationshipList struct {
 
 // TridentMirrorRelationshipSpec defines the desired state of TridentMirrorRelationship
 type TridentMirrorRelationshipSpec struct {
-	MirrorState         string                                    `json:"state"`
-	ReplicationPolicy   string                                    `json:"replicationPolicy"`
-	ReplicationSchedule string                                    `json:"replicationSchedule"`
-	VolumeMappings      []*TridentMirrorRelationshipVolumeMapping `json:"volumeMappings"`
+	MirrorState            string                                    `json:"state"`
+	ReplicationPolicy      string                                    `json:"replicationPolicy"`
+	ReplicationSchedule    string                                    `json:"replicationSchedule"`
+	RecoveryPointObjective string                                    `json:"recoveryPointObjective,omitempty"`
+	VolumeMappings         []*TridentMirrorRelationshipVolumeMapping `json:"volumeMappings"`
 }
 type TridentMirrorRelationshipVolumeMapping struct {
 	RemoteVolumeHandle     string `json:"remoteVolumeHandle"`
@@ -141,6 +142,9 @@ type TridentMirrorRelationshipCondition struct {
 	RemoteVolumeHandle  string `json:"remoteVolumeHandle"`
 	ReplicationPolicy   string `json:"replicationPolicy"`
 	ReplicationSchedule string `json:"replicationSchedule"`
+	Health              string `json:"health,omitempty"`
+	LastTransferTime    string `json:"lastTransferTime,omitempty"`
+	ReplicationLag      string `json:"replicationLag,omitempty"`
 }
 
 // TridentMirrorRelationshipStatus defines the observed state of TridentMirrorRelationship
@@ -300,6 +304,28 @@ type TridentTransactionList struct {
 	Items []*TridentTransaction `json:"items"`
 }
 
+// TridentJob defines a Trident asynchronous job.
+// +genclient
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentJob struct {
+	metav1.TypeMeta `json:",inline"`
+	// +k8s:openapi-gen=false
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Job is the job struct
+	Job runtime.RawExtension `json:"job"`
+}
+
+// TridentJobList is a list of TridentJob objects.
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TridentJobList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of TridentJob objects
+	Items []*TridentJob `json:"items"`
+}
+
 // TridentNode defines a Trident CSI node object.
 // +genclient
 // +k8s:openapi-gen=true
@@ -318,8 +344,12 @@ type TridentNode struct {
 	NodePrep runtime.RawExtension `json:"nodePrep,omitempty"`
 	// HostInfo contains information about the node's host machine
 	HostInfo runtime.RawExtension `json:"hostInfo,omitempty"`
+	// RESTPort is the port on which the node's HTTPS REST interface is listening
+	RESTPort string `json:"restPort,omitempty"`
 	// Deleted indicates that Trident received an event that the node has been removed
 	Deleted bool `json:"deleted"`
+	// Fenced indicates that the node's access to storage has been revoked
+	Fenced bool `json:"fenced,omitempty"`
 }
 
 // TridentNodeList is a list of TridentNode objects.
@@ -22,6 +22,10 @@ func (c *FakeTridentV1) TridentBackendConfigs(namespace string) v1.TridentBacken
 	return &FakeTridentBackendConfigs{c, namespace}
 }
 
+func (c *FakeTridentV1) TridentJobs(namespace string) v1.TridentJobInterface {
+	return &FakeTridentJobs{c, namespace}
+}
+
 func (c *FakeTridentV1) TridentMirrorRelationships(namespace string) v1.TridentMirrorRelationshipInterface {
 	return &FakeTridentMirrorRelationships{c, namespace}
 }
@@ -0,0 +1,116 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	netappv1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeTridentJobs implements TridentJobInterface
+type FakeTridentJobs struct {
+	Fake *FakeTridentV1
+	ns   string
+}
+
+var tridentjobsResource = schema.GroupVersionResource{Group: "trident.netapp.io", Version: "v1", Resource: "tridentjobs"}
+
+var tridentjobsKind = schema.GroupVersionKind{Group: "trident.netapp.io", Version: "v1", Kind: "TridentJob"}
+
+// Get takes name of the tridentJob, and returns the corresponding tridentJob object, and an error if there is any.
+func (c *FakeTridentJobs) Get(ctx context.Context, name string, options v1.GetOptions) (result *netappv1.TridentJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(tridentjobsResource, c.ns, name), &netappv1.TridentJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*netappv1.TridentJob), err
+}
+
+// List takes label and field selectors, and returns the list of TridentJobs that match those selectors.
+func (c *FakeTridentJobs) List(ctx context.Context, opts v1.ListOptions) (result *netappv1.TridentJobList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(tridentjobsResource, tridentjobsKind, c.ns, opts), &netappv1.TridentJobList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &netappv1.TridentJobList{ListMeta: obj.(*netappv1.TridentJobList).ListMeta}
+	for _, item := range obj.(*netappv1.TridentJobList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested tridentJobs.
+func (c *FakeTridentJobs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(tridentjobsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a tridentJob and creates it.  Returns the server's representation of the tridentJob, and an error, if there is any.
+func (c *FakeTridentJobs) Create(ctx context.Context, tridentJob *netappv1.TridentJob, opts v1.CreateOptions) (result *netappv1.TridentJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(tridentjobsResource, c.ns, tridentJob), &netappv1.TridentJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*netappv1.TridentJob), err
+}
+
+// Update takes the representation of a tridentJob and updates it. Returns the server's representation of the tridentJob, and an error, if there is any.
+func (c *FakeTridentJobs) Update(ctx context.Context, tridentJob *netappv1.TridentJob, opts v1.UpdateOptions) (result *netappv1.TridentJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(tridentjobsResource, c.ns, tridentJob), &netappv1.TridentJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*netappv1.TridentJob), err
+}
+
+// Delete takes name of the tridentJob and deletes it. Returns an error if one occurs.
+func (c *FakeTridentJobs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(tridentjobsResource, c.ns, name), &netappv1.TridentJob{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeTridentJobs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(tridentjobsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &netappv1.TridentJobList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched tridentJob.
+func (c *FakeTridentJobs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *netappv1.TridentJob, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(tridentjobsResource, c.ns, name, pt, data, subresources...), &netappv1.TridentJob{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*netappv1.TridentJob), err
+}
@@ -8,6 +8,8 @@ type TridentBackendExpansion interface{}
 
 type TridentBackendConfigExpansion interface{}
 
+type TridentJobExpansion interface{}
+
 type TridentMirrorRelationshipExpansion interface{}
 
 type TridentNodeExpansion interface{}
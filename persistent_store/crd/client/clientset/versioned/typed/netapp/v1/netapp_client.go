@@ -14,6 +14,7 @@ type TridentV1Interface interface {
 	RESTClient() rest.Interface
 	TridentBackendsGetter
 	TridentBackendConfigsGetter
+	TridentJobsGetter
 	TridentMirrorRelationshipsGetter
 	TridentNodesGetter
 	TridentSnapshotsGetter
@@ -39,6 +40,10 @@ func (c *TridentV1Client) TridentBackendConfigs(namespace string) TridentBackend
 	return newTridentBackendConfigs(c, namespace)
 }
 
+func (c *TridentV1Client) TridentJobs(namespace string) TridentJobInterface {
+	return newTridentJobs(c, namespace)
+}
+
 func (c *TridentV1Client) TridentMirrorRelationships(namespace string) TridentMirrorRelationshipInterface {
 	return newTridentMirrorRelationships(c, namespace)
 }
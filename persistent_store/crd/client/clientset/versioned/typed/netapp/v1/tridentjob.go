@@ -0,0 +1,164 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	scheme "github.com/netapp/trident/persistent_store/crd/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// TridentJobsGetter has a method to return a TridentJobInterface.
+// A group's client should implement this interface.
+type TridentJobsGetter interface {
+	TridentJobs(namespace string) TridentJobInterface
+}
+
+// TridentJobInterface has methods to work with TridentJob resources.
+type TridentJobInterface interface {
+	Create(ctx context.Context, tridentJob *v1.TridentJob, opts metav1.CreateOptions) (*v1.TridentJob, error)
+	Update(ctx context.Context, tridentJob *v1.TridentJob, opts metav1.UpdateOptions) (*v1.TridentJob, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.TridentJob, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.TridentJobList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.TridentJob, err error)
+	TridentJobExpansion
+}
+
+// tridentJobs implements TridentJobInterface
+type tridentJobs struct {
+	client rest.Interface
+	ns     string
+}
+
+// newTridentJobs returns a TridentJobs
+func newTridentJobs(c *TridentV1Client, namespace string) *tridentJobs {
+	return &tridentJobs{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the tridentJob, and returns the corresponding tridentJob object, and an error if there is any.
+func (c *tridentJobs) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.TridentJob, err error) {
+	result = &v1.TridentJob{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("tridentjobs").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of TridentJobs that match those selectors.
+func (c *tridentJobs) List(ctx context.Context, opts metav1.ListOptions) (result *v1.TridentJobList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.TridentJobList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("tridentjobs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested tridentJobs.
+func (c *tridentJobs) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("tridentjobs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a tridentJob and creates it.  Returns the server's representation of the tridentJob, and an error, if there is any.
+func (c *tridentJobs) Create(ctx context.Context, tridentJob *v1.TridentJob, opts metav1.CreateOptions) (result *v1.TridentJob, err error) {
+	result = &v1.TridentJob{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("tridentjobs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tridentJob).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a tridentJob and updates it. Returns the server's representation of the tridentJob, and an error, if there is any.
+func (c *tridentJobs) Update(ctx context.Context, tridentJob *v1.TridentJob, opts metav1.UpdateOptions) (result *v1.TridentJob, err error) {
+	result = &v1.TridentJob{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("tridentjobs").
+		Name(tridentJob.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(tridentJob).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the tridentJob and deletes it. Returns an error if one occurs.
+func (c *tridentJobs) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("tridentjobs").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *tridentJobs) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("tridentjobs").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched tridentJob.
+func (c *tridentJobs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.TridentJob, err error) {
+	result = &v1.TridentJob{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("tridentjobs").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -43,6 +43,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentBackends().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tridentbackendconfigs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentBackendConfigs().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("tridentjobs"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentJobs().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tridentmirrorrelationships"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Trident().V1().TridentMirrorRelationships().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tridentnodes"):
@@ -14,6 +14,8 @@ type Interface interface {
 	TridentBackends() TridentBackendInformer
 	// TridentBackendConfigs returns a TridentBackendConfigInformer.
 	TridentBackendConfigs() TridentBackendConfigInformer
+	// TridentJobs returns a TridentJobInformer.
+	TridentJobs() TridentJobInformer
 	// TridentMirrorRelationships returns a TridentMirrorRelationshipInformer.
 	TridentMirrorRelationships() TridentMirrorRelationshipInformer
 	// TridentNodes returns a TridentNodeInformer.
@@ -57,6 +59,11 @@ func (v *version) TridentBackendConfigs() TridentBackendConfigInformer {
 	return &tridentBackendConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TridentJobs returns a TridentJobInformer.
+func (v *version) TridentJobs() TridentJobInformer {
+	return &tridentJobInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TridentMirrorRelationships returns a TridentMirrorRelationshipInformer.
 func (v *version) TridentMirrorRelationships() TridentMirrorRelationshipInformer {
 	return &tridentMirrorRelationshipInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
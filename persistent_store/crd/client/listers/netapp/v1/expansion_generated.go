@@ -20,6 +20,14 @@ type TridentBackendConfigListerExpansion interface{}
 // TridentBackendConfigNamespaceLister.
 type TridentBackendConfigNamespaceListerExpansion interface{}
 
+// TridentJobListerExpansion allows custom methods to be added to
+// TridentJobLister.
+type TridentJobListerExpansion interface{}
+
+// TridentJobNamespaceListerExpansion allows custom methods to be added to
+// TridentJobNamespaceLister.
+type TridentJobNamespaceListerExpansion interface{}
+
 // TridentMirrorRelationshipListerExpansion allows custom methods to be added to
 // TridentMirrorRelationshipLister.
 type TridentMirrorRelationshipListerExpansion interface{}
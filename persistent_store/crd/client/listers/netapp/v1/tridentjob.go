@@ -0,0 +1,80 @@
+// Copyright 2021 NetApp, Inc. All Rights Reserved.
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/netapp/trident/persistent_store/crd/apis/netapp/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// TridentJobLister helps list TridentJobs.
+type TridentJobLister interface {
+	// List lists all TridentJobs in the indexer.
+	List(selector labels.Selector) (ret []*v1.TridentJob, err error)
+	// TridentJobs returns an object that can list and get TridentJobs.
+	TridentJobs(namespace string) TridentJobNamespaceLister
+	TridentJobListerExpansion
+}
+
+// tridentJobLister implements the TridentJobLister interface.
+type tridentJobLister struct {
+	indexer cache.Indexer
+}
+
+// NewTridentJobLister returns a new TridentJobLister.
+func NewTridentJobLister(indexer cache.Indexer) TridentJobLister {
+	return &tridentJobLister{indexer: indexer}
+}
+
+// List lists all TridentJobs in the indexer.
+func (s *tridentJobLister) List(selector labels.Selector) (ret []*v1.TridentJob, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.TridentJob))
+	})
+	return ret, err
+}
+
+// TridentJobs returns an object that can list and get TridentJobs.
+func (s *tridentJobLister) TridentJobs(namespace string) TridentJobNamespaceLister {
+	return tridentJobNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// TridentJobNamespaceLister helps list and get TridentJobs.
+type TridentJobNamespaceLister interface {
+	// List lists all TridentJobs in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.TridentJob, err error)
+	// Get retrieves the TridentJob from the indexer for a given namespace and name.
+	Get(name string) (*v1.TridentJob, error)
+	TridentJobNamespaceListerExpansion
+}
+
+// tridentJobNamespaceLister implements the TridentJobNamespaceLister
+// interface.
+type tridentJobNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all TridentJobs in the indexer for a given namespace.
+func (s tridentJobNamespaceLister) List(selector labels.Selector) (ret []*v1.TridentJob, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.TridentJob))
+	})
+	return ret, err
+}
+
+// Get retrieves the TridentJob from the indexer for a given namespace and name.
+func (s tridentJobNamespaceLister) Get(name string) (*v1.TridentJob, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("tridentjob"), name)
+	}
+	return obj.(*v1.TridentJob), nil
+}
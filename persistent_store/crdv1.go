@@ -1178,6 +1178,66 @@ func (k *CRDClientV1) DeleteVolumeTransaction(ctx context.Context, volTxn *stora
 		k.deleteOpts())
 }
 
+func (k *CRDClientV1) AddJob(ctx context.Context, job *storage.Job) error {
+	newJob, err := v1.NewTridentJob(job)
+	if err != nil {
+		return err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"op":   job.Op,
+		"name": v1.NameFix(job.ID),
+	}).Debug("AddJob")
+
+	_, err = k.crdClient.TridentV1().TridentJobs(k.namespace).Create(ctx, newJob, createOpts)
+	return err
+}
+
+func (k *CRDClientV1) UpdateJob(ctx context.Context, update *storage.Job) error {
+	tjob, err := k.crdClient.TridentV1().TridentJobs(k.namespace).Get(ctx, v1.NameFix(update.ID), getOpts)
+	if err != nil {
+		return err
+	}
+
+	if err = tjob.Apply(update); err != nil {
+		return err
+	}
+
+	_, err = k.crdClient.TridentV1().TridentJobs(k.namespace).Update(ctx, tjob, updateOpts)
+	return err
+}
+
+func (k *CRDClientV1) GetJobs(ctx context.Context) ([]*storage.Job, error) {
+	jobList, err := k.crdClient.TridentV1().TridentJobs(k.namespace).List(ctx, listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*storage.Job, 0)
+
+	for _, item := range jobList.Items {
+		if !item.ObjectMeta.DeletionTimestamp.IsZero() {
+			Logc(ctx).WithFields(log.Fields{
+				"Name":              item.Name,
+				"DeletionTimestamp": item.DeletionTimestamp,
+			}).Debug("GetJobs skipping deleted Job")
+			continue
+		}
+
+		if job, err := item.Persistent(); err != nil {
+			return nil, err
+		} else {
+			results = append(results, job)
+		}
+	}
+
+	return results, nil
+}
+
+func (k *CRDClientV1) DeleteJob(ctx context.Context, job *storage.Job) error {
+	return k.crdClient.TridentV1().TridentJobs(k.namespace).Delete(ctx, v1.NameFix(job.ID), k.deleteOpts())
+}
+
 func (k *CRDClientV1) AddStorageClass(ctx context.Context, sc *storageclass.StorageClass) error {
 	persistentSC, err := v1.NewTridentStorageClass(sc.ConstructPersistent())
 	if err != nil {
@@ -5,6 +5,7 @@ package persistentstore
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/google/uuid"
 
@@ -23,6 +24,8 @@ type InMemoryClient struct {
 	storageClassesAdded     int
 	volumeTxns              map[string]*storage.VolumeTransaction
 	volumeTxnsAdded         int
+	jobs                    map[string]*storage.Job
+	jobsMutex               sync.Mutex // jobs are saved by job workers outside any orchestrator lock
 	volumePublications      map[string]*utils.VolumePublication
 	volumePublicationsAdded int
 	version                 *config.PersistentStateVersion
@@ -39,6 +42,7 @@ func NewInMemoryClient() *InMemoryClient {
 		volumes:            make(map[string]*storage.VolumeExternal),
 		storageClasses:     make(map[string]*sc.Persistent),
 		volumeTxns:         make(map[string]*storage.VolumeTransaction),
+		jobs:               make(map[string]*storage.Job),
 		volumePublications: make(map[string]*utils.VolumePublication),
 		nodes:              make(map[string]*utils.Node),
 		snapshots:          make(map[string]*storage.SnapshotPersistent),
@@ -290,6 +294,50 @@ func (c *InMemoryClient) DeleteVolumeTransaction(_ context.Context, volTxn *stor
 	return nil
 }
 
+func (c *InMemoryClient) AddJob(_ context.Context, job *storage.Job) error {
+	c.jobsMutex.Lock()
+	defer c.jobsMutex.Unlock()
+
+	if _, ok := c.jobs[job.ID]; ok {
+		return fmt.Errorf("job %s already exists", job.ID)
+	}
+	c.jobs[job.ID] = job.ConstructClone()
+	return nil
+}
+
+func (c *InMemoryClient) UpdateJob(_ context.Context, job *storage.Job) error {
+	c.jobsMutex.Lock()
+	defer c.jobsMutex.Unlock()
+
+	if _, ok := c.jobs[job.ID]; !ok {
+		return NewPersistentStoreError(KeyNotFoundErr, job.ID)
+	}
+	c.jobs[job.ID] = job.ConstructClone()
+	return nil
+}
+
+func (c *InMemoryClient) GetJobs(context.Context) ([]*storage.Job, error) {
+	c.jobsMutex.Lock()
+	defer c.jobsMutex.Unlock()
+
+	ret := make([]*storage.Job, 0, len(c.jobs))
+	for _, job := range c.jobs {
+		ret = append(ret, job.ConstructClone())
+	}
+	return ret, nil
+}
+
+func (c *InMemoryClient) DeleteJob(_ context.Context, job *storage.Job) error {
+	c.jobsMutex.Lock()
+	defer c.jobsMutex.Unlock()
+
+	if _, ok := c.jobs[job.ID]; !ok {
+		return NewPersistentStoreError(KeyNotFoundErr, job.ID)
+	}
+	delete(c.jobs, job.ID)
+	return nil
+}
+
 func (c *InMemoryClient) AddStorageClass(_ context.Context, s *sc.StorageClass) error {
 	storageClass := s.ConstructPersistent()
 	if _, ok := c.storageClasses[storageClass.GetName()]; ok {
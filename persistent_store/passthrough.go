@@ -409,6 +409,22 @@ func (c *PassthroughClient) DeleteVolumeTransaction(context.Context, *storage.Vo
 	return nil
 }
 
+func (c *PassthroughClient) AddJob(context.Context, *storage.Job) error {
+	return nil
+}
+
+func (c *PassthroughClient) UpdateJob(context.Context, *storage.Job) error {
+	return nil
+}
+
+func (c *PassthroughClient) GetJobs(context.Context) ([]*storage.Job, error) {
+	return make([]*storage.Job, 0), nil
+}
+
+func (c *PassthroughClient) DeleteJob(context.Context, *storage.Job) error {
+	return nil
+}
+
 func (c *PassthroughClient) AddStorageClass(context.Context, *sc.StorageClass) error {
 	return nil
 }
@@ -441,23 +457,68 @@ func (c *PassthroughClient) DeleteNode(context.Context, *utils.Node) error {
 	return nil
 }
 
-func (c *PassthroughClient) AddVolumePublication(context.Context, *utils.VolumePublication) error {
-	return nil
+// AddVolumePublication records a publication with its volume on the storage system, if the volume's backend
+// is able to do so.  Docker hosts share no other store, so these records are how each host learns which
+// hosts are using a volume.
+func (c *PassthroughClient) AddVolumePublication(ctx context.Context, vp *utils.VolumePublication) error {
+	return c.updateVolumePublications(ctx, vp, false)
 }
 
-func (c *PassthroughClient) UpdateVolumePublication(context.Context, *utils.VolumePublication) error {
-	return nil
+func (c *PassthroughClient) UpdateVolumePublication(ctx context.Context, vp *utils.VolumePublication) error {
+	return c.updateVolumePublications(ctx, vp, false)
 }
 
 func (c *PassthroughClient) GetVolumePublication(_ context.Context, vpName string) (*utils.VolumePublication, error) {
 	return nil, NewPersistentStoreError(KeyNotFoundErr, vpName)
 }
 
-func (c *PassthroughClient) GetVolumePublications(context.Context) ([]*utils.VolumePublication, error) {
-	return make([]*utils.VolumePublication, 0), nil
+// GetVolumePublications reads the publications recorded with each volume on the storage systems.
+func (c *PassthroughClient) GetVolumePublications(ctx context.Context) ([]*utils.VolumePublication, error) {
+	publications := make([]*utils.VolumePublication, 0)
+	for _, backend := range c.liveBackends {
+		for _, volume := range backend.Volumes() {
+			volumePublications, err := backend.GetVolumePublications(ctx, volume.Config)
+			if utils.IsUnsupportedError(err) {
+				break
+			} else if err != nil {
+				Logc(ctx).WithFields(log.Fields{
+					"backend": backend.Name(),
+					"volume":  volume.Config.Name,
+				}).WithError(err).Error("Could not read volume publications.")
+				continue
+			}
+			publications = append(publications, volumePublications...)
+		}
+	}
+	return publications, nil
 }
 
-func (c *PassthroughClient) DeleteVolumePublication(context.Context, *utils.VolumePublication) error {
+func (c *PassthroughClient) DeleteVolumePublication(ctx context.Context, vp *utils.VolumePublication) error {
+	return c.updateVolumePublications(ctx, vp, true)
+}
+
+// updateVolumePublications saves or removes a publication in the records kept with its volume.  The backend
+// updates the records atomically, since other hosts may change them at the same time.
+func (c *PassthroughClient) updateVolumePublications(
+	ctx context.Context, vp *utils.VolumePublication, remove bool,
+) error {
+	for _, backend := range c.liveBackends {
+		volume, ok := backend.Volumes()[vp.VolumeName]
+		if !ok {
+			continue
+		}
+
+		var err error
+		if remove {
+			err = backend.DeleteVolumePublication(ctx, volume.Config, vp.NodeName)
+		} else {
+			err = backend.AddVolumePublication(ctx, volume.Config, vp, false)
+		}
+		if utils.IsUnsupportedError(err) {
+			return nil
+		}
+		return err
+	}
 	return nil
 }
 
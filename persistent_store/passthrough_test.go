@@ -9,7 +9,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
+
 	"github.com/netapp/trident/config"
+	mockstorage "github.com/netapp/trident/mocks/mock_storage"
 	"github.com/netapp/trident/storage"
 	sa "github.com/netapp/trident/storage_attribute"
 	sc "github.com/netapp/trident/storage_class"
@@ -935,3 +938,57 @@ func TestPassthroughClient_DeleteSnapshots(t *testing.T) {
 		t.Error("Could not delete snapshots from passthrough client!")
 	}
 }
+
+func TestPassthroughClient_VolumePublications(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	backend := mockstorage.NewMockBackend(mockCtrl)
+	volume := &storage.Volume{Config: &storage.VolumeConfig{Name: "vol1"}}
+	backend.EXPECT().Volumes().Return(map[string]*storage.Volume{"vol1": volume}).AnyTimes()
+
+	p := newPassthroughClient()
+	p.liveBackends["backend1"] = backend
+
+	// Records are kept with the volume, and records of other hosts are left alone
+	host1 := &utils.VolumePublication{Name: "vol1.host1", VolumeName: "vol1", NodeName: "host1"}
+	backend.EXPECT().AddVolumePublication(ctx(), volume.Config, host1, false).Return(nil)
+	if err := p.AddVolumePublication(ctx(), host1); err != nil {
+		t.Errorf("Could not add volume publication; %v", err)
+	}
+
+	host2 := &utils.VolumePublication{Name: "vol1.host2", VolumeName: "vol1", NodeName: "host2"}
+	backend.EXPECT().GetVolumePublications(ctx(), volume.Config).Return(
+		[]*utils.VolumePublication{host2, host1}, nil)
+	backend.EXPECT().DeleteVolumePublication(ctx(), volume.Config, "host1").Return(nil)
+	if err := p.DeleteVolumePublication(ctx(), host1); err != nil {
+		t.Errorf("Could not delete volume publication; %v", err)
+	}
+
+	publications, err := p.GetVolumePublications(ctx())
+	if err != nil || len(publications) != 2 {
+		t.Errorf("Unexpected volume publications %v; %v", publications, err)
+	}
+
+	// Publications of unknown volumes are ignored
+	unknown := &utils.VolumePublication{Name: "vol2.host1", VolumeName: "vol2", NodeName: "host1"}
+	if err = p.AddVolumePublication(ctx(), unknown); err != nil {
+		t.Errorf("Could not add volume publication; %v", err)
+	}
+}
+
+func TestPassthroughClient_VolumePublicationsUnsupported(t *testing.T) {
+	p := newPassthroughClient()
+	fakeBackend := getFakeBackend()
+	fakeVolume := getFakeVolume(fakeBackend)
+	fakeBackend.Volumes()[fakeVolume.Config.Name] = fakeVolume
+	p.liveBackends[fakeBackend.Name()] = fakeBackend
+
+	vp := &utils.VolumePublication{Name: "fake_volume.host1", VolumeName: fakeVolume.Config.Name, NodeName: "host1"}
+	if err := p.AddVolumePublication(ctx(), vp); err != nil {
+		t.Errorf("Could not add volume publication; %v", err)
+	}
+
+	publications, err := p.GetVolumePublications(ctx())
+	if err != nil || len(publications) != 0 {
+		t.Errorf("Unexpected volume publications %v; %v", publications, err)
+	}
+}
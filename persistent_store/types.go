@@ -67,6 +67,11 @@ type Client interface {
 	)
 	DeleteVolumeTransaction(ctx context.Context, volTxn *storage.VolumeTransaction) error
 
+	AddJob(ctx context.Context, job *storage.Job) error
+	UpdateJob(ctx context.Context, job *storage.Job) error
+	GetJobs(ctx context.Context) ([]*storage.Job, error)
+	DeleteJob(ctx context.Context, job *storage.Job) error
+
 	AddStorageClass(ctx context.Context, sc *storageclass.StorageClass) error
 	GetStorageClass(ctx context.Context, scName string) (*storageclass.Persistent, error)
 	GetStorageClasses(ctx context.Context) ([]*storageclass.Persistent, error)
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/RoaringBitmap/roaring"
@@ -75,6 +76,29 @@ type Unpublisher interface {
 	Unpublish(ctx context.Context, volConfig *VolumeConfig, publishInfo *utils.VolumePublishInfo) error
 }
 
+// HealthProber is implemented by drivers that can cheaply check whether their storage system is responding
+type HealthProber interface {
+	ProbeHealth(ctx context.Context) error
+}
+
+// PerformanceReporter is implemented by drivers that can report how much of each storage pool's performance
+// capacity is in use.  Utilization is a percentage keyed by pool name; pools that are not included are not
+// reported on.
+type PerformanceReporter interface {
+	GetPoolUtilization(ctx context.Context) (map[string]float64, error)
+}
+
+// VolumePublicationRecorder is implemented by drivers that can record a volume's publications with the volume
+// on the storage system.  Deployments without a shared persistent store, such as Docker hosts, rely on these
+// records to learn which hosts are using a volume.
+type VolumePublicationRecorder interface {
+	GetVolumePublications(ctx context.Context, volConfig *VolumeConfig) ([]*utils.VolumePublication, error)
+	AddVolumePublication(
+		ctx context.Context, volConfig *VolumeConfig, publication *utils.VolumePublication, exclusive bool,
+	) error
+	DeleteVolumePublication(ctx context.Context, volConfig *VolumeConfig, nodeName string) error
+}
+
 // Mirrorer provides a common interface for backends that support mirror replication
 type Mirrorer interface {
 	EstablishMirror(
@@ -89,6 +113,14 @@ type Mirrorer interface {
 	GetMirrorStatus(ctx context.Context, localVolumeHandle, remoteVolumeHandle string) (string, error)
 	ReleaseMirror(ctx context.Context, localVolumeHandle string) error
 	GetReplicationDetails(ctx context.Context, localVolumeHandle, remoteVolumeHandle string) (string, string, error)
+	GetMirrorHealth(ctx context.Context, localVolumeHandle, remoteVolumeHandle string) (*MirrorHealth, error)
+}
+
+// MirrorHealth describes the health of a mirror relationship as seen from its destination
+type MirrorHealth struct {
+	Healthy          bool       `json:"healthy"`
+	UnhealthyReason  string     `json:"unhealthyReason,omitempty"`
+	LastTransferTime *time.Time `json:"lastTransferTime,omitempty"`
 }
 
 type StorageBackend struct {
@@ -99,8 +131,14 @@ type StorageBackend struct {
 	state              BackendState
 	storage            map[string]Pool
 	volumes            map[string]*Volume
+	volumesLock        sync.RWMutex
 	configRef          string
 	nodeAccessUpToDate bool
+	// nodeAccessGeneration counts the times node access was invalidated, so that a reconcile that overlapped an
+	// invalidation does not mark node access up to date
+	nodeAccessGeneration uint64
+	nodeAccessLock       sync.Mutex
+	limiter              *backendLimiter
 }
 
 func (b *StorageBackend) Driver() Driver {
@@ -151,6 +189,9 @@ func (b *StorageBackend) SetStorage(Storage map[string]Pool) {
 	b.storage = Storage
 }
 
+// Volumes returns the backend's volume cache.  Volumes created, cloned, or deleted by the backend may be added
+// to or removed from the cache concurrently, so callers that modify the returned map must hold the orchestrator's
+// lock for this backend.
 func (b *StorageBackend) Volumes() map[string]*Volume {
 	return b.volumes
 }
@@ -239,6 +280,9 @@ func NewStorageBackend(ctx context.Context, driver Driver) (*StorageBackend, err
 		return nil, err
 	}
 
+	backend.limiter = newBackendLimiter(backend.name, driver.GetCommonConfig(ctx))
+	backend.limiter.probe = backend.probe
+
 	return &backend, nil
 }
 
@@ -312,7 +356,8 @@ func (b *StorageBackend) AddVolume(
 
 	// Add volume to the backend
 	volumeExists := false
-	if err = b.driver.Create(ctx, volConfig, storagePool, volAttributes); err != nil {
+	err = b.call(ctx, func() error { return b.driver.Create(ctx, volConfig, storagePool, volAttributes) })
+	if err != nil {
 		if drivers.IsVolumeExistsError(err) {
 
 			// Implement idempotency by ignoring the error if the volume exists already
@@ -330,7 +375,7 @@ func (b *StorageBackend) AddVolume(
 	}
 
 	// Always perform the follow-up steps
-	if err = b.driver.CreateFollowup(ctx, volConfig); err != nil {
+	if err = b.call(ctx, func() error { return b.driver.CreateFollowup(ctx, volConfig) }); err != nil {
 
 		Logc(ctx).WithFields(log.Fields{
 			"backend":      b.name,
@@ -347,7 +392,7 @@ func (b *StorageBackend) AddVolume(
 				"volume":  volConfig.InternalName,
 			}).Errorf("CreateFollowup failed for newly created volume, deleting the volume.")
 
-			errDestroy := b.driver.Destroy(ctx, volConfig)
+			errDestroy := b.call(ctx, func() error { return b.driver.Destroy(ctx, volConfig) })
 			if errDestroy != nil {
 				Logc(ctx).WithFields(log.Fields{
 					"backend": b.name,
@@ -362,7 +407,7 @@ func (b *StorageBackend) AddVolume(
 	}
 
 	vol := NewVolume(volConfig, b.backendUUID, storagePool.Name(), false, VolumeStateOnline)
-	b.volumes[vol.Config.Name] = vol
+	b.CacheVolume(vol)
 	return vol, nil
 }
 
@@ -425,7 +470,10 @@ func (b *StorageBackend) CloneVolume(
 
 	// Clone volume on the backend
 	volumeExists := false
-	if err := b.driver.CreateClone(ctx, sourceVolConfig, cloneVolConfig, storagePool); err != nil {
+	err := b.call(ctx, func() error {
+		return b.driver.CreateClone(ctx, sourceVolConfig, cloneVolConfig, storagePool)
+	})
+	if err != nil {
 		if drivers.IsVolumeExistsError(err) {
 
 			// Implement idempotency by ignoring the error if the volume exists already
@@ -444,7 +492,7 @@ func (b *StorageBackend) CloneVolume(
 
 	// The clone may not be fully created when the clone API returns, so wait here until it exists.
 	checkCloneExists := func() error {
-		return b.driver.Get(ctx, cloneVolConfig.InternalName)
+		return b.call(ctx, func() error { return b.driver.Get(ctx, cloneVolConfig.InternalName) })
 	}
 	cloneExistsNotify := func(err error, duration time.Duration) {
 		Logc(ctx).WithField("increment", duration).Debug("Clone not yet present, waiting.")
@@ -463,11 +511,11 @@ func (b *StorageBackend) CloneVolume(
 		Logc(ctx).WithField("clone_volume", cloneVolConfig.Name).Debug("Clone found.")
 	}
 
-	if err := b.driver.CreateFollowup(ctx, cloneVolConfig); err != nil {
+	if err := b.call(ctx, func() error { return b.driver.CreateFollowup(ctx, cloneVolConfig) }); err != nil {
 
 		// If follow-up fails and we just created the volume, clean up by deleting it
 		if !volumeExists || retry {
-			errDestroy := b.driver.Destroy(ctx, cloneVolConfig)
+			errDestroy := b.call(ctx, func() error { return b.driver.Destroy(ctx, cloneVolConfig) })
 			if errDestroy != nil {
 				Logc(ctx).WithFields(log.Fields{
 					"backend": b.name,
@@ -491,7 +539,7 @@ func (b *StorageBackend) CloneVolume(
 	}
 
 	vol := NewVolume(cloneVolConfig, b.backendUUID, poolName, false, VolumeStateOnline)
-	b.volumes[vol.Config.Name] = vol
+	b.CacheVolume(vol)
 	return vol, nil
 }
 
@@ -511,12 +559,12 @@ func (b *StorageBackend) PublishVolume(
 	}
 	// This is to ensure all backend volume mounting has occurred
 	// FIXME(ameade): Should probably be renamed from createfollowup
-	if err := b.driver.CreateFollowup(ctx, volConfig); err != nil {
+	if err := b.call(ctx, func() error { return b.driver.CreateFollowup(ctx, volConfig) }); err != nil {
 		// TODO: Should this error be obfuscated to a more general error?
 		return err
 	}
 
-	return b.driver.Publish(ctx, volConfig, publishInfo)
+	return b.call(ctx, func() error { return b.driver.Publish(ctx, volConfig, publishInfo) })
 }
 
 func (b *StorageBackend) UnpublishVolume(
@@ -540,7 +588,7 @@ func (b *StorageBackend) UnpublishVolume(
 	if unpublisher, ok := b.driver.(Unpublisher); !ok {
 		return nil
 	} else {
-		return unpublisher.Unpublish(ctx, volConfig, publishInfo)
+		return b.call(ctx, func() error { return unpublisher.Unpublish(ctx, volConfig, publishInfo) })
 	}
 }
 
@@ -550,11 +598,15 @@ func (b *StorageBackend) GetVolumeExternal(ctx context.Context, volumeName strin
 		return nil, err
 	}
 
-	if err := b.driver.Get(ctx, volumeName); err != nil {
+	if err := b.call(ctx, func() error { return b.driver.Get(ctx, volumeName) }); err != nil {
 		return nil, fmt.Errorf("failed to get volume %s: %v", volumeName, err)
 	}
 
-	volExternal, err := b.driver.GetVolumeExternal(ctx, volumeName)
+	var volExternal *VolumeExternal
+	err := b.call(ctx, func() (err error) {
+		volExternal, err = b.driver.GetVolumeExternal(ctx, volumeName)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error requesting volume size: %v", err)
 	}
@@ -583,18 +635,18 @@ func (b *StorageBackend) ImportVolume(ctx context.Context, volConfig *VolumeConf
 		b.driver.CreatePrepare(ctx, volConfig)
 	}
 
-	err := b.driver.Import(ctx, volConfig, volConfig.ImportOriginalName)
+	err := b.call(ctx, func() error { return b.driver.Import(ctx, volConfig, volConfig.ImportOriginalName) })
 	if err != nil {
 		return nil, fmt.Errorf("driver import volume failed: %v", err)
 	}
 
-	err = b.driver.CreateFollowup(ctx, volConfig)
+	err = b.call(ctx, func() error { return b.driver.CreateFollowup(ctx, volConfig) })
 	if err != nil {
 		return nil, fmt.Errorf("failed post import volume operations : %v", err)
 	}
 
 	volume := NewVolume(volConfig, b.backendUUID, drivers.UnsetPool, false, VolumeStateOnline)
-	b.volumes[volume.Config.Name] = volume
+	b.CacheVolume(volume)
 	return volume, nil
 }
 
@@ -624,7 +676,7 @@ func (b *StorageBackend) ResizeVolume(ctx context.Context, volConfig *VolumeConf
 		"volume":      volConfig.InternalName,
 		"volume_size": newSizeBytes,
 	}).Debug("Attempting volume resize.")
-	return b.driver.Resize(ctx, volConfig, newSizeBytes)
+	return b.call(ctx, func() error { return b.driver.Resize(ctx, volConfig, newSizeBytes) })
 }
 
 func (b *StorageBackend) RenameVolume(ctx context.Context, volConfig *VolumeConfig, newName string) error {
@@ -643,10 +695,10 @@ func (b *StorageBackend) RenameVolume(ctx context.Context, volConfig *VolumeConf
 		return fmt.Errorf("backend %s is not Online", b.name)
 	}
 
-	if err := b.driver.Get(ctx, oldName); err != nil {
+	if err := b.call(ctx, func() error { return b.driver.Get(ctx, oldName) }); err != nil {
 		return fmt.Errorf("volume %s not found on backend %s; %v", oldName, b.name, err)
 	}
-	if err := b.driver.Rename(ctx, oldName, newName); err != nil {
+	if err := b.call(ctx, func() error { return b.driver.Rename(ctx, oldName, newName) }); err != nil {
 		return fmt.Errorf("error attempting to rename volume %s on backend %s: %v", oldName, b.name, err)
 	}
 	return nil
@@ -670,7 +722,7 @@ func (b *StorageBackend) RemoveVolume(ctx context.Context, volConfig *VolumeConf
 		return err
 	}
 
-	if err := b.driver.Destroy(ctx, volConfig); err != nil {
+	if err := b.call(ctx, func() error { return b.driver.Destroy(ctx, volConfig) }); err != nil {
 		// TODO:  Check the error being returned once the nDVP throws errors
 		// for volumes that aren't found.
 		return err
@@ -679,7 +731,16 @@ func (b *StorageBackend) RemoveVolume(ctx context.Context, volConfig *VolumeConf
 	return nil
 }
 
+// CacheVolume adds a volume to the backend's volume cache
+func (b *StorageBackend) CacheVolume(volume *Volume) {
+	b.volumesLock.Lock()
+	defer b.volumesLock.Unlock()
+	b.volumes[volume.Config.Name] = volume
+}
+
 func (b *StorageBackend) RemoveCachedVolume(volumeName string) {
+	b.volumesLock.Lock()
+	defer b.volumesLock.Unlock()
 	delete(b.volumes, volumeName)
 }
 
@@ -703,7 +764,7 @@ func (b *StorageBackend) GetSnapshot(
 		return nil, err
 	}
 
-	if snapshot, err := b.driver.GetSnapshot(ctx, snapConfig, volConfig); err != nil {
+	if snapshot, err := b.getSnapshot(ctx, snapConfig, volConfig); err != nil {
 		// An error here means we couldn't check for the snapshot.  It does not mean the snapshot doesn't exist.
 		return nil, err
 	} else if snapshot == nil {
@@ -732,7 +793,12 @@ func (b *StorageBackend) GetSnapshots(ctx context.Context, volConfig *VolumeConf
 		return nil, err
 	}
 
-	return b.driver.GetSnapshots(ctx, volConfig)
+	var snapshots []*Snapshot
+	err := b.call(ctx, func() (err error) {
+		snapshots, err = b.driver.GetSnapshots(ctx, volConfig)
+		return err
+	})
+	return snapshots, err
 }
 
 func (b *StorageBackend) CreateSnapshot(
@@ -760,7 +826,7 @@ func (b *StorageBackend) CreateSnapshot(
 	snapConfig.InternalName = snapConfig.Name
 
 	// Implement idempotency by checking for the snapshot first
-	if existingSnapshot, err := b.driver.GetSnapshot(ctx, snapConfig, volConfig); err != nil {
+	if existingSnapshot, err := b.getSnapshot(ctx, snapConfig, volConfig); err != nil {
 		// An error here means we couldn't check for the snapshot.  It does not mean the snapshot doesn't exist.
 		return nil, err
 	} else if existingSnapshot != nil {
@@ -776,7 +842,12 @@ func (b *StorageBackend) CreateSnapshot(
 	}
 
 	// Create snapshot
-	return b.driver.CreateSnapshot(ctx, snapConfig, volConfig)
+	var snapshot *Snapshot
+	err := b.call(ctx, func() (err error) {
+		snapshot, err = b.driver.CreateSnapshot(ctx, snapConfig, volConfig)
+		return err
+	})
+	return snapshot, err
 }
 
 func (b *StorageBackend) RestoreSnapshot(
@@ -800,7 +871,7 @@ func (b *StorageBackend) RestoreSnapshot(
 	}
 
 	// Restore snapshot
-	return b.driver.RestoreSnapshot(ctx, snapConfig, volConfig)
+	return b.call(ctx, func() error { return b.driver.RestoreSnapshot(ctx, snapConfig, volConfig) })
 }
 
 func (b *StorageBackend) DeleteSnapshot(
@@ -824,7 +895,7 @@ func (b *StorageBackend) DeleteSnapshot(
 	}
 
 	// Implement idempotency by checking for the snapshot first
-	if existingSnapshot, err := b.driver.GetSnapshot(ctx, snapConfig, volConfig); err != nil {
+	if existingSnapshot, err := b.getSnapshot(ctx, snapConfig, volConfig); err != nil {
 		// An error here means we couldn't check for the snapshot.  It does not mean the snapshot doesn't exist.
 		return err
 	} else if existingSnapshot == nil {
@@ -840,7 +911,18 @@ func (b *StorageBackend) DeleteSnapshot(
 	}
 
 	// Delete snapshot
-	return b.driver.DeleteSnapshot(ctx, snapConfig, volConfig)
+	return b.call(ctx, func() error { return b.driver.DeleteSnapshot(ctx, snapConfig, volConfig) })
+}
+
+// getSnapshot calls the driver's GetSnapshot, which returns no error and no snapshot if the snapshot doesn't exist.
+func (b *StorageBackend) getSnapshot(
+	ctx context.Context, snapConfig *SnapshotConfig, volConfig *VolumeConfig,
+) (snapshot *Snapshot, err error) {
+	err = b.call(ctx, func() (err error) {
+		snapshot, err = b.driver.GetSnapshot(ctx, snapConfig, volConfig)
+		return err
+	})
+	return snapshot, err
 }
 
 const (
@@ -864,6 +946,8 @@ func (b *StorageBackend) GetUpdateType(ctx context.Context, origBackend Backend)
 // HasVolumes returns true if the Backend has one or more volumes
 // provisioned on it.
 func (b *StorageBackend) HasVolumes() bool {
+	b.volumesLock.RLock()
+	defer b.volumesLock.RUnlock()
 	return len(b.volumes) > 0
 }
 
@@ -882,13 +966,18 @@ func (b *StorageBackend) Terminate(ctx context.Context) {
 		Logc(ctx).WithFields(logFields).Warning("Cannot terminate an uninitialized backend.")
 	} else {
 		Logc(ctx).WithFields(logFields).Debug("Terminating backend.")
+		b.limiter.stop()
 		b.driver.Terminate(ctx, b.backendUUID)
 	}
 }
 
 // InvalidateNodeAccess marks the backend as needing the node access rule reconciled
 func (b *StorageBackend) InvalidateNodeAccess() {
+	b.nodeAccessLock.Lock()
+	defer b.nodeAccessLock.Unlock()
+
 	b.nodeAccessUpToDate = false
+	b.nodeAccessGeneration++
 }
 
 // ReconcileNodeAccess will ensure that the driver only has allowed access
@@ -897,20 +986,160 @@ func (b *StorageBackend) InvalidateNodeAccess() {
 func (b *StorageBackend) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node) error {
 	if err := b.ensureOnlineOrDeleting(ctx); err == nil {
 		// Only reconcile backends that need it
-		if b.nodeAccessUpToDate {
+		b.nodeAccessLock.Lock()
+		upToDate, generation := b.nodeAccessUpToDate, b.nodeAccessGeneration
+		b.nodeAccessLock.Unlock()
+		if upToDate {
 			Logc(ctx).WithField("backend", b.name).Trace("Backend node access rules are already up-to-date, skipping.")
 			return nil
 		}
 		Logc(ctx).WithField("backend", b.name).Trace("Backend node access rules are out-of-date, updating.")
-		err = b.driver.ReconcileNodeAccess(ctx, nodes, b.backendUUID)
+		err = b.call(ctx, func() error { return b.driver.ReconcileNodeAccess(ctx, nodes, b.backendUUID) })
 		if err == nil {
-			b.nodeAccessUpToDate = true
+			b.nodeAccessLock.Lock()
+			b.nodeAccessUpToDate = b.nodeAccessGeneration == generation
+			b.nodeAccessLock.Unlock()
 		}
 		return err
 	}
 	return nil
 }
 
+// call makes a storage API call to the backend, subject to the backend's request limits and circuit breaker.
+func (b *StorageBackend) call(ctx context.Context, apiCall func() error) error {
+	done, err := b.limiter.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	err = apiCall()
+	done(err)
+	return err
+}
+
+// ProbeHealth checks whether the backend's storage system is responding.  Backends whose drivers cannot be
+// probed are assumed to be healthy.
+func (b *StorageBackend) ProbeHealth(ctx context.Context) error {
+	if !b.driver.Initialized() {
+		return fmt.Errorf("backend %s is not initialized", b.name)
+	}
+	if prober, ok := b.driver.(HealthProber); ok {
+		return b.call(ctx, func() error { return prober.ProbeHealth(ctx) })
+	}
+	return nil
+}
+
+// Degraded returns whether the backend's circuit breaker has stopped calls to the backend because it is not
+// responding, and if so, why.
+func (b *StorageBackend) Degraded() (bool, string) {
+	return b.limiter.degraded()
+}
+
+// UpdatePoolUtilization refreshes the performance utilization of the backend's storage pools from the driver.
+// Backends whose drivers cannot report utilization return an UnsupportedError.
+func (b *StorageBackend) UpdatePoolUtilization(ctx context.Context) error {
+	reporter, ok := b.driver.(PerformanceReporter)
+	if !ok {
+		return utils.UnsupportedError(fmt.Sprintf("backend %s does not report performance utilization", b.name))
+	}
+
+	var utilization map[string]float64
+	err := b.call(ctx, func() (err error) {
+		utilization, err = reporter.GetPoolUtilization(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for name, pool := range b.storage {
+		value, ok := utilization[name]
+		pool.SetUtilization(value, ok)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"backend":     b.name,
+		"utilization": utilization,
+	}).Trace("Updated storage pool utilization.")
+
+	return nil
+}
+
+// GetVolumePublications returns the publications recorded with a volume on the storage system.
+func (b *StorageBackend) GetVolumePublications(
+	ctx context.Context, volConfig *VolumeConfig,
+) ([]*utils.VolumePublication, error) {
+	recorder, ok := b.driver.(VolumePublicationRecorder)
+	if !ok {
+		return nil, utils.UnsupportedError(fmt.Sprintf("backend %s does not record volume publications", b.name))
+	}
+	if err := b.ensureOnline(ctx); err != nil {
+		return nil, err
+	}
+
+	var publications []*utils.VolumePublication
+	err := b.call(ctx, func() (err error) {
+		publications, err = recorder.GetVolumePublications(ctx, volConfig)
+		return err
+	})
+	return publications, err
+}
+
+// AddVolumePublication records a publication with a volume on the storage system, replacing any earlier
+// publication to the same node.  The records are updated atomically, so if exclusive is true and another
+// node has a publication, a FoundError is returned and nothing is recorded.
+func (b *StorageBackend) AddVolumePublication(
+	ctx context.Context, volConfig *VolumeConfig, publication *utils.VolumePublication, exclusive bool,
+) error {
+	recorder, ok := b.driver.(VolumePublicationRecorder)
+	if !ok {
+		return utils.UnsupportedError(fmt.Sprintf("backend %s does not record volume publications", b.name))
+	}
+	if err := b.ensureOnline(ctx); err != nil {
+		return err
+	}
+
+	return b.call(ctx, func() error {
+		return recorder.AddVolumePublication(ctx, volConfig, publication, exclusive)
+	})
+}
+
+// DeleteVolumePublication removes the publication of a volume to a node from the storage system.
+func (b *StorageBackend) DeleteVolumePublication(ctx context.Context, volConfig *VolumeConfig, nodeName string) error {
+	recorder, ok := b.driver.(VolumePublicationRecorder)
+	if !ok {
+		return utils.UnsupportedError(fmt.Sprintf("backend %s does not record volume publications", b.name))
+	}
+	if err := b.ensureOnline(ctx); err != nil {
+		return err
+	}
+
+	return b.call(ctx, func() error { return recorder.DeleteVolumePublication(ctx, volConfig, nodeName) })
+}
+
+// probe checks whether a degraded backend is responding, using the driver's health probe if it has one
+// or else by looking up one of the backend's volumes.
+func (b *StorageBackend) probe(ctx context.Context) error {
+	if prober, ok := b.driver.(HealthProber); ok {
+		return prober.ProbeHealth(ctx)
+	}
+
+	var internalName string
+
+	b.volumesLock.RLock()
+	for _, volume := range b.volumes {
+		if volume.Config != nil && volume.Config.InternalName != "" && !volume.Config.ImportNotManaged {
+			internalName = volume.Config.InternalName
+			break
+		}
+	}
+	b.volumesLock.RUnlock()
+
+	if internalName == "" {
+		return errNoProbe
+	}
+	return b.driver.Get(ctx, internalName)
+}
+
 func (b *StorageBackend) ensureOnline(ctx context.Context) error {
 	if b.state != Online {
 		Logc(ctx).WithFields(log.Fields{
@@ -943,6 +1172,9 @@ type BackendExternal struct {
 	Online      bool                   `json:"online"`
 	Volumes     []string               `json:"volumes"`
 	ConfigRef   string                 `json:"configRef"`
+	// Degraded is set while Trident has stopped calling the backend because it is not responding
+	Degraded       bool   `json:"degraded,omitempty"`
+	DegradedReason string `json:"degradedReason,omitempty"`
 }
 
 func (b *StorageBackend) ConstructExternal(ctx context.Context) *BackendExternal {
@@ -957,13 +1189,16 @@ func (b *StorageBackend) ConstructExternal(ctx context.Context) *BackendExternal
 		Volumes:     make([]string, 0),
 		ConfigRef:   b.configRef,
 	}
+	backendExternal.Degraded, backendExternal.DegradedReason = b.limiter.degraded()
 
 	for name, pool := range b.storage {
 		backendExternal.Storage[name] = pool.ConstructExternal()
 	}
+	b.volumesLock.RLock()
 	for volName := range b.volumes {
 		backendExternal.Volumes = append(backendExternal.Volumes, volName)
 	}
+	b.volumesLock.RUnlock()
 	return &backendExternal
 }
 
@@ -1141,8 +1376,10 @@ func (b *StorageBackend) EstablishMirror(
 			fmt.Sprintf("mirroring is not implemented by backends of type %v", b.driver.Name()))
 	}
 
-	return mirrorDriver.EstablishMirror(ctx, localVolumeHandle, remoteVolumeHandle, replicationPolicy,
-		replicationSchedule)
+	return b.call(ctx, func() error {
+		return mirrorDriver.EstablishMirror(ctx, localVolumeHandle, remoteVolumeHandle, replicationPolicy,
+			replicationSchedule)
+	})
 }
 
 func (b *StorageBackend) PromoteMirror(
@@ -1154,7 +1391,12 @@ func (b *StorageBackend) PromoteMirror(
 			fmt.Sprintf("mirroring is not implemented by backends of type %v", b.driver.Name()))
 	}
 
-	return mirrorDriver.PromoteMirror(ctx, localVolumeHandle, remoteVolumeHandle, snapshotHandle)
+	var waitingForSnapshot bool
+	err := b.call(ctx, func() (err error) {
+		waitingForSnapshot, err = mirrorDriver.PromoteMirror(ctx, localVolumeHandle, remoteVolumeHandle, snapshotHandle)
+		return err
+	})
+	return waitingForSnapshot, err
 }
 
 func (b *StorageBackend) ReestablishMirror(
@@ -1167,8 +1409,10 @@ func (b *StorageBackend) ReestablishMirror(
 			fmt.Sprintf("mirroring is not implemented by backends of type %v", b.driver.Name()))
 	}
 
-	return mirrorDriver.ReestablishMirror(ctx, localVolumeHandle, remoteVolumeHandle, replicationPolicy,
-		replicationSchedule)
+	return b.call(ctx, func() error {
+		return mirrorDriver.ReestablishMirror(ctx, localVolumeHandle, remoteVolumeHandle, replicationPolicy,
+			replicationSchedule)
+	})
 }
 
 func (b *StorageBackend) GetMirrorStatus(
@@ -1179,7 +1423,12 @@ func (b *StorageBackend) GetMirrorStatus(
 		return "", utils.UnsupportedError(fmt.Sprintf(
 			"mirroring is not implemented by backends of type %v", b.driver.Name()))
 	}
-	return mirrorDriver.GetMirrorStatus(ctx, localVolumeHandle, remoteVolumeHandle)
+	var status string
+	err := b.call(ctx, func() (err error) {
+		status, err = mirrorDriver.GetMirrorStatus(ctx, localVolumeHandle, remoteVolumeHandle)
+		return err
+	})
+	return status, err
 }
 
 func (b *StorageBackend) CanMirror() bool {
@@ -1194,7 +1443,7 @@ func (b *StorageBackend) ReleaseMirror(ctx context.Context, localVolumeHandle st
 			fmt.Sprintf("mirroring is not implemented by backends of type %v", b.driver.Name()))
 	}
 
-	return mirrorDriver.ReleaseMirror(ctx, localVolumeHandle)
+	return b.call(ctx, func() error { return mirrorDriver.ReleaseMirror(ctx, localVolumeHandle) })
 }
 
 func (b *StorageBackend) GetReplicationDetails(
@@ -1205,7 +1454,28 @@ func (b *StorageBackend) GetReplicationDetails(
 		return "", "", utils.UnsupportedError(fmt.Sprintf(
 			"mirroring is not implemented by backends of type %v", b.driver.Name()))
 	}
-	return mirrorDriver.GetReplicationDetails(ctx, localVolumeHandle, remoteVolumeHandle)
+	var policy, schedule string
+	err := b.call(ctx, func() (err error) {
+		policy, schedule, err = mirrorDriver.GetReplicationDetails(ctx, localVolumeHandle, remoteVolumeHandle)
+		return err
+	})
+	return policy, schedule, err
+}
+
+func (b *StorageBackend) GetMirrorHealth(
+	ctx context.Context, localVolumeHandle, remoteVolumeHandle string,
+) (*MirrorHealth, error) {
+	mirrorDriver, ok := b.driver.(Mirrorer)
+	if !ok {
+		return nil, utils.UnsupportedError(fmt.Sprintf(
+			"mirroring is not implemented by backends of type %v", b.driver.Name()))
+	}
+	var health *MirrorHealth
+	err := b.call(ctx, func() (err error) {
+		health, err = mirrorDriver.GetMirrorHealth(ctx, localVolumeHandle, remoteVolumeHandle)
+		return err
+	})
+	return health, err
 }
 
 func (b *StorageBackend) GetChapInfo(ctx context.Context, volumeName, nodeName string) (*utils.IscsiChapInfo, error) {
@@ -1214,13 +1484,28 @@ func (b *StorageBackend) GetChapInfo(ctx context.Context, volumeName, nodeName s
 		return nil, utils.UnsupportedError(fmt.Sprintf(
 			"retrieving chap credentials is not supported on backends of type %v", b.driver.Name()))
 	}
-	return chapEnabledDriver.GetChapInfo(ctx, volumeName, nodeName)
+	var chapInfo *utils.IscsiChapInfo
+	err := b.call(ctx, func() (err error) {
+		chapInfo, err = chapEnabledDriver.GetChapInfo(ctx, volumeName, nodeName)
+		return err
+	})
+	return chapInfo, err
 }
 
 func (b *StorageBackend) EnablePublishEnforcement(ctx context.Context, volume *Volume) error {
 	driver, ok := b.driver.(PublishEnforceable)
 	if ok {
-		return driver.EnablePublishEnforcement(ctx, volume)
+		return b.call(ctx, func() error { return driver.EnablePublishEnforcement(ctx, volume) })
+	}
+	return nil
+}
+
+// RevokeNodeAccess removes a node from the access rules the backend keeps for individual nodes, such as per-node
+// igroups and volume export rules.  Rules shared by all nodes are updated by ReconcileNodeAccess instead.
+func (b *StorageBackend) RevokeNodeAccess(ctx context.Context, node *utils.Node, tridentUUID string) error {
+	driver, ok := b.driver.(NodeAccessRevocable)
+	if ok {
+		return b.call(ctx, func() error { return driver.RevokeNodeAccess(ctx, node, tridentUUID) })
 	}
 	return nil
 }
@@ -0,0 +1,333 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	. "github.com/netapp/trident/logger"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	DefaultCircuitBreakerThreshold     = 5
+	DefaultCircuitBreakerProbeInterval = 30 * time.Second
+
+	circuitBreakerProbeTimeout = 30 * time.Second
+)
+
+type circuitState int
+
+const (
+	// circuitClosed allows all calls to the backend
+	circuitClosed circuitState = iota
+	// circuitOpen fails all calls to the backend without making them
+	circuitOpen
+	// circuitHalfOpen allows a single trial call to the backend to learn whether it has recovered
+	circuitHalfOpen
+)
+
+// errNoProbe is returned by a backend probe that has nothing it can safely call, in which case the next
+// request to the backend is used as the probe.
+var errNoProbe = errors.New("no probe available")
+
+// unreachableErrorMessages are fragments of the errors returned by the HTTP clients used by the storage
+// drivers when a backend cannot be reached.  Most drivers flatten errors into strings, so the error types
+// are often lost by the time they reach the backend.  Deadlines only count when they are the driver's own,
+// since calls whose caller ran out of time are not recorded at all.
+var unreachableErrorMessages = []string{
+	"connection refused",
+	"connection reset",
+	"no route to host",
+	"network is unreachable",
+	"i/o timeout",
+	"tls handshake timeout",
+	"context deadline exceeded",
+	"client.timeout exceeded",
+}
+
+// backendLimiter bounds the number and rate of storage API calls made to a backend, and trips a circuit
+// breaker when the backend stops responding so that callers fail fast instead of queueing up behind it.
+// While the breaker is open, the backend is probed periodically and the breaker closes once it responds.
+type backendLimiter struct {
+	backendName string
+	requests    chan struct{}
+	rate        *rate.Limiter
+
+	threshold     int
+	probeInterval time.Duration
+	probe         func(ctx context.Context) error
+
+	mutex         sync.Mutex
+	state         circuitState
+	trialInFlight bool
+	failures      int
+	lastError     error
+	openedTime    time.Time
+	probeTimer    *time.Timer
+	stopped       bool
+}
+
+// newBackendLimiter returns a limiter configured by the storage API limits in a backend's common config.
+// The config is validated when the backend config is parsed, so invalid values here revert to defaults.
+func newBackendLimiter(backendName string, config *drivers.CommonStorageDriverConfig) *backendLimiter {
+	l := &backendLimiter{
+		backendName:   backendName,
+		threshold:     DefaultCircuitBreakerThreshold,
+		probeInterval: DefaultCircuitBreakerProbeInterval,
+	}
+	if config == nil {
+		return l
+	}
+
+	if config.MaxConcurrentRequests > 0 {
+		l.requests = make(chan struct{}, config.MaxConcurrentRequests)
+	}
+	if config.MaxRequestsPerSecond > 0 {
+		burst := int(math.Ceil(config.MaxRequestsPerSecond))
+		l.rate = rate.NewLimiter(rate.Limit(config.MaxRequestsPerSecond), burst)
+	}
+	if config.CircuitBreakerThreshold != 0 {
+		l.threshold = config.CircuitBreakerThreshold
+	}
+	if config.CircuitBreakerProbeInterval != "" {
+		if interval, err := time.ParseDuration(config.CircuitBreakerProbeInterval); err == nil && interval > 0 {
+			l.probeInterval = interval
+		}
+	}
+	return l
+}
+
+// acquire waits until a call may be made to the backend, and returns a function that must be called with
+// the call's result once it completes.  If the circuit breaker is open, acquire fails immediately with a
+// BackendUnavailableError.  A nil limiter allows every call.
+func (l *backendLimiter) acquire(ctx context.Context) (func(error), error) {
+	if l == nil {
+		return func(error) {}, nil
+	}
+
+	trial, err := l.admit()
+	if err != nil {
+		return nil, err
+	}
+
+	if l.requests != nil {
+		select {
+		case l.requests <- struct{}{}:
+		case <-ctx.Done():
+			l.abandonTrial(trial)
+			return nil, ctx.Err()
+		}
+	}
+	if l.rate != nil {
+		if err = l.rate.Wait(ctx); err != nil {
+			l.release()
+			l.abandonTrial(trial)
+			return nil, err
+		}
+	}
+
+	return func(err error) {
+		l.release()
+		l.record(ctx, err, trial)
+	}, nil
+}
+
+// admit checks the circuit breaker, returning whether the call is the trial call of a half-open breaker.
+func (l *backendLimiter) admit() (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	switch l.state {
+	case circuitOpen:
+		return false, l.unavailableError()
+	case circuitHalfOpen:
+		if l.trialInFlight {
+			return false, l.unavailableError()
+		}
+		l.trialInFlight = true
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (l *backendLimiter) release() {
+	if l.requests != nil {
+		<-l.requests
+	}
+}
+
+// abandonTrial allows another call to act as the trial if a trial call was never made.
+func (l *backendLimiter) abandonTrial(trial bool) {
+	if !trial {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.trialInFlight = false
+}
+
+func (l *backendLimiter) unavailableError() error {
+	return utils.BackendUnavailableError(fmt.Sprintf(
+		"backend %s is degraded and will not be called until it responds again; %v", l.backendName, l.lastError))
+}
+
+// record updates the circuit breaker with the result of a call to the backend.
+func (l *backendLimiter) record(ctx context.Context, err error, trial bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if trial {
+		l.trialInFlight = false
+	}
+
+	switch {
+	case ctx.Err() != nil, errors.Is(err, context.Canceled):
+		// The caller gave up or ran out of time, which says nothing about the backend
+		return
+	case isBackendUnreachableError(err):
+		l.failures++
+		l.lastError = err
+		if l.state != circuitOpen && l.threshold > 0 && (trial || l.failures >= l.threshold) {
+			l.open(ctx)
+		}
+	default:
+		l.failures = 0
+		if l.state != circuitClosed {
+			l.close(ctx)
+		}
+	}
+}
+
+// open trips the circuit breaker and schedules a probe of the backend.  The caller must hold the mutex.
+func (l *backendLimiter) open(ctx context.Context) {
+	logFields := log.Fields{
+		"backend":       l.backendName,
+		"failures":      l.failures,
+		"probeInterval": l.probeInterval,
+	}
+	if l.state == circuitClosed {
+		Logc(ctx).WithFields(logFields).WithError(l.lastError).Warning(
+			"Backend is not responding, marking it degraded.")
+		l.openedTime = time.Now()
+	} else {
+		Logc(ctx).WithFields(logFields).WithError(l.lastError).Debug("Degraded backend is still not responding.")
+	}
+
+	l.state = circuitOpen
+	if !l.stopped {
+		l.probeTimer = time.AfterFunc(l.probeInterval, l.runProbe)
+	}
+}
+
+// close resets the circuit breaker.  The caller must hold the mutex.
+func (l *backendLimiter) close(ctx context.Context) {
+	Logc(ctx).WithFields(log.Fields{
+		"backend":       l.backendName,
+		"degradedSince": l.openedTime,
+	}).Info("Backend is responding again, marking it no longer degraded.")
+
+	l.state = circuitClosed
+	l.failures = 0
+	l.lastError = nil
+	l.openedTime = time.Time{}
+	if l.probeTimer != nil {
+		l.probeTimer.Stop()
+		l.probeTimer = nil
+	}
+}
+
+// runProbe half-opens the circuit breaker and, if the backend offers a probe, uses it as the trial call.
+func (l *backendLimiter) runProbe() {
+	ctx := GenerateRequestContext(context.Background(), "", ContextSourcePeriodic)
+
+	l.mutex.Lock()
+	if l.stopped || l.state != circuitOpen {
+		l.mutex.Unlock()
+		return
+	}
+	l.state = circuitHalfOpen
+	l.trialInFlight = l.probe != nil
+	l.mutex.Unlock()
+
+	if l.probe == nil {
+		return
+	}
+
+	Logc(ctx).WithField("backend", l.backendName).Debug("Probing degraded backend.")
+
+	probeCtx, cancel := context.WithTimeout(ctx, circuitBreakerProbeTimeout)
+	defer cancel()
+
+	err := l.probe(probeCtx)
+	if errors.Is(err, errNoProbe) {
+		// Let the next request to the backend act as the probe
+		l.abandonTrial(true)
+		return
+	}
+	l.record(ctx, err, true)
+}
+
+// stop cancels any pending probe; the limiter's backend will not be called again.
+func (l *backendLimiter) stop() {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.stopped = true
+	if l.probeTimer != nil {
+		l.probeTimer.Stop()
+		l.probeTimer = nil
+	}
+}
+
+// degraded returns whether the circuit breaker is not closed, and if so, why.
+func (l *backendLimiter) degraded() (bool, string) {
+	if l == nil {
+		return false, ""
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.state == circuitClosed {
+		return false, ""
+	}
+	return true, fmt.Sprintf("backend stopped responding at %s; %v",
+		l.openedTime.UTC().Format(time.RFC3339), l.lastError)
+}
+
+// isBackendUnreachableError returns true if the error indicates that the backend's storage API could not be
+// reached, as opposed to the backend rejecting a request.
+func isBackendUnreachableError(err error) bool {
+	if err == nil || utils.IsBackendUnavailableError(err) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, fragment := range unreachableErrorMessages {
+		if strings.Contains(message, fragment) {
+			return true
+		}
+	}
+	return false
+}
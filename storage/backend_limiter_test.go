@@ -0,0 +1,210 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/utils"
+)
+
+var errUnreachable = errors.New("dial tcp 10.0.0.1:443: connect: connection refused")
+
+func callLimited(l *backendLimiter, err error) error {
+	backend := &StorageBackend{name: "backend", limiter: l}
+	return backend.call(context.Background(), func() error { return err })
+}
+
+func TestNewBackendLimiter(t *testing.T) {
+	l := newBackendLimiter("backend", nil)
+	assert.Nil(t, l.requests)
+	assert.Nil(t, l.rate)
+	assert.Equal(t, DefaultCircuitBreakerThreshold, l.threshold)
+	assert.Equal(t, DefaultCircuitBreakerProbeInterval, l.probeInterval)
+
+	l = newBackendLimiter("backend", &drivers.CommonStorageDriverConfig{
+		MaxConcurrentRequests:       4,
+		MaxRequestsPerSecond:        2.5,
+		CircuitBreakerThreshold:     -1,
+		CircuitBreakerProbeInterval: "1m",
+	})
+	assert.Equal(t, 4, cap(l.requests))
+	if assert.NotNil(t, l.rate) {
+		assert.Equal(t, 3, l.rate.Burst())
+	}
+	assert.Equal(t, -1, l.threshold)
+	assert.Equal(t, time.Minute, l.probeInterval)
+}
+
+func TestBackendLimiter_CircuitBreaker(t *testing.T) {
+	l := newBackendLimiter("backend", &drivers.CommonStorageDriverConfig{CircuitBreakerThreshold: 3})
+	l.probeInterval = time.Hour
+	defer l.stop()
+
+	// Errors from a responding backend don't trip the breaker
+	for i := 0; i < 5; i++ {
+		assert.Error(t, callLimited(l, errors.New("volume not found")))
+	}
+	degraded, _ := l.degraded()
+	assert.False(t, degraded)
+
+	// A success resets the count of consecutive failures
+	assert.Error(t, callLimited(l, errUnreachable))
+	assert.Error(t, callLimited(l, errUnreachable))
+	assert.NoError(t, callLimited(l, nil))
+	assert.Error(t, callLimited(l, errUnreachable))
+	assert.Error(t, callLimited(l, errUnreachable))
+	degraded, _ = l.degraded()
+	assert.False(t, degraded)
+
+	assert.Error(t, callLimited(l, fmt.Errorf("could not get volume; %v", context.DeadlineExceeded)))
+	degraded, reason := l.degraded()
+	assert.True(t, degraded)
+	assert.Contains(t, reason, "deadline exceeded")
+
+	// Calls now fail fast without reaching the backend
+	called := false
+	err := (&StorageBackend{limiter: l}).call(context.Background(), func() error {
+		called = true
+		return nil
+	})
+	assert.False(t, called)
+	assert.True(t, utils.IsBackendUnavailableError(err))
+}
+
+func TestBackendLimiter_CallerDeadline(t *testing.T) {
+	l := newBackendLimiter("backend", &drivers.CommonStorageDriverConfig{CircuitBreakerThreshold: 1})
+	l.probeInterval = time.Hour
+	defer l.stop()
+
+	// A call that outlives its caller's deadline says nothing about the backend
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err := (&StorageBackend{limiter: l}).call(ctx, func() error {
+		<-ctx.Done()
+		return fmt.Errorf("could not clone volume; %v", ctx.Err())
+	})
+	assert.Error(t, err)
+	degraded, _ := l.degraded()
+	assert.False(t, degraded)
+
+	// The driver's own timeouts still count while the caller is waiting
+	assert.Error(t, callLimited(l, fmt.Errorf("could not clone volume; %v", context.DeadlineExceeded)))
+	degraded, _ = l.degraded()
+	assert.True(t, degraded)
+}
+
+func TestBackendLimiter_Disabled(t *testing.T) {
+	l := newBackendLimiter("backend", &drivers.CommonStorageDriverConfig{CircuitBreakerThreshold: -1})
+
+	for i := 0; i < 2*DefaultCircuitBreakerThreshold; i++ {
+		assert.Equal(t, errUnreachable, callLimited(l, errUnreachable))
+	}
+	degraded, _ := l.degraded()
+	assert.False(t, degraded)
+
+	var nilLimiter *backendLimiter
+	assert.NoError(t, callLimited(nilLimiter, nil))
+	degraded, _ = nilLimiter.degraded()
+	assert.False(t, degraded)
+}
+
+func TestBackendLimiter_Probe(t *testing.T) {
+	l := newBackendLimiter("backend", &drivers.CommonStorageDriverConfig{CircuitBreakerThreshold: 1})
+	l.probeInterval = 10 * time.Millisecond
+	defer l.stop()
+
+	var probes int32
+	l.probe = func(ctx context.Context) error {
+		// The backend recovers after the second probe
+		if atomic.AddInt32(&probes, 1) < 2 {
+			return errUnreachable
+		}
+		return nil
+	}
+
+	assert.Error(t, callLimited(l, errUnreachable))
+	degraded, _ := l.degraded()
+	assert.True(t, degraded)
+
+	assert.Eventually(t, func() bool {
+		degraded, _ := l.degraded()
+		return !degraded
+	}, 5*time.Second, time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&probes))
+	assert.NoError(t, callLimited(l, nil))
+}
+
+func TestBackendLimiter_NoProbe(t *testing.T) {
+	l := newBackendLimiter("backend", &drivers.CommonStorageDriverConfig{CircuitBreakerThreshold: 1})
+	l.probeInterval = 10 * time.Millisecond
+	l.probe = func(ctx context.Context) error { return errNoProbe }
+	defer l.stop()
+
+	assert.Error(t, callLimited(l, errUnreachable))
+
+	// Without a probe, the breaker half-opens and lets the next call through as a trial
+	assert.Eventually(t, func() bool {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		return l.state == circuitHalfOpen && !l.trialInFlight
+	}, 5*time.Second, time.Millisecond)
+
+	// A failed trial reopens the breaker
+	assert.Equal(t, errUnreachable, callLimited(l, errUnreachable))
+	assert.True(t, utils.IsBackendUnavailableError(callLimited(l, nil)))
+
+	assert.Eventually(t, func() bool {
+		return callLimited(l, nil) == nil
+	}, 5*time.Second, time.Millisecond)
+	degraded, _ := l.degraded()
+	assert.False(t, degraded)
+}
+
+func TestBackendLimiter_MaxConcurrentRequests(t *testing.T) {
+	l := newBackendLimiter("backend", &drivers.CommonStorageDriverConfig{MaxConcurrentRequests: 1})
+
+	done, err := l.acquire(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// A second call waits for the first to finish
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done(nil)
+	done, err = l.acquire(context.Background())
+	assert.NoError(t, err)
+	done(nil)
+}
+
+func TestIsBackendUnreachableError(t *testing.T) {
+	tests := map[string]struct {
+		err         error
+		unreachable bool
+	}{
+		"nil":                {nil, false},
+		"not found":          {errors.New("volume vol1 not found"), false},
+		"connection refused": {errUnreachable, true},
+		"timeout":            {errors.New("Post \"https://10.0.0.1/api\": net/http: TLS handshake timeout"), true},
+		"deadline":           {fmt.Errorf("wrapped; %w", context.DeadlineExceeded), true},
+		"canceled":           {context.Canceled, false},
+		"unavailable":        {utils.BackendUnavailableError("connection refused"), false},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.unreachable, isBackendUnreachableError(test.err))
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package storage
+
+import (
+	"time"
+)
+
+type JobOperation string
+
+const (
+	JobAddVolume   JobOperation = "addVolume"
+	JobCloneVolume JobOperation = "cloneVolume"
+)
+
+type JobState string
+
+const (
+	JobQueued    JobState = "queued"
+	JobRunning   JobState = "running"
+	JobSucceeded JobState = "succeeded"
+	JobFailed    JobState = "failed"
+)
+
+// Job is a long-running operation, such as a volume create, that is run asynchronously by the orchestrator.
+// Jobs are persisted so that they may be resumed if Trident restarts, and so that their progress may be
+// reported to callers that did not start them.
+type Job struct {
+	ID     string        `json:"id"`
+	Op     JobOperation  `json:"op"`
+	Config *VolumeConfig `json:"config"`
+	// BackendUUID is the backend on which the job is running, once known
+	BackendUUID string   `json:"backendUUID,omitempty"`
+	State       JobState `json:"state"`
+	// Progress is a rough estimate of the job's completion, in percent
+	Progress    int       `json:"progress"`
+	Message     string    `json:"message,omitempty"`
+	Attempts    int       `json:"attempts"`
+	CreatedTime time.Time `json:"createdTime"`
+	UpdatedTime time.Time `json:"updatedTime"`
+}
+
+// JobExternal describes a job for display by the REST API and tridentctl.
+type JobExternal struct {
+	ID          string       `json:"id"`
+	Op          JobOperation `json:"op"`
+	Volume      string       `json:"volume"`
+	BackendUUID string       `json:"backendUUID,omitempty"`
+	State       JobState     `json:"state"`
+	Progress    int          `json:"progress"`
+	Message     string       `json:"message,omitempty"`
+	Attempts    int          `json:"attempts"`
+	CreatedTime string       `json:"createdTime"`
+	UpdatedTime string       `json:"updatedTime"`
+	AgeSeconds  int64        `json:"ageSeconds"`
+}
+
+// Done returns true if the job has finished, whether or not it succeeded.
+func (j *Job) Done() bool {
+	return j.State == JobSucceeded || j.State == JobFailed
+}
+
+// VolumeName returns the name of the volume on which the job operates.
+func (j *Job) VolumeName() string {
+	if j.Config == nil {
+		return ""
+	}
+	return j.Config.Name
+}
+
+func (j *Job) ConstructClone() *Job {
+	clone := *j
+	if j.Config != nil {
+		clone.Config = j.Config.ConstructClone()
+	}
+	return &clone
+}
+
+// ConstructExternal returns the external form of the job, with its age measured from now.
+func (j *Job) ConstructExternal() *JobExternal {
+	return &JobExternal{
+		ID:          j.ID,
+		Op:          j.Op,
+		Volume:      j.VolumeName(),
+		BackendUUID: j.BackendUUID,
+		State:       j.State,
+		Progress:    j.Progress,
+		Message:     j.Message,
+		Attempts:    j.Attempts,
+		CreatedTime: j.CreatedTime.UTC().Format(time.RFC3339),
+		UpdatedTime: j.UpdatedTime.UTC().Format(time.RFC3339),
+		AgeSeconds:  int64(time.Since(j.CreatedTime).Seconds()),
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 
 	drivers "github.com/netapp/trident/storage_drivers"
 
@@ -29,6 +30,11 @@ type StoragePool struct {
 	attributes          map[string]sa.Offer // These attributes are used to match storage classes
 	internalAttributes  map[string]string   // These attributes are defined & used internally by storage drivers
 	supportedTopologies []map[string]string
+
+	// Utilization is the percentage of the pool's performance capacity in use, as last reported by its backend
+	utilizationLock  sync.RWMutex
+	utilization      float64
+	utilizationKnown bool
 }
 
 func (p *StoragePool) Name() string {
@@ -79,6 +85,21 @@ func (p *StoragePool) SetSupportedTopologies(supportedTopologies []map[string]st
 	p.supportedTopologies = supportedTopologies
 }
 
+// Utilization returns the percentage of the pool's performance capacity in use, and whether its backend
+// has reported it.
+func (p *StoragePool) Utilization() (float64, bool) {
+	p.utilizationLock.RLock()
+	defer p.utilizationLock.RUnlock()
+	return p.utilization, p.utilizationKnown
+}
+
+func (p *StoragePool) SetUtilization(utilization float64, known bool) {
+	p.utilizationLock.Lock()
+	defer p.utilizationLock.Unlock()
+	p.utilization = utilization
+	p.utilizationKnown = known
+}
+
 func NewStoragePool(backend Backend, name string) *StoragePool {
 	return &StoragePool{
 		name:               name,
@@ -113,6 +134,7 @@ type PoolExternal struct {
 	// TODO: can't have an interface here for unmarshalling
 	Attributes          map[string]sa.Offer `json:"storageAttributes"`
 	SupportedTopologies []map[string]string `json:"supportedTopologies"`
+	Utilization         *float64            `json:"utilization,omitempty"`
 }
 
 func (p *StoragePool) ConstructExternal() *PoolExternal {
@@ -122,6 +144,9 @@ func (p *StoragePool) ConstructExternal() *PoolExternal {
 		Attributes:          p.attributes,
 		SupportedTopologies: p.supportedTopologies,
 	}
+	if utilization, ok := p.Utilization(); ok {
+		external.Utilization = &utilization
+	}
 
 	// We want to sort these so that the output remains consistent;
 	// there are cases where the order won't always be the same.
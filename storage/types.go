@@ -53,6 +53,7 @@ type Backend interface {
 	ResizeVolume(ctx context.Context, volConfig *VolumeConfig, newSize string) error
 	RenameVolume(ctx context.Context, volConfig *VolumeConfig, newName string) error
 	RemoveVolume(ctx context.Context, volConfig *VolumeConfig) error
+	CacheVolume(volume *Volume)
 	RemoveCachedVolume(volumeName string)
 	CanSnapshot(ctx context.Context, snapConfig *SnapshotConfig, volConfig *VolumeConfig) error
 	GetSnapshot(ctx context.Context, snapConfig *SnapshotConfig, volConfig *VolumeConfig) (*Snapshot, error)
@@ -65,17 +66,32 @@ type Backend interface {
 	Terminate(ctx context.Context)
 	InvalidateNodeAccess()
 	ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node) error
+	ProbeHealth(ctx context.Context) error
+	Degraded() (bool, string)
+	UpdatePoolUtilization(ctx context.Context) error
+	GetVolumePublications(ctx context.Context, volConfig *VolumeConfig) ([]*utils.VolumePublication, error)
+	AddVolumePublication(
+		ctx context.Context, volConfig *VolumeConfig, publication *utils.VolumePublication, exclusive bool,
+	) error
+	DeleteVolumePublication(ctx context.Context, volConfig *VolumeConfig, nodeName string) error
 	ConstructExternal(ctx context.Context) *BackendExternal
 	ConstructPersistent(ctx context.Context) *BackendPersistent
 	CanMirror() bool
 	ChapEnabled
 	PublishEnforceable
+	NodeAccessRevocable
 }
 
 type PublishEnforceable interface {
 	EnablePublishEnforcement(ctx context.Context, volume *Volume) error
 }
 
+// NodeAccessRevocable is implemented by drivers that grant nodes access to volumes one node at a time, so that a
+// fenced node's access may be removed from every volume at once.
+type NodeAccessRevocable interface {
+	RevokeNodeAccess(ctx context.Context, node *utils.Node, tridentUUID string) error
+}
+
 type ChapEnabled interface {
 	GetChapInfo(ctx context.Context, volumeName, nodeName string) (*utils.IscsiChapInfo, error)
 }
@@ -93,6 +109,8 @@ type Pool interface {
 	SetInternalAttributes(internalAttributes map[string]string)
 	SupportedTopologies() []map[string]string
 	SetSupportedTopologies(supportedTopologies []map[string]string)
+	Utilization() (float64, bool)
+	SetUtilization(utilization float64, known bool)
 	AddStorageClass(class string)
 	RemoveStorageClass(class string) bool
 	ConstructExternal() *PoolExternal
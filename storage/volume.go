@@ -55,15 +55,24 @@ type VolumeConfig struct {
 	PreferredTopologies       []map[string]string    `json:"preferredTopologies,omitempty"`
 	AllowedTopologies         []map[string]string    `json:"allowedTopologies,omitempty"`
 	LUKSPassphraseNames       []string               `json:"luksPassphraseNames,omitempty"`
-	MirrorHandle              string                 `json:"mirrorHandle,omitempty"`
+	// LUKSWrappedKey is the KMS-wrapped data-encryption key, if the volume's LUKS key is held by a KMS
+	LUKSWrappedKey *utils.LUKSWrappedKey `json:"luksWrappedKey,omitempty"`
+	MirrorHandle   string                `json:"mirrorHandle,omitempty"`
 	// IsMirrorDestination is whether the volume is currently the destination in a mirror relationship
 	IsMirrorDestination bool `json:"mirrorDestination,omitempty"`
 	// PeerVolumeHandle is the internal volume handle for the source volume if this volume is a mirror destination
 	PeerVolumeHandle string `json:"requiredPeerVolumeHandle,omitempty"`
+	// ReplicaVolume is the name of this volume's cross-zone mirror destination, if it has one
+	ReplicaVolume string `json:"replicaVolume,omitempty"`
+	// ReplicaSourceVolume is the name of the primary volume if this volume is a cross-zone replica
+	ReplicaSourceVolume string `json:"replicaSourceVolume,omitempty"`
 	// InternalID is an optional, backend-specific identifier to help find an object
 	InternalID         string                 `json:"internalID,omitempty"`
 	ShareSourceVolume  string                 `json:"shareSourceVolume"`
 	SubordinateVolumes map[string]interface{} `json:"-"`
+	// SCSIPersistentReservation is whether the nodes sharing this raw block volume coordinate with SCSI-3
+	// persistent reservations
+	SCSIPersistentReservation bool `json:"scsiPersistentReservation,omitempty"`
 }
 
 type VolumeCreatingConfig struct {
@@ -117,6 +126,8 @@ const (
 	VolumeStateUpgrading      = VolumeState("upgrading")
 	VolumeStateMissingBackend = VolumeState("missing_backend")
 	VolumeStateSubordinate    = VolumeState("subordinate")
+	// VolumeStateRestoring is held in memory only, while a volume is restored from a snapshot
+	VolumeStateRestoring = VolumeState("restoring")
 	// TODO should Orphaned be moved to a VolumeState?
 )
 
@@ -156,6 +167,10 @@ func (s VolumeState) IsSubordinate() bool {
 	return s == VolumeStateSubordinate
 }
 
+func (s VolumeState) IsRestoring() bool {
+	return s == VolumeStateRestoring
+}
+
 func NewVolume(conf *VolumeConfig, backendUUID, pool string, orphaned bool, state VolumeState) *Volume {
 	return &Volume{
 		Config:      conf,
@@ -239,6 +254,41 @@ func (r *UpgradeVolumeRequest) Validate() error {
 	return nil
 }
 
+type LUKSPassphraseRotationState string
+
+const (
+	LUKSPassphraseRotationPending  = LUKSPassphraseRotationState("pending")
+	LUKSPassphraseRotationRotating = LUKSPassphraseRotationState("rotating")
+	LUKSPassphraseRotationComplete = LUKSPassphraseRotationState("complete")
+	LUKSPassphraseRotationFailed   = LUKSPassphraseRotationState("failed")
+)
+
+// LUKSPassphraseRotation tracks the progress of moving a LUKS-encrypted volume to the passphrase
+// currently held in the volume's node-stage secret.
+type LUKSPassphraseRotation struct {
+	VolumeName     string                      `json:"volumeName"`
+	State          LUKSPassphraseRotationState `json:"state"`
+	PassphraseName string                      `json:"passphraseName"`
+	// VolumePassphraseNames are the passphrase names Trident last recorded for the volume
+	VolumePassphraseNames []string `json:"volumePassphraseNames,omitempty"`
+	// Snapshots lists snapshots of the volume that do not yet reference the current passphrase
+	Snapshots      []string  `json:"snapshots,omitempty"`
+	Node           string    `json:"node,omitempty"`
+	Attempts       int       `json:"attempts"`
+	Message        string    `json:"message,omitempty"`
+	StartTime      time.Time `json:"startTime,omitempty"`
+	CompletionTime time.Time `json:"completionTime,omitempty"`
+}
+
+// UsesPreviousPassphrase returns true if the volume or any of its snapshots may still require a
+// passphrase other than the current one.
+func (r *LUKSPassphraseRotation) UsesPreviousPassphrase() bool {
+	if len(r.Snapshots) > 0 {
+		return true
+	}
+	return len(r.VolumePassphraseNames) != 1 || r.VolumePassphraseNames[0] != r.PassphraseName
+}
+
 type PatchRequestStringSlice struct {
 	Op    string   `json:"op"`
 	Path  string   `json:"path"`
@@ -50,3 +50,38 @@ func TestVolumeState(t *testing.T) {
 		assert.True(t, test.predicate(test.input), "Predicate failed")
 	}
 }
+
+func TestLUKSPassphraseRotationUsesPreviousPassphrase(t *testing.T) {
+	tests := map[string]struct {
+		rotation LUKSPassphraseRotation
+		expected bool
+	}{
+		"Current only": {
+			rotation: LUKSPassphraseRotation{PassphraseName: "B", VolumePassphraseNames: []string{"B"}},
+			expected: false,
+		},
+		"Current and previous": {
+			rotation: LUKSPassphraseRotation{PassphraseName: "B", VolumePassphraseNames: []string{"B", "A"}},
+			expected: true,
+		},
+		"Previous only": {
+			rotation: LUKSPassphraseRotation{PassphraseName: "B", VolumePassphraseNames: []string{"A"}},
+			expected: true,
+		},
+		"No names": {
+			rotation: LUKSPassphraseRotation{PassphraseName: "B"},
+			expected: true,
+		},
+		"Snapshot on previous": {
+			rotation: LUKSPassphraseRotation{
+				PassphraseName: "B", VolumePassphraseNames: []string{"B"}, Snapshots: []string{"snap1"},
+			},
+			expected: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.rotation.UsesPreviousPassphrase())
+		})
+	}
+}
@@ -3,6 +3,8 @@
 package storage
 
 import (
+	"time"
+
 	v1 "k8s.io/api/core/v1"
 )
 
@@ -28,6 +30,19 @@ type VolumeTransaction struct {
 	SnapshotConfig       *SnapshotConfig
 	PVUpgradeConfig      *PVUpgradeConfig
 	Op                   VolumeOperation
+	// StartTime is when the operation began, so that abandoned transactions may be found and recovered
+	StartTime time.Time
+	// Failed is set when an abandoned transaction could not be recovered automatically
+	Failed bool
+}
+
+// VolumeTransactionExternal describes an in-flight transaction for display by the REST API and tridentctl.
+type VolumeTransactionExternal struct {
+	Name       string          `json:"name"`
+	Op         VolumeOperation `json:"op"`
+	StartTime  string          `json:"startTime,omitempty"`
+	AgeSeconds int64           `json:"ageSeconds"`
+	Failed     bool            `json:"failed,omitempty"`
 }
 
 type PVUpgradeConfig struct {
@@ -50,3 +65,17 @@ func (t *VolumeTransaction) Name() string {
 		return t.Config.Name
 	}
 }
+
+// ConstructExternal returns the external form of the transaction, with its age measured from now.
+func (t *VolumeTransaction) ConstructExternal() *VolumeTransactionExternal {
+	external := &VolumeTransactionExternal{
+		Name:   t.Name(),
+		Op:     t.Op,
+		Failed: t.Failed,
+	}
+	if !t.StartTime.IsZero() {
+		external.StartTime = t.StartTime.UTC().Format(time.RFC3339)
+		external.AgeSeconds = int64(time.Since(t.StartTime).Seconds())
+	}
+	return external
+}
@@ -4,7 +4,9 @@ package storageattribute
 
 const (
 	// Constants for integer storage category attributes
-	IOPS = "IOPS"
+	IOPS          = "IOPS"
+	MaxVolumeSize = "maxVolumeSize"
+	MaxSnapshots  = "maxSnapshots"
 
 	// Constants for boolean storage category attributes
 	Snapshots   = "snapshots"
@@ -19,6 +21,7 @@ const (
 	Region           = "region"
 	Zone             = "zone"
 	NASType          = "nasType"
+	FileSystemTypes  = "fileSystemTypes"
 
 	// Constants for label attributes
 	Labels   = "labels"
@@ -47,6 +50,14 @@ const (
 	StoragePools           = "storagePools"
 	AdditionalStoragePools = "additionalStoragePools"
 	ExcludeStoragePools    = "excludeStoragePools"
+
+	// Cross-zone replica storage class parameters
+	CrossZoneReplica    = "crossZoneReplica"
+	ReplicationPolicy   = "replicationPolicy"
+	ReplicationSchedule = "replicationSchedule"
+
+	// PoolUtilizationThreshold prefers pools using no more than this percentage of their performance capacity
+	PoolUtilizationThreshold = "poolUtilizationThreshold"
 )
 
 var attrTypes = map[string]Type{
@@ -67,4 +78,7 @@ var attrTypes = map[string]Type{
 	NonexistentBool:  boolType,
 	Replication:      boolType,
 	NASType:          stringType,
+	MaxVolumeSize:    intType,
+	MaxSnapshots:     intType,
+	FileSystemTypes:  stringType,
 }
@@ -4,6 +4,16 @@ package storageattribute
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	intComparisonRegex = regexp.MustCompile(`^(?P<operator>>=|<=|!=|==|=|>|<)\s*(?P<value>[^\s<>=!]+)$`)
+	intRangeRegex      = regexp.MustCompile(`^(?P<min>[^\s-]+)\s*-\s*(?P<max>[^\s-]+)$`)
 )
 
 func NewIntOffer(min, max int) Offer {
@@ -13,12 +23,18 @@ func NewIntOffer(min, max int) Offer {
 	}
 }
 
+// Matches returns whether at least one value in the offered range satisfies the request.
 func (o *intOffer) Matches(r Request) bool {
-	ir, ok := r.(*intRequest)
-	if !ok {
+	switch request := r.(type) {
+	case *intRequest:
+		return request.Request >= o.Min && request.Request <= o.Max
+	case *intComparisonRequest:
+		return request.matchesRange(o.Min, o.Max)
+	case *intRangeRequest:
+		return request.min <= o.Max && request.max >= o.Min
+	default:
 		return false
 	}
-	return ir.Request >= o.Min && ir.Request <= o.Max
 }
 
 func (o *intOffer) String() string {
@@ -35,6 +51,74 @@ func NewIntRequest(request int) Request {
 	}
 }
 
+// NewIntRequestFromExpression parses an integer request, which may be a plain integer or quantity ("100Gi"),
+// a comparison (">=100Gi", "!=0"), or an inclusive range ("1Gi-10Gi").
+func NewIntRequestFromExpression(request string) (Request, error) {
+	request = strings.TrimSpace(request)
+
+	v, parseErr := strconv.ParseInt(request, 10, 0)
+	if parseErr == nil {
+		return NewIntRequest(int(v)), nil
+	}
+
+	if match := intComparisonRegex.FindStringSubmatch(request); match != nil {
+		value, err := parseIntQuantity(match[2])
+		if err != nil {
+			return nil, err
+		}
+		operator := match[1]
+		if operator == "==" {
+			operator = "="
+		}
+		return &intComparisonRequest{
+			Request:  request,
+			operator: operator,
+			value:    value,
+		}, nil
+	}
+
+	if match := intRangeRegex.FindStringSubmatch(request); match != nil {
+		min, err := parseIntQuantity(match[1])
+		if err != nil {
+			return nil, err
+		}
+		max, err := parseIntQuantity(match[2])
+		if err != nil {
+			return nil, err
+		}
+		if min > max {
+			return nil, fmt.Errorf("invalid range %s; the minimum is greater than the maximum", request)
+		}
+		return &intRangeRequest{
+			Request: request,
+			min:     min,
+			max:     max,
+		}, nil
+	}
+
+	if value, err := parseIntQuantity(request); err == nil {
+		return NewIntRequest(value), nil
+	}
+
+	return nil, parseErr
+}
+
+// parseIntQuantity parses a whole number that may be written as a quantity with units, such as 100Gi or 5k.
+func parseIntQuantity(value string) (int, error) {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer value %s; %v", value, err)
+	}
+	if i, ok := quantity.AsInt64(); ok {
+		return int(i), nil
+	}
+	// Quantities such as 1.5Gi are whole numbers even though they are written as fractions
+	if quantity.MilliValue()%1000 != 0 {
+		return 0, fmt.Errorf("invalid integer value %s; not a whole number", value)
+	}
+	return int(quantity.Value()), nil
+}
+
 func (r *intRequest) Value() interface{} {
 	return r.Request
 }
@@ -46,3 +130,47 @@ func (r *intRequest) GetType() Type {
 func (r *intRequest) String() string {
 	return fmt.Sprintf("%d", r.Request)
 }
+
+// matchesRange returns whether any value between min and max satisfies the comparison.
+func (r *intComparisonRequest) matchesRange(min, max int) bool {
+	switch r.operator {
+	case "=":
+		return r.value >= min && r.value <= max
+	case "!=":
+		return min != r.value || max != r.value
+	case ">":
+		return max > r.value
+	case ">=":
+		return max >= r.value
+	case "<":
+		return min < r.value
+	case "<=":
+		return min <= r.value
+	default:
+		return false
+	}
+}
+
+func (r *intComparisonRequest) Value() interface{} {
+	return r.Request
+}
+
+func (r *intComparisonRequest) GetType() Type {
+	return intType
+}
+
+func (r *intComparisonRequest) String() string {
+	return r.Request
+}
+
+func (r *intRangeRequest) Value() interface{} {
+	return r.Request
+}
+
+func (r *intRangeRequest) GetType() Type {
+	return intType
+}
+
+func (r *intRangeRequest) String() string {
+	return r.Request
+}
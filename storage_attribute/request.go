@@ -68,14 +68,17 @@ func CreateAttributeRequestFromAttributeValue(name, val string) (Request, error)
 		}
 		req = NewBoolRequest(v)
 	case intType:
-		v, err := strconv.ParseInt(val, 10, 0)
+		req, err = NewIntRequestFromExpression(val)
 		if err != nil {
 			return nil, fmt.Errorf("storage attribute value (%s) doesn't match the specified type (%s); %v", val,
 				valType, err)
 		}
-		req = NewIntRequest(int(v))
 	case stringType:
-		req = NewStringRequest(val)
+		req, err = NewStringRequestFromExpression(val)
+		if err != nil {
+			return nil, fmt.Errorf("storage attribute value (%s) doesn't match the specified type (%s); %v", val,
+				valType, err)
+		}
 	case labelType:
 		req, err = NewLabelRequest(val)
 		if err != nil {
@@ -88,6 +91,22 @@ func CreateAttributeRequestFromAttributeValue(name, val string) (Request, error)
 	return req, nil
 }
 
+// MatchesMissingOffer returns whether a request is satisfied by a storage pool that doesn't offer the
+// requested attribute at all, which is only the case for requests that exclude values, such as
+// "!= hdd" or "notin (zone1, zone2)".
+func MatchesMissingOffer(r Request) bool {
+	switch request := r.(type) {
+	case *stringSetRequest:
+		return request.negated
+	case *intComparisonRequest:
+		return request.operator == "!="
+	case *labelRequest:
+		return NewLabelOffer().Matches(request)
+	default:
+		return false
+	}
+}
+
 func CreateBackendStoragePoolsMapFromEncodedString(
 	arg string,
 ) (map[string][]string, error) {
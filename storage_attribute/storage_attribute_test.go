@@ -437,3 +437,117 @@ func TestUnmarshalOfferMapNegative(t *testing.T) {
 		assert.Equal(t, test.expectedErr, actualErr, fmt.Sprintf("Test case %d failed", i))
 	}
 }
+
+func TestMatchesExpressions(t *testing.T) {
+	mustCreate := func(name, val string) Request {
+		req, err := CreateAttributeRequestFromAttributeValue(name, val)
+		if err != nil {
+			t.Fatalf("could not create request %s: %v", val, err)
+		}
+		return req
+	}
+
+	for _, test := range []struct {
+		r        Request
+		o        Offer
+		expected bool
+	}{
+		{mustCreate(MaxVolumeSize, "100Gi"), NewIntOffer(0, 1099511627776), true},
+		{mustCreate(MaxVolumeSize, ">=100Gi"), NewIntOffer(0, 1099511627776), true},
+		{mustCreate(MaxVolumeSize, "> 1Ti"), NewIntOffer(0, 1099511627776), false},
+		{mustCreate(MaxVolumeSize, "<=1Ti"), NewIntOffer(0, 1099511627776), true},
+		{mustCreate(MaxVolumeSize, ">1.5Ti"), NewIntOffer(0, 1099511627776), false},
+		{mustCreate(IOPS, "< 1000"), NewIntOffer(1000, 10000), false},
+		{mustCreate(IOPS, "<=1000"), NewIntOffer(1000, 10000), true},
+		{mustCreate(IOPS, "==5k"), NewIntOffer(1000, 10000), true},
+		{mustCreate(IOPS, "!=1000"), NewIntOffer(1000, 10000), true},
+		{mustCreate(IOPS, "!=1000"), NewIntOffer(1000, 1000), false},
+		{mustCreate(IOPS, "500-1500"), NewIntOffer(1000, 10000), true},
+		{mustCreate(IOPS, "11000 - 20000"), NewIntOffer(1000, 10000), false},
+		{mustCreate(MaxSnapshots, ">=200"), NewIntOffer(0, 32), false},
+		{mustCreate(MaxSnapshots, ">=200"), NewStringOffer("200"), false},
+		{mustCreate(Media, "!= hdd"), NewStringOffer("hdd"), false},
+		{mustCreate(Media, "!=hdd"), NewStringOffer("hdd", "ssd"), false},
+		{mustCreate(Media, "!=hdd"), NewStringOffer("ssd", "hybrid"), true},
+		{mustCreate(Zone, "in (zone1, zone2)"), NewStringOffer("zone2"), true},
+		{mustCreate(Zone, "in (zone1, zone2)"), NewStringOffer("zone3"), false},
+		{mustCreate(Zone, "notin (zone1, zone2)"), NewStringOffer("zone2"), false},
+		{mustCreate(Zone, "notin (zone1, zone2)"), NewStringOffer("zone3"), true},
+		{mustCreate(Zone, "notin (zone1, zone2)"), NewStringOffer("zone3", "zone1"), false},
+		{mustCreate(Zone, "notin (zone1, zone2)"), NewStringOffer("zone3", "zone4"), true},
+		{mustCreate(Zone, "in (zone1, zone2)"), NewStringOffer("zone3", "zone1"), true},
+		{mustCreate(FileSystemTypes, "xfs"), NewStringOffer("ext4", "xfs"), true},
+		{mustCreate(Zone, "notin (zone1)"), NewIntOffer(0, 10), false},
+	} {
+		assert.Equal(t, test.expected, test.o.Matches(test.r), "request %s, offer %s", test.r, test.o.ToString())
+	}
+}
+
+func TestCreateAttributeRequestFromExpression(t *testing.T) {
+	for _, val := range []string{">=100Gi", "!= 0", "1Gi-10Gi"} {
+		req, err := CreateAttributeRequestFromAttributeValue(MaxVolumeSize, val)
+		if assert.NoError(t, err, val) {
+			// Expressions must survive being persisted
+			assert.Equal(t, val, req.String())
+			assert.Equal(t, intType, req.GetType())
+		}
+	}
+
+	req, err := CreateAttributeRequestFromAttributeValue(MaxVolumeSize, "1Gi")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1073741824, req.Value())
+	}
+
+	req, err = CreateAttributeRequestFromAttributeValue(Zone, " notin (zone1, zone2) ")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "notin (zone1, zone2)", req.String())
+		assert.Equal(t, stringType, req.GetType())
+	}
+
+	for _, test := range []struct {
+		name string
+		val  string
+	}{
+		{MaxVolumeSize, ">=lots"},
+		{MaxVolumeSize, "10Gi-1Gi"},
+		{MaxVolumeSize, ">"},
+		{IOPS, "10.52"},
+		{Zone, "in ()"},
+		{Zone, "notin ( , )"},
+	} {
+		_, err := CreateAttributeRequestFromAttributeValue(test.name, test.val)
+		assert.Error(t, err, test.val)
+	}
+}
+
+func TestMatchesMissingOffer(t *testing.T) {
+	for _, test := range []struct {
+		r        Request
+		expected bool
+	}{
+		{NewIntRequest(5), false},
+		{NewStringRequest("hdd"), false},
+		{NewBoolRequest(true), false},
+		{NewLabelRequestMustCompile("performance=gold"), false},
+		{NewLabelRequestMustCompile("performance notin (gold); !cloud"), true},
+	} {
+		assert.Equal(t, test.expected, MatchesMissingOffer(test.r), test.r.String())
+	}
+
+	for _, test := range []struct {
+		name     string
+		val      string
+		expected bool
+	}{
+		{Media, "!= hdd", true},
+		{Zone, "notin (zone1, zone2)", true},
+		{Zone, "in (zone1, zone2)", false},
+		{MaxSnapshots, "!= 0", true},
+		{MaxSnapshots, ">= 10", false},
+	} {
+		req, err := CreateAttributeRequestFromAttributeValue(test.name, test.val)
+		if assert.NoError(t, err) {
+			assert.Equal(t, test.expected, MatchesMissingOffer(req), test.val)
+		}
+	}
+}
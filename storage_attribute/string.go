@@ -4,9 +4,16 @@ package storageattribute
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 )
 
+var (
+	stringNotEqualRegex = regexp.MustCompile(`^!=\s*(?P<value>\S.*)$`)
+	stringInSetRegex    = regexp.MustCompile(`^in\s*[(](?P<set>[^()]*)[)]$`)
+	stringNotInSetRegex = regexp.MustCompile(`^notin\s*[(](?P<set>[^()]*)[)]$`)
+)
+
 func NewStringOffer(offers ...string) Offer {
 	return &stringOffer{
 		Offers: offers,
@@ -36,14 +43,30 @@ func NewStringOfferFromOffers(offers ...Offer) Offer {
 	}
 }
 
+// Matches returns whether the offer satisfies the request.  Plain and "in" requests match if any offered
+// value is requested.  Negated requests ("!=" and "notin") match only if none of the offered values is
+// excluded, so a pool offering both hdd and ssd does not match "!= hdd".
 func (o *stringOffer) Matches(r Request) bool {
-	sr, ok := r.(*stringRequest)
-	if !ok {
-		return false
-	}
-	for _, s := range o.Offers {
-		if s == sr.Request {
-			return true
+	switch request := r.(type) {
+	case *stringRequest:
+		for _, s := range o.Offers {
+			if s == request.Request {
+				return true
+			}
+		}
+	case *stringSetRequest:
+		if request.negated {
+			for _, s := range o.Offers {
+				if request.containsValue(s) {
+					return false
+				}
+			}
+			return len(o.Offers) > 0
+		}
+		for _, s := range o.Offers {
+			if request.containsValue(s) {
+				return true
+			}
 		}
 	}
 	return false
@@ -63,6 +86,45 @@ func NewStringRequest(request string) Request {
 	}
 }
 
+// NewStringRequestFromExpression parses a string request, which may be a plain value or one of the
+// expressions "!= value", "in (value1, value2)" or "notin (value1, value2)".
+func NewStringRequestFromExpression(request string) (Request, error) {
+	trimmed := strings.TrimSpace(request)
+
+	if match := stringNotEqualRegex.FindStringSubmatch(trimmed); match != nil {
+		return &stringSetRequest{
+			Request: trimmed,
+			values:  []string{strings.TrimSpace(match[1])},
+			negated: true,
+		}, nil
+	}
+
+	for _, setRegex := range []*regexp.Regexp{stringInSetRegex, stringNotInSetRegex} {
+		match := setRegex.FindStringSubmatch(trimmed)
+		if match == nil {
+			continue
+		}
+
+		values := make([]string, 0)
+		for _, value := range strings.Split(match[1], ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("value set may not be empty: %s", trimmed)
+		}
+
+		return &stringSetRequest{
+			Request: trimmed,
+			values:  values,
+			negated: setRegex == stringNotInSetRegex,
+		}, nil
+	}
+
+	return NewStringRequest(request), nil
+}
+
 func (r *stringRequest) Value() interface{} {
 	return r.Request
 }
@@ -74,3 +136,25 @@ func (r *stringRequest) GetType() Type {
 func (r *stringRequest) String() string {
 	return r.Request
 }
+
+// containsValue returns whether a value is in the request's set of values.
+func (r *stringSetRequest) containsValue(value string) bool {
+	for _, v := range r.values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *stringSetRequest) Value() interface{} {
+	return r.Request
+}
+
+func (r *stringSetRequest) GetType() Type {
+	return stringType
+}
+
+func (r *stringSetRequest) String() string {
+	return r.Request
+}
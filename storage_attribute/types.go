@@ -33,6 +33,18 @@ type intRequest struct {
 	Request int `json:"request"`
 }
 
+type intComparisonRequest struct {
+	Request  string `json:"request"`
+	operator string
+	value    int
+}
+
+type intRangeRequest struct {
+	Request string `json:"request"`
+	min     int
+	max     int
+}
+
 type boolOffer struct {
 	Offer bool `json:"offer"`
 }
@@ -49,6 +61,12 @@ type stringRequest struct {
 	Request string `json:"request"`
 }
 
+type stringSetRequest struct {
+	Request string `json:"request"`
+	values  []string
+	negated bool
+}
+
 type labelOffer struct {
 	Offers map[string]string `json:"offer"`
 }
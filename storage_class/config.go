@@ -18,6 +18,12 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 		RequiredStorage map[string][]string `json:"requiredStorage,omitempty"`
 		AdditionalPools map[string][]string `json:"additionalStoragePools,omitempty"`
 		ExcludePools    map[string][]string `json:"excludeStoragePools,omitempty"`
+
+		CrossZoneReplica    bool   `json:"crossZoneReplica,omitempty"`
+		ReplicationPolicy   string `json:"replicationPolicy,omitempty"`
+		ReplicationSchedule string `json:"replicationSchedule,omitempty"`
+
+		PoolUtilizationThreshold int `json:"poolUtilizationThreshold,omitempty"`
 	}
 	err := json.Unmarshal(data, &tmp)
 	if err != nil {
@@ -36,6 +42,10 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	}
 
 	c.ExcludePools = tmp.ExcludePools
+	c.CrossZoneReplica = tmp.CrossZoneReplica
+	c.ReplicationPolicy = tmp.ReplicationPolicy
+	c.ReplicationSchedule = tmp.ReplicationSchedule
+	c.PoolUtilizationThreshold = tmp.PoolUtilizationThreshold
 
 	return err
 }
@@ -49,12 +59,22 @@ func (c *Config) MarshalJSON() ([]byte, error) {
 		Pools           map[string][]string `json:"storagePools,omitempty"`
 		AdditionalPools map[string][]string `json:"additionalStoragePools,omitempty"`
 		ExcludePools    map[string][]string `json:"excludeStoragePools,omitempty"`
+
+		CrossZoneReplica    bool   `json:"crossZoneReplica,omitempty"`
+		ReplicationPolicy   string `json:"replicationPolicy,omitempty"`
+		ReplicationSchedule string `json:"replicationSchedule,omitempty"`
+
+		PoolUtilizationThreshold int `json:"poolUtilizationThreshold,omitempty"`
 	}
 	tmp.Version = c.Version
 	tmp.Name = c.Name
 	tmp.Pools = c.Pools
 	tmp.AdditionalPools = c.AdditionalPools
 	tmp.ExcludePools = c.ExcludePools
+	tmp.CrossZoneReplica = c.CrossZoneReplica
+	tmp.ReplicationPolicy = c.ReplicationPolicy
+	tmp.ReplicationSchedule = c.ReplicationSchedule
+	tmp.PoolUtilizationThreshold = c.PoolUtilizationThreshold
 	// TODO (agagan): The below function MarshalRequestMap always return a positive response.
 	//  The negative use case is not covered in the unit test.
 	attrs, err := storageattribute.MarshalRequestMap(c.Attributes)
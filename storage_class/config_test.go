@@ -73,3 +73,21 @@ func TestMarshalJSON(t *testing.T) {
 	assert.Empty(t, jsonMap["attributes"], "config attribute is not empty")
 	assert.Equal(t, "v1", jsonMap["version"], "config version does not match")
 }
+
+func TestMarshalJSON_CrossZoneReplica(t *testing.T) {
+	conf := &Config{
+		Name:                "gold",
+		Version:             "v1",
+		CrossZoneReplica:    true,
+		ReplicationPolicy:   "MirrorAllSnapshots",
+		ReplicationSchedule: "5min",
+	}
+	response, err := json.Marshal(conf)
+	assert.NoError(t, err, "found error")
+
+	result := &Config{}
+	assert.NoError(t, json.Unmarshal(response, result), "found error")
+	assert.True(t, result.CrossZoneReplica, "cross-zone replica was not preserved")
+	assert.Equal(t, "MirrorAllSnapshots", result.ReplicationPolicy, "replication policy does not match")
+	assert.Equal(t, "5min", result.ReplicationSchedule, "replication schedule does not match")
+}
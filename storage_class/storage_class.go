@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -17,6 +18,7 @@ import (
 	. "github.com/netapp/trident/logger"
 	"github.com/netapp/trident/storage"
 	storageattribute "github.com/netapp/trident/storage_attribute"
+	"github.com/netapp/trident/utils"
 )
 
 type BackendPoolInfo struct {
@@ -141,6 +143,12 @@ func (s *StorageClass) regexMatcher(ctx context.Context, storagePool storage.Poo
 }
 
 func (s *StorageClass) Matches(ctx context.Context, storagePool storage.Pool) bool {
+	matches, _ := s.matches(ctx, storagePool)
+	return matches
+}
+
+// matches returns whether a storage pool satisfies the storage class, and why.
+func (s *StorageClass) matches(ctx context.Context, storagePool storage.Pool) (bool, string) {
 	Logc(ctx).WithFields(log.Fields{
 		"storageClass": s.GetName(),
 		"config":       s.config,
@@ -151,14 +159,14 @@ func (s *StorageClass) Matches(ctx context.Context, storagePool storage.Pool) bo
 	// Check excludeStoragePools first, since it can reject a match
 	if len(s.config.ExcludePools) > 0 {
 		if matches := s.regexMatcher(ctx, storagePool, s.config.ExcludePools); matches {
-			return false
+			return false, "pool is listed in excludeStoragePools"
 		}
 	}
 
 	// Check additionalStoragePools next, since it can yield a match result by itself
 	if len(s.config.AdditionalPools) > 0 {
 		if matches := s.regexMatcher(ctx, storagePool, s.config.AdditionalPools); matches {
-			return true
+			return true, "pool is listed in additionalStoragePools"
 		}
 
 		// Handle the sub-case where additionalStoragePools is specified (but didn't match) and
@@ -169,7 +177,7 @@ func (s *StorageClass) Matches(ctx context.Context, storagePool storage.Pool) bo
 				"storageClass": s.GetName(),
 				"pool":         storagePool.Name(),
 			}).Debug("Pool failed to match storage class additionalStoragePools attribute.")
-			return false
+			return false, "pool is not listed in additionalStoragePools"
 		}
 	}
 
@@ -177,6 +185,7 @@ func (s *StorageClass) Matches(ctx context.Context, storagePool storage.Pool) bo
 	// specified, then all pools can match.  If one or more attributes are specified in the
 	// storage class, then all must match.
 	attributesMatch := true
+	reason := ""
 	for name, request := range s.config.Attributes {
 
 		// Remap the "selector" storage class attribute to the "labels" pool attribute
@@ -184,7 +193,11 @@ func (s *StorageClass) Matches(ctx context.Context, storagePool storage.Pool) bo
 			name = "labels"
 		}
 
-		if offer, ok := storagePool.Attributes()[name]; !ok || !offer.Matches(request) {
+		offer, ok := storagePool.Attributes()[name]
+		if !ok && storageattribute.MatchesMissingOffer(request) {
+			continue
+		}
+		if !ok || !offer.Matches(request) {
 			Logc(ctx).WithFields(log.Fields{
 				"offer":        offer,
 				"request":      request,
@@ -194,6 +207,12 @@ func (s *StorageClass) Matches(ctx context.Context, storagePool storage.Pool) bo
 				"found":        ok,
 			}).Debug("Attribute for storage pool failed to match storage class.")
 			attributesMatch = false
+			if !ok {
+				reason = fmt.Sprintf("pool does not offer attribute %s", name)
+			} else {
+				reason = fmt.Sprintf("pool offers %s %s, which does not satisfy %s", name, offer.ToString(),
+					request.String())
+			}
 			break
 		}
 	}
@@ -204,6 +223,9 @@ func (s *StorageClass) Matches(ctx context.Context, storagePool storage.Pool) bo
 	poolsMatch := true
 	if len(s.config.Pools) > 0 {
 		poolsMatch = s.regexMatcher(ctx, storagePool, s.config.Pools)
+		if !poolsMatch && attributesMatch {
+			reason = "pool is not listed in storagePools"
+		}
 	}
 
 	result := attributesMatch && poolsMatch
@@ -216,7 +238,10 @@ func (s *StorageClass) Matches(ctx context.Context, storagePool storage.Pool) bo
 		"storageClass":    s.GetName(),
 	}).Debug("Result of pool match for storage class.")
 
-	return result
+	if result {
+		reason = "pool matches storage class"
+	}
+	return result, reason
 }
 
 // CheckAndAddBackend iterates through each of the storage pools
@@ -286,32 +311,62 @@ func (s *StorageClass) GetAdditionalStoragePools() map[string][]string {
 	return s.config.AdditionalPools
 }
 
+// HasCrossZoneReplica returns whether volumes in this storage class get a mirrored replica in another zone
+func (s *StorageClass) HasCrossZoneReplica() bool {
+	return s.config.CrossZoneReplica
+}
+
+func (s *StorageClass) GetReplicationPolicy() string {
+	return s.config.ReplicationPolicy
+}
+
+func (s *StorageClass) GetReplicationSchedule() string {
+	return s.config.ReplicationSchedule
+}
+
+// GetPoolUtilizationThreshold returns the performance utilization percentage above which pools are avoided,
+// or zero if pools are not ordered by utilization.
+func (s *StorageClass) GetPoolUtilizationThreshold() int {
+	return s.config.PoolUtilizationThreshold
+}
+
 func (s *StorageClass) GetStoragePoolsForProtocol(
 	ctx context.Context, p config.Protocol, accessMode config.AccessMode,
 ) []storage.Pool {
 	ret := make([]storage.Pool, 0, len(s.pools))
 	// TODO:  Change this to work with indices of backends?
 	for _, storagePool := range s.pools {
-		storagePoolProtocol := storagePool.Backend().GetProtocol(ctx)
-
-		if p == config.ProtocolAny || storagePoolProtocol == p {
-			// TODO (arorar): Remove this check after ROX is disabled for iSCSI (non-raw block) volumes.
-			if storagePoolProtocol == config.BlockOnFile && (accessMode == config.
-				ReadOnlyMany || accessMode == config.ReadWriteMany) {
-				continue
-			}
-
+		if supported, _ := isProtocolSupportedByPool(ctx, storagePool, p, accessMode); supported {
 			ret = append(ret, storagePool)
 		}
+	}
+	return ret
+}
 
-		// AddRawBlockSupportOnBoF: Add below else-if code block to allow raw block volumes on BlockOnFile
-		// Allow only RWO raw-block on Block-On-File
+// isProtocolSupportedByPool returns whether the specific pool can create volumes with the given protocol and
+// access mode, and if not, why.
+func isProtocolSupportedByPool(
+	ctx context.Context, storagePool storage.Pool, p config.Protocol, accessMode config.AccessMode,
+) (bool, string) {
+	storagePoolProtocol := storagePool.Backend().GetProtocol(ctx)
 
-		// else if p == config.Block && accessMode == config.ReadWriteOnce && storagePoolProtocol == config.BlockOnFile {
-		//     ret = append(ret, storagePool)
-		// }
+	if p != config.ProtocolAny && storagePoolProtocol != p {
+		return false, fmt.Sprintf("backend protocol %s does not match requested protocol %s", storagePoolProtocol, p)
 	}
-	return ret
+
+	// TODO (arorar): Remove this check after ROX is disabled for iSCSI (non-raw block) volumes.
+	if storagePoolProtocol == config.BlockOnFile && (accessMode == config.
+		ReadOnlyMany || accessMode == config.ReadWriteMany) {
+		return false, fmt.Sprintf("backend protocol %s does not support access mode %s", storagePoolProtocol,
+			accessMode)
+	}
+
+	// AddRawBlockSupportOnBoF: Allow only RWO raw-block on Block-On-File
+	// if p == config.Block && accessMode == config.ReadWriteOnce && storagePoolProtocol == config.BlockOnFile {
+	//     return true, ""
+	// }
+
+	return true, ""
 }
 
 // isTopologySupportedByPool returns whether the specific pool can create volumes accessible by the given topology
@@ -358,6 +413,33 @@ func FilterPoolsOnTopology(
 	return filteredPools
 }
 
+// FilterPoolsOutsideTopology returns a subset of the provided pools that are limited to topologies, none of
+// which can satisfy any of the excludedTopologies.  Pools with no topology restrictions are never returned, since
+// they could not place a volume in a distinct failure domain.
+func FilterPoolsOutsideTopology(
+	ctx context.Context, pools []storage.Pool, excludedTopologies []map[string]string,
+) []storage.Pool {
+	filteredPools := make([]storage.Pool, 0)
+
+	for _, pool := range pools {
+		if len(pool.SupportedTopologies()) == 0 {
+			continue
+		}
+		overlaps := false
+		for _, topology := range excludedTopologies {
+			if isTopologySupportedByPool(ctx, pool, topology) {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			filteredPools = append(filteredPools, pool)
+		}
+	}
+
+	return filteredPools
+}
+
 // FilterPoolsOnNasType returns pools filtered over nasType SMB. If not found returns the provided pool list as it is.
 func FilterPoolsOnNasType(
 	ctx context.Context, pools []storage.Pool, scAttributes map[string]storageattribute.Request,
@@ -444,6 +526,46 @@ func SortPoolsByPreferredTopologies(
 	return append(orderedPools, remainingPools...)
 }
 
+// SortPoolsByUtilization moves pools whose backends report them using more than the threshold percentage of their
+// performance capacity to the end of the list, after pools that have not reported their utilization.  Pools over
+// the threshold are ordered from least to most utilized; the order of the other pools is unchanged.  A threshold
+// of zero or less leaves the pools in the order given.
+func SortPoolsByUtilization(ctx context.Context, pools []storage.Pool, threshold int) []storage.Pool {
+	if threshold <= 0 {
+		return pools
+	}
+
+	under := make([]storage.Pool, 0, len(pools))
+	unknown := make([]storage.Pool, 0)
+	over := make([]storage.Pool, 0)
+	for _, pool := range pools {
+		utilization, ok := pool.Utilization()
+		switch {
+		case !ok:
+			unknown = append(unknown, pool)
+		case utilization <= float64(threshold):
+			under = append(under, pool)
+		default:
+			over = append(over, pool)
+		}
+	}
+
+	sort.SliceStable(over, func(i, j int) bool {
+		iUtilization, _ := over[i].Utilization()
+		jUtilization, _ := over[j].Utilization()
+		return iUtilization < jUtilization
+	})
+
+	if len(over) > 0 {
+		Logc(ctx).WithFields(log.Fields{
+			"threshold": threshold,
+			"count":     len(over),
+		}).Debug("Storage pools over the utilization threshold will be tried last.")
+	}
+
+	return append(append(under, unknown...), over...)
+}
+
 // GetStoragePoolsForProtocolByBackend returns an ordered list of pools, where
 // each pool matches the supplied protocol.
 func (s *StorageClass) GetStoragePoolsForProtocolByBackend(
@@ -466,12 +588,89 @@ func (s *StorageClass) GetStoragePoolsForProtocolByBackend(
 		Logc(ctx).Info("no backend pools found for given NASType")
 	}
 	pools = SortPoolsByPreferredTopologies(ctx, pools, preferredTopologies)
+	pools = SortPoolsByUtilization(ctx, pools, s.GetPoolUtilizationThreshold())
 
 	Logc(ctx).Debugf("Finally got %d storage pools", len(pools))
 
 	return pools
 }
 
+// ExplainPlacement reports, for each of the supplied storage pools, whether provisioning a volume with the
+// given protocol and config in this storage class would try the pool, and if not, why.  Nothing is provisioned.
+// Accepted pools are returned first, in the order provisioning would try them.
+func (s *StorageClass) ExplainPlacement(
+	ctx context.Context, pools []storage.Pool, p config.Protocol, volumeConfig *storage.VolumeConfig,
+) []PoolPlacement {
+	var sizeRequest storageattribute.Request
+	if sizeBytes, err := utils.ConvertSizeToBytes(volumeConfig.Size); err == nil {
+		if size, err := strconv.ParseInt(sizeBytes, 10, 0); err == nil && size > 0 {
+			sizeRequest = storageattribute.NewIntRequest(int(size))
+		}
+	}
+
+	accepted := make([]storage.Pool, 0)
+	rejected := make([]PoolPlacement, 0)
+	reject := func(pool storage.Pool, reason string) {
+		rejected = append(rejected, newPoolPlacement(pool, false, reason))
+	}
+
+	for _, pool := range pools {
+		if matches, reason := s.matches(ctx, pool); !matches {
+			reject(pool, reason)
+		} else if supported, reason := isProtocolSupportedByPool(ctx, pool, p, volumeConfig.AccessMode); !supported {
+			reject(pool, reason)
+		} else if len(FilterPoolsOnTopology(ctx, []storage.Pool{pool}, volumeConfig.RequisiteTopologies)) == 0 {
+			reject(pool, "pool does not support any of the requisite topologies")
+		} else if len(FilterPoolsOnNasType(ctx, []storage.Pool{pool}, s.GetAttributes())) == 0 {
+			reject(pool, "pool does not offer the requested NAS type")
+		} else if offer, ok := pool.Attributes()[storageattribute.MaxVolumeSize]; ok && sizeRequest != nil &&
+			!offer.Matches(sizeRequest) {
+			reject(pool, fmt.Sprintf("requested size of %s bytes is larger than the pool's maximum volume size",
+				sizeRequest.String()))
+		} else if volumeConfig.IsMirrorDestination && !pool.Backend().CanMirror() {
+			reject(pool, "backend cannot host mirror destinations")
+		} else {
+			accepted = append(accepted, pool)
+		}
+	}
+
+	accepted = SortPoolsByPreferredTopologies(ctx, accepted, volumeConfig.PreferredTopologies)
+	accepted = SortPoolsByUtilization(ctx, accepted, s.GetPoolUtilizationThreshold())
+
+	placements := make([]PoolPlacement, 0, len(pools))
+	for _, pool := range accepted {
+		reason := "pool matches storage class"
+		if threshold := s.GetPoolUtilizationThreshold(); threshold > 0 {
+			if utilization, known := pool.Utilization(); known && utilization > float64(threshold) {
+				reason = fmt.Sprintf("pool matches storage class, but is %.0f%% utilized", utilization)
+			}
+		}
+		placements = append(placements, newPoolPlacement(pool, true, reason))
+	}
+	SortPoolPlacements(rejected)
+
+	return append(placements, rejected...)
+}
+
+func newPoolPlacement(pool storage.Pool, accepted bool, reason string) PoolPlacement {
+	return PoolPlacement{
+		Backend:  pool.Backend().Name(),
+		Pool:     pool.Name(),
+		Accepted: accepted,
+		Reason:   reason,
+	}
+}
+
+// SortPoolPlacements sorts pool placements by backend and pool name.
+func SortPoolPlacements(placements []PoolPlacement) {
+	sort.SliceStable(placements, func(i, j int) bool {
+		if placements[i].Backend != placements[j].Backend {
+			return placements[i].Backend < placements[j].Backend
+		}
+		return placements[i].Pool < placements[j].Pool
+	})
+}
+
 func (s *StorageClass) Pools() []storage.Pool {
 	return s.pools
 }
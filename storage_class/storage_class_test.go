@@ -491,6 +491,36 @@ func TestFilterPoolsOnTopology(t *testing.T) {
 	}
 }
 
+func TestFilterPoolsOutsideTopology(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+
+	zone1 := map[string]string{"topology.kubernetes.io/region": "R1", "topology.kubernetes.io/zone": "Z1"}
+	zone2 := map[string]string{"topology.kubernetes.io/region": "R1", "topology.kubernetes.io/zone": "Z2"}
+
+	fakePool1 := mockstorage.NewMockPool(mockCtrl)
+	fakePool1.EXPECT().SupportedTopologies().Return([]map[string]string{zone1}).AnyTimes()
+	fakePool2 := mockstorage.NewMockPool(mockCtrl)
+	fakePool2.EXPECT().SupportedTopologies().Return([]map[string]string{zone2}).AnyTimes()
+	fakePoolBoth := mockstorage.NewMockPool(mockCtrl)
+	fakePoolBoth.EXPECT().SupportedTopologies().Return([]map[string]string{zone1, zone2}).AnyTimes()
+	fakePoolNoRestriction := mockstorage.NewMockPool(mockCtrl)
+	fakePoolNoRestriction.EXPECT().SupportedTopologies().Return(nil).AnyTimes()
+	fakePools := []storage.Pool{fakePool1, fakePool2, fakePoolBoth, fakePoolNoRestriction}
+
+	filteredPools := FilterPoolsOutsideTopology(context.Background(), fakePools, []map[string]string{zone1})
+	assert.Equal(t, []storage.Pool{fakePool2}, filteredPools, "only the pool in zone 2 should be returned")
+
+	filteredPools = FilterPoolsOutsideTopology(context.Background(), fakePools, []map[string]string{zone1, zone2})
+	assert.Empty(t, filteredPools, "no pools should be returned")
+
+	filteredPools = FilterPoolsOutsideTopology(context.Background(), fakePools,
+		[]map[string]string{{"topology.kubernetes.io/region": "R1"}})
+	assert.Empty(t, filteredPools, "no pools should be returned for an overlapping region")
+
+	filteredPools = FilterPoolsOutsideTopology(context.Background(), fakePools, nil)
+	assert.Len(t, filteredPools, 3, "only pools with topology restrictions should be returned")
+}
+
 func TestFilterPoolsOnNasType(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 
@@ -991,3 +1021,103 @@ func TestGetStoragePoolsForProtocolNegative(t *testing.T) {
 
 	assert.Empty(t, storagePool, "unable to get storage pool")
 }
+
+func TestSortPoolsByUtilization(t *testing.T) {
+	newPool := func(name string, utilization float64, known bool) storage.Pool {
+		pool := storage.NewStoragePool(nil, name)
+		pool.SetUtilization(utilization, known)
+		return pool
+	}
+
+	idle := newPool("idle", 20, true)
+	busy := newPool("busy", 95, true)
+	busier := newPool("busier", 120, true)
+	unknown := newPool("unknown", 0, false)
+	moderate := newPool("moderate", 60, true)
+	pools := []storage.Pool{busier, idle, unknown, busy, moderate}
+
+	// No threshold leaves the pools in their order
+	assert.Equal(t, pools, SortPoolsByUtilization(context.Background(), pools, 0))
+
+	// Pools under the threshold come first in their order, then pools whose utilization is unknown,
+	// then busy pools from least to most utilized
+	expected := []storage.Pool{idle, moderate, unknown, busy, busier}
+	assert.Equal(t, expected, SortPoolsByUtilization(context.Background(), pools, 80))
+}
+
+func TestStorageClassMatches_MissingOffer(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	fakePool, _ := getFakeStoragePool(mockCtrl, "fakepool1", "backend1", "ontap-nas", "sc1", nil)
+
+	newStorageClass := func(name, value string) *StorageClass {
+		req, err := sa.CreateAttributeRequestFromAttributeValue(name, value)
+		if err != nil {
+			t.Fatalf("could not create request %s: %v", value, err)
+		}
+		return New(&Config{Name: "sc1", Attributes: map[string]sa.Request{name: req}})
+	}
+
+	// A pool that doesn't offer an attribute can only satisfy requests that exclude values
+	assert.True(t, newStorageClass(sa.Media, "!= hdd").Matches(ctx, fakePool))
+	assert.True(t, newStorageClass(sa.Zone, "notin (zone1, zone2)").Matches(ctx, fakePool))
+	assert.False(t, newStorageClass(sa.Zone, "in (zone1, zone2)").Matches(ctx, fakePool))
+	assert.False(t, newStorageClass(sa.MaxVolumeSize, ">=100Gi").Matches(ctx, fakePool))
+	assert.True(t, newStorageClass(sa.BackendType, "!= ontap-san").Matches(ctx, fakePool))
+	assert.False(t, newStorageClass(sa.BackendType, "notin (ontap-nas)").Matches(ctx, fakePool))
+}
+
+func TestExplainPlacement(t *testing.T) {
+	ctx := context.TODO()
+	mockCtrl := gomock.NewController(t)
+
+	backend := mockstorage.NewMockBackend(mockCtrl)
+	backend.EXPECT().Name().Return("backend1").AnyTimes()
+	backend.EXPECT().GetProtocol(gomock.Any()).Return(config.File).AnyTimes()
+
+	newPool := func(name string, attributes map[string]sa.Offer, topologies []map[string]string) storage.Pool {
+		pool := storage.NewStoragePool(backend, name)
+		pool.Attributes()[sa.Media] = sa.NewStringOffer(sa.SSD)
+		pool.Attributes()[sa.NASType] = sa.NewStringOffer(sa.NFS)
+		for k, v := range attributes {
+			pool.Attributes()[k] = v
+		}
+		pool.SetSupportedTopologies(topologies)
+		return pool
+	}
+
+	pools := []storage.Pool{
+		newPool("hdd", map[string]sa.Offer{sa.Media: sa.NewStringOffer(sa.HDD)}, nil),
+		newPool("smb", map[string]sa.Offer{sa.NASType: sa.NewStringOffer(sa.SMB)}, nil),
+		newPool("zone2", nil, []map[string]string{{"topology.kubernetes.io/zone": "zone2"}}),
+		newPool("small", map[string]sa.Offer{sa.MaxVolumeSize: sa.NewIntOffer(0, 1024)}, nil),
+		newPool("ok", nil, []map[string]string{{"topology.kubernetes.io/zone": "zone1"}}),
+	}
+
+	sc := New(&Config{
+		Name: "sc1",
+		Attributes: map[string]sa.Request{
+			sa.Media:   sa.NewStringRequest(sa.SSD),
+			sa.NASType: sa.NewStringRequest(sa.NFS),
+		},
+	})
+	volumeConfig := &storage.VolumeConfig{
+		Name:                "vol1",
+		Size:                "1Gi",
+		RequisiteTopologies: []map[string]string{{"topology.kubernetes.io/zone": "zone1"}},
+	}
+
+	placements := sc.ExplainPlacement(ctx, pools, config.File, volumeConfig)
+
+	assert.Equal(t, []PoolPlacement{
+		{Backend: "backend1", Pool: "ok", Accepted: true, Reason: "pool matches storage class"},
+		{Backend: "backend1", Pool: "hdd", Reason: "pool offers media hdd, which does not satisfy ssd"},
+		{
+			Backend: "backend1", Pool: "small",
+			Reason: "requested size of 1073741824 bytes is larger than the pool's maximum volume size",
+		},
+		{Backend: "backend1", Pool: "smb", Reason: "pool offers nasType smb, which does not satisfy nfs"},
+		{Backend: "backend1", Pool: "zone2", Reason: "pool does not support any of the requisite topologies"},
+	}, placements)
+}
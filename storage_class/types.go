@@ -21,6 +21,12 @@ type Config struct {
 	Pools           map[string][]string                 `json:"storagePools,omitempty"`
 	AdditionalPools map[string][]string                 `json:"additionalStoragePools,omitempty"`
 	ExcludePools    map[string][]string                 `json:"excludeStoragePools,omitempty"`
+	// CrossZoneReplica requests a mirrored replica of each volume in a topology zone other than the primary's
+	CrossZoneReplica    bool   `json:"crossZoneReplica,omitempty"`
+	ReplicationPolicy   string `json:"replicationPolicy,omitempty"`
+	ReplicationSchedule string `json:"replicationSchedule,omitempty"`
+	// PoolUtilizationThreshold prefers pools using no more than this percentage of their performance capacity
+	PoolUtilizationThreshold int `json:"poolUtilizationThreshold,omitempty"`
 }
 
 type External struct {
@@ -35,3 +41,11 @@ type External struct {
 type Persistent struct {
 	Config *Config `json:"config"`
 }
+
+// PoolPlacement explains whether a new volume could be placed on a storage pool, and why.
+type PoolPlacement struct {
+	Backend  string `json:"backend"`
+	Pool     string `json:"pool"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason"`
+}
@@ -1076,6 +1076,54 @@ func (c Client) ModifyVolume(
 	return nil
 }
 
+// ModifyVolumeExportPolicy sends a VolumePatch to replace a volume's export rules.
+func (c Client) ModifyVolumeExportPolicy(
+	ctx context.Context, filesystem *FileSystem, exportPolicy *ExportPolicy,
+) error {
+	logFields := log.Fields{
+		"API":    "VolumesClient.BeginUpdate",
+		"volume": filesystem.FullName,
+	}
+
+	patch := netapp.VolumePatch{
+		ID:       &filesystem.ID,
+		Location: &filesystem.Location,
+		Name:     &filesystem.Name,
+		Properties: &netapp.VolumePatchProperties{
+			ExportPolicy: &netapp.VolumePatchPropertiesExportPolicy{
+				Rules: exportPolicyExport(exportPolicy).Rules,
+			},
+		},
+	}
+
+	var rawResponse *http.Response
+	responseCtx := runtime.WithCaptureResponse(ctx, &rawResponse)
+
+	poller, err := c.sdkClient.VolumesClient.BeginUpdate(responseCtx,
+		filesystem.ResourceGroup, filesystem.NetAppAccount, filesystem.CapacityPool, filesystem.Name, patch, nil)
+
+	logFields["correlationID"] = GetCorrelationID(rawResponse)
+
+	if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Error modifying volume export policy.")
+		return err
+	}
+
+	Logc(ctx).WithFields(logFields).Debug("Volume export policy modify request issued.")
+
+	_, err = poller.PollUntilDone(responseCtx, &runtime.PollUntilDoneOptions{Frequency: 2 * time.Second})
+	if err != nil {
+		Logc(ctx).WithFields(logFields).WithError(err).Error("Error polling for volume export policy modify result.")
+		return err
+	}
+
+	filesystem.ExportPolicy = *exportPolicy
+
+	Logc(ctx).WithFields(logFields).Debug("Volume export policy modified.")
+
+	return nil
+}
+
 // ResizeVolume sends a VolumePatch to update a volume's quota.
 func (c Client) ResizeVolume(ctx context.Context, filesystem *FileSystem, newSizeBytes int64) error {
 	logFields := log.Fields{
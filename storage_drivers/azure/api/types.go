@@ -37,6 +37,7 @@ type Azure interface {
 	WaitForVolumeState(context.Context, *FileSystem, string, []string, time.Duration) (string, error)
 	CreateVolume(context.Context, *FilesystemCreateRequest) (*FileSystem, error)
 	ModifyVolume(context.Context, *FileSystem, map[string]string, *string) error
+	ModifyVolumeExportPolicy(context.Context, *FileSystem, *ExportPolicy) error
 	ResizeVolume(context.Context, *FileSystem, int64) error
 	DeleteVolume(context.Context, *FileSystem) error
 
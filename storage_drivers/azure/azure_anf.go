@@ -10,6 +10,7 @@ import (
 	"net"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -53,6 +54,7 @@ const (
 	NetappAccounts  = "netappAccounts"
 	CapacityPools   = "capacityPools"
 	FilePoolVolumes = "filePoolVolumes"
+	LUKSEncryption  = "LUKSEncryption"
 
 	nfsVersion3  = "3"
 	nfsVersion4  = "4"
@@ -561,6 +563,18 @@ func (d *NASStorageDriver) validate(ctx context.Context) error {
 		return err
 	}
 
+	// LUKS encryption requires a block device, which is only available from the subvolume driver
+	luksEncryption := []string{d.Config.LUKSEncryption}
+	for _, vpool := range d.Config.Storage {
+		luksEncryption = append(luksEncryption, vpool.LUKSEncryption)
+	}
+	for _, value := range luksEncryption {
+		if isLUKS, err := strconv.ParseBool(value); err == nil && isLUKS {
+			return fmt.Errorf("LUKS encrypted volumes are only supported by the %s driver",
+				drivers.AzureNASBlockStorageDriverName)
+		}
+	}
+
 	// Validate pool-level attributes
 	for poolName, pool := range d.pools {
 
@@ -773,8 +787,14 @@ func (d *NASStorageDriver) Create(
 			protocolTypes = []string{api.ProtocolTypeNFSv41}
 		}
 
+		// Volumes with publish enforcement are only exported to nodes once they are published
+		allowedClients := pool.InternalAttributes()[ExportRule]
+		if volConfig.AccessInfo.PublishEnforcement {
+			allowedClients = drivers.NoAccessExportRule
+		}
+
 		apiExportRule = api.ExportRule{
-			AllowedClients: pool.InternalAttributes()[ExportRule],
+			AllowedClients: allowedClients,
 			Cifs:           cifsAccess,
 			Nfsv3:          nfsV3Access,
 			Nfsv41:         nfsV41Access,
@@ -795,6 +815,9 @@ func (d *NASStorageDriver) Create(
 		return err
 	}
 	labels[storage.ProvisioningLabelTag] = poolLabels
+	if volConfig.AccessInfo.PublishEnforcement {
+		labels[drivers.PublishEnforcementLabel] = "true"
+	}
 
 	networkFeatures := pool.InternalAttributes()[NetworkFeatures]
 
@@ -1030,6 +1053,15 @@ func (d *NASStorageDriver) CreateClone(
 	if d.Config.NASType == sa.NFS {
 		createRequest.ExportPolicy = sourceVolume.ExportPolicy
 		createRequest.UnixPermissions = sourceVolume.UnixPermissions
+
+		// Clones don't inherit the nodes their source is published to
+		if cloneVolConfig.AccessInfo.PublishEnforcement {
+			createRequest.ExportPolicy = *d.exportPolicyForClients(sourceVolume,
+				[]string{drivers.NoAccessExportRule})
+			labels[drivers.PublishEnforcementLabel] = "true"
+		} else {
+			delete(labels, drivers.PublishEnforcementLabel)
+		}
 	}
 
 	// Clone the volume
@@ -1350,11 +1382,180 @@ func (d *NASStorageDriver) Publish(
 		publishInfo.NfsServerIP = (volume.MountTargets)[0].IPAddress
 		publishInfo.FilesystemType = sa.NFS
 		publishInfo.MountOptions = mountOptions
+
+		// Export the volume to the node if access is limited to the nodes it is published to
+		if volConfig.AccessInfo.PublishEnforcement {
+			if err = d.exportVolumeToNode(ctx, volume, publishInfo); err != nil {
+				return fmt.Errorf("could not export volume %s to node %s; %v", name, publishInfo.HostName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Unpublish the volume from the host specified in publishInfo.  If the volume is only exported to the nodes
+// it is published to, its export rules are limited to the nodes it remains published to.
+func (d *NASStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "NASStorageDriver",
+			"name":   name,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	if !volConfig.AccessInfo.PublishEnforcement || d.Config.NASType == sa.SMB {
+		// Nothing to do if publish enforcement is not enabled
+		return nil
+	}
+
+	// Update resource cache as needed
+	if err := d.SDK.RefreshAzureResources(ctx); err != nil {
+		return fmt.Errorf("could not update ANF resource cache; %v", err)
+	}
+
+	volume, err := d.SDK.Volume(ctx, volConfig)
+	if err != nil {
+		return fmt.Errorf("could not find volume %s; %v", name, err)
+	}
+
+	clients := drivers.GetPublishedNodeExportClients(ctx, publishInfo.Nodes, d.Config.ExportRule)
+	if err = d.setVolumeExportClients(ctx, volume, clients); err != nil {
+		return fmt.Errorf("could not update export rules of volume %s; %v", name, err)
 	}
 
 	return nil
 }
 
+// exportVolumeToNode adds an export rule for the node in publishInfo to a volume's export rules.
+func (d *NASStorageDriver) exportVolumeToNode(
+	ctx context.Context, volume *api.FileSystem, publishInfo *utils.VolumePublishInfo,
+) error {
+	var node *utils.Node
+	for _, n := range publishInfo.Nodes {
+		if n.Name == publishInfo.HostName {
+			node = n
+			break
+		}
+	}
+	if node == nil {
+		return fmt.Errorf("node %s has not registered with Trident", publishInfo.HostName)
+	}
+
+	nodeClients, err := drivers.GetNodeExportClients(ctx, node, d.Config.ExportRule)
+	if err != nil {
+		return err
+	}
+
+	clients := make([]string, 0)
+	for _, client := range volumeExportClients(volume) {
+		if client != drivers.NoAccessExportRule {
+			clients = append(clients, client)
+		}
+	}
+	clients = append(clients, nodeClients)
+
+	return d.setVolumeExportClients(ctx, volume, clients)
+}
+
+// volumeExportClients returns each client allowed by a volume's export rules.
+func volumeExportClients(volume *api.FileSystem) []string {
+	rules := make([]string, 0, len(volume.ExportPolicy.Rules))
+	for _, rule := range volume.ExportPolicy.Rules {
+		rules = append(rules, rule.AllowedClients)
+	}
+	return splitExportClients(rules)
+}
+
+// splitExportClients returns each client in a list of comma-separated allowed clients, without duplicates.
+func splitExportClients(allowedClients []string) []string {
+	clients := make([]string, 0, len(allowedClients))
+	for _, joined := range allowedClients {
+		for _, client := range strings.Split(joined, ",") {
+			client = strings.TrimSpace(client)
+			if client != "" && !utils.SliceContainsString(clients, client) {
+				clients = append(clients, client)
+			}
+		}
+	}
+	return clients
+}
+
+// exportPolicyForClients builds an export policy with a single rule allowing all the given clients.  ANF
+// allows only five rules per volume, so clients aren't given rules of their own.
+func (d *NASStorageDriver) exportPolicyForClients(volume *api.FileSystem, clients []string) *api.ExportPolicy {
+	return &api.ExportPolicy{
+		Rules: []api.ExportRule{
+			{
+				AllowedClients: strings.Join(splitExportClients(clients), ","),
+				Nfsv3:          utils.SliceContainsString(volume.ProtocolTypes, api.ProtocolTypeNFSv3),
+				Nfsv41:         utils.SliceContainsString(volume.ProtocolTypes, api.ProtocolTypeNFSv41),
+				RuleIndex:      1,
+				UnixReadWrite:  true,
+			},
+		},
+	}
+}
+
+// setVolumeExportClients replaces a volume's export rules with one allowing the given clients, unless the
+// volume's rules already allow exactly those clients.
+func (d *NASStorageDriver) setVolumeExportClients(
+	ctx context.Context, volume *api.FileSystem, clients []string,
+) error {
+	current := volumeExportClients(volume)
+	desired := splitExportClients(clients)
+	sort.Strings(current)
+	sort.Strings(desired)
+	if len(volume.ExportPolicy.Rules) == 1 && reflect.DeepEqual(current, desired) {
+		return nil
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":  volume.CreationToken,
+		"clients": clients,
+	}).Debug("Updating volume export rules.")
+
+	return d.SDK.ModifyVolumeExportPolicy(ctx, volume, d.exportPolicyForClients(volume, clients))
+}
+
+// EnablePublishEnforcement limits an existing volume's export rules to the nodes it is published to.  This is
+// only done while the volume is not published anywhere, so the volume is left without access until the
+// next publish.
+func (d *NASStorageDriver) EnablePublishEnforcement(ctx context.Context, volume *storage.Volume) error {
+	// Do not enable publish enforcement on unmanaged imports or SMB volumes
+	if volume.Config.ImportNotManaged || d.Config.NASType == sa.SMB {
+		return nil
+	}
+
+	// Update resource cache as needed
+	if err := d.SDK.RefreshAzureResources(ctx); err != nil {
+		return fmt.Errorf("could not update ANF resource cache; %v", err)
+	}
+
+	filesystem, err := d.SDK.Volume(ctx, volume.Config)
+	if err != nil {
+		return fmt.Errorf("could not find volume %s; %v", volume.Config.InternalName, err)
+	}
+
+	if err = d.setVolumeExportClients(ctx, filesystem, []string{drivers.NoAccessExportRule}); err != nil {
+		return fmt.Errorf("could not remove export rules of volume %s; %v", volume.Config.InternalName, err)
+	}
+	labels := map[string]string{drivers.PublishEnforcementLabel: "true"}
+	if err = d.SDK.ModifyVolume(ctx, filesystem, labels, nil); err != nil {
+		return fmt.Errorf("could not label volume %s; %v", volume.Config.InternalName, err)
+	}
+
+	volume.Config.AccessInfo.PublishEnforcement = true
+	return nil
+}
+
 // CanSnapshot determines whether a snapshot as specified in the provided snapshot config may be taken.
 func (d *NASStorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig, _ *storage.VolumeConfig) error {
 	return nil
@@ -1749,6 +1950,12 @@ func (d *NASStorageDriver) GetStorageBackendSpecs(_ context.Context, backend sto
 // CreatePrepare is called prior to volume creation.  Currently its only role is to create the internal volume name.
 func (d *NASStorageDriver) CreatePrepare(ctx context.Context, volConfig *storage.VolumeConfig) {
 	volConfig.InternalName = d.GetInternalVolumeName(ctx, volConfig.Name)
+
+	// New CSI NFS volumes start with publish enforcement on; imported volumes are limited on first publish
+	if tridentconfig.CurrentDriverContext == tridentconfig.ContextCSI && d.Config.NASType != sa.SMB &&
+		volConfig.ImportOriginalName == "" {
+		volConfig.AccessInfo.PublishEnforcement = true
+	}
 }
 
 // GetStorageBackendPhysicalPoolNames retrieves storage backend physical pools
@@ -1968,9 +2175,9 @@ func (d *NASStorageDriver) GetUpdateType(_ context.Context, driverOrig storage.D
 	return bitmap
 }
 
-// ReconcileNodeAccess updates a per-backend export policy to match the set of Kubernetes cluster
-// nodes.  Not supported by this driver.
-func (d *NASStorageDriver) ReconcileNodeAccess(ctx context.Context, _ []*utils.Node, _ string) error {
+// ReconcileNodeAccess updates the export rules of volumes with publish enforcement to the current addresses
+// of the nodes they are exported to, and removes the rules of nodes that no longer exist.
+func (d *NASStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, _ string) error {
 	if d.Config.DebugTraceFlags["method"] {
 		fields := log.Fields{
 			"Method": "ReconcileNodeAccess",
@@ -1980,6 +2187,64 @@ func (d *NASStorageDriver) ReconcileNodeAccess(ctx context.Context, _ []*utils.N
 		defer Logc(ctx).WithFields(fields).Debug("<<<< ReconcileNodeAccess")
 	}
 
+	if d.Config.NASType == sa.SMB {
+		return nil
+	}
+
+	// Update resource cache as needed
+	if err := d.SDK.RefreshAzureResources(ctx); err != nil {
+		return fmt.Errorf("could not update ANF resource cache; %v", err)
+	}
+
+	volumes, err := d.SDK.Volumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	prefix := *d.Config.StoragePrefix
+	for _, volume := range *volumes {
+		if volume.Labels[drivers.PublishEnforcementLabel] != "true" || !strings.HasPrefix(volume.CreationToken, prefix) {
+			continue
+		}
+
+		clients := drivers.ReconcileNodeExportClients(ctx, volumeExportClients(volume), nodes, d.Config.ExportRule)
+		if err = d.setVolumeExportClients(ctx, volume, clients); err != nil {
+			return fmt.Errorf("could not reconcile export rules of volume %s; %v", volume.CreationToken, err)
+		}
+	}
+
+	return nil
+}
+
+// RevokeNodeAccess removes a node's addresses from the export rules of volumes with publish enforcement.
+func (d *NASStorageDriver) RevokeNodeAccess(ctx context.Context, node *utils.Node, _ string) error {
+	if d.Config.NASType == sa.SMB {
+		return nil
+	}
+
+	// Update resource cache as needed
+	if err := d.SDK.RefreshAzureResources(ctx); err != nil {
+		return fmt.Errorf("could not update ANF resource cache; %v", err)
+	}
+
+	volumes, err := d.SDK.Volumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	prefix := *d.Config.StoragePrefix
+	for _, volume := range *volumes {
+		if volume.Labels[drivers.PublishEnforcementLabel] != "true" || !strings.HasPrefix(volume.CreationToken, prefix) {
+			continue
+		}
+
+		clients := drivers.RemoveNodeExportClients(volumeExportClients(volume), node)
+		if err = d.setVolumeExportClients(ctx, volume, clients); err != nil {
+			return fmt.Errorf("could not remove node %s from export rules of volume %s; %v", node.Name,
+				volume.CreationToken, err)
+		}
+	}
+
 	return nil
 }
 
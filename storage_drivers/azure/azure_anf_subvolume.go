@@ -30,6 +30,7 @@ const (
 	RequiredHashLength        = 16
 
 	defaultSubvolumeSizeStr = "20971520"
+	defaultLUKSEncryption   = "false"
 
 	snapshotNameSeparator = "--"
 	pvcPrefix             = "pvc-"
@@ -335,12 +336,17 @@ func (d *NASBlockStorageDriver) populateConfigurationDefaults(
 	if config.LimitVolumeSize == "" {
 		config.LimitVolumeSize = defaultLimitVolumeSize
 	}
+
+	if config.LUKSEncryption == "" {
+		config.LUKSEncryption = defaultLUKSEncryption
+	}
 	Logc(ctx).WithFields(log.Fields{
 		"StoragePrefix":   *config.StoragePrefix,
 		"Size":            config.Size,
 		"ServiceLevel":    config.ServiceLevel,
 		"NfsMountOptions": config.NfsMountOptions,
 		"LimitVolumeSize": config.LimitVolumeSize,
+		"LUKSEncryption":  config.LUKSEncryption,
 	}).Debugf("Configuration defaults")
 
 	return
@@ -402,6 +408,7 @@ func (d *NASBlockStorageDriver) initializeStoragePools(
 
 			pool.InternalAttributes()[Size] = d.Config.Size
 			pool.InternalAttributes()[FilePoolVolumes] = filePoolVolume.FullName
+			pool.InternalAttributes()[LUKSEncryption] = d.Config.LUKSEncryption
 
 			pool.SetSupportedTopologies(d.Config.SupportedTopologies)
 
@@ -432,6 +439,11 @@ func (d *NASBlockStorageDriver) initializeStoragePools(
 				size = vpool.Size
 			}
 
+			luksEncryption := d.Config.LUKSEncryption
+			if vpool.LUKSEncryption != "" {
+				luksEncryption = vpool.LUKSEncryption
+			}
+
 			supportedTopologies := d.Config.SupportedTopologies
 			if vpool.SupportedTopologies != nil {
 				supportedTopologies = vpool.SupportedTopologies
@@ -473,6 +485,7 @@ func (d *NASBlockStorageDriver) initializeStoragePools(
 			pool.InternalAttributes()[Size] = size
 			// TODO: When supporting multiple filePoolVolumes this will change
 			pool.InternalAttributes()[FilePoolVolumes] = filePoolVolumes[0].FullName
+			pool.InternalAttributes()[LUKSEncryption] = luksEncryption
 
 			pool.SetSupportedTopologies(supportedTopologies)
 
@@ -620,6 +633,11 @@ func (d *NASBlockStorageDriver) validate(ctx context.Context) error {
 		if _, err := utils.ConvertSizeToBytes(pool.InternalAttributes()[Size]); err != nil {
 			return fmt.Errorf("invalid value for default volume size in pool %s: %v", pool.Name(), err)
 		}
+
+		// Validate LUKS encryption
+		if _, err := strconv.ParseBool(pool.InternalAttributes()[LUKSEncryption]); err != nil {
+			return fmt.Errorf("invalid value for LUKSEncryption in pool %s: %v", pool.Name(), err)
+		}
 	}
 
 	return nil
@@ -719,6 +737,9 @@ func (d *NASBlockStorageDriver) Create(
 		"volume":        storagePool.InternalAttributes()[FilePoolVolumes],
 	}).Debug("Creating subvolume.")
 
+	// Record whether the node must layer LUKS on the loop device
+	volConfig.LUKSEncryption = storagePool.InternalAttributes()[LUKSEncryption]
+
 	subvolumeCreateRequest := &api.SubvolumeCreateRequest{
 		CreationToken: creationToken,
 		Volume:        storagePool.InternalAttributes()[FilePoolVolumes],
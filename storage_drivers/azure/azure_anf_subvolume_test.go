@@ -923,6 +923,34 @@ func TestSubvolumeValidate_InvalidVolumeSizeError(t *testing.T) {
 	assert.Error(t, result, "validated configuration")
 }
 
+func TestSubvolumeValidate_InvalidLUKSEncryptionError(t *testing.T) {
+	commonConfig, azureNFSSDPool, filesystems := getStructsForSubvolumeInitializeStoragePools()
+
+	prefix := "test"
+	commonConfig.StoragePrefix = &prefix
+
+	config := &drivers.AzureNASStorageDriverConfig{
+		CommonStorageDriverConfig: commonConfig,
+		AzureNASStorageDriverPool: azureNFSSDPool,
+	}
+
+	mockAPI, driver := newMockANFSubvolumeDriver(t)
+	driver.Config = *config
+
+	tempPool := make(map[string]storage.Pool)
+	pool := storage.NewStoragePool(nil, "test-pool2")
+	pool.InternalAttributes()[Size] = "1Gi"
+	pool.InternalAttributes()[LUKSEncryption] = "maybe"
+	tempPool[pool.Name()] = pool
+	driver.virtualPools = tempPool
+
+	mockAPI.EXPECT().ValidateFilePoolVolumes(ctx, gomock.Any()).Return(filesystems, nil).Times(1)
+	_, _, _ = driver.initializeStoragePools(ctx)
+
+	result := driver.validate(ctx)
+	assert.Error(t, result, "validated configuration")
+}
+
 func getStructsForSubvolumeCreate() (
 	*drivers.AzureNASStorageDriverConfig, []*api.FileSystem, *storage.VolumeConfig,
 	*api.Subvolume, *api.SubvolumeCreateRequest,
@@ -4349,6 +4349,28 @@ func TestCreatePrepare(t *testing.T) {
 	assert.Equal(t, "myPrefix-testvol1", volConfig.InternalName)
 }
 
+func TestCreatePrepare_PublishEnforcement(t *testing.T) {
+	_, driver := newMockANFDriver(t)
+	driver.Config.NASType = "nfs"
+
+	originalContext := tridentconfig.CurrentDriverContext
+	tridentconfig.CurrentDriverContext = tridentconfig.ContextCSI
+	defer func() { tridentconfig.CurrentDriverContext = originalContext }()
+
+	volConfig := &storage.VolumeConfig{Name: "testvol1"}
+	driver.CreatePrepare(ctx, volConfig)
+	assert.True(t, volConfig.AccessInfo.PublishEnforcement, "publish enforcement not enabled")
+
+	importConfig := &storage.VolumeConfig{Name: "testvol2", ImportOriginalName: "original"}
+	driver.CreatePrepare(ctx, importConfig)
+	assert.False(t, importConfig.AccessInfo.PublishEnforcement, "publish enforcement enabled on import")
+
+	driver.Config.NASType = "smb"
+	smbConfig := &storage.VolumeConfig{Name: "testvol3"}
+	driver.CreatePrepare(ctx, smbConfig)
+	assert.False(t, smbConfig.AccessInfo.PublishEnforcement, "publish enforcement enabled on SMB volume")
+}
+
 func TestGetStorageBackendPhysicalPoolNames(t *testing.T) {
 	_, driver := newMockANFDriver(t)
 
@@ -4759,16 +4781,213 @@ func TestGetUpdateType_OtherChanges(t *testing.T) {
 }
 
 func TestReconcileNodeAccess(t *testing.T) {
-	mockCtrl := gomock.NewController(t)
-	mockAPI := mockapi.NewMockAzure(mockCtrl)
+	mockAPI, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+	driver.Config.ExportRule = "0.0.0.0/0"
 
-	driver := *newTestANFDriver(mockAPI)
+	_, enforced, _ := getStructsForPublishNFSVolume(ctx, driver)
+	enforced.Labels[drivers.PublishEnforcementLabel] = "true"
+	enforced.CreationToken = "test-testvol1"
+	enforced.ExportPolicy = api.ExportPolicy{Rules: []api.ExportRule{{AllowedClients: "10.0.0.1,10.0.0.2"}}}
+	_, unenforced, _ := getStructsForPublishNFSVolume(ctx, driver)
+	unenforced.ExportPolicy = api.ExportPolicy{Rules: []api.ExportRule{{AllowedClients: "0.0.0.0/0"}}}
+
+	nodes := []*utils.Node{{Name: "node1", IPs: []string{"10.0.0.1"}}}
+	expectedPolicy := &api.ExportPolicy{Rules: []api.ExportRule{
+		{AllowedClients: "10.0.0.1", Nfsv3: true, RuleIndex: 1, UnixReadWrite: true},
+	}}
+
+	mockAPI.EXPECT().RefreshAzureResources(ctx).Return(nil).Times(1)
+	mockAPI.EXPECT().Volumes(ctx).Return(&[]*api.FileSystem{enforced, unenforced}, nil).Times(1)
+	mockAPI.EXPECT().ModifyVolumeExportPolicy(ctx, enforced, expectedPolicy).Return(nil).Times(1)
+
+	result := driver.ReconcileNodeAccess(ctx, nodes, BackendUUID)
+
+	assert.Nil(t, result, "not nil")
+}
+
+func TestRevokeNodeAccess(t *testing.T) {
+	mockAPI, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+
+	_, enforced, _ := getStructsForPublishNFSVolume(ctx, driver)
+	enforced.Labels[drivers.PublishEnforcementLabel] = "true"
+	enforced.CreationToken = "test-testvol1"
+	enforced.ExportPolicy = api.ExportPolicy{Rules: []api.ExportRule{{AllowedClients: "10.0.0.1,10.0.0.2"}}}
+	_, unenforced, _ := getStructsForPublishNFSVolume(ctx, driver)
+	unenforced.ExportPolicy = api.ExportPolicy{Rules: []api.ExportRule{{AllowedClients: "0.0.0.0/0"}}}
+
+	expectedPolicy := &api.ExportPolicy{Rules: []api.ExportRule{
+		{AllowedClients: "10.0.0.2", Nfsv3: true, RuleIndex: 1, UnixReadWrite: true},
+	}}
+
+	mockAPI.EXPECT().RefreshAzureResources(ctx).Return(nil).Times(1)
+	mockAPI.EXPECT().Volumes(ctx).Return(&[]*api.FileSystem{enforced, unenforced}, nil).Times(1)
+	mockAPI.EXPECT().ModifyVolumeExportPolicy(ctx, enforced, expectedPolicy).Return(nil).Times(1)
+
+	result := driver.RevokeNodeAccess(ctx, &utils.Node{Name: "node1", IPs: []string{"10.0.0.1"}}, "")
+
+	assert.Nil(t, result, "not nil")
+}
+
+func TestReconcileNodeAccess_SMB(t *testing.T) {
+	_, driver := newMockANFDriver(t)
+	driver.Config.NASType = "smb"
 
 	result := driver.ReconcileNodeAccess(ctx, nil, "")
 
 	assert.Nil(t, result, "not nil")
 }
 
+func TestPublish_NFSVolumePublishEnforcement(t *testing.T) {
+	mockAPI, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+	driver.Config.ExportRule = "0.0.0.0/0"
+
+	volConfig, filesystem, publishInfo := getStructsForPublishNFSVolume(ctx, driver)
+	volConfig.AccessInfo.PublishEnforcement = true
+	filesystem.ExportPolicy = api.ExportPolicy{Rules: []api.ExportRule{{AllowedClients: drivers.NoAccessExportRule}}}
+	publishInfo.HostName = "node1"
+	publishInfo.Nodes = []*utils.Node{{Name: "node1", IPs: []string{"10.0.0.1", "fe80::1"}}}
+
+	expectedPolicy := &api.ExportPolicy{Rules: []api.ExportRule{
+		{AllowedClients: "10.0.0.1", Nfsv3: true, RuleIndex: 1, UnixReadWrite: true},
+	}}
+
+	mockAPI.EXPECT().RefreshAzureResources(ctx).Return(nil).Times(1)
+	mockAPI.EXPECT().Volume(ctx, volConfig).Return(filesystem, nil).Times(1)
+	mockAPI.EXPECT().ModifyVolumeExportPolicy(ctx, filesystem, expectedPolicy).Return(nil).Times(1)
+
+	result := driver.Publish(ctx, volConfig, publishInfo)
+
+	assert.Nil(t, result, "not nil")
+}
+
+func TestPublish_NFSVolumePublishEnforcementSecondNode(t *testing.T) {
+	mockAPI, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+	driver.Config.ExportRule = "0.0.0.0/0"
+
+	volConfig, filesystem, publishInfo := getStructsForPublishNFSVolume(ctx, driver)
+	volConfig.AccessInfo.PublishEnforcement = true
+	filesystem.ExportPolicy = api.ExportPolicy{Rules: []api.ExportRule{{AllowedClients: "10.0.0.1"}}}
+	publishInfo.HostName = "node2"
+	publishInfo.Nodes = []*utils.Node{
+		{Name: "node1", IPs: []string{"10.0.0.1"}},
+		{Name: "node2", IPs: []string{"10.0.0.2"}},
+	}
+
+	// All nodes share one rule, since ANF allows only a few rules per volume
+	expectedPolicy := &api.ExportPolicy{Rules: []api.ExportRule{
+		{AllowedClients: "10.0.0.1,10.0.0.2", Nfsv3: true, RuleIndex: 1, UnixReadWrite: true},
+	}}
+
+	mockAPI.EXPECT().RefreshAzureResources(ctx).Return(nil).Times(1)
+	mockAPI.EXPECT().Volume(ctx, volConfig).Return(filesystem, nil).Times(1)
+	mockAPI.EXPECT().ModifyVolumeExportPolicy(ctx, filesystem, expectedPolicy).Return(nil).Times(1)
+
+	result := driver.Publish(ctx, volConfig, publishInfo)
+
+	assert.Nil(t, result, "not nil")
+}
+
+func TestPublish_NFSVolumePublishEnforcementUnknownNode(t *testing.T) {
+	mockAPI, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+
+	volConfig, filesystem, publishInfo := getStructsForPublishNFSVolume(ctx, driver)
+	volConfig.AccessInfo.PublishEnforcement = true
+	publishInfo.HostName = "node1"
+
+	mockAPI.EXPECT().RefreshAzureResources(ctx).Return(nil).Times(1)
+	mockAPI.EXPECT().Volume(ctx, volConfig).Return(filesystem, nil).Times(1)
+	mockAPI.EXPECT().ModifyVolumeExportPolicy(ctx, gomock.Any(), gomock.Any()).Times(0)
+
+	result := driver.Publish(ctx, volConfig, publishInfo)
+
+	assert.NotNil(t, result, "expected error")
+}
+
+func TestUnpublish_NFSVolume(t *testing.T) {
+	mockAPI, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+
+	volConfig, filesystem, publishInfo := getStructsForPublishNFSVolume(ctx, driver)
+	volConfig.AccessInfo.PublishEnforcement = true
+	filesystem.ExportPolicy = api.ExportPolicy{Rules: []api.ExportRule{{AllowedClients: "10.0.0.1"}}}
+	publishInfo.HostName = "node1"
+
+	expectedPolicy := &api.ExportPolicy{Rules: []api.ExportRule{
+		{AllowedClients: drivers.NoAccessExportRule, Nfsv3: true, RuleIndex: 1, UnixReadWrite: true},
+	}}
+
+	mockAPI.EXPECT().RefreshAzureResources(ctx).Return(nil).Times(1)
+	mockAPI.EXPECT().Volume(ctx, volConfig).Return(filesystem, nil).Times(1)
+	mockAPI.EXPECT().ModifyVolumeExportPolicy(ctx, filesystem, expectedPolicy).Return(nil).Times(1)
+
+	result := driver.Unpublish(ctx, volConfig, publishInfo)
+
+	assert.Nil(t, result, "not nil")
+}
+
+func TestUnpublish_NoPublishEnforcement(t *testing.T) {
+	_, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+
+	volConfig, _, publishInfo := getStructsForPublishNFSVolume(ctx, driver)
+
+	result := driver.Unpublish(ctx, volConfig, publishInfo)
+
+	assert.Nil(t, result, "not nil")
+}
+
+func TestEnablePublishEnforcement(t *testing.T) {
+	mockAPI, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+
+	volConfig, filesystem, _ := getStructsForPublishNFSVolume(ctx, driver)
+	filesystem.ExportPolicy = api.ExportPolicy{Rules: []api.ExportRule{{AllowedClients: "0.0.0.0/0"}}}
+	volume := &storage.Volume{Config: volConfig}
+
+	expectedPolicy := &api.ExportPolicy{Rules: []api.ExportRule{
+		{AllowedClients: drivers.NoAccessExportRule, Nfsv3: true, RuleIndex: 1, UnixReadWrite: true},
+	}}
+	expectedLabels := map[string]string{drivers.PublishEnforcementLabel: "true"}
+
+	mockAPI.EXPECT().RefreshAzureResources(ctx).Return(nil).Times(1)
+	mockAPI.EXPECT().Volume(ctx, volConfig).Return(filesystem, nil).Times(1)
+	mockAPI.EXPECT().ModifyVolumeExportPolicy(ctx, filesystem, expectedPolicy).Return(nil).Times(1)
+	mockAPI.EXPECT().ModifyVolume(ctx, filesystem, expectedLabels, nil).Return(nil).Times(1)
+
+	result := driver.EnablePublishEnforcement(ctx, volume)
+
+	assert.Nil(t, result, "not nil")
+	assert.True(t, volume.Config.AccessInfo.PublishEnforcement, "publish enforcement not enabled")
+}
+
+func TestEnablePublishEnforcement_ImportNotManaged(t *testing.T) {
+	_, driver := newMockANFDriver(t)
+	driver.initializeTelemetry(ctx, BackendUUID)
+	driver.Config.NASType = "nfs"
+
+	volConfig, _, _ := getStructsForPublishNFSVolume(ctx, driver)
+	volConfig.ImportNotManaged = true
+	volume := &storage.Volume{Config: volConfig}
+
+	result := driver.EnablePublishEnforcement(ctx, volume)
+
+	assert.Nil(t, result, "not nil")
+	assert.False(t, volume.Config.AccessInfo.PublishEnforcement, "publish enforcement enabled")
+}
+
 func TestValidateStoragePrefix(t *testing.T) {
 	tests := []struct {
 		Name          string
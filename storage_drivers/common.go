@@ -9,14 +9,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	trident "github.com/netapp/trident/config"
 	. "github.com/netapp/trident/logger"
+	sa "github.com/netapp/trident/storage_attribute"
 	"github.com/netapp/trident/utils"
 )
 
@@ -74,6 +77,20 @@ func ValidateCommonSettings(ctx context.Context, configJSON string) (*CommonStor
 		}
 	}
 
+	// Validate storage API limits (if set)
+	if config.MaxConcurrentRequests < 0 {
+		return nil, fmt.Errorf("invalid value for maxConcurrentRequests: %d", config.MaxConcurrentRequests)
+	}
+	if config.MaxRequestsPerSecond < 0 {
+		return nil, fmt.Errorf("invalid value for maxRequestsPerSecond: %v", config.MaxRequestsPerSecond)
+	}
+	if config.CircuitBreakerProbeInterval != "" {
+		if interval, err := time.ParseDuration(config.CircuitBreakerProbeInterval); err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid value for circuitBreakerProbeInterval: %v",
+				config.CircuitBreakerProbeInterval)
+		}
+	}
+
 	if config.Credentials != nil {
 		Logc(ctx).Debug("Credentials field not empty.")
 
@@ -205,6 +222,20 @@ func CheckVolumeSizeLimits(
 	return true, volumeSizeLimit, nil
 }
 
+// NewMaxVolumeSizeOffer returns a storage pool offer of volume sizes up to the smaller of the backend's
+// limitVolumeSize, if set, and the largest volume the storage system can create.
+func NewMaxVolumeSizeOffer(config *CommonStorageDriverConfig, systemLimit uint64) sa.Offer {
+	maxVolumeSize := systemLimit
+	if config.LimitVolumeSize != "" {
+		if limitStr, err := utils.ConvertSizeToBytes(config.LimitVolumeSize); err == nil {
+			if limit, err := strconv.ParseUint(limitStr, 10, 64); err == nil && limit < maxVolumeSize {
+				maxVolumeSize = limit
+			}
+		}
+	}
+	return sa.NewIntOffer(0, int(maxVolumeSize))
+}
+
 // CheckMinVolumeSize returns UnsupportedCapacityRangeError if the requested volume size is less than the minimum
 // volume size
 func CheckMinVolumeSize(requestedSizeBytes, minVolumeSizeBytes uint64) error {
@@ -276,3 +307,112 @@ func ensureJoinedStringContainsElem(joined, elem, sep string) string {
 	}
 	return joined + sep + elem
 }
+
+const (
+	// PublishEnforcementLabel marks cloud volumes whose export rules are limited to the nodes they are
+	// published to.
+	PublishEnforcementLabel = "trident-publish-enforcement"
+
+	// NoAccessExportRule is the allowed clients of an export rule that no remote client matches.  Cloud volumes
+	// must keep at least one export rule, so it stands in for an empty set of rules.
+	NoAccessExportRule = "127.0.0.1"
+)
+
+// GetNodeExportClients returns the addresses of a node that are allowed by a backend's export rule, joined for
+// use as the allowed clients of an export rule for just that node.  Plain addresses in the backend's export
+// rule are treated as single hosts.
+func GetNodeExportClients(ctx context.Context, node *utils.Node, exportRule string) (string, error) {
+	cidrs := make([]string, 0)
+	for _, rule := range strings.Split(exportRule, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if !strings.Contains(rule, "/") {
+			if ip := net.ParseIP(rule); ip != nil && ip.To4() == nil {
+				rule += "/128"
+			} else {
+				rule += "/32"
+			}
+		}
+		cidrs = append(cidrs, rule)
+	}
+
+	ips, err := utils.FilterIPs(ctx, node.IPs, cidrs)
+	if err != nil {
+		return "", err
+	}
+	if len(ips) == 0 {
+		return "", fmt.Errorf("node %s has no addresses allowed by export rule %s", node.Name, exportRule)
+	}
+	return strings.Join(ips, ","), nil
+}
+
+// GetPublishedNodeExportClients returns the allowed clients of the export rules that limit a volume to a set
+// of nodes, one per node.  Nodes without allowed addresses are skipped, and if no nodes remain, the no-access
+// rule is returned.
+func GetPublishedNodeExportClients(ctx context.Context, nodes []*utils.Node, exportRule string) []string {
+	clients := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		nodeClients, err := GetNodeExportClients(ctx, node, exportRule)
+		if err != nil {
+			Logc(ctx).WithField("node", node.Name).WithError(err).Warning("Node will not be granted access.")
+			continue
+		}
+		clients = append(clients, nodeClients)
+	}
+	if len(clients) == 0 {
+		clients = append(clients, NoAccessExportRule)
+	}
+	return clients
+}
+
+// ReconcileNodeExportClients updates the allowed clients of a volume's export rules to the current addresses of
+// the nodes they were made for, one entry per node.  Addresses of nodes that no longer exist are dropped, and if
+// no nodes remain, the no-access rule is returned.
+func ReconcileNodeExportClients(
+	ctx context.Context, clients []string, nodes []*utils.Node, exportRule string,
+) []string {
+	nodeClients := make(map[string]string)
+	for _, node := range nodes {
+		if nc, err := GetNodeExportClients(ctx, node, exportRule); err == nil {
+			for _, ip := range node.IPs {
+				nodeClients[ip] = nc
+			}
+		}
+	}
+
+	reconciled := make([]string, 0, len(clients))
+	for _, rule := range clients {
+		for _, ip := range strings.Split(rule, ",") {
+			if nc, ok := nodeClients[strings.TrimSpace(ip)]; ok && !utils.SliceContainsString(reconciled, nc) {
+				reconciled = append(reconciled, nc)
+			}
+		}
+	}
+	if len(reconciled) == 0 {
+		reconciled = append(reconciled, NoAccessExportRule)
+	}
+	return reconciled
+}
+
+// RemoveNodeExportClients removes a node's addresses from the allowed clients of a volume's export rules.  Rules
+// left without clients are dropped, and if no rules remain, the no-access rule is returned.
+func RemoveNodeExportClients(clients []string, node *utils.Node) []string {
+	remaining := make([]string, 0, len(clients))
+	for _, rule := range clients {
+		ruleClients := make([]string, 0)
+		for _, ip := range strings.Split(rule, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" && !utils.SliceContainsString(node.IPs, ip) {
+				ruleClients = append(ruleClients, ip)
+			}
+		}
+		if len(ruleClients) > 0 {
+			remaining = append(remaining, strings.Join(ruleClients, ","))
+		}
+	}
+	if len(remaining) == 0 {
+		remaining = append(remaining, NoAccessExportRule)
+	}
+	return remaining
+}
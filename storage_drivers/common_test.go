@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/netapp/trident/config"
+	sa "github.com/netapp/trident/storage_attribute"
 	"github.com/netapp/trident/utils"
 )
 
@@ -107,6 +108,39 @@ func TestValidateCommonSettings(t *testing.T) {
 				errorExpected: true,
 			},
 		},
+		"fails when maxConcurrentRequests is negative": {
+			configJSON: `{
+				"version": 1,
+				"storageDriverName": "ontap-nas",
+				"maxConcurrentRequests": -1
+			}`,
+			output: output{
+				config:        nil,
+				errorExpected: true,
+			},
+		},
+		"fails when maxRequestsPerSecond is negative": {
+			configJSON: `{
+				"version": 1,
+				"storageDriverName": "ontap-nas",
+				"maxRequestsPerSecond": -0.5
+			}`,
+			output: output{
+				config:        nil,
+				errorExpected: true,
+			},
+		},
+		"fails when circuitBreakerProbeInterval is invalid": {
+			configJSON: `{
+				"version": 1,
+				"storageDriverName": "ontap-nas",
+				"circuitBreakerProbeInterval": "soon"
+			}`,
+			output: output{
+				config:        nil,
+				errorExpected: true,
+			},
+		},
 		"fails when invalid credentials are specified": {
 			configJSON: `{
 				"version": 1,
@@ -574,3 +608,68 @@ func TestEnsureJoinedStringContainsElem(t *testing.T) {
 		})
 	}
 }
+
+func TestNewMaxVolumeSizeOffer(t *testing.T) {
+	config := &CommonStorageDriverConfig{}
+	assert.Equal(t, sa.NewIntOffer(0, 1000000000000), NewMaxVolumeSizeOffer(config, 1000000000000))
+
+	config.LimitVolumeSize = "1Gi"
+	assert.Equal(t, sa.NewIntOffer(0, 1073741824), NewMaxVolumeSizeOffer(config, 1000000000000))
+
+	// The storage system's own limit wins if it is smaller
+	assert.Equal(t, sa.NewIntOffer(0, 1000000), NewMaxVolumeSizeOffer(config, 1000000))
+}
+
+func TestGetNodeExportClients(t *testing.T) {
+	node := &utils.Node{Name: "node1", IPs: []string{"10.0.0.5", "172.17.0.1", "192.168.1.7"}}
+
+	clients, err := GetNodeExportClients(context.Background(), node, "10.0.0.0/24,192.168.1.7")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.5,192.168.1.7", clients)
+
+	_, err = GetNodeExportClients(context.Background(), node, "10.1.0.0/16")
+	assert.Error(t, err, "a node without allowed addresses can't be granted access")
+}
+
+func TestGetPublishedNodeExportClients(t *testing.T) {
+	nodes := []*utils.Node{
+		{Name: "node1", IPs: []string{"10.0.0.5"}},
+		{Name: "node2", IPs: []string{"10.1.0.5"}},
+		{Name: "node3", IPs: []string{"10.0.0.6", "10.0.0.7"}},
+	}
+
+	clients := GetPublishedNodeExportClients(context.Background(), nodes, "10.0.0.0/24")
+	assert.Equal(t, []string{"10.0.0.5", "10.0.0.6,10.0.0.7"}, clients)
+
+	clients = GetPublishedNodeExportClients(context.Background(), nil, "10.0.0.0/24")
+	assert.Equal(t, []string{NoAccessExportRule}, clients)
+}
+
+func TestReconcileNodeExportClients(t *testing.T) {
+	nodes := []*utils.Node{
+		{Name: "node1", IPs: []string{"10.0.0.5", "10.0.0.8"}},
+		{Name: "node2", IPs: []string{"10.0.0.6"}},
+	}
+
+	// Rules follow their node's current addresses, and rules of missing nodes are dropped
+	clients := ReconcileNodeExportClients(context.Background(),
+		[]string{"10.0.0.5", "10.0.0.9", "10.0.0.6"}, nodes, "0.0.0.0/0")
+	assert.Equal(t, []string{"10.0.0.5,10.0.0.8", "10.0.0.6"}, clients)
+
+	// Clients of several nodes may share a rule
+	clients = ReconcileNodeExportClients(context.Background(), []string{"10.0.0.6, 10.0.0.5"}, nodes, "0.0.0.0/0")
+	assert.Equal(t, []string{"10.0.0.6", "10.0.0.5,10.0.0.8"}, clients)
+
+	clients = ReconcileNodeExportClients(context.Background(), []string{NoAccessExportRule}, nodes, "0.0.0.0/0")
+	assert.Equal(t, []string{NoAccessExportRule}, clients)
+}
+
+func TestRemoveNodeExportClients(t *testing.T) {
+	node := &utils.Node{Name: "node1", IPs: []string{"10.0.0.5", "10.0.0.8"}}
+
+	clients := RemoveNodeExportClients([]string{"10.0.0.5,10.0.0.8", "10.0.0.6,10.0.0.5"}, node)
+	assert.Equal(t, []string{"10.0.0.6"}, clients)
+
+	clients = RemoveNodeExportClients([]string{"10.0.0.5, 10.0.0.8"}, node)
+	assert.Equal(t, []string{NoAccessExportRule}, clients)
+}
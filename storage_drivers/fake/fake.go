@@ -13,6 +13,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/RoaringBitmap/roaring"
@@ -48,6 +49,9 @@ type StorageDriver struct {
 	initialized bool
 	Config      drivers.FakeStorageDriverConfig
 
+	// mutex serializes access to the fake volumes and snapshots, as a real storage system would
+	mutex *sync.Mutex
+
 	// Volumes saves info about Volumes created on this driver
 	Volumes map[string]fake.Volume
 
@@ -117,6 +121,7 @@ func NewFakeStorageDriver(ctx context.Context, config drivers.FakeStorageDriverC
 		Snapshots:          make(map[string]map[string]*storage.Snapshot),
 		DestroyedSnapshots: make(map[string]bool),
 		Secret:             "secret",
+		mutex:              &sync.Mutex{},
 	}
 	_ = driver.populateConfigurationDefaults(ctx, &config)
 	_ = driver.initializeStoragePools()
@@ -149,6 +154,7 @@ func NewFakeStorageDriverWithPools(
 		Snapshots:          make(map[string]map[string]*storage.Snapshot),
 		DestroyedSnapshots: make(map[string]bool),
 		Secret:             "fake-secret",
+		mutex:              &sync.Mutex{},
 	}
 
 	err := driver.initializeStoragePools()
@@ -178,6 +184,7 @@ func NewFakeStorageDriverWithDebugTraceFlags(debugTraceFlags map[string]bool) *S
 		Snapshots:          make(map[string]map[string]*storage.Snapshot),
 		DestroyedSnapshots: make(map[string]bool),
 		Secret:             "fake-secret",
+		mutex:              &sync.Mutex{},
 	}
 
 	return driver
@@ -303,6 +310,7 @@ func (d *StorageDriver) Initialize(
 		return fmt.Errorf("could not configure storage pools: %v", err)
 	}
 
+	d.mutex = &sync.Mutex{}
 	d.Volumes = make(map[string]fake.Volume)
 	d.CreatingVolumes = d.generateCreatingVolumes()
 	d.DestroyedVolumes = make(map[string]bool)
@@ -562,6 +570,9 @@ func (d *StorageDriver) validate(ctx context.Context) error {
 func (d *StorageDriver) Create(
 	ctx context.Context, volConfig *storage.VolumeConfig, storagePool storage.Pool, volAttributes map[string]sa.Request,
 ) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	name := volConfig.InternalName
 	if _, ok := d.Volumes[name]; ok {
 		return drivers.NewVolumeExistsError(name)
@@ -731,6 +742,9 @@ func (d *StorageDriver) BootstrapVolume(ctx context.Context, volume *storage.Vol
 func (d *StorageDriver) CreateClone(
 	ctx context.Context, _, cloneVolConfig *storage.VolumeConfig, _ storage.Pool,
 ) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	name := cloneVolConfig.InternalName
 	source := cloneVolConfig.CloneSourceVolumeInternal
 	snapshot := cloneVolConfig.CloneSourceSnapshot
@@ -788,6 +802,9 @@ func (d *StorageDriver) CreateClone(
 }
 
 func (d *StorageDriver) Import(ctx context.Context, volConfig *storage.VolumeConfig, originalName string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	Logc(ctx).WithFields(log.Fields{
 		"volumeConfig": volConfig,
 		"originalName": originalName,
@@ -809,6 +826,9 @@ func (d *StorageDriver) Import(ctx context.Context, volConfig *storage.VolumeCon
 }
 
 func (d *StorageDriver) Rename(ctx context.Context, name, newName string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	Logc(ctx).WithFields(log.Fields{
 		"name":    name,
 		"newName": newName,
@@ -826,6 +846,9 @@ func (d *StorageDriver) Rename(ctx context.Context, name, newName string) error
 }
 
 func (d *StorageDriver) Destroy(ctx context.Context, volConfig *storage.VolumeConfig) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	name := volConfig.InternalName
 
 	d.DestroyedVolumes[name] = true
@@ -870,6 +893,9 @@ func (d *StorageDriver) CanSnapshot(_ context.Context, _ *storage.SnapshotConfig
 func (d *StorageDriver) GetSnapshot(
 	_ context.Context, snapConfig *storage.SnapshotConfig, _ *storage.VolumeConfig,
 ) (*storage.Snapshot, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
 
@@ -893,6 +919,9 @@ func (d *StorageDriver) GetSnapshot(
 func (d *StorageDriver) GetSnapshots(_ context.Context, volConfig *storage.VolumeConfig) (
 	[]*storage.Snapshot, error,
 ) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalVolName := volConfig.InternalName
 
 	snapshots := make([]*storage.Snapshot, 0)
@@ -914,6 +943,9 @@ func (d *StorageDriver) GetSnapshots(_ context.Context, volConfig *storage.Volum
 func (d *StorageDriver) CreateSnapshot(
 	ctx context.Context, snapConfig *storage.SnapshotConfig, _ *storage.VolumeConfig,
 ) (*storage.Snapshot, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
 
@@ -990,6 +1022,9 @@ func (d *StorageDriver) BootstrapSnapshot(
 func (d *StorageDriver) RestoreSnapshot(
 	_ context.Context, snapConfig *storage.SnapshotConfig, _ *storage.VolumeConfig,
 ) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
 
@@ -1013,6 +1048,9 @@ func (d *StorageDriver) RestoreSnapshot(
 func (d *StorageDriver) DeleteSnapshot(
 	_ context.Context, snapConfig *storage.SnapshotConfig, _ *storage.VolumeConfig,
 ) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	internalSnapName := snapConfig.InternalName
 	internalVolName := snapConfig.VolumeInternalName
 
@@ -1029,6 +1067,9 @@ func (d *StorageDriver) DeleteSnapshot(
 }
 
 func (d *StorageDriver) Get(_ context.Context, name string) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	_, ok := d.Volumes[name]
 	if !ok {
 		return fmt.Errorf("could not find volume %s", name)
@@ -1039,6 +1080,9 @@ func (d *StorageDriver) Get(_ context.Context, name string) error {
 
 // Resize expands the volume size.
 func (d *StorageDriver) Resize(_ context.Context, volConfig *storage.VolumeConfig, sizeBytes uint64) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	name := volConfig.InternalName
 	vol := d.Volumes[name]
 
@@ -1165,6 +1209,9 @@ func (d *StorageDriver) GetExternalConfig(context.Context) interface{} {
 }
 
 func (d *StorageDriver) GetVolumeExternal(_ context.Context, name string) (*storage.VolumeExternal, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	volume, ok := d.Volumes[name]
 	if !ok {
 		return nil, fmt.Errorf("fake volume %s not found", name)
@@ -1177,8 +1224,15 @@ func (d *StorageDriver) GetVolumeExternalWrappers(_ context.Context, channel cha
 	// Let the caller know we're done by closing the channel
 	defer close(channel)
 
-	// Convert all volumes to VolumeExternal and write them to the channel
+	d.mutex.Lock()
+	volumes := make([]fake.Volume, 0, len(d.Volumes))
 	for _, volume := range d.Volumes {
+		volumes = append(volumes, volume)
+	}
+	d.mutex.Unlock()
+
+	// Convert all volumes to VolumeExternal and write them to the channel
+	for _, volume := range volumes {
 		channel <- &storage.VolumeExternalWrapper{Volume: d.getVolumeExternal(volume), Error: nil}
 	}
 }
@@ -1235,7 +1289,10 @@ func (d *StorageDriver) GetUpdateType(_ context.Context, driverOrig storage.Driv
 }
 
 // CopyVolumes copies Volumes into this instance; there is no "storage system of truth" to use
-func (d StorageDriver) CopyVolumes(volumes map[string]fake.Volume) {
+func (d *StorageDriver) CopyVolumes(volumes map[string]fake.Volume) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
 	for name, vol := range volumes {
 		d.Volumes[name] = vol
 	}
@@ -1295,6 +1352,11 @@ func (d StorageDriver) generateCreatingVolumes() map[string]fake.CreatingVolume
 	return creatingVolumes
 }
 
+// ProbeHealth always succeeds, as the fake storage system is always responding
+func (d *StorageDriver) ProbeHealth(context.Context) error {
+	return nil
+}
+
 func (d *StorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, _ string) error {
 	nodeNames := make([]string, 0)
 	for _, node := range nodes {
@@ -646,6 +646,49 @@ func (d *Client) ChangeVolumeUnixPermissions(ctx context.Context, volume *Volume
 	return volume, nil
 }
 
+func (d *Client) ChangeVolumeExportPolicy(ctx context.Context, volume *Volume, exportPolicy ExportPolicy) (*Volume,
+	error,
+) {
+	resourcePath := fmt.Sprintf("/Volumes/%s", volume.VolumeID)
+
+	request := &VolumeChangeExportPolicyRequest{
+		Region:        volume.Region,
+		CreationToken: volume.CreationToken,
+		ProtocolTypes: volume.ProtocolTypes,
+		ExportPolicy:  exportPolicy,
+		QuotaInBytes:  volume.QuotaInBytes,
+	}
+
+	jsonRequest, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal JSON request: %v; %v", request, err)
+	}
+
+	response, responseBody, err := d.InvokeAPI(ctx, jsonRequest, "PUT", d.makeURL(resourcePath))
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.getErrorFromAPIResponse(response, responseBody)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(responseBody, volume)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse volume data: %s; %v", string(responseBody), err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"name":          volume.Name,
+		"exportPolicy":  exportPolicy,
+		"creationToken": request.CreationToken,
+		"statusCode":    response.StatusCode,
+	}).Debug("Volume export policy changed.")
+
+	return volume, nil
+}
+
 func (d *Client) RelabelVolume(ctx context.Context, volume *Volume, labels []string) (*Volume, error) {
 	resourcePath := fmt.Sprintf("/Volumes/%s", volume.VolumeID)
 
@@ -137,6 +137,14 @@ type VolumeChangeUnixPermissionsRequest struct {
 	ServiceLevel    string `json:"serviceLevel"`
 }
 
+type VolumeChangeExportPolicyRequest struct {
+	Region        string       `json:"region"`
+	CreationToken string       `json:"creationToken"`
+	ProtocolTypes []string     `json:"protocolTypes"`
+	ExportPolicy  ExportPolicy `json:"exportPolicy"`
+	QuotaInBytes  int64        `json:"quotaInBytes"`
+}
+
 type VolumeResizeRequest struct {
 	Region        string   `json:"region"`
 	CreationToken string   `json:"creationToken"`
@@ -31,6 +31,7 @@ type GCPClient interface {
 	CreateVolume(ctx context.Context, request *VolumeCreateRequest) error
 	RenameVolume(ctx context.Context, volume *Volume, newName string) (*Volume, error)
 	ChangeVolumeUnixPermissions(ctx context.Context, volume *Volume, newUnixPermissions string) (*Volume, error)
+	ChangeVolumeExportPolicy(ctx context.Context, volume *Volume, exportPolicy ExportPolicy) (*Volume, error)
 	RelabelVolume(ctx context.Context, volume *Volume, labels []string) (*Volume, error)
 	RenameRelabelVolume(
 		ctx context.Context, volume *Volume, newName string, labels []string,
@@ -809,16 +809,13 @@ func (d *NFSStorageDriver) Create(
 		"zone":             zone,
 	}).Debug("Creating volume.")
 
-	apiExportRule := api.ExportRule{
-		AllowedClients: pool.InternalAttributes()[ExportRule],
-		Access:         api.AccessReadWrite,
-		NFSv3:          api.Checked{Checked: true},
-		NFSv4:          api.Checked{Checked: true},
+	// Volumes with publish enforcement are only exported to nodes once they are published
+	allowedClients := pool.InternalAttributes()[ExportRule]
+	if volConfig.AccessInfo.PublishEnforcement {
+		allowedClients = drivers.NoAccessExportRule
 	}
 
-	exportPolicy := api.ExportPolicy{
-		Rules: []api.ExportRule{apiExportRule},
-	}
+	exportPolicy := d.exportPolicyForClients([]string{allowedClients})
 
 	labels := []string{d.getTelemetryLabels(ctx)}
 	poolLabels, err := pool.GetLabelsJSON(ctx, storage.ProvisioningLabelTag, api.MaxLabelLength)
@@ -828,6 +825,9 @@ func (d *NFSStorageDriver) Create(
 	if poolLabels != "" {
 		labels = append(labels, poolLabels)
 	}
+	if volConfig.AccessInfo.PublishEnforcement {
+		labels = append(labels, drivers.PublishEnforcementLabel)
+	}
 
 	snapshotPolicy := api.SnapshotPolicy{
 		Enabled: false,
@@ -1123,12 +1123,20 @@ func (d *NFSStorageDriver) CreateClone(
 		labels = storage.UpdateProvisioningLabels(poolLabels, labels)
 	}
 
+	// Clones don't inherit the nodes their source is published to
+	exportPolicy := sourceVolume.ExportPolicy
+	labels = utils.RemoveStringFromSlice(labels, drivers.PublishEnforcementLabel)
+	if cloneVolConfig.AccessInfo.PublishEnforcement {
+		exportPolicy = d.exportPolicyForClients([]string{drivers.NoAccessExportRule})
+		labels = append(labels, drivers.PublishEnforcementLabel)
+	}
+
 	createRequest := &api.VolumeCreateRequest{
 		Name:              cloneVolConfig.Name,
 		Region:            sourceVolume.Region,
 		Zone:              sourceVolume.Zone,
 		CreationToken:     name,
-		ExportPolicy:      sourceVolume.ExportPolicy,
+		ExportPolicy:      exportPolicy,
 		Labels:            labels,
 		ProtocolTypes:     sourceVolume.ProtocolTypes,
 		QuotaInBytes:      sourceVolume.QuotaInBytes,
@@ -1438,6 +1446,143 @@ func (d *NFSStorageDriver) Publish(
 	publishInfo.FilesystemType = "nfs"
 	publishInfo.MountOptions = mountOptions
 
+	// Export the volume to the node if access is limited to the nodes it is published to
+	if volConfig.AccessInfo.PublishEnforcement {
+		if err = d.exportVolumeToNode(ctx, volume, publishInfo); err != nil {
+			return fmt.Errorf("could not export volume %s to node %s; %v", name, publishInfo.HostName, err)
+		}
+	}
+
+	return nil
+}
+
+// Unpublish the volume from the host specified in publishInfo.  If the volume is only exported to the nodes
+// it is published to, its export rules are limited to the nodes it remains published to.
+func (d *NFSStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "NFSStorageDriver",
+			"name":   name,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	if !volConfig.AccessInfo.PublishEnforcement {
+		// Nothing to do if publish enforcement is not enabled
+		return nil
+	}
+
+	volume, err := d.API.GetVolumeByCreationToken(ctx, name)
+	if err != nil {
+		return fmt.Errorf("could not find volume %s: %v", name, err)
+	}
+
+	clients := drivers.GetPublishedNodeExportClients(ctx, publishInfo.Nodes, d.Config.ExportRule)
+	if err = d.setVolumeExportClients(ctx, volume, clients); err != nil {
+		return fmt.Errorf("could not update export rules of volume %s; %v", name, err)
+	}
+
+	return nil
+}
+
+// exportVolumeToNode adds an export rule for the node in publishInfo to a volume's export rules.
+func (d *NFSStorageDriver) exportVolumeToNode(
+	ctx context.Context, volume *api.Volume, publishInfo *utils.VolumePublishInfo,
+) error {
+	var node *utils.Node
+	for _, n := range publishInfo.Nodes {
+		if n.Name == publishInfo.HostName {
+			node = n
+			break
+		}
+	}
+	if node == nil {
+		return fmt.Errorf("node %s has not registered with Trident", publishInfo.HostName)
+	}
+
+	nodeClients, err := drivers.GetNodeExportClients(ctx, node, d.Config.ExportRule)
+	if err != nil {
+		return err
+	}
+
+	clients := make([]string, 0, len(volume.ExportPolicy.Rules)+1)
+	for _, rule := range volume.ExportPolicy.Rules {
+		if rule.AllowedClients != drivers.NoAccessExportRule {
+			clients = append(clients, rule.AllowedClients)
+		}
+	}
+	if !utils.SliceContainsString(clients, nodeClients) {
+		clients = append(clients, nodeClients)
+	}
+
+	return d.setVolumeExportClients(ctx, volume, clients)
+}
+
+// exportPolicyForClients builds an export policy with a read/write rule for each set of allowed clients.
+func (d *NFSStorageDriver) exportPolicyForClients(clients []string) api.ExportPolicy {
+	rules := make([]api.ExportRule, 0, len(clients))
+	for _, allowedClients := range clients {
+		rules = append(rules, api.ExportRule{
+			AllowedClients: allowedClients,
+			Access:         api.AccessReadWrite,
+			NFSv3:          api.Checked{Checked: true},
+			NFSv4:          api.Checked{Checked: true},
+		})
+	}
+	return api.ExportPolicy{Rules: rules}
+}
+
+// setVolumeExportClients replaces a volume's export rules with one for each set of allowed clients, unless
+// the volume's rules already match.
+func (d *NFSStorageDriver) setVolumeExportClients(ctx context.Context, volume *api.Volume, clients []string) error {
+	current := make([]string, 0, len(volume.ExportPolicy.Rules))
+	for _, rule := range volume.ExportPolicy.Rules {
+		current = append(current, rule.AllowedClients)
+	}
+	if reflect.DeepEqual(current, clients) {
+		return nil
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"volume":  volume.CreationToken,
+		"clients": clients,
+	}).Debug("Updating volume export rules.")
+
+	_, err := d.API.ChangeVolumeExportPolicy(ctx, volume, d.exportPolicyForClients(clients))
+	return err
+}
+
+// EnablePublishEnforcement limits an existing volume's export rules to the nodes it is published to.  This is
+// only done while the volume is not published anywhere, so the volume is left without access until the
+// next publish.
+func (d *NFSStorageDriver) EnablePublishEnforcement(ctx context.Context, volume *storage.Volume) error {
+	// Do not enable publish enforcement on unmanaged imports
+	if volume.Config.ImportNotManaged {
+		return nil
+	}
+
+	gcpVolume, err := d.API.GetVolumeByCreationToken(ctx, volume.Config.InternalName)
+	if err != nil {
+		return fmt.Errorf("could not find volume %s: %v", volume.Config.InternalName, err)
+	}
+
+	if err = d.setVolumeExportClients(ctx, gcpVolume, []string{drivers.NoAccessExportRule}); err != nil {
+		return fmt.Errorf("could not remove export rules of volume %s; %v", volume.Config.InternalName, err)
+	}
+	if !utils.SliceContainsString(gcpVolume.Labels, drivers.PublishEnforcementLabel) {
+		labels := append(append([]string{}, gcpVolume.Labels...), drivers.PublishEnforcementLabel)
+		if _, err = d.API.RelabelVolume(ctx, gcpVolume, labels); err != nil {
+			return fmt.Errorf("could not label volume %s; %v", volume.Config.InternalName, err)
+		}
+	}
+
+	volume.Config.AccessInfo.PublishEnforcement = true
 	return nil
 }
 
@@ -1868,6 +2013,11 @@ func (d *NFSStorageDriver) GetStorageBackendSpecs(_ context.Context, backend sto
 
 func (d *NFSStorageDriver) CreatePrepare(ctx context.Context, volConfig *storage.VolumeConfig) {
 	volConfig.InternalName = d.GetInternalVolumeName(ctx, volConfig.Name)
+
+	// New CSI volumes start with publish enforcement on; imported volumes are limited on first publish
+	if tridentconfig.CurrentDriverContext == tridentconfig.ContextCSI && volConfig.ImportOriginalName == "" {
+		volConfig.AccessInfo.PublishEnforcement = true
+	}
 }
 
 // GetStorageBackendPhysicalPoolNames retrieves storage backend physical pools
@@ -2093,6 +2243,59 @@ func (d *NFSStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*uti
 		defer Logc(ctx).WithFields(fields).Debug("<<<< ReconcileNodeAccess")
 	}
 
+	volumes, err := d.API.GetVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Update the export rules of volumes limited to the nodes they are published to
+	prefix := *d.Config.StoragePrefix
+	for i := range *volumes {
+		volume := &(*volumes)[i]
+		if !utils.SliceContainsString(volume.Labels, drivers.PublishEnforcementLabel) ||
+			!strings.HasPrefix(volume.CreationToken, prefix) {
+			continue
+		}
+
+		current := make([]string, 0, len(volume.ExportPolicy.Rules))
+		for _, rule := range volume.ExportPolicy.Rules {
+			current = append(current, rule.AllowedClients)
+		}
+		clients := drivers.ReconcileNodeExportClients(ctx, current, nodes, d.Config.ExportRule)
+		if err = d.setVolumeExportClients(ctx, volume, clients); err != nil {
+			return fmt.Errorf("could not reconcile export rules of volume %s; %v", volume.CreationToken, err)
+		}
+	}
+
+	return nil
+}
+
+// RevokeNodeAccess removes a node's addresses from the export rules of volumes with publish enforcement.
+func (d *NFSStorageDriver) RevokeNodeAccess(ctx context.Context, node *utils.Node, _ string) error {
+	volumes, err := d.API.GetVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	prefix := *d.Config.StoragePrefix
+	for i := range *volumes {
+		volume := &(*volumes)[i]
+		if !utils.SliceContainsString(volume.Labels, drivers.PublishEnforcementLabel) ||
+			!strings.HasPrefix(volume.CreationToken, prefix) {
+			continue
+		}
+
+		current := make([]string, 0, len(volume.ExportPolicy.Rules))
+		for _, rule := range volume.ExportPolicy.Rules {
+			current = append(current, rule.AllowedClients)
+		}
+		clients := drivers.RemoveNodeExportClients(current, node)
+		if err = d.setVolumeExportClients(ctx, volume, clients); err != nil {
+			return fmt.Errorf("could not remove node %s from export rules of volume %s; %v", node.Name,
+				volume.CreationToken, err)
+		}
+	}
+
 	return nil
 }
 
@@ -628,3 +628,125 @@ func TestGetPoolsForCreate(t *testing.T) {
 		})
 	}
 }
+
+func TestPublish_PublishEnforcement(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	gcpClient := mockGCPClient.NewMockGCPClient(mockCtrl)
+
+	d := newTestGCPDriver()
+	d.API = gcpClient
+	d.Config.ExportRule = "10.0.0.0/24"
+
+	volConfig := &storage.VolumeConfig{InternalName: "test_vol1", InternalID: "id"}
+	volConfig.AccessInfo.PublishEnforcement = true
+	volume := &api.Volume{
+		CreationToken: "test_vol1",
+		MountPoints:   []api.MountPoint{{Server: "1.1.1.1", Export: "/test_vol1"}},
+		ExportPolicy:  d.exportPolicyForClients([]string{"10.0.0.2"}),
+	}
+	publishInfo := &utils.VolumePublishInfo{
+		HostName: "node1",
+		Nodes:    []*utils.Node{{Name: "node1", IPs: []string{"10.0.0.1", "192.168.0.1"}}},
+	}
+
+	gcpClient.EXPECT().GetVolumeByCreationToken(ctx(), "test_vol1").Return(volume, nil)
+	gcpClient.EXPECT().ChangeVolumeExportPolicy(ctx(), volume,
+		d.exportPolicyForClients([]string{"10.0.0.2", "10.0.0.1"})).Return(volume, nil)
+
+	err := d.Publish(ctx(), volConfig, publishInfo)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1.1.1.1", publishInfo.NfsServerIP)
+}
+
+func TestUnpublish(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	gcpClient := mockGCPClient.NewMockGCPClient(mockCtrl)
+
+	d := newTestGCPDriver()
+	d.API = gcpClient
+	d.Config.ExportRule = "0.0.0.0/0"
+
+	volConfig := &storage.VolumeConfig{InternalName: "test_vol1"}
+	volume := &api.Volume{
+		CreationToken: "test_vol1",
+		ExportPolicy:  d.exportPolicyForClients([]string{"10.0.0.1", "10.0.0.2"}),
+	}
+	publishInfo := &utils.VolumePublishInfo{
+		HostName: "node1",
+		Nodes:    []*utils.Node{{Name: "node2", IPs: []string{"10.0.0.2"}}},
+	}
+
+	// Nothing is done without publish enforcement
+	assert.NoError(t, d.Unpublish(ctx(), volConfig, publishInfo))
+
+	volConfig.AccessInfo.PublishEnforcement = true
+	gcpClient.EXPECT().GetVolumeByCreationToken(ctx(), "test_vol1").Return(volume, nil)
+	gcpClient.EXPECT().ChangeVolumeExportPolicy(ctx(), volume,
+		d.exportPolicyForClients([]string{"10.0.0.2"})).Return(volume, nil)
+
+	assert.NoError(t, d.Unpublish(ctx(), volConfig, publishInfo))
+}
+
+func TestEnablePublishEnforcement(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	gcpClient := mockGCPClient.NewMockGCPClient(mockCtrl)
+
+	d := newTestGCPDriver()
+	d.API = gcpClient
+
+	volume := &storage.Volume{Config: &storage.VolumeConfig{InternalName: "test_vol1"}}
+	gcpVolume := &api.Volume{
+		CreationToken: "test_vol1",
+		Labels:        []string{"label"},
+		ExportPolicy:  d.exportPolicyForClients([]string{"0.0.0.0/0"}),
+	}
+
+	gcpClient.EXPECT().GetVolumeByCreationToken(ctx(), "test_vol1").Return(gcpVolume, nil)
+	gcpClient.EXPECT().ChangeVolumeExportPolicy(ctx(), gcpVolume,
+		d.exportPolicyForClients([]string{drivers.NoAccessExportRule})).Return(gcpVolume, nil)
+	gcpClient.EXPECT().RelabelVolume(ctx(), gcpVolume,
+		[]string{"label", drivers.PublishEnforcementLabel}).Return(gcpVolume, nil)
+
+	err := d.EnablePublishEnforcement(ctx(), volume)
+
+	assert.NoError(t, err)
+	assert.True(t, volume.Config.AccessInfo.PublishEnforcement)
+}
+
+func TestReconcileNodeAccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	gcpClient := mockGCPClient.NewMockGCPClient(mockCtrl)
+
+	d := newTestGCPDriver()
+	d.API = gcpClient
+	d.Config.ExportRule = "0.0.0.0/0"
+
+	volumes := []api.Volume{
+		{
+			CreationToken: "test_vol1",
+			Labels:        []string{drivers.PublishEnforcementLabel},
+			ExportPolicy:  d.exportPolicyForClients([]string{"10.0.0.1", "10.0.0.2"}),
+		},
+		{
+			CreationToken: "test_vol2",
+			ExportPolicy:  d.exportPolicyForClients([]string{"0.0.0.0/0"}),
+		},
+		{
+			CreationToken: "other_vol3",
+			Labels:        []string{drivers.PublishEnforcementLabel},
+			ExportPolicy:  d.exportPolicyForClients([]string{"10.0.0.2"}),
+		},
+	}
+	nodes := []*utils.Node{{Name: "node1", IPs: []string{"10.0.0.1"}}}
+
+	gcpClient.EXPECT().GetVolumes(ctx()).Return(&volumes, nil)
+	gcpClient.EXPECT().ChangeVolumeExportPolicy(ctx(), &volumes[0],
+		d.exportPolicyForClients([]string{"10.0.0.1"})).Return(&volumes[0], nil)
+
+	assert.NoError(t, d.ReconcileNodeAccess(ctx(), nodes, ""))
+}
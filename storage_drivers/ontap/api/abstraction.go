@@ -44,6 +44,8 @@ const (
 
 type OntapAPI interface {
 	APIVersion(context.Context) (string, error)
+	// Ping makes a lightweight, uncached call to the cluster to verify that it is responding
+	Ping(context.Context) error
 	SVMName() string
 
 	EmsAutosupportLog(
@@ -87,10 +89,13 @@ type OntapAPI interface {
 	LunGetGeometry(ctx context.Context, lunPath string) (uint64, error)
 	LunGetComment(ctx context.Context, lunPath string) (string, bool, error)
 	LunSetAttribute(ctx context.Context, lunPath, attribute, fstype, context, luks string) error
+	LunGetAttribute(ctx context.Context, lunPath, attribute string) (string, error)
 	ParseLunComment(ctx context.Context, commentJSON string) (map[string]string, error)
 	LunSetQosPolicyGroup(ctx context.Context, lunPath string, qosPolicyGroup QosPolicyGroup) error
 	LunGetByName(ctx context.Context, name string) (*Lun, error)
 	LunRename(ctx context.Context, lunPath, newLunPath string) error
+	LunMove(ctx context.Context, lunPath, newLunPath string) error
+	LunMoveStatus(ctx context.Context, newLunPath string) (bool, error)
 	LunMapInfo(ctx context.Context, initiatorGroupName, lunPath string) (int, error)
 	EnsureLunMapped(ctx context.Context, initiatorGroupName, lunPath string, importNotManaged bool) (int, error)
 	LunUnmap(ctx context.Context, initiatorGroupName, lunPath string) error
@@ -117,6 +122,7 @@ type OntapAPI interface {
 	GetSVMAggregateAttributes(ctx context.Context) (map[string]string, error)
 	GetSVMAggregateNames(ctx context.Context) ([]string, error)
 	GetSVMAggregateSpace(ctx context.Context, aggregate string) ([]SVMAggregateSpace, error)
+	GetAggregatePerformanceUtilization(ctx context.Context) (map[string]float64, error)
 	GetSVMPeers(ctx context.Context) ([]string, error)
 
 	GetSVMUUID() string
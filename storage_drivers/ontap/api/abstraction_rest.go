@@ -332,6 +332,13 @@ func (d OntapAPIREST) APIVersion(ctx context.Context) (string, error) {
 	return d.api.SystemGetOntapVersion(ctx)
 }
 
+func (d OntapAPIREST) Ping(ctx context.Context) error {
+	if _, err := d.api.ClusterInfo(ctx); err != nil {
+		return fmt.Errorf("could not read cluster info: %v", err)
+	}
+	return nil
+}
+
 func (d OntapAPIREST) NodeListSerialNumbers(ctx context.Context) ([]string, error) {
 	return d.api.NodeListSerialNumbers(ctx)
 }
@@ -647,6 +654,54 @@ func hasRestAggrSpaceInformation(ctx context.Context, aggrSpace *models.Aggregat
 	return true
 }
 
+// GetAggregatePerformanceUtilization returns the percentage of each aggregate's performance capacity in use,
+// keyed by aggregate name.  Performance capacity used is the aggregate's current utilization relative to its
+// optimal point, beyond which latency rises faster than throughput.
+func (d OntapAPIREST) GetAggregatePerformanceUtilization(ctx context.Context) (map[string]float64, error) {
+	response, err := d.api.AggregateHeadroomList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil || response.Payload == nil {
+		return nil, fmt.Errorf("error looking up aggregate performance headroom")
+	}
+
+	utilization := make(map[string]float64)
+	for _, row := range response.Payload.Records {
+		if row == nil {
+			continue
+		}
+
+		aggrName := ""
+		for _, property := range row.Properties {
+			if property != nil && property.Name == headroomAggregateProperty {
+				aggrName = property.Value
+			}
+		}
+
+		var current, optimal int64
+		for _, counter := range row.Counters {
+			if counter == nil {
+				continue
+			}
+			switch counter.Name {
+			case headroomCurrentUtilization:
+				current = counter.Value
+			case headroomOptimalPointUtilization:
+				optimal = counter.Value
+			}
+		}
+
+		if aggrName == "" || optimal <= 0 {
+			Logc(ctx).WithField("row", row.ID).Debug("Skipping aggregate headroom row without utilization.")
+			continue
+		}
+		utilization[aggrName] = 100 * float64(current) / float64(optimal)
+	}
+
+	return utilization, nil
+}
+
 func (d OntapAPIREST) GetSVMAggregateSpace(ctx context.Context, aggregate string) ([]SVMAggregateSpace, error) {
 	response, aggrSpaceErr := d.api.AggregateList(ctx, aggregate)
 	if aggrSpaceErr != nil {
@@ -1482,6 +1537,11 @@ func (d OntapAPIREST) LunSetAttribute(ctx context.Context, lunPath, attribute, f
 	return nil
 }
 
+// LunGetAttribute returns the value of a named LUN attribute, or an empty string if it is not set.
+func (d OntapAPIREST) LunGetAttribute(ctx context.Context, lunPath, attribute string) (string, error) {
+	return d.api.LunGetAttribute(ctx, lunPath, attribute)
+}
+
 // TODO: Change this for LUN Attributes when available
 func (d OntapAPIREST) LunGetComment(ctx context.Context, lunPath string) (string, bool, error) {
 	// parse := true
@@ -1645,6 +1705,17 @@ func (d OntapAPIREST) LunRename(ctx context.Context, lunPath, newLunPath string)
 	return d.api.LunRename(ctx, lunPath, newLunPath)
 }
 
+// LunMove is not supported with REST, since moving a LUN by renaming it gives no way to follow the
+// progress of the move.
+func (d OntapAPIREST) LunMove(_ context.Context, _, _ string) error {
+	return utils.UnsupportedError("LUN move is not supported with the ONTAP REST API")
+}
+
+// LunMoveStatus is not supported with REST; see LunMove.
+func (d OntapAPIREST) LunMoveStatus(_ context.Context, _ string) (bool, error) {
+	return false, utils.UnsupportedError("LUN move is not supported with the ONTAP REST API")
+}
+
 func (d OntapAPIREST) LunMapInfo(ctx context.Context, initiatorGroupName, lunPath string) (int, error) {
 	lunID := -1
 	info, err := d.api.LunMapInfo(ctx, initiatorGroupName, lunPath)
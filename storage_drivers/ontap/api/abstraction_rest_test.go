@@ -12,8 +12,10 @@ import (
 
 	mockapi "github.com/netapp/trident/mocks/mock_storage_drivers/mock_ontap"
 	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/storage_drivers/ontap/api/rest/client/cluster"
 	"github.com/netapp/trident/storage_drivers/ontap/api/rest/client/s_a_n"
 	"github.com/netapp/trident/storage_drivers/ontap/api/rest/models"
+	"github.com/netapp/trident/utils"
 )
 
 var ctx = context.Background()
@@ -133,3 +135,57 @@ func TestEnsureLunMapped(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, int(number), resultLun)
 }
+
+func TestLunMove_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rsi := mockapi.NewMockRestClientInterface(ctrl)
+	oapi, err := api.NewOntapAPIRESTFromRestClientInterface(rsi)
+	assert.NoError(t, err)
+
+	// Renaming a LUN to another Flexvol does not move its data, so it must not be used to move LUNs
+	err = oapi.LunMove(ctx, "/vol/flexvol1/lun1", "/vol/flexvol2/lun1")
+	assert.True(t, utils.IsUnsupportedError(err))
+
+	_, err = oapi.LunMoveStatus(ctx, "/vol/flexvol2/lun1")
+	assert.True(t, utils.IsUnsupportedError(err))
+}
+
+func TestGetAggregatePerformanceUtilization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rsi := mockapi.NewMockRestClientInterface(ctrl)
+	oapi, err := api.NewOntapAPIRESTFromRestClientInterface(rsi)
+	assert.NoError(t, err)
+
+	headroomRow := func(aggrName string, current, optimal int64) *models.CounterRow {
+		return &models.CounterRow{
+			ID:         aggrName,
+			Properties: []*models.CounterProperty{{Name: "aggregate.name", Value: aggrName}},
+			Counters: []*models.Counter{
+				{Name: "current_utilization", Value: current},
+				{Name: "optimal_point_utilization", Value: optimal},
+			},
+		}
+	}
+
+	rsi.EXPECT().AggregateHeadroomList(ctx).Return(&cluster.CounterRowCollectionGetOK{
+		Payload: &models.CounterRowResponse{
+			Records: []*models.CounterRow{
+				headroomRow("aggr1", 30, 60),
+				headroomRow("aggr2", 90, 60),
+				headroomRow("aggr3", 10, 0),
+				nil,
+			},
+		},
+	}, nil)
+	utilization, err := oapi.GetAggregatePerformanceUtilization(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"aggr1": 50, "aggr2": 150}, utilization)
+
+	rsi.EXPECT().AggregateHeadroomList(ctx).Return(nil, errors.New("counter table not found"))
+	_, err = oapi.GetAggregatePerformanceUtilization(ctx)
+	assert.Error(t, err)
+}
@@ -244,6 +244,10 @@ func (d OntapAPIZAPI) APIVersion(ctx context.Context) (string, error) {
 	return d.api.SystemGetOntapiVersion(ctx)
 }
 
+func (d OntapAPIZAPI) Ping(ctx context.Context) error {
+	return d.api.SystemPing(ctx)
+}
+
 func (d OntapAPIZAPI) SupportsFeature(ctx context.Context, feature Feature) bool {
 	return d.api.SupportsFeature(ctx, feature)
 }
@@ -402,6 +406,15 @@ func (d OntapAPIZAPI) LunGetComment(ctx context.Context, lunPath string) (string
 	return fstype, parse, nil
 }
 
+// LunGetAttribute returns the value of a named LUN attribute, or an empty string if it is not set.
+func (d OntapAPIZAPI) LunGetAttribute(ctx context.Context, lunPath, attribute string) (string, error) {
+	attrResponse, err := d.api.LunGetAttribute(lunPath, attribute)
+	if err = azgo.GetError(ctx, attrResponse, err); err != nil {
+		return "", err
+	}
+	return attrResponse.Result.Value(), nil
+}
+
 func (d OntapAPIZAPI) LunSetAttribute(ctx context.Context, lunPath, attribute, fstype, context, luks string) error {
 	var attrResponse interface{}
 	var err error
@@ -545,6 +558,41 @@ func (d OntapAPIZAPI) LunRename(ctx context.Context, lunPath, newLunPath string)
 	return nil
 }
 
+func (d OntapAPIZAPI) LunMove(ctx context.Context, lunPath, newLunPath string) error {
+	moveResponse, err := d.api.LunMoveStart(lunPath, newLunPath)
+	if err = azgo.GetError(ctx, moveResponse, err); err != nil {
+		Logc(ctx).WithField("originalPath", lunPath).Errorf("moving LUN failed: %v", err)
+		return fmt.Errorf("LUN %s move failed: %v", lunPath, err)
+	}
+
+	return nil
+}
+
+// LunMoveStatus returns whether ONTAP has finished moving a LUN to the specified path.  ONTAP stops reporting
+// a move some time after it completes, so a move that is not reported is taken to have finished.  A move that
+// has stopped with an error is reported as finished, along with the error.
+func (d OntapAPIZAPI) LunMoveStatus(ctx context.Context, newLunPath string) (bool, error) {
+	moveResponse, err := d.api.LunMoveGet(newLunPath)
+	if err = azgo.GetError(ctx, moveResponse, err); err != nil {
+		return false, fmt.Errorf("could not get state of LUN move to %s: %v", newLunPath, err)
+	}
+
+	if moveResponse.Result.AttributesListPtr == nil || len(moveResponse.Result.AttributesListPtr.LunMoveInfoPtr) == 0 {
+		return true, nil
+	}
+	lunMove := moveResponse.Result.AttributesListPtr.LunMoveInfoPtr[0]
+
+	switch strings.ReplaceAll(strings.ToLower(lunMove.JobStatus()), "-", "_") {
+	case "complete", "destroyed":
+		return true, nil
+	case "paused_error", "paused_admin":
+		return true, fmt.Errorf("move of LUN %s to %s is %s: %s", lunMove.SourcePath(), newLunPath,
+			lunMove.JobStatus(), lunMove.LastFailureReason())
+	default:
+		return false, nil
+	}
+}
+
 func (d OntapAPIZAPI) LunMapInfo(ctx context.Context, initiatorGroupName, lunPath string) (int, error) {
 	lunID := -1
 	lunMapResponse, err := d.api.LunMapListInfo(lunPath)
@@ -1336,6 +1384,10 @@ func hasZapiAggrSpaceInformation(ctx context.Context, aggrSpace azgo.SpaceInform
 	return true
 }
 
+func (d OntapAPIZAPI) GetAggregatePerformanceUtilization(_ context.Context) (map[string]float64, error) {
+	return nil, utils.UnsupportedError("aggregate performance headroom is only available with the REST API")
+}
+
 func (d OntapAPIZAPI) GetSVMAggregateSpace(ctx context.Context, aggregate string) ([]SVMAggregateSpace, error) {
 	// lookup aggregate
 	aggrSpaceResponse, aggrSpaceErr := d.api.AggrSpaceGetIterRequest(aggregate)
@@ -2123,6 +2175,11 @@ func (d OntapAPIZAPI) SnapmirrorGet(
 		snapmirror.ReplicationSchedule = info.Schedule()
 	}
 
+	if info.LastTransferEndTimestampPtr != nil {
+		lastTransferEndTime := time.Unix(int64(info.LastTransferEndTimestamp()), 0)
+		snapmirror.LastTransferEndTime = &lastTransferEndTime
+	}
+
 	return snapmirror, nil
 }
 
@@ -0,0 +1,372 @@
+// Code generated automatically. DO NOT EDIT.
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package azgo
+
+import (
+	"encoding/xml"
+	log "github.com/sirupsen/logrus"
+	"reflect"
+)
+
+// LunMoveGetIterRequest is a structure to represent a lun-move-get-iter Request ZAPI object
+type LunMoveGetIterRequest struct {
+	XMLName              xml.Name                                `xml:"lun-move-get-iter"`
+	DesiredAttributesPtr *LunMoveGetIterRequestDesiredAttributes `xml:"desired-attributes"`
+	MaxRecordsPtr        *int                                    `xml:"max-records"`
+	QueryPtr             *LunMoveGetIterRequestQuery             `xml:"query"`
+	TagPtr               *string                                 `xml:"tag"`
+}
+
+// LunMoveGetIterResponse is a structure to represent a lun-move-get-iter Response ZAPI object
+type LunMoveGetIterResponse struct {
+	XMLName         xml.Name                     `xml:"netapp"`
+	ResponseVersion string                       `xml:"version,attr"`
+	ResponseXmlns   string                       `xml:"xmlns,attr"`
+	Result          LunMoveGetIterResponseResult `xml:"results"`
+}
+
+// NewLunMoveGetIterResponse is a factory method for creating new instances of LunMoveGetIterResponse objects
+func NewLunMoveGetIterResponse() *LunMoveGetIterResponse {
+	return &LunMoveGetIterResponse{}
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o LunMoveGetIterResponse) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ToXML converts this object into an xml string representation
+func (o *LunMoveGetIterResponse) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// LunMoveGetIterResponseResult is a structure to represent a lun-move-get-iter Response Result ZAPI object
+type LunMoveGetIterResponseResult struct {
+	XMLName           xml.Name                                    `xml:"results"`
+	ResultStatusAttr  string                                      `xml:"status,attr"`
+	ResultReasonAttr  string                                      `xml:"reason,attr"`
+	ResultErrnoAttr   string                                      `xml:"errno,attr"`
+	AttributesListPtr *LunMoveGetIterResponseResultAttributesList `xml:"attributes-list"`
+	NextTagPtr        *string                                     `xml:"next-tag"`
+	NumRecordsPtr     *int                                        `xml:"num-records"`
+}
+
+// NewLunMoveGetIterRequest is a factory method for creating new instances of LunMoveGetIterRequest objects
+func NewLunMoveGetIterRequest() *LunMoveGetIterRequest {
+	return &LunMoveGetIterRequest{}
+}
+
+// NewLunMoveGetIterResponseResult is a factory method for creating new instances of LunMoveGetIterResponseResult objects
+func NewLunMoveGetIterResponseResult() *LunMoveGetIterResponseResult {
+	return &LunMoveGetIterResponseResult{}
+}
+
+// ToXML converts this object into an xml string representation
+func (o *LunMoveGetIterRequest) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// ToXML converts this object into an xml string representation
+func (o *LunMoveGetIterResponseResult) ToXML() (string, error) {
+	output, err := xml.MarshalIndent(o, " ", "    ")
+	if err != nil {
+		log.Errorf("error: %v", err)
+	}
+	return string(output), err
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o LunMoveGetIterRequest) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o LunMoveGetIterResponseResult) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// ExecuteUsing converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *LunMoveGetIterRequest) ExecuteUsing(zr *ZapiRunner) (*LunMoveGetIterResponse, error) {
+	return o.executeWithIteration(zr)
+}
+
+// executeWithoutIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+
+func (o *LunMoveGetIterRequest) executeWithoutIteration(zr *ZapiRunner) (*LunMoveGetIterResponse, error) {
+	result, err := zr.ExecuteUsing(o, "LunMoveGetIterRequest", NewLunMoveGetIterResponse())
+	if result == nil {
+		return nil, err
+	}
+	return result.(*LunMoveGetIterResponse), err
+}
+
+// executeWithIteration converts this object to a ZAPI XML representation and uses the supplied ZapiRunner to send to a filer
+func (o *LunMoveGetIterRequest) executeWithIteration(zr *ZapiRunner) (*LunMoveGetIterResponse, error) {
+	combined := NewLunMoveGetIterResponse()
+	combined.Result.SetAttributesList(LunMoveGetIterResponseResultAttributesList{})
+	var nextTagPtr *string
+	done := false
+	for !done {
+		n, err := o.executeWithoutIteration(zr)
+
+		if err != nil {
+			return nil, err
+		}
+		nextTagPtr = n.Result.NextTagPtr
+		if nextTagPtr == nil {
+			done = true
+		} else {
+			o.SetTag(*nextTagPtr)
+		}
+
+		if n.Result.NumRecordsPtr == nil {
+			done = true
+		} else {
+			recordsRead := n.Result.NumRecords()
+			if recordsRead == 0 {
+				done = true
+			}
+		}
+
+		if n.Result.AttributesListPtr != nil {
+			if combined.Result.AttributesListPtr == nil {
+				combined.Result.SetAttributesList(LunMoveGetIterResponseResultAttributesList{})
+			}
+			combinedAttributesList := combined.Result.AttributesList()
+			combinedAttributes := combinedAttributesList.values()
+
+			resultAttributesList := n.Result.AttributesList()
+			resultAttributes := resultAttributesList.values()
+
+			combined.Result.AttributesListPtr.setValues(append(combinedAttributes, resultAttributes...))
+		}
+
+		if done {
+
+			combined.Result.ResultErrnoAttr = n.Result.ResultErrnoAttr
+			combined.Result.ResultReasonAttr = n.Result.ResultReasonAttr
+			combined.Result.ResultStatusAttr = n.Result.ResultStatusAttr
+
+			combinedAttributesList := combined.Result.AttributesList()
+			combinedAttributes := combinedAttributesList.values()
+			combined.Result.SetNumRecords(len(combinedAttributes))
+
+		}
+	}
+	return combined, nil
+}
+
+// LunMoveGetIterRequestDesiredAttributes is a wrapper
+type LunMoveGetIterRequestDesiredAttributes struct {
+	XMLName        xml.Name         `xml:"desired-attributes"`
+	LunMoveInfoPtr *LunMoveInfoType `xml:"lun-move-info"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o LunMoveGetIterRequestDesiredAttributes) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// LunMoveInfo is a 'getter' method
+func (o *LunMoveGetIterRequestDesiredAttributes) LunMoveInfo() LunMoveInfoType {
+	var r LunMoveInfoType
+	if o.LunMoveInfoPtr == nil {
+		return r
+	}
+	r = *o.LunMoveInfoPtr
+	return r
+}
+
+// SetLunMoveInfo is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterRequestDesiredAttributes) SetLunMoveInfo(newValue LunMoveInfoType) *LunMoveGetIterRequestDesiredAttributes {
+	o.LunMoveInfoPtr = &newValue
+	return o
+}
+
+// DesiredAttributes is a 'getter' method
+func (o *LunMoveGetIterRequest) DesiredAttributes() LunMoveGetIterRequestDesiredAttributes {
+	var r LunMoveGetIterRequestDesiredAttributes
+	if o.DesiredAttributesPtr == nil {
+		return r
+	}
+	r = *o.DesiredAttributesPtr
+	return r
+}
+
+// SetDesiredAttributes is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterRequest) SetDesiredAttributes(newValue LunMoveGetIterRequestDesiredAttributes) *LunMoveGetIterRequest {
+	o.DesiredAttributesPtr = &newValue
+	return o
+}
+
+// MaxRecords is a 'getter' method
+func (o *LunMoveGetIterRequest) MaxRecords() int {
+	var r int
+	if o.MaxRecordsPtr == nil {
+		return r
+	}
+	r = *o.MaxRecordsPtr
+	return r
+}
+
+// SetMaxRecords is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterRequest) SetMaxRecords(newValue int) *LunMoveGetIterRequest {
+	o.MaxRecordsPtr = &newValue
+	return o
+}
+
+// LunMoveGetIterRequestQuery is a wrapper
+type LunMoveGetIterRequestQuery struct {
+	XMLName        xml.Name         `xml:"query"`
+	LunMoveInfoPtr *LunMoveInfoType `xml:"lun-move-info"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o LunMoveGetIterRequestQuery) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// LunMoveInfo is a 'getter' method
+func (o *LunMoveGetIterRequestQuery) LunMoveInfo() LunMoveInfoType {
+	var r LunMoveInfoType
+	if o.LunMoveInfoPtr == nil {
+		return r
+	}
+	r = *o.LunMoveInfoPtr
+	return r
+}
+
+// SetLunMoveInfo is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterRequestQuery) SetLunMoveInfo(newValue LunMoveInfoType) *LunMoveGetIterRequestQuery {
+	o.LunMoveInfoPtr = &newValue
+	return o
+}
+
+// Query is a 'getter' method
+func (o *LunMoveGetIterRequest) Query() LunMoveGetIterRequestQuery {
+	var r LunMoveGetIterRequestQuery
+	if o.QueryPtr == nil {
+		return r
+	}
+	r = *o.QueryPtr
+	return r
+}
+
+// SetQuery is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterRequest) SetQuery(newValue LunMoveGetIterRequestQuery) *LunMoveGetIterRequest {
+	o.QueryPtr = &newValue
+	return o
+}
+
+// Tag is a 'getter' method
+func (o *LunMoveGetIterRequest) Tag() string {
+	var r string
+	if o.TagPtr == nil {
+		return r
+	}
+	r = *o.TagPtr
+	return r
+}
+
+// SetTag is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterRequest) SetTag(newValue string) *LunMoveGetIterRequest {
+	o.TagPtr = &newValue
+	return o
+}
+
+// LunMoveGetIterResponseResultAttributesList is a wrapper
+type LunMoveGetIterResponseResultAttributesList struct {
+	XMLName        xml.Name          `xml:"attributes-list"`
+	LunMoveInfoPtr []LunMoveInfoType `xml:"lun-move-info"`
+}
+
+// String returns a string representation of this object's fields and implements the Stringer interface
+func (o LunMoveGetIterResponseResultAttributesList) String() string {
+	return ToString(reflect.ValueOf(o))
+}
+
+// LunMoveInfo is a 'getter' method
+func (o *LunMoveGetIterResponseResultAttributesList) LunMoveInfo() []LunMoveInfoType {
+	r := o.LunMoveInfoPtr
+	return r
+}
+
+// SetLunMoveInfo is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterResponseResultAttributesList) SetLunMoveInfo(newValue []LunMoveInfoType) *LunMoveGetIterResponseResultAttributesList {
+	newSlice := make([]LunMoveInfoType, len(newValue))
+	copy(newSlice, newValue)
+	o.LunMoveInfoPtr = newSlice
+	return o
+}
+
+// values is a 'getter' method
+func (o *LunMoveGetIterResponseResultAttributesList) values() []LunMoveInfoType {
+	r := o.LunMoveInfoPtr
+	return r
+}
+
+// setValues is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterResponseResultAttributesList) setValues(newValue []LunMoveInfoType) *LunMoveGetIterResponseResultAttributesList {
+	newSlice := make([]LunMoveInfoType, len(newValue))
+	copy(newSlice, newValue)
+	o.LunMoveInfoPtr = newSlice
+	return o
+}
+
+// AttributesList is a 'getter' method
+func (o *LunMoveGetIterResponseResult) AttributesList() LunMoveGetIterResponseResultAttributesList {
+	var r LunMoveGetIterResponseResultAttributesList
+	if o.AttributesListPtr == nil {
+		return r
+	}
+	r = *o.AttributesListPtr
+	return r
+}
+
+// SetAttributesList is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterResponseResult) SetAttributesList(newValue LunMoveGetIterResponseResultAttributesList) *LunMoveGetIterResponseResult {
+	o.AttributesListPtr = &newValue
+	return o
+}
+
+// NextTag is a 'getter' method
+func (o *LunMoveGetIterResponseResult) NextTag() string {
+	var r string
+	if o.NextTagPtr == nil {
+		return r
+	}
+	r = *o.NextTagPtr
+	return r
+}
+
+// SetNextTag is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterResponseResult) SetNextTag(newValue string) *LunMoveGetIterResponseResult {
+	o.NextTagPtr = &newValue
+	return o
+}
+
+// NumRecords is a 'getter' method
+func (o *LunMoveGetIterResponseResult) NumRecords() int {
+	var r int
+	if o.NumRecordsPtr == nil {
+		return r
+	}
+	r = *o.NumRecordsPtr
+	return r
+}
+
+// SetNumRecords is a fluent style 'setter' method that can be chained
+func (o *LunMoveGetIterResponseResult) SetNumRecords(newValue int) *LunMoveGetIterResponseResult {
+	o.NumRecordsPtr = &newValue
+	return o
+}
@@ -2969,6 +2969,52 @@ func (c RestClient) AggregateList(ctx context.Context, pattern string) (*storage
 	return result, nil
 }
 
+// AggregateHeadroomList returns the performance headroom counters of all aggregates
+func (c RestClient) AggregateHeadroomList(ctx context.Context) (*cluster.CounterRowCollectionGetOK, error) {
+	params := cluster.NewCounterRowCollectionGetParamsWithTimeout(c.httpClient.Timeout)
+
+	params.Context = ctx
+	params.HTTPClient = c.httpClient
+
+	params.SetCounterTableNamePathParameter(aggregateHeadroomCounterTable)
+	params.SetFieldsQueryParameter([]string{"properties", "counters"})
+
+	result, err := c.api.Cluster.CounterRowCollectionGet(params, c.authInfo)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, nil
+	}
+
+	if HasNextLink(result.Payload) {
+		nextLink := result.Payload.Links.Next
+		done := false
+	NextLoop:
+		for !done {
+			resultNext, errNext := c.api.Cluster.CounterRowCollectionGet(params, c.authInfo, WithNextLink(nextLink))
+			if errNext != nil {
+				return nil, errNext
+			}
+			if resultNext == nil {
+				done = true
+				continue NextLoop
+			}
+
+			result.Payload.NumRecords += resultNext.Payload.NumRecords
+			result.Payload.Records = append(result.Payload.Records, resultNext.Payload.Records...)
+
+			if !HasNextLink(resultNext.Payload) {
+				done = true
+				continue NextLoop
+			} else {
+				nextLink = resultNext.Payload.Links.Next
+			}
+		}
+	}
+	return result, nil
+}
+
 // ////////////////////////////////////////////////////////////////////////////
 // SVM/Vserver operations
 // ////////////////////////////////////////////////////////////////////////////
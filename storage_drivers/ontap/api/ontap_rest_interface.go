@@ -178,6 +178,8 @@ type RestClientInterface interface {
 	PollJobStatus(ctx context.Context, payload *models.JobLinkResponse) error
 	// AggregateList returns the names of all Aggregates whose names match the supplied pattern
 	AggregateList(ctx context.Context, pattern string) (*storage.AggregateCollectionGetOK, error)
+	// AggregateHeadroomList returns the performance headroom counters of all aggregates
+	AggregateHeadroomList(ctx context.Context) (*cluster.CounterRowCollectionGetOK, error)
 	// SvmGet gets the volume with the specified uuid
 	SvmGet(ctx context.Context, uuid string) (*svm.SvmGetOK, error)
 	// SvmList returns the names of all SVMs whose names match the supplied pattern
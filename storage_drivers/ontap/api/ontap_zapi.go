@@ -736,6 +736,32 @@ func (c Client) LunRename(path, newPath string) (*azgo.LunMoveResponse, error) {
 	return response, err
 }
 
+// LunMoveStart moves a LUN to a different volume within the same SVM.  The LUN is available
+// at its new path immediately while ONTAP copies the data in the background.
+// equivalent to filer::> lun move start -vserver iscsi_vs -source-path /vol/v1/lun0 -destination-path /vol/v2/lun0
+func (c Client) LunMoveStart(path, newPath string) (*azgo.LunMoveStartResponse, error) {
+	response, err := azgo.NewLunMoveStartRequest().
+		SetSourcePath(path).
+		SetDestinationPath(newPath).
+		ExecuteUsing(c.zr)
+	return response, err
+}
+
+// LunMoveGet returns the state of the move of a LUN to the specified path, if ONTAP still reports it
+// equivalent to filer::> lun move show -vserver iscsi_vs -destination-path /vol/v2/lun0
+func (c Client) LunMoveGet(newPath string) (*azgo.LunMoveGetIterResponse, error) {
+	query := &azgo.LunMoveGetIterRequestQuery{}
+	lunMoveInfo := azgo.NewLunMoveInfoType().
+		SetDestinationPath(newPath)
+	query.SetLunMoveInfo(*lunMoveInfo)
+
+	response, err := azgo.NewLunMoveGetIterRequest().
+		SetMaxRecords(DefaultZapiRecords).
+		SetQuery(*query).
+		ExecuteUsing(c.zr)
+	return response, err
+}
+
 // LunUnmap deletes the lun mapping for the given LUN path and igroup
 // equivalent to filer::> lun mapping delete -vserver iscsi_vs -path /vol/v/lun0 -igroup group
 func (c Client) LunUnmap(initiatorGroupName, lunPath string) (*azgo.LunUnmapResponse, error) {
@@ -2862,6 +2888,15 @@ func (c Client) SystemGetOntapiVersion(ctx context.Context) (string, error) {
 	return c.zr.OntapiVersion, nil
 }
 
+// SystemPing reads the ONTAPI version from the cluster, bypassing the cache, to verify that it is responding.
+func (c Client) SystemPing(ctx context.Context) error {
+	result, err := azgo.NewSystemGetOntapiVersionRequest().ExecuteUsing(c.zr)
+	if err = azgo.GetError(ctx, result, err); err != nil {
+		return fmt.Errorf("could not read ONTAPI version: %v", err)
+	}
+	return nil
+}
+
 func (c Client) NodeListSerialNumbers(ctx context.Context) ([]string, error) {
 	serialNumbers := make([]string, 0)
 	zr := c.GetNontunneledZapiRunner()
@@ -102,6 +102,13 @@ type ZapiClientInterface interface {
 	LunCount(ctx context.Context, volume string) (int, error)
 	// LunRename changes the name of a LUN
 	LunRename(path, newPath string) (*azgo.LunMoveResponse, error)
+	// LunMoveStart moves a LUN to a different volume within the same SVM.  The LUN is available
+	// at its new path immediately while ONTAP copies the data in the background.
+	// equivalent to filer::> lun move start -vserver iscsi_vs -source-path /vol/v1/lun0 -destination-path /vol/v2/lun0
+	LunMoveStart(path, newPath string) (*azgo.LunMoveStartResponse, error)
+	// LunMoveGet returns the state of the move of a LUN to the specified path, if ONTAP still reports it
+	// equivalent to filer::> lun move show -vserver iscsi_vs -destination-path /vol/v2/lun0
+	LunMoveGet(newPath string) (*azgo.LunMoveGetIterResponse, error)
 	// LunUnmap deletes the lun mapping for the given LUN path and igroup
 	// equivalent to filer::> lun mapping delete -vserver iscsi_vs -path /vol/v/lun0 -igroup group
 	LunUnmap(initiatorGroupName, lunPath string) (*azgo.LunUnmapResponse, error)
@@ -318,6 +325,8 @@ type ZapiClientInterface interface {
 	SystemGetVersion() (*azgo.SystemGetVersionResponse, error)
 	// SystemGetOntapiVersion gets the ONTAPI version using the credentials, and caches & returns the result.
 	SystemGetOntapiVersion(ctx context.Context) (string, error)
+	// SystemPing reads the ONTAPI version from the cluster, bypassing the cache, to verify that it is responding.
+	SystemPing(ctx context.Context) error
 	NodeListSerialNumbers(ctx context.Context) ([]string, error)
 	// EmsAutosupportLog generates an auto support message with the supplied parameters
 	EmsAutosupportLog(appVersion string, autoSupport bool, category string, computerName string, eventDescription string, eventID int, eventSource string, logLevel int) (*azgo.EmsAutosupportLogResponse, error)
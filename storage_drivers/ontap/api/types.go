@@ -4,6 +4,8 @@ package api
 
 //go:generate mockgen -destination=../../../mocks/mock_storage_drivers/mock_ontap/mock_api.go github.com/netapp/trident/storage_drivers/ontap/api OntapAPI
 
+import "time"
+
 type Volume struct {
 	AccessType        string
 	Aggregates        []string
@@ -97,6 +99,14 @@ const (
 	SnapmirrorPolicyRuleAll = "all_source_snapshots"
 )
 
+// The ONTAP counter table and counters that describe how much of an aggregate's performance capacity is in use
+const (
+	aggregateHeadroomCounterTable   = "resource_headroom_aggr"
+	headroomAggregateProperty       = "aggregate.name"
+	headroomCurrentUtilization      = "current_utilization"
+	headroomOptimalPointUtilization = "optimal_point_utilization"
+)
+
 type SnapmirrorState string
 
 const (
@@ -139,6 +149,7 @@ type Snapmirror struct {
 	UnhealthyReason     string
 	ReplicationPolicy   string
 	ReplicationSchedule string
+	LastTransferEndTime *time.Time
 }
 
 type SnapmirrorPolicyType string
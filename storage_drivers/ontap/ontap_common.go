@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	log "github.com/sirupsen/logrus"
 
 	tridentconfig "github.com/netapp/trident/config"
@@ -91,9 +92,11 @@ const (
 
 // For legacy reasons, these strings mustn't change
 const (
-	artifactPrefixDocker     = "ndvp"
-	artifactPrefixKubernetes = "trident"
-	LUNAttributeFSType       = "com.netapp.ndvp.fstype"
+	artifactPrefixDocker         = "ndvp"
+	artifactPrefixKubernetes     = "trident"
+	LUNAttributeFSType           = "com.netapp.ndvp.fstype"
+	LUNAttributePublications     = "com.netapp.ndvp.publications"
+	LUNAttributePublicationsLock = "com.netapp.ndvp.publications.lock"
 )
 
 // CleanBackendName removes brackets and replaces colons with periods to avoid regex parsing errors.
@@ -454,6 +457,29 @@ func reconcileSANNodeAccess(
 	return nil
 }
 
+// revokeSANNodeAccess unmaps every LUN from a node's igroup and deletes the igroup.
+func revokeSANNodeAccess(ctx context.Context, clientAPI api.OntapAPI, igroupName string) error {
+	luns, err := clientAPI.IgroupListLUNsMapped(ctx, igroupName)
+	if err != nil {
+		return fmt.Errorf("error listing LUNs mapped to igroup %s; %v", igroupName, err)
+	}
+	for _, lunPath := range luns {
+		if err = clientAPI.LunUnmap(ctx, igroupName, lunPath); err != nil {
+			return fmt.Errorf("error unmapping LUN %s from igroup %s; %v", lunPath, igroupName, err)
+		}
+	}
+	if err = clientAPI.IgroupDestroy(ctx, igroupName); err != nil {
+		return fmt.Errorf("error deleting igroup %s; %v", igroupName, err)
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"igroup": igroupName,
+		"LUNs":   len(luns),
+	}).Debug("Revoked node access to LUNs.")
+
+	return nil
+}
+
 // GetISCSITargetInfo returns the iSCSI node name and iSCSI interfaces using the provided client's SVM.
 func GetISCSITargetInfo(
 	ctx context.Context, clientAPI api.OntapAPI, config *drivers.OntapStorageDriverConfig,
@@ -537,6 +563,220 @@ func getNodeSpecificIgroupName(nodeName, tridentUUID string) string {
 	return fmt.Sprintf("%s-%s", nodeName, tridentUUID)
 }
 
+// getDockerHostIgroupName returns the igroup that maps LUNs to a single Docker host.  Docker hosts don't share
+// a Trident UUID, so the backend's igroup name is used to keep the name stable across hosts and restarts.
+func getDockerHostIgroupName(hostName, igroupName string) string {
+	return getNodeSpecificIgroupName(hostName, igroupName)
+}
+
+// lunPublication is the compact form of a volume publication recorded in a LUN attribute.
+type lunPublication struct {
+	Node       string `json:"node"`
+	ReadOnly   bool   `json:"readOnly,omitempty"`
+	AccessMode int32  `json:"accessMode,omitempty"`
+}
+
+// getLUNPublications returns the publications of a volume recorded in its LUN's attributes.
+func getLUNPublications(
+	ctx context.Context, clientAPI api.OntapAPI, volumeName, lunPath string,
+) ([]*utils.VolumePublication, error) {
+	value, err := clientAPI.LunGetAttribute(ctx, lunPath, LUNAttributePublications)
+	if err != nil {
+		return nil, fmt.Errorf("error reading publications of LUN %s; %v", lunPath, err)
+	}
+
+	publications := make([]*utils.VolumePublication, 0)
+	if value == "" {
+		return publications, nil
+	}
+
+	var lunPublications []lunPublication
+	if err = json.Unmarshal([]byte(value), &lunPublications); err != nil {
+		return nil, fmt.Errorf("invalid publications recorded on LUN %s; %v", lunPath, err)
+	}
+	for _, p := range lunPublications {
+		publications = append(publications, &utils.VolumePublication{
+			Name:       utils.GenerateVolumePublishName(volumeName, p.Node),
+			VolumeName: volumeName,
+			NodeName:   p.Node,
+			ReadOnly:   p.ReadOnly,
+			AccessMode: p.AccessMode,
+		})
+	}
+	return publications, nil
+}
+
+// setLUNPublications records the publications of a volume in its LUN's attributes.  The caller must hold
+// the LUN's publications lock.
+func setLUNPublications(
+	ctx context.Context, clientAPI api.OntapAPI, lunPath string, publications []*utils.VolumePublication,
+) error {
+	lunPublications := make([]lunPublication, 0, len(publications))
+	for _, p := range publications {
+		lunPublications = append(lunPublications, lunPublication{
+			Node:       p.NodeName,
+			ReadOnly:   p.ReadOnly,
+			AccessMode: p.AccessMode,
+		})
+	}
+	value, err := json.Marshal(lunPublications)
+	if err != nil {
+		return err
+	}
+
+	if err = clientAPI.LunSetAttribute(ctx, lunPath, LUNAttributePublications, string(value), "", ""); err != nil {
+		return fmt.Errorf("error recording publications of LUN %s; %v", lunPath, err)
+	}
+	return nil
+}
+
+// addLUNPublication records a publication of a volume in its LUN's attributes, replacing any earlier
+// publication to the same node.  If exclusive is true, a publication to another node is reported as a
+// FoundError and nothing is recorded.
+func addLUNPublication(
+	ctx context.Context, clientAPI api.OntapAPI, volumeName, lunPath string, publication *utils.VolumePublication,
+	exclusive bool,
+) error {
+	return updateLUNPublications(ctx, clientAPI, volumeName, lunPath,
+		func(publications []*utils.VolumePublication) ([]*utils.VolumePublication, error) {
+			updated := make([]*utils.VolumePublication, 0, len(publications)+1)
+			for _, p := range publications {
+				if p.NodeName == publication.NodeName {
+					continue
+				}
+				if exclusive {
+					return nil, utils.FoundError(fmt.Sprintf("volume %s is in use by host %s", volumeName,
+						p.NodeName))
+				}
+				updated = append(updated, p)
+			}
+			return append(updated, publication), nil
+		})
+}
+
+// deleteLUNPublication removes the publication of a volume to a node from its LUN's attributes.
+func deleteLUNPublication(ctx context.Context, clientAPI api.OntapAPI, volumeName, lunPath, nodeName string) error {
+	return updateLUNPublications(ctx, clientAPI, volumeName, lunPath,
+		func(publications []*utils.VolumePublication) ([]*utils.VolumePublication, error) {
+			updated := make([]*utils.VolumePublication, 0, len(publications))
+			for _, p := range publications {
+				if p.NodeName != nodeName {
+					updated = append(updated, p)
+				}
+			}
+			return updated, nil
+		})
+}
+
+// updateLUNPublications changes the publications recorded on a LUN while holding the LUN's publications
+// lock, since hosts that share the backend may change them at the same time.
+func updateLUNPublications(
+	ctx context.Context, clientAPI api.OntapAPI, volumeName, lunPath string,
+	update func([]*utils.VolumePublication) ([]*utils.VolumePublication, error),
+) error {
+	unlock, err := lockLUNPublications(ctx, clientAPI, lunPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	publications, err := getLUNPublications(ctx, clientAPI, volumeName, lunPath)
+	if err != nil {
+		return err
+	}
+	if publications, err = update(publications); err != nil {
+		return err
+	}
+	return setLUNPublications(ctx, clientAPI, lunPath, publications)
+}
+
+var (
+	// lunPublicationsLockLease is how long a publications lock is held before other hosts may break it
+	lunPublicationsLockLease = 30 * time.Second
+	// lunPublicationsLockSettleTime is how long a host waits after writing the publications lock before
+	// checking that no other host overwrote it.  It must be longer than a LUN attribute takes to write.
+	lunPublicationsLockSettleTime = 2 * time.Second
+	// lunPublicationsLockTimeout is how long a host waits for another host's publications lock
+	lunPublicationsLockTimeout = 2 * time.Minute
+)
+
+// lockLUNPublications takes a lease on the publications recorded on a LUN.  ONTAP can't compare and swap a
+// LUN attribute, so a host that finds the lock free writes its own lease, waits for any competing writes to
+// land, and holds the lock only if its lease survived.  An expired lease, left by a host that failed while
+// holding the lock, is taken over.  The returned function releases the lock.
+func lockLUNPublications(ctx context.Context, clientAPI api.OntapAPI, lunPath string) (func(), error) {
+	token := utils.RandomString(16)
+	var lease string
+
+	acquire := func() error {
+		value, err := clientAPI.LunGetAttribute(ctx, lunPath, LUNAttributePublicationsLock)
+		if err != nil {
+			return backoff.Permanent(fmt.Errorf("error reading publications lock of LUN %s; %v", lunPath, err))
+		}
+		if holder, expires := parseLUNPublicationsLease(value); holder != "" && time.Now().Before(expires) {
+			return fmt.Errorf("publications of LUN %s are locked until %v", lunPath, expires)
+		}
+
+		lease = fmt.Sprintf("%s %d", token, time.Now().Add(lunPublicationsLockLease).Unix())
+		if err = clientAPI.LunSetAttribute(ctx, lunPath, LUNAttributePublicationsLock, lease, "", ""); err != nil {
+			return backoff.Permanent(fmt.Errorf("error writing publications lock of LUN %s; %v", lunPath, err))
+		}
+
+		time.Sleep(lunPublicationsLockSettleTime)
+
+		if value, err = clientAPI.LunGetAttribute(ctx, lunPath, LUNAttributePublicationsLock); err != nil {
+			return backoff.Permanent(fmt.Errorf("error reading publications lock of LUN %s; %v", lunPath, err))
+		}
+		if value != lease {
+			return fmt.Errorf("another host locked the publications of LUN %s", lunPath)
+		}
+		return nil
+	}
+	acquireNotify := func(err error, duration time.Duration) {
+		Logc(ctx).WithFields(log.Fields{
+			"LUN":       lunPath,
+			"increment": duration,
+		}).WithError(err).Debug("Publications lock not acquired, waiting.")
+	}
+	acquireBackoff := backoff.NewExponentialBackOff()
+	acquireBackoff.InitialInterval = 500 * time.Millisecond
+	acquireBackoff.MaxInterval = 5 * time.Second
+	acquireBackoff.Multiplier = 2
+	acquireBackoff.RandomizationFactor = 0.5
+	acquireBackoff.MaxElapsedTime = lunPublicationsLockTimeout
+
+	if err := backoff.RetryNotify(acquire, acquireBackoff, acquireNotify); err != nil {
+		return nil, fmt.Errorf("could not lock publications of LUN %s; %v", lunPath, err)
+	}
+
+	unlock := func() {
+		// Release the lease by expiring it, unless it was broken in the meantime.  Attributes can't be unset.
+		value, err := clientAPI.LunGetAttribute(ctx, lunPath, LUNAttributePublicationsLock)
+		if err == nil && value == lease {
+			err = clientAPI.LunSetAttribute(ctx, lunPath, LUNAttributePublicationsLock, token+" 0", "", "")
+		}
+		if err != nil {
+			Logc(ctx).WithField("LUN", lunPath).WithError(err).Warning(
+				"Could not release publications lock; it expires on its own.")
+		}
+	}
+	return unlock, nil
+}
+
+// parseLUNPublicationsLease returns the holder and expiry of a publications lock lease, or an empty holder
+// if the lease is malformed.
+func parseLUNPublicationsLease(value string) (string, time.Time) {
+	holder, expires, ok := strings.Cut(value, " ")
+	if !ok {
+		return "", time.Time{}
+	}
+	seconds, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", time.Time{}
+	}
+	return holder, time.Unix(seconds, 0)
+}
+
 // PublishLUN publishes the volume to the host specified in publishInfo from ontap-san or
 // ontap-san-economy. This method may or may not be running on the host where the volume will be
 // mounted, so it should limit itself to updating access rules, initiator groups, etc. that require
@@ -1123,6 +1363,14 @@ const (
 	DefaultTieringPolicy             = ""
 )
 
+// Limits of the ONTAP storage objects Trident creates, offered to storage classes by each pool
+const (
+	MaxFlexvolSizeBytes    = 100 * 1024 * 1024 * 1024 * 1024       // 100 TiB
+	MaxFlexgroupSizeBytes  = 20 * 1024 * 1024 * 1024 * 1024 * 1024 // 20 PiB
+	MaxLUNSizeBytes        = 16 * 1024 * 1024 * 1024 * 1024        // 16 TiB
+	MaxSnapshotsPerFlexvol = 1023
+)
+
 // PopulateConfigurationDefaults fills in default values for configuration settings if not supplied in the config file
 func PopulateConfigurationDefaults(ctx context.Context, config *drivers.OntapStorageDriverConfig) error {
 	if config.DebugTraceFlags["method"] {
@@ -2150,6 +2398,50 @@ func getStorageBackendSpecsCommon(
 	return nil
 }
 
+// getPoolUtilizationCommon reports the percentage of each pool's performance capacity in use.  Physical pools
+// are aggregates, while volumes in a virtual pool may be placed on any of the backend's aggregates, so virtual
+// pools report the mean utilization of those aggregates.
+func getPoolUtilizationCommon(
+	ctx context.Context, d StorageDriver, physicalPools, virtualPools map[string]storage.Pool,
+) (map[string]float64, error) {
+	aggrUtilization, err := d.GetAPI().GetAggregatePerformanceUtilization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	utilization := make(map[string]float64)
+	aggrNames := make([]string, 0, len(physicalPools))
+	for name := range physicalPools {
+		if value, ok := aggrUtilization[name]; ok {
+			utilization[name] = value
+		}
+		aggrNames = append(aggrNames, name)
+	}
+
+	if mean, ok := meanAggregateUtilization(aggrUtilization, aggrNames); ok {
+		for name := range virtualPools {
+			utilization[name] = mean
+		}
+	}
+
+	return utilization, nil
+}
+
+// meanAggregateUtilization returns the mean utilization of those of the named aggregates that reported it.
+func meanAggregateUtilization(aggrUtilization map[string]float64, aggrNames []string) (float64, bool) {
+	total, count := 0.0, 0
+	for _, aggrName := range aggrNames {
+		if value, ok := aggrUtilization[aggrName]; ok {
+			total += value
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, false
+	}
+	return total / float64(count), true
+}
+
 func getVolumeOptsCommon(
 	ctx context.Context, volConfig *storage.VolumeConfig, requests map[string]sa.Request,
 ) map[string]string {
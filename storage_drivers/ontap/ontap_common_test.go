@@ -1823,3 +1823,36 @@ func TestConstructOntapNASQTreeSMBVolumePath(t *testing.T) {
 	result := ConstructOntapNASQTreeSMBVolumePath(ctx, "test_share", "flex-vol", "vol")
 	assert.Equal(t, "\\test_share\\flex-vol\\vol", result, "unable to construct Ontap-NAS-QTree SMB volume path")
 }
+
+func TestGetPoolUtilizationCommon(t *testing.T) {
+	ctx := context.Background()
+
+	mockOntapAPI := newMockOntapAPI(t)
+	mockOntapAPI.EXPECT().GetAggregatePerformanceUtilization(ctx).Return(
+		map[string]float64{"aggr1": 20, "aggr2": 60, "other": 90}, nil).Times(2)
+	d := newTestOntapNASDriver(ONTAPTEST_LOCALHOST, "0", ONTAPTEST_VSERVER_AGGR_NAME, "CSI", false)
+	d.API = mockOntapAPI
+
+	physicalPools := map[string]storage.Pool{
+		"aggr1": storage.NewStoragePool(nil, "aggr1"),
+		"aggr2": storage.NewStoragePool(nil, "aggr2"),
+		"aggr3": storage.NewStoragePool(nil, "aggr3"),
+	}
+	virtualPools := map[string]storage.Pool{
+		"pool_0": storage.NewStoragePool(nil, "pool_0"),
+	}
+
+	// Aggregates that don't report utilization are left out
+	utilization, err := getPoolUtilizationCommon(ctx, d, physicalPools, map[string]storage.Pool{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"aggr1": 20, "aggr2": 60}, utilization)
+
+	// Virtual pools report the mean utilization of the aggregates
+	utilization, err = getPoolUtilizationCommon(ctx, d, physicalPools, virtualPools)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(40), utilization["pool_0"])
+
+	mockOntapAPI.EXPECT().GetAggregatePerformanceUtilization(ctx).Return(nil, fmt.Errorf("unsupported"))
+	_, err = getPoolUtilizationCommon(ctx, d, physicalPools, virtualPools)
+	assert.Error(t, err)
+}
@@ -918,6 +918,8 @@ func (d *NASStorageDriver) getStoragePoolAttributes(ctx context.Context) map[str
 		sa.Encryption:       sa.NewBoolOffer(true),
 		sa.Replication:      sa.NewBoolOffer(mirroring),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+		sa.MaxVolumeSize:    drivers.NewMaxVolumeSizeOffer(d.Config.CommonStorageDriverConfig, MaxFlexvolSizeBytes),
+		sa.MaxSnapshots:     sa.NewIntOffer(0, MaxSnapshotsPerFlexvol),
 	}
 }
 
@@ -1135,6 +1137,16 @@ func (d *NASStorageDriver) Resize(
 	return nil
 }
 
+// ProbeHealth verifies that the ONTAP cluster is responding
+func (d *NASStorageDriver) ProbeHealth(ctx context.Context) error {
+	return d.API.Ping(ctx)
+}
+
+// GetPoolUtilization reports the percentage of each pool's performance capacity in use
+func (d *NASStorageDriver) GetPoolUtilization(ctx context.Context) (map[string]float64, error) {
+	return getPoolUtilizationCommon(ctx, d, d.physicalPools, d.virtualPools)
+}
+
 func (d *NASStorageDriver) ReconcileNodeAccess(
 	ctx context.Context, nodes []*utils.Node, backendUUID string,
 ) error {
@@ -1280,6 +1292,13 @@ func (d *NASStorageDriver) GetReplicationDetails(
 	return getReplicationDetails(ctx, localVolumeHandle, remoteVolumeHandle, d.API)
 }
 
+// GetMirrorHealth returns the health and last transfer time of a snapmirror relationship
+func (d *NASStorageDriver) GetMirrorHealth(
+	ctx context.Context, localVolumeHandle, remoteVolumeHandle string,
+) (*storage.MirrorHealth, error) {
+	return getMirrorHealth(ctx, localVolumeHandle, remoteVolumeHandle, d.API)
+}
+
 // MountVolume returns the volume mount error(if any)
 func (d *NASStorageDriver) MountVolume(
 	ctx context.Context, name, junctionPath string, flexVol *api.Volume,
@@ -1301,6 +1301,8 @@ func (d *NASFlexGroupStorageDriver) getStoragePoolAttributes() map[string]sa.Off
 		sa.Replication:      sa.NewBoolOffer(false),
 		sa.Clones:           sa.NewBoolOffer(true),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+		sa.MaxVolumeSize:    drivers.NewMaxVolumeSizeOffer(d.Config.CommonStorageDriverConfig, MaxFlexgroupSizeBytes),
+		sa.MaxSnapshots:     sa.NewIntOffer(0, MaxSnapshotsPerFlexvol),
 	}
 }
 
@@ -1528,6 +1530,33 @@ func (d *NASFlexGroupStorageDriver) Resize(
 	return nil
 }
 
+// ProbeHealth verifies that the ONTAP cluster is responding
+func (d *NASFlexGroupStorageDriver) ProbeHealth(ctx context.Context) error {
+	return d.API.Ping(ctx)
+}
+
+// GetPoolUtilization reports the percentage of each pool's performance capacity in use.  FlexGroups span all
+// of the SVM's aggregates, so every pool reports the mean utilization of those aggregates.
+func (d *NASFlexGroupStorageDriver) GetPoolUtilization(ctx context.Context) (map[string]float64, error) {
+	aggrNames, err := d.API.GetSVMAggregateNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	aggrUtilization, err := d.API.GetAggregatePerformanceUtilization(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	utilization := make(map[string]float64)
+	if mean, ok := meanAggregateUtilization(aggrUtilization, aggrNames); ok {
+		utilization[d.physicalPool.Name()] = mean
+		for name := range d.virtualPools {
+			utilization[name] = mean
+		}
+	}
+	return utilization, nil
+}
+
 func (d *NASFlexGroupStorageDriver) ReconcileNodeAccess(
 	ctx context.Context, nodes []*utils.Node, backendUUID string,
 ) error {
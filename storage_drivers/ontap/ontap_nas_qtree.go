@@ -1359,6 +1359,7 @@ func (d *NASQtreeStorageDriver) getStoragePoolAttributes() map[string]sa.Offer {
 		sa.Encryption:       sa.NewBoolOffer(true),
 		sa.Replication:      sa.NewBoolOffer(false),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+		sa.MaxVolumeSize:    drivers.NewMaxVolumeSizeOffer(d.Config.CommonStorageDriverConfig, MaxFlexvolSizeBytes),
 	}
 }
 
@@ -1865,6 +1866,16 @@ func (d *NASQtreeStorageDriver) resizeFlexvol(ctx context.Context, flexvol strin
 	return nil
 }
 
+// ProbeHealth verifies that the ONTAP cluster is responding
+func (d *NASQtreeStorageDriver) ProbeHealth(ctx context.Context) error {
+	return d.API.Ping(ctx)
+}
+
+// GetPoolUtilization reports the percentage of each pool's performance capacity in use
+func (d *NASQtreeStorageDriver) GetPoolUtilization(ctx context.Context) (map[string]float64, error) {
+	return getPoolUtilizationCommon(ctx, d, d.physicalPools, d.virtualPools)
+}
+
 func (d *NASQtreeStorageDriver) ReconcileNodeAccess(
 	ctx context.Context, nodes []*utils.Node, backendUUID string,
 ) error {
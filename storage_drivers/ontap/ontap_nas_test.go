@@ -2787,3 +2787,13 @@ func TestOntapNasStorageDriverBackendName(t *testing.T) {
 
 	assert.Equal(t, result, "myBackend")
 }
+
+func TestOntapNasStorageDriverProbeHealth(t *testing.T) {
+	mockAPI, driver := newMockOntapNASDriver(t)
+
+	mockAPI.EXPECT().Ping(ctx).Return(nil)
+	assert.NoError(t, driver.ProbeHealth(ctx))
+
+	mockAPI.EXPECT().Ping(ctx).Return(fmt.Errorf("connection refused"))
+	assert.Error(t, driver.ProbeHealth(ctx))
+}
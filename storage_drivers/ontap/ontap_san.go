@@ -25,6 +25,13 @@ func lunPath(name string) string {
 	return fmt.Sprintf("/vol/%v/lun0", name)
 }
 
+// publishEnforced returns true if a volume is mapped to the igroup of each host it is published to, rather
+// than to the backend's igroup.  Docker hosts keep no record of a volume's publish enforcement, so every
+// Docker host uses its own igroup.
+func publishEnforced(volConfig *storage.VolumeConfig) bool {
+	return volConfig.AccessInfo.PublishEnforcement || tridentconfig.CurrentDriverContext == tridentconfig.ContextDocker
+}
+
 // SANStorageDriver is for iSCSI storage provisioning
 type SANStorageDriver struct {
 	initialized bool
@@ -760,9 +767,17 @@ func (d *SANStorageDriver) Publish(
 	lunPath := lunPath(name)
 	igroupName := d.Config.IgroupName
 	// Use the node specific igroup if publish enforcement is enabled
-	if volConfig.AccessInfo.PublishEnforcement && tridentconfig.CurrentDriverContext == tridentconfig.ContextCSI {
-		igroupName = getNodeSpecificIgroupName(publishInfo.HostName, publishInfo.TridentUUID)
-		err = ensureIGroupExists(ctx, d.GetAPI(), igroupName)
+	if publishEnforced(volConfig) {
+		switch tridentconfig.CurrentDriverContext {
+		case tridentconfig.ContextCSI:
+			igroupName = getNodeSpecificIgroupName(publishInfo.HostName, publishInfo.TridentUUID)
+			err = ensureIGroupExists(ctx, d.GetAPI(), igroupName)
+		case tridentconfig.ContextDocker:
+			igroupName = getDockerHostIgroupName(publishInfo.HostName, d.Config.IgroupName)
+			if err = ensureIGroupExists(ctx, d.GetAPI(), igroupName); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Get target info
@@ -799,13 +814,21 @@ func (d *SANStorageDriver) Unpublish(
 		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
 	}
 
-	if !volConfig.AccessInfo.PublishEnforcement || tridentconfig.CurrentDriverContext != tridentconfig.ContextCSI {
+	if !publishEnforced(volConfig) {
 		// Nothing to do if publish enforcement is not enabled
 		return nil
 	}
 
 	// Unmap the LUN from the node's igroup
-	igroupName := getNodeSpecificIgroupName(publishInfo.HostName, publishInfo.TridentUUID)
+	var igroupName string
+	switch tridentconfig.CurrentDriverContext {
+	case tridentconfig.ContextCSI:
+		igroupName = getNodeSpecificIgroupName(publishInfo.HostName, publishInfo.TridentUUID)
+	case tridentconfig.ContextDocker:
+		igroupName = getDockerHostIgroupName(publishInfo.HostName, d.Config.IgroupName)
+	default:
+		return nil
+	}
 	lunPath := lunPath(name)
 	lunID, err := d.API.LunMapInfo(ctx, igroupName, lunPath)
 	if err != nil {
@@ -989,6 +1012,10 @@ func (d *SANStorageDriver) getStoragePoolAttributes(ctx context.Context) map[str
 		sa.Encryption:       sa.NewBoolOffer(true),
 		sa.Replication:      sa.NewBoolOffer(mirroring),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+		sa.MaxVolumeSize:    drivers.NewMaxVolumeSizeOffer(d.Config.CommonStorageDriverConfig, MaxLUNSizeBytes),
+		sa.MaxSnapshots:     sa.NewIntOffer(0, MaxSnapshotsPerFlexvol),
+		sa.FileSystemTypes: sa.NewStringOffer(tridentconfig.FsExt3, tridentconfig.FsExt4, tridentconfig.FsXfs,
+			tridentconfig.FsRaw),
 	}
 }
 
@@ -1003,8 +1030,10 @@ func (d *SANStorageDriver) GetInternalVolumeName(_ context.Context, name string)
 }
 
 func (d *SANStorageDriver) CreatePrepare(ctx context.Context, volConfig *storage.VolumeConfig) {
-	if !volConfig.ImportNotManaged && tridentconfig.CurrentDriverContext == tridentconfig.ContextCSI {
-		// All new CSI ONTAP SAN volumes start with publish enforcement on, unless they're unmanaged imports
+	if !volConfig.ImportNotManaged && (tridentconfig.CurrentDriverContext == tridentconfig.ContextCSI ||
+		tridentconfig.CurrentDriverContext == tridentconfig.ContextDocker) {
+		// All new CSI and Docker ONTAP SAN volumes start with publish enforcement on, unless they're unmanaged
+		// imports
 		volConfig.AccessInfo.PublishEnforcement = true
 	}
 	createPrepareCommon(ctx, d, volConfig)
@@ -1164,6 +1193,8 @@ func (d *SANStorageDriver) getVolumeExternal(
 		BlockSize:       "",
 		FileSystem:      "",
 	}
+	// Docker hosts always map volumes to their own igroups
+	volumeConfig.AccessInfo.PublishEnforcement = tridentconfig.CurrentDriverContext == tridentconfig.ContextDocker
 
 	pool := drivers.UnsetPool
 	if len(volume.Aggregates) > 0 {
@@ -1322,6 +1353,16 @@ func (d *SANStorageDriver) Resize(
 	return nil
 }
 
+// ProbeHealth verifies that the ONTAP cluster is responding
+func (d *SANStorageDriver) ProbeHealth(ctx context.Context) error {
+	return d.API.Ping(ctx)
+}
+
+// GetPoolUtilization reports the percentage of each pool's performance capacity in use
+func (d *SANStorageDriver) GetPoolUtilization(ctx context.Context) (map[string]float64, error) {
+	return getPoolUtilizationCommon(ctx, d, d.physicalPools, d.virtualPools)
+}
+
 func (d *SANStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, _ string) error {
 	// Discover known nodes
 	nodeNames := make([]string, 0)
@@ -1468,6 +1509,13 @@ func (d *SANStorageDriver) GetReplicationDetails(
 	return getReplicationDetails(ctx, localVolumeHandle, remoteVolumeHandle, d.API)
 }
 
+// GetMirrorHealth returns the health and last transfer time of a snapmirror relationship
+func (d *SANStorageDriver) GetMirrorHealth(
+	ctx context.Context, localVolumeHandle, remoteVolumeHandle string,
+) (*storage.MirrorHealth, error) {
+	return getMirrorHealth(ctx, localVolumeHandle, remoteVolumeHandle, d.API)
+}
+
 func (d *SANStorageDriver) GetChapInfo(_ context.Context, _, _ string) (*utils.IscsiChapInfo, error) {
 	return &utils.IscsiChapInfo{
 		UseCHAP:              d.Config.UseCHAP,
@@ -1478,12 +1526,35 @@ func (d *SANStorageDriver) GetChapInfo(_ context.Context, _, _ string) (*utils.I
 	}, nil
 }
 
+// GetVolumePublications returns the publications of a volume recorded on its LUN.
+func (d *SANStorageDriver) GetVolumePublications(
+	ctx context.Context, volConfig *storage.VolumeConfig,
+) ([]*utils.VolumePublication, error) {
+	return getLUNPublications(ctx, d.GetAPI(), volConfig.Name, lunPath(volConfig.InternalName))
+}
+
+// AddVolumePublication records a publication of a volume on its LUN, so that it is visible to every host
+// sharing the backend.  If exclusive is true, the publication is refused if another host has one.
+func (d *SANStorageDriver) AddVolumePublication(
+	ctx context.Context, volConfig *storage.VolumeConfig, publication *utils.VolumePublication, exclusive bool,
+) error {
+	return addLUNPublication(ctx, d.GetAPI(), volConfig.Name, lunPath(volConfig.InternalName), publication,
+		exclusive)
+}
+
+// DeleteVolumePublication removes the publication of a volume to a host from its LUN.
+func (d *SANStorageDriver) DeleteVolumePublication(
+	ctx context.Context, volConfig *storage.VolumeConfig, nodeName string,
+) error {
+	return deleteLUNPublication(ctx, d.GetAPI(), volConfig.Name, lunPath(volConfig.InternalName), nodeName)
+}
+
 func (d *SANStorageDriver) EnablePublishEnforcement(ctx context.Context, volume *storage.Volume) error {
 	// Do not enable publish enforcement on unmanaged imports
 	if volume.Config.ImportNotManaged {
 		return nil
 	}
-	err := LunUnmapAllIgroups(ctx, d.GetAPI(), lunPath(volume.Config.Name))
+	err := LunUnmapAllIgroups(ctx, d.GetAPI(), lunPath(volume.Config.InternalName))
 	if err != nil {
 		msg := "error removing all mappings from LUN"
 		Logc(ctx).WithError(err).Error(msg)
@@ -1494,3 +1565,9 @@ func (d *SANStorageDriver) EnablePublishEnforcement(ctx context.Context, volume
 	volume.Config.AccessInfo.PublishEnforcement = true
 	return nil
 }
+
+// RevokeNodeAccess unmaps every LUN from a node's igroup, which holds the LUNs with publish enforcement that
+// are published to the node, and deletes the igroup.
+func (d *SANStorageDriver) RevokeNodeAccess(ctx context.Context, node *utils.Node, tridentUUID string) error {
+	return revokeSANNodeAccess(ctx, d.API, getNodeSpecificIgroupName(node.Name, tridentUUID))
+}
@@ -198,6 +198,8 @@ type SANEconomyStorageDriver struct {
 	flexvolNamePrefix string
 	helper            *LUNHelper
 	lunsPerFlexvol    int
+	sharedLockID      string
+	rebalanceTask     *lunRebalanceTask
 
 	physicalPools map[string]storage.Pool
 	virtualPools  map[string]storage.Pool
@@ -238,6 +240,13 @@ func (d *SANEconomyStorageDriver) FlexvolNamePrefix() string {
 	return d.flexvolNamePrefix
 }
 
+// lunLockID returns the ID of the lock that keeps a LUN, together with its snap-LUNs, in its Flexvol.
+// The name may be the LUN's internal name or the name of its group in a LUN rebalance.
+func (d *SANEconomyStorageDriver) lunLockID(name string) string {
+	name = strings.TrimPrefix(strings.ReplaceAll(name, "-", "_"), *d.Config.StoragePrefix)
+	return d.sharedLockID + "-lun-" + name
+}
+
 // Initialize from the provided config
 func (d *SANEconomyStorageDriver) Initialize(
 	ctx context.Context, driverContext tridentconfig.DriverContext, configJSON string,
@@ -311,10 +320,13 @@ func (d *SANEconomyStorageDriver) Initialize(
 		}
 	}
 
+	d.sharedLockID = d.API.GetSVMUUID() + "-" + *d.Config.StoragePrefix
+
 	Logc(ctx).WithFields(
 		log.Fields{
 			"FlexvolNamePrefix": d.flexvolNamePrefix,
 			"LUNsPerFlexvol":    d.lunsPerFlexvol,
+			"SharedLockID":      d.sharedLockID,
 		},
 	).Debugf("SAN Economy driver settings.")
 
@@ -344,6 +356,12 @@ func (d *SANEconomyStorageDriver) Initialize(
 	d.telemetry.TridentBackendUUID = backendUUID
 	d.telemetry.Start(ctx)
 
+	// Start moving LUNs between Flexvols in the background, if so configured
+	d.rebalanceTask = newLUNRebalanceTask(ctx, d)
+	if d.rebalanceTask != nil {
+		d.rebalanceTask.Start(ctx)
+	}
+
 	d.initialized = true
 	return nil
 }
@@ -371,6 +389,10 @@ func (d *SANEconomyStorageDriver) Terminate(ctx context.Context, _ string) {
 		d.telemetry.Stop()
 	}
 
+	if d.rebalanceTask != nil {
+		d.rebalanceTask.Stop()
+	}
+
 	d.initialized = false
 }
 
@@ -413,6 +435,10 @@ func (d *SANEconomyStorageDriver) Create(
 		defer Logc(ctx).WithFields(fields).Debug("<<<< Create")
 	}
 
+	// Ensure no LUN starts moving between Flexvols while this operation is in progress
+	utils.Lock(ctx, "create", d.sharedLockID)
+	defer utils.Unlock(ctx, "create", d.sharedLockID)
+
 	// Generic user-facing message
 	createError := errors.New("error volume creation failed")
 
@@ -728,6 +754,14 @@ func (d *SANEconomyStorageDriver) CreateClone(
 		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateClone")
 	}
 
+	// Ensure the LUN is not moved between Flexvols, and that no other LUN starts moving, while this
+	// operation is in progress
+	lunLockID := d.lunLockID(source)
+	utils.Lock(ctx, "clone", lunLockID)
+	defer utils.Unlock(ctx, "clone", lunLockID)
+	utils.Lock(ctx, "clone", d.sharedLockID)
+	defer utils.Unlock(ctx, "clone", d.sharedLockID)
+
 	qosPolicyGroup, err := api.NewQosPolicyGroup(qosPolicy, adaptiveQosPolicy)
 	if err != nil {
 		return err
@@ -838,6 +872,14 @@ func (d *SANEconomyStorageDriver) Destroy(ctx context.Context, volConfig *storag
 		defer Logc(ctx).WithFields(fields).Debug("<<<< Destroy")
 	}
 
+	// Ensure the LUN is not moved between Flexvols, and that no other LUN starts moving, while this
+	// operation is in progress
+	lunLockID := d.lunLockID(name)
+	utils.Lock(ctx, "destroy", lunLockID)
+	defer utils.Unlock(ctx, "destroy", lunLockID)
+	utils.Lock(ctx, "destroy", d.sharedLockID)
+	defer utils.Unlock(ctx, "destroy", d.sharedLockID)
+
 	var (
 		err           error
 		iSCSINodeName string
@@ -888,7 +930,7 @@ func (d *SANEconomyStorageDriver) Destroy(ctx context.Context, volConfig *storag
 		return deleteError
 	}
 	for _, snap := range snapList {
-		err = d.DeleteSnapshot(ctx, snap.Config, volConfig)
+		err = d.deleteSnapshot(ctx, snap.Config)
 		if err != nil {
 			Logc(ctx).Errorf("Error snap-LUN delete failed: %v", err)
 			return err
@@ -1163,6 +1205,14 @@ func (d *SANEconomyStorageDriver) CreateSnapshot(
 		defer Logc(ctx).WithFields(fields).Info("<<<< CreateSnapshot")
 	}
 
+	// Ensure the LUN is not moved between Flexvols, and that no other LUN starts moving, while this
+	// operation is in progress
+	lunLockID := d.lunLockID(snapConfig.VolumeInternalName)
+	utils.Lock(ctx, "snapshot", lunLockID)
+	defer utils.Unlock(ctx, "snapshot", lunLockID)
+	utils.Lock(ctx, "snapshot", d.sharedLockID)
+	defer utils.Unlock(ctx, "snapshot", d.sharedLockID)
+
 	internalSnapName := snapConfig.InternalName
 	internalVolumeName := snapConfig.VolumeInternalName
 
@@ -1259,6 +1309,19 @@ func (d *SANEconomyStorageDriver) DeleteSnapshot(
 		defer Logc(ctx).WithFields(fields).Debug("<<<< DeleteSnapshot")
 	}
 
+	// Ensure the LUN is not moved between Flexvols, and that no other LUN starts moving, while this
+	// operation is in progress
+	lunLockID := d.lunLockID(snapConfig.VolumeInternalName)
+	utils.Lock(ctx, "deleteSnapshot", lunLockID)
+	defer utils.Unlock(ctx, "deleteSnapshot", lunLockID)
+	utils.Lock(ctx, "deleteSnapshot", d.sharedLockID)
+	defer utils.Unlock(ctx, "deleteSnapshot", d.sharedLockID)
+
+	return d.deleteSnapshot(ctx, snapConfig)
+}
+
+// deleteSnapshot deletes a snap-LUN.  The caller must hold the driver's shared lock.
+func (d *SANEconomyStorageDriver) deleteSnapshot(ctx context.Context, snapConfig *storage.SnapshotConfig) error {
 	internalSnapName := snapConfig.InternalName
 	// Creating the path string pattern
 	snapLunName := d.helper.GetSnapshotName(snapConfig.VolumeInternalName, internalSnapName)
@@ -1569,6 +1632,9 @@ func (d *SANEconomyStorageDriver) getStoragePoolAttributes() map[string]sa.Offer
 		sa.Encryption:       sa.NewBoolOffer(true),
 		sa.Replication:      sa.NewBoolOffer(false),
 		sa.ProvisioningType: sa.NewStringOffer("thick", "thin"),
+		sa.MaxVolumeSize:    drivers.NewMaxVolumeSizeOffer(d.Config.CommonStorageDriverConfig, MaxLUNSizeBytes),
+		sa.FileSystemTypes: sa.NewStringOffer(tridentconfig.FsExt3, tridentconfig.FsExt4, tridentconfig.FsXfs,
+			tridentconfig.FsRaw),
 	}
 }
 
@@ -1598,6 +1664,14 @@ func (d *SANEconomyStorageDriver) CreateFollowup(ctx context.Context, volConfig
 		defer Logc(ctx).WithFields(fields).Debug("<<<< CreateFollowup")
 	}
 
+	// Ensure the LUN is not moved between Flexvols, and that no other LUN starts moving, while this
+	// operation is in progress
+	lunLockID := d.lunLockID(volConfig.InternalName)
+	utils.Lock(ctx, "createFollowup", lunLockID)
+	defer utils.Unlock(ctx, "createFollowup", lunLockID)
+	utils.Lock(ctx, "createFollowup", d.sharedLockID)
+	defer utils.Unlock(ctx, "createFollowup", d.sharedLockID)
+
 	if d.Config.DriverContext == tridentconfig.ContextDocker {
 		Logc(ctx).Debug("No follow-up create actions for Docker.")
 		return nil
@@ -1839,6 +1913,14 @@ func (d *SANEconomyStorageDriver) Resize(ctx context.Context, volConfig *storage
 		defer Logc(ctx).WithFields(fields).Debug("<<<< Resize")
 	}
 
+	// Ensure the LUN is not moved between Flexvols, and that no other LUN starts moving, while this
+	// operation is in progress
+	lunLockID := d.lunLockID(name)
+	utils.Lock(ctx, "resize", lunLockID)
+	defer utils.Unlock(ctx, "resize", lunLockID)
+	utils.Lock(ctx, "resize", d.sharedLockID)
+	defer utils.Unlock(ctx, "resize", d.sharedLockID)
+
 	// Generic user-facing message
 	resizeError := errors.New("storage driver failed to resize the volume")
 
@@ -2009,6 +2091,16 @@ func (d *SANEconomyStorageDriver) resizeFlexvol(ctx context.Context, flexvol str
 	return nil
 }
 
+// ProbeHealth verifies that the ONTAP cluster is responding
+func (d *SANEconomyStorageDriver) ProbeHealth(ctx context.Context) error {
+	return d.API.Ping(ctx)
+}
+
+// GetPoolUtilization reports the percentage of each pool's performance capacity in use
+func (d *SANEconomyStorageDriver) GetPoolUtilization(ctx context.Context) (map[string]float64, error) {
+	return getPoolUtilizationCommon(ctx, d, d.physicalPools, d.virtualPools)
+}
+
 func (d *SANEconomyStorageDriver) ReconcileNodeAccess(
 	ctx context.Context, nodes []*utils.Node, _ string,
 ) error {
@@ -0,0 +1,558 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+	drivers "github.com/netapp/trident/storage_drivers"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/utils"
+)
+
+const (
+	defaultLUNRebalancePeriodSecs = uint64(0) // disabled unless configured
+	maxLUNMovesPerRebalance       = 10
+	lunRebalanceSizeTolerance     = 0.1 // Flexvols within 10% of the mean size are considered balanced
+	maxLUNMoveWaitTime            = 2 * time.Hour
+)
+
+// LUNMove describes relocating a LUN, together with its snap-LUNs, from one bucket Flexvol to another.
+// The LUN keeps its internal name; only the Flexvol portion of its path changes.
+type LUNMove struct {
+	Name               string   `json:"name"`
+	SourceFlexvol      string   `json:"sourceFlexvol"`
+	DestinationFlexvol string   `json:"destinationFlexvol"`
+	LUNs               []string `json:"luns"`
+	SizeBytes          uint64   `json:"sizeBytes"`
+}
+
+// economyLUNGroup is a LUN plus all of its snap-LUNs, which must always share a Flexvol.
+type economyLUNGroup struct {
+	name      string
+	luns      []string
+	sizeBytes uint64
+	lunCount  int
+}
+
+// economyBucket is the rebalancer's view of a single bucket Flexvol.
+type economyBucket struct {
+	volume    *api.Volume
+	groups    map[string]*economyLUNGroup
+	sizeBytes uint64
+	lunCount  int
+}
+
+// RebalanceLUNs evens out the space and LUN count of the bucket Flexvols managed by this driver
+// by moving LUNs from fuller Flexvols into emptier ones.  Only Flexvols with identical attributes
+// are balanced against each other, and each LUN is moved along with its snap-LUNs so that its
+// snapshots remain usable.  If dryRun is true, the planned moves are reported but not performed.
+func (d *SANEconomyStorageDriver) RebalanceLUNs(ctx context.Context, dryRun bool) ([]LUNMove, error) {
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "RebalanceLUNs",
+			"Type":   "SANEconomyStorageDriver",
+			"dryRun": dryRun,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> RebalanceLUNs")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< RebalanceLUNs")
+	}
+
+	// Only one rebalance may run at a time, but other operations are held off only briefly
+	rebalanceLockID := d.sharedLockID + "-rebalance"
+	utils.Lock(ctx, "rebalance", rebalanceLockID)
+	defer utils.Unlock(ctx, "rebalance", rebalanceLockID)
+
+	utils.Lock(ctx, "rebalance", d.sharedLockID)
+	moves, err := d.planLUNRebalance(ctx)
+	utils.Unlock(ctx, "rebalance", d.sharedLockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not plan LUN rebalance; %v", err)
+	}
+
+	if len(moves) == 0 {
+		Logc(ctx).Debug("LUNs are balanced between Flexvols.")
+		return moves, nil
+	}
+
+	if dryRun {
+		for _, move := range moves {
+			Logc(ctx).WithFields(log.Fields{
+				"LUN":                move.Name,
+				"snapLUNs":           len(move.LUNs) - 1,
+				"sizeBytes":          move.SizeBytes,
+				"sourceFlexvol":      move.SourceFlexvol,
+				"destinationFlexvol": move.DestinationFlexvol,
+			}).Info("Dry run, LUN would be moved.")
+		}
+		return moves, nil
+	}
+
+	for i, move := range moves {
+		if err := d.moveLUNGroup(ctx, move); err != nil {
+			return moves[:i], err
+		}
+	}
+
+	return moves, nil
+}
+
+// planLUNRebalance inspects all bucket Flexvols and returns the LUN moves needed to even them out.
+func (d *SANEconomyStorageDriver) planLUNRebalance(ctx context.Context) ([]LUNMove, error) {
+	volumes, err := d.API.VolumeListByPrefix(ctx, d.FlexvolNamePrefix())
+	if err != nil {
+		return nil, fmt.Errorf("error listing Flexvols; %v", err)
+	}
+
+	shouldLimitFlexvolSize, flexvolSizeLimit, err := drivers.CheckVolumeSizeLimits(
+		ctx, 0, d.Config.CommonStorageDriverConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only Flexvols with matching attributes may exchange LUNs
+	bucketSets := make(map[string][]*economyBucket)
+	for _, volume := range volumes {
+		bucket, err := d.getEconomyBucket(ctx, volume)
+		if err != nil {
+			return nil, err
+		}
+		key := economyBucketKey(volume)
+		bucketSets[key] = append(bucketSets[key], bucket)
+	}
+
+	keys := make([]string, 0, len(bucketSets))
+	for key := range bucketSets {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	moves := make([]LUNMove, 0)
+	for _, key := range keys {
+		remaining := maxLUNMovesPerRebalance - len(moves)
+		if remaining <= 0 {
+			break
+		}
+
+		fits := func(bucket *economyBucket, group *economyLUNGroup) bool {
+			if bucket.lunCount+group.lunCount > d.lunsPerFlexvol {
+				return false
+			}
+			if shouldLimitFlexvolSize {
+				newSize := calculateFlexvolEconomySizeBytes(
+					ctx, bucket.volume.Name, bucket.volume, group.sizeBytes, bucket.sizeBytes)
+				if newSize > flexvolSizeLimit {
+					return false
+				}
+			}
+			return true
+		}
+
+		moves = append(moves, planEconomyBucketMoves(bucketSets[key], remaining, fits)...)
+	}
+
+	return moves, nil
+}
+
+// getEconomyBucket lists the LUNs in a bucket Flexvol and groups each LUN with its snap-LUNs.
+func (d *SANEconomyStorageDriver) getEconomyBucket(ctx context.Context, volume *api.Volume) (*economyBucket, error) {
+	luns, err := d.API.LunList(ctx, fmt.Sprintf("/vol/%s/*", volume.Name))
+	if err != nil {
+		return nil, fmt.Errorf("error enumerating LUNs for volume %v: %v", volume.Name, err)
+	}
+
+	bucket := &economyBucket{
+		volume: volume,
+		groups: make(map[string]*economyLUNGroup),
+	}
+
+	for _, lun := range luns {
+		lunPath := GetLUNPathEconomy(volume.Name, lun.Name)
+		lunSize, _ := strconv.ParseUint(lun.Size, 10, 64)
+		bucket.sizeBytes += lunSize
+
+		isSnapLUN := d.helper.IsValidSnapLUNPath(lunPath)
+		if !isSnapLUN {
+			bucket.lunCount++
+		}
+
+		// Snap-LUN names use underscores in place of hyphens, so key the groups the same way
+		groupName := strings.ReplaceAll(d.helper.GetExternalVolumeNameFromPath(lunPath), "-", "_")
+		if groupName == "" {
+			// Not a LUN created by this driver, so leave it where it is
+			Logc(ctx).WithField("LUN", lunPath).Debug("Skipping unmanaged LUN.")
+			continue
+		}
+
+		group, ok := bucket.groups[groupName]
+		if !ok {
+			group = &economyLUNGroup{name: groupName}
+			bucket.groups[groupName] = group
+		}
+		group.sizeBytes += lunSize
+		if isSnapLUN {
+			group.luns = append(group.luns, lun.Name)
+		} else {
+			group.lunCount++
+			// Move the LUN itself ahead of its snap-LUNs
+			group.luns = append([]string{lun.Name}, group.luns...)
+		}
+	}
+
+	return bucket, nil
+}
+
+// economyBucketKey returns a string that is identical for Flexvols that may exchange LUNs.
+func economyBucketKey(volume *api.Volume) string {
+	aggregates := append([]string{}, volume.Aggregates...)
+	sort.Strings(aggregates)
+	return fmt.Sprintf("%s|%s|%s|%d|%s|%s",
+		strings.Join(aggregates, ","), utils.GetPrintableBoolPtrValue(volume.Encrypt), volume.SnapshotPolicy,
+		volume.SnapshotReserve, volume.SpaceReserve, volume.TieringPolicy)
+}
+
+// planEconomyBucketMoves greedily picks up to maxMoves LUN moves between the supplied buckets, each
+// time choosing the move that most reduces the spread of space and LUN count across the buckets.
+// The buckets are updated in place to reflect the planned moves.
+func planEconomyBucketMoves(
+	buckets []*economyBucket, maxMoves int, fits func(*economyBucket, *economyLUNGroup) bool,
+) []LUNMove {
+	moves := make([]LUNMove, 0)
+	if len(buckets) < 2 {
+		return moves
+	}
+
+	// Keep the plan deterministic
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].volume.Name < buckets[j].volume.Name })
+
+	var totalSize uint64
+	totalCount := 0
+	for _, bucket := range buckets {
+		totalSize += bucket.sizeBytes
+		totalCount += bucket.lunCount
+	}
+	meanSize := float64(totalSize) / float64(len(buckets))
+	meanCount := float64(totalCount) / float64(len(buckets))
+
+	imbalance := func(sizeBytes uint64, lunCount int) float64 {
+		var cost float64
+		if meanSize > 0 {
+			cost += (float64(sizeBytes) - meanSize) * (float64(sizeBytes) - meanSize) / (meanSize * meanSize)
+		}
+		if meanCount > 0 {
+			cost += (float64(lunCount) - meanCount) * (float64(lunCount) - meanCount) / (meanCount * meanCount)
+		}
+		return cost
+	}
+
+	for len(moves) < maxMoves && !economyBucketsBalanced(buckets, meanSize) {
+		var bestSource, bestDestination *economyBucket
+		var bestGroup *economyLUNGroup
+		bestDelta := -1e-9
+
+		for _, source := range buckets {
+			groupNames := make([]string, 0, len(source.groups))
+			for name := range source.groups {
+				groupNames = append(groupNames, name)
+			}
+			sort.Strings(groupNames)
+
+			for _, groupName := range groupNames {
+				group := source.groups[groupName]
+				for _, destination := range buckets {
+					if destination == source || !fits(destination, group) {
+						continue
+					}
+					delta := imbalance(source.sizeBytes-group.sizeBytes, source.lunCount-group.lunCount) +
+						imbalance(destination.sizeBytes+group.sizeBytes, destination.lunCount+group.lunCount) -
+						imbalance(source.sizeBytes, source.lunCount) -
+						imbalance(destination.sizeBytes, destination.lunCount)
+					if delta < bestDelta {
+						bestDelta = delta
+						bestSource, bestDestination, bestGroup = source, destination, group
+					}
+				}
+			}
+		}
+
+		if bestGroup == nil {
+			break
+		}
+
+		delete(bestSource.groups, bestGroup.name)
+		bestSource.sizeBytes -= bestGroup.sizeBytes
+		bestSource.lunCount -= bestGroup.lunCount
+		bestDestination.groups[bestGroup.name] = bestGroup
+		bestDestination.sizeBytes += bestGroup.sizeBytes
+		bestDestination.lunCount += bestGroup.lunCount
+
+		moves = append(moves, LUNMove{
+			Name:               bestGroup.name,
+			SourceFlexvol:      bestSource.volume.Name,
+			DestinationFlexvol: bestDestination.volume.Name,
+			LUNs:               append([]string{}, bestGroup.luns...),
+			SizeBytes:          bestGroup.sizeBytes,
+		})
+	}
+
+	return moves
+}
+
+// economyBucketsBalanced returns true if the buckets are close enough in size and LUN count
+// that moving LUNs between them isn't worthwhile.
+func economyBucketsBalanced(buckets []*economyBucket, meanSize float64) bool {
+	minSize, maxSize := buckets[0].sizeBytes, buckets[0].sizeBytes
+	minCount, maxCount := buckets[0].lunCount, buckets[0].lunCount
+	for _, bucket := range buckets[1:] {
+		if bucket.sizeBytes < minSize {
+			minSize = bucket.sizeBytes
+		}
+		if bucket.sizeBytes > maxSize {
+			maxSize = bucket.sizeBytes
+		}
+		if bucket.lunCount < minCount {
+			minCount = bucket.lunCount
+		}
+		if bucket.lunCount > maxCount {
+			maxCount = bucket.lunCount
+		}
+	}
+	return float64(maxSize-minSize) <= lunRebalanceSizeTolerance*meanSize && maxCount-minCount <= 1
+}
+
+// moveLUNGroup moves a LUN and its snap-LUNs to another bucket Flexvol, growing the destination
+// beforehand.  ONTAP copies the data of a moved LUN in the background, so the source is shrunk (or
+// deleted) only once every LUN in the group has finished moving.  If any LUN in the group cannot be
+// moved, the LUNs already moved are moved back, so that no snap-LUN is parted from its LUN.  The LUN
+// is locked throughout, but the shared lock is held only while the moves are started and completed,
+// not while ONTAP copies the data.
+func (d *SANEconomyStorageDriver) moveLUNGroup(ctx context.Context, move LUNMove) error {
+	fields := log.Fields{
+		"LUN":                move.Name,
+		"sourceFlexvol":      move.SourceFlexvol,
+		"destinationFlexvol": move.DestinationFlexvol,
+	}
+
+	lunLockID := d.lunLockID(move.Name)
+	utils.Lock(ctx, "rebalance", lunLockID)
+	defer utils.Unlock(ctx, "rebalance", lunLockID)
+
+	moved, moveErr := d.startLUNGroupMove(ctx, &move)
+	if moveErr == nil && len(moved) == 0 {
+		Logc(ctx).WithFields(fields).Debug("LUN is no longer in the source Flexvol, not moving it.")
+		return nil
+	}
+	if moveErr == nil {
+		moveErr = d.waitForLUNMoves(ctx, move.DestinationFlexvol, moved)
+	}
+
+	if moveErr != nil {
+		Logc(ctx).WithFields(fields).WithError(moveErr).Error("Could not move LUN, moving it back.")
+		d.rollBackLUNMoves(ctx, move, moved)
+		return moveErr
+	}
+
+	Logc(ctx).WithFields(fields).Info("Moved LUN to rebalance Flexvols.")
+
+	utils.Lock(ctx, "rebalance", d.sharedLockID)
+	defer utils.Unlock(ctx, "rebalance", d.sharedLockID)
+
+	return d.DeleteBucketIfEmpty(ctx, move.SourceFlexvol)
+}
+
+// startLUNGroupMove grows the destination Flexvol of a move and starts moving each LUN in the group,
+// returning the names of the LUNs whose moves were started.  Since snapshots may have been created or
+// deleted since the move was planned, the group is first listed again from the source Flexvol and the
+// move updated to match; if the group is gone, no LUNs are moved.  The caller must hold the LUN lock.
+func (d *SANEconomyStorageDriver) startLUNGroupMove(ctx context.Context, move *LUNMove) ([]string, error) {
+	utils.Lock(ctx, "rebalance", d.sharedLockID)
+	defer utils.Unlock(ctx, "rebalance", d.sharedLockID)
+
+	source, err := d.getEconomyBucket(ctx, &api.Volume{Name: move.SourceFlexvol})
+	if err != nil {
+		return nil, err
+	}
+	group, ok := source.groups[move.Name]
+	if !ok {
+		return nil, nil
+	}
+	move.LUNs = append([]string{}, group.luns...)
+	move.SizeBytes = group.sizeBytes
+
+	if err := d.resizeFlexvol(ctx, move.DestinationFlexvol, move.SizeBytes); err != nil {
+		return nil, fmt.Errorf("could not grow Flexvol %s for LUN %s; %v", move.DestinationFlexvol, move.Name, err)
+	}
+
+	moved := make([]string, 0, len(move.LUNs))
+	for _, lunName := range move.LUNs {
+		sourcePath := GetLUNPathEconomy(move.SourceFlexvol, lunName)
+		destinationPath := GetLUNPathEconomy(move.DestinationFlexvol, lunName)
+		if err := d.API.LunMove(ctx, sourcePath, destinationPath); err != nil {
+			return moved, fmt.Errorf("could not move LUN %s to Flexvol %s; %v",
+				sourcePath, move.DestinationFlexvol, err)
+		}
+		moved = append(moved, lunName)
+	}
+
+	return moved, nil
+}
+
+// waitForLUNMoves waits for ONTAP to finish moving the named LUNs into a Flexvol.
+func (d *SANEconomyStorageDriver) waitForLUNMoves(ctx context.Context, flexvol string, lunNames []string) error {
+	for _, lunName := range lunNames {
+		lunPath := GetLUNPathEconomy(flexvol, lunName)
+
+		checkLUNMoved := func() error {
+			done, err := d.API.LunMoveStatus(ctx, lunPath)
+			if done && err != nil {
+				return backoff.Permanent(err)
+			} else if err != nil {
+				return err
+			} else if !done {
+				return fmt.Errorf("LUN %s is still being moved", lunPath)
+			}
+			return nil
+		}
+		lunMovedNotify := func(err error, duration time.Duration) {
+			Logc(ctx).WithFields(log.Fields{
+				"LUN":       lunPath,
+				"increment": duration,
+			}).WithError(err).Debug("LUN not yet moved, waiting.")
+		}
+		lunMoveBackoff := backoff.NewExponentialBackOff()
+		lunMoveBackoff.InitialInterval = 1 * time.Second
+		lunMoveBackoff.MaxInterval = 30 * time.Second
+		lunMoveBackoff.Multiplier = 2
+		lunMoveBackoff.RandomizationFactor = 0.1
+		lunMoveBackoff.MaxElapsedTime = maxLUNMoveWaitTime
+
+		if err := backoff.RetryNotify(checkLUNMoved, lunMoveBackoff, lunMovedNotify); err != nil {
+			return fmt.Errorf("LUN %s was not moved; %v", lunPath, err)
+		}
+	}
+	return nil
+}
+
+// rollBackLUNMoves moves the named LUNs of a group back to the group's source Flexvol, once ONTAP is done
+// with their moves to the destination, and shrinks the destination again.  LUNs that cannot be moved back
+// are logged, since they must be moved back by hand to keep the group together.  The caller must hold the
+// LUN lock, and the shared lock is taken only to start each move back and to shrink the destination.
+func (d *SANEconomyStorageDriver) rollBackLUNMoves(ctx context.Context, move LUNMove, lunNames []string) {
+	for _, lunName := range lunNames {
+		sourcePath := GetLUNPathEconomy(move.SourceFlexvol, lunName)
+		destinationPath := GetLUNPathEconomy(move.DestinationFlexvol, lunName)
+
+		err := d.waitForLUNMoves(ctx, move.DestinationFlexvol, []string{lunName})
+		if err == nil {
+			utils.Lock(ctx, "rebalance", d.sharedLockID)
+			err = d.API.LunMove(ctx, destinationPath, sourcePath)
+			utils.Unlock(ctx, "rebalance", d.sharedLockID)
+		}
+		if err == nil {
+			err = d.waitForLUNMoves(ctx, move.SourceFlexvol, []string{lunName})
+		}
+		if err != nil {
+			Logc(ctx).WithFields(log.Fields{
+				"LUN":                destinationPath,
+				"sourceFlexvol":      move.SourceFlexvol,
+				"destinationFlexvol": move.DestinationFlexvol,
+			}).WithError(err).Error("Could not move LUN back; move it to the source Flexvol to keep it with " +
+				"its LUN or snap-LUNs.")
+		}
+	}
+
+	utils.Lock(ctx, "rebalance", d.sharedLockID)
+	defer utils.Unlock(ctx, "rebalance", d.sharedLockID)
+
+	if err := d.resizeFlexvol(ctx, move.DestinationFlexvol, 0); err != nil {
+		Logc(ctx).WithField("flexvol", move.DestinationFlexvol).WithError(err).Warning(
+			"Could not shrink Flexvol after moving LUNs back.")
+	}
+}
+
+// lunRebalanceTask periodically rebalances LUNs between the bucket Flexvols of a SAN economy driver.
+type lunRebalanceTask struct {
+	Ticker       *time.Ticker
+	InitialDelay time.Duration
+	Done         chan struct{}
+	DryRun       bool
+	Driver       *SANEconomyStorageDriver
+	stopped      bool
+}
+
+// newLUNRebalanceTask returns a rebalance task for the driver, or nil if rebalancing is disabled.
+func newLUNRebalanceTask(ctx context.Context, d *SANEconomyStorageDriver) *lunRebalanceTask {
+	// Read the rebalance period from the config file, use the default if missing or invalid
+	rebalancePeriodSecs := defaultLUNRebalancePeriodSecs
+	if d.Config.LUNRebalancePeriod != "" {
+		i, err := strconv.ParseUint(d.Config.LUNRebalancePeriod, 10, 64)
+		if err != nil {
+			Logc(ctx).WithField("interval", d.Config.LUNRebalancePeriod).Warnf(
+				"Invalid LUN rebalance interval. %v", err)
+		} else {
+			rebalancePeriodSecs = i
+		}
+	}
+
+	if rebalancePeriodSecs == 0 {
+		Logc(ctx).Debug("LUN rebalancing is disabled.")
+		return nil
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"IntervalSeconds": rebalancePeriodSecs,
+		"DryRun":          d.Config.LUNRebalanceDryRun,
+	}).Debug("Configured LUN rebalance period.")
+
+	return &lunRebalanceTask{
+		Ticker:       time.NewTicker(time.Duration(rebalancePeriodSecs) * time.Second),
+		InitialDelay: HousekeepingStartupDelaySecs * time.Second,
+		Done:         make(chan struct{}),
+		DryRun:       d.Config.LUNRebalanceDryRun,
+		Driver:       d,
+	}
+}
+
+func (t *lunRebalanceTask) Start(ctx context.Context) {
+	go func() {
+		select {
+		case <-time.After(t.InitialDelay):
+		case <-t.Done:
+			return
+		}
+		for {
+			select {
+			case tick := <-t.Ticker.C:
+				Logc(ctx).WithFields(log.Fields{
+					"tick":   tick,
+					"driver": t.Driver.Name(),
+				}).Debug("Rebalancing LUNs between Flexvols.")
+				if _, err := t.Driver.RebalanceLUNs(ctx, t.DryRun); err != nil {
+					Logc(ctx).WithError(err).Warning("LUN rebalance failed.")
+				}
+			case <-t.Done:
+				Logc(ctx).WithFields(log.Fields{
+					"driver": t.Driver.Name(),
+				}).Debugf("Shut down LUN rebalancing for the driver.")
+				return
+			}
+		}
+	}()
+}
+
+func (t *lunRebalanceTask) Stop() {
+	if !t.stopped {
+		t.Ticker.Stop()
+		close(t.Done)
+		t.stopped = true
+	}
+}
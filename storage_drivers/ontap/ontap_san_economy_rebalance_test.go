@@ -0,0 +1,365 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package ontap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	tridentconfig "github.com/netapp/trident/config"
+	mockapi "github.com/netapp/trident/mocks/mock_storage_drivers/mock_ontap"
+	"github.com/netapp/trident/storage_drivers/ontap/api"
+	"github.com/netapp/trident/utils"
+)
+
+func newTestRebalanceSanEcoDriver(mockAPI *mockapi.MockOntapAPI) *SANEconomyStorageDriver {
+	mockAPI.EXPECT().SVMName().AnyTimes().Return("SVM1")
+
+	d := newTestOntapSanEcoDriver(ONTAPTEST_LOCALHOST, "0", ONTAPTEST_VSERVER_AGGR_NAME, true, mockAPI)
+	d.helper = NewLUNHelper(d.Config, tridentconfig.ContextCSI)
+	d.flexvolNamePrefix = "trident_lun_pool_test_"
+	d.lunsPerFlexvol = defaultLUNsPerFlexvol
+	return d
+}
+
+func newTestEconomyBucket(name string, groupSizes map[string]uint64) *economyBucket {
+	bucket := &economyBucket{
+		volume: &api.Volume{Name: name},
+		groups: make(map[string]*economyLUNGroup),
+	}
+	for groupName, size := range groupSizes {
+		bucket.groups[groupName] = &economyLUNGroup{
+			name:      groupName,
+			luns:      []string{"test_" + groupName},
+			sizeBytes: size,
+			lunCount:  1,
+		}
+		bucket.sizeBytes += size
+		bucket.lunCount++
+	}
+	return bucket
+}
+
+func TestPlanEconomyBucketMoves(t *testing.T) {
+	fitsAll := func(*economyBucket, *economyLUNGroup) bool { return true }
+
+	tests := []struct {
+		name          string
+		buckets       []*economyBucket
+		fits          func(*economyBucket, *economyLUNGroup) bool
+		expectedMoves []LUNMove
+	}{
+		{
+			name:          "SingleBucket",
+			buckets:       []*economyBucket{newTestEconomyBucket("b1", map[string]uint64{"v1": 10, "v2": 10})},
+			fits:          fitsAll,
+			expectedMoves: []LUNMove{},
+		},
+		{
+			name: "AlreadyBalanced",
+			buckets: []*economyBucket{
+				newTestEconomyBucket("b1", map[string]uint64{"v1": 10, "v2": 10}),
+				newTestEconomyBucket("b2", map[string]uint64{"v3": 10, "v4": 10}),
+			},
+			fits:          fitsAll,
+			expectedMoves: []LUNMove{},
+		},
+		{
+			name: "MoveToEmptierBucket",
+			buckets: []*economyBucket{
+				newTestEconomyBucket("b1", map[string]uint64{"v1": 10, "v2": 10, "v3": 10, "v4": 10}),
+				newTestEconomyBucket("b2", map[string]uint64{}),
+			},
+			fits: fitsAll,
+			expectedMoves: []LUNMove{
+				{Name: "v1", SourceFlexvol: "b1", DestinationFlexvol: "b2", LUNs: []string{"test_v1"}, SizeBytes: 10},
+				{Name: "v2", SourceFlexvol: "b1", DestinationFlexvol: "b2", LUNs: []string{"test_v2"}, SizeBytes: 10},
+			},
+		},
+		{
+			name: "BalanceBySize",
+			buckets: []*economyBucket{
+				newTestEconomyBucket("b1", map[string]uint64{"v1": 40, "v2": 35, "v3": 25}),
+				newTestEconomyBucket("b2", map[string]uint64{"v4": 20}),
+			},
+			fits: fitsAll,
+			expectedMoves: []LUNMove{
+				{Name: "v1", SourceFlexvol: "b1", DestinationFlexvol: "b2", LUNs: []string{"test_v1"}, SizeBytes: 40},
+			},
+		},
+		{
+			name: "DestinationFull",
+			buckets: []*economyBucket{
+				newTestEconomyBucket("b1", map[string]uint64{"v1": 10, "v2": 10, "v3": 10, "v4": 10}),
+				newTestEconomyBucket("b2", map[string]uint64{}),
+			},
+			fits:          func(*economyBucket, *economyLUNGroup) bool { return false },
+			expectedMoves: []LUNMove{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			moves := planEconomyBucketMoves(test.buckets, maxLUNMovesPerRebalance, test.fits)
+			assert.Equal(t, test.expectedMoves, moves)
+		})
+	}
+}
+
+func TestPlanEconomyBucketMoves_MaxMoves(t *testing.T) {
+	fitsAll := func(*economyBucket, *economyLUNGroup) bool { return true }
+	buckets := []*economyBucket{
+		newTestEconomyBucket("b1", map[string]uint64{"v1": 10, "v2": 10, "v3": 10, "v4": 10}),
+		newTestEconomyBucket("b2", map[string]uint64{}),
+	}
+
+	moves := planEconomyBucketMoves(buckets, 1, fitsAll)
+
+	assert.Len(t, moves, 1)
+}
+
+func TestRebalanceLUNs_DryRun(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	d := newTestRebalanceSanEcoDriver(mockAPI)
+
+	volumes := api.Volumes{
+		{Name: "trident_lun_pool_test_A", Aggregates: []string{"aggr1"}},
+		{Name: "trident_lun_pool_test_B", Aggregates: []string{"aggr1"}},
+		{Name: "trident_lun_pool_test_C", Aggregates: []string{"aggr2"}},
+	}
+	mockAPI.EXPECT().VolumeListByPrefix(ctx, "trident_lun_pool_test_").Return(volumes, nil)
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_A/*").Return(api.Luns{
+		{Name: "test_vol1", Size: "1073741824"},
+		{Name: "test_vol1_snapshot_snap1", Size: "1073741824"},
+		{Name: "test_vol2", Size: "1073741824"},
+		{Name: "test_vol3", Size: "1073741824"},
+	}, nil)
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_B/*").Return(api.Luns{}, nil)
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_C/*").Return(api.Luns{}, nil)
+
+	moves, err := d.RebalanceLUNs(ctx, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []LUNMove{
+		{
+			Name:               "vol1",
+			SourceFlexvol:      "trident_lun_pool_test_A",
+			DestinationFlexvol: "trident_lun_pool_test_B",
+			LUNs:               []string{"test_vol1", "test_vol1_snapshot_snap1"},
+			SizeBytes:          2147483648,
+		},
+	}, moves)
+}
+
+func TestRebalanceLUNs(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	d := newTestRebalanceSanEcoDriver(mockAPI)
+
+	volumes := api.Volumes{
+		{Name: "trident_lun_pool_test_A", Aggregates: []string{"aggr1"}},
+		{Name: "trident_lun_pool_test_B", Aggregates: []string{"aggr1"}},
+	}
+	mockAPI.EXPECT().VolumeListByPrefix(ctx, "trident_lun_pool_test_").Return(volumes, nil)
+	// Listed once to plan the moves and again before starting them
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_A/*").Times(2).Return(api.Luns{
+		{Name: "test_vol1", Size: "1073741824"},
+		{Name: "test_vol1_snapshot_snap1", Size: "1073741824"},
+		{Name: "test_vol2", Size: "1073741824"},
+		{Name: "test_vol3", Size: "1073741824"},
+	}, nil)
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_B/*").Return(api.Luns{}, nil)
+
+	// Resizing the Flexvols before and after the move
+	mockAPI.EXPECT().VolumeInfo(ctx, gomock.Any()).AnyTimes().Return(&api.Volume{}, nil)
+	mockAPI.EXPECT().LunList(ctx, gomock.Any()).AnyTimes().Return(api.Luns{
+		{Name: "test_vol2", Size: "1073741824"},
+	}, nil)
+	mockAPI.EXPECT().VolumeSetSize(ctx, gomock.Any(), gomock.Any()).Times(2).Return(nil)
+
+	sharedLockFree := func() bool {
+		acquired := make(chan struct{})
+		go func() {
+			utils.Lock(ctx, "test", d.sharedLockID)
+			utils.Unlock(ctx, "test", d.sharedLockID)
+			close(acquired)
+		}()
+		select {
+		case <-acquired:
+			return true
+		case <-time.After(5 * time.Second):
+			return false
+		}
+	}
+
+	gomock.InOrder(
+		mockAPI.EXPECT().LunMove(ctx, "/vol/trident_lun_pool_test_A/test_vol1",
+			"/vol/trident_lun_pool_test_B/test_vol1").Return(nil),
+		mockAPI.EXPECT().LunMove(ctx, "/vol/trident_lun_pool_test_A/test_vol1_snapshot_snap1",
+			"/vol/trident_lun_pool_test_B/test_vol1_snapshot_snap1").Return(nil),
+		// Other operations must not wait for ONTAP to copy the LUN
+		mockAPI.EXPECT().LunMoveStatus(ctx, "/vol/trident_lun_pool_test_B/test_vol1").DoAndReturn(
+			func(context.Context, string) (bool, error) {
+				assert.True(t, sharedLockFree(), "shared lock held while waiting for LUN move")
+				return false, nil
+			}),
+		mockAPI.EXPECT().LunMoveStatus(ctx, "/vol/trident_lun_pool_test_B/test_vol1").Return(true, nil),
+		mockAPI.EXPECT().LunMoveStatus(ctx, "/vol/trident_lun_pool_test_B/test_vol1_snapshot_snap1").
+			Return(true, nil),
+	)
+
+	moves, err := d.RebalanceLUNs(ctx, false)
+
+	assert.NoError(t, err)
+	assert.Len(t, moves, 1)
+}
+
+func TestRebalanceLUNs_MoveFailedRolledBack(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	d := newTestRebalanceSanEcoDriver(mockAPI)
+
+	volumes := api.Volumes{
+		{Name: "trident_lun_pool_test_A", Aggregates: []string{"aggr1"}},
+		{Name: "trident_lun_pool_test_B", Aggregates: []string{"aggr1"}},
+	}
+	mockAPI.EXPECT().VolumeListByPrefix(ctx, "trident_lun_pool_test_").Return(volumes, nil)
+	// Listed once to plan the moves and again before starting them
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_A/*").Times(2).Return(api.Luns{
+		{Name: "test_vol1", Size: "1073741824"},
+		{Name: "test_vol1_snapshot_snap1", Size: "1073741824"},
+		{Name: "test_vol2", Size: "1073741824"},
+		{Name: "test_vol3", Size: "1073741824"},
+	}, nil)
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_B/*").Return(api.Luns{}, nil)
+	mockAPI.EXPECT().VolumeInfo(ctx, gomock.Any()).AnyTimes().Return(&api.Volume{}, nil)
+	mockAPI.EXPECT().LunList(ctx, gomock.Any()).AnyTimes().Return(api.Luns{}, nil)
+	mockAPI.EXPECT().VolumeSetSize(ctx, gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+
+	// The snap-LUN cannot be moved, so its LUN is moved back to keep them together
+	gomock.InOrder(
+		mockAPI.EXPECT().LunMove(ctx, "/vol/trident_lun_pool_test_A/test_vol1",
+			"/vol/trident_lun_pool_test_B/test_vol1").Return(nil),
+		mockAPI.EXPECT().LunMove(ctx, "/vol/trident_lun_pool_test_A/test_vol1_snapshot_snap1",
+			"/vol/trident_lun_pool_test_B/test_vol1_snapshot_snap1").Return(errors.New("move failed")),
+		mockAPI.EXPECT().LunMoveStatus(ctx, "/vol/trident_lun_pool_test_B/test_vol1").Return(true, nil),
+		mockAPI.EXPECT().LunMove(ctx, "/vol/trident_lun_pool_test_B/test_vol1",
+			"/vol/trident_lun_pool_test_A/test_vol1").Return(nil),
+		mockAPI.EXPECT().LunMoveStatus(ctx, "/vol/trident_lun_pool_test_A/test_vol1").Return(true, nil),
+	)
+
+	moves, err := d.RebalanceLUNs(ctx, false)
+
+	assert.Error(t, err)
+	assert.Empty(t, moves)
+}
+
+func TestRebalanceLUNs_MovePaused(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	d := newTestRebalanceSanEcoDriver(mockAPI)
+
+	volumes := api.Volumes{
+		{Name: "trident_lun_pool_test_A", Aggregates: []string{"aggr1"}},
+		{Name: "trident_lun_pool_test_B", Aggregates: []string{"aggr1"}},
+	}
+	mockAPI.EXPECT().VolumeListByPrefix(ctx, "trident_lun_pool_test_").Return(volumes, nil)
+	// Listed once to plan the moves and again before starting them
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_A/*").Times(2).Return(api.Luns{
+		{Name: "test_vol1", Size: "1073741824"},
+		{Name: "test_vol2", Size: "1073741824"},
+		{Name: "test_vol3", Size: "1073741824"},
+		{Name: "test_vol4", Size: "1073741824"},
+	}, nil)
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_B/*").Return(api.Luns{}, nil)
+	mockAPI.EXPECT().VolumeInfo(ctx, gomock.Any()).AnyTimes().Return(&api.Volume{}, nil)
+	mockAPI.EXPECT().LunList(ctx, gomock.Any()).AnyTimes().Return(api.Luns{}, nil)
+	mockAPI.EXPECT().VolumeSetSize(ctx, gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	mockAPI.EXPECT().LunMove(ctx, "/vol/trident_lun_pool_test_A/test_vol1",
+		"/vol/trident_lun_pool_test_B/test_vol1").Return(nil)
+	// A paused move can be neither finished nor moved back, so the source Flexvol is kept
+	mockAPI.EXPECT().LunMoveStatus(ctx, "/vol/trident_lun_pool_test_B/test_vol1").Times(2).Return(true,
+		errors.New("LUN move paused"))
+
+	moves, err := d.RebalanceLUNs(ctx, false)
+
+	assert.Error(t, err)
+	assert.Empty(t, moves)
+}
+
+func TestRebalanceLUNs_MoveFailed(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	d := newTestRebalanceSanEcoDriver(mockAPI)
+
+	volumes := api.Volumes{
+		{Name: "trident_lun_pool_test_A", Aggregates: []string{"aggr1"}},
+		{Name: "trident_lun_pool_test_B", Aggregates: []string{"aggr1"}},
+	}
+	mockAPI.EXPECT().VolumeListByPrefix(ctx, "trident_lun_pool_test_").Return(volumes, nil)
+	// Listed once to plan the moves and again before starting them
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_A/*").Times(2).Return(api.Luns{
+		{Name: "test_vol1", Size: "1073741824"},
+		{Name: "test_vol2", Size: "1073741824"},
+		{Name: "test_vol3", Size: "1073741824"},
+		{Name: "test_vol4", Size: "1073741824"},
+	}, nil)
+	mockAPI.EXPECT().LunList(ctx, "/vol/trident_lun_pool_test_B/*").Return(api.Luns{}, nil)
+	mockAPI.EXPECT().VolumeInfo(ctx, gomock.Any()).AnyTimes().Return(&api.Volume{}, nil)
+	mockAPI.EXPECT().LunList(ctx, gomock.Any()).AnyTimes().Return(api.Luns{}, nil)
+	mockAPI.EXPECT().VolumeSetSize(ctx, gomock.Any(), gomock.Any()).AnyTimes().Return(nil)
+	mockAPI.EXPECT().LunMove(ctx, gomock.Any(), gomock.Any()).Return(errors.New("move failed"))
+
+	moves, err := d.RebalanceLUNs(ctx, false)
+
+	assert.Error(t, err)
+	assert.Empty(t, moves)
+}
+
+func TestRebalanceLUNs_ListFailed(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	d := newTestRebalanceSanEcoDriver(mockAPI)
+
+	mockAPI.EXPECT().VolumeListByPrefix(ctx, gomock.Any()).Return(nil, errors.New("list failed"))
+
+	moves, err := d.RebalanceLUNs(ctx, true)
+
+	assert.Error(t, err)
+	assert.Nil(t, moves)
+}
+
+func TestNewLUNRebalanceTask(t *testing.T) {
+	ctx := context.Background()
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	d := newTestRebalanceSanEcoDriver(mockAPI)
+
+	assert.Nil(t, newLUNRebalanceTask(ctx, d), "rebalancing should be disabled by default")
+
+	d.Config.LUNRebalancePeriod = "invalid"
+	assert.Nil(t, newLUNRebalanceTask(ctx, d), "invalid period should leave rebalancing disabled")
+
+	d.Config.LUNRebalancePeriod = "3600"
+	d.Config.LUNRebalanceDryRun = true
+	task := newLUNRebalanceTask(ctx, d)
+	assert.NotNil(t, task)
+	assert.True(t, task.DryRun)
+
+	task.Start(ctx)
+	task.Stop()
+	task.Stop()
+	assert.True(t, task.stopped)
+}
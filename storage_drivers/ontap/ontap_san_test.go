@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -780,3 +781,201 @@ func TestOntapSanVolumePublishSLMError(t *testing.T) {
 	err := d.Publish(ctx, volConfig, publishInfo)
 	assert.Errorf(t, err, "no reporting nodes found")
 }
+
+func TestOntapSanVolumePublishDocker(t *testing.T) {
+	ctx := context.Background()
+	originalContext := tridentconfig.CurrentDriverContext
+	tridentconfig.CurrentDriverContext = tridentconfig.ContextDocker
+	defer func() { tridentconfig.CurrentDriverContext = originalContext }()
+
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+
+	mockAPI.EXPECT().SVMName().AnyTimes().Return("SVM1")
+
+	d := newTestOntapSANDriver(ONTAPTEST_LOCALHOST, "0", ONTAPTEST_VSERVER_AGGR_NAME, true, mockAPI)
+	d.API = mockAPI
+	d.ips = []string{"127.0.0.1"}
+	d.Config.IgroupName = "trident"
+
+	volConfig := &storage.VolumeConfig{
+		InternalName: "lunName",
+		Size:         "1g",
+		Encryption:   "false",
+		FileSystem:   "xfs",
+		AccessInfo:   utils.VolumeAccessInfo{PublishEnforcement: true},
+	}
+
+	publishInfo := &utils.VolumePublishInfo{
+		HostName:         "host1",
+		HostIQN:          []string{"host_iqn"},
+		TridentUUID:      "1234",
+		VolumeAccessInfo: utils.VolumeAccessInfo{PublishEnforcement: true},
+	}
+
+	// Each Docker host gets its own igroup, named after the backend's igroup rather than the Trident UUID
+	mockAPI.EXPECT().VolumeInfo(ctx, gomock.Any()).Times(1).Return(&api.Volume{AccessType: VolTypeRW}, nil)
+	mockAPI.EXPECT().IgroupCreate(ctx, "host1-trident", "iscsi", "linux").Times(1).Return(nil)
+	mockAPI.EXPECT().IscsiNodeGetNameRequest(ctx).Times(1).Return("node1", nil)
+	mockAPI.EXPECT().IscsiInterfaceGet(ctx, gomock.Any()).Return([]string{"iscsi_if"}, nil).Times(1)
+	mockAPI.EXPECT().LunGetComment(ctx, "/vol/lunName/lun0")
+	mockAPI.EXPECT().EnsureIgroupAdded(ctx, "host1-trident", "host_iqn").Times(1)
+	mockAPI.EXPECT().EnsureLunMapped(ctx, "host1-trident", "/vol/lunName/lun0", gomock.Any()).Times(1).Return(1, nil)
+	mockAPI.EXPECT().LunMapGetReportingNodes(ctx, gomock.Any(), gomock.Any()).Times(1).Return([]string{"node1"}, nil)
+	mockAPI.EXPECT().GetSLMDataLifs(ctx, gomock.Any(), gomock.Any()).Times(1).Return([]string{"1.1.1.1"}, nil)
+
+	err := d.Publish(ctx, volConfig, publishInfo)
+	assert.NoError(t, err)
+}
+
+func TestOntapSanUnpublishDocker(t *testing.T) {
+	ctx := context.Background()
+	originalContext := tridentconfig.CurrentDriverContext
+	tridentconfig.CurrentDriverContext = tridentconfig.ContextDocker
+	defer func() { tridentconfig.CurrentDriverContext = originalContext }()
+
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+
+	mockAPI.EXPECT().SVMName().AnyTimes().Return("SVM1")
+
+	d := newTestOntapSANDriver(ONTAPTEST_LOCALHOST, "0", ONTAPTEST_VSERVER_AGGR_NAME, true, mockAPI)
+	d.API = mockAPI
+	d.Config.IgroupName = "trident"
+
+	volConfig := &storage.VolumeConfig{
+		InternalName: "foo",
+		AccessInfo:   utils.VolumeAccessInfo{PublishEnforcement: true},
+	}
+	publishInfo := &utils.VolumePublishInfo{HostName: "host1"}
+
+	mockAPI.EXPECT().LunMapInfo(ctx, "host1-trident", "/vol/foo/lun0").Return(1, nil)
+	mockAPI.EXPECT().LunUnmap(ctx, "host1-trident", "/vol/foo/lun0").Return(nil)
+	mockAPI.EXPECT().IgroupListLUNsMapped(ctx, "host1-trident").Return([]string{}, nil)
+	mockAPI.EXPECT().IgroupDestroy(ctx, "host1-trident").Return(nil)
+
+	err := d.Unpublish(ctx, volConfig, publishInfo)
+	assert.NoError(t, err)
+}
+
+func TestOntapSanRevokeNodeAccess(t *testing.T) {
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+
+	mockAPI.EXPECT().SVMName().AnyTimes().Return("SVM1")
+
+	d := newTestOntapSANDriver(ONTAPTEST_LOCALHOST, "0", ONTAPTEST_VSERVER_AGGR_NAME, true, mockAPI)
+	d.API = mockAPI
+
+	// Every LUN is unmapped from the node's igroup before it is deleted
+	igroupName := getNodeSpecificIgroupName("node1", "1234")
+	gomock.InOrder(
+		mockAPI.EXPECT().IgroupListLUNsMapped(ctx, igroupName).Return(
+			[]string{"/vol/foo/lun0", "/vol/bar/lun0"}, nil),
+		mockAPI.EXPECT().LunUnmap(ctx, igroupName, "/vol/foo/lun0").Return(nil),
+		mockAPI.EXPECT().LunUnmap(ctx, igroupName, "/vol/bar/lun0").Return(nil),
+		mockAPI.EXPECT().IgroupDestroy(ctx, igroupName).Return(nil),
+	)
+
+	err := d.RevokeNodeAccess(ctx, &utils.Node{Name: "node1"}, "1234")
+	assert.NoError(t, err)
+
+	// The igroup is kept if a LUN can't be unmapped
+	mockAPI.EXPECT().IgroupListLUNsMapped(ctx, igroupName).Return([]string{"/vol/foo/lun0"}, nil)
+	mockAPI.EXPECT().LunUnmap(ctx, igroupName, "/vol/foo/lun0").Return(fmt.Errorf("failed"))
+
+	err = d.RevokeNodeAccess(ctx, &utils.Node{Name: "node1"}, "1234")
+	assert.Error(t, err)
+}
+
+func TestOntapSanVolumePublications(t *testing.T) {
+	ctx := context.Background()
+
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+
+	mockAPI.EXPECT().SVMName().AnyTimes().Return("SVM1")
+
+	d := newTestOntapSANDriver(ONTAPTEST_LOCALHOST, "0", ONTAPTEST_VSERVER_AGGR_NAME, true, mockAPI)
+	d.API = mockAPI
+
+	volConfig := &storage.VolumeConfig{Name: "vol1", InternalName: "trident_vol1"}
+	lunPath := "/vol/trident_vol1/lun0"
+
+	// No attribute means no publications
+	mockAPI.EXPECT().LunGetAttribute(ctx, lunPath, LUNAttributePublications).Return("", nil)
+	publications, err := d.GetVolumePublications(ctx, volConfig)
+	assert.NoError(t, err)
+	assert.Empty(t, publications)
+
+	// Publications are written as compact JSON while holding the publications lock
+	lockSettleTime := lunPublicationsLockSettleTime
+	lunPublicationsLockSettleTime = 0
+	defer func() { lunPublicationsLockSettleTime = lockSettleTime }()
+
+	attributes := map[string]string{}
+	var locks []string
+	mockAPI.EXPECT().LunGetAttribute(ctx, lunPath, gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _, name string) (string, error) {
+			return attributes[name], nil
+		})
+	mockAPI.EXPECT().LunSetAttribute(ctx, lunPath, gomock.Any(), gomock.Any(), "", "").AnyTimes().DoAndReturn(
+		func(_ context.Context, _, name, value, _, _ string) error {
+			if name == LUNAttributePublicationsLock {
+				locks = append(locks, value)
+			} else if holder, expires := parseLUNPublicationsLease(
+				attributes[LUNAttributePublicationsLock]); holder == "" || !time.Now().Before(expires) {
+				t.Errorf("Publications written without holding the lock")
+			}
+			attributes[name] = value
+			return nil
+		})
+
+	host1 := &utils.VolumePublication{Name: "vol1.host1", VolumeName: "vol1", NodeName: "host1", ReadOnly: true}
+	err = d.AddVolumePublication(ctx, volConfig, host1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"node":"host1","readOnly":true}]`, attributes[LUNAttributePublications])
+
+	// The lock is released by expiring it
+	if assert.Len(t, locks, 2) {
+		holder, _ := parseLUNPublicationsLease(locks[0])
+		assert.Equal(t, holder+" 0", locks[1])
+	}
+
+	// Exclusive publications are refused while another host has the volume
+	host2 := &utils.VolumePublication{Name: "vol1.host2", VolumeName: "vol1", NodeName: "host2"}
+	err = d.AddVolumePublication(ctx, volConfig, host2, true)
+	assert.True(t, utils.IsFoundError(err))
+	assert.Equal(t, `[{"node":"host1","readOnly":true}]`, attributes[LUNAttributePublications])
+
+	err = d.AddVolumePublication(ctx, volConfig, host2, false)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"node":"host1","readOnly":true},{"node":"host2"}]`, attributes[LUNAttributePublications])
+
+	// An empty list is recorded explicitly so the attribute is overwritten
+	assert.NoError(t, d.DeleteVolumePublication(ctx, volConfig, "host1"))
+	assert.NoError(t, d.DeleteVolumePublication(ctx, volConfig, "host2"))
+	assert.Equal(t, "[]", attributes[LUNAttributePublications])
+
+	// A lock held by another host is waited for, but an expired one is taken over
+	attributes[LUNAttributePublicationsLock] = fmt.Sprintf("other %d", time.Now().Add(-time.Second).Unix())
+	err = d.AddVolumePublication(ctx, volConfig, host2, true)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"node":"host2"}]`, attributes[LUNAttributePublications])
+
+	// Recorded publications are expanded with the volume's name
+	attributes[LUNAttributePublications] = `[{"node":"host1"}]`
+	publications, err = d.GetVolumePublications(ctx, volConfig)
+	if assert.NoError(t, err) && assert.Len(t, publications, 1) {
+		assert.Equal(t, utils.GenerateVolumePublishName("vol1", "host1"), publications[0].Name)
+		assert.Equal(t, "vol1", publications[0].VolumeName)
+		assert.Equal(t, "host1", publications[0].NodeName)
+	}
+
+	// Garbage in the attribute is reported
+	attributes[LUNAttributePublications] = "{"
+	_, err = d.GetVolumePublications(ctx, volConfig)
+	assert.Error(t, err)
+}
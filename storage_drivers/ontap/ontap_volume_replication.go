@@ -451,3 +451,36 @@ func getReplicationDetails(
 
 	return snapmirror.ReplicationPolicy, snapmirror.ReplicationSchedule, nil
 }
+
+// getMirrorHealth returns the health and last transfer time of a snapmirror relationship
+func getMirrorHealth(
+	ctx context.Context, localVolumeHandle, remoteVolumeHandle string, d api.OntapAPI,
+) (*storage.MirrorHealth, error) {
+	// Empty remote means there is no mirror to check for
+	if remoteVolumeHandle == "" {
+		return nil, utils.NotFoundError("no remote volume handle")
+	}
+
+	localSVMName, localFlexvolName, err := parseVolumeHandle(localVolumeHandle)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse localVolumeHandle '%v'; %v", localVolumeHandle, err)
+	}
+	remoteSVMName, remoteFlexvolName, err := parseVolumeHandle(remoteVolumeHandle)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse remoteVolumeHandle '%v'; %v", remoteVolumeHandle, err)
+	}
+
+	snapmirror, err := d.SnapmirrorGet(ctx, localFlexvolName, localSVMName, remoteFlexvolName, remoteSVMName)
+	if err != nil {
+		if api.IsNotFoundError(err) {
+			return nil, utils.NotFoundError(err.Error())
+		}
+		return nil, err
+	}
+
+	return &storage.MirrorHealth{
+		Healthy:          snapmirror.IsHealthy,
+		UnhealthyReason:  snapmirror.UnhealthyReason,
+		LastTransferTime: snapmirror.LastTransferEndTime,
+	}, nil
+}
@@ -5,6 +5,7 @@ package ontap
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -392,6 +393,66 @@ func TestGetReplicationDetails_SnapmirrorGetError(t *testing.T) {
 	assert.Error(t, err, "snapmirror get error")
 }
 
+func TestGetMirrorHealth_NoErrors(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	ctx := context.Background()
+	lastTransferTime := time.Unix(1650000000, 0)
+
+	mockAPI.EXPECT().SnapmirrorGet(ctx, localFlexvolName, localSVMName, remoteFlexvolName, remoteSVMName).Times(1).
+		Return(&api.Snapmirror{IsHealthy: false, UnhealthyReason: "transfer failed",
+			LastTransferEndTime: &lastTransferTime}, nil)
+
+	health, err := getMirrorHealth(ctx, localVolumeHandle, remoteVolumeHandle, mockAPI)
+
+	assert.NoError(t, err, "get mirror health should not return an error")
+	assert.False(t, health.Healthy, "mirror should be unhealthy")
+	assert.Equal(t, "transfer failed", health.UnhealthyReason, "reason should match what snapmirror returns")
+	assert.Equal(t, &lastTransferTime, health.LastTransferTime, "transfer time should match what snapmirror returns")
+}
+
+func TestGetMirrorHealth_EmptyRemoteHandle(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	ctx := context.Background()
+
+	health, err := getMirrorHealth(ctx, localVolumeHandle, "", mockAPI)
+
+	assert.Nil(t, health, "health should be empty")
+	assert.True(t, utils.IsNotFoundError(err), "should return a not found error without a remote")
+}
+
+func TestGetMirrorHealth_InvalidVolumeHandle(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	ctx := context.Background()
+
+	_, err := getMirrorHealth(ctx, "pvc-a", remoteVolumeHandle, mockAPI)
+	assert.Error(t, err, "should return an error if cannot parse volume handle")
+
+	_, err = getMirrorHealth(ctx, localVolumeHandle, "pvc-a", mockAPI)
+	assert.Error(t, err, "should return an error if cannot parse volume handle")
+}
+
+func TestGetMirrorHealth_SnapmirrorGetError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
+	ctx := context.Background()
+
+	mockAPI.EXPECT().SnapmirrorGet(ctx, localFlexvolName, localSVMName, remoteFlexvolName, remoteSVMName).Times(1).
+		Return(nil, errNotFound)
+
+	_, err := getMirrorHealth(ctx, localVolumeHandle, remoteVolumeHandle, mockAPI)
+	assert.True(t, utils.IsNotFoundError(err), "snapmirror not found error")
+
+	mockAPI.EXPECT().SnapmirrorGet(ctx, localFlexvolName, localSVMName, remoteFlexvolName, remoteSVMName).Times(1).
+		Return(nil, api.ApiError("snapmirror get error"))
+
+	_, err = getMirrorHealth(ctx, localVolumeHandle, remoteVolumeHandle, mockAPI)
+	assert.Error(t, err, "snapmirror get error")
+	assert.False(t, utils.IsNotFoundError(err), "snapmirror get error")
+}
+
 func TestValidateReplicationConfig_NoErrors(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	mockAPI := mockapi.NewMockOntapAPI(mockCtrl)
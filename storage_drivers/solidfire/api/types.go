@@ -207,11 +207,24 @@ type AddVolumesToVolumeAccessGroupRequest struct {
 	Volumes             []int64 `json:"volumes"`
 }
 
+// RemoveVolumesFromVolumeAccessGroupRequest
+type RemoveVolumesFromVolumeAccessGroupRequest struct {
+	VolumeAccessGroupID int64   `json:"volumeAccessGroupID"`
+	Volumes             []int64 `json:"volumes"`
+}
+
 // CreateVolumeAccessGroupRequest
 type CreateVolumeAccessGroupRequest struct {
-	Name       string   `json:"name"`
-	Volumes    []int64  `json:"volumes,omitempty"`
-	Initiators []string `json:"initiators,omitempty"`
+	Name       string      `json:"name"`
+	Volumes    []int64     `json:"volumes,omitempty"`
+	Initiators []string    `json:"initiators,omitempty"`
+	Attributes interface{} `json:"attributes,omitempty"`
+}
+
+// DeleteVolumeAccessGroupRequest
+type DeleteVolumeAccessGroupRequest struct {
+	VAGID                  int64 `json:"volumeAccessGroupID"`
+	DeleteOrphanInitiators bool  `json:"deleteOrphanInitiators"`
 }
 
 // CreateVolumeAccessGroupResult
@@ -228,6 +241,12 @@ type AddInitiatorsToVolumeAccessGroupRequest struct {
 	VAGID      int64    `json:"volumeAccessGroupID"`
 }
 
+// RemoveInitiatorsFromVolumeAccessGroupRequest
+type RemoveInitiatorsFromVolumeAccessGroupRequest struct {
+	Initiators []string `json:"initiators"`
+	VAGID      int64    `json:"volumeAccessGroupID"`
+}
+
 // ListVolumeAccessGroupsRequest
 type ListVolumeAccessGroupsRequest struct {
 	StartVAGID int64 `json:"startVolumeAccessGroupID,omitempty"`
@@ -53,3 +53,28 @@ func (c *Client) AddInitiatorsToVolumeAccessGroup(
 	}
 	return nil
 }
+
+// RemoveInitiatorsFromVolumeAccessGroup tbd
+func (c *Client) RemoveInitiatorsFromVolumeAccessGroup(
+	ctx context.Context, r *RemoveInitiatorsFromVolumeAccessGroupRequest,
+) error {
+	_, err := c.Request(ctx, "RemoveInitiatorsFromVolumeAccessGroup", r, NewReqID())
+	if err != nil {
+		Logc(ctx).Errorf("Error in RemoveInitiator from VAG API response: %+v", err)
+		return errors.New("failed to remove initiator from VAG")
+	}
+	return nil
+}
+
+// DeleteVolumeAccessGroup tbd
+func (c *Client) DeleteVolumeAccessGroup(ctx context.Context, r *DeleteVolumeAccessGroupRequest) error {
+	_, err := c.Request(ctx, "DeleteVolumeAccessGroup", r, NewReqID())
+	if err != nil {
+		if apiErr, ok := err.(Error); ok && apiErr.Fields.Name == "xVolumeAccessGroupIDDoesNotExist" {
+			return nil
+		}
+		Logc(ctx).Errorf("Error in DeleteVolumeAccessGroup API response: %+v", err)
+		return errors.New("failed to delete VAG")
+	}
+	return nil
+}
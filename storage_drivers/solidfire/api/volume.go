@@ -189,6 +189,21 @@ func (c *Client) AddVolumesToAccessGroup(ctx context.Context, req *AddVolumesToV
 	return err
 }
 
+// RemoveVolumesFromAccessGroup tbd
+func (c *Client) RemoveVolumesFromAccessGroup(
+	ctx context.Context, req *RemoveVolumesFromVolumeAccessGroupRequest,
+) (err error) {
+	_, err = c.Request(ctx, "RemoveVolumesFromVolumeAccessGroup", req, NewReqID())
+	if err != nil {
+		if apiErr, ok := err.(Error); ok && apiErr.Fields.Name == "xNotInVolumeAccessGroup" {
+			return nil
+		}
+		Logc(ctx).Errorf("error response from Remove from VAG request: %+v ", err)
+		return errors.New("device API error")
+	}
+	return err
+}
+
 // DeleteVolume tbd
 func (c *Client) DeleteVolume(ctx context.Context, volumeID int64) (err error) {
 	// TODO(jdg): Add options like purge=True|False, range, ALL etc
@@ -32,6 +32,10 @@ const (
 	sfDefaultMaxIOPS     = 10000
 	sfMinimumAPIVersion  = "8.0"
 
+	// Limits of the Element volumes Trident creates, offered to storage classes by each pool
+	sfMaxVolumeSizeBytes    = 16 * 1024 * 1024 * 1024 * 1024 // 16 TiB
+	sfMaxSnapshotsPerVolume = 32
+
 	// Constants for internal pool attributes
 	Size    = "size"
 	Region  = "region"
@@ -60,6 +64,19 @@ type SANStorageDriver struct {
 	virtualPools map[string]storage.Pool
 }
 
+const (
+	// Attributes identifying the per-node VAGs used for volumes with publish enforcement
+	nodeVAGBackendAttribute = "trident-backend-uuid"
+	nodeVAGNodeAttribute    = "trident-node"
+
+	// publishEnforcementAttribute marks volumes that are only in the VAGs of the nodes they are published to
+	publishEnforcementAttribute = "trident-publish-enforcement"
+
+	maxVAGNameLength = 64
+	// maxVolumeAccessGroups is the most VAGs a volume may belong to
+	maxVolumeAccessGroups = 4
+)
+
 type Telemetry struct {
 	tridentconfig.Telemetry
 	Plugin string `json:"plugin"`
@@ -404,7 +421,9 @@ func (d *SANStorageDriver) populateConfigurationDefaults(
 		}
 	}
 
-	// Force CHAP for Docker & CSI
+	// Force CHAP for Docker & CSI, unless CSI volumes are to be reached through VAGs.  The account's CHAP
+	// secrets reach all of its volumes, so only backends that use VAGs limit volumes to the nodes they are
+	// published to.
 	switch config.DriverContext {
 	case tridentconfig.ContextDocker:
 		if !config.UseCHAP {
@@ -412,7 +431,7 @@ func (d *SANStorageDriver) populateConfigurationDefaults(
 			config.UseCHAP = true
 		}
 	case tridentconfig.ContextCSI:
-		if !config.UseCHAP {
+		if !config.UseCHAP && len(config.AccessGroups) == 0 {
 			Logc(ctx).Info("Enabling CHAP for CSI volumes.")
 			config.UseCHAP = true
 		}
@@ -484,6 +503,11 @@ func (d *SANStorageDriver) initializeStoragePools(ctx context.Context) error {
 			pool.Attributes()[sa.Encryption] = sa.NewBoolOffer(false)
 			pool.Attributes()[sa.Replication] = sa.NewBoolOffer(false)
 			pool.Attributes()[sa.ProvisioningType] = sa.NewStringOffer(sa.Thin)
+			pool.Attributes()[sa.MaxVolumeSize] = drivers.NewMaxVolumeSizeOffer(d.Config.CommonStorageDriverConfig,
+				sfMaxVolumeSizeBytes)
+			pool.Attributes()[sa.MaxSnapshots] = sa.NewIntOffer(0, sfMaxSnapshotsPerVolume)
+			pool.Attributes()[sa.FileSystemTypes] = sa.NewStringOffer(tridentconfig.FsExt3, tridentconfig.FsExt4,
+				tridentconfig.FsXfs, tridentconfig.FsRaw)
 			pool.Attributes()[sa.Labels] = sa.NewLabelOffer(d.Config.Labels)
 
 			if d.Config.Region != "" {
@@ -544,6 +568,11 @@ func (d *SANStorageDriver) initializeStoragePools(ctx context.Context) error {
 			pool.Attributes()[sa.Encryption] = sa.NewBoolOffer(false)
 			pool.Attributes()[sa.Replication] = sa.NewBoolOffer(false)
 			pool.Attributes()[sa.ProvisioningType] = sa.NewStringOffer(sa.Thin)
+			pool.Attributes()[sa.MaxVolumeSize] = drivers.NewMaxVolumeSizeOffer(d.Config.CommonStorageDriverConfig,
+				sfMaxVolumeSizeBytes)
+			pool.Attributes()[sa.MaxSnapshots] = sa.NewIntOffer(0, sfMaxSnapshotsPerVolume)
+			pool.Attributes()[sa.FileSystemTypes] = sa.NewStringOffer(tridentconfig.FsExt3, tridentconfig.FsExt4,
+				tridentconfig.FsXfs, tridentconfig.FsRaw)
 			pool.Attributes()[sa.Labels] = sa.NewLabelOffer(d.Config.Labels, vpool.Labels)
 
 			if region != "" {
@@ -673,10 +702,10 @@ func (d *SANStorageDriver) validate(ctx context.Context) error {
 					return err
 				}
 			}
-		} else if len(d.Config.AccessGroups) > 4 {
+		} else if len(d.Config.AccessGroups) > maxVolumeAccessGroups {
 			err = fmt.Errorf(
-				"the maximum number of allowed Volume Access Groups per config is 4 but your config has specified %d",
-				len(d.Config.AccessGroups))
+				"the maximum number of allowed Volume Access Groups per config is %d but your config has specified %d",
+				maxVolumeAccessGroups, len(d.Config.AccessGroups))
 			return err
 		} else {
 			// We only need this in the case that AccessGroups were specified, if it was zero and we
@@ -697,7 +726,8 @@ func (d *SANStorageDriver) validate(ctx context.Context) error {
 		req.AccountID = d.AccountID
 		volumes, _ := d.Client.ListVolumesForAccount(ctx, &req)
 		for _, v := range volumes {
-			if v.Status != "deleted" {
+			// Volumes with publish enforcement stay out of the backend's VAGs
+			if v.Status != "deleted" && v.GetAttributesAsMap()[publishEnforcementAttribute] != "true" {
 				vIDs = append(vIDs, v.VolumeID)
 			}
 		}
@@ -889,6 +919,9 @@ func (d *SANStorageDriver) Create(
 	}
 
 	meta["fstype"] = fstype
+	if volConfig.AccessInfo.PublishEnforcement {
+		meta[publishEnforcementAttribute] = "true"
+	}
 	if err = d.setProvisioningLabels(ctx, storagePool, meta); err != nil {
 		return err
 	}
@@ -1005,6 +1038,9 @@ func (d *SANStorageDriver) CreateClone(
 
 	svMeta := sourceVolume.GetAttributesAsMap()
 	meta["fstype"] = svMeta["fstype"] // copy the fstype from the source's metadata
+	if cloneVolConfig.AccessInfo.PublishEnforcement {
+		meta[publishEnforcementAttribute] = "true"
+	}
 	if err = d.setProvisioningLabels(ctx, storagePool, meta); err != nil {
 		return err
 	}
@@ -1214,6 +1250,257 @@ func (d *SANStorageDriver) Publish(
 	publishInfo.UseCHAP = true
 	publishInfo.SharedTarget = false
 
+	// Backends using VAGs don't hand out the account's CHAP secrets, which would reach every volume of the account
+	if !d.Config.UseCHAP {
+		publishInfo.IscsiUsername = ""
+		publishInfo.IscsiInitiatorSecret = ""
+		publishInfo.UseCHAP = false
+	}
+
+	// Volumes with publish enforcement are reached through the node's VAG
+	if volConfig.AccessInfo.PublishEnforcement {
+		if len(publishInfo.HostIQN) == 0 {
+			return fmt.Errorf("host %s has no iSCSI initiator name", publishInfo.HostName)
+		}
+		if err = d.publishToNodeVAG(ctx, &v, publishInfo.HostName, publishInfo.HostIQN[0]); err != nil {
+			return fmt.Errorf("could not add volume %s to VAG for node %s; %v", name, publishInfo.HostName, err)
+		}
+	}
+
+	return nil
+}
+
+// publishToNodeVAG adds a volume to a node's VAG.  A volume may only be in a few VAGs, so it can't be published
+// to more nodes than that at once.
+func (d *SANStorageDriver) publishToNodeVAG(ctx context.Context, v *api.Volume, nodeName, iqn string) error {
+	if len(v.VolumeAccessGroups) >= maxVolumeAccessGroups {
+		vags, err := d.getNodeVAGs(ctx)
+		if err != nil {
+			return err
+		}
+		vag, ok := vags[nodeName]
+		if !ok || !utils.SliceContains(v.VolumeAccessGroups, vag.VAGID) {
+			return fmt.Errorf("volume is already published to %d nodes, the most a SolidFire volume allows",
+				len(v.VolumeAccessGroups))
+		}
+	}
+
+	vag, err := d.ensureNodeVAG(ctx, nodeName, iqn)
+	if err != nil {
+		return err
+	}
+	req := api.AddVolumesToVolumeAccessGroupRequest{
+		VolumeAccessGroupID: vag.VAGID,
+		Volumes:             []int64{v.VolumeID},
+	}
+	return d.Client.AddVolumesToAccessGroup(ctx, &req)
+}
+
+// Unpublish the volume from the host specified in publishInfo.  If the volume is only reachable from the nodes
+// it is published to, it is removed from the host's VAG, and the VAG is deleted once it is empty.
+func (d *SANStorageDriver) Unpublish(
+	ctx context.Context, volConfig *storage.VolumeConfig, publishInfo *utils.VolumePublishInfo,
+) error {
+	name := volConfig.InternalName
+
+	if d.Config.DebugTraceFlags["method"] {
+		fields := log.Fields{
+			"Method": "Unpublish",
+			"Type":   "SANStorageDriver",
+			"name":   name,
+		}
+		Logc(ctx).WithFields(fields).Debug(">>>> Unpublish")
+		defer Logc(ctx).WithFields(fields).Debug("<<<< Unpublish")
+	}
+
+	if !volConfig.AccessInfo.PublishEnforcement {
+		// Nothing to do if publish enforcement is not enabled
+		return nil
+	}
+
+	v, err := d.GetVolume(ctx, name)
+	if err != nil {
+		return fmt.Errorf("could not find SolidFire volume %s; %v", name, err)
+	}
+
+	vags, err := d.getNodeVAGs(ctx)
+	if err != nil {
+		return err
+	}
+	vag, ok := vags[publishInfo.HostName]
+	if !ok {
+		return nil
+	}
+
+	remaining := make([]int64, 0, len(vag.Volumes))
+	for _, volumeID := range vag.Volumes {
+		if volumeID != v.VolumeID {
+			remaining = append(remaining, volumeID)
+		}
+	}
+
+	if len(remaining) == 0 {
+		return d.deleteNodeVAG(ctx, vag)
+	}
+	if len(remaining) < len(vag.Volumes) {
+		req := api.RemoveVolumesFromVolumeAccessGroupRequest{
+			VolumeAccessGroupID: vag.VAGID,
+			Volumes:             []int64{v.VolumeID},
+		}
+		if err = d.Client.RemoveVolumesFromAccessGroup(ctx, &req); err != nil {
+			return fmt.Errorf("could not remove volume %s from VAG for node %s; %v", name, publishInfo.HostName, err)
+		}
+	}
+
+	return nil
+}
+
+// EnablePublishEnforcement removes an existing volume from the backend's VAGs, so that it is only reachable
+// from the nodes it is published to.  Any node holding the account's CHAP secrets could still reach the
+// volume, so this is only done on backends that use VAGs.
+func (d *SANStorageDriver) EnablePublishEnforcement(ctx context.Context, volume *storage.Volume) error {
+	// Do not enable publish enforcement on unmanaged imports or backends using CHAP
+	if volume.Config.ImportNotManaged || d.Config.UseCHAP {
+		return nil
+	}
+
+	v, err := d.GetVolume(ctx, volume.Config.InternalName)
+	if err != nil {
+		return fmt.Errorf("could not find SolidFire volume %s; %v", volume.Config.InternalName, err)
+	}
+
+	// Mark the volume first, so it isn't returned to the backend's VAGs if the driver restarts
+	attributes := make(map[string]interface{})
+	if attrs, ok := v.Attributes.(map[string]interface{}); ok {
+		for key, value := range attrs {
+			attributes[key] = value
+		}
+	}
+	attributes[publishEnforcementAttribute] = "true"
+	modifyReq := api.ModifyVolumeRequest{
+		VolumeID:   v.VolumeID,
+		Attributes: attributes,
+	}
+	if err = d.Client.ModifyVolume(ctx, &modifyReq); err != nil {
+		return fmt.Errorf("could not mark volume %s; %v", volume.Config.InternalName, err)
+	}
+
+	for _, vagID := range v.VolumeAccessGroups {
+		req := api.RemoveVolumesFromVolumeAccessGroupRequest{
+			VolumeAccessGroupID: vagID,
+			Volumes:             []int64{v.VolumeID},
+		}
+		if err = d.Client.RemoveVolumesFromAccessGroup(ctx, &req); err != nil {
+			return fmt.Errorf("could not remove volume %s from VAG %d; %v", volume.Config.InternalName, vagID, err)
+		}
+	}
+
+	volume.Config.AccessInfo.IscsiVAGs = nil
+	volume.Config.AccessInfo.PublishEnforcement = true
+	return nil
+}
+
+// RevokeNodeAccess deletes a node's VAG, through which it reaches the volumes with publish enforcement that are
+// published to it.
+func (d *SANStorageDriver) RevokeNodeAccess(ctx context.Context, node *utils.Node, _ string) error {
+	vags, err := d.getNodeVAGs(ctx)
+	if err != nil {
+		return err
+	}
+	if vag, ok := vags[node.Name]; ok {
+		return d.deleteNodeVAG(ctx, vag)
+	}
+	return nil
+}
+
+// backendUUID returns the UUID of the backend this driver was initialized for.
+func (d *SANStorageDriver) backendUUID() string {
+	if d.telemetry == nil {
+		return ""
+	}
+	return d.telemetry.TridentBackendUUID
+}
+
+// getNodeVAGs returns the VAGs this backend created for volumes with publish enforcement, keyed by node name.
+func (d *SANStorageDriver) getNodeVAGs(ctx context.Context) (map[string]api.VolumeAccessGroup, error) {
+	vags, err := d.Client.ListVolumeAccessGroups(ctx, &api.ListVolumeAccessGroupsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve VAGs from SolidFire backend: %+v", err)
+	}
+
+	nodeVAGs := make(map[string]api.VolumeAccessGroup)
+	for _, vag := range vags {
+		attrs, _ := vag.Attributes.(map[string]interface{})
+		if attrs[nodeVAGBackendAttribute] != d.backendUUID() {
+			continue
+		}
+		if nodeName, ok := attrs[nodeVAGNodeAttribute].(string); ok {
+			nodeVAGs[nodeName] = vag
+		}
+	}
+	return nodeVAGs, nil
+}
+
+// ensureNodeVAG returns a node's VAG, creating it or adding the node's initiator to it as needed.
+func (d *SANStorageDriver) ensureNodeVAG(
+	ctx context.Context, nodeName, iqn string,
+) (*api.VolumeAccessGroup, error) {
+	vags, err := d.getNodeVAGs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if vag, ok := vags[nodeName]; ok {
+		if !utils.SliceContainsStringCaseInsensitive(vag.Initiators, iqn) {
+			req := api.AddInitiatorsToVolumeAccessGroupRequest{
+				Initiators: []string{iqn},
+				VAGID:      vag.VAGID,
+			}
+			if err = d.Client.AddInitiatorsToVolumeAccessGroup(ctx, &req); err != nil {
+				return nil, err
+			}
+			vag.Initiators = append(vag.Initiators, iqn)
+		}
+		return &vag, nil
+	}
+
+	name := nodeName
+	if len(name) > maxVAGNameLength {
+		name = name[:maxVAGNameLength]
+	}
+	req := api.CreateVolumeAccessGroupRequest{
+		Name:       name,
+		Initiators: []string{iqn},
+		Attributes: map[string]string{
+			nodeVAGBackendAttribute: d.backendUUID(),
+			nodeVAGNodeAttribute:    nodeName,
+		},
+	}
+	vagID, err := d.Client.CreateVolumeAccessGroup(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	Logc(ctx).WithFields(log.Fields{
+		"node": nodeName,
+		"vag":  vagID,
+	}).Debug("Created VAG for node.")
+
+	return &api.VolumeAccessGroup{Name: name, VAGID: vagID, Initiators: []string{iqn}}, nil
+}
+
+// deleteNodeVAG deletes a node's VAG, along with any initiators not used by other VAGs.
+func (d *SANStorageDriver) deleteNodeVAG(ctx context.Context, vag api.VolumeAccessGroup) error {
+	req := api.DeleteVolumeAccessGroupRequest{
+		VAGID:                  vag.VAGID,
+		DeleteOrphanInitiators: true,
+	}
+	if err := d.Client.DeleteVolumeAccessGroup(ctx, &req); err != nil {
+		return fmt.Errorf("could not delete VAG %s; %v", vag.Name, err)
+	}
+
+	Logc(ctx).WithField("vag", vag.Name).Debug("Deleted VAG for node.")
+
 	return nil
 }
 
@@ -1621,6 +1908,13 @@ func (d *SANStorageDriver) GetInternalVolumeName(ctx context.Context, name strin
 
 func (d *SANStorageDriver) CreatePrepare(ctx context.Context, volConfig *storage.VolumeConfig) {
 	volConfig.InternalName = d.GetInternalVolumeName(ctx, volConfig.Name)
+
+	// New CSI volumes start with publish enforcement on if the backend uses VAGs; imported volumes are moved to
+	// node VAGs on first publish
+	if tridentconfig.CurrentDriverContext == tridentconfig.ContextCSI && !d.Config.UseCHAP &&
+		volConfig.ImportOriginalName == "" {
+		volConfig.AccessInfo.PublishEnforcement = true
+	}
 }
 
 func (d *SANStorageDriver) CreateFollowup(ctx context.Context, volConfig *storage.VolumeConfig) error {
@@ -1657,7 +1951,9 @@ func (d *SANStorageDriver) mapSolidfireLun(ctx context.Context, volConfig *stora
 	volConfig.AccessInfo.IscsiLunNumber = 0
 	volConfig.AccessInfo.IscsiInterface = d.InitiatorIFace
 
-	if d.Config.UseCHAP {
+	if volConfig.AccessInfo.PublishEnforcement {
+		Logc(ctx).WithField("volume", name).Debug("Volume will be added to node VAGs as it is published.")
+	} else if d.Config.UseCHAP {
 		var req api.GetAccountByIDRequest
 		req.AccountID = v.AccountID
 		a, err := d.Client.GetAccountByID(ctx, &req)
@@ -1991,6 +2287,32 @@ func (d *SANStorageDriver) Resize(ctx context.Context, volConfig *storage.Volume
 	return nil
 }
 
+// ProbeHealth verifies that the SolidFire cluster is responding
+func (d *SANStorageDriver) ProbeHealth(ctx context.Context) error {
+	_, err := d.Client.GetClusterCapacity(ctx)
+	return err
+}
+
+// GetPoolUtilization reports the percentage of each pool's performance capacity in use.  All pools share the
+// cluster's IOPS, so each reports the cluster's average IOPS as a percentage of its maximum.
+func (d *SANStorageDriver) GetPoolUtilization(ctx context.Context) (map[string]float64, error) {
+	capacity, err := d.Client.GetClusterCapacity(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	utilization := make(map[string]float64)
+	if capacity == nil || capacity.MaxIOPS <= 0 {
+		return utilization, nil
+	}
+
+	clusterUtilization := 100 * float64(capacity.AverageIOPS) / float64(capacity.MaxIOPS)
+	for name := range d.virtualPools {
+		utilization[name] = clusterUtilization
+	}
+	return utilization, nil
+}
+
 func (d *SANStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*utils.Node, _ string) error {
 	nodeNames := make([]string, 0)
 	for _, node := range nodes {
@@ -2006,6 +2328,56 @@ func (d *SANStorageDriver) ReconcileNodeAccess(ctx context.Context, nodes []*uti
 		defer Logc(ctx).WithFields(fields).Debug("<<<< ReconcileNodeAccess")
 	}
 
+	vags, err := d.getNodeVAGs(ctx)
+	if err != nil {
+		return err
+	}
+
+	nodeMap := make(map[string]*utils.Node, len(nodes))
+	for _, node := range nodes {
+		nodeMap[node.Name] = node
+	}
+
+	for nodeName, vag := range vags {
+		node, ok := nodeMap[nodeName]
+		if !ok {
+			// The node is gone, so its VAG is no longer needed
+			if err = d.deleteNodeVAG(ctx, vag); err != nil {
+				return err
+			}
+			continue
+		}
+		if node.IQN == "" {
+			continue
+		}
+
+		// Replace the VAG's initiators if the node's initiator name has changed
+		if !utils.SliceContainsStringCaseInsensitive(vag.Initiators, node.IQN) {
+			addReq := api.AddInitiatorsToVolumeAccessGroupRequest{
+				Initiators: []string{node.IQN},
+				VAGID:      vag.VAGID,
+			}
+			if err = d.Client.AddInitiatorsToVolumeAccessGroup(ctx, &addReq); err != nil {
+				return fmt.Errorf("could not add initiator to VAG for node %s; %v", nodeName, err)
+			}
+		}
+		stale := make([]string, 0)
+		for _, initiator := range vag.Initiators {
+			if !strings.EqualFold(initiator, node.IQN) {
+				stale = append(stale, initiator)
+			}
+		}
+		if len(stale) > 0 {
+			removeReq := api.RemoveInitiatorsFromVolumeAccessGroupRequest{
+				Initiators: stale,
+				VAGID:      vag.VAGID,
+			}
+			if err = d.Client.RemoveInitiatorsFromVolumeAccessGroup(ctx, &removeReq); err != nil {
+				return fmt.Errorf("could not remove initiators from VAG for node %s; %v", nodeName, err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -4,12 +4,18 @@ package solidfire
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
+	tridentconfig "github.com/netapp/trident/config"
+	"github.com/netapp/trident/storage"
 	drivers "github.com/netapp/trident/storage_drivers"
 	"github.com/netapp/trident/storage_drivers/solidfire/api"
+	"github.com/netapp/trident/utils"
 )
 
 const (
@@ -188,3 +194,339 @@ func TestValidateStoragePrefix(t *testing.T) {
 		})
 	}
 }
+
+// solidfireAPICall is a JSON-RPC request received by a fake SolidFire API.
+type solidfireAPICall struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// newFakeSolidfireAPI starts a JSON-RPC server that answers each method with the given result, and points
+// the driver's client at it.  The requests it receives are returned in order.
+func newFakeSolidfireAPI(
+	t *testing.T, driver *SANStorageDriver, results map[string]interface{},
+) *[]solidfireAPICall {
+	calls := make([]solidfireAPICall, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var call solidfireAPICall
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			t.Errorf("could not decode request; %v", err)
+		}
+		calls = append(calls, call)
+
+		result, ok := results[call.Method]
+		if !ok {
+			result = struct{}{}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "result": result})
+	}))
+	t.Cleanup(server.Close)
+
+	driver.Client.Endpoint = server.URL
+	driver.telemetry = &Telemetry{Telemetry: tridentconfig.Telemetry{TridentBackendUUID: "backend1"}}
+	return &calls
+}
+
+func solidfireAPIMethods(calls *[]solidfireAPICall) []string {
+	methods := make([]string, 0, len(*calls))
+	for _, call := range *calls {
+		methods = append(methods, call.Method)
+	}
+	return methods
+}
+
+func nodeVAG(id int64, nodeName, backendUUID string, initiators []string, volumes []int64) api.VolumeAccessGroup {
+	return api.VolumeAccessGroup{
+		VAGID:      id,
+		Name:       nodeName,
+		Initiators: initiators,
+		Volumes:    volumes,
+		Attributes: map[string]interface{}{
+			nodeVAGBackendAttribute: backendUUID,
+			nodeVAGNodeAttribute:    nodeName,
+		},
+	}
+}
+
+var testSolidfireVolumes = map[string]interface{}{
+	"volumes": []api.Volume{
+		{VolumeID: 10, Name: "vol1", Status: "active", Iqn: "iqn.vol1", VolumeAccessGroups: []int64{1, 2}},
+	},
+}
+
+func TestCreatePrepare_PublishEnforcement(t *testing.T) {
+	driver := newTestSolidfireSANDriver()
+
+	originalContext := tridentconfig.CurrentDriverContext
+	tridentconfig.CurrentDriverContext = tridentconfig.ContextCSI
+	defer func() { tridentconfig.CurrentDriverContext = originalContext }()
+
+	// CHAP secrets reach every volume of the account, so volumes on CHAP backends aren't enforced
+	volConfig := &storage.VolumeConfig{Name: "vol1"}
+	driver.CreatePrepare(ctx(), volConfig)
+	assert.False(t, volConfig.AccessInfo.PublishEnforcement)
+
+	driver.Config.UseCHAP = false
+	volConfig = &storage.VolumeConfig{Name: "vol1"}
+	driver.CreatePrepare(ctx(), volConfig)
+	assert.True(t, volConfig.AccessInfo.PublishEnforcement)
+
+	importConfig := &storage.VolumeConfig{Name: "vol2", ImportOriginalName: "original"}
+	driver.CreatePrepare(ctx(), importConfig)
+	assert.False(t, importConfig.AccessInfo.PublishEnforcement)
+}
+
+func TestPopulateConfigurationDefaults_CSIAccessGroups(t *testing.T) {
+	driver := newTestSolidfireSANDriver()
+	driver.Config.DriverContext = tridentconfig.ContextCSI
+	driver.Config.UseCHAP = false
+
+	// CSI backends use CHAP unless they are given VAGs
+	assert.NoError(t, driver.populateConfigurationDefaults(ctx(), &driver.Config))
+	assert.True(t, driver.Config.UseCHAP)
+
+	driver.Config.UseCHAP = false
+	driver.Config.AccessGroups = []int64{1}
+	assert.NoError(t, driver.populateConfigurationDefaults(ctx(), &driver.Config))
+	assert.False(t, driver.Config.UseCHAP)
+}
+
+func TestPublish_PublishEnforcement(t *testing.T) {
+	driver := newTestSolidfireSANDriver()
+	driver.Config.UseCHAP = false
+	calls := newFakeSolidfireAPI(t, driver, map[string]interface{}{
+		"ListVolumesForAccount":   testSolidfireVolumes,
+		"GetAccountByID":          map[string]interface{}{"account": api.Account{Username: "user", InitiatorSecret: "s"}},
+		"ListVolumeAccessGroups":  map[string]interface{}{"volumeAccessGroups": []api.VolumeAccessGroup{}},
+		"CreateVolumeAccessGroup": map[string]interface{}{"volumeAccessGroupID": 5},
+	})
+
+	volConfig := &storage.VolumeConfig{InternalName: "vol1"}
+	volConfig.AccessInfo.PublishEnforcement = true
+	publishInfo := &utils.VolumePublishInfo{HostName: "node1", HostIQN: []string{"iqn.node1"}}
+
+	err := driver.Publish(ctx(), volConfig, publishInfo)
+
+	assert.NoError(t, err)
+	assert.False(t, publishInfo.UseCHAP, "CHAP used with publish enforcement")
+	assert.Empty(t, publishInfo.IscsiInitiatorSecret)
+	assert.Equal(t, "iqn.vol1", publishInfo.IscsiTargetIQN)
+	assert.Equal(t, []string{
+		"ListVolumesForAccount", "GetAccountByID", "ListVolumeAccessGroups", "CreateVolumeAccessGroup",
+		"AddVolumesToVolumeAccessGroup",
+	}, solidfireAPIMethods(calls))
+
+	var createReq api.CreateVolumeAccessGroupRequest
+	assert.NoError(t, json.Unmarshal((*calls)[3].Params, &createReq))
+	assert.Equal(t, []string{"iqn.node1"}, createReq.Initiators)
+	assert.Equal(t, map[string]interface{}{
+		nodeVAGBackendAttribute: "backend1",
+		nodeVAGNodeAttribute:    "node1",
+	}, createReq.Attributes)
+
+	var addReq api.AddVolumesToVolumeAccessGroupRequest
+	assert.NoError(t, json.Unmarshal((*calls)[4].Params, &addReq))
+	assert.Equal(t, api.AddVolumesToVolumeAccessGroupRequest{VolumeAccessGroupID: 5, Volumes: []int64{10}}, addReq)
+}
+
+func TestPublish_PublishEnforcementVAGLimit(t *testing.T) {
+	vags := []api.VolumeAccessGroup{
+		nodeVAG(5, "node1", "backend1", []string{"iqn.node1"}, []int64{10}),
+		nodeVAG(6, "node2", "backend1", []string{"iqn.node2"}, []int64{10}),
+		nodeVAG(7, "node3", "backend1", []string{"iqn.node3"}, []int64{10}),
+		nodeVAG(8, "node4", "backend1", []string{"iqn.node4"}, []int64{10}),
+	}
+	volumes := map[string]interface{}{
+		"volumes": []api.Volume{
+			{VolumeID: 10, Name: "vol1", Status: "active", Iqn: "iqn.vol1", VolumeAccessGroups: []int64{5, 6, 7, 8}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		node    string
+		wantErr bool
+	}{
+		{name: "NewNode", node: "node5", wantErr: true},
+		{name: "PublishedNode", node: "node2", wantErr: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			driver := newTestSolidfireSANDriver()
+			driver.Config.UseCHAP = false
+			calls := newFakeSolidfireAPI(t, driver, map[string]interface{}{
+				"ListVolumesForAccount":  volumes,
+				"GetAccountByID":         map[string]interface{}{"account": api.Account{Username: "user"}},
+				"ListVolumeAccessGroups": map[string]interface{}{"volumeAccessGroups": vags},
+			})
+
+			volConfig := &storage.VolumeConfig{InternalName: "vol1"}
+			volConfig.AccessInfo.PublishEnforcement = true
+			publishInfo := &utils.VolumePublishInfo{HostName: test.node, HostIQN: []string{"iqn." + test.node}}
+
+			err := driver.Publish(ctx(), volConfig, publishInfo)
+
+			if test.wantErr {
+				// The volume can't join another VAG, so no VAG is created for the node
+				assert.Error(t, err)
+				assert.NotContains(t, solidfireAPIMethods(calls), "CreateVolumeAccessGroup")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPublish_PublishEnforcementNoIQN(t *testing.T) {
+	driver := newTestSolidfireSANDriver()
+	newFakeSolidfireAPI(t, driver, map[string]interface{}{
+		"ListVolumesForAccount": testSolidfireVolumes,
+		"GetAccountByID":        map[string]interface{}{"account": api.Account{Username: "user"}},
+	})
+
+	volConfig := &storage.VolumeConfig{InternalName: "vol1"}
+	volConfig.AccessInfo.PublishEnforcement = true
+
+	err := driver.Publish(ctx(), volConfig, &utils.VolumePublishInfo{HostName: "node1"})
+
+	assert.Error(t, err)
+}
+
+func TestUnpublish_PublishEnforcement(t *testing.T) {
+	tests := []struct {
+		name          string
+		vagVolumes    []int64
+		expectedCalls []string
+	}{
+		{
+			name:          "LastVolume",
+			vagVolumes:    []int64{10},
+			expectedCalls: []string{"ListVolumesForAccount", "ListVolumeAccessGroups", "DeleteVolumeAccessGroup"},
+		},
+		{
+			name:       "OtherVolumes",
+			vagVolumes: []int64{10, 11},
+			expectedCalls: []string{
+				"ListVolumesForAccount", "ListVolumeAccessGroups", "RemoveVolumesFromVolumeAccessGroup",
+			},
+		},
+		{
+			name:          "NotInVAG",
+			vagVolumes:    []int64{11},
+			expectedCalls: []string{"ListVolumesForAccount", "ListVolumeAccessGroups"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			driver := newTestSolidfireSANDriver()
+			calls := newFakeSolidfireAPI(t, driver, map[string]interface{}{
+				"ListVolumesForAccount": testSolidfireVolumes,
+				"ListVolumeAccessGroups": map[string]interface{}{"volumeAccessGroups": []api.VolumeAccessGroup{
+					nodeVAG(5, "node1", "backend1", []string{"iqn.node1"}, test.vagVolumes),
+				}},
+			})
+
+			volConfig := &storage.VolumeConfig{InternalName: "vol1"}
+			volConfig.AccessInfo.PublishEnforcement = true
+
+			err := driver.Unpublish(ctx(), volConfig, &utils.VolumePublishInfo{HostName: "node1"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedCalls, solidfireAPIMethods(calls))
+		})
+	}
+}
+
+func TestEnablePublishEnforcement(t *testing.T) {
+	driver := newTestSolidfireSANDriver()
+	calls := newFakeSolidfireAPI(t, driver, map[string]interface{}{
+		"ListVolumesForAccount": testSolidfireVolumes,
+	})
+
+	volume := &storage.Volume{Config: &storage.VolumeConfig{InternalName: "vol1"}}
+	volume.Config.AccessInfo.IscsiVAGs = []int64{1, 2}
+
+	// Backends using CHAP can't limit who reaches a volume
+	err := driver.EnablePublishEnforcement(ctx(), volume)
+
+	assert.NoError(t, err)
+	assert.False(t, volume.Config.AccessInfo.PublishEnforcement)
+	assert.Empty(t, *calls)
+
+	driver.Config.UseCHAP = false
+	err = driver.EnablePublishEnforcement(ctx(), volume)
+
+	assert.NoError(t, err)
+	assert.True(t, volume.Config.AccessInfo.PublishEnforcement)
+	assert.Empty(t, volume.Config.AccessInfo.IscsiVAGs)
+	assert.Equal(t, []string{
+		"ListVolumesForAccount", "ModifyVolume", "RemoveVolumesFromVolumeAccessGroup",
+		"RemoveVolumesFromVolumeAccessGroup",
+	}, solidfireAPIMethods(calls))
+
+	// The volume is marked so it stays out of the backend's VAGs
+	var modifyReq api.ModifyVolumeRequest
+	assert.NoError(t, json.Unmarshal((*calls)[1].Params, &modifyReq))
+	assert.Equal(t, map[string]interface{}{publishEnforcementAttribute: "true"}, modifyReq.Attributes)
+}
+
+func TestRevokeNodeAccess(t *testing.T) {
+	driver := newTestSolidfireSANDriver()
+	calls := newFakeSolidfireAPI(t, driver, map[string]interface{}{
+		"ListVolumeAccessGroups": map[string]interface{}{"volumeAccessGroups": []api.VolumeAccessGroup{
+			nodeVAG(5, "node1", "backend1", []string{"iqn.node1"}, []int64{10, 11}),
+			nodeVAG(6, "node2", "backend1", []string{"iqn.node2"}, []int64{10}),
+		}},
+	})
+
+	// Only the node's own VAG is deleted
+	err := driver.RevokeNodeAccess(ctx(), &utils.Node{Name: "node1"}, "")
+
+	assert.NoError(t, err)
+	if assert.Equal(t, []string{"ListVolumeAccessGroups", "DeleteVolumeAccessGroup"}, solidfireAPIMethods(calls)) {
+		assert.JSONEq(t, `{"volumeAccessGroupID":5,"deleteOrphanInitiators":true}`, string((*calls)[1].Params))
+	}
+
+	// Nodes without a VAG have nothing to revoke
+	err = driver.RevokeNodeAccess(ctx(), &utils.Node{Name: "node3"}, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, *calls, 3)
+}
+
+func TestReconcileNodeAccess(t *testing.T) {
+	driver := newTestSolidfireSANDriver()
+	calls := newFakeSolidfireAPI(t, driver, map[string]interface{}{
+		"ListVolumeAccessGroups": map[string]interface{}{"volumeAccessGroups": []api.VolumeAccessGroup{
+			nodeVAG(5, "node1", "backend1", []string{"iqn.node1-old"}, []int64{10}),
+			nodeVAG(6, "node2", "backend1", []string{"iqn.node2"}, []int64{11}),
+			nodeVAG(7, "node3", "backend1", []string{"iqn.node3"}, []int64{12}),
+			nodeVAG(8, "node4", "backend2", []string{"iqn.node4"}, []int64{13}),
+			{VAGID: 1, Name: "legacy", Initiators: []string{"iqn.node1-old"}},
+		}},
+	})
+
+	nodes := []*utils.Node{
+		{Name: "node1", IQN: "iqn.node1"},
+		{Name: "node2", IQN: "iqn.node2"},
+	}
+
+	err := driver.ReconcileNodeAccess(ctx(), nodes, "backend1")
+
+	assert.NoError(t, err)
+
+	// node1's initiator is replaced and node3's VAG is deleted; node2 and other backends' VAGs are untouched
+	requests := make(map[string]string)
+	for _, call := range (*calls)[1:] {
+		requests[call.Method] = string(call.Params)
+	}
+	assert.Len(t, *calls, 4)
+	assert.JSONEq(t, `{"initiators":["iqn.node1"],"volumeAccessGroupID":5}`,
+		requests["AddInitiatorsToVolumeAccessGroup"])
+	assert.JSONEq(t, `{"initiators":["iqn.node1-old"],"volumeAccessGroupID":5}`,
+		requests["RemoveInitiatorsFromVolumeAccessGroup"])
+	assert.JSONEq(t, `{"volumeAccessGroupID":7,"deleteOrphanInitiators":true}`,
+		requests["DeleteVolumeAccessGroup"])
+}
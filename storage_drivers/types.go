@@ -76,6 +76,14 @@ type CommonStorageDriverConfig struct {
 	DriverContext     trident.DriverContext `json:"-"`
 	LimitVolumeSize   string                `json:"limitVolumeSize"`
 	Credentials       map[string]string     `json:"credentials"`
+	// MaxConcurrentRequests limits the number of storage API calls Trident makes to the backend at once
+	MaxConcurrentRequests int `json:"maxConcurrentRequests,omitempty"`
+	// MaxRequestsPerSecond limits the rate of storage API calls Trident makes to the backend
+	MaxRequestsPerSecond float64 `json:"maxRequestsPerSecond,omitempty"`
+	// CircuitBreakerThreshold is the number of consecutive unreachable errors after which Trident stops
+	// calling the backend until a probe succeeds; a negative value disables the circuit breaker
+	CircuitBreakerThreshold     int    `json:"circuitBreakerThreshold,omitempty"`
+	CircuitBreakerProbeInterval string `json:"circuitBreakerProbeInterval,omitempty"`
 }
 
 type CommonStorageDriverConfigDefaults struct {
@@ -117,6 +125,8 @@ type OntapStorageDriverConfig struct {
 	QtreeQuotaResizePeriod           string   `json:"qtreeQuotaResizePeriod"`           // in seconds, default to 60
 	QtreesPerFlexvol                 string   `json:"qtreesPerFlexvol"`                 // default to 200
 	LUNsPerFlexvol                   string   `json:"lunsPerFlexvol"`                   // default to 100
+	LUNRebalancePeriod               string   `json:"lunRebalancePeriod"`               // in seconds, default to 0 (disabled)
+	LUNRebalanceDryRun               bool     `json:"lunRebalanceDryRun"`               // report planned moves only
 	EmptyFlexvolDeferredDeletePeriod string   `json:"emptyFlexvolDeferredDeletePeriod"` // in seconds, default to 28800
 	NfsMountOptions                  string   `json:"nfsMountOptions"`
 	LimitAggregateUsage              string   `json:"limitAggregateUsage"`
@@ -435,6 +445,7 @@ type AzureNASStorageDriverConfigDefaults struct {
 	ExportRule      string `json:"exportRule"`
 	SnapshotDir     string `json:"snapshotDir"`
 	UnixPermissions string `json:"unixPermissions"`
+	LUKSEncryption  string `json:"LUKSEncryption"`
 	CommonStorageDriverConfigDefaults
 }
 
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,8 +23,11 @@ import (
 
 var BofUtils = NewBlockOnFileReconcileUtils()
 
+// AttachBlockOnFileVolume mounts the NFS share containing a subvolume, attaches the subvolume's file to a loop
+// device, and prepares the device for use.  If the volume is LUKS encrypted, the loop device is LUKS formatted
+// and opened using the supplied secrets, and the LUKS mapping is returned in place of the loop device.
 func AttachBlockOnFileVolume(
-	ctx context.Context, mountPath string, publishInfo *VolumePublishInfo,
+	ctx context.Context, name, mountPath string, publishInfo *VolumePublishInfo, secrets map[string]string,
 ) (string, string, error) {
 	Logc(ctx).Debug(">>>> bof.AttachBlockOnFileVolume")
 	defer Logc(ctx).Debug("<<<< bof.AttachBlockOnFileVolume")
@@ -93,64 +97,139 @@ func AttachBlockOnFileVolume(
 		}
 	}
 
+	isLUKSDevice, err := isBlockOnFileLUKSVolume(publishInfo)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Layer LUKS on the loop device, so that the data is encrypted before it reaches the NFS share
+	devicePath := loopDevice.Name
+	var luksFormatted bool
+	if isLUKSDevice {
+		if err = ensureDeviceReadableWithRetry(ctx, loopDevice.Name); err != nil {
+			return "", "", err
+		}
+		luksDevice, _ := NewLUKSDevice(loopDevice.Name, name)
+		devicePath, luksFormatted, err = mapBlockOnFileLUKSDevice(ctx, luksDevice, name, secrets)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
 	if fsType != fsRaw {
-		err = ensureDeviceReadableWithRetry(ctx, loopDevice.Name)
+		err = ensureDeviceReadableWithRetry(ctx, devicePath)
 		if err != nil {
 			return "", "", err
 		}
 
-		existingFstype, err := getDeviceFSTypeRetry(ctx, loopDevice.Name)
+		existingFstype, err := getDeviceFSTypeRetry(ctx, devicePath)
 		if err != nil {
 			return "", "", err
 		}
 
 		if existingFstype == "" {
-			if unformatted, err := isDeviceUnformatted(ctx, loopDevice.Name); err != nil {
-				Logc(ctx).WithField("device",
-					loopDevice.Name).Errorf("Unable to identify if the device is unformatted; err: %v", err)
-				return "", "", err
-			} else if !unformatted {
-				Logc(ctx).WithField("device", loopDevice.Name).Errorf("Device is not unformatted; err: %v", err)
-				return "", "", fmt.Errorf("device %v is not unformatted", loopDevice.Name)
+			if !isLUKSDevice {
+				if unformatted, err := isDeviceUnformatted(ctx, devicePath); err != nil {
+					Logc(ctx).WithField("device",
+						devicePath).Errorf("Unable to identify if the device is unformatted; err: %v", err)
+					return "", "", err
+				} else if !unformatted {
+					Logc(ctx).WithField("device", devicePath).Errorf("Device is not unformatted; err: %v", err)
+					return "", "", fmt.Errorf("device %v is not unformatted", devicePath)
+				}
+			} else if !luksFormatted {
+				// We can safely assume if we just luksFormatted the device, we can also add a filesystem without
+				// data loss; otherwise an empty LUKS device may hold data the file system probe cannot see
+				Logc(ctx).WithField("device", devicePath).Error("Unable to identify if the LUKS device is empty.")
+				return "", "", fmt.Errorf("LUKS device %v has no file system and was not newly formatted",
+					devicePath)
 			}
-			Logc(ctx).WithFields(log.Fields{"device": loopDevice.Name, "fsType": fsType}).Debug("Formatting Device.")
-			err := formatVolumeRetry(ctx, loopDevice.Name, fsType)
+			Logc(ctx).WithFields(log.Fields{"device": devicePath, "fsType": fsType}).Debug("Formatting Device.")
+			err := formatVolumeRetry(ctx, devicePath, fsType)
 			if err != nil {
-				return "", "", fmt.Errorf("error formatting device %s: %v", loopDevice.Name, err)
+				return "", "", fmt.Errorf("error formatting device %s: %v", devicePath, err)
 			}
 		} else if existingFstype != unknownFstype && existingFstype != fsType {
 			Logc(ctx).WithFields(log.Fields{
-				"device":          loopDevice.Name,
+				"device":          devicePath,
 				"existingFstype":  existingFstype,
 				"requestedFstype": fsType,
 			}).Error("Device formatted with a different file system type.")
-			return "", "", fmt.Errorf("device %s already formatted with other filesystem: %s", loopDevice.Name,
+			return "", "", fmt.Errorf("device %s already formatted with other filesystem: %s", devicePath,
 				existingFstype)
 		} else {
 			Logc(ctx).WithFields(log.Fields{
-				"device": loopDevice.Name,
+				"device": devicePath,
 				"fstype": fsType,
 			}).Debug("Device already formatted.")
 		}
 	}
 
-	mounted, err := IsMounted(ctx, loopDevice.Name, "", "")
+	mounted, err := IsMounted(ctx, devicePath, "", "")
 	if err != nil {
 		return "", "", err
 	}
 	if !mounted {
-		_ = repairVolume(ctx, loopDevice.Name, fsType)
+		_ = repairVolume(ctx, devicePath, fsType)
 	}
 
 	if deviceMountpoint != "" {
-		err = MountDevice(ctx, loopDevice.Name, deviceMountpoint, deviceOptions, false)
+		err = MountDevice(ctx, devicePath, deviceMountpoint, deviceOptions, false)
 		if err != nil {
 			return "", "", fmt.Errorf("error mounting device %v, mountpoint %v; %s",
-				loopDevice.Name, deviceMountpoint, err)
+				devicePath, deviceMountpoint, err)
 		}
 	}
 
-	return loopDevice.Name, deviceMountpoint, nil
+	return devicePath, deviceMountpoint, nil
+}
+
+// isBlockOnFileLUKSVolume returns whether a subvolume is LUKS encrypted.
+func isBlockOnFileLUKSVolume(publishInfo *VolumePublishInfo) (bool, error) {
+	if publishInfo.LUKSEncryption == "" {
+		return false, nil
+	}
+	isLUKS, err := strconv.ParseBool(publishInfo.LUKSEncryption)
+	if err != nil {
+		return false, fmt.Errorf("could not parse LUKSEncryption into a bool, got %v", publishInfo.LUKSEncryption)
+	}
+	return isLUKS, nil
+}
+
+// mapBlockOnFileLUKSDevice opens a LUKS device on a subvolume's loop device, formatting it first if the loop device
+// is empty, and returns the path of the LUKS mapping and whether it was formatted.
+func mapBlockOnFileLUKSDevice(
+	ctx context.Context, luksDevice LUKSDeviceInterface, name string, secrets map[string]string,
+) (string, bool, error) {
+	luksFormatted, err := EnsureLUKSDeviceMappedOnHost(ctx, luksDevice, name, secrets)
+	if err != nil {
+		return "", false, err
+	}
+	return luksDevice.MappedDevicePath(), luksFormatted, nil
+}
+
+// GetBlockOnFileLoopDevicePath returns the loop device of an attached subvolume.  The device path of a LUKS encrypted
+// subvolume is its LUKS mapping, so the loop device is the one beneath it.
+func GetBlockOnFileLoopDevicePath(ctx context.Context, publishInfo *VolumePublishInfo) (string, error) {
+	isLUKS, err := isBlockOnFileLUKSVolume(publishInfo)
+	if err != nil || !isLUKS {
+		return publishInfo.DevicePath, err
+	}
+	loopDevicePath, err := GetUnderlyingDevicePathForLUKSDevice(ctx, publishInfo.DevicePath)
+	if err != nil {
+		return "", fmt.Errorf("unable to identify loop device for LUKS device '%s'; %v", publishInfo.DevicePath, err)
+	}
+	return loopDevicePath, nil
+}
+
+// CloseBlockOnFileLUKSDevice closes the LUKS mapping of a LUKS encrypted subvolume, which holds its loop device open,
+// so that the loop device may be detached.
+func CloseBlockOnFileLUKSDevice(ctx context.Context, publishInfo *VolumePublishInfo) error {
+	isLUKS, err := isBlockOnFileLUKSVolume(publishInfo)
+	if err != nil || !isLUKS {
+		return err
+	}
+	return EnsureLUKSDeviceClosed(ctx, publishInfo.DevicePath)
 }
 
 func DetachBlockOnFileVolume(ctx context.Context, loopDevice, loopFile string) error {
@@ -0,0 +1,93 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+//go:build linux
+
+package utils
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/netapp/trident/mocks/mock_utils/mock_luks"
+)
+
+func TestMapBlockOnFileLUKSDevice(t *testing.T) {
+	ctx := context.Background()
+	secrets := map[string]string{"luks-passphrase-name": "A", "luks-passphrase": "passphraseA"}
+
+	// An empty loop device is LUKS formatted and its mapping is used in its place
+	mockCtrl := gomock.NewController(t)
+	mockLUKSDevice := mock_luks.NewMockLUKSDeviceInterface(mockCtrl)
+	mockLUKSDevice.EXPECT().EnsureFormattedAndOpen(gomock.Any(), "passphraseA").Return(true, nil)
+	mockLUKSDevice.EXPECT().MappedDevicePath().Return("/dev/mapper/luks-pvc-1")
+
+	devicePath, formatted, err := mapBlockOnFileLUKSDevice(ctx, mockLUKSDevice, "pvc-1", secrets)
+	assert.NoError(t, err)
+	assert.True(t, formatted)
+	assert.Equal(t, "/dev/mapper/luks-pvc-1", devicePath)
+
+	// Without a passphrase the device is not touched
+	mockLUKSDevice = mock_luks.NewMockLUKSDeviceInterface(mockCtrl)
+	_, _, err = mapBlockOnFileLUKSDevice(ctx, mockLUKSDevice, "pvc-1", map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestGetBlockOnFileLoopDevicePath(t *testing.T) {
+	execCmd = fakeExecCommand
+	defer func() {
+		execCmd = exec.CommandContext
+	}()
+	ctx := context.Background()
+
+	// Unencrypted subvolumes are attached directly to their loop device
+	publishInfo := &VolumePublishInfo{DevicePath: "/dev/loop3"}
+	loopDevicePath, err := GetBlockOnFileLoopDevicePath(ctx, publishInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, "/dev/loop3", loopDevicePath)
+
+	// Encrypted subvolumes are attached through their LUKS mapping
+	publishInfo = &VolumePublishInfo{DevicePath: "/dev/mapper/luks-pvc-1", LUKSEncryption: "true"}
+	execReturnCode = 0
+	execReturnValue = "/dev/mapper/luks-pvc-1 is active.\n  type:    LUKS2\n  device:  /dev/loop3\n"
+	loopDevicePath, err = GetBlockOnFileLoopDevicePath(ctx, publishInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, "/dev/loop3", loopDevicePath)
+
+	execReturnCode = 1
+	_, err = GetBlockOnFileLoopDevicePath(ctx, publishInfo)
+	assert.Error(t, err)
+
+	publishInfo.LUKSEncryption = "maybe"
+	_, err = GetBlockOnFileLoopDevicePath(ctx, publishInfo)
+	assert.Error(t, err)
+}
+
+func TestCloseBlockOnFileLUKSDevice(t *testing.T) {
+	execCmd = fakeExecCommand
+	defer func() {
+		execCmd = exec.CommandContext
+		osFs = afero.NewOsFs()
+	}()
+	ctx := context.Background()
+	osFs = afero.NewMemMapFs()
+	_, _ = osFs.Create("/dev/mapper/luks-pvc-1")
+
+	// Unencrypted subvolumes have nothing to close
+	execReturnCode = 1
+	assert.NoError(t, CloseBlockOnFileLUKSDevice(ctx, &VolumePublishInfo{DevicePath: "/dev/loop3"}))
+
+	// The LUKS mapping is closed, so that the loop device can be detached
+	publishInfo := &VolumePublishInfo{DevicePath: "/dev/mapper/luks-pvc-1", LUKSEncryption: "true"}
+	assert.Error(t, CloseBlockOnFileLUKSDevice(ctx, publishInfo))
+
+	execReturnCode = 0
+	assert.NoError(t, CloseBlockOnFileLUKSDevice(ctx, publishInfo))
+
+	publishInfo.LUKSEncryption = "maybe"
+	assert.Error(t, CloseBlockOnFileLUKSDevice(ctx, publishInfo))
+}
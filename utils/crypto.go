@@ -33,14 +33,17 @@ type CertInfo struct {
 	ServerCert string
 	ClientKey  string
 	ClientCert string
+	// The controller's client key and cert identify the controller to nodes, so they must not be given to nodes
+	ControllerKey  string
+	ControllerCert string
 }
 
-// makeHTTPCertInfo generates a CA key and cert, then uses that key to sign two
-// other keys and certs, one for a TLS server and one for a TLS client. None of
+// makeHTTPCertInfo generates a CA key and cert, then uses that key to sign three
+// other keys and certs, one for a TLS server and two for TLS clients. None of
 // the parameters are configurable...the serial numbers and principal names are
 // hardcoded, the validity period is hardcoded to 1970-2070, and the algorithm
 // and key size are hardcoded to 521-bit elliptic curve.
-func MakeHTTPCertInfo(caCertName, serverCertName, clientCertName string) (*CertInfo, error) {
+func MakeHTTPCertInfo(caCertName, serverCertName, clientCertName, controllerCertName string) (*CertInfo, error) {
 	certInfo := &CertInfo{}
 
 	notBefore := time.Unix(0, 0)                      // The Epoch (1970 Jan 1)
@@ -119,26 +122,45 @@ func MakeHTTPCertInfo(caCertName, serverCertName, clientCertName string) (*CertI
 	}
 	certInfo.ServerCert = certToBase64String(derBytes)
 
-	// Create HTTPS client key
-	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), cryptoRand.Reader)
+	// Create HTTPS client key and cert
+	certInfo.ClientKey, certInfo.ClientCert, err = makeClientKeyAndCert(clientCertName, &caCert, caKey)
 	if err != nil {
 		return nil, err
 	}
-	clientKeyBase64, err := keyToBase64String(clientKey)
+
+	// Create HTTPS controller client key and cert
+	certInfo.ControllerKey, certInfo.ControllerCert, err = makeClientKeyAndCert(controllerCertName, &caCert, caKey)
 	if err != nil {
 		return nil, err
 	}
-	certInfo.ClientKey = clientKeyBase64
+
+	return certInfo, nil
+}
+
+// makeClientKeyAndCert generates a key and a TLS client cert signed by the CA, returning both base64-encoded.
+func makeClientKeyAndCert(
+	clientCertName string, caCert *x509.Certificate, caKey *ecdsa.PrivateKey,
+) (string, string, error) {
+	notBefore := time.Unix(0, 0)                      // The Epoch (1970 Jan 1)
+	notAfter := notBefore.Add(time.Hour * 24 * 36525) // 100 years (365.25 days per year)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), cryptoRand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	clientKeyBase64, err := keyToBase64String(clientKey)
+	if err != nil {
+		return "", "", err
+	}
 
 	clientSerial, err := makeSerial()
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
 	clientKeyId, err := bigIntHash(clientKey.D)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-	// Create HTTPS client cert
 	clientCert := x509.Certificate{
 		SerialNumber:   clientSerial,
 		Subject:        makeSubject(clientCertName),
@@ -149,13 +171,12 @@ func MakeHTTPCertInfo(caCertName, serverCertName, clientCertName string) (*CertI
 		SubjectKeyId:   clientKeyId,
 	}
 
-	derBytes, err = x509.CreateCertificate(cryptoRand.Reader, &clientCert, &caCert, &clientKey.PublicKey, caKey)
+	derBytes, err := x509.CreateCertificate(cryptoRand.Reader, &clientCert, caCert, &clientKey.PublicKey, caKey)
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-	certInfo.ClientCert = certToBase64String(derBytes)
 
-	return certInfo, nil
+	return clientKeyBase64, certToBase64String(derBytes), nil
 }
 
 func makeSubject(cn string) pkix.Name {
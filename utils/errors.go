@@ -632,3 +632,27 @@ func HasResourceExhaustedError(err error) (bool, *resourceExhaustedError) {
 	ok := errors.As(err, &resourceExhaustedErrorPtr)
 	return ok, resourceExhaustedErrorPtr
 }
+
+/////////////////////////////////////////////////////////////////////////////
+// backendUnavailableError
+/////////////////////////////////////////////////////////////////////////////
+
+type backendUnavailableError struct {
+	message string
+}
+
+func (e *backendUnavailableError) Error() string { return e.message }
+
+func BackendUnavailableError(message string) error {
+	return &backendUnavailableError{message}
+}
+
+// IsBackendUnavailableError returns true if the error, or any error it wraps, indicates that a backend's
+// storage API is not being called because the backend has stopped responding.
+func IsBackendUnavailableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var backendUnavailableErrorPtr *backendUnavailableError
+	return errors.As(err, &backendUnavailableErrorPtr)
+}
@@ -112,3 +112,40 @@ func TestResourceExhaustedError(t *testing.T) {
 		})
 	}
 }
+
+func TestBackendUnavailableError(t *testing.T) {
+	unavailableErr := BackendUnavailableError("backend is degraded")
+
+	tests := []struct {
+		Name    string
+		Err     error
+		wantErr assert.BoolAssertionFunc
+	}{
+		{
+			Name:    "NilError",
+			Err:     nil,
+			wantErr: assert.False,
+		},
+		{
+			Name:    "NotBackendUnavailableError",
+			Err:     fmt.Errorf("a generic error"),
+			wantErr: assert.False,
+		},
+		{
+			Name:    "BackendUnavailableError",
+			Err:     unavailableErr,
+			wantErr: assert.True,
+		},
+		{
+			Name:    "WrappedBackendUnavailableError",
+			Err:     fmt.Errorf("failed to create volume; %w", unavailableErr),
+			wantErr: assert.True,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			tt.wantErr(t, IsBackendUnavailableError(tt.Err), "Unexpected error")
+		})
+	}
+}
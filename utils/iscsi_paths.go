@@ -0,0 +1,215 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+const (
+	iSCSISessionLoggedIn = "LOGGED_IN"
+	scsiDeviceRunning    = "running"
+	scsiDeviceOffline    = "offline"
+	multipathPathActive  = "active"
+	multipathPathFailed  = "failed"
+)
+
+// ISCSIPath is a SCSI device of an iSCSI LUN, which is one path of the LUN's multipath map.
+type ISCSIPath struct {
+	Device          string // e.g. sdb
+	TargetIQN       string
+	LUN             int32
+	SessionNumber   int
+	SessionState    string // e.g. LOGGED_IN or FAILED
+	DeviceState     string // e.g. running or offline
+	MultipathDevice string // e.g. dm-0, or empty if the path is not part of a multipath map
+	DMState         string // active or failed; empty if multipathd does not know the path
+	CheckerState    string // e.g. ready, ghost or faulty; empty if multipathd does not know the path
+}
+
+// IsHealthy returns whether I/O can be sent down the path.
+func (p ISCSIPath) IsHealthy() bool {
+	return p.DeviceState == scsiDeviceRunning && p.DMState == multipathPathActive && p.checkerUp()
+}
+
+// NeedsReinstatement returns whether the path checker finds the path usable but device mapper still has it failed,
+// which multipathd should correct by itself but sometimes does not.
+func (p ISCSIPath) NeedsReinstatement() bool {
+	return p.DeviceState == scsiDeviceRunning && p.DMState == multipathPathFailed && p.checkerUp()
+}
+
+func (p ISCSIPath) checkerUp() bool {
+	return p.CheckerState == "ready" || p.CheckerState == "ghost"
+}
+
+// SessionLoggedIn returns whether the path's iSCSI session is logged in, so that it may be used to rescan the LUN.
+func (p ISCSIPath) SessionLoggedIn() bool {
+	return p.SessionState == iSCSISessionLoggedIn
+}
+
+// GetISCSIPaths returns every SCSI device attached through an iSCSI session, along with the state of the device, its
+// session, and its multipath path.
+func GetISCSIPaths(ctx context.Context) ([]ISCSIPath, error) {
+	Logc(ctx).Debug(">>>> iscsi_paths.GetISCSIPaths")
+	defer Logc(ctx).Debug("<<<< iscsi_paths.GetISCSIPaths")
+
+	sessionsPath := chrootPathPrefix + "/sys/class/iscsi_session"
+	sessionDirs, err := os.ReadDir(sessionsPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s; %v", sessionsPath, err)
+	}
+
+	pathStates, err := getMultipathPathStates(ctx)
+	if err != nil {
+		// Without multipathd every path looks failed, so don't guess
+		return nil, err
+	}
+
+	paths := make([]ISCSIPath, 0)
+	for _, sessionDir := range sessionDirs {
+		if !strings.HasPrefix(sessionDir.Name(), "session") {
+			continue
+		}
+		sessionNumber, err := strconv.Atoi(strings.TrimPrefix(sessionDir.Name(), "session"))
+		if err != nil {
+			continue
+		}
+		sessionPath := filepath.Join(sessionsPath, sessionDir.Name())
+		targetIQN := readSysfsValue(filepath.Join(sessionPath, "targetname"))
+		sessionState := readSysfsValue(filepath.Join(sessionPath, "state"))
+
+		// SCSI devices are at /sys/class/iscsi_session/sessionN/device/targetH:B:T/H:B:T:L/block/sdX
+		lunPaths, _ := filepath.Glob(filepath.Join(sessionPath, "device", "target*", "*:*:*:*"))
+		for _, lunPath := range lunPaths {
+			hctl := strings.Split(filepath.Base(lunPath), ":")
+			lun, err := strconv.ParseInt(hctl[len(hctl)-1], 10, 32)
+			if err != nil {
+				continue
+			}
+
+			blockDirs, err := os.ReadDir(filepath.Join(lunPath, "block"))
+			if err != nil {
+				continue
+			}
+			for _, blockDir := range blockDirs {
+				device := blockDir.Name()
+				pathState := pathStates[device]
+				paths = append(paths, ISCSIPath{
+					Device:          device,
+					TargetIQN:       targetIQN,
+					LUN:             int32(lun),
+					SessionNumber:   sessionNumber,
+					SessionState:    sessionState,
+					DeviceState:     readSysfsValue(filepath.Join(lunPath, "state")),
+					MultipathDevice: findMultipathDeviceForDevice(ctx, device),
+					DMState:         pathState.dmState,
+					CheckerState:    pathState.checkerState,
+				})
+			}
+		}
+	}
+
+	return paths, nil
+}
+
+func readSysfsValue(path string) string {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(contents))
+}
+
+type multipathPathState struct {
+	dmState      string
+	checkerState string
+}
+
+// getMultipathPathStates returns the device mapper and path checker states multipathd reports for each path.
+func getMultipathPathStates(ctx context.Context) (map[string]multipathPathState, error) {
+	// The checker state is last, since it may contain spaces, e.g. "i/o pending"
+	out, err := execCommandWithTimeout(ctx, "multipathd", 5*time.Second, false, "show", "paths", "format",
+		"%d %t %T")
+	if err != nil {
+		return nil, fmt.Errorf("could not list multipath paths; %v", err)
+	}
+	return parseMultipathPathStates(string(out)), nil
+}
+
+func parseMultipathPathStates(output string) map[string]multipathPathState {
+	states := make(map[string]multipathPathState)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || fields[0] == "dev" {
+			continue
+		}
+		dmState := fields[1]
+		if dmState == "undef" {
+			dmState = ""
+		}
+		states[fields[0]] = multipathPathState{dmState: dmState, checkerState: strings.Join(fields[2:], " ")}
+	}
+	return states
+}
+
+// RescanISCSIPath brings an offline SCSI device back online and rescans it, so that the kernel picks up the state
+// of the LUN from the target again.
+func RescanISCSIPath(ctx context.Context, device string) error {
+	Logc(ctx).WithField("device", device).Debug(">>>> iscsi_paths.RescanISCSIPath")
+	defer Logc(ctx).Debug("<<<< iscsi_paths.RescanISCSIPath")
+
+	devicePath := chrootPathPrefix + "/sys/block/" + device + "/device"
+
+	if readSysfsValue(devicePath+"/state") == scsiDeviceOffline {
+		if err := os.WriteFile(devicePath+"/state", []byte(scsiDeviceRunning), 0o200); err != nil {
+			return fmt.Errorf("could not set device %s running; %v", device, err)
+		}
+		Logc(ctx).WithField("device", device).Info("Set offline iSCSI device running.")
+	}
+
+	return rescanOneLun(ctx, devicePath)
+}
+
+// ReinstateMultipathPath has multipathd use a failed path again.
+func ReinstateMultipathPath(ctx context.Context, device string) error {
+	Logc(ctx).WithField("device", device).Debug(">>>> iscsi_paths.ReinstateMultipathPath")
+	defer Logc(ctx).Debug("<<<< iscsi_paths.ReinstateMultipathPath")
+
+	out, err := execCommandWithTimeout(ctx, "multipathd", 10*time.Second, false, "reinstate", "path", device)
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"device": device,
+			"output": string(out),
+		}).WithError(err).Error("Could not reinstate multipath path.")
+		return fmt.Errorf("could not reinstate multipath path %s; %v", device, err)
+	}
+	return nil
+}
+
+// RemoveISCSIPath removes a path from its multipath map and deletes the SCSI device.  If the LUN is still mapped
+// through the path's session, scanning the session finds it again.
+func RemoveISCSIPath(ctx context.Context, device string) error {
+	Logc(ctx).WithField("device", device).Debug(">>>> iscsi_paths.RemoveISCSIPath")
+	defer Logc(ctx).Debug("<<<< iscsi_paths.RemoveISCSIPath")
+
+	if out, err := execCommandWithTimeout(ctx, "multipathd", 10*time.Second, false, "del", "path",
+		device); err != nil {
+		// The device is deleted regardless, after which multipathd drops the path
+		Logc(ctx).WithFields(log.Fields{
+			"device": device,
+			"output": string(out),
+		}).WithError(err).Warning("Could not remove path from multipathd.")
+	}
+
+	return purgeOneLun(ctx, chrootPathPrefix+"/sys/block/"+device+"/device")
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMultipathPathStates(t *testing.T) {
+	output := "dev dm_st  chk_st\n" +
+		"sdb active ready\n" +
+		"sdc failed faulty\n" +
+		"sdd undef  i/o pending\n" +
+		"\n"
+
+	assert.Equal(t, map[string]multipathPathState{
+		"sdb": {dmState: "active", checkerState: "ready"},
+		"sdc": {dmState: "failed", checkerState: "faulty"},
+		"sdd": {dmState: "", checkerState: "i/o pending"},
+	}, parseMultipathPathStates(output))
+}
+
+func TestISCSIPathStates(t *testing.T) {
+	healthy := ISCSIPath{SessionState: "LOGGED_IN", DeviceState: "running", DMState: "active", CheckerState: "ready"}
+	assert.True(t, healthy.IsHealthy())
+	assert.True(t, healthy.SessionLoggedIn())
+	assert.False(t, healthy.NeedsReinstatement())
+
+	standby := healthy
+	standby.CheckerState = "ghost"
+	assert.True(t, standby.IsHealthy())
+
+	offline := healthy
+	offline.DeviceState = "offline"
+	assert.False(t, offline.IsHealthy())
+
+	failed := healthy
+	failed.DMState = "failed"
+	failed.SessionState = "FAILED"
+	assert.False(t, failed.IsHealthy())
+	assert.False(t, failed.SessionLoggedIn())
+	assert.True(t, failed.NeedsReinstatement())
+
+	faulty := failed
+	faulty.CheckerState = "faulty"
+	assert.False(t, faulty.IsHealthy())
+	assert.False(t, faulty.NeedsReinstatement())
+
+	unknown := healthy
+	unknown.DMState = ""
+	assert.False(t, unknown.IsHealthy())
+	assert.False(t, unknown.NeedsReinstatement())
+}
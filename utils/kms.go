@@ -0,0 +1,415 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	cryptoRand "crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+const (
+	LUKSKMSProviderVaultTransit = "vault-transit"
+	LUKSKMSProviderLocal        = "local"
+
+	// Secret keys that select and configure a key-management service in place of a static LUKS passphrase
+	LUKSKMSProviderKey       = "luks-kms-provider"
+	LUKSKMSKeyNameKey        = "luks-kms-key-name"
+	LUKSKMSVaultAddressKey   = "luks-kms-vault-address"
+	LUKSKMSVaultTokenKey     = "luks-kms-vault-token"
+	LUKSKMSVaultMountKey     = "luks-kms-vault-mount"
+	LUKSKMSVaultNamespaceKey = "luks-kms-vault-namespace"
+	LUKSKMSVaultCACertKey    = "luks-kms-vault-ca-cert"
+	LUKSKMSLocalKeyKey       = "luks-kms-local-key"
+	LUKSKMSLocalPrevKeyKey   = "luks-kms-local-previous-key"
+
+	luksDataKeyLength    = 32
+	defaultVaultMount    = "transit"
+	kmsRequestTimeout    = 30 * time.Second
+	localWrappedKeyLabel = "local"
+)
+
+// LUKSWrappedKey is a LUKS data-encryption key as wrapped by an external key-management service.  Only the
+// wrapped form is ever persisted; the plaintext key exists only in node memory while a device is being opened.
+type LUKSWrappedKey struct {
+	Provider   string `json:"provider"`
+	KeyName    string `json:"keyName"`
+	WrappedKey string `json:"wrappedKey"`
+	Revoked    bool   `json:"revoked,omitempty"`
+}
+
+// LUKSWrappedKeyUpdate replaces a volume's wrapped key, as long as the key it replaces is still the volume's key.
+type LUKSWrappedKeyUpdate struct {
+	LUKSWrappedKey
+	// PreviousWrappedKey is the wrapped key being replaced, or empty if the volume has no key yet
+	PreviousWrappedKey string `json:"previousWrappedKey,omitempty"`
+}
+
+// KeyManagementService wraps and unwraps LUKS data-encryption keys with a key-encryption key held by a KMS.
+type KeyManagementService interface {
+	Provider() string
+	// GenerateDataKey returns a new data-encryption key along with its wrapped form.
+	GenerateDataKey(ctx context.Context, keyName string) ([]byte, string, error)
+	// WrapDataKey wraps an existing data-encryption key with the named key-encryption key.
+	WrapDataKey(ctx context.Context, keyName string, dataKey []byte) (string, error)
+	// UnwrapDataKey returns the plaintext data-encryption key; it fails if the KMS has revoked access.
+	UnwrapDataKey(ctx context.Context, keyName, wrappedKey string) ([]byte, error)
+	// RewrapDataKey re-wraps a data-encryption key with the latest version of the key-encryption key,
+	// returning the wrapped key unchanged if it is already current.
+	RewrapDataKey(ctx context.Context, keyName, wrappedKey string) (string, error)
+}
+
+// IsLUKSKMSConfigured returns whether the secret map selects a key-management service for LUKS keys.
+func IsLUKSKMSConfigured(secrets map[string]string) bool {
+	return secrets[LUKSKMSProviderKey] != ""
+}
+
+// NewKeyManagementService returns the key-management service configured in the secret map.
+func NewKeyManagementService(secrets map[string]string) (KeyManagementService, error) {
+	if secrets[LUKSKMSKeyNameKey] == "" {
+		return nil, fmt.Errorf("%s must be specified", LUKSKMSKeyNameKey)
+	}
+
+	switch provider := secrets[LUKSKMSProviderKey]; provider {
+	case LUKSKMSProviderVaultTransit:
+		return newVaultTransitKMS(secrets)
+	case LUKSKMSProviderLocal:
+		return newLocalKMS(secrets)
+	default:
+		return nil, UnsupportedError(fmt.Sprintf("unsupported LUKS key-management provider '%s'; must be one of %s",
+			provider, strings.Join([]string{LUKSKMSProviderVaultTransit, LUKSKMSProviderLocal}, ", ")))
+	}
+}
+
+// GetLUKSSecretsForDataKey returns a secret map holding the LUKS passphrase derived from a data-encryption key.
+// The passphrase name is a fingerprint of the key, so it is stable for the lifetime of the key.
+func GetLUKSSecretsForDataKey(dataKey []byte) map[string]string {
+	fingerprint := sha256.Sum256(dataKey)
+	return map[string]string{
+		"luks-passphrase":      base64.StdEncoding.EncodeToString(dataKey),
+		"luks-passphrase-name": "kms-" + hex.EncodeToString(fingerprint[:8]),
+	}
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Vault transit secrets engine
+// ---------------------------------------------------------------------------------------------------------------------
+
+type vaultTransitKMS struct {
+	address   string
+	token     string
+	mount     string
+	namespace string
+	client    *http.Client
+}
+
+func newVaultTransitKMS(secrets map[string]string) (*vaultTransitKMS, error) {
+	v := &vaultTransitKMS{
+		address:   strings.TrimSuffix(secrets[LUKSKMSVaultAddressKey], "/"),
+		token:     secrets[LUKSKMSVaultTokenKey],
+		mount:     strings.Trim(secrets[LUKSKMSVaultMountKey], "/"),
+		namespace: secrets[LUKSKMSVaultNamespaceKey],
+	}
+	if v.address == "" {
+		return nil, fmt.Errorf("%s must be specified", LUKSKMSVaultAddressKey)
+	}
+	if v.token == "" {
+		return nil, fmt.Errorf("%s must be specified", LUKSKMSVaultTokenKey)
+	}
+	if v.mount == "" {
+		v.mount = defaultVaultMount
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caCert := secrets[LUKSKMSVaultCACertKey]; caCert != "" {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, fmt.Errorf("could not parse %s", LUKSKMSVaultCACertKey)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+	v.client = &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		Timeout:   kmsRequestTimeout,
+	}
+
+	return v, nil
+}
+
+func (v *vaultTransitKMS) Provider() string {
+	return LUKSKMSProviderVaultTransit
+}
+
+// invoke sends a request to the transit engine and returns the "data" object of the response.
+func (v *vaultTransitKMS) invoke(
+	ctx context.Context, method, path string, request interface{},
+) (map[string]interface{}, error) {
+	var body io.Reader
+	if request != nil {
+		requestBytes, err := json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(requestBytes)
+	}
+
+	url := v.address + "/v1/" + v.mount + "/" + path
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Content-Type", "application/json")
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	Logc(ctx).WithFields(log.Fields{"method": method, "url": url}).Debug("Invoking Vault transit API.")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not communicate with Vault; %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var response struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []string               `json:"errors"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("could not parse Vault response; %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Vault returned status %d; %s", resp.StatusCode, strings.Join(response.Errors, "; "))
+	}
+
+	return response.Data, nil
+}
+
+func (v *vaultTransitKMS) GenerateDataKey(ctx context.Context, keyName string) ([]byte, string, error) {
+	data, err := v.invoke(ctx, http.MethodPost, "datakey/plaintext/"+keyName,
+		map[string]interface{}{"bits": luksDataKeyLength * 8})
+	if err != nil {
+		return nil, "", fmt.Errorf("could not generate data key with Vault key %s; %v", keyName, err)
+	}
+	plaintext, ok := data["plaintext"].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("Vault response did not include a plaintext data key")
+	}
+	ciphertext, ok := data["ciphertext"].(string)
+	if !ok {
+		return nil, "", fmt.Errorf("Vault response did not include a wrapped data key")
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(plaintext)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not decode data key; %v", err)
+	}
+	return dataKey, ciphertext, nil
+}
+
+func (v *vaultTransitKMS) WrapDataKey(ctx context.Context, keyName string, dataKey []byte) (string, error) {
+	data, err := v.invoke(ctx, http.MethodPost, "encrypt/"+keyName,
+		map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dataKey)})
+	if err != nil {
+		return "", fmt.Errorf("could not wrap data key with Vault key %s; %v", keyName, err)
+	}
+	ciphertext, ok := data["ciphertext"].(string)
+	if !ok {
+		return "", fmt.Errorf("Vault response did not include a wrapped data key")
+	}
+	return ciphertext, nil
+}
+
+func (v *vaultTransitKMS) UnwrapDataKey(ctx context.Context, keyName, wrappedKey string) ([]byte, error) {
+	data, err := v.invoke(ctx, http.MethodPost, "decrypt/"+keyName, map[string]string{"ciphertext": wrappedKey})
+	if err != nil {
+		return nil, fmt.Errorf("could not unwrap data key with Vault key %s; %v", keyName, err)
+	}
+	plaintext, ok := data["plaintext"].(string)
+	if !ok {
+		return nil, fmt.Errorf("Vault response did not include a plaintext data key")
+	}
+	dataKey, err := base64.StdEncoding.DecodeString(plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode data key; %v", err)
+	}
+	return dataKey, nil
+}
+
+func (v *vaultTransitKMS) RewrapDataKey(ctx context.Context, keyName, wrappedKey string) (string, error) {
+	// Vault ciphertext is of the form "vault:v<version>:<ciphertext>"
+	parts := strings.SplitN(wrappedKey, ":", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[1], "v") {
+		return "", fmt.Errorf("invalid Vault wrapped key")
+	}
+	version, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil {
+		return "", fmt.Errorf("invalid Vault wrapped key version; %v", err)
+	}
+
+	data, err := v.invoke(ctx, http.MethodGet, "keys/"+keyName, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not read Vault key %s; %v", keyName, err)
+	}
+	latestVersion, ok := data["latest_version"].(float64)
+	if !ok {
+		return "", fmt.Errorf("Vault response did not include the latest key version")
+	}
+	if version >= int(latestVersion) {
+		return wrappedKey, nil
+	}
+
+	data, err = v.invoke(ctx, http.MethodPost, "rewrap/"+keyName, map[string]string{"ciphertext": wrappedKey})
+	if err != nil {
+		return "", fmt.Errorf("could not rewrap data key with Vault key %s; %v", keyName, err)
+	}
+	ciphertext, ok := data["ciphertext"].(string)
+	if !ok {
+		return "", fmt.Errorf("Vault response did not include a wrapped data key")
+	}
+	return ciphertext, nil
+}
+
+// ---------------------------------------------------------------------------------------------------------------------
+// Local stand-in
+// ---------------------------------------------------------------------------------------------------------------------
+
+// localKMS wraps data keys with AES-256-GCM using a master key supplied in the secret map.  It stands in for a
+// real key-management service in test environments and offers none of the protection of an external KMS.
+type localKMS struct {
+	key         []byte
+	previousKey []byte
+}
+
+func newLocalKMS(secrets map[string]string) (*localKMS, error) {
+	key, err := decodeLocalKMSKey(secrets[LUKSKMSLocalKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s; %v", LUKSKMSLocalKeyKey, err)
+	}
+	l := &localKMS{key: key}
+
+	if secrets[LUKSKMSLocalPrevKeyKey] != "" {
+		if l.previousKey, err = decodeLocalKMSKey(secrets[LUKSKMSLocalPrevKeyKey]); err != nil {
+			return nil, fmt.Errorf("invalid %s; %v", LUKSKMSLocalPrevKeyKey, err)
+		}
+	}
+	return l, nil
+}
+
+func decodeLocalKMSKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != luksDataKeyLength {
+		return nil, fmt.Errorf("key must be %d bytes", luksDataKeyLength)
+	}
+	return key, nil
+}
+
+// localKeyID identifies a master key in wrapped keys without revealing it.
+func localKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+func (l *localKMS) Provider() string {
+	return LUKSKMSProviderLocal
+}
+
+func (l *localKMS) GenerateDataKey(ctx context.Context, keyName string) ([]byte, string, error) {
+	dataKey := make([]byte, luksDataKeyLength)
+	if _, err := io.ReadFull(cryptoRand.Reader, dataKey); err != nil {
+		return nil, "", err
+	}
+	wrappedKey, err := l.WrapDataKey(ctx, keyName, dataKey)
+	if err != nil {
+		return nil, "", err
+	}
+	return dataKey, wrappedKey, nil
+}
+
+// WrapDataKey returns "local:<key ID>:<base64 nonce and ciphertext>", binding the key name as additional data.
+func (l *localKMS) WrapDataKey(_ context.Context, keyName string, dataKey []byte) (string, error) {
+	gcm, err := newGCM(l.key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(cryptoRand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, dataKey, []byte(keyName))
+	return strings.Join([]string{localWrappedKeyLabel, localKeyID(l.key),
+		base64.StdEncoding.EncodeToString(sealed)}, ":"), nil
+}
+
+func (l *localKMS) UnwrapDataKey(_ context.Context, keyName, wrappedKey string) ([]byte, error) {
+	parts := strings.SplitN(wrappedKey, ":", 3)
+	if len(parts) != 3 || parts[0] != localWrappedKeyLabel {
+		return nil, fmt.Errorf("invalid local wrapped key")
+	}
+
+	var key []byte
+	if parts[1] == localKeyID(l.key) {
+		key = l.key
+	} else if l.previousKey != nil && parts[1] == localKeyID(l.previousKey) {
+		key = l.previousKey
+	} else {
+		return nil, fmt.Errorf("wrapped key was not wrapped by a known local key")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode wrapped key; %v", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	dataKey, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(keyName))
+	if err != nil {
+		return nil, fmt.Errorf("could not unwrap data key; %v", err)
+	}
+	return dataKey, nil
+}
+
+func (l *localKMS) RewrapDataKey(ctx context.Context, keyName, wrappedKey string) (string, error) {
+	if strings.HasPrefix(wrappedKey, localWrappedKeyLabel+":"+localKeyID(l.key)+":") {
+		return wrappedKey, nil
+	}
+	dataKey, err := l.UnwrapDataKey(ctx, keyName, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+	return l.WrapDataKey(ctx, keyName, dataKey)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
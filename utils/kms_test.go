@@ -0,0 +1,207 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func localKMSSecrets(t *testing.T) map[string]string {
+	key, err := GenerateAESKey()
+	assert.NoError(t, err)
+	return map[string]string{
+		LUKSKMSProviderKey: LUKSKMSProviderLocal,
+		LUKSKMSKeyNameKey:  "trident",
+		LUKSKMSLocalKeyKey: key,
+	}
+}
+
+func TestNewKeyManagementService(t *testing.T) {
+	assert.False(t, IsLUKSKMSConfigured(map[string]string{"luks-passphrase": "secret"}))
+
+	secrets := localKMSSecrets(t)
+	assert.True(t, IsLUKSKMSConfigured(secrets))
+	kms, err := NewKeyManagementService(secrets)
+	assert.NoError(t, err)
+	assert.Equal(t, LUKSKMSProviderLocal, kms.Provider())
+
+	// Key name is required
+	_, err = NewKeyManagementService(map[string]string{LUKSKMSProviderKey: LUKSKMSProviderLocal})
+	assert.Error(t, err)
+
+	// Unknown provider
+	_, err = NewKeyManagementService(map[string]string{LUKSKMSProviderKey: "kmip", LUKSKMSKeyNameKey: "trident"})
+	assert.True(t, IsUnsupportedError(err))
+
+	// Vault requires an address and token
+	_, err = NewKeyManagementService(map[string]string{
+		LUKSKMSProviderKey: LUKSKMSProviderVaultTransit, LUKSKMSKeyNameKey: "trident",
+	})
+	assert.Error(t, err)
+
+	// Local key must be 32 bytes
+	secrets[LUKSKMSLocalKeyKey] = base64.StdEncoding.EncodeToString([]byte("short"))
+	_, err = NewKeyManagementService(secrets)
+	assert.Error(t, err)
+}
+
+func TestLocalKMS_WrapUnwrap(t *testing.T) {
+	ctx := context.Background()
+	kms, err := NewKeyManagementService(localKMSSecrets(t))
+	assert.NoError(t, err)
+
+	dataKey, wrappedKey, err := kms.GenerateDataKey(ctx, "trident")
+	assert.NoError(t, err)
+	assert.Len(t, dataKey, 32)
+	assert.NotContains(t, wrappedKey, base64.StdEncoding.EncodeToString(dataKey))
+
+	unwrapped, err := kms.UnwrapDataKey(ctx, "trident", wrappedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// The key name is bound to the wrapped key
+	_, err = kms.UnwrapDataKey(ctx, "other", wrappedKey)
+	assert.Error(t, err)
+
+	// A key wrapped by a different master key cannot be unwrapped
+	otherKMS, err := NewKeyManagementService(localKMSSecrets(t))
+	assert.NoError(t, err)
+	_, err = otherKMS.UnwrapDataKey(ctx, "trident", wrappedKey)
+	assert.Error(t, err)
+
+	// Already current, so rewrap is a no-op
+	rewrapped, err := kms.RewrapDataKey(ctx, "trident", wrappedKey)
+	assert.NoError(t, err)
+	assert.Equal(t, wrappedKey, rewrapped)
+}
+
+func TestLocalKMS_Rotation(t *testing.T) {
+	ctx := context.Background()
+	oldSecrets := localKMSSecrets(t)
+	oldKMS, err := NewKeyManagementService(oldSecrets)
+	assert.NoError(t, err)
+	dataKey, wrappedKey, err := oldKMS.GenerateDataKey(ctx, "trident")
+	assert.NoError(t, err)
+
+	newSecrets := localKMSSecrets(t)
+	newSecrets[LUKSKMSLocalPrevKeyKey] = oldSecrets[LUKSKMSLocalKeyKey]
+	newKMS, err := NewKeyManagementService(newSecrets)
+	assert.NoError(t, err)
+
+	rewrapped, err := newKMS.RewrapDataKey(ctx, "trident", wrappedKey)
+	assert.NoError(t, err)
+	assert.NotEqual(t, wrappedKey, rewrapped)
+
+	unwrapped, err := newKMS.UnwrapDataKey(ctx, "trident", rewrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	// Once the previous key is retired, only the rewrapped key can be opened
+	delete(newSecrets, LUKSKMSLocalPrevKeyKey)
+	retiredKMS, err := NewKeyManagementService(newSecrets)
+	assert.NoError(t, err)
+	_, err = retiredKMS.UnwrapDataKey(ctx, "trident", wrappedKey)
+	assert.Error(t, err)
+	_, err = retiredKMS.UnwrapDataKey(ctx, "trident", rewrapped)
+	assert.NoError(t, err)
+}
+
+func TestVaultTransitKMS(t *testing.T) {
+	ctx := context.Background()
+	dataKey := []byte(strings.Repeat("k", 32))
+	encodedKey := base64.StdEncoding.EncodeToString(dataKey)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+			return
+		}
+		var request map[string]interface{}
+		if r.Body != nil {
+			_ = json.NewDecoder(r.Body).Decode(&request)
+		}
+
+		var data map[string]interface{}
+		switch r.URL.Path {
+		case "/v1/transit/datakey/plaintext/trident":
+			data = map[string]interface{}{"plaintext": encodedKey, "ciphertext": "vault:v1:wrapped"}
+		case "/v1/transit/encrypt/trident":
+			data = map[string]interface{}{"ciphertext": "vault:v2:wrapped"}
+		case "/v1/transit/decrypt/trident":
+			if request["ciphertext"] == "vault:v1:revoked" {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"key is disabled"}})
+				return
+			}
+			data = map[string]interface{}{"plaintext": encodedKey}
+		case "/v1/transit/keys/trident":
+			data = map[string]interface{}{"latest_version": 2}
+		case "/v1/transit/rewrap/trident":
+			data = map[string]interface{}{"ciphertext": "vault:v2:rewrapped"}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer server.Close()
+
+	kms, err := NewKeyManagementService(map[string]string{
+		LUKSKMSProviderKey:     LUKSKMSProviderVaultTransit,
+		LUKSKMSKeyNameKey:      "trident",
+		LUKSKMSVaultAddressKey: server.URL,
+		LUKSKMSVaultTokenKey:   "token",
+	})
+	assert.NoError(t, err)
+
+	generated, wrappedKey, err := kms.GenerateDataKey(ctx, "trident")
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, generated)
+	assert.Equal(t, "vault:v1:wrapped", wrappedKey)
+
+	wrappedKey, err = kms.WrapDataKey(ctx, "trident", dataKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "vault:v2:wrapped", wrappedKey)
+
+	unwrapped, err := kms.UnwrapDataKey(ctx, "trident", "vault:v1:wrapped")
+	assert.NoError(t, err)
+	assert.Equal(t, dataKey, unwrapped)
+
+	_, err = kms.UnwrapDataKey(ctx, "trident", "vault:v1:revoked")
+	assert.Error(t, err)
+
+	rewrapped, err := kms.RewrapDataKey(ctx, "trident", "vault:v1:wrapped")
+	assert.NoError(t, err)
+	assert.Equal(t, "vault:v2:rewrapped", rewrapped)
+
+	rewrapped, err = kms.RewrapDataKey(ctx, "trident", "vault:v2:wrapped")
+	assert.NoError(t, err)
+	assert.Equal(t, "vault:v2:wrapped", rewrapped)
+
+	_, err = kms.RewrapDataKey(ctx, "trident", "garbage")
+	assert.Error(t, err)
+
+	_, err = kms.UnwrapDataKey(ctx, "unknown", "vault:v1:wrapped")
+	assert.Error(t, err)
+}
+
+func TestGetLUKSSecretsForDataKey(t *testing.T) {
+	dataKey := []byte(strings.Repeat("k", 32))
+	secrets := GetLUKSSecretsForDataKey(dataKey)
+
+	name, passphrase, _, _ := GetLUKSPassphrasesFromSecretMap(secrets)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(dataKey), passphrase)
+	assert.True(t, strings.HasPrefix(name, "kms-"))
+	assert.Equal(t, name, GetLUKSSecretsForDataKey(dataKey)["luks-passphrase-name"])
+	assert.NotEqual(t, name, GetLUKSSecretsForDataKey([]byte(strings.Repeat("j", 32)))["luks-passphrase-name"])
+}
@@ -0,0 +1,463 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+const (
+	multipathConfigFile       = "/etc/multipath.conf"
+	multipathDefaultConfigDir = "/etc/multipath/conf.d"
+	multipathDropInFilename   = "trident.conf"
+)
+
+// hostRootPath is where the node plugin sees the host's root filesystem.  Files Trident manages on the host are
+// written there, while the commands it runs are already chrooted to the host.
+var hostRootPath = "/host"
+
+// multipathConfigSection is a section of a multipath configuration, such as defaults, or a device within devices.
+type multipathConfigSection struct {
+	name       string
+	attributes map[string][]string // Blacklist sections may repeat attributes, e.g. devnode
+	children   []*multipathConfigSection
+}
+
+// multipathVendor describes the LUNs of a NetApp storage system, and the multipath settings Trident expects for them.
+type multipathVendor struct {
+	name       string
+	vendor     string
+	product    string // A representative product, to evaluate device sections
+	productExp string // The product expression of the device section in Trident's drop-in configuration
+	wwid       string // A representative WWID, to evaluate wwid blacklists
+	wwidExp    string // A blacklist exception that matches the WWIDs of the vendor's LUNs
+	settings   [][2]string
+}
+
+var multipathVendors = []multipathVendor{
+	{
+		name:       "ONTAP",
+		vendor:     "NETAPP",
+		product:    "LUN C-Mode",
+		productExp: "LUN",
+		wwid:       "3600a098038303634722b4d59614f6a77",
+		wwidExp:    "^3600a098",
+		settings: [][2]string{
+			{"path_grouping_policy", "group_by_prio"},
+			{"failback", "immediate"},
+			{"no_path_retry", "queue"},
+		},
+	},
+	{
+		name:       "SolidFire",
+		vendor:     "SolidFir",
+		product:    "SSD SAN",
+		productExp: "SSD SAN",
+		wwid:       "36f47acc100000000707a646c00000001",
+		wwidExp:    "^36f47acc",
+		settings: [][2]string{
+			{"path_grouping_policy", "multibus"},
+		},
+	},
+}
+
+// unsupportedFindMultipathsValues are the find_multipaths values that keep multipathd from creating maps for LUNs
+// until a second path appears, or at all, which makes iSCSI staging time out.
+var unsupportedFindMultipathsValues = map[string]struct{}{
+	"yes": {}, "on": {}, "smart": {}, "strict": {},
+}
+
+// parseMultipathConfig parses a multipath configuration in the format of multipath.conf, which is also the format
+// of "multipathd show config".
+func parseMultipathConfig(text string) *multipathConfigSection {
+	root := &multipathConfigSection{attributes: make(map[string][]string)}
+	stack := []*multipathConfigSection{root}
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(stripMultipathComment(line))
+		if line == "" {
+			continue
+		}
+
+		current := stack[len(stack)-1]
+		switch {
+		case line == "}":
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		case strings.HasSuffix(line, "{"):
+			section := &multipathConfigSection{
+				name:       strings.TrimSpace(strings.TrimSuffix(line, "{")),
+				attributes: make(map[string][]string),
+			}
+			current.children = append(current.children, section)
+			stack = append(stack, section)
+		default:
+			name, value := line, ""
+			if i := strings.IndexAny(line, " \t"); i > 0 {
+				name, value = line[:i], strings.Trim(strings.TrimSpace(line[i:]), `"`)
+			}
+			current.attributes[name] = append(current.attributes[name], value)
+		}
+	}
+
+	return root
+}
+
+// stripMultipathComment removes a comment, which starts with # or !, unless it is within a quoted value.
+func stripMultipathComment(line string) string {
+	quoted := false
+	for i, c := range line {
+		switch c {
+		case '"':
+			quoted = !quoted
+		case '#', '!':
+			// A ! at the start of a quoted value negates a regular expression
+			if !quoted {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// sections returns the child sections with the given name.
+func (s *multipathConfigSection) sections(name string) []*multipathConfigSection {
+	sections := make([]*multipathConfigSection, 0)
+	if s == nil {
+		return sections
+	}
+	for _, child := range s.children {
+		if child.name == name {
+			sections = append(sections, child)
+		}
+	}
+	return sections
+}
+
+// section returns the last child section with the given name, since later sections take precedence.
+func (s *multipathConfigSection) section(name string) *multipathConfigSection {
+	sections := s.sections(name)
+	if len(sections) == 0 {
+		return nil
+	}
+	return sections[len(sections)-1]
+}
+
+// attribute returns the value of an attribute of the section, if it is set.  If the attribute is repeated, the last
+// value takes precedence.
+func (s *multipathConfigSection) attribute(name string) (string, bool) {
+	values := s.values(name)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// values returns every value of an attribute of the section.
+func (s *multipathConfigSection) values(name string) []string {
+	if s == nil {
+		return nil
+	}
+	return s.attributes[name]
+}
+
+// matchesDevice returns whether a device section's vendor and product expressions match the given device.
+func (s *multipathConfigSection) matchesDevice(vendor, product string) bool {
+	vendorExp, ok := s.attribute("vendor")
+	if !ok || !multipathRegexMatches(vendorExp, vendor) {
+		return false
+	}
+	productExp, ok := s.attribute("product")
+	return !ok || multipathRegexMatches(productExp, product)
+}
+
+// multipathRegexMatches evaluates a multipath regular expression, which is negated by a leading !.
+func multipathRegexMatches(expression, value string) bool {
+	negate := strings.HasPrefix(expression, "!")
+	expression = strings.TrimPrefix(expression, "!")
+	exp, err := regexp.Compile(expression)
+	if err != nil {
+		// multipathd only accepts valid expressions, so this can only come from a malformed configuration file
+		return false
+	}
+	return exp.MatchString(value) != negate
+}
+
+// deviceSetting returns the effective value of a setting for a device, which comes from the overrides section,
+// then the last matching device section that sets it, then the defaults section.
+func (s *multipathConfigSection) deviceSetting(vendor, product, setting string) (string, bool) {
+	if value, ok := s.section("overrides").attribute(setting); ok {
+		return value, true
+	}
+
+	value, found := "", false
+	for _, devices := range s.sections("devices") {
+		for _, device := range devices.sections("device") {
+			if device.matchesDevice(vendor, product) {
+				if deviceValue, ok := device.attribute(setting); ok {
+					value, found = deviceValue, true
+				}
+			}
+		}
+	}
+	if found {
+		return value, true
+	}
+
+	return s.section("defaults").attribute(setting)
+}
+
+// blacklists returns whether the blacklist excludes a vendor's LUNs.  Each kind of blacklist entry is only
+// reverted by an exception of the same kind.
+func (s *multipathConfigSection) blacklists(vendor multipathVendor) bool {
+	blacklists := s.sections("blacklist")
+	exceptions := s.sections("blacklist_exceptions")
+
+	matchesAttribute := func(sections []*multipathConfigSection, attribute, value string) bool {
+		for _, section := range sections {
+			for _, expression := range section.values(attribute) {
+				if multipathRegexMatches(expression, value) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	matchesDevice := func(sections []*multipathConfigSection) bool {
+		for _, section := range sections {
+			for _, device := range section.sections("device") {
+				if device.matchesDevice(vendor.vendor, vendor.product) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// iSCSI LUNs are SCSI disks, e.g. sdb
+	if matchesAttribute(blacklists, "devnode", "sdb") && !matchesAttribute(exceptions, "devnode", "sdb") {
+		return true
+	}
+	if matchesAttribute(blacklists, "wwid", vendor.wwid) && !matchesAttribute(exceptions, "wwid", vendor.wwid) {
+		return true
+	}
+	return matchesDevice(blacklists) && !matchesDevice(exceptions)
+}
+
+// validateMultipathConfig checks the effective multipath configuration for the settings Trident relies on.  If the
+// contents of multipath.conf are supplied, issues caused by settings in that file are attributed to it.
+func validateMultipathConfig(effective, file *multipathConfigSection) []MultipathConfigIssue {
+	issues := make([]MultipathConfigIssue, 0)
+
+	fileSource := func(set bool) string {
+		if set {
+			return multipathConfigFile
+		}
+		return ""
+	}
+
+	findMultipaths, _ := effective.section("defaults").attribute("find_multipaths")
+	if _, unsupported := unsupportedFindMultipathsValues[findMultipaths]; unsupported {
+		_, inFile := file.section("defaults").attribute("find_multipaths")
+		issues = append(issues, MultipathConfigIssue{
+			Setting:  "defaults/find_multipaths",
+			Value:    findMultipaths,
+			Expected: "no",
+			Message:  "multipathd will not create maps for LUNs with a single path, so iSCSI staging may time out",
+			Source:   fileSource(inFile),
+		})
+	}
+
+	for _, vendor := range multipathVendors {
+		if effective.blacklists(vendor) {
+			issues = append(issues, MultipathConfigIssue{
+				Setting: "blacklist",
+				Message: fmt.Sprintf("%s LUNs are blacklisted; add a blacklist_exceptions entry for them",
+					vendor.name),
+				Source: fileSource(file.blacklists(vendor)),
+			})
+		}
+
+		for _, setting := range vendor.settings {
+			name, expected := setting[0], setting[1]
+			value, _ := effective.deviceSetting(vendor.vendor, vendor.product, name)
+			if value == expected {
+				continue
+			}
+			fileValue, inFile := file.deviceSetting(vendor.vendor, vendor.product, name)
+			issues = append(issues, MultipathConfigIssue{
+				Setting:  fmt.Sprintf("%s %s/%s", vendor.vendor, vendor.product, name),
+				Value:    value,
+				Expected: expected,
+				Message:  fmt.Sprintf("%s LUNs should use %s %s", vendor.name, name, expected),
+				Source:   fileSource(inFile && fileValue == value),
+			})
+		}
+	}
+
+	return issues
+}
+
+// ValidateMultipathConfig checks that multipathd is running and that its configuration has the settings Trident
+// relies on when it stages iSCSI volumes, which would otherwise surface as staging timeouts.
+func ValidateMultipathConfig(ctx context.Context) *MultipathConfigStatus {
+	Logc(ctx).Debug(">>>> multipath_config.ValidateMultipathConfig")
+	defer Logc(ctx).Debug("<<<< multipath_config.ValidateMultipathConfig")
+
+	status := &MultipathConfigStatus{}
+
+	if !multipathdIsRunning(ctx) {
+		status.Issues = []MultipathConfigIssue{{Setting: "multipathd", Message: "multipathd is not running"}}
+		return status
+	}
+
+	effective, err := getEffectiveMultipathConfig(ctx)
+	if err != nil {
+		status.Issues = []MultipathConfigIssue{{Setting: "multipathd", Message: err.Error()}}
+		return status
+	}
+
+	var file *multipathConfigSection
+	fileIssues := make([]MultipathConfigIssue, 0)
+	if out, err := execCommand(ctx, "cat", multipathConfigFile); err != nil {
+		fileIssues = append(fileIssues, MultipathConfigIssue{
+			Setting: multipathConfigFile,
+			Message: fmt.Sprintf("%s does not exist, so multipathd uses its built-in configuration",
+				multipathConfigFile),
+		})
+	} else {
+		file = parseMultipathConfig(string(out))
+	}
+
+	status.Issues = append(fileIssues, validateMultipathConfig(effective, file)...)
+	status.Valid = len(status.Issues) == 0
+
+	if dropInPath := multipathDropInPath(effective); isTridentMultipathDropIn(dropInPath) {
+		status.ManagedConfig = dropInPath
+	}
+
+	if !status.Valid {
+		Logc(ctx).WithField("issues", status.Issues).Warning("Multipath configuration is not as Trident expects.")
+	}
+
+	return status
+}
+
+// getEffectiveMultipathConfig returns the configuration multipathd is running with, including its built-in settings
+// and any drop-in configuration files.
+func getEffectiveMultipathConfig(ctx context.Context) (*multipathConfigSection, error) {
+	out, err := execCommandWithTimeout(ctx, "multipathd", 5*time.Second, false, "show", "config")
+	if err != nil {
+		return nil, fmt.Errorf("could not read multipathd configuration; %v", err)
+	}
+	return parseMultipathConfig(string(out)), nil
+}
+
+// multipathDropInPath returns the path of Trident's drop-in file in multipathd's configuration directory.
+func multipathDropInPath(effective *multipathConfigSection) string {
+	configDir, ok := effective.section("defaults").attribute("config_dir")
+	if !ok || configDir == "" {
+		configDir = multipathDefaultConfigDir
+	}
+	return filepath.Join(configDir, multipathDropInFilename)
+}
+
+func isTridentMultipathDropIn(dropInPath string) bool {
+	contents, err := os.ReadFile(filepath.Join(hostRootPath, dropInPath))
+	return err == nil && strings.HasPrefix(string(contents), multipathDropInHeader)
+}
+
+const multipathDropInHeader = "# This file is managed by Trident; changes to it will be overwritten.\n"
+
+// multipathDropInConfig returns the drop-in configuration with the settings Trident relies on.
+func multipathDropInConfig() string {
+	var config strings.Builder
+
+	config.WriteString(multipathDropInHeader)
+	config.WriteString("defaults {\n\tfind_multipaths no\n}\n")
+
+	config.WriteString("blacklist_exceptions {\n")
+	for _, vendor := range multipathVendors {
+		config.WriteString(fmt.Sprintf("\twwid \"%s\"\n", vendor.wwidExp))
+	}
+	for _, vendor := range multipathVendors {
+		config.WriteString(fmt.Sprintf("\tdevice {\n\t\tvendor \"%s\"\n\t\tproduct \"%s\"\n\t}\n",
+			vendor.vendor, vendor.productExp))
+	}
+	config.WriteString("}\n")
+
+	config.WriteString("devices {\n")
+	for _, vendor := range multipathVendors {
+		config.WriteString(fmt.Sprintf("\tdevice {\n\t\tvendor \"%s\"\n\t\tproduct \"%s\"\n", vendor.vendor,
+			vendor.productExp))
+		for _, setting := range vendor.settings {
+			config.WriteString(fmt.Sprintf("\t\t%s %s\n", setting[0], setting[1]))
+		}
+		config.WriteString("\t}\n")
+	}
+	config.WriteString("}\n")
+
+	return config.String()
+}
+
+// EnsureMultipathDropIn writes a drop-in configuration file with the settings Trident relies on to multipathd's
+// configuration directory, and reconfigures multipathd if the file changed.  A drop-in cannot fix settings in the
+// overrides section of multipath.conf, or blacklist entries by device node, so the configuration should be validated
+// afterwards.  A file of the same name that Trident did not write is left alone.
+func EnsureMultipathDropIn(ctx context.Context) (string, error) {
+	Logc(ctx).Debug(">>>> multipath_config.EnsureMultipathDropIn")
+	defer Logc(ctx).Debug("<<<< multipath_config.EnsureMultipathDropIn")
+
+	if !multipathdIsRunning(ctx) {
+		return "", fmt.Errorf("multipathd is not running")
+	}
+
+	effective, err := getEffectiveMultipathConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	dropInPath := multipathDropInPath(effective)
+	hostPath := filepath.Join(hostRootPath, dropInPath)
+	desired := multipathDropInConfig()
+
+	current, err := os.ReadFile(hostPath)
+	if err == nil {
+		if string(current) == desired {
+			return dropInPath, nil
+		}
+		if !strings.HasPrefix(string(current), multipathDropInHeader) {
+			return "", fmt.Errorf("%s exists and is not managed by Trident", dropInPath)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("could not read %s; %v", dropInPath, err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(hostPath), 0o755); err != nil {
+		return "", fmt.Errorf("could not create %s; %v", filepath.Dir(dropInPath), err)
+	}
+	if err = os.WriteFile(hostPath, []byte(desired), 0o644); err != nil {
+		return "", fmt.Errorf("could not write %s; %v", dropInPath, err)
+	}
+	Logc(ctx).WithField("path", dropInPath).Info("Wrote multipath drop-in configuration.")
+
+	if out, err := execCommandWithTimeout(ctx, "multipathd", 10*time.Second, false, "reconfigure"); err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"output": string(out),
+		}).WithError(err).Error("Could not reconfigure multipathd.")
+		return dropInPath, fmt.Errorf("could not reconfigure multipathd; %v", err)
+	}
+
+	return dropInPath, nil
+}
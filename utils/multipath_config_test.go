@@ -0,0 +1,200 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMultipathShowConfig = `defaults {
+	verbosity 2
+	find_multipaths "no"
+	config_dir "/etc/multipath/conf.d"
+}
+blacklist {
+	devnode "^(ram|zram|raw|loop|fd|md|dm-|sr|scd|st|dcssblk)[0-9]"
+	devnode "^(td|hd|vd)[a-z]"
+	device {
+		vendor "DGC"
+		product "LUNZ"
+	}
+}
+blacklist_exceptions {
+	property "(SCSI_IDENT_|ID_WWN)"
+}
+devices {
+	device {
+		vendor "NETAPP"
+		product "LUN.*"
+		path_grouping_policy "group_by_prio"
+		failback "immediate"
+		no_path_retry "queue"
+	}
+	device {
+		vendor "SolidFir"
+		product "SSD SAN"
+		path_grouping_policy "multibus"
+	}
+}
+overrides {
+}
+`
+
+func TestParseMultipathConfig(t *testing.T) {
+	config := parseMultipathConfig(testMultipathShowConfig + "# comment\nmultipaths {\n\tmultipath {\n\t\t" +
+		"wwid \"3600a0980\" # trailing comment\n\t}\n}\n")
+
+	value, ok := config.section("defaults").attribute("find_multipaths")
+	assert.True(t, ok)
+	assert.Equal(t, "no", value)
+
+	assert.Equal(t, []string{
+		"^(ram|zram|raw|loop|fd|md|dm-|sr|scd|st|dcssblk)[0-9]", "^(td|hd|vd)[a-z]",
+	}, config.section("blacklist").values("devnode"))
+	assert.Len(t, config.section("devices").sections("device"), 2)
+
+	value, ok = config.section("multipaths").section("multipath").attribute("wwid")
+	assert.True(t, ok)
+	assert.Equal(t, "3600a0980", value)
+
+	_, ok = config.section("missing").attribute("find_multipaths")
+	assert.False(t, ok)
+
+	value, ok = config.deviceSetting("NETAPP", "LUN C-Mode", "no_path_retry")
+	assert.True(t, ok)
+	assert.Equal(t, "queue", value)
+}
+
+func TestMultipathRegexMatches(t *testing.T) {
+	assert.True(t, multipathRegexMatches("^sd", "sdb"))
+	assert.False(t, multipathRegexMatches("!^sd", "sdb"))
+	assert.True(t, multipathRegexMatches("!^sd", "nvme0n1"))
+	assert.False(t, multipathRegexMatches("(", "sdb"))
+}
+
+func TestValidateMultipathConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		expected []MultipathConfigIssue
+	}{
+		{
+			name:     "Valid",
+			file:     "defaults {\n\tfind_multipaths no\n}\n",
+			expected: []MultipathConfigIssue{},
+		},
+		{
+			name: "FindMultipaths",
+			file: "defaults {\n\tfind_multipaths yes\n}\n",
+			expected: []MultipathConfigIssue{{
+				Setting:  "defaults/find_multipaths",
+				Value:    "yes",
+				Expected: "no",
+				Message:  "multipathd will not create maps for LUNs with a single path, so iSCSI staging may time out",
+				Source:   multipathConfigFile,
+			}},
+		},
+		{
+			name: "BlacklistAll",
+			file: "blacklist {\n\twwid \".*\"\n}\nblacklist_exceptions {\n\twwid \"^36f47acc\"\n}\n",
+			expected: []MultipathConfigIssue{{
+				Setting: "blacklist",
+				Message: "ONTAP LUNs are blacklisted; add a blacklist_exceptions entry for them",
+				Source:  multipathConfigFile,
+			}},
+		},
+		{
+			name: "BlacklistDevnodeWithWWIDException",
+			file: "blacklist {\n\tdevnode \"^sd[a-z]\"\n}\nblacklist_exceptions {\n\twwid \"^3600a098\"\n" +
+				"\twwid \"^36f47acc\"\n}\n",
+			expected: []MultipathConfigIssue{
+				{
+					Setting: "blacklist",
+					Message: "ONTAP LUNs are blacklisted; add a blacklist_exceptions entry for them",
+					Source:  multipathConfigFile,
+				},
+				{
+					Setting: "blacklist",
+					Message: "SolidFire LUNs are blacklisted; add a blacklist_exceptions entry for them",
+					Source:  multipathConfigFile,
+				},
+			},
+		},
+		{
+			name: "NoPathRetryOverride",
+			file: "devices {\n\tdevice {\n\t\tvendor \"NETAPP\"\n\t\tproduct \"LUN\"\n\t\tno_path_retry fail\n" +
+				"\t}\n}\n",
+			expected: []MultipathConfigIssue{{
+				Setting:  "NETAPP LUN C-Mode/no_path_retry",
+				Value:    "fail",
+				Expected: "queue",
+				Message:  "ONTAP LUNs should use no_path_retry queue",
+				Source:   multipathConfigFile,
+			}},
+		},
+		{
+			name: "OverridesSection",
+			file: "overrides {\n\tfailback manual\n\tpath_grouping_policy multibus\n}\n",
+			expected: []MultipathConfigIssue{
+				{
+					Setting:  "NETAPP LUN C-Mode/path_grouping_policy",
+					Value:    "multibus",
+					Expected: "group_by_prio",
+					Message:  "ONTAP LUNs should use path_grouping_policy group_by_prio",
+					Source:   multipathConfigFile,
+				},
+				{
+					Setting:  "NETAPP LUN C-Mode/failback",
+					Value:    "manual",
+					Expected: "immediate",
+					Message:  "ONTAP LUNs should use failback immediate",
+					Source:   multipathConfigFile,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// multipathd merges multipath.conf into its built-in configuration
+			effective := parseMultipathConfig(testMultipathShowConfig + test.file)
+			file := parseMultipathConfig(test.file)
+
+			assert.Equal(t, test.expected, validateMultipathConfig(effective, file))
+		})
+	}
+}
+
+func TestValidateMultipathConfig_NotFromFile(t *testing.T) {
+	// The setting comes from a drop-in file rather than multipath.conf
+	effective := parseMultipathConfig(testMultipathShowConfig + "defaults {\n\tfind_multipaths smart\n}\n")
+
+	issues := validateMultipathConfig(effective, nil)
+
+	assert.Len(t, issues, 1)
+	assert.Equal(t, "smart", issues[0].Value)
+	assert.Empty(t, issues[0].Source)
+}
+
+func TestMultipathDropInConfig(t *testing.T) {
+	bad := "defaults {\n\tfind_multipaths yes\n}\nblacklist {\n\twwid \".*\"\n}\ndevices {\n\tdevice {\n" +
+		"\t\tvendor \"NETAPP\"\n\t\tproduct \"LUN\"\n\t\tno_path_retry fail\n\t}\n}\n"
+
+	effective := parseMultipathConfig(testMultipathShowConfig + bad)
+	assert.NotEmpty(t, validateMultipathConfig(effective, nil))
+
+	// multipathd reads drop-in files after multipath.conf
+	effective = parseMultipathConfig(testMultipathShowConfig + bad + multipathDropInConfig())
+	assert.Empty(t, validateMultipathConfig(effective, nil))
+
+	assert.True(t, len(multipathDropInConfig()) > len(multipathDropInHeader))
+	assert.Equal(t, multipathDropInHeader, multipathDropInConfig()[:len(multipathDropInHeader)])
+}
+
+func TestMultipathDropInPath(t *testing.T) {
+	assert.Equal(t, "/etc/multipath/conf.d/trident.conf", multipathDropInPath(nil))
+	assert.Equal(t, "/etc/mpath.d/trident.conf",
+		multipathDropInPath(parseMultipathConfig("defaults {\n\tconfig_dir \"/etc/mpath.d\"\n}\n")))
+}
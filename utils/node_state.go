@@ -0,0 +1,162 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+var iSCSIDeviceSessionRegex = regexp.MustCompile(`/session(\d+)/`)
+
+// NodeState is a snapshot of the kernel state on a node that Trident volumes depend on, which is compared with
+// the node's volume tracking files to find resources that were left behind or are missing.
+type NodeState struct {
+	Mounts []MountInfo
+	// ISCSIAvailable is false if the iSCSI tools are not installed, in which case there are no sessions to list
+	ISCSIAvailable bool
+	ISCSISessions  []ISCSISessionInfo
+	// ISCSIDeviceSessions maps SCSI devices (e.g. sdb) attached through iSCSI to their session IDs
+	ISCSIDeviceSessions map[string]string
+	MultipathMaps       []MultipathMap
+	LoopDevices         []LoopDevice
+}
+
+// MultipathMap is a device mapper multipath device and the devices it is built from and used by.
+type MultipathMap struct {
+	Device  string   // e.g. dm-3
+	Name    string   // e.g. 3600a098038303634722b4d59614f6a77
+	Devices []string // e.g. sdb, sdc
+	Holders []string // e.g. dm-4, for a LUKS mapping on top of the map
+}
+
+// GetNodeState lists the mounts, iSCSI sessions, multipath maps and loop devices on this node.
+func GetNodeState(ctx context.Context) (*NodeState, error) {
+	Logc(ctx).Debug(">>>> node_state.GetNodeState")
+	defer Logc(ctx).Debug("<<<< node_state.GetNodeState")
+
+	var err error
+	state := &NodeState{}
+
+	if state.Mounts, err = GetSelfMountInfo(ctx); err != nil {
+		return nil, fmt.Errorf("could not list mounts; %v", err)
+	}
+
+	if state.ISCSIAvailable = ISCSISupported(ctx); state.ISCSIAvailable {
+		if state.ISCSISessions, err = getISCSISessionInfo(ctx); err != nil {
+			return nil, fmt.Errorf("could not list iSCSI sessions; %v", err)
+		}
+	}
+	state.ISCSIDeviceSessions = getISCSIDeviceSessions(ctx)
+	state.MultipathMaps = listMultipathMaps(ctx)
+
+	if state.LoopDevices, err = getLoopDeviceInfo(ctx); err != nil {
+		return nil, fmt.Errorf("could not list loop devices; %v", err)
+	}
+
+	return state, nil
+}
+
+// getISCSIDeviceSessions returns the SCSI devices attached through iSCSI, mapped to the IDs of their sessions.  The
+// sysfs path of such a device runs through its session, e.g.
+// /sys/devices/platform/host3/session1/target3:0:0/3:0:0:1/block/sdb.
+func getISCSIDeviceSessions(ctx context.Context) map[string]string {
+	deviceSessions := make(map[string]string)
+
+	blockDir := chrootPathPrefix + "/sys/block"
+	entries, err := os.ReadDir(blockDir)
+	if err != nil {
+		Logc(ctx).WithError(err).Debug("Could not list block devices.")
+		return deviceSessions
+	}
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), "sd") {
+			continue
+		}
+		devicePath, err := filepath.EvalSymlinks(filepath.Join(blockDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if match := iSCSIDeviceSessionRegex.FindStringSubmatch(devicePath); match != nil {
+			deviceSessions[entry.Name()] = match[1]
+		}
+	}
+
+	return deviceSessions
+}
+
+// listMultipathMaps returns the device mapper multipath devices on this node.
+func listMultipathMaps(ctx context.Context) []MultipathMap {
+	maps := make([]MultipathMap, 0)
+
+	blockDir := chrootPathPrefix + "/sys/block"
+	entries, err := os.ReadDir(blockDir)
+	if err != nil {
+		Logc(ctx).WithError(err).Debug("Could not list block devices.")
+		return maps
+	}
+
+	for _, entry := range entries {
+		device := entry.Name()
+		if !strings.HasPrefix(device, "dm-") {
+			continue
+		}
+		uuid, err := os.ReadFile(filepath.Join(blockDir, device, "dm", "uuid"))
+		if err != nil || !strings.HasPrefix(string(uuid), "mpath-") {
+			continue
+		}
+		name, _ := os.ReadFile(filepath.Join(blockDir, device, "dm", "name"))
+
+		maps = append(maps, MultipathMap{
+			Device:  device,
+			Name:    strings.TrimSpace(string(name)),
+			Devices: listDirEntryNames(filepath.Join(blockDir, device, "slaves")),
+			Holders: listDirEntryNames(filepath.Join(blockDir, device, "holders")),
+		})
+	}
+
+	return maps
+}
+
+func listDirEntryNames(dir string) []string {
+	names := make([]string, 0)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return names
+	}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+// FlushMultipathMap removes an unused multipath map, such as one left behind when its LUN was unmapped.
+func FlushMultipathMap(ctx context.Context, devicePath string) error {
+	Logc(ctx).WithField("devicePath", devicePath).Debug(">>>> node_state.FlushMultipathMap")
+	defer Logc(ctx).Debug("<<<< node_state.FlushMultipathMap")
+
+	out, err := execCommandWithTimeout(ctx, "multipath", 10*time.Second, false, "-f", devicePath)
+	if err != nil {
+		Logc(ctx).WithFields(log.Fields{
+			"devicePath": devicePath,
+			"output":     string(out),
+		}).WithError(err).Error("Could not flush multipath map.")
+		return fmt.Errorf("could not flush multipath map %s; %v", devicePath, err)
+	}
+	return nil
+}
+
+// DetachLoopDevice detaches a loop device from its backing file.
+func DetachLoopDevice(ctx context.Context, loopDeviceName string) error {
+	return detachLoopDevice(ctx, loopDeviceName)
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSysfsFile(t *testing.T, path, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestListMultipathMaps(t *testing.T) {
+	root := t.TempDir()
+	originalPrefix := chrootPathPrefix
+	chrootPathPrefix = root
+	defer func() { chrootPathPrefix = originalPrefix }()
+
+	blockDir := filepath.Join(root, "sys", "block")
+
+	// A multipath map with two paths and a LUKS mapping on top
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-0", "dm", "uuid"), "mpath-3600a0980\n")
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-0", "dm", "name"), "3600a0980\n")
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-0", "slaves", "sdb"), "")
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-0", "slaves", "sdc"), "")
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-0", "holders", "dm-1"), "")
+
+	// A LUKS mapping, which is not a multipath map
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-1", "dm", "uuid"), "CRYPT-LUKS2-1234-luks-pvc-1\n")
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-1", "dm", "name"), "luks-pvc-1\n")
+
+	// A multipath map whose paths are all gone
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-2", "dm", "uuid"), "mpath-3600a0981\n")
+	writeSysfsFile(t, filepath.Join(blockDir, "dm-2", "dm", "name"), "3600a0981\n")
+
+	writeSysfsFile(t, filepath.Join(blockDir, "sda", "size"), "1024\n")
+
+	maps := listMultipathMaps(context.Background())
+
+	assert.Equal(t, []MultipathMap{
+		{Device: "dm-0", Name: "3600a0980", Devices: []string{"sdb", "sdc"}, Holders: []string{"dm-1"}},
+		{Device: "dm-2", Name: "3600a0981", Devices: []string{}, Holders: []string{}},
+	}, maps)
+}
+
+func TestGetISCSIDeviceSessions(t *testing.T) {
+	root := t.TempDir()
+	originalPrefix := chrootPathPrefix
+	chrootPathPrefix = root
+	defer func() { chrootPathPrefix = originalPrefix }()
+
+	devicesDir := filepath.Join(root, "sys", "devices")
+	blockDir := filepath.Join(root, "sys", "block")
+	assert.NoError(t, os.MkdirAll(blockDir, 0o755))
+
+	iSCSIDevice := filepath.Join(devicesDir, "platform", "host3", "session7", "target3:0:0", "3:0:0:1", "block", "sdb")
+	localDevice := filepath.Join(devicesDir, "pci0000:00", "0000:00:10.0", "host0", "target0:0:0", "0:0:0:0",
+		"block", "sda")
+	assert.NoError(t, os.MkdirAll(iSCSIDevice, 0o755))
+	assert.NoError(t, os.MkdirAll(localDevice, 0o755))
+	assert.NoError(t, os.Symlink(iSCSIDevice, filepath.Join(blockDir, "sdb")))
+	assert.NoError(t, os.Symlink(localDevice, filepath.Join(blockDir, "sda")))
+
+	assert.Equal(t, map[string]string{"sdb": "7"}, getISCSIDeviceSessions(context.Background()))
+}
@@ -0,0 +1,259 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	. "github.com/netapp/trident/logger"
+)
+
+// Nodes sharing a multi-attach block volume coordinate with SCSI-3 persistent reservations.  Each node registers
+// a key on every path to the LUN, and the LUN holds a Write Exclusive - All Registrants reservation, so only
+// registered nodes may write to it.  The reservation lasts as long as any node is registered, so it does not lapse
+// when the node that made it leaves.  A node that is force detached is preempted right away by a node still
+// sharing the volume, or else by the next node to stage it, which removes its registration and aborts any I/O it
+// still has outstanding.
+
+const (
+	sgPersistTimeout = 20 * time.Second
+
+	// Write Exclusive - All Registrants
+	persistentReservationType = "7"
+)
+
+var (
+	reservationKeyRegex    = regexp.MustCompile(`(?m)^\s*(0x[0-9a-fA-F]+)\s*$`)
+	reservationHolderRegex = regexp.MustCompile(`Key=(0x[0-9a-fA-F]+)`)
+)
+
+// PersistentReservationKey returns the reservation key a node registers on multi-attach block volumes.  Keys are
+// derived from node names, so the controller knows which key to preempt when a node is force detached.
+func PersistentReservationKey(nodeName string) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(nodeName))
+	key := hash.Sum64()
+	if key == 0 {
+		// A key of zero unregisters
+		key = 1
+	}
+	return fmt.Sprintf("0x%x", key)
+}
+
+// normalizeReservationKey returns a reservation key in the form sg_persist reports it.
+func normalizeReservationKey(key string) (string, error) {
+	value, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(key), "0x"), 16, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid reservation key %s; %v", key, err)
+	}
+	return fmt.Sprintf("0x%x", value), nil
+}
+
+// parseReservationKeys returns the keys listed by 'sg_persist --read-keys'.
+func parseReservationKeys(output string) []string {
+	keys := make([]string, 0)
+	for _, match := range reservationKeyRegex.FindAllStringSubmatch(output, -1) {
+		if key, err := normalizeReservationKey(match[1]); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// parseReservationHolder returns the key holding the reservation listed by 'sg_persist --read-reservation', or
+// an empty string if no reservation is held.  All Registrants reservations are listed with a key of zero.
+func parseReservationHolder(output string) string {
+	match := reservationHolderRegex.FindStringSubmatch(output)
+	if match == nil {
+		return ""
+	}
+	key, err := normalizeReservationKey(match[1])
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// sgPersist runs sg_persist against a device.
+func sgPersist(ctx context.Context, args ...string) ([]byte, error) {
+	args = append([]string{"--no-inquiry"}, args...)
+	out, err := execCommandWithTimeout(ctx, "sg_persist", sgPersistTimeout, true, args...)
+	if err != nil {
+		return out, fmt.Errorf("sg_persist %s failed; %s; %v", strings.Join(args, " "),
+			strings.TrimSpace(string(out)), err)
+	}
+	return out, nil
+}
+
+// getPersistentReservationPaths returns every path to an iSCSI LUN, since reservation keys are registered per path.
+func getPersistentReservationPaths(ctx context.Context, lunID int, iSCSINodeName string) ([]string, error) {
+	deviceInfo, err := getDeviceInfoForLUN(ctx, lunID, iSCSINodeName, false, false)
+	if err != nil {
+		return nil, err
+	} else if deviceInfo == nil || len(deviceInfo.Devices) == 0 {
+		return nil, fmt.Errorf("could not find devices for LUN %d on target %s", lunID, iSCSINodeName)
+	}
+
+	paths := make([]string, 0, len(deviceInfo.Devices))
+	for _, device := range deviceInfo.Devices {
+		paths = append(paths, "/dev/"+device)
+	}
+	return paths, nil
+}
+
+// EnsurePersistentReservation registers this node's reservation key on every path to a volume's LUN, preempts the
+// keys of nodes that were force detached from it, and reserves the LUN if no node holds a reservation.
+func EnsurePersistentReservation(ctx context.Context, publishInfo *VolumePublishInfo) error {
+	fields := log.Fields{
+		"lunID":          publishInfo.IscsiLunNumber,
+		"targetIQN":      publishInfo.IscsiTargetIQN,
+		"reservationKey": publishInfo.ReservationKey,
+	}
+	Logc(ctx).WithFields(fields).Debug(">>>> persistent_reservation.EnsurePersistentReservation")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< persistent_reservation.EnsurePersistentReservation")
+
+	key, err := normalizeReservationKey(publishInfo.ReservationKey)
+	if err != nil {
+		return err
+	}
+
+	paths, err := getPersistentReservationPaths(ctx, int(publishInfo.IscsiLunNumber), publishInfo.IscsiTargetIQN)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if _, err = sgPersist(ctx, "--out", "--register-ignore", "--param-sark="+key, path); err != nil {
+			return fmt.Errorf("could not register reservation key on %s; %v", path, err)
+		}
+	}
+
+	// Reservations apply to the LUN, so any one path may be used from here on
+	device := paths[0]
+
+	out, err := sgPersist(ctx, "--in", "--read-keys", device)
+	if err != nil {
+		return err
+	}
+	if err = preemptReservationKeys(ctx, device, key, parseReservationKeys(string(out)),
+		publishInfo.PreemptReservationKeys); err != nil {
+		return err
+	}
+
+	out, err = sgPersist(ctx, "--in", "--read-reservation", device)
+	if err != nil {
+		return err
+	}
+	if parseReservationHolder(string(out)) != "" {
+		return nil
+	}
+
+	if _, err = sgPersist(ctx, "--out", "--reserve", "--prout-type="+persistentReservationType,
+		"--param-rk="+key, device); err != nil {
+		// Another node may have reserved the LUN first, which is just as good
+		if out, readErr := sgPersist(ctx, "--in", "--read-reservation", device); readErr == nil &&
+			parseReservationHolder(string(out)) != "" {
+			return nil
+		}
+		return fmt.Errorf("could not reserve LUN %d; %v", publishInfo.IscsiLunNumber, err)
+	}
+
+	Logc(ctx).WithFields(fields).Info("Reserved LUN.")
+	return nil
+}
+
+// PreemptPersistentReservationKeys removes the registrations of nodes that were force detached from a volume this
+// node has staged, so they can no longer write to its LUN even if they are still running.
+func PreemptPersistentReservationKeys(
+	ctx context.Context, publishInfo *VolumePublishInfo, preemptKeys []string,
+) error {
+	fields := log.Fields{
+		"lunID":          publishInfo.IscsiLunNumber,
+		"targetIQN":      publishInfo.IscsiTargetIQN,
+		"reservationKey": publishInfo.ReservationKey,
+	}
+	Logc(ctx).WithFields(fields).Debug(">>>> persistent_reservation.PreemptPersistentReservationKeys")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< persistent_reservation.PreemptPersistentReservationKeys")
+
+	key, err := normalizeReservationKey(publishInfo.ReservationKey)
+	if err != nil {
+		return err
+	}
+
+	paths, err := getPersistentReservationPaths(ctx, int(publishInfo.IscsiLunNumber), publishInfo.IscsiTargetIQN)
+	if err != nil {
+		return err
+	}
+	device := paths[0]
+
+	out, err := sgPersist(ctx, "--in", "--read-keys", device)
+	if err != nil {
+		return err
+	}
+
+	return preemptReservationKeys(ctx, device, key, parseReservationKeys(string(out)), preemptKeys)
+}
+
+// preemptReservationKeys preempts each of the supplied keys that is still registered on a LUN, other than this
+// node's own key, aborting any I/O the preempted nodes have outstanding.
+func preemptReservationKeys(ctx context.Context, device, key string, registeredKeys, preemptKeys []string) error {
+	for _, preemptKey := range preemptKeys {
+		preemptKey, err := normalizeReservationKey(preemptKey)
+		if err != nil {
+			return err
+		}
+		if preemptKey == key || !SliceContainsString(registeredKeys, preemptKey) {
+			continue
+		}
+
+		Logc(ctx).WithFields(log.Fields{
+			"device":     device,
+			"preemptKey": preemptKey,
+		}).Warning("Preempting reservation of force-detached node.")
+
+		if _, err = sgPersist(ctx, "--out", "--preempt-abort", "--prout-type="+persistentReservationType,
+			"--param-rk="+key, "--param-sark="+preemptKey, device); err != nil {
+			return fmt.Errorf("could not preempt reservation key %s; %v", preemptKey, err)
+		}
+	}
+	return nil
+}
+
+// ReleasePersistentReservation unregisters this node's key from every path to a volume's LUN, so the node can no
+// longer write to the LUN.  The reservation is kept for the nodes still sharing the volume, and lapses when the
+// last of them unregisters; the next node to stage the volume reserves it again.
+func ReleasePersistentReservation(ctx context.Context, publishInfo *VolumePublishInfo) error {
+	fields := log.Fields{
+		"lunID":          publishInfo.IscsiLunNumber,
+		"targetIQN":      publishInfo.IscsiTargetIQN,
+		"reservationKey": publishInfo.ReservationKey,
+	}
+	Logc(ctx).WithFields(fields).Debug(">>>> persistent_reservation.ReleasePersistentReservation")
+	defer Logc(ctx).WithFields(fields).Debug("<<<< persistent_reservation.ReleasePersistentReservation")
+
+	if _, err := normalizeReservationKey(publishInfo.ReservationKey); err != nil {
+		return err
+	}
+
+	paths, err := getPersistentReservationPaths(ctx, int(publishInfo.IscsiLunNumber), publishInfo.IscsiTargetIQN)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if _, err = sgPersist(ctx, "--out", "--register-ignore", "--param-sark=0", path); err != nil {
+			return fmt.Errorf("could not unregister reservation key on %s; %v", path, err)
+		}
+	}
+
+	Logc(ctx).WithFields(fields).Info("Unregistered from LUN.")
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 NetApp, Inc. All Rights Reserved.
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistentReservationKey(t *testing.T) {
+	key := PersistentReservationKey("node1")
+
+	// Keys are stable, distinct per node, and in the form sg_persist reports them
+	assert.Equal(t, key, PersistentReservationKey("node1"))
+	assert.NotEqual(t, key, PersistentReservationKey("node2"))
+	normalized, err := normalizeReservationKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, key, normalized)
+}
+
+func TestNormalizeReservationKey(t *testing.T) {
+	key, err := normalizeReservationKey("0x00000000000ABC12")
+	assert.NoError(t, err)
+	assert.Equal(t, "0xabc12", key)
+
+	_, err = normalizeReservationKey("node1")
+	assert.Error(t, err)
+}
+
+func TestParseReservationKeys(t *testing.T) {
+	output := `  PR generation=0x4, 2 registered reservation keys follow:
+    0x1a2b
+    0x3C4D
+`
+	assert.Equal(t, []string{"0x1a2b", "0x3c4d"}, parseReservationKeys(output))
+
+	output = "  PR generation=0x0, there are NO registered reservation keys\n"
+	assert.Empty(t, parseReservationKeys(output))
+}
+
+func TestParseReservationHolder(t *testing.T) {
+	output := `  PR generation=0x4, Reservation follows:
+    Key=0x1a2b
+    scope: LU_SCOPE,  type: Write Exclusive, registrants only
+`
+	assert.Equal(t, "0x1a2b", parseReservationHolder(output))
+
+	output = `  PR generation=0x4, Reservation follows:
+    Key=0x0
+    scope: LU_SCOPE,  type: Write Exclusive, all registrants
+`
+	assert.Equal(t, "0x0", parseReservationHolder(output))
+
+	output = "  PR generation=0x4, there is NO reservation held\n"
+	assert.Equal(t, "", parseReservationHolder(output))
+}
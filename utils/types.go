@@ -20,6 +20,8 @@ type VolumeAccessInfo struct {
 	MountOptions       string `json:"mountOptions,omitempty"`
 	PublishEnforcement bool   `json:"publishEnforcement,omitempty"`
 	ReadOnly           bool   `json:"readOnly,omitempty"`
+	// Reservation keys of nodes that were force detached and must be preempted when the volume is next staged
+	PreemptReservationKeys []string `json:"preemptReservationKeys,omitempty"`
 	// The access mode values are defined by CSI
 	// See https://github.com/container-storage-interface/spec/blob/release-1.5/lib/go/csi/csi.pb.go#L135
 	AccessMode int32 `json:"accessMode,omitempty"`
@@ -82,6 +84,9 @@ type VolumePublishInfo struct {
 	StagingMountpoint string   `json:"stagingMountpoint,omitempty"` // NOTE: Added in 22.04 release
 	TridentUUID       string   `json:"tridentUUID,omitempty"`       // NOTE: Added in 22.07 release
 	LUKSEncryption    string   `json:"LUKSEncryption,omitempty"`
+	// SCSIPersistentReservation is set when nodes sharing the volume must hold a persistent reservation to write
+	SCSIPersistentReservation bool   `json:"scsiPersistentReservation,omitempty"`
+	ReservationKey            string `json:"reservationKey,omitempty"`
 	VolumeAccessInfo
 }
 
@@ -94,6 +99,52 @@ type VolumeTrackingInfo struct {
 	VolumeTrackingInfoPath string
 	StagingTargetPath      string              `json:"stagingTargetPath"`
 	PublishedPaths         map[string]struct{} `json:"publishedTargetPaths"`
+	// Ephemeral is set for CSI ephemeral volumes, which the node provisions when they are published and deletes
+	// when they are unpublished
+	Ephemeral bool `json:"ephemeral,omitempty"`
+}
+
+// NodeCheckFindingType identifies an inconsistency between a node's volume tracking files and its kernel state.
+type NodeCheckFindingType string
+
+const (
+	// NodeCheckOrphanedMount is a Trident publication mounted in a pod with no tracking file for its volume
+	NodeCheckOrphanedMount NodeCheckFindingType = "orphanedMount"
+	// NodeCheckUntrackedPublishedPath is a Trident publication mounted in a pod but missing from its tracking file
+	NodeCheckUntrackedPublishedPath NodeCheckFindingType = "untrackedPublishedPath"
+	// NodeCheckDanglingPublishedPath is a published path in a tracking file that is no longer mounted
+	NodeCheckDanglingPublishedPath NodeCheckFindingType = "danglingPublishedPath"
+	// NodeCheckDanglingTrackingFile is a tracking file for a volume that is no longer present on the node
+	NodeCheckDanglingTrackingFile NodeCheckFindingType = "danglingTrackingFile"
+	// NodeCheckInvalidTrackingFile is a tracking file that cannot be read
+	NodeCheckInvalidTrackingFile NodeCheckFindingType = "invalidTrackingFile"
+	// NodeCheckMissingISCSISession is a mounted iSCSI volume with no session to its target
+	NodeCheckMissingISCSISession NodeCheckFindingType = "missingISCSISession"
+	// NodeCheckOrphanedISCSISession is an iSCSI session to a target that no tracked volume uses
+	NodeCheckOrphanedISCSISession NodeCheckFindingType = "orphanedISCSISession"
+	// NodeCheckOrphanedMultipathMap is an unused multipath map of iSCSI devices that no tracked volume uses
+	NodeCheckOrphanedMultipathMap NodeCheckFindingType = "orphanedMultipathMap"
+	// NodeCheckOrphanedLoopDevice is an unused loop device backed by a file on an NFS mount that no tracked volume uses
+	NodeCheckOrphanedLoopDevice NodeCheckFindingType = "orphanedLoopDevice"
+)
+
+// NodeCheckFinding is a single inconsistency found by a node check, along with its repair and the repair's outcome.
+type NodeCheckFinding struct {
+	Type     NodeCheckFindingType `json:"type"`
+	VolumeID string               `json:"volumeID,omitempty"`
+	Resource string               `json:"resource"`
+	Message  string               `json:"message"`
+	Repair   string               `json:"repair,omitempty"` // Empty if the finding cannot be repaired automatically
+	Repaired bool                 `json:"repaired"`
+	Error    string               `json:"error,omitempty"` // Set if the repair failed
+}
+
+// NodeCheckReport is the result of comparing a node's volume tracking files with its kernel state.
+type NodeCheckReport struct {
+	Node     string             `json:"node"`
+	Repair   bool               `json:"repair"`
+	DryRun   bool               `json:"dryRun"`
+	Findings []NodeCheckFinding `json:"findings"`
 }
 
 type VolumePublication struct {
@@ -151,7 +202,9 @@ type Node struct {
 	TopologyLabels map[string]string `json:"topologyLabels,omitempty"`
 	NodePrep       *NodePrep         `json:"nodePrep,omitempty"`
 	HostInfo       *HostSystem       `json:"hostInfo,omitempty"`
+	RESTPort       string            `json:"restPort,omitempty"`
 	Deleted        bool              `json:"deleted"`
+	Fenced         bool              `json:"fenced,omitempty"`
 }
 
 // NodePrep struct is deprecated and only here for backwards compatibility
@@ -166,8 +219,33 @@ type NodePrep struct {
 type NodePrepStatus string
 
 type HostSystem struct {
-	OS       SystemOS `json:"os"`
-	Services []string `json:"services,omitempty"`
+	OS        SystemOS               `json:"os"`
+	Services  []string               `json:"services,omitempty"`
+	Multipath *MultipathConfigStatus `json:"multipath,omitempty"`
+}
+
+// MultipathConfigStatus is the result of validating a node's multipath configuration against the settings that
+// Trident relies on when it stages iSCSI volumes.
+type MultipathConfigStatus struct {
+	Valid  bool                   `json:"valid"`
+	Issues []MultipathConfigIssue `json:"issues,omitempty"`
+	// ManagedConfig is the path of the drop-in configuration file written by Trident, if there is one
+	ManagedConfig string `json:"managedConfig,omitempty"`
+}
+
+type MultipathConfigIssue struct {
+	Setting  string `json:"setting"` // e.g. defaults/find_multipaths or NETAPP LUN/no_path_retry
+	Value    string `json:"value,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Message  string `json:"message"`
+	Source   string `json:"source,omitempty"` // Set if the setting comes from /etc/multipath.conf
+}
+
+func (i MultipathConfigIssue) String() string {
+	if i.Expected == "" {
+		return i.Message
+	}
+	return fmt.Sprintf("%s: %s (expected %s)", i.Setting, i.Value, i.Expected)
 }
 
 type SystemOS struct {